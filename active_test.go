@@ -0,0 +1,46 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetActive(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	visible := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, visible)
+	ecs.AddComponent[CameraComponent](em, visible)
+
+	pooled := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, pooled)
+	ecs.AddComponent[CameraComponent](em, pooled)
+	em.Tag(pooled, "bullet")
+
+	em.SetActive(pooled, false)
+	assert.False(t, em.Active(pooled))
+	assert.True(t, em.Exists(pooled))
+	assert.True(t, em.Active(visible))
+
+	assert.Equal(t, []ecs.EntityID{visible}, slices.Collect(ecs.Query[TransformComponent](em)))
+	assert.Equal(t, []ecs.EntityID{visible}, slices.Collect(ecs.Query2[TransformComponent, CameraComponent](em)))
+	assert.Equal(t, []ecs.EntityID{visible}, slices.Collect(em.Query(CameraComponent{})))
+	assert.Empty(t, slices.Collect(ecs.QueryTagged(em, "bullet")))
+
+	all := ecs.NewQuery2[TransformComponent, CameraComponent](em).IncludeInactive().Iter()
+	assert.ElementsMatch(t, []ecs.EntityID{visible, pooled}, slices.Collect(all))
+
+	// Components of inactive entities are still accessible.
+	_, ok := ecs.GetComponent[TransformComponent](em, pooled)
+	assert.True(t, ok)
+
+	em.SetActive(pooled, true)
+	assert.ElementsMatch(t, []ecs.EntityID{visible, pooled}, slices.Collect(ecs.Query[TransformComponent](em)))
+
+	em.Remove(pooled)
+	assert.False(t, em.Active(pooled))
+	assert.False(t, em.Exists(pooled))
+}
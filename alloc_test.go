@@ -0,0 +1,68 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// queryAllocs returns the allocations of one pass of fn over a world of n entities.
+func queryAllocs(n int, fn func(em *ecs.EntityManager)) float64 {
+	em := ecs.NewEntityManager()
+	for i := range n {
+		entityID := em.NewEntity()
+		ecs.AddComponent[TransformComponent](em, entityID)
+		if i%2 == 0 {
+			ecs.AddComponent[CameraComponent](em, entityID).Zoom = 2
+		}
+	}
+
+	// Warm up the query plan cache.
+	fn(em)
+
+	return testing.AllocsPerRun(100, func() { fn(em) })
+}
+
+func TestQueryAllocations(t *testing.T) {
+	zoomed := func(c *CameraComponent) bool { return c.Zoom > 1 }
+
+	passes := map[string]func(em *ecs.EntityManager){
+		"Query": func(em *ecs.EntityManager) {
+			for entityID := range ecs.Query[TransformComponent](em) {
+				ecs.MustGetComponent[TransformComponent](em, entityID).Rotation++
+			}
+		},
+		"Query2": func(em *ecs.EntityManager) {
+			for entityID := range ecs.Query2[TransformComponent, CameraComponent](em) {
+				ecs.MustGetComponent[CameraComponent](em, entityID).Zoom++
+			}
+		},
+		"QueryWith": func(em *ecs.EntityManager) {
+			for entityID := range ecs.QueryWith(em, zoomed) {
+				ecs.MustGetComponent[CameraComponent](em, entityID).Zoom--
+			}
+		},
+		"Where": func(em *ecs.EntityManager) {
+			ecs.Count(ecs.Where(em, ecs.Query2[TransformComponent, CameraComponent](em), zoomed))
+		},
+	}
+
+	for name, pass := range passes {
+		t.Run(name, func(t *testing.T) {
+			small := queryAllocs(10, pass)
+			large := queryAllocs(1000, pass)
+
+			// Only creating the iterator closures allocates, never the iteration itself.
+			assert.Equal(t, small, large, "allocations grow with the number of entities")
+		})
+	}
+
+	em := ecs.NewEntityManager()
+	entityID := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, entityID)
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		ecs.GetComponent[TransformComponent](em, entityID)
+		ecs.HasComponent[CameraComponent](em, entityID)
+	}))
+}
@@ -0,0 +1,218 @@
+// Package animation plays sprite sheet animations by updating the Source rectangle of render.Sprite components.
+package animation
+
+import (
+	"image"
+	"time"
+)
+
+// LoopMode controls what happens when a clip reaches its last frame.
+type LoopMode int
+
+const (
+	// Loop restarts the clip from the first frame.
+	Loop LoopMode = iota
+	// Once stops on the last frame and marks the animation finished.
+	Once
+	// PingPong plays the clip backwards, then forwards again.
+	PingPong
+)
+
+// Frame is a single frame of a clip.
+type Frame struct {
+	Source   image.Rectangle
+	Duration time.Duration
+}
+
+// Clip is a named sequence of frames.
+type Clip struct {
+	Name   string
+	Frames []Frame
+	Loop   LoopMode
+}
+
+// SheetClip builds a clip from a sprite sheet laid out in a grid of frameWidth x frameHeight cells,
+// columns wide. indices are the cell indices in row-major order and every frame lasts duration.
+func SheetClip(name string, frameWidth, frameHeight, columns int, indices []int, duration time.Duration, loop LoopMode) *Clip {
+	clip := &Clip{
+		Name:   name,
+		Frames: make([]Frame, len(indices)),
+		Loop:   loop,
+	}
+
+	for i, index := range indices {
+		x := (index % columns) * frameWidth
+		y := (index / columns) * frameHeight
+
+		clip.Frames[i] = Frame{
+			Source:   image.Rect(x, y, x+frameWidth, y+frameHeight),
+			Duration: duration,
+		}
+	}
+
+	return clip
+}
+
+// Animation is the component holding an entity's clips and playback state.
+type Animation struct {
+	Clips map[string]*Clip
+	// Speed multiplies the delta time used to advance frames. Zero is treated as 1.
+	Speed float64
+
+	current  *Clip
+	frame    int
+	elapsed  time.Duration
+	reverse  bool
+	playing  bool
+	finished bool
+}
+
+func (a *Animation) Reset() {
+	*a = Animation{}
+}
+
+// AddClip registers clips on the animation.
+func (a *Animation) AddClip(clips ...*Clip) {
+	if a.Clips == nil {
+		a.Clips = make(map[string]*Clip, len(clips))
+	}
+
+	for _, clip := range clips {
+		a.Clips[clip.Name] = clip
+	}
+}
+
+// Play starts the named clip from its first frame, unless it is already playing.
+// It returns false if the clip does not exist.
+func (a *Animation) Play(name string) bool {
+	clip, ok := a.Clips[name]
+	if !ok {
+		return false
+	}
+
+	if a.current == clip && a.playing {
+		return true
+	}
+
+	a.current = clip
+	a.frame = 0
+	a.elapsed = 0
+	a.reverse = false
+	a.playing = true
+	a.finished = false
+
+	return true
+}
+
+// Restart plays the current clip again from its first frame.
+func (a *Animation) Restart() {
+	if a.current == nil {
+		return
+	}
+
+	a.playing = false
+	a.Play(a.current.Name)
+}
+
+// Pause stops advancing frames, keeping the current frame.
+func (a *Animation) Pause() {
+	a.playing = false
+}
+
+// Resume continues a paused clip.
+func (a *Animation) Resume() {
+	a.playing = a.current != nil && !a.finished
+}
+
+// Clip returns the name of the current clip, or an empty string if none has been played.
+func (a *Animation) Clip() string {
+	if a.current == nil {
+		return ""
+	}
+
+	return a.current.Name
+}
+
+// Frame returns the index of the current frame within the current clip.
+func (a *Animation) Frame() int {
+	return a.frame
+}
+
+// Playing reports whether the animation is advancing.
+func (a *Animation) Playing() bool {
+	return a.playing
+}
+
+// Finished reports whether a clip played with Once has reached its end.
+func (a *Animation) Finished() bool {
+	return a.finished
+}
+
+// CurrentFrame returns the current frame, if a clip is set.
+func (a *Animation) CurrentFrame() (Frame, bool) {
+	if a.current == nil || len(a.current.Frames) == 0 {
+		return Frame{}, false
+	}
+
+	return a.current.Frames[a.frame], true
+}
+
+// Advance moves the animation forward by dt and reports whether the frame changed.
+func (a *Animation) Advance(dt time.Duration) bool {
+	if !a.playing || a.current == nil || len(a.current.Frames) == 0 {
+		return false
+	}
+
+	if a.Speed != 0 {
+		dt = time.Duration(float64(dt) * a.Speed)
+	}
+
+	a.elapsed += dt
+	changed := false
+
+	for a.playing {
+		duration := a.current.Frames[a.frame].Duration
+		if duration <= 0 || a.elapsed < duration {
+			break
+		}
+
+		a.elapsed -= duration
+		a.step()
+		changed = true
+	}
+
+	return changed
+}
+
+func (a *Animation) step() {
+	last := len(a.current.Frames) - 1
+
+	switch a.current.Loop {
+	case Loop:
+		a.frame = (a.frame + 1) % len(a.current.Frames)
+	case Once:
+		if a.frame < last {
+			a.frame++
+		}
+
+		if a.frame == last {
+			a.playing = false
+			a.finished = true
+			a.elapsed = 0
+		}
+	case PingPong:
+		if last == 0 {
+			return
+		}
+
+		if a.reverse && a.frame == 0 || !a.reverse && a.frame == last {
+			a.reverse = !a.reverse
+		}
+
+		if a.reverse {
+			a.frame--
+		} else {
+			a.frame++
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package animation_test
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/samix73/ebiten-ecs/animation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSheetClip(t *testing.T) {
+	clip := animation.SheetClip("walk", 16, 32, 4, []int{0, 5}, 100*time.Millisecond, animation.Loop)
+
+	assert.Equal(t, image.Rect(0, 0, 16, 32), clip.Frames[0].Source)
+	assert.Equal(t, image.Rect(16, 32, 32, 64), clip.Frames[1].Source)
+}
+
+func TestAnimationLoopModes(t *testing.T) {
+	frame := 100 * time.Millisecond
+
+	var a animation.Animation
+	a.AddClip(
+		animation.SheetClip("walk", 8, 8, 4, []int{0, 1, 2}, frame, animation.Loop),
+		animation.SheetClip("die", 8, 8, 4, []int{0, 1, 2}, frame, animation.Once),
+		animation.SheetClip("idle", 8, 8, 4, []int{0, 1, 2}, frame, animation.PingPong),
+	)
+
+	assert.False(t, a.Play("fly"))
+	assert.True(t, a.Play("walk"))
+
+	assert.False(t, a.Advance(50*time.Millisecond))
+	assert.True(t, a.Advance(50*time.Millisecond))
+	assert.Equal(t, 1, a.Frame())
+	a.Advance(2 * frame)
+	assert.Equal(t, 0, a.Frame())
+
+	a.Play("die")
+	a.Advance(10 * frame)
+	assert.Equal(t, 2, a.Frame())
+	assert.True(t, a.Finished())
+	assert.False(t, a.Playing())
+
+	a.Play("idle")
+	frames := make([]int, 0, 5)
+	for range 5 {
+		a.Advance(frame)
+		frames = append(frames, a.Frame())
+	}
+	assert.Equal(t, []int{1, 2, 1, 0, 1}, frames)
+}
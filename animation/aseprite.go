@@ -0,0 +1,203 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png" // Register PNG for LoadAseprite.
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Sheet is a sprite sheet exported by Aseprite with File > Export Sprite Sheet, as JSON data and an image.
+type Sheet struct {
+	// Image is the sheet image, nil for sheets parsed with ParseAseprite.
+	Image *ebiten.Image
+	// Frames are all the frames of the sheet, in Aseprite's order.
+	Frames []Frame
+	// Clips hold one clip per tag, or a single clip of all the frames if the sprite has no tags.
+	Clips []*Clip
+}
+
+// Clip returns the clip with the given name.
+func (s *Sheet) Clip(name string) (*Clip, bool) {
+	for _, clip := range s.Clips {
+		if clip.Name == name {
+			return clip, true
+		}
+	}
+
+	return nil, false
+}
+
+// asepriteFile is the JSON data exported by Aseprite. Frames is an array or, with the default
+// "Hash" format, an object keyed by file name, in frame order.
+type asepriteFile struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string        `json:"image"`
+		FrameTags []asepriteTag `json:"frameTags"`
+	} `json:"meta"`
+}
+
+type asepriteFrame struct {
+	Frame struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+		W int `json:"w"`
+		H int `json:"h"`
+	} `json:"frame"`
+	Duration int `json:"duration"`
+}
+
+type asepriteTag struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+	Repeat    string `json:"repeat"`
+}
+
+// LoadAseprite loads the Aseprite JSON data at name in fsys and the sheet image it references,
+// relative to the JSON file. Without tags, the single clip is named after the JSON file, without extension.
+// Sheets must be exported without trimming nor rotation, which clips cannot represent.
+func LoadAseprite(fsys fs.FS, name string) (*Sheet, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("animation.LoadAseprite fs.ReadFile error: %w", err)
+	}
+
+	base := path.Base(name)
+	sheet, imageName, err := parseAseprite(data, strings.TrimSuffix(base, path.Ext(base)))
+	if err != nil {
+		return nil, fmt.Errorf("animation.LoadAseprite parseAseprite error: %q: %w", name, err)
+	}
+
+	if imageName == "" {
+		return sheet, nil
+	}
+
+	f, err := fsys.Open(path.Join(path.Dir(name), imageName))
+	if err != nil {
+		return nil, fmt.Errorf("animation.LoadAseprite fsys.Open error: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("animation.LoadAseprite image.Decode error: %q: %w", imageName, err)
+	}
+	sheet.Image = ebiten.NewImageFromImage(img)
+
+	return sheet, nil
+}
+
+// ParseAseprite parses Aseprite JSON data, without loading the sheet image. Without tags,
+// the single clip is named name.
+func ParseAseprite(data []byte, name string) (*Sheet, error) {
+	sheet, _, err := parseAseprite(data, name)
+	if err != nil {
+		return nil, fmt.Errorf("animation.ParseAseprite parseAseprite error: %w", err)
+	}
+
+	return sheet, nil
+}
+
+func parseAseprite(data []byte, name string) (*Sheet, string, error) {
+	var file asepriteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, "", fmt.Errorf("animation.parseAseprite json.Unmarshal error: %w", err)
+	}
+
+	frames, err := asepriteFrames(file.Frames)
+	if err != nil {
+		return nil, "", fmt.Errorf("animation.parseAseprite asepriteFrames error: %w", err)
+	}
+
+	sheet := &Sheet{Frames: make([]Frame, len(frames))}
+	for i, frame := range frames {
+		sheet.Frames[i] = Frame{
+			Source:   image.Rect(frame.Frame.X, frame.Frame.Y, frame.Frame.X+frame.Frame.W, frame.Frame.Y+frame.Frame.H),
+			Duration: time.Duration(frame.Duration) * time.Millisecond,
+		}
+	}
+
+	if len(file.Meta.FrameTags) == 0 {
+		sheet.Clips = []*Clip{{Name: name, Frames: sheet.Frames, Loop: Loop}}
+		return sheet, file.Meta.Image, nil
+	}
+
+	for _, tag := range file.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(sheet.Frames) || tag.From > tag.To {
+			return nil, "", fmt.Errorf("animation.parseAseprite tag %q: frames %d to %d out of range", tag.Name, tag.From, tag.To)
+		}
+
+		sheet.Clips = append(sheet.Clips, tagClip(tag, sheet.Frames[tag.From:tag.To+1]))
+	}
+
+	return sheet, file.Meta.Image, nil
+}
+
+// tagClip builds the clip of a tag from its frames. Reverse tags play their frames backwards, ping-pong tags
+// with PingPong, and tags repeated once play Once.
+func tagClip(tag asepriteTag, frames []Frame) *Clip {
+	clip := &Clip{Name: tag.Name, Frames: make([]Frame, len(frames)), Loop: Loop}
+	copy(clip.Frames, frames)
+
+	if tag.Direction == "reverse" || tag.Direction == "pingpong_reverse" {
+		for i, j := 0, len(clip.Frames)-1; i < j; i, j = i+1, j-1 {
+			clip.Frames[i], clip.Frames[j] = clip.Frames[j], clip.Frames[i]
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(tag.Direction, "pingpong"):
+		clip.Loop = PingPong
+	case tag.Repeat == "1":
+		clip.Loop = Once
+	}
+
+	return clip
+}
+
+// asepriteFrames decodes the frames of the array and hash formats, keeping the order of the hash keys.
+func asepriteFrames(raw json.RawMessage) ([]asepriteFrame, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	if raw[0] == '[' {
+		var frames []asepriteFrame
+		if err := json.Unmarshal(raw, &frames); err != nil {
+			return nil, fmt.Errorf("animation.asepriteFrames json.Unmarshal error: %w", err)
+		}
+
+		return frames, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("animation.asepriteFrames decoder.Token error: %w", err)
+	}
+
+	var frames []asepriteFrame
+	for decoder.More() {
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("animation.asepriteFrames decoder.Token error: %w", err)
+		}
+
+		var frame asepriteFrame
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, fmt.Errorf("animation.asepriteFrames decoder.Decode error: %w", err)
+		}
+		frames = append(frames, frame)
+	}
+
+	return frames, nil
+}
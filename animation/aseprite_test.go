@@ -0,0 +1,88 @@
+package animation_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/samix73/ebiten-ecs/animation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const asepriteHash = `{
+ "frames": {
+  "hero 2.aseprite": { "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "duration": 300 },
+  "hero 0.aseprite": { "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "duration": 100 },
+  "hero 1.aseprite": { "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "duration": 200 }
+ },
+ "meta": {
+  "image": "hero.png",
+  "frameTags": [
+   { "name": "idle", "from": 0, "to": 0, "direction": "forward" },
+   { "name": "walk", "from": 0, "to": 2, "direction": "reverse" },
+   { "name": "bob", "from": 1, "to": 2, "direction": "pingpong" },
+   { "name": "die", "from": 1, "to": 2, "direction": "forward", "repeat": "1" }
+  ]
+ }
+}`
+
+func TestLoadAseprite(t *testing.T) {
+	var sheetPNG bytes.Buffer
+	require.NoError(t, png.Encode(&sheetPNG, image.NewRGBA(image.Rect(0, 0, 48, 16))))
+
+	fsys := fstest.MapFS{
+		"sprites/hero.json": {Data: []byte(asepriteHash)},
+		"sprites/hero.png":  {Data: sheetPNG.Bytes()},
+	}
+
+	sheet, err := animation.LoadAseprite(fsys, "sprites/hero.json")
+	require.NoError(t, err)
+	require.NotNil(t, sheet.Image)
+	assert.Equal(t, 48, sheet.Image.Bounds().Dx())
+
+	require.Len(t, sheet.Frames, 3)
+	assert.Equal(t, image.Rect(32, 0, 48, 16), sheet.Frames[0].Source, "hash frames keep the file order")
+	assert.Equal(t, 300*time.Millisecond, sheet.Frames[0].Duration)
+
+	require.Len(t, sheet.Clips, 4)
+	walk, ok := sheet.Clip("walk")
+	require.True(t, ok)
+	assert.Equal(t, animation.Loop, walk.Loop)
+	assert.Equal(t, 200*time.Millisecond, walk.Frames[0].Duration, "reverse tags play backwards")
+	assert.Equal(t, 300*time.Millisecond, walk.Frames[2].Duration)
+
+	bob, _ := sheet.Clip("bob")
+	assert.Equal(t, animation.PingPong, bob.Loop)
+	die, _ := sheet.Clip("die")
+	assert.Equal(t, animation.Once, die.Loop)
+
+	var a animation.Animation
+	a.AddClip(sheet.Clips...)
+	assert.True(t, a.Play("walk"))
+	a.Advance(200 * time.Millisecond)
+	frame, _ := a.CurrentFrame()
+	assert.Equal(t, image.Rect(0, 0, 16, 16), frame.Source)
+}
+
+func TestParseAsepriteArray(t *testing.T) {
+	sheet, err := animation.ParseAseprite([]byte(`{
+ "frames": [
+  { "filename": "coin 0", "frame": { "x": 0, "y": 0, "w": 8, "h": 8 }, "duration": 50 },
+  { "filename": "coin 1", "frame": { "x": 8, "y": 0, "w": 8, "h": 8 }, "duration": 50 }
+ ],
+ "meta": { "image": "coin.png" }
+}`), "coin")
+	require.NoError(t, err)
+	assert.Nil(t, sheet.Image)
+
+	clip, ok := sheet.Clip("coin")
+	require.True(t, ok, "sprites without tags have a single clip")
+	assert.Len(t, clip.Frames, 2)
+
+	_, err = animation.ParseAseprite([]byte(`{"frames": [], "meta": {"frameTags": [{"name": "x", "from": 0, "to": 3}]}}`), "x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package animation
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+)
+
+// System advances every Animation with the game's delta time and copies the current frame
+// into the entity's render.Sprite.
+type System struct {
+	*ecs.BaseSystem
+}
+
+// NewSystem creates a new animation System with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int) *System {
+	return &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+}
+
+// Update advances all animations.
+func (s *System) Update() error {
+	em := s.EntityManager()
+	dt := s.Time().DeltaDuration()
+
+	for entityID := range ecs.Query2[Animation, render.Sprite](em) {
+		animation := ecs.MustGetComponent[Animation](em, entityID)
+		animation.Advance(dt)
+
+		frame, ok := animation.CurrentFrame()
+		if !ok {
+			continue
+		}
+
+		sprite := ecs.MustGetComponent[render.Sprite](em, entityID)
+		sprite.Source = frame.Source
+	}
+
+	return nil
+}
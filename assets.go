@@ -0,0 +1,366 @@
+package ecs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Register the common image formats for Assets.Image.
+	_ "image/png"
+	"io"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+)
+
+// AssetKind identifies the type of an asset.
+type AssetKind int
+
+const (
+	AssetImage AssetKind = iota
+	AssetFont
+	AssetAudio
+)
+
+func (k AssetKind) String() string {
+	switch k {
+	case AssetImage:
+		return "image"
+	case AssetFont:
+		return "font"
+	case AssetAudio:
+		return "audio"
+	default:
+		return fmt.Sprintf("AssetKind(%d)", int(k))
+	}
+}
+
+// ErrAssetKind is returned when an asset is requested as a different kind than it was loaded as.
+var ErrAssetKind = errors.New("asset loaded as a different kind")
+
+type assetEntry struct {
+	kind  AssetKind
+	refs  int
+	ready chan struct{}
+	value any
+	err   error
+}
+
+// Assets loads and caches images, fonts and audio data from an fs.FS, such as an embed.FS.
+// Each asset is read once and shared; getters increment the asset's reference count
+// and Release decrements it, freeing the asset when it is no longer referenced.
+// Assets is safe for concurrent use.
+type Assets struct {
+	fsys fs.FS
+
+	mu      sync.Mutex
+	entries map[string]*assetEntry
+}
+
+// NewAssets creates an asset manager reading from fsys.
+func NewAssets(fsys fs.FS) *Assets {
+	return &Assets{
+		fsys:    fsys,
+		entries: make(map[string]*assetEntry),
+	}
+}
+
+// FS returns the file system assets are read from.
+func (a *Assets) FS() fs.FS {
+	return a.fsys
+}
+
+// Image returns the image at name, loading it on first use.
+func (a *Assets) Image(name string) (*ebiten.Image, error) {
+	value, err := a.acquire(name, AssetImage)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.Image a.acquire error: %w", err)
+	}
+
+	return value.(*ebiten.Image), nil
+}
+
+// Font returns the OpenType/TrueType font at name, loading it on first use.
+func (a *Assets) Font(name string) (*opentype.Font, error) {
+	value, err := a.acquire(name, AssetFont)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.Font a.acquire error: %w", err)
+	}
+
+	return value.(*opentype.Font), nil
+}
+
+// FontFace returns a face of the font at name with the given size in points at 72 DPI.
+// The caller owns the returned face; the underlying font is reference counted like any other asset.
+func (a *Assets) FontFace(name string, size float64) (font.Face, error) {
+	f, err := a.Font(name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.FontFace a.Font error: %w", err)
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.FontFace opentype.NewFace error: %w", err)
+	}
+
+	return face, nil
+}
+
+// Audio returns the raw, still encoded, contents of the audio file at name, loading it on first use.
+func (a *Assets) Audio(name string) ([]byte, error) {
+	value, err := a.acquire(name, AssetAudio)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.Audio a.acquire error: %w", err)
+	}
+
+	return value.([]byte), nil
+}
+
+// Release decrements the reference count of the asset, freeing it once it reaches zero.
+func (a *Assets) Release(name string) {
+	a.mu.Lock()
+	entry, ok := a.entries[name]
+	if !ok {
+		a.mu.Unlock()
+		return
+	}
+
+	entry.refs--
+	if entry.refs > 0 {
+		a.mu.Unlock()
+		return
+	}
+
+	delete(a.entries, name)
+	a.mu.Unlock()
+
+	<-entry.ready
+	if img, ok := entry.value.(*ebiten.Image); ok {
+		img.Deallocate()
+	}
+}
+
+// Refs returns the reference count of the asset, or 0 if it is not loaded.
+func (a *Assets) Refs(name string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if entry, ok := a.entries[name]; ok {
+		return entry.refs
+	}
+
+	return 0
+}
+
+// Loaded reports whether the asset has finished loading successfully.
+func (a *Assets) Loaded(name string) bool {
+	a.mu.Lock()
+	entry, ok := a.entries[name]
+	a.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	select {
+	case <-entry.ready:
+		return entry.err == nil
+	default:
+		return false
+	}
+}
+
+// Teardown frees every asset regardless of its reference count.
+func (a *Assets) Teardown() {
+	a.mu.Lock()
+	entries := a.entries
+	a.entries = make(map[string]*assetEntry)
+	a.mu.Unlock()
+
+	for _, entry := range entries {
+		<-entry.ready
+		if img, ok := entry.value.(*ebiten.Image); ok {
+			img.Deallocate()
+		}
+	}
+}
+
+func (a *Assets) acquire(name string, kind AssetKind) (any, error) {
+	a.mu.Lock()
+	entry, ok := a.entries[name]
+	if ok {
+		if entry.kind != kind {
+			a.mu.Unlock()
+			return nil, fmt.Errorf("%s %q is a %s: %w", kind, name, entry.kind, ErrAssetKind)
+		}
+
+		entry.refs++
+		a.mu.Unlock()
+
+		<-entry.ready
+		return entry.value, entry.err
+	}
+
+	entry = &assetEntry{kind: kind, refs: 1, ready: make(chan struct{})}
+	a.entries[name] = entry
+	a.mu.Unlock()
+
+	entry.value, entry.err = a.load(name, kind)
+	close(entry.ready)
+
+	if entry.err != nil {
+		a.mu.Lock()
+		if a.entries[name] == entry {
+			delete(a.entries, name)
+		}
+		a.mu.Unlock()
+	}
+
+	return entry.value, entry.err
+}
+
+func (a *Assets) load(name string, kind AssetKind) (any, error) {
+	f, err := a.fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.load fsys.Open error: %w", err)
+	}
+	defer f.Close()
+
+	switch kind {
+	case AssetImage:
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("ecs.Assets.load image.Decode error: %w", err)
+		}
+
+		return ebiten.NewImageFromImage(img), nil
+	case AssetFont:
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("ecs.Assets.load io.ReadAll error: %w", err)
+		}
+
+		parsed, err := opentype.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("ecs.Assets.load opentype.Parse error: %w", err)
+		}
+
+		return parsed, nil
+	case AssetAudio:
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("ecs.Assets.load io.ReadAll error: %w", err)
+		}
+
+		return data, nil
+	default:
+		return nil, fmt.Errorf("ecs.Assets.load: unknown asset kind %s", kind)
+	}
+}
+
+// AssetManifest lists assets to preload. It can be decoded from JSON:
+//
+//	{"images": ["player.png"], "fonts": ["ui.ttf"], "audio": ["jump.ogg"]}
+type AssetManifest struct {
+	Images []string `json:"images"`
+	Fonts  []string `json:"fonts"`
+	Audio  []string `json:"audio"`
+}
+
+// LoadAssetManifest reads a JSON AssetManifest from the asset file system.
+func (a *Assets) LoadAssetManifest(name string) (*AssetManifest, error) {
+	data, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.Assets.LoadAssetManifest fs.ReadFile error: %w", err)
+	}
+
+	var manifest AssetManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("ecs.Assets.LoadAssetManifest json.Unmarshal error: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// Preload tracks the progress of an asynchronous Assets.Preload.
+type Preload struct {
+	total  int
+	loaded atomic.Int64
+	done   chan struct{}
+	err    error
+}
+
+// Progress returns the number of assets loaded so far and the total number of assets.
+func (p *Preload) Progress() (loaded, total int) {
+	return int(p.loaded.Load()), p.total
+}
+
+// Done reports whether all assets have been loaded.
+func (p *Preload) Done() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until all assets are loaded and returns the errors encountered, if any.
+func (p *Preload) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// Preload loads every asset of the manifest in the background.
+// Each preloaded asset holds one reference, released with Release or Unload.
+// Poll the returned Preload from a system to drive a loading screen.
+func (a *Assets) Preload(manifest *AssetManifest) *Preload {
+	type job struct {
+		name string
+		kind AssetKind
+	}
+
+	jobs := make([]job, 0, len(manifest.Images)+len(manifest.Fonts)+len(manifest.Audio))
+	for _, name := range manifest.Images {
+		jobs = append(jobs, job{name, AssetImage})
+	}
+	for _, name := range manifest.Fonts {
+		jobs = append(jobs, job{name, AssetFont})
+	}
+	for _, name := range manifest.Audio {
+		jobs = append(jobs, job{name, AssetAudio})
+	}
+
+	p := &Preload{total: len(jobs), done: make(chan struct{})}
+
+	go func() {
+		var wg sync.WaitGroup
+		errs := make([]error, len(jobs))
+
+		for i, j := range jobs {
+			wg.Go(func() {
+				_, errs[i] = a.acquire(j.name, j.kind)
+				p.loaded.Add(1)
+			})
+		}
+
+		wg.Wait()
+		p.err = errors.Join(errs...)
+		close(p.done)
+	}()
+
+	return p
+}
+
+// Unload releases one reference to every asset of the manifest.
+func (a *Assets) Unload(manifest *AssetManifest) {
+	for _, names := range [][]string{manifest.Images, manifest.Fonts, manifest.Audio} {
+		for _, name := range names {
+			a.Release(name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package ecs_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngBytes(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+
+	return buf.Bytes()
+}
+
+func TestAssetsCaching(t *testing.T) {
+	assets := ecs.NewAssets(fstest.MapFS{
+		"player.png": {Data: pngBytes(t, 4, 2)},
+		"jump.ogg":   {Data: []byte("ogg")},
+	})
+
+	img1, err := assets.Image("player.png")
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 4, 2), img1.Bounds())
+
+	img2, err := assets.Image("player.png")
+	require.NoError(t, err)
+	assert.Same(t, img1, img2)
+	assert.Equal(t, 2, assets.Refs("player.png"))
+
+	_, err = assets.Audio("player.png")
+	assert.ErrorIs(t, err, ecs.ErrAssetKind)
+
+	_, err = assets.Image("missing.png")
+	assert.Error(t, err)
+	assert.False(t, assets.Loaded("missing.png"))
+
+	assets.Release("player.png")
+	assert.True(t, assets.Loaded("player.png"))
+	assets.Release("player.png")
+	assert.False(t, assets.Loaded("player.png"))
+	assert.Equal(t, 0, assets.Refs("player.png"))
+}
+
+func TestAssetsPreload(t *testing.T) {
+	assets := ecs.NewAssets(fstest.MapFS{
+		"manifest.json": {Data: []byte(`{"images": ["a.png", "b.png"], "audio": ["jump.ogg"]}`)},
+		"a.png":         {Data: pngBytes(t, 1, 1)},
+		"b.png":         {Data: pngBytes(t, 2, 2)},
+		"jump.ogg":      {Data: []byte("ogg")},
+	})
+
+	manifest, err := assets.LoadAssetManifest("manifest.json")
+	require.NoError(t, err)
+
+	preload := assets.Preload(manifest)
+	require.NoError(t, preload.Wait())
+	assert.True(t, preload.Done())
+
+	loaded, total := preload.Progress()
+	assert.Equal(t, 3, loaded)
+	assert.Equal(t, 3, total)
+	assert.True(t, assets.Loaded("a.png"))
+	assert.True(t, assets.Loaded("jump.ogg"))
+
+	assets.Unload(manifest)
+	assert.False(t, assets.Loaded("a.png"))
+}
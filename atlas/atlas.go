@@ -0,0 +1,157 @@
+// Package atlas packs images into shared pages at load time, so that sprites drawn from the same page
+// can be batched by Ebiten into fewer draw calls without authoring sprite sheets by hand.
+package atlas
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/png" // Register PNG for Atlas.AddFile.
+	"io/fs"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+)
+
+// DefaultPageSize is the width and height of the pages of atlases created by New with a zero page size.
+const DefaultPageSize = 2048
+
+// ErrTooLarge is returned when an image does not fit in an empty page.
+var ErrTooLarge = errors.New("image larger than the atlas page")
+
+// Atlas packs images into pages of a fixed size, with the skyline algorithm, and returns
+// the packed images as sub-images of the pages. Sub-images can be used wherever the original
+// images were, including render.Sprite, whose Source rectangles stay relative to the image.
+// An Atlas is not safe for concurrent use.
+type Atlas struct {
+	pageSize int
+	padding  int
+
+	pages     []*ebiten.Image
+	packers   []*skyline
+	regions   map[string]*ebiten.Image
+	originals map[*ebiten.Image]*ebiten.Image
+}
+
+// New creates an empty atlas with pages of pageSize x pageSize pixels, or DefaultPageSize if zero.
+// padding transparent pixels are kept between images so that filtering does not bleed neighbours in.
+func New(pageSize, padding int) *Atlas {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &Atlas{
+		pageSize:  pageSize,
+		padding:   max(padding, 0),
+		regions:   make(map[string]*ebiten.Image),
+		originals: make(map[*ebiten.Image]*ebiten.Image),
+	}
+}
+
+// Add copies img into the atlas under name and returns its packed copy. Adding a name twice returns
+// the first copy. When img is an *ebiten.Image, sprites drawing it can be moved to the atlas with Rewrite.
+func (a *Atlas) Add(name string, img image.Image) (*ebiten.Image, error) {
+	if region, ok := a.regions[name]; ok {
+		return region, nil
+	}
+
+	size := img.Bounds().Size()
+	page, position, err := a.reserve(size.X, size.Y)
+	if err != nil {
+		return nil, fmt.Errorf("atlas.Atlas.Add a.reserve error: %q: %w", name, err)
+	}
+
+	src, isEbitenImage := img.(*ebiten.Image)
+	if !isEbitenImage {
+		src = ebiten.NewImageFromImage(img)
+		defer src.Deallocate()
+	}
+
+	op := &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy}
+	op.GeoM.Translate(float64(position.X), float64(position.Y))
+	page.DrawImage(src, op)
+
+	region := page.SubImage(image.Rectangle{Min: position, Max: position.Add(size)}).(*ebiten.Image)
+	a.regions[name] = region
+	if isEbitenImage {
+		a.originals[src] = region
+	}
+
+	return region, nil
+}
+
+// AddFile decodes the image at name in fsys and adds it to the atlas under its name.
+func (a *Atlas) AddFile(fsys fs.FS, name string) (*ebiten.Image, error) {
+	if region, ok := a.regions[name]; ok {
+		return region, nil
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("atlas.Atlas.AddFile fsys.Open error: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("atlas.Atlas.AddFile image.Decode error: %q: %w", name, err)
+	}
+
+	region, err := a.Add(name, img)
+	if err != nil {
+		return nil, fmt.Errorf("atlas.Atlas.AddFile a.Add error: %w", err)
+	}
+
+	return region, nil
+}
+
+// Image returns the packed image added under name.
+func (a *Atlas) Image(name string) (*ebiten.Image, bool) {
+	region, ok := a.regions[name]
+	return region, ok
+}
+
+// Pages returns the pages of the atlas.
+func (a *Atlas) Pages() []*ebiten.Image {
+	return a.pages
+}
+
+// Rewrite replaces the images of the render.Sprite components drawing an *ebiten.Image added to the atlas
+// with its packed copy, active or not, and returns the number of sprites changed. Their Source rectangles
+// are unchanged.
+func (a *Atlas) Rewrite(em *ecs.EntityManager) int {
+	rewritten := 0
+	for entityID := range ecs.NewQuery[render.Sprite](em).IncludeInactive().Iter() {
+		sprite := ecs.MustGetComponent[render.Sprite](em, entityID)
+		if region, ok := a.originals[sprite.Image]; ok {
+			sprite.Image = region
+			rewritten++
+		}
+	}
+
+	return rewritten
+}
+
+// reserve finds room for a width x height image, adding a page if needed.
+func (a *Atlas) reserve(width, height int) (*ebiten.Image, image.Point, error) {
+	paddedWidth, paddedHeight := width+a.padding, height+a.padding
+	if paddedWidth > a.pageSize || paddedHeight > a.pageSize {
+		return nil, image.Point{}, fmt.Errorf("%dx%d in %dx%d: %w", width, height, a.pageSize, a.pageSize, ErrTooLarge)
+	}
+
+	for i, packer := range a.packers {
+		if position, ok := packer.insert(paddedWidth, paddedHeight); ok {
+			return a.pages[i], position, nil
+		}
+	}
+
+	packer := newSkyline(a.pageSize, a.pageSize)
+	position, _ := packer.insert(paddedWidth, paddedHeight)
+
+	page := ebiten.NewImage(a.pageSize, a.pageSize)
+	a.pages = append(a.pages, page)
+	a.packers = append(a.packers, packer)
+
+	return page, position, nil
+}
@@ -0,0 +1,100 @@
+package atlas_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/atlas"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtlasPacking(t *testing.T) {
+	a := atlas.New(64, 1)
+
+	tall, err := a.Add("tall", image.NewRGBA(image.Rect(0, 0, 16, 40)))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 16, 40), tall.Bounds())
+
+	wide, err := a.Add("wide", image.NewRGBA(image.Rect(0, 0, 40, 16)))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(17, 0, 57, 16), wide.Bounds(), "placed on the lowest segment")
+
+	small, err := a.Add("small", image.NewRGBA(image.Rect(0, 0, 20, 20)))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(17, 17, 37, 37), small.Bounds(), "placed on top of the wide image")
+
+	again, err := a.Add("wide", image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	require.NoError(t, err)
+	assert.Same(t, wide, again, "names are added once")
+
+	big, err := a.Add("big", image.NewRGBA(image.Rect(0, 0, 60, 60)))
+	require.NoError(t, err)
+	assert.Len(t, a.Pages(), 2, "a page is added when full")
+	assert.Equal(t, image.Rect(0, 0, 60, 60), big.Bounds())
+
+	_, err = a.Add("huge", image.NewRGBA(image.Rect(0, 0, 64, 64)))
+	require.ErrorIs(t, err, atlas.ErrTooLarge, "the padding must fit")
+
+	img, ok := a.Image("small")
+	assert.True(t, ok)
+	assert.Same(t, small, img)
+}
+
+func TestAtlasAddFile(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	src.Set(1, 2, color.RGBA{R: 0xff, A: 0xff})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+
+	a := atlas.New(0, 0)
+	_, err := a.Add("first", image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	require.NoError(t, err)
+
+	region, err := a.AddFile(fstest.MapFS{"red.png": {Data: buf.Bytes()}}, "red.png")
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(8, 0, 12, 4), region.Bounds())
+
+	_, err = a.AddFile(fstest.MapFS{}, "missing.png")
+	assert.Error(t, err)
+}
+
+func TestAtlasRewrite(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	original := ebiten.NewImage(32, 32)
+	other := ebiten.NewImage(8, 8)
+
+	a := atlas.New(256, 1)
+	_, err := a.Add("padding", image.NewRGBA(image.Rect(0, 0, 10, 10)))
+	require.NoError(t, err)
+	region, err := a.Add("hero", original)
+	require.NoError(t, err)
+
+	hero := em.NewEntity()
+	heroSprite := ecs.AddComponent[render.Sprite](em, hero)
+	heroSprite.Image = original
+	heroSprite.Source = image.Rect(16, 0, 32, 16)
+
+	stranger := em.NewEntity()
+	ecs.AddComponent[render.Sprite](em, stranger).Image = other
+
+	sm.Add(atlas.NewSystem(1, 0, a))
+	require.NoError(t, sm.Update())
+
+	assert.Same(t, region, heroSprite.Image)
+	assert.Equal(t, image.Rect(16, 0, 32, 16), heroSprite.Source, "sources stay relative to the image")
+	assert.Equal(t, image.Rect(27, 0, 43, 16), heroSprite.SubImage().Bounds())
+	assert.Same(t, other, ecs.MustGetComponent[render.Sprite](em, stranger).Image)
+
+	assert.Zero(t, a.Rewrite(em), "sprites are rewritten once")
+}
@@ -0,0 +1,102 @@
+package atlas
+
+import "image"
+
+// skyline packs rectangles into a fixed-size page with the skyline bottom-left heuristic:
+// the top edge of the packed rectangles is kept as a list of horizontal segments, and each
+// rectangle is placed on the segment where its top ends lowest, then leftmost.
+type skyline struct {
+	width, height int
+	// segments cover the width of the page from left to right.
+	segments []segment
+}
+
+// segment is a horizontal part of the skyline, width wide at height y.
+type segment struct {
+	x, y, width int
+}
+
+func newSkyline(width, height int) *skyline {
+	return &skyline{
+		width:    width,
+		height:   height,
+		segments: []segment{{width: width}},
+	}
+}
+
+// insert reserves a width x height rectangle and returns its top-left corner, or false if it does not fit.
+func (s *skyline) insert(width, height int) (image.Point, bool) {
+	best, bestY, bestX := -1, 0, 0
+	for i := range s.segments {
+		y, ok := s.fit(i, width, height)
+		if !ok {
+			continue
+		}
+
+		if best < 0 || y < bestY || (y == bestY && s.segments[i].x < bestX) {
+			best, bestY, bestX = i, y, s.segments[i].x
+		}
+	}
+
+	if best < 0 {
+		return image.Point{}, false
+	}
+
+	s.raise(best, width, bestY+height)
+
+	return image.Pt(bestX, bestY), true
+}
+
+// fit returns the height at which a width x height rectangle placed at the left of segment i rests,
+// or false if it would leave the page.
+func (s *skyline) fit(i, width, height int) (int, bool) {
+	x := s.segments[i].x
+	if x+width > s.width {
+		return 0, false
+	}
+
+	y := 0
+	for remaining := width; remaining > 0; i++ {
+		y = max(y, s.segments[i].y)
+		if y+height > s.height {
+			return 0, false
+		}
+
+		remaining -= s.segments[i].width
+	}
+
+	return y, true
+}
+
+// raise adds a segment of the given width at height y starting at the left of segment i, shortening
+// or removing the segments it covers, and merges it with neighbours of the same height.
+func (s *skyline) raise(i, width, y int) {
+	x := s.segments[i].x
+	s.segments = append(s.segments[:i], append([]segment{{x: x, y: y, width: width}}, s.segments[i:]...)...)
+
+	end := x + width
+	for j := i + 1; j < len(s.segments); {
+		next := &s.segments[j]
+		if next.x >= end {
+			break
+		}
+
+		if next.x+next.width <= end {
+			s.segments = append(s.segments[:j], s.segments[j+1:]...)
+			continue
+		}
+
+		next.width -= end - next.x
+		next.x = end
+		break
+	}
+
+	for j := 0; j+1 < len(s.segments); {
+		if s.segments[j].y == s.segments[j+1].y {
+			s.segments[j].width += s.segments[j+1].width
+			s.segments = append(s.segments[:j+1], s.segments[j+2:]...)
+			continue
+		}
+		j++
+	}
+}
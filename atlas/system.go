@@ -0,0 +1,27 @@
+package atlas
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// System moves the sprites drawing images added to an Atlas to the atlas on every update, so that
+// sprites spawned with the original images are batched without changing the code spawning them.
+type System struct {
+	*ecs.BaseSystem
+
+	atlas *Atlas
+}
+
+// NewSystem creates a new atlas System rewriting the sprites of atlas, with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int, atlas *Atlas) *System {
+	return &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		atlas:      atlas,
+	}
+}
+
+// Update rewrites the sprites drawing original images, see Atlas.Rewrite.
+func (s *System) Update() error {
+	s.atlas.Rewrite(s.EntityManager())
+	return nil
+}
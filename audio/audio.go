@@ -0,0 +1,84 @@
+// Package audio plays sound effects and music through volume buses. Playback is delegated to players
+// created by a NewPlayerFunc, usually wrapping an ebiten/audio Context, so that this package and the games
+// importing it still build on dedicated servers without audio libraries:
+//
+//	ctx := ebitenaudio.NewContext(44100)
+//	system := audio.NewSystem(id, 0, func(src io.Reader) (audio.Player, error) {
+//		return ctx.NewPlayer(src)
+//	})
+package audio
+
+import "io"
+
+// Player plays a stream of decoded PCM data. *audio.Player from github.com/hajimehoshi/ebiten/v2/audio
+// implements it.
+type Player interface {
+	Play()
+	Pause()
+	IsPlaying() bool
+	// Rewind moves back to the start of the stream, which must be an io.Seeker.
+	Rewind() error
+	SetVolume(volume float64)
+	Close() error
+}
+
+// NewPlayerFunc creates a player for src, which holds PCM data decoded at the sample rate of the audio context,
+// such as the output of ebiten's wav.DecodeWithSampleRate.
+type NewPlayerFunc func(src io.Reader) (Player, error)
+
+// Bus is a volume category. The volume of a sound is multiplied by the volume of its bus and of BusMaster.
+type Bus int
+
+const (
+	// BusSFX is the bus of sound effects, the default of PlaySound.
+	BusSFX Bus = iota
+	// BusMusic is the bus of the Music components.
+	BusMusic
+	// BusMaster scales every other bus.
+	BusMaster
+
+	busCount
+)
+
+// Volumes is the resource holding the volume of each bus, from 0 (muted) to 1, e.g. for the sliders
+// of an options menu. It is shared by the audio Systems of all worlds.
+type Volumes struct {
+	levels [busCount]float64
+}
+
+// NewVolumes creates volumes with every bus at 1.
+func NewVolumes() *Volumes {
+	v := &Volumes{}
+	for i := range v.levels {
+		v.levels[i] = 1
+	}
+
+	return v
+}
+
+// Set sets the volume of the bus, clamped between 0 and 1.
+func (v *Volumes) Set(bus Bus, volume float64) {
+	if bus < 0 || bus >= busCount {
+		return
+	}
+
+	v.levels[bus] = min(max(volume, 0), 1)
+}
+
+// Get returns the volume of the bus.
+func (v *Volumes) Get(bus Bus) float64 {
+	if bus < 0 || bus >= busCount {
+		return 0
+	}
+
+	return v.levels[bus]
+}
+
+// Effective returns the volume of the bus scaled by the master volume.
+func (v *Volumes) Effective(bus Bus) float64 {
+	if bus == BusMaster {
+		return v.Get(BusMaster)
+	}
+
+	return v.Get(bus) * v.Get(BusMaster)
+}
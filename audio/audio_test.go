@@ -0,0 +1,189 @@
+package audio_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/audio"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+// fakePlayer plays until the test stops it, as if the stream ended.
+type fakePlayer struct {
+	src     io.Reader
+	playing bool
+	volume  float64
+	rewinds int
+	closed  bool
+}
+
+func (p *fakePlayer) Play()                    { p.playing = true }
+func (p *fakePlayer) Pause()                   { p.playing = false }
+func (p *fakePlayer) IsPlaying() bool          { return p.playing }
+func (p *fakePlayer) Rewind() error            { p.rewinds++; return nil }
+func (p *fakePlayer) SetVolume(volume float64) { p.volume = volume }
+func (p *fakePlayer) Close() error             { p.closed = true; return nil }
+
+type fakeContext struct {
+	players []*fakePlayer
+}
+
+func (c *fakeContext) newPlayer(src io.Reader) (audio.Player, error) {
+	player := &fakePlayer{src: src}
+	c.players = append(c.players, player)
+	return player, nil
+}
+
+func newTestSystem(t *testing.T) (*ecs.Game, *ecs.EntityManager, *ecs.SystemManager, *audio.System, *fakeContext) {
+	t.Helper()
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	ctx := &fakeContext{}
+	system := audio.NewSystem(ecs.NextID(), 0, ctx.newPlayer)
+	sm.Add(system)
+	system.OnWorldStart(nil)
+
+	return game, em, sm, system, ctx
+}
+
+func TestPlaySound(t *testing.T) {
+	game, _, sm, system, ctx := newTestSystem(t)
+
+	resource, ok := ecs.GetResource[audio.System](game.Resources())
+	require.True(t, ok, "the system is a resource")
+	assert.Same(t, system, resource)
+
+	volumes := ecs.MustGetResource[audio.Volumes](game.Resources())
+	volumes.Set(audio.BusSFX, 0.5)
+	volumes.Set(audio.BusMaster, 2)
+	assert.Equal(t, 1.0, volumes.Get(audio.BusMaster), "volumes are clamped")
+
+	assert.ErrorIs(t, system.PlaySound("jump", nil), audio.ErrSoundNotFound)
+
+	system.AddSound("jump", []byte{1, 2, 3, 4})
+	require.NoError(t, system.PlaySound("jump", &audio.SoundOptions{Volume: 0.5}))
+	require.Len(t, ctx.players, 1)
+	jump := ctx.players[0]
+	assert.True(t, jump.playing)
+	assert.Equal(t, 0.25, jump.volume)
+
+	volumes.Set(audio.BusMaster, 0.5)
+	require.NoError(t, sm.Update())
+	assert.Equal(t, 0.125, jump.volume, "bus volumes apply to playing sounds")
+
+	jump.playing = false
+	require.NoError(t, sm.Update())
+	assert.True(t, jump.closed, "ended sounds are closed")
+}
+
+func TestMusicCrossfade(t *testing.T) {
+	game, em, sm, system, ctx := newTestSystem(t)
+	system.Volumes().Set(audio.BusMusic, 0.5)
+
+	step := func() {
+		game.Time().Advance(0.5)
+		require.NoError(t, sm.Update())
+	}
+
+	menu := em.NewEntity()
+	*ecs.AddComponent[audio.Music](em, menu) = audio.Music{Source: bytes.NewReader(nil), Loop: true, Volume: 1}
+
+	step()
+	require.Len(t, ctx.players, 1)
+	menuPlayer := ctx.players[0]
+	assert.True(t, menuPlayer.playing)
+	assert.Equal(t, 0.5, menuPlayer.volume, "without crossfade, tracks start at full volume")
+
+	menuPlayer.playing = false
+	step()
+	assert.True(t, menuPlayer.playing)
+	assert.Equal(t, 1, menuPlayer.rewinds, "looping tracks restart")
+
+	level := em.NewEntity()
+	*ecs.AddComponent[audio.Music](em, level) = audio.Music{Source: bytes.NewReader(nil), Volume: 1, Crossfade: time.Second}
+
+	step()
+	require.Len(t, ctx.players, 2)
+	levelPlayer := ctx.players[1]
+	assert.Equal(t, 0.25, levelPlayer.volume)
+	assert.Equal(t, 0.25, menuPlayer.volume)
+
+	step()
+	assert.Equal(t, 0.5, levelPlayer.volume)
+	assert.False(t, menuPlayer.playing, "faded out tracks are paused")
+	assert.False(t, ecs.MustGetComponent[audio.Music](em, menu).Playing())
+
+	levelPlayer.playing = false
+	step()
+	assert.True(t, ecs.MustGetComponent[audio.Music](em, level).Finished())
+	assert.Zero(t, levelPlayer.rewinds)
+
+	em.Remove(level)
+	assert.True(t, levelPlayer.closed, "removing the entity stops its track")
+
+	step()
+	assert.True(t, menuPlayer.playing, "the previous track resumes")
+	assert.Equal(t, 1, menuPlayer.rewinds)
+}
+
+func TestSpatialSource(t *testing.T) {
+	game, em, sm, system, ctx := newTestSystem(t)
+	system.AddSound("waterfall", []byte{0, 64, 0, 64})
+
+	listener := em.NewEntity()
+	ecs.AddComponent[audio.Listener](em, listener)
+
+	waterfall := em.NewEntity()
+	source := ecs.AddComponent[audio.SpatialSource](em, waterfall)
+	source.Sound = "waterfall"
+	source.Loop = true
+	source.MinDistance = 100
+	source.MaxDistance = 300
+	tr := ecs.MustGetComponent[transform.Transform](em, waterfall)
+	tr.Position = f64.Vec2{200, 0}
+
+	step := func() {
+		game.Time().Advance(0.1)
+		require.NoError(t, sm.Update())
+	}
+
+	step()
+	require.Len(t, ctx.players, 1)
+	player := ctx.players[0]
+	assert.True(t, player.playing)
+	assert.Equal(t, 0.5, player.volume, "halfway between the distances, the volume is halved")
+	assert.Equal(t, 1.0, source.Pan(), "sources on the right are panned right")
+
+	pcm, err := io.ReadAll(player.src)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 64}, pcm, "the left channel is silenced")
+
+	tr.Position = f64.Vec2{-50, 0}
+	step()
+	assert.Equal(t, 1.0, player.volume)
+	assert.Equal(t, -0.5, source.Pan(), "sources within the minimum distance are panned less")
+
+	ecs.MustGetComponent[transform.Transform](em, listener).Rotation = math.Pi
+	step()
+	assert.InDelta(t, 0.5, source.Pan(), 1e-9, "the listener rotation turns its sides")
+
+	tr.Position = f64.Vec2{400, 0}
+	player.playing = false
+	step()
+	assert.Zero(t, player.volume)
+	assert.True(t, player.playing)
+	assert.Equal(t, 1, player.rewinds, "looping sources restart")
+
+	em.Remove(waterfall)
+	assert.True(t, player.closed)
+}
@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"io"
+	"time"
+)
+
+// Music is a component playing a music track on BusMusic. The track of the Music entity with the highest ID
+// plays; when a newer one is added, it fades in over its Crossfade while the others fade out and pause.
+// Removing the entity stops its track.
+type Music struct {
+	// Source is the decoded PCM stream of the track. It must be an io.Seeker to Loop.
+	Source io.Reader
+	// Loop restarts the track when it ends.
+	Loop bool
+	// Volume scales the volume of the track, 1 by default.
+	Volume float64
+	// Crossfade is the duration of the fade in of the track, and of the fade out of the previous one.
+	Crossfade time.Duration
+
+	player   Player
+	fade     float64
+	paused   bool
+	started  bool
+	finished bool
+}
+
+// Init sets the volume to 1.
+func (m *Music) Init() {
+	m.Volume = 1
+}
+
+func (m *Music) Reset() {
+	if m.player != nil {
+		m.player.Close()
+	}
+
+	*m = Music{}
+}
+
+// Playing reports whether the track is audible: playing and not entirely faded out.
+func (m *Music) Playing() bool {
+	return m.player != nil && m.fade > 0 && m.player.IsPlaying()
+}
+
+// Finished reports whether a track without Loop has ended.
+func (m *Music) Finished() bool {
+	return m.finished
+}
+
+// Fade returns the fade level of the track, from 0 (silent) to 1.
+func (m *Music) Fade() float64 {
+	return m.fade
+}
+
+// pause pauses the player, keeping its position so that the track resumes if it becomes current again.
+func (m *Music) pause() {
+	if m.player != nil && m.player.IsPlaying() {
+		m.player.Pause()
+		m.paused = true
+	}
+}
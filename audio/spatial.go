@@ -0,0 +1,237 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+)
+
+func init() {
+	ecs.Require[Listener, transform.Transform]()
+	ecs.Require[SpatialSource, transform.Transform]()
+}
+
+// DefaultMaxDistance is the distance, in world units, beyond which spatial sources are silent,
+// unless their MaxDistance is changed.
+const DefaultMaxDistance = 640
+
+// Listener marks the entity hearing the SpatialSources, usually the render.Camera. A transform.Transform is
+// added with it if missing; its rotation turns the left and right of the listener. If several entities have
+// a Listener, the one with the lowest ID hears.
+type Listener struct{}
+
+// SpatialSource is a component playing a sound added with System.AddSound from the position of its entity's
+// transform.Transform, which is added with it if missing. Every update, the volume is attenuated with the
+// distance to the Listener and the sound panned to its side. Removing the entity stops the sound.
+type SpatialSource struct {
+	// Sound is the name of the sound played.
+	Sound string
+	// Loop restarts the sound when it ends, e.g. for a waterfall or an engine.
+	Loop bool
+	// Volume scales the volume of the sound, 1 by default.
+	Volume float64
+	// Bus is the volume bus of the sound, BusSFX by default.
+	Bus Bus
+	// The sound is at full volume within MinDistance of the listener and fades out linearly until MaxDistance,
+	// DefaultMaxDistance by default. Within MinDistance, it is also panned less.
+	MinDistance, MaxDistance float64
+
+	player   Player
+	stream   *PanStream
+	started  bool
+	finished bool
+}
+
+// Init sets the volume to 1 and the maximum distance to DefaultMaxDistance.
+func (s *SpatialSource) Init() {
+	s.Volume = 1
+	s.MaxDistance = DefaultMaxDistance
+}
+
+func (s *SpatialSource) Reset() {
+	if s.player != nil {
+		s.player.Close()
+	}
+
+	*s = SpatialSource{}
+}
+
+// Playing reports whether the sound is playing.
+func (s *SpatialSource) Playing() bool {
+	return s.player != nil && s.player.IsPlaying()
+}
+
+// Finished reports whether a sound without Loop has ended.
+func (s *SpatialSource) Finished() bool {
+	return s.finished
+}
+
+// Pan returns the current pan of the sound, from -1 (left) to 1 (right).
+func (s *SpatialSource) Pan() float64 {
+	if s.stream == nil {
+		return 0
+	}
+
+	return s.stream.Pan()
+}
+
+// Attenuation returns the gain of a sound at distance from the listener: 1 within minDistance, 0 beyond
+// maxDistance and linear in between.
+func Attenuation(distance, minDistance, maxDistance float64) float64 {
+	switch {
+	case distance <= minDistance:
+		return 1
+	case distance >= maxDistance:
+		return 0
+	default:
+		return 1 - (distance-minDistance)/(maxDistance-minDistance)
+	}
+}
+
+// PanStream pans a stream of 16-bit signed little-endian stereo PCM data, the format of ebiten's
+// audio.Context.NewPlayer, by attenuating the channel opposite to the pan. The pan can be changed
+// while the stream is read by the audio thread.
+type PanStream struct {
+	src io.ReadSeeker
+	pan atomic.Uint64
+}
+
+// NewPanStream creates a centered PanStream reading src.
+func NewPanStream(src io.ReadSeeker) *PanStream {
+	return &PanStream{src: src}
+}
+
+// Pan returns the pan, from -1 (left) to 1 (right).
+func (s *PanStream) Pan() float64 {
+	return math.Float64frombits(s.pan.Load())
+}
+
+// SetPan sets the pan, clamped between -1 (left) and 1 (right).
+func (s *PanStream) SetPan(pan float64) {
+	s.pan.Store(math.Float64bits(min(max(pan, -1), 1)))
+}
+
+// Read reads whole stereo frames of 4 bytes from the source and pans them.
+func (s *PanStream) Read(p []byte) (int, error) {
+	const frameSize = 4
+
+	p = p[:len(p)/frameSize*frameSize]
+	n, err := s.src.Read(p)
+	if rest := n % frameSize; rest != 0 {
+		var m int
+		m, err = io.ReadFull(s.src, p[n:n+frameSize-rest])
+		n += m
+	}
+
+	pan := s.Pan()
+	left, right := min(1-pan, 1), min(1+pan, 1)
+	if left == 1 && right == 1 {
+		return n, err
+	}
+
+	for i := 0; i+frameSize <= n; i += frameSize {
+		scaleSample(p[i:i+2], left)
+		scaleSample(p[i+2:i+4], right)
+	}
+
+	return n, err
+}
+
+// Seek seeks the source, e.g. when the player is rewound.
+func (s *PanStream) Seek(offset int64, whence int) (int64, error) {
+	return s.src.Seek(offset, whence)
+}
+
+func scaleSample(b []byte, gain float64) {
+	sample := int16(binary.LittleEndian.Uint16(b))
+	binary.LittleEndian.PutUint16(b, uint16(int16(float64(sample)*gain)))
+}
+
+// updateSpatial starts, attenuates, pans and loops the spatial sources around the listener.
+func (s *System) updateSpatial() error {
+	em := s.EntityManager()
+
+	var listener *transform.Transform
+	listenerID, found := ecs.UndefinedID, false
+	for entityID := range ecs.Query2[Listener, transform.Transform](em) {
+		if !found || entityID < listenerID {
+			listenerID, found = entityID, true
+		}
+	}
+
+	if found {
+		listener = ecs.MustGetComponent[transform.Transform](em, listenerID)
+	}
+
+	for entityID := range ecs.Query2[SpatialSource, transform.Transform](em) {
+		source := ecs.MustGetComponent[SpatialSource](em, entityID)
+		if source.finished {
+			continue
+		}
+
+		if source.player == nil {
+			pcm, ok := s.sounds[source.Sound]
+			if !ok {
+				return fmt.Errorf("audio.System.updateSpatial error: %q: %w", source.Sound, ErrSoundNotFound)
+			}
+
+			source.stream = NewPanStream(bytes.NewReader(pcm))
+			player, err := s.newPlayer(source.stream)
+			if err != nil {
+				return fmt.Errorf("audio.System.updateSpatial s.newPlayer error: %w", err)
+			}
+			source.player = player
+		}
+
+		gain, pan := 1.0, 0.0
+		if listener != nil {
+			gain, pan = spatialize(source, ecs.MustGetComponent[transform.Transform](em, entityID), listener)
+		}
+
+		source.stream.SetPan(pan)
+		source.player.SetVolume(source.Volume * gain * s.volumes.Effective(source.Bus))
+
+		if source.player.IsPlaying() {
+			continue
+		}
+
+		if source.started {
+			if !source.Loop {
+				source.finished = true
+				continue
+			}
+
+			if err := source.player.Rewind(); err != nil {
+				return fmt.Errorf("audio.System.updateSpatial source.player.Rewind error: %w", err)
+			}
+		}
+
+		source.player.Play()
+		source.started = true
+	}
+
+	return nil
+}
+
+// spatialize returns the gain and the pan of source at tr, heard by the listener: the offset to the source is
+// turned into the listener's frame, and panned by its direction, less within MinDistance.
+func spatialize(source *SpatialSource, tr, listener *transform.Transform) (gain, pan float64) {
+	dx, dy := tr.Position[0]-listener.Position[0], tr.Position[1]-listener.Position[1]
+	distance := math.Hypot(dx, dy)
+
+	sin, cos := math.Sincos(-listener.Rotation)
+	right := dx*cos - dy*sin
+
+	gain = Attenuation(distance, source.MinDistance, source.MaxDistance)
+	if distance > 0 {
+		pan = right / max(distance, source.MinDistance)
+	}
+
+	return gain, pan
+}
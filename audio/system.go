@@ -0,0 +1,239 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.WorldStarter = (*System)(nil)
+
+// ErrSoundNotFound is returned by PlaySound for sounds that have not been added.
+var ErrSoundNotFound = errors.New("sound not found")
+
+// SoundOptions configures a sound played with PlaySound.
+type SoundOptions struct {
+	// Volume scales the volume of the sound. Zero is treated as 1.
+	Volume float64
+	// Bus is the volume bus of the sound, BusSFX by default.
+	Bus Bus
+}
+
+// voice is a sound effect being played.
+type voice struct {
+	player Player
+	volume float64
+	bus    Bus
+}
+
+// System plays the sound effects started with PlaySound, the Music components and the SpatialSources around
+// the Listener, with the volumes of the Volumes resource. When its world starts, it registers itself and the
+// Volumes as resources of the game, so that other systems can play sounds:
+//
+//	ecs.MustGetResource[audio.System](s.Game().Resources()).PlaySound("jump", nil)
+type System struct {
+	*ecs.BaseSystem
+
+	newPlayer NewPlayerFunc
+	volumes   *Volumes
+
+	sounds map[string][]byte
+	voices []voice
+}
+
+// NewSystem creates a new audio System with the given ID and priority, playing through the players
+// created by newPlayer.
+func NewSystem(id ecs.SystemID, priority int, newPlayer NewPlayerFunc) *System {
+	return &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		newPlayer:  newPlayer,
+		volumes:    NewVolumes(),
+		sounds:     make(map[string][]byte),
+	}
+}
+
+// OnWorldStart registers the system as a resource, and adopts the Volumes resource of the game,
+// or registers its own if there is none.
+func (s *System) OnWorldStart(ecs.World) {
+	game := s.Game()
+	if game == nil {
+		return
+	}
+
+	ecs.SetResource(game.Resources(), s)
+	if volumes, ok := ecs.GetResource[Volumes](game.Resources()); ok {
+		s.volumes = volumes
+	} else {
+		ecs.SetResource(game.Resources(), s.volumes)
+	}
+}
+
+// Volumes returns the bus volumes used by the system.
+func (s *System) Volumes() *Volumes {
+	return s.volumes
+}
+
+// AddSound registers a sound effect under name. pcm is the decoded PCM data of the whole sound,
+// shared by all the plays of the sound.
+func (s *System) AddSound(name string, pcm []byte) {
+	s.sounds[name] = pcm
+}
+
+// PlaySound plays the sound added under name, fire-and-forget: the player is closed when it ends.
+// opts may be nil.
+func (s *System) PlaySound(name string, opts *SoundOptions) error {
+	pcm, ok := s.sounds[name]
+	if !ok {
+		return fmt.Errorf("audio.System.PlaySound error: %q: %w", name, ErrSoundNotFound)
+	}
+
+	v := voice{volume: 1, bus: BusSFX}
+	if opts != nil {
+		if opts.Volume != 0 {
+			v.volume = opts.Volume
+		}
+		v.bus = opts.Bus
+	}
+
+	player, err := s.newPlayer(bytes.NewReader(pcm))
+	if err != nil {
+		return fmt.Errorf("audio.System.PlaySound s.newPlayer error: %w", err)
+	}
+	v.player = player
+
+	player.SetVolume(v.volume * s.volumes.Effective(v.bus))
+	player.Play()
+	s.voices = append(s.voices, v)
+
+	return nil
+}
+
+// Update releases the sound effects that ended, applies the bus volumes, fades the music
+// and spatializes the SpatialSources.
+func (s *System) Update() error {
+	s.voices = s.updateVoices(s.voices)
+
+	if err := s.updateSpatial(); err != nil {
+		return fmt.Errorf("audio.System.Update s.updateSpatial error: %w", err)
+	}
+
+	if err := s.updateMusic(s.Time().DeltaDuration()); err != nil {
+		return fmt.Errorf("audio.System.Update s.updateMusic error: %w", err)
+	}
+
+	return nil
+}
+
+// Teardown stops the sound effects and pauses the music and the spatial sources.
+func (s *System) Teardown() {
+	for _, v := range s.voices {
+		v.player.Close()
+	}
+	s.voices = s.voices[:0]
+
+	em := s.EntityManager()
+	for entityID := range ecs.Query[Music](em) {
+		ecs.MustGetComponent[Music](em, entityID).pause()
+	}
+
+	for entityID := range ecs.Query[SpatialSource](em) {
+		if source := ecs.MustGetComponent[SpatialSource](em, entityID); source.Playing() {
+			source.player.Pause()
+		}
+	}
+}
+
+func (s *System) updateVoices(voices []voice) []voice {
+	playing := voices[:0]
+	for _, v := range voices {
+		if !v.player.IsPlaying() {
+			v.player.Close()
+			continue
+		}
+
+		v.player.SetVolume(v.volume * s.volumes.Effective(v.bus))
+		playing = append(playing, v)
+	}
+	clear(voices[len(playing):])
+
+	return playing
+}
+
+// updateMusic fades the current track in and the others out, and starts, loops or pauses their players.
+func (s *System) updateMusic(dt time.Duration) error {
+	em := s.EntityManager()
+
+	current, found := ecs.UndefinedID, false
+	for entityID := range ecs.Query[Music](em) {
+		if !found || entityID > current {
+			current, found = entityID, true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	step := 1.0
+	if crossfade := ecs.MustGetComponent[Music](em, current).Crossfade; crossfade > 0 {
+		step = float64(dt) / float64(crossfade)
+	}
+
+	for entityID := range ecs.Query[Music](em) {
+		music := ecs.MustGetComponent[Music](em, entityID)
+		if entityID == current {
+			music.fade = min(music.fade+step, 1)
+		} else {
+			music.fade = max(music.fade-step, 0)
+		}
+
+		if music.fade == 0 {
+			music.pause()
+			continue
+		}
+
+		if err := s.playMusic(music); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *System) playMusic(music *Music) error {
+	if music.finished || music.Source == nil {
+		return nil
+	}
+
+	if music.player == nil {
+		player, err := s.newPlayer(music.Source)
+		if err != nil {
+			return fmt.Errorf("audio.System.playMusic s.newPlayer error: %w", err)
+		}
+		music.player = player
+	}
+
+	music.player.SetVolume(music.Volume * music.fade * s.volumes.Effective(BusMusic))
+	if music.player.IsPlaying() {
+		return nil
+	}
+
+	switch {
+	case !music.started || music.paused:
+	case music.Loop:
+		if err := music.player.Rewind(); err != nil {
+			return fmt.Errorf("audio.System.playMusic music.player.Rewind error: %w", err)
+		}
+	default:
+		music.finished = true
+		return nil
+	}
+
+	music.player.Play()
+	music.started, music.paused = true, false
+
+	return nil
+}
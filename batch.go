@@ -0,0 +1,102 @@
+package ecs
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// AddComponentToQuery adds component C, set to value, to every entity yielded by query that does not have it yet,
+// e.g. applying a Frozen component to all enemies in an area. Entities that already have C are left untouched.
+// The query is fully consumed before any component is added, so it may depend on C itself.
+// The component storage is resolved and grown once for the whole batch.
+// It returns the number of entities that received the component.
+func AddComponentToQuery[C any](em *EntityManager, query iter.Seq[EntityID], value C) int {
+	defer em.beginStructural("AddComponentToQuery")()
+
+	componentType := reflect.TypeFor[C]()
+
+	buf := getEntityIDBuffer()
+	defer putEntityIDBuffer(buf)
+
+	entityIDs := slices.DeleteFunc(slices.AppendSeq(*buf, query), func(entityID EntityID) bool {
+		if _, exists := em.entities[entityID]; !exists {
+			return true
+		}
+
+		return em.hasComponentType(entityID, componentType)
+	})
+
+	*buf = entityIDs
+	if len(entityIDs) == 0 {
+		return 0
+	}
+
+	container := componentContainer[C](em)
+	container.Reserve(len(entityIDs))
+
+	for _, entityID := range entityIDs {
+		em.checkRequired(entityID, componentType)
+	}
+
+	for _, entityID := range entityIDs {
+		component := container.Add(entityID).(*C)
+		*component = value
+		em.setComponentBit(entityID, componentType)
+		em.logComponent("component added", entityID, componentType)
+		em.addRequired(entityID, componentType)
+	}
+
+	return len(entityIDs)
+}
+
+// RemoveComponentFromQuery removes component C from every entity yielded by query.
+// The query is fully consumed before any component is removed, so it may depend on C itself.
+// It returns the number of entities that lost the component.
+func RemoveComponentFromQuery[C any](em *EntityManager, query iter.Seq[EntityID]) int {
+	defer em.beginStructural("RemoveComponentFromQuery")()
+
+	componentType := reflect.TypeFor[C]()
+
+	container, exists := em.componentContainers[componentType]
+	if !exists {
+		return 0
+	}
+
+	buf := getEntityIDBuffer()
+	defer putEntityIDBuffer(buf)
+
+	*buf = slices.AppendSeq(*buf, query)
+
+	removed := 0
+	for _, entityID := range *buf {
+		if !em.hasComponentType(entityID, componentType) {
+			continue
+		}
+
+		container.Remove(entityID)
+		em.clearComponentBit(entityID, componentType)
+		em.logComponent("component removed", entityID, componentType)
+		removed++
+	}
+
+	return removed
+}
+
+// entityIDBuffers pools the slices batch operations collect their queries into.
+var entityIDBuffers = sync.Pool{
+	New: func() any {
+		buf := make([]EntityID, 0, 256)
+		return &buf
+	},
+}
+
+func getEntityIDBuffer() *[]EntityID {
+	return entityIDBuffers.Get().(*[]EntityID)
+}
+
+func putEntityIDBuffer(buf *[]EntityID) {
+	*buf = (*buf)[:0]
+	entityIDBuffers.Put(buf)
+}
@@ -0,0 +1,51 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type FrozenComponent struct {
+	Seconds float64
+}
+
+func TestAddComponentToQuery(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	for range 10 {
+		NewCameraEntity(t, em)
+	}
+	for range 5 {
+		NewPlayerEntity(t, em)
+	}
+
+	frozen := ecs.AddComponentToQuery(em, ecs.Query[CameraComponent](em), FrozenComponent{Seconds: 2})
+	assert.Equal(t, 10, frozen)
+	assert.Equal(t, 10, ecs.Count(ecs.Query2[CameraComponent, FrozenComponent](em)))
+
+	for entityID := range ecs.Query[FrozenComponent](em) {
+		assert.Equal(t, 2.0, ecs.MustGetComponent[FrozenComponent](em, entityID).Seconds)
+	}
+
+	// Entities that already have the component are skipped.
+	frozen = ecs.AddComponentToQuery(em, ecs.Query[TransformComponent](em), FrozenComponent{Seconds: 5})
+	assert.Equal(t, 5, frozen)
+	assert.Equal(t, 15, ecs.Count(ecs.Query[FrozenComponent](em)))
+
+	removed := ecs.RemoveComponentFromQuery[FrozenComponent](em, ecs.Query[FrozenComponent](em))
+	assert.Equal(t, 15, removed)
+	assert.Equal(t, 0, ecs.Count(ecs.Query[FrozenComponent](em)))
+}
+
+func TestRemoveComponent(t *testing.T) {
+	em := ecs.NewEntityManager()
+	camera := NewCameraEntity(t, em)
+
+	ecs.RemoveComponent[CameraComponent](em, camera)
+
+	assert.False(t, ecs.HasComponent[CameraComponent](em, camera))
+	assert.True(t, ecs.HasComponent[TransformComponent](em, camera))
+	assert.Equal(t, 0, ecs.Count(ecs.Query[CameraComponent](em)))
+}
@@ -0,0 +1,181 @@
+// Package behavior runs behavior trees for AI entities.
+//
+// A Tree is an immutable description shared by any number of entities; each entity with an Agent
+// component keeps its own Blackboard and the state of its running nodes, and is ticked by a System
+// every update:
+//
+//	tree, err := behavior.NewBuilder().
+//		Selector().
+//			Sequence().
+//				Condition(seesPlayer).
+//				Action(chase).
+//			End().
+//			Sequence().
+//				Action(pickWaypoint).
+//				Action(walkToWaypoint).
+//				Wait(time.Second).
+//			End().
+//		End().
+//		Build()
+//
+//	ecs.AddComponent[behavior.Agent](em, goblin).Tree = tree
+package behavior
+
+import (
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Status is the result of ticking a node.
+type Status int
+
+const (
+	// Running means the node has not finished and must be ticked again on the next update.
+	Running Status = iota
+	Success
+	Failure
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "Running"
+	case Success:
+		return "Success"
+	case Failure:
+		return "Failure"
+	default:
+		return "Status(?)"
+	}
+}
+
+// Context is passed to the nodes of the tree being ticked.
+type Context struct {
+	EntityManager *ecs.EntityManager
+	// EntityID is the agent entity.
+	EntityID ecs.EntityID
+	// Blackboard is the agent's blackboard.
+	Blackboard Blackboard
+	// Delta is the scaled duration of the current update.
+	Delta time.Duration
+
+	agent *Agent
+}
+
+// State returns the per-agent state of node, creating it with init on first use.
+// Nodes that span several ticks keep their progress there, since the tree is shared by all agents.
+func State[S any](ctx *Context, node Node, init func() *S) *S {
+	if state, ok := ctx.agent.states[node]; ok {
+		return state.(*S)
+	}
+
+	if ctx.agent.states == nil {
+		ctx.agent.states = make(map[Node]any)
+	}
+
+	state := init()
+	ctx.agent.states[node] = state
+
+	return state
+}
+
+// Node is a node of a behavior tree.
+type Node interface {
+	Tick(ctx *Context) Status
+}
+
+// Tree is a behavior tree, built with a Builder or NewTree.
+type Tree struct {
+	root Node
+}
+
+// NewTree creates a tree from its root node.
+func NewTree(root Node) *Tree {
+	return &Tree{root: root}
+}
+
+// Tick ticks the tree for an agent.
+func (t *Tree) Tick(ctx *Context) Status {
+	return t.root.Tick(ctx)
+}
+
+// Blackboard is the per-agent memory shared by the nodes of a tree.
+type Blackboard map[string]any
+
+// Get returns the value stored under key, if it has type T.
+func Get[T any](bb Blackboard, key string) (T, bool) {
+	value, ok := bb[key].(T)
+	return value, ok
+}
+
+// Agent is the component attaching a behavior tree to an entity.
+type Agent struct {
+	Tree       *Tree
+	Blackboard Blackboard
+
+	status Status
+	states map[Node]any
+}
+
+func (a *Agent) Reset() {
+	*a = Agent{}
+}
+
+// Status returns the status of the last tick.
+func (a *Agent) Status() Status {
+	return a.status
+}
+
+// Restart drops the state of the running nodes, so the next tick starts the tree from the root.
+// The blackboard is kept.
+func (a *Agent) Restart() {
+	clear(a.states)
+	a.status = Running
+}
+
+// System ticks the tree of every Agent once per update.
+type System struct {
+	*ecs.BaseSystem
+
+	ctx Context
+}
+
+// NewSystem creates a new behavior System with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int) *System {
+	s := &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+	s.SetAccess(ecs.SystemAccess{Writes: ecs.Types(Agent{})})
+
+	return s
+}
+
+// Update ticks all agents.
+func (s *System) Update() error {
+	em := s.EntityManager()
+
+	s.ctx.EntityManager = em
+	s.ctx.Delta = s.Time().DeltaDuration()
+
+	for entityID := range ecs.Query[Agent](em) {
+		agent := ecs.MustGetComponent[Agent](em, entityID)
+		if agent.Tree == nil {
+			continue
+		}
+
+		if agent.Blackboard == nil {
+			agent.Blackboard = make(Blackboard)
+		}
+
+		s.ctx.EntityID = entityID
+		s.ctx.Blackboard = agent.Blackboard
+		s.ctx.agent = agent
+
+		agent.status = agent.Tree.Tick(&s.ctx)
+	}
+
+	s.ctx.agent = nil
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package behavior_test
+
+import (
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/behavior"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+	sm.Add(behavior.NewSystem(ecs.NextID(), 0))
+
+	var log []string
+	record := func(name string, status behavior.Status) func(*behavior.Context) behavior.Status {
+		return func(ctx *behavior.Context) behavior.Status {
+			log = append(log, name)
+			return status
+		}
+	}
+
+	seesPlayer := func(ctx *behavior.Context) bool {
+		sees, _ := behavior.Get[bool](ctx.Blackboard, "seesPlayer")
+		return sees
+	}
+
+	tree, err := behavior.NewBuilder().
+		Selector().
+		Sequence().
+		Condition(seesPlayer).
+		Action(record("chase", behavior.Success)).
+		End().
+		Sequence().
+		Action(record("patrol", behavior.Success)).
+		Wait(time.Second).
+		End().
+		End().
+		Build()
+	require.NoError(t, err)
+
+	guard := em.NewEntity()
+	agent := ecs.AddComponent[behavior.Agent](em, guard)
+	agent.Tree = tree
+
+	other := em.NewEntity()
+	otherAgent := ecs.AddComponent[behavior.Agent](em, other)
+	otherAgent.Tree = tree
+	otherAgent.Blackboard = behavior.Blackboard{"seesPlayer": true}
+
+	step := func() {
+		game.Time().Advance(0.6)
+		require.NoError(t, sm.Update())
+	}
+
+	step()
+	assert.Equal(t, []string{"patrol", "chase"}, log)
+	assert.Equal(t, behavior.Running, agent.Status(), "waiting")
+	assert.Equal(t, behavior.Success, otherAgent.Status(), "trees are shared, state is not")
+
+	log = nil
+	step()
+	assert.Equal(t, []string{"chase"}, log, "the running Wait is resumed without patrolling again")
+	assert.Equal(t, behavior.Success, agent.Status())
+
+	log = nil
+	step()
+	assert.Equal(t, []string{"patrol", "chase"}, log)
+}
+
+func TestDecorators(t *testing.T) {
+	tick := func(node behavior.Node) behavior.Status {
+		em := ecs.NewEntityManager()
+		agent := ecs.AddComponent[behavior.Agent](em, em.NewEntity())
+		agent.Tree = behavior.NewTree(node)
+
+		sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+		sm.Add(behavior.NewSystem(ecs.NextID(), 0))
+		require.NoError(t, sm.Update())
+
+		return agent.Status()
+	}
+
+	succeed := behavior.Action(func(*behavior.Context) behavior.Status { return behavior.Success })
+	fail := behavior.Action(func(*behavior.Context) behavior.Status { return behavior.Failure })
+
+	assert.Equal(t, behavior.Failure, tick(behavior.Invert(succeed)))
+	assert.Equal(t, behavior.Success, tick(behavior.AlwaysSucceed(fail)))
+	assert.Equal(t, behavior.Failure, tick(behavior.AlwaysFail(succeed)))
+	assert.Equal(t, behavior.Success, tick(behavior.Parallel(1, fail, succeed)))
+	assert.Equal(t, behavior.Failure, tick(behavior.Parallel(2, fail, succeed)))
+	assert.Equal(t, behavior.Success, tick(behavior.Repeat(1, succeed)))
+	assert.Equal(t, behavior.Running, tick(behavior.Repeat(0, succeed)))
+}
+
+func TestBuilderErrors(t *testing.T) {
+	_, err := behavior.NewBuilder().Sequence().Action(nil).Build()
+	assert.ErrorIs(t, err, behavior.ErrUnclosedNode)
+
+	_, err = behavior.NewBuilder().Sequence().End().Build()
+	assert.ErrorIs(t, err, behavior.ErrEmptyNode)
+
+	_, err = behavior.NewBuilder().End().Build()
+	assert.ErrorIs(t, err, behavior.ErrUnexpectedEnd)
+
+	_, err = behavior.NewBuilder().Wait(0).Wait(0).Build()
+	assert.ErrorIs(t, err, behavior.ErrNoRoot)
+
+	tree, err := behavior.NewBuilder().Invert().Repeat(2).Sequence().Wait(0).End().Build()
+	require.NoError(t, err)
+	assert.NotNil(t, tree)
+}
@@ -0,0 +1,160 @@
+package behavior
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrUnclosedNode is returned by Builder.Build when a composite or decorator has not been completed.
+	ErrUnclosedNode = errors.New("unclosed composite or decorator")
+	// ErrNoRoot is returned by Builder.Build when the tree is empty or has several roots.
+	ErrNoRoot = errors.New("tree must have exactly one root node")
+	// ErrUnexpectedEnd is returned by Builder.Build when End is called without an open composite.
+	ErrUnexpectedEnd = errors.New("End without an open composite")
+	// ErrEmptyNode is returned by Builder.Build when a composite or decorator has no child.
+	ErrEmptyNode = errors.New("composite or decorator without children")
+)
+
+// frame is a composite or decorator being built.
+type frame struct {
+	name     string
+	children []Node
+	// single marks decorators, which close as soon as their child is added.
+	single bool
+	build  func(children []Node) Node
+}
+
+// Builder builds a Tree with a fluent API. Composites are closed with End; decorators apply to the
+// node that follows them, which may itself be a composite.
+//
+// The first error, such as an End without a composite, is reported by Build.
+type Builder struct {
+	stack []*frame
+	err   error
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{stack: []*frame{{name: "root"}}}
+}
+
+func (b *Builder) open(f *frame) *Builder {
+	b.stack = append(b.stack, f)
+	return b
+}
+
+// add appends node to the innermost frame, closing the decorators it completes.
+func (b *Builder) add(node Node) *Builder {
+	for {
+		top := b.stack[len(b.stack)-1]
+		top.children = append(top.children, node)
+		if !top.single {
+			return b
+		}
+
+		b.stack = b.stack[:len(b.stack)-1]
+		node = top.build(top.children)
+	}
+}
+
+// Sequence opens a Sequence composite.
+func (b *Builder) Sequence() *Builder {
+	return b.open(&frame{name: "Sequence", build: func(children []Node) Node { return Sequence(children...) }})
+}
+
+// Selector opens a Selector composite.
+func (b *Builder) Selector() *Builder {
+	return b.open(&frame{name: "Selector", build: func(children []Node) Node { return Selector(children...) }})
+}
+
+// Parallel opens a Parallel composite.
+func (b *Builder) Parallel(successes int) *Builder {
+	return b.open(&frame{name: "Parallel", build: func(children []Node) Node { return Parallel(successes, children...) }})
+}
+
+// End closes the innermost composite.
+func (b *Builder) End() *Builder {
+	top := b.stack[len(b.stack)-1]
+	if len(b.stack) == 1 || top.single {
+		b.fail(fmt.Errorf("behavior.Builder.End: %w", ErrUnexpectedEnd))
+		return b
+	}
+
+	if len(top.children) == 0 {
+		b.fail(fmt.Errorf("behavior.Builder.End %s: %w", top.name, ErrEmptyNode))
+	}
+
+	b.stack = b.stack[:len(b.stack)-1]
+
+	return b.add(top.build(top.children))
+}
+
+// Invert decorates the next node with Invert.
+func (b *Builder) Invert() *Builder {
+	return b.open(&frame{name: "Invert", single: true, build: func(children []Node) Node { return Invert(children[0]) }})
+}
+
+// AlwaysSucceed decorates the next node with AlwaysSucceed.
+func (b *Builder) AlwaysSucceed() *Builder {
+	return b.open(&frame{name: "AlwaysSucceed", single: true, build: func(children []Node) Node { return AlwaysSucceed(children[0]) }})
+}
+
+// AlwaysFail decorates the next node with AlwaysFail.
+func (b *Builder) AlwaysFail() *Builder {
+	return b.open(&frame{name: "AlwaysFail", single: true, build: func(children []Node) Node { return AlwaysFail(children[0]) }})
+}
+
+// Repeat decorates the next node with Repeat.
+func (b *Builder) Repeat(times int) *Builder {
+	return b.open(&frame{name: "Repeat", single: true, build: func(children []Node) Node { return Repeat(times, children[0]) }})
+}
+
+// Cooldown decorates the next node with Cooldown.
+func (b *Builder) Cooldown(d time.Duration) *Builder {
+	return b.open(&frame{name: "Cooldown", single: true, build: func(children []Node) Node { return Cooldown(d, children[0]) }})
+}
+
+// Action adds an Action leaf.
+func (b *Builder) Action(fn func(ctx *Context) Status) *Builder {
+	return b.add(Action(fn))
+}
+
+// Condition adds a Condition leaf.
+func (b *Builder) Condition(fn func(ctx *Context) bool) *Builder {
+	return b.add(Condition(fn))
+}
+
+// Wait adds a Wait leaf.
+func (b *Builder) Wait(d time.Duration) *Builder {
+	return b.add(Wait(d))
+}
+
+// Node adds a custom node, or a subtree shared with other trees.
+func (b *Builder) Node(node Node) *Builder {
+	return b.add(node)
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Build returns the built tree.
+func (b *Builder) Build() (*Tree, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(b.stack) > 1 {
+		return nil, fmt.Errorf("behavior.Builder.Build %s: %w", b.stack[len(b.stack)-1].name, ErrUnclosedNode)
+	}
+
+	if len(b.stack[0].children) != 1 {
+		return nil, fmt.Errorf("behavior.Builder.Build %d roots: %w", len(b.stack[0].children), ErrNoRoot)
+	}
+
+	return NewTree(b.stack[0].children[0]), nil
+}
@@ -0,0 +1,231 @@
+package behavior
+
+import "time"
+
+// ActionFunc is a leaf node running a function.
+type ActionFunc func(ctx *Context) Status
+
+func (f ActionFunc) Tick(ctx *Context) Status {
+	return f(ctx)
+}
+
+// Action returns a leaf node running fn.
+func Action(fn func(ctx *Context) Status) Node {
+	return ActionFunc(fn)
+}
+
+// Condition returns a leaf node that succeeds if fn returns true and fails otherwise.
+func Condition(fn func(ctx *Context) bool) Node {
+	return ActionFunc(func(ctx *Context) Status {
+		if fn(ctx) {
+			return Success
+		}
+		return Failure
+	})
+}
+
+type wait struct {
+	duration time.Duration
+}
+
+// Wait returns a leaf node that is Running for d of game time, then succeeds.
+func Wait(d time.Duration) Node {
+	return &wait{duration: d}
+}
+
+func (n *wait) Tick(ctx *Context) Status {
+	elapsed := State(ctx, n, func() *time.Duration { return new(time.Duration) })
+
+	*elapsed += ctx.Delta
+	if *elapsed < n.duration {
+		return Running
+	}
+
+	*elapsed = 0
+	return Success
+}
+
+type sequence struct {
+	children []Node
+}
+
+// Sequence returns a composite node ticking its children in order until one fails.
+// It succeeds if all children succeed. A Running child is resumed on the next tick.
+func Sequence(children ...Node) Node {
+	return &sequence{children: children}
+}
+
+func (n *sequence) Tick(ctx *Context) Status {
+	return tickInOrder(ctx, n, n.children, Success)
+}
+
+type selector struct {
+	children []Node
+}
+
+// Selector returns a composite node ticking its children in order until one succeeds.
+// It fails if all children fail. A Running child is resumed on the next tick.
+func Selector(children ...Node) Node {
+	return &selector{children: children}
+}
+
+func (n *selector) Tick(ctx *Context) Status {
+	return tickInOrder(ctx, n, n.children, Failure)
+}
+
+// tickInOrder ticks children from the running one while they return next, and remembers the running child.
+func tickInOrder(ctx *Context, node Node, children []Node, next Status) Status {
+	current := State(ctx, node, func() *int { return new(int) })
+
+	for *current < len(children) {
+		status := children[*current].Tick(ctx)
+		if status == Running {
+			return Running
+		}
+
+		if status != next {
+			*current = 0
+			return status
+		}
+
+		*current++
+	}
+
+	*current = 0
+	return next
+}
+
+type parallel struct {
+	successes int
+	children  []Node
+}
+
+// Parallel returns a composite node ticking all its children on every tick. It succeeds once at least
+// successes children succeed in the same tick and fails once that is no longer possible; otherwise it is Running.
+// Children are ticked again even if they finished on a previous tick.
+func Parallel(successes int, children ...Node) Node {
+	return &parallel{successes: successes, children: children}
+}
+
+func (n *parallel) Tick(ctx *Context) Status {
+	succeeded, failed := 0, 0
+	for _, child := range n.children {
+		switch child.Tick(ctx) {
+		case Success:
+			succeeded++
+		case Failure:
+			failed++
+		}
+	}
+
+	switch {
+	case succeeded >= n.successes:
+		return Success
+	case failed > len(n.children)-n.successes:
+		return Failure
+	default:
+		return Running
+	}
+}
+
+type invert struct {
+	child Node
+}
+
+// Invert returns a decorator swapping the Success and Failure of its child.
+func Invert(child Node) Node {
+	return &invert{child: child}
+}
+
+func (n *invert) Tick(ctx *Context) Status {
+	switch status := n.child.Tick(ctx); status {
+	case Success:
+		return Failure
+	case Failure:
+		return Success
+	default:
+		return status
+	}
+}
+
+type force struct {
+	status Status
+	child  Node
+}
+
+// AlwaysSucceed returns a decorator that succeeds once its child finishes, whatever its result.
+func AlwaysSucceed(child Node) Node {
+	return &force{status: Success, child: child}
+}
+
+// AlwaysFail returns a decorator that fails once its child finishes, whatever its result.
+func AlwaysFail(child Node) Node {
+	return &force{status: Failure, child: child}
+}
+
+func (n *force) Tick(ctx *Context) Status {
+	if n.child.Tick(ctx) == Running {
+		return Running
+	}
+
+	return n.status
+}
+
+type repeat struct {
+	times int
+	child Node
+}
+
+// Repeat returns a decorator running its child times times in a row, or forever if times <= 0.
+// It fails as soon as the child fails. The child is run at most once per tick.
+func Repeat(times int, child Node) Node {
+	return &repeat{times: times, child: child}
+}
+
+func (n *repeat) Tick(ctx *Context) Status {
+	count := State(ctx, n, func() *int { return new(int) })
+
+	switch n.child.Tick(ctx) {
+	case Running:
+		return Running
+	case Failure:
+		*count = 0
+		return Failure
+	}
+
+	*count++
+	if n.times <= 0 || *count < n.times {
+		return Running
+	}
+
+	*count = 0
+	return Success
+}
+
+type cooldown struct {
+	duration time.Duration
+	child    Node
+}
+
+// Cooldown returns a decorator that fails without ticking its child for d of game time after the child finishes.
+func Cooldown(d time.Duration, child Node) Node {
+	return &cooldown{duration: d, child: child}
+}
+
+func (n *cooldown) Tick(ctx *Context) Status {
+	remaining := State(ctx, n, func() *time.Duration { return new(time.Duration) })
+
+	if *remaining > 0 {
+		*remaining -= ctx.Delta
+		if *remaining > 0 {
+			return Failure
+		}
+	}
+
+	status := n.child.Tick(ctx)
+	if status != Running {
+		*remaining = n.duration
+	}
+
+	return status
+}
@@ -0,0 +1,100 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// bundleFields caches the component types of the fields of bundle types.
+var bundleFields sync.Map // map[reflect.Type][]reflect.Type
+
+// fieldsOfBundle returns the component types of the fields of the bundle type, in declaration order.
+func fieldsOfBundle(bundleType reflect.Type) []reflect.Type {
+	if fields, ok := bundleFields.Load(bundleType); ok {
+		return fields.([]reflect.Type)
+	}
+
+	if bundleType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("ecs.AddBundle: bundle %s is not a struct", bundleType))
+	}
+
+	fields := make([]reflect.Type, 0, bundleType.NumField())
+	for i := range bundleType.NumField() {
+		field := bundleType.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			panic(fmt.Sprintf("ecs.AddBundle: field %s of bundle %s is not exported", field.Name, bundleType))
+		}
+
+		if slices.Contains(fields, field.Type) {
+			panic(fmt.Sprintf("ecs.AddBundle: bundle %s has several %s fields", bundleType, field.Type))
+		}
+
+		fields = append(fields, field.Type)
+	}
+
+	bundleFields.Store(bundleType, fields)
+
+	return fields
+}
+
+// AddBundle adds every field of bundle, a struct grouping the components commonly used together,
+// as a component of the entity set to the field's value. Components the entity already has are overwritten.
+// Fields are usually embedded components; the others must be exported. All must be of distinct types:
+//
+//	type PlayerBundle struct {
+//		transform.Transform
+//		render.Sprite
+//		Health
+//	}
+//
+//	ecs.AddBundle(em, player, PlayerBundle{Health: Health{Max: 3}})
+//
+// The requirements of the components, see Require, are checked once the whole bundle is added,
+// so a bundle may list a component before the components it requires.
+func AddBundle[B any](em *EntityManager, entityID EntityID, bundle B) {
+	defer em.beginStructural("AddBundle")()
+
+	if _, exists := em.entities[entityID]; !exists {
+		return
+	}
+
+	// An addressable copy lets the fields of embedded unexported types be read.
+	value := reflect.New(reflect.TypeFor[B]()).Elem()
+	value.Set(reflect.ValueOf(bundle))
+	fields := fieldsOfBundle(value.Type())
+
+	for _, componentType := range fields {
+		for _, r := range requirementsOf(componentType) {
+			if em.strict && !slices.Contains(fields, r.componentType) && !em.hasComponentType(entityID, r.componentType) {
+				panic(fmt.Sprintf("ecs.AddBundle: entity %d: %s requires %s", entityID, componentType, r.componentType))
+			}
+		}
+	}
+
+	for i, componentType := range fields {
+		component, ok := em.storageOf(componentType).Get(entityID)
+		if !ok {
+			component = em.componentContainers[componentType].Add(entityID)
+			em.setComponentBit(entityID, componentType)
+			em.logComponent("component added", entityID, componentType)
+		}
+
+		field := value.Field(i)
+		reflect.ValueOf(component).Elem().Set(reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem())
+	}
+
+	for _, componentType := range fields {
+		em.addRequired(entityID, componentType)
+	}
+}
+
+// SpawnBundle creates an entity with the components of bundle, see AddBundle.
+func SpawnBundle[B any](em *EntityManager, bundle B) EntityID {
+	entityID := em.NewEntity()
+	AddBundle(em, entityID, bundle)
+
+	return entityID
+}
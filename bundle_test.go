@@ -0,0 +1,69 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+type cameraBundle struct {
+	TransformComponent
+	CameraComponent
+	Name NameComponent
+}
+
+type spriteBundle struct {
+	requiringSprite
+	TransformComponent
+}
+
+func TestAddBundle(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	camera := ecs.SpawnBundle(em, cameraBundle{
+		TransformComponent: TransformComponent{Position: f64.Vec2{1, 2}},
+		CameraComponent:    CameraComponent{Zoom: 2},
+		Name:               NameComponent{Name: "main"},
+	})
+
+	transform, cam, name, ok := ecs.GetComponents3[TransformComponent, CameraComponent, NameComponent](em, camera)
+	require.True(t, ok)
+	assert.Equal(t, f64.Vec2{1, 2}, transform.Position)
+	assert.Equal(t, 2.0, cam.Zoom)
+	assert.Equal(t, "main", name.Name)
+	assert.Equal(t, 1, ecs.Count(ecs.Query3[TransformComponent, CameraComponent, NameComponent](em)))
+
+	ecs.AddBundle(em, camera, cameraBundle{CameraComponent: CameraComponent{Zoom: 4}})
+	assert.Same(t, cam, ecs.MustGetComponent[CameraComponent](em, camera), "existing components are kept")
+	assert.Equal(t, 4.0, cam.Zoom, "and overwritten")
+	assert.Equal(t, f64.Vec2{}, transform.Position)
+
+	ecs.AddBundle(em, ecs.UndefinedID, cameraBundle{})
+	assert.Equal(t, 1, ecs.Count(ecs.Query[CameraComponent](em)))
+
+	assert.PanicsWithValue(t, "ecs.AddBundle: bundle int is not a struct", func() {
+		ecs.AddBundle(em, camera, 1)
+	})
+}
+
+func TestAddBundleRequirements(t *testing.T) {
+	em := ecs.NewEntityManager()
+	em.SetStrict(true)
+
+	entityID := ecs.SpawnBundle(em, spriteBundle{
+		requiringSprite:    requiringSprite{Frame: 3},
+		TransformComponent: TransformComponent{Rotation: 1},
+	})
+
+	assert.Equal(t, 3, ecs.MustGetComponent[requiringSprite](em, entityID).Frame)
+	assert.Equal(t, 1.0, ecs.MustGetComponent[TransformComponent](em, entityID).Rotation)
+	assert.Empty(t, em.CheckRequirements())
+
+	em.SetStrict(false)
+	bodyID := ecs.SpawnBundle(em, struct{ Body requiringBody }{Body: requiringBody{Mass: 5}})
+	assert.Equal(t, 5.0, ecs.MustGetComponent[requiringBody](em, bodyID).Mass)
+	assert.True(t, ecs.HasComponent[requiringSprite](em, bodyID), "missing requirements are added")
+}
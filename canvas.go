@@ -0,0 +1,27 @@
+package ecs
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var _ Canvas = (*ebiten.Image)(nil)
+
+// Canvas is the drawing surface used by CanvasSystems.
+// *ebiten.Image implements Canvas, as does render.Recorder, which captures draw calls for tests.
+type Canvas interface {
+	Bounds() image.Rectangle
+	Fill(clr color.Color)
+	DrawImage(img *ebiten.Image, options *ebiten.DrawImageOptions)
+	DrawRectShader(width, height int, shader *ebiten.Shader, options *ebiten.DrawRectShaderOptions)
+	DrawTriangles(vertices []ebiten.Vertex, indices []uint16, img *ebiten.Image, options *ebiten.DrawTrianglesOptions)
+}
+
+// CanvasSystem is an optional interface for systems that draw through a Canvas instead of directly onto an *ebiten.Image.
+// Drawing through a Canvas allows the issued draw calls to be recorded and compared without a GPU.
+type CanvasSystem interface {
+	System
+	DrawCanvas(canvas Canvas)
+}
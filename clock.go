@@ -0,0 +1,45 @@
+package ecs
+
+import "time"
+
+// Clock is the simulation clock. It advances only when the game ticks, by the scaled
+// duration of the tick, so anything timed with it stays in sync when the game is paused,
+// slowed down or replayed. Systems should read the Clock instead of calling time.Now.
+//
+// The game Clock is available as a resource and through Game.Clock.
+type Clock struct {
+	tick    uint64
+	elapsed time.Duration
+}
+
+// NewClock creates a clock at tick zero.
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// Tick returns the number of ticks the clock has advanced.
+func (c *Clock) Tick() uint64 {
+	return c.tick
+}
+
+// Now returns the simulated time elapsed since the clock started.
+func (c *Clock) Now() time.Duration {
+	return c.elapsed
+}
+
+// Since returns the simulated time elapsed since t, a value previously returned by Now.
+func (c *Clock) Since(t time.Duration) time.Duration {
+	return c.elapsed - t
+}
+
+// Advance moves the clock forward by one tick lasting d.
+func (c *Clock) Advance(d time.Duration) {
+	c.tick++
+	c.elapsed += max(d, 0)
+}
+
+// Reset moves the clock back to tick zero.
+func (c *Clock) Reset() {
+	c.tick = 0
+	c.elapsed = 0
+}
@@ -0,0 +1,39 @@
+// Command ecslint checks Go packages for common misuses of the ECS.
+//
+// Usage:
+//
+//	ecslint [dir ...]
+//
+// Each directory is checked non-recursively; append /... to a directory to check it recursively.
+// ecslint exits with status 1 if any problem is found.
+//
+// Checks:
+//   - wallclock: systems must not read the wall clock (time.Now, time.Since, ...) in their methods;
+//     they should use the simulation ecs.Clock so pausing, slow motion and replays stay in sync.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	dirs := os.Args[1:]
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	findings, err := checkDirs(dirs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ecslint:", err)
+		os.Exit(2)
+	}
+
+	for _, finding := range findings {
+		fmt.Println(finding)
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
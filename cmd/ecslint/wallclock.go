@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// wallClockFuncs are the functions of package time that read or wait on the wall clock.
+var wallClockFuncs = []string{"Now", "Since", "Until", "Tick", "After", "AfterFunc", "NewTimer", "NewTicker", "Sleep"}
+
+// Finding is a single problem reported by a check.
+type Finding struct {
+	Pos     token.Position
+	Check   string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Pos, f.Check, f.Message)
+}
+
+func checkDirs(patterns []string) ([]Finding, error) {
+	var findings []Finding
+
+	for _, pattern := range patterns {
+		dir, recursive := strings.CutSuffix(pattern, "/...")
+
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if path != dir && (!recursive || strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+
+				dirFindings, err := checkDir(path)
+				if err != nil {
+					return err
+				}
+				findings = append(findings, dirFindings...)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("checkDirs filepath.WalkDir error: %w", err)
+		}
+	}
+
+	return findings, nil
+}
+
+func checkDir(dir string) ([]Finding, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("checkDir os.ReadDir error: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("checkDir parser.ParseFile error: %w", err)
+		}
+		files = append(files, file)
+	}
+
+	return checkWallClock(fset, files), nil
+}
+
+// checkWallClock reports wall clock reads inside the methods of system types,
+// i.e. struct types that embed BaseSystem.
+func checkWallClock(fset *token.FileSet, files []*ast.File) []Finding {
+	systems := make(map[string]bool)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			if st, ok := spec.Type.(*ast.StructType); ok && embedsBaseSystem(st) {
+				systems[spec.Name.Name] = true
+			}
+
+			return false
+		})
+	}
+
+	var findings []Finding
+	for _, file := range files {
+		timeName, ok := importName(file, "time")
+		if !ok {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil {
+				continue
+			}
+
+			recv := receiverType(fn.Recv.List[0].Type)
+			if !systems[recv] {
+				continue
+			}
+
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+
+				pkg, ok := sel.X.(*ast.Ident)
+				if !ok || pkg.Name != timeName || !slices.Contains(wallClockFuncs, sel.Sel.Name) {
+					return true
+				}
+
+				findings = append(findings, Finding{
+					Pos:     fset.Position(sel.Pos()),
+					Check:   "wallclock",
+					Message: fmt.Sprintf("system %s.%s reads the wall clock via time.%s; use the game ecs.Clock instead", recv, fn.Name.Name, sel.Sel.Name),
+				})
+
+				return true
+			})
+		}
+	}
+
+	return findings
+}
+
+func embedsBaseSystem(st *ast.StructType) bool {
+	for _, field := range st.Fields.List {
+		if len(field.Names) != 0 {
+			continue
+		}
+
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+
+		switch typ := typ.(type) {
+		case *ast.Ident:
+			if typ.Name == "BaseSystem" {
+				return true
+			}
+		case *ast.SelectorExpr:
+			if typ.Sel.Name == "BaseSystem" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func receiverType(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(expr.X)
+	case *ast.IndexExpr:
+		return receiverType(expr.X)
+	case *ast.IndexListExpr:
+		return receiverType(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	default:
+		return ""
+	}
+}
+
+func importName(file *ast.File, path string) (string, bool) {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || importPath != path {
+			continue
+		}
+
+		if spec.Name != nil {
+			return spec.Name.Name, spec.Name.Name != "_"
+		}
+
+		return filepath.Base(path), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wallClockSrc = `package game
+
+import (
+	stdtime "time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+type SpawnSystem struct {
+	*ecs.BaseSystem
+
+	last stdtime.Time
+}
+
+func (s *SpawnSystem) Update() error {
+	if stdtime.Since(s.last) > stdtime.Second {
+		s.last = stdtime.Now()
+	}
+	return nil
+}
+
+type helper struct{}
+
+func (h helper) Update() error {
+	_ = stdtime.Now()
+	return nil
+}
+`
+
+func TestCheckWallClock(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "spawn.go", wallClockSrc, 0)
+	require.NoError(t, err)
+
+	findings := checkWallClock(fset, []*ast.File{file})
+	require.Len(t, findings, 2)
+
+	assert.Equal(t, 16, findings[0].Pos.Line)
+	assert.Contains(t, findings[0].Message, "SpawnSystem.Update")
+	assert.Contains(t, findings[0].Message, "time.Since")
+	assert.Equal(t, 17, findings[1].Pos.Line)
+	assert.Contains(t, findings[1].Message, "time.Now")
+}
@@ -0,0 +1,46 @@
+// Command ecsmigrate helps moving a project from donburi or arche to this ECS.
+//
+// Usage:
+//
+//	ecsmigrate [-w] [dir ...]
+//
+// Each directory is processed non-recursively; append /... to a directory to process it recursively.
+//
+// ecsmigrate lists the imports of donburi and arche that have an adapter shim in the compat packages
+// and every use of an identifier the shims do not provide, which has to be ported by hand.
+// With -w it also rewrites the imports to the shims in place, so the project keeps compiling while
+// its systems are ported one at a time. Packages without a shim, such as arche's ecs package, are reported.
+// ecsmigrate exits with status 1 if anything remains to be ported by hand.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	write := flag.Bool("w", false, "rewrite the imports of shimmed packages in place")
+	flag.Parse()
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	findings, err := migrateDirs(dirs, *write)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ecsmigrate:", err)
+		os.Exit(2)
+	}
+
+	manual := false
+	for _, finding := range findings {
+		fmt.Println(finding)
+		manual = manual || finding.Manual
+	}
+
+	if manual {
+		os.Exit(1)
+	}
+}
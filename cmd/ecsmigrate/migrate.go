@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const compatPath = "github.com/samix73/ebiten-ecs/compat/"
+
+// shim is a package of another ECS and the compat package replacing it.
+type shim struct {
+	path   string
+	compat string
+	// symbols are the exported identifiers the compat package provides.
+	symbols []string
+}
+
+var shims = []shim{
+	{
+		path:    "github.com/yohamta/donburi",
+		compat:  compatPath + "donburi",
+		symbols: []string{"ComponentType", "Entity", "Entry", "IComponentType", "NewComponentType", "NewQuery", "NewWorld", "Null", "Query", "World", "Wrap"},
+	},
+	{
+		path:    "github.com/yohamta/donburi/filter",
+		compat:  compatPath + "donburi/filter",
+		symbols: []string{"And", "ComponentType", "Contains", "LayoutFilter", "Not", "Or"},
+	},
+	{
+		path:   "github.com/mlange-42/arche/generic",
+		compat: compatPath + "arche/generic",
+		symbols: []string{
+			"Comp", "Filter1", "Filter2", "Filter3", "Map", "Map1", "Map2", "Map3",
+			"NewFilter1", "NewFilter2", "NewFilter3", "NewMap", "NewMap1", "NewMap2", "NewMap3",
+			"Query1", "Query2", "Query3", "T",
+		},
+	},
+}
+
+// unshimmedRoots are the module paths whose packages without a shim must be ported by hand.
+var unshimmedRoots = []string{"github.com/yohamta/donburi", "github.com/mlange-42/arche"}
+
+// Finding is an import or identifier reported by ecsmigrate.
+type Finding struct {
+	Pos     token.Position
+	Message string
+	// Manual is set when the code has to be ported by hand.
+	Manual bool
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+func migrateDirs(patterns []string, write bool) ([]Finding, error) {
+	var findings []Finding
+
+	for _, pattern := range patterns {
+		dir, recursive := strings.CutSuffix(pattern, "/...")
+
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if path != dir && (!recursive || strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+
+			fileFindings, err := migrateFile(path, write)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, fileFindings...)
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("migrateDirs filepath.WalkDir error: %w", err)
+		}
+	}
+
+	return findings, nil
+}
+
+func migrateFile(path string, write bool) ([]Finding, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("migrateFile os.ReadFile error: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("migrateFile parser.ParseFile error: %w", err)
+	}
+
+	findings, rewritten := migrate(fset, file)
+	if !write || !rewritten {
+		return findings, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("migrateFile format.Node error: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("migrateFile os.WriteFile error: %w", err)
+	}
+
+	return findings, nil
+}
+
+// migrate points the shimmed imports of file to their compat packages and reports the identifiers
+// of the imported packages that have no shim. It reports whether an import was changed.
+func migrate(fset *token.FileSet, file *ast.File) ([]Finding, bool) {
+	var findings []Finding
+	rewritten := false
+
+	// names maps the local name of each shimmed import to its shim.
+	names := make(map[string]shim)
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		i := slices.IndexFunc(shims, func(s shim) bool { return s.path == importPath })
+		if i < 0 {
+			if isUnshimmed(importPath) {
+				findings = append(findings, Finding{
+					Pos:     fset.Position(spec.Pos()),
+					Message: fmt.Sprintf("%s has no shim; port its uses to package ecs by hand", importPath),
+					Manual:  true,
+				})
+			}
+
+			continue
+		}
+
+		name := filepath.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		names[name] = shims[i]
+
+		findings = append(findings, Finding{
+			Pos:     fset.Position(spec.Pos()),
+			Message: fmt.Sprintf("%s -> %s", importPath, shims[i].compat),
+		})
+
+		spec.Path.Value = strconv.Quote(shims[i].compat)
+		rewritten = true
+	}
+
+	if len(names) == 0 {
+		return findings, rewritten
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		s, ok := names[pkg.Name]
+		if !ok || slices.Contains(s.symbols, sel.Sel.Name) {
+			return true
+		}
+
+		findings = append(findings, Finding{
+			Pos:     fset.Position(sel.Pos()),
+			Message: fmt.Sprintf("%s.%s is not provided by %s; port it by hand", pkg.Name, sel.Sel.Name, s.compat),
+			Manual:  true,
+		})
+
+		return true
+	})
+
+	return findings, rewritten
+}
+
+func isUnshimmed(importPath string) bool {
+	for _, root := range unshimmedRoots {
+		if importPath == root || strings.HasPrefix(importPath, root+"/") {
+			return true
+		}
+	}
+
+	return false
+}
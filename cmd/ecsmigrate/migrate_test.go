@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const donburiSrc = `package game
+
+import (
+	"github.com/yohamta/donburi"
+	"github.com/yohamta/donburi/ecs"
+	dfilter "github.com/yohamta/donburi/filter"
+)
+
+var Position = donburi.NewComponentType[struct{ X, Y float64 }]()
+
+var moving = donburi.NewQuery(dfilter.Contains(Position))
+
+func setup(w donburi.World) {
+	donburi.Add(w.Entry(w.Create(Position)), Position, nil)
+	_ = ecs.NewECS(w)
+}
+`
+
+func TestMigrate(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "game.go", donburiSrc, parser.ParseComments)
+	require.NoError(t, err)
+
+	findings, rewritten := migrate(fset, file)
+	assert.True(t, rewritten)
+
+	messages := make([]string, len(findings))
+	for i, finding := range findings {
+		messages[i] = finding.String()
+	}
+	assert.Equal(t, []string{
+		"game.go:4:2: github.com/yohamta/donburi -> github.com/samix73/ebiten-ecs/compat/donburi",
+		"game.go:5:2: github.com/yohamta/donburi/ecs has no shim; port its uses to package ecs by hand",
+		"game.go:6:2: github.com/yohamta/donburi/filter -> github.com/samix73/ebiten-ecs/compat/donburi/filter",
+		"game.go:14:2: donburi.Add is not provided by github.com/samix73/ebiten-ecs/compat/donburi; port it by hand",
+	}, messages)
+
+	var buf bytes.Buffer
+	require.NoError(t, format.Node(&buf, fset, file))
+	assert.Contains(t, buf.String(), `dfilter "github.com/samix73/ebiten-ecs/compat/donburi/filter"`)
+	assert.Contains(t, buf.String(), `"github.com/samix73/ebiten-ecs/compat/donburi"`+"\n")
+}
+
+// TestShimSymbols keeps the symbol lists in sync with the exported identifiers of the compat packages.
+func TestShimSymbols(t *testing.T) {
+	for _, s := range shims {
+		dir := filepath.Join("..", "..", strings.TrimPrefix(s.compat, "github.com/samix73/ebiten-ecs/"))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+
+		var exported []string
+		fset := token.NewFileSet()
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+
+			file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+			require.NoError(t, err)
+
+			for _, decl := range file.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					if decl.Recv == nil && decl.Name.IsExported() {
+						exported = append(exported, decl.Name.Name)
+					}
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						switch spec := spec.(type) {
+						case *ast.TypeSpec:
+							if spec.Name.IsExported() {
+								exported = append(exported, spec.Name.Name)
+							}
+						case *ast.ValueSpec:
+							for _, name := range spec.Names {
+								if name.IsExported() {
+									exported = append(exported, name.Name)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+
+		slices.Sort(exported)
+		assert.Equal(t, exported, s.symbols, s.compat)
+	}
+}
@@ -0,0 +1,41 @@
+// Package codec encodes components for the serialization and networking layers, such as the replication
+// package. JSON is readable and handles any type; Protobuf encodes struct components in the compact
+// protobuf wire format for real-time sync, with a schema derived from the component types:
+//
+//	registry := replication.NewRegistryWithCodec(codec.Protobuf)
+//
+//	// For clients not written in Go:
+//	schema, err := codec.ProtoSchema(reflect.TypeFor[transform.Transform]())
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrUnsupportedType is returned by the Protobuf codec for types it cannot encode, such as maps,
+// interfaces, functions and channels.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// ErrMalformed is returned by the Protobuf codec and NewDeflate compressors when decoding truncated or corrupted data.
+var ErrMalformed = errors.New("malformed data")
+
+// Codec encodes components. Marshal must be deterministic so unchanged components encode to the
+// same bytes, e.g. to leave them out of delta snapshots.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// JSON encodes the exported fields of components with encoding/json.
+var JSON Codec = jsonCodec{}
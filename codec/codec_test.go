@@ -0,0 +1,81 @@
+package codec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/samix73/ebiten-ecs/codec"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+type Stats struct {
+	Level   int8
+	Speed   float32
+	Alive   bool
+	Name    string
+	Scores  []int
+	Items   []Item
+	Data    []byte
+	Target  *f64.Vec2
+	Ignored string `proto:"-"`
+	Renamed uint   `proto:"20"`
+}
+
+type Item struct {
+	ID    uint16
+	Count int
+}
+
+func TestProtobuf(t *testing.T) {
+	stats := Stats{
+		Level:   -3,
+		Speed:   1.5,
+		Alive:   true,
+		Name:    "orc",
+		Scores:  []int{10, -20, 0},
+		Items:   []Item{{ID: 1, Count: 2}, {}},
+		Data:    []byte{1, 2},
+		Target:  &f64.Vec2{4, 5},
+		Ignored: "skipped",
+		Renamed: 300,
+	}
+
+	data, err := codec.Protobuf.Marshal(&stats)
+	require.NoError(t, err)
+
+	decoded := Stats{Name: "stale", Scores: []int{99}}
+	require.NoError(t, codec.Protobuf.Unmarshal(data, &decoded))
+	stats.Ignored = ""
+	assert.Equal(t, stats, decoded)
+
+	tr := transform.Transform{Position: f64.Vec2{10, 20}, Rotation: 0.5, Scale: f64.Vec2{1, 1}}
+	protoData, err := codec.Protobuf.Marshal(&tr)
+	require.NoError(t, err)
+	jsonData, err := codec.JSON.Marshal(&tr)
+	require.NoError(t, err)
+	assert.Less(t, len(protoData), len(jsonData))
+
+	var decodedTransform transform.Transform
+	require.NoError(t, codec.Protobuf.Unmarshal(protoData, &decodedTransform))
+	assert.Equal(t, tr, decodedTransform)
+
+	assert.ErrorIs(t, codec.Protobuf.Unmarshal(protoData[:len(protoData)-1], &decodedTransform), codec.ErrMalformed)
+
+	_, err = codec.Protobuf.Marshal(&struct{ M map[string]int }{})
+	assert.ErrorIs(t, err, codec.ErrUnsupportedType)
+}
+
+func TestProtoSchema(t *testing.T) {
+	schema, err := codec.ProtoSchema(reflect.TypeFor[Item]())
+	require.NoError(t, err)
+	assert.Equal(t, `syntax = "proto3";
+
+message Item {
+  uint32 ID = 1;
+  sint64 Count = 2;
+}
+`, schema)
+}
@@ -0,0 +1,55 @@
+package codec
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+)
+
+// Compressor compresses encoded data, such as replication snapshots and save files. Implementations
+// for other algorithms, e.g. zstd or snappy, can be plugged in wherever a Compressor is accepted.
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+type deflateCompressor struct {
+	level int
+}
+
+// NewDeflate returns a Compressor using compress/flate at the given level, from flate.BestSpeed to
+// flate.BestCompression. flate.BestSpeed suits per-tick network snapshots.
+func NewDeflate(level int) Compressor {
+	return deflateCompressor{level: level}
+}
+
+func (c deflateCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, c.level)
+	if err != nil {
+		return nil, fmt.Errorf("codec.Deflate.Compress flate.NewWriter error: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("codec.Deflate.Compress Write error: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("codec.Deflate.Compress Close error: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c deflateCompressor) Decompress(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("codec.Deflate.Decompress: %w", ErrMalformed)
+	}
+
+	return decompressed, nil
+}
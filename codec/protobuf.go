@@ -0,0 +1,463 @@
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// Protobuf encodes struct components in the protobuf wire format, as described by ProtoSchema.
+// Every exported field is a protobuf field numbered by its position in the struct, starting at 1,
+// unless a `proto:"N"` tag numbers it; `proto:"-"` leaves it out. Keep the numbers of existing
+// fields when changing a component shared with older builds.
+//
+// Signed integers are zigzag encoded, floats are fixed size, and nested structs, arrays and slices
+// are nested messages and repeated fields. Like proto.Unmarshal, Unmarshal clears the component
+// before decoding, since zero fields are not encoded.
+var Protobuf Codec = protobufCodec{}
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// protoField is an exported struct field and its protobuf field number.
+type protoField struct {
+	number int
+	index  int
+	name   string
+	typ    reflect.Type
+}
+
+// protoFields caches the fields of struct types, by reflect.Type.
+var protoFields sync.Map
+
+// fieldsOf returns the protobuf fields of the struct type t, in field number order.
+func fieldsOf(t reflect.Type) ([]protoField, error) {
+	if cached, ok := protoFields.Load(t); ok {
+		return cached.([]protoField), nil
+	}
+
+	var fields []protoField
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		number := i + 1
+		if tag, ok := field.Tag.Lookup("proto"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			n, err := strconv.Atoi(tag)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("codec: %s.%s has invalid proto tag %q", t, field.Name, tag)
+			}
+			number = n
+		}
+
+		if err := checkType(field.Type); err != nil {
+			return nil, fmt.Errorf("codec: %s.%s: %w", t, field.Name, err)
+		}
+
+		fields = append(fields, protoField{number: number, index: i, name: field.Name, typ: field.Type})
+	}
+
+	slices.SortFunc(fields, func(a, b protoField) int { return a.number - b.number })
+	for i := 1; i < len(fields); i++ {
+		if fields[i].number == fields[i-1].number {
+			return nil, fmt.Errorf("codec: %s.%s and %s.%s have the same field number %d", t, fields[i-1].name, t, fields[i].name, fields[i].number)
+		}
+	}
+
+	protoFields.Store(t, fields)
+	return fields, nil
+}
+
+// checkType returns ErrUnsupportedType if t cannot be a protobuf field.
+func checkType(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return checkType(t.Elem())
+	case reflect.Slice, reflect.Array:
+		elem := t.Elem()
+		if t.Kind() == reflect.Slice && elem.Kind() == reflect.Uint8 {
+			return nil
+		}
+
+		if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array || elem.Kind() == reflect.Pointer {
+			return fmt.Errorf("%s: %w", t, ErrUnsupportedType)
+		}
+
+		return checkType(elem)
+	case reflect.String, reflect.Struct:
+		return nil
+	default:
+		if _, ok := scalarWire(t.Kind()); ok {
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", t, ErrUnsupportedType)
+	}
+}
+
+// scalarWire returns the wire type of a scalar kind.
+func scalarWire(kind reflect.Kind) (uint64, bool) {
+	switch kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return wireVarint, true
+	case reflect.Float32:
+		return wireFixed32, true
+	case reflect.Float64:
+		return wireFixed64, true
+	default:
+		return 0, false
+	}
+}
+
+type protobufCodec struct{}
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("codec.Protobuf.Marshal %s: %w", rv.Type(), ErrUnsupportedType)
+	}
+
+	return appendMessage(nil, rv)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("codec.Protobuf.Unmarshal %T: %w", v, ErrUnsupportedType)
+	}
+
+	rv = rv.Elem()
+	rv.SetZero()
+
+	return decodeMessage(data, rv)
+}
+
+// appendMessage appends the non-zero fields of the struct v to b.
+func appendMessage(b []byte, v reflect.Value) ([]byte, error) {
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		value := v.Field(field.index)
+		if value.IsZero() {
+			continue
+		}
+
+		if b, err = appendField(b, field.number, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// appendField appends the field numbered number with value v to b.
+func appendField(b []byte, number int, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		return appendField(b, number, v.Elem())
+	case reflect.String:
+		b = appendTag(b, number, wireBytes)
+		b = binary.AppendUvarint(b, uint64(v.Len()))
+		return append(b, v.String()...), nil
+	case reflect.Struct:
+		message, err := appendMessage(nil, v)
+		if err != nil {
+			return nil, err
+		}
+
+		b = appendTag(b, number, wireBytes)
+		b = binary.AppendUvarint(b, uint64(len(message)))
+		return append(b, message...), nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			b = appendTag(b, number, wireBytes)
+			b = binary.AppendUvarint(b, uint64(v.Len()))
+			return append(b, v.Bytes()...), nil
+		}
+
+		if _, scalar := scalarWire(v.Type().Elem().Kind()); scalar {
+			var packed []byte
+			for i := range v.Len() {
+				packed = appendScalar(packed, v.Index(i))
+			}
+
+			b = appendTag(b, number, wireBytes)
+			b = binary.AppendUvarint(b, uint64(len(packed)))
+			return append(b, packed...), nil
+		}
+
+		var err error
+		for i := range v.Len() {
+			if b, err = appendField(b, number, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+
+		return b, nil
+	default:
+		wire, ok := scalarWire(v.Kind())
+		if !ok {
+			return nil, fmt.Errorf("codec: %s: %w", v.Type(), ErrUnsupportedType)
+		}
+
+		return appendScalar(appendTag(b, number, wire), v), nil
+	}
+}
+
+func appendTag(b []byte, number int, wire uint64) []byte {
+	return binary.AppendUvarint(b, uint64(number)<<3|wire)
+}
+
+// appendScalar appends the scalar v without a tag.
+func appendScalar(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(b, v.Int())
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v.Float()))
+	default:
+		return binary.AppendUvarint(b, v.Uint())
+	}
+}
+
+// decodeMessage decodes the fields in data into the struct v, skipping unknown fields.
+func decodeMessage(data []byte, v reflect.Value) error {
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return err
+	}
+
+	// next is the index of the next element of every array field.
+	var next map[int]int
+
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return ErrMalformed
+		}
+		data = data[n:]
+
+		number, wire := int(key>>3), key&7
+		var raw []byte
+		switch wire {
+		case wireVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return ErrMalformed
+			}
+			raw, data = data[:n], data[n:]
+		case wireFixed64, wireFixed32:
+			size := 8
+			if wire == wireFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return ErrMalformed
+			}
+			raw, data = data[:size], data[size:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return ErrMalformed
+			}
+			raw, data = data[n:n+int(length)], data[n+int(length):]
+		default:
+			return ErrMalformed
+		}
+
+		index := slices.IndexFunc(fields, func(field protoField) bool { return field.number == number })
+		if index < 0 {
+			continue
+		}
+
+		field := v.Field(fields[index].index)
+		for field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+
+		if field.Kind() == reflect.Array {
+			if next == nil {
+				next = make(map[int]int)
+			}
+
+			i := next[number]
+			if next[number], err = decodeArray(field, i, wire, raw); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := decodeField(field, wire, raw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeField decodes the raw value of a field with the given wire type into v.
+func decodeField(v reflect.Value, wire uint64, raw []byte) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeField(v.Elem(), wire, raw)
+	case reflect.String:
+		if wire != wireBytes {
+			return ErrMalformed
+		}
+		v.SetString(string(raw))
+		return nil
+	case reflect.Struct:
+		if wire != wireBytes {
+			return ErrMalformed
+		}
+		return decodeMessage(raw, v)
+	case reflect.Slice:
+		elemType := v.Type().Elem()
+		if elemType.Kind() == reflect.Uint8 {
+			if wire != wireBytes {
+				return ErrMalformed
+			}
+			v.SetBytes(slices.Clone(raw))
+			return nil
+		}
+
+		if elemWire, scalar := scalarWire(elemType.Kind()); scalar && wire == wireBytes {
+			for len(raw) > 0 {
+				elem := reflect.New(elemType).Elem()
+				var err error
+				if raw, err = decodeScalar(elem, elemWire, raw); err != nil {
+					return err
+				}
+				v.Set(reflect.Append(v, elem))
+			}
+			return nil
+		}
+
+		elem := reflect.New(elemType).Elem()
+		if err := decodeField(elem, wire, raw); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, elem))
+		return nil
+	default:
+		if scalarWireOf(v.Kind()) != wire {
+			return ErrMalformed
+		}
+
+		_, err := decodeScalar(v, wire, raw)
+		return err
+	}
+}
+
+// decodeArray decodes the raw value of a field into the array v from index i on, and returns the index
+// following the decoded elements. Elements past the end of the array are dropped.
+func decodeArray(v reflect.Value, i int, wire uint64, raw []byte) (int, error) {
+	elemType := v.Type().Elem()
+	if elemWire, scalar := scalarWire(elemType.Kind()); scalar && wire == wireBytes {
+		for ; len(raw) > 0; i++ {
+			elem := reflect.New(elemType).Elem()
+			var err error
+			if raw, err = decodeScalar(elem, elemWire, raw); err != nil {
+				return i, err
+			}
+
+			if i < v.Len() {
+				v.Index(i).Set(elem)
+			}
+		}
+
+		return i, nil
+	}
+
+	if i < v.Len() {
+		if err := decodeField(v.Index(i), wire, raw); err != nil {
+			return i, err
+		}
+	}
+
+	return i + 1, nil
+}
+
+func scalarWireOf(kind reflect.Kind) uint64 {
+	wire, _ := scalarWire(kind)
+	return wire
+}
+
+// decodeScalar decodes one scalar with the given wire type from the start of raw into v, and returns
+// the rest of raw.
+func decodeScalar(v reflect.Value, wire uint64, raw []byte) ([]byte, error) {
+	switch wire {
+	case wireFixed32:
+		if len(raw) < 4 {
+			return nil, ErrMalformed
+		}
+		v.SetFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(raw))))
+		return raw[4:], nil
+	case wireFixed64:
+		if len(raw) < 8 {
+			return nil, ErrMalformed
+		}
+		v.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(raw)))
+		return raw[8:], nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, n := binary.Varint(raw)
+		if n <= 0 {
+			return nil, ErrMalformed
+		}
+		v.SetInt(x)
+		return raw[n:], nil
+	default:
+		x, n := binary.Uvarint(raw)
+		if n <= 0 {
+			return nil, ErrMalformed
+		}
+
+		if v.Kind() == reflect.Bool {
+			v.SetBool(x != 0)
+		} else {
+			v.SetUint(x)
+		}
+		return raw[n:], nil
+	}
+}
@@ -0,0 +1,108 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ProtoSchema returns the proto3 definition of the messages the Protobuf codec encodes for the struct
+// type t, e.g. to generate decoders for clients not written in Go. Nested struct types are defined as
+// nested messages.
+func ProtoSchema(t reflect.Type) (string, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return "", fmt.Errorf("codec.ProtoSchema %s: %w", t, ErrUnsupportedType)
+	}
+
+	var b strings.Builder
+	b.WriteString("syntax = \"proto3\";\n\n")
+	if err := writeMessage(&b, t, messageName(t, "Component"), "", make(map[reflect.Type]bool)); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// writeMessage writes the definition of the message for the struct type t, indented by indent.
+func writeMessage(b *strings.Builder, t reflect.Type, name, indent string, visiting map[reflect.Type]bool) error {
+	if visiting[t] {
+		return fmt.Errorf("codec.ProtoSchema: %s is recursive: %w", t, ErrUnsupportedType)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	fields, err := fieldsOf(t)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(b, "%smessage %s {\n", indent, name)
+
+	nested := make(map[reflect.Type]string)
+	for _, field := range fields {
+		elem := field.typ
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+
+		repeated := ""
+		if (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Elem().Kind() != reflect.Uint8 {
+			repeated = "repeated "
+			elem = elem.Elem()
+		}
+
+		typeName := protoType(elem)
+		if elem.Kind() == reflect.Struct {
+			typeName = nested[elem]
+			if typeName == "" {
+				typeName = messageName(elem, field.name)
+				nested[elem] = typeName
+				if err := writeMessage(b, elem, typeName, indent+"  ", visiting); err != nil {
+					return err
+				}
+			}
+		}
+
+		fmt.Fprintf(b, "%s  %s%s %s = %d;\n", indent, repeated, typeName, field.name, field.number)
+	}
+
+	fmt.Fprintf(b, "%s}\n", indent)
+	return nil
+}
+
+// messageName returns the name of the message for the struct type t, or fallback for unnamed types.
+func messageName(t reflect.Type, fallback string) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+
+	return fallback
+}
+
+// protoType returns the protobuf type of a scalar, string or byte slice type.
+func protoType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return "sint32"
+	case reflect.Int, reflect.Int64:
+		return "sint64"
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "uint32"
+	case reflect.Uint, reflect.Uint64:
+		return "uint64"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	case reflect.String:
+		return "string"
+	default:
+		return "bytes"
+	}
+}
@@ -0,0 +1,57 @@
+// Package collision provides collider components describing the solid shape of entities.
+package collision
+
+import "golang.org/x/image/math/f64"
+
+// Shape identifies the geometry of a Collider.
+type Shape int
+
+const (
+	// ShapeRect is an axis-aligned rectangle of Width x Height anchored at the top-left corner.
+	ShapeRect Shape = iota
+	// ShapeEllipse is an ellipse inscribed in the Width x Height rectangle.
+	ShapeEllipse
+	// ShapePolygon is a closed polygon described by Points, relative to the entity position.
+	ShapePolygon
+)
+
+// Collider describes the collision shape of an entity relative to its transform.Transform.
+type Collider struct {
+	Shape  Shape
+	Width  float64
+	Height float64
+	Points []f64.Vec2
+
+	// Static colliders never move and are not pushed by the physics systems.
+	Static bool
+}
+
+func (c *Collider) Reset() {
+	c.Shape = ShapeRect
+	c.Width = 0
+	c.Height = 0
+	c.Points = nil
+	c.Static = false
+}
+
+// Bounds returns the axis-aligned bounding box of the collider relative to the entity position.
+func (c *Collider) Bounds() (minX, minY, maxX, maxY float64) {
+	if c.Shape != ShapePolygon {
+		return 0, 0, c.Width, c.Height
+	}
+
+	if len(c.Points) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	minX, minY = c.Points[0][0], c.Points[0][1]
+	maxX, maxY = minX, minY
+	for _, p := range c.Points[1:] {
+		minX = min(minX, p[0])
+		minY = min(minY, p[1])
+		maxX = max(maxX, p[0])
+		maxY = max(maxY, p[1])
+	}
+
+	return minX, minY, maxX, maxY
+}
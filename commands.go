@@ -0,0 +1,121 @@
+package ecs
+
+import "sync"
+
+// CommandBuffer records structural changes to apply later, so they can be requested while iterating
+// a query or from ParallelEach workers. It is safe for concurrent use.
+//
+// Every EntityManager has a command buffer, returned by EntityManager.Commands, which the
+// SystemManager flushes after each system's Update.
+type CommandBuffer struct {
+	mu       sync.Mutex
+	commands []func(em *EntityManager)
+}
+
+// NewCommandBuffer creates an empty command buffer.
+func NewCommandBuffer() *CommandBuffer {
+	return &CommandBuffer{}
+}
+
+// Do records an arbitrary command.
+func (cb *CommandBuffer) Do(command func(em *EntityManager)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.commands = append(cb.commands, command)
+}
+
+// Spawn records the spawning of a prefab. then, if not nil, is called with the new entity.
+func (cb *CommandBuffer) Spawn(prefab *Prefab, then func(entityID EntityID)) {
+	cb.Do(func(em *EntityManager) {
+		entityID := prefab.Spawn(em)
+		if then != nil {
+			then(entityID)
+		}
+	})
+}
+
+// NewEntity allocates the ID of a new entity and records creating it, so that the commands recorded
+// after it, e.g. with DeferAddComponent, can refer to the entity before it exists.
+func (cb *CommandBuffer) NewEntity() EntityID {
+	entityID := NextID()
+	cb.Do(func(em *EntityManager) {
+		defer em.beginStructural("CommandBuffer.NewEntity")()
+		em.createEntity(entityID)
+	})
+
+	return entityID
+}
+
+// Remove records the removal of an entity.
+func (cb *CommandBuffer) Remove(entityID EntityID) {
+	cb.Do(func(em *EntityManager) { em.Remove(entityID) })
+}
+
+// SetActive records enabling or disabling an entity.
+func (cb *CommandBuffer) SetActive(entityID EntityID, active bool) {
+	cb.Do(func(em *EntityManager) { em.SetActive(entityID, active) })
+}
+
+// Tag records adding tags to an entity.
+func (cb *CommandBuffer) Tag(entityID EntityID, tags ...Tag) {
+	cb.Do(func(em *EntityManager) { em.Tag(entityID, tags...) })
+}
+
+// Untag records removing tags from an entity.
+func (cb *CommandBuffer) Untag(entityID EntityID, tags ...Tag) {
+	cb.Do(func(em *EntityManager) { em.Untag(entityID, tags...) })
+}
+
+// Release records returning an entity to its pool.
+func (cb *CommandBuffer) Release(pool *Pool, entityID EntityID) {
+	cb.Do(func(*EntityManager) { pool.Release(entityID) })
+}
+
+// DeferAddComponent records adding component C, set to value, to an entity.
+// If the entity already has C when the buffer is flushed, its value is overwritten.
+func DeferAddComponent[C any](cb *CommandBuffer, entityID EntityID, value C) {
+	cb.Do(func(em *EntityManager) { SetComponent(em, entityID, value) })
+}
+
+// DeferRemoveComponent records removing component C from an entity.
+func DeferRemoveComponent[C any](cb *CommandBuffer, entityID EntityID) {
+	cb.Do(func(em *EntityManager) { RemoveComponent[C](em, entityID) })
+}
+
+// Len returns the number of recorded commands.
+func (cb *CommandBuffer) Len() int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return len(cb.commands)
+}
+
+// Flush applies the recorded commands to em in the order they were recorded and clears the buffer.
+// Commands recorded while flushing are applied in the same flush.
+func (cb *CommandBuffer) Flush(em *EntityManager) {
+	defer em.lockStructure()()
+
+	for i := 0; ; i++ {
+		cb.mu.Lock()
+		if i >= len(cb.commands) {
+			clear(cb.commands)
+			cb.commands = cb.commands[:0]
+			cb.mu.Unlock()
+			return
+		}
+		command := cb.commands[i]
+		cb.mu.Unlock()
+
+		command(em)
+	}
+}
+
+// Reset discards the recorded commands.
+func (cb *CommandBuffer) Reset() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	clear(cb.commands)
+	cb.commands = cb.commands[:0]
+}
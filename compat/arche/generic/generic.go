@@ -0,0 +1,378 @@
+// Package generic mirrors the generic API of github.com/mlange-42/arche/generic on top of
+// ecs.EntityManager, so projects built on arche can move to this ECS one system at a time.
+//
+// Replace the import of arche's generic package with this one and pass the ecs.EntityManager
+// where arche takes a *ecs.World. Maps and filters then work as before:
+//
+//	mapper := generic.NewMap2[Position, Velocity](em)
+//	entity := mapper.New()
+//
+//	query := generic.NewFilter2[Position, Velocity]().Without(generic.T[Frozen]()).Query(em)
+//	for query.Next() {
+//		pos, vel := query.Get()
+//		...
+//	}
+//
+// Unlike arche, a query captures the matching entities when it is created instead of locking the world,
+// so structural changes while iterating do not panic but are not seen by the query either.
+package generic
+
+import (
+	"reflect"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Comp is a component type used in filters, created with T.
+type Comp struct {
+	typ reflect.Type
+}
+
+// T returns the Comp of component type C.
+func T[C any]() Comp {
+	return Comp{typ: reflect.TypeFor[C]()}
+}
+
+// Type returns the component type.
+func (c Comp) Type() reflect.Type {
+	return c.typ
+}
+
+// zeros returns zero values of the component types, as taken by ecs.QueryBuilder.With and Without.
+func zeros(comps []Comp) []any {
+	values := make([]any, len(comps))
+	for i, comp := range comps {
+		values[i] = reflect.Zero(comp.typ).Interface()
+	}
+
+	return values
+}
+
+// Map gives access to component A of entities.
+type Map[A any] struct {
+	em *ecs.EntityManager
+}
+
+// NewMap creates a Map for component A.
+func NewMap[A any](em *ecs.EntityManager) Map[A] {
+	return Map[A]{em: em}
+}
+
+// Get returns the entity's component, or nil if it does not have it.
+func (m Map[A]) Get(entity ecs.EntityID) *A {
+	a, _ := ecs.GetComponent[A](m.em, entity)
+	return a
+}
+
+// Has reports whether the entity has the component.
+func (m Map[A]) Has(entity ecs.EntityID) bool {
+	return ecs.HasComponent[A](m.em, entity)
+}
+
+// Set sets the entity's component to a copy of value, adding the component if needed.
+func (m Map[A]) Set(entity ecs.EntityID, value *A) *A {
+	a := ecs.AddComponent[A](m.em, entity)
+	if a != nil {
+		*a = *value
+	}
+
+	return a
+}
+
+// Map1 creates, reads and removes component A.
+type Map1[A any] struct {
+	em *ecs.EntityManager
+}
+
+// NewMap1 creates a Map1.
+func NewMap1[A any](em *ecs.EntityManager) Map1[A] {
+	return Map1[A]{em: em}
+}
+
+// New creates an entity with the component.
+func (m Map1[A]) New() ecs.EntityID {
+	entity := m.em.NewEntity()
+	m.Add(entity)
+
+	return entity
+}
+
+// NewWith creates an entity with a copy of the component.
+func (m Map1[A]) NewWith(a *A) ecs.EntityID {
+	entity := m.em.NewEntity()
+	*ecs.AddComponent[A](m.em, entity) = *a
+
+	return entity
+}
+
+// Get returns the entity's component.
+func (m Map1[A]) Get(entity ecs.EntityID) *A {
+	a, _ := ecs.GetComponent[A](m.em, entity)
+	return a
+}
+
+// Add adds the component to an entity.
+func (m Map1[A]) Add(entity ecs.EntityID) {
+	ecs.AddComponent[A](m.em, entity)
+}
+
+// Remove removes the component from an entity.
+func (m Map1[A]) Remove(entity ecs.EntityID) {
+	ecs.RemoveComponent[A](m.em, entity)
+}
+
+// Map2 creates, reads and removes components A and B together.
+type Map2[A, B any] struct {
+	em *ecs.EntityManager
+}
+
+// NewMap2 creates a Map2.
+func NewMap2[A, B any](em *ecs.EntityManager) Map2[A, B] {
+	return Map2[A, B]{em: em}
+}
+
+// New creates an entity with the components.
+func (m Map2[A, B]) New() ecs.EntityID {
+	entity := m.em.NewEntity()
+	m.Add(entity)
+
+	return entity
+}
+
+// NewWith creates an entity with copies of the components.
+func (m Map2[A, B]) NewWith(a *A, b *B) ecs.EntityID {
+	entity := m.em.NewEntity()
+	*ecs.AddComponent[A](m.em, entity) = *a
+	*ecs.AddComponent[B](m.em, entity) = *b
+
+	return entity
+}
+
+// Get returns the entity's components. A missing component is nil.
+func (m Map2[A, B]) Get(entity ecs.EntityID) (*A, *B) {
+	a, _ := ecs.GetComponent[A](m.em, entity)
+	b, _ := ecs.GetComponent[B](m.em, entity)
+
+	return a, b
+}
+
+// Add adds the components to an entity.
+func (m Map2[A, B]) Add(entity ecs.EntityID) {
+	ecs.AddComponent[A](m.em, entity)
+	ecs.AddComponent[B](m.em, entity)
+}
+
+// Remove removes the components from an entity.
+func (m Map2[A, B]) Remove(entity ecs.EntityID) {
+	ecs.RemoveComponent[A](m.em, entity)
+	ecs.RemoveComponent[B](m.em, entity)
+}
+
+// Map3 creates, reads and removes components A, B and C together.
+type Map3[A, B, C any] struct {
+	em *ecs.EntityManager
+}
+
+// NewMap3 creates a Map3.
+func NewMap3[A, B, C any](em *ecs.EntityManager) Map3[A, B, C] {
+	return Map3[A, B, C]{em: em}
+}
+
+// New creates an entity with the components.
+func (m Map3[A, B, C]) New() ecs.EntityID {
+	entity := m.em.NewEntity()
+	m.Add(entity)
+
+	return entity
+}
+
+// NewWith creates an entity with copies of the components.
+func (m Map3[A, B, C]) NewWith(a *A, b *B, c *C) ecs.EntityID {
+	entity := m.em.NewEntity()
+	*ecs.AddComponent[A](m.em, entity) = *a
+	*ecs.AddComponent[B](m.em, entity) = *b
+	*ecs.AddComponent[C](m.em, entity) = *c
+
+	return entity
+}
+
+// Get returns the entity's components. A missing component is nil.
+func (m Map3[A, B, C]) Get(entity ecs.EntityID) (*A, *B, *C) {
+	a, _ := ecs.GetComponent[A](m.em, entity)
+	b, _ := ecs.GetComponent[B](m.em, entity)
+	c, _ := ecs.GetComponent[C](m.em, entity)
+
+	return a, b, c
+}
+
+// Add adds the components to an entity.
+func (m Map3[A, B, C]) Add(entity ecs.EntityID) {
+	ecs.AddComponent[A](m.em, entity)
+	ecs.AddComponent[B](m.em, entity)
+	ecs.AddComponent[C](m.em, entity)
+}
+
+// Remove removes the components from an entity.
+func (m Map3[A, B, C]) Remove(entity ecs.EntityID) {
+	ecs.RemoveComponent[A](m.em, entity)
+	ecs.RemoveComponent[B](m.em, entity)
+	ecs.RemoveComponent[C](m.em, entity)
+}
+
+// filter holds the additional constraints shared by the filters.
+type filter struct {
+	with    []Comp
+	without []Comp
+}
+
+// cursor iterates the entities captured by a query.
+type cursor struct {
+	em       *ecs.EntityManager
+	entities []ecs.EntityID
+	index    int
+}
+
+// Next advances to the next entity, skipping entities removed since the query was created.
+// It returns false when the query is exhausted.
+func (c *cursor) Next() bool {
+	for c.index < len(c.entities) {
+		c.index++
+		if c.em.Exists(c.entities[c.index-1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Entity returns the current entity.
+func (c *cursor) Entity() ecs.EntityID {
+	return c.entities[c.index-1]
+}
+
+// Count returns the number of entities captured by the query.
+func (c *cursor) Count() int {
+	return len(c.entities)
+}
+
+// Close ends the query early.
+func (c *cursor) Close() {
+	c.index = len(c.entities)
+}
+
+// Filter1 selects entities with component A.
+type Filter1[A any] struct {
+	filter
+}
+
+// NewFilter1 creates a Filter1.
+func NewFilter1[A any]() *Filter1[A] {
+	return &Filter1[A]{}
+}
+
+// With additionally requires the components.
+func (f *Filter1[A]) With(comps ...Comp) *Filter1[A] {
+	f.with = append(f.with, comps...)
+	return f
+}
+
+// Without excludes entities with any of the components.
+func (f *Filter1[A]) Without(comps ...Comp) *Filter1[A] {
+	f.without = append(f.without, comps...)
+	return f
+}
+
+// Query captures the matching entities of em.
+func (f *Filter1[A]) Query(em *ecs.EntityManager) Query1[A] {
+	query := ecs.NewQuery[A](em).With(zeros(f.with)...).Without(zeros(f.without)...)
+	return Query1[A]{cursor: &cursor{em: em, entities: slices.Collect(query.Iter())}}
+}
+
+// Query1 iterates the entities of a Filter1.
+type Query1[A any] struct {
+	*cursor
+}
+
+// Get returns the current entity's component.
+func (q Query1[A]) Get() *A {
+	return ecs.MustGetComponent[A](q.em, q.Entity())
+}
+
+// Filter2 selects entities with components A and B.
+type Filter2[A, B any] struct {
+	filter
+}
+
+// NewFilter2 creates a Filter2.
+func NewFilter2[A, B any]() *Filter2[A, B] {
+	return &Filter2[A, B]{}
+}
+
+// With additionally requires the components.
+func (f *Filter2[A, B]) With(comps ...Comp) *Filter2[A, B] {
+	f.with = append(f.with, comps...)
+	return f
+}
+
+// Without excludes entities with any of the components.
+func (f *Filter2[A, B]) Without(comps ...Comp) *Filter2[A, B] {
+	f.without = append(f.without, comps...)
+	return f
+}
+
+// Query captures the matching entities of em.
+func (f *Filter2[A, B]) Query(em *ecs.EntityManager) Query2[A, B] {
+	query := ecs.NewQuery2[A, B](em).With(zeros(f.with)...).Without(zeros(f.without)...)
+	return Query2[A, B]{cursor: &cursor{em: em, entities: slices.Collect(query.Iter())}}
+}
+
+// Query2 iterates the entities of a Filter2.
+type Query2[A, B any] struct {
+	*cursor
+}
+
+// Get returns the current entity's components.
+func (q Query2[A, B]) Get() (*A, *B) {
+	entity := q.Entity()
+	return ecs.MustGetComponent[A](q.em, entity), ecs.MustGetComponent[B](q.em, entity)
+}
+
+// Filter3 selects entities with components A, B and C.
+type Filter3[A, B, C any] struct {
+	filter
+}
+
+// NewFilter3 creates a Filter3.
+func NewFilter3[A, B, C any]() *Filter3[A, B, C] {
+	return &Filter3[A, B, C]{}
+}
+
+// With additionally requires the components.
+func (f *Filter3[A, B, C]) With(comps ...Comp) *Filter3[A, B, C] {
+	f.with = append(f.with, comps...)
+	return f
+}
+
+// Without excludes entities with any of the components.
+func (f *Filter3[A, B, C]) Without(comps ...Comp) *Filter3[A, B, C] {
+	f.without = append(f.without, comps...)
+	return f
+}
+
+// Query captures the matching entities of em.
+func (f *Filter3[A, B, C]) Query(em *ecs.EntityManager) Query3[A, B, C] {
+	query := ecs.NewQuery3[A, B, C](em).With(zeros(f.with)...).Without(zeros(f.without)...)
+	return Query3[A, B, C]{cursor: &cursor{em: em, entities: slices.Collect(query.Iter())}}
+}
+
+// Query3 iterates the entities of a Filter3.
+type Query3[A, B, C any] struct {
+	*cursor
+}
+
+// Get returns the current entity's components.
+func (q Query3[A, B, C]) Get() (*A, *B, *C) {
+	entity := q.Entity()
+	return ecs.MustGetComponent[A](q.em, entity), ecs.MustGetComponent[B](q.em, entity), ecs.MustGetComponent[C](q.em, entity)
+}
@@ -0,0 +1,49 @@
+package generic_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/compat/arche/generic"
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct{ X, Y float64 }
+
+type velocity struct{ X, Y float64 }
+
+type frozen struct{}
+
+func TestMapsAndFilters(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	mapper := generic.NewMap2[position, velocity](em)
+	moving := mapper.NewWith(&position{}, &velocity{X: 2})
+	stopped := mapper.NewWith(&position{}, &velocity{X: 5})
+	generic.NewMap1[frozen](em).Add(stopped)
+
+	removed := mapper.New()
+
+	query := generic.NewFilter2[position, velocity]().Without(generic.T[frozen]()).Query(em)
+	assert.Equal(t, 2, query.Count())
+
+	em.Remove(removed)
+
+	var visited []ecs.EntityID
+	for query.Next() {
+		pos, vel := query.Get()
+		pos.X += vel.X
+		visited = append(visited, query.Entity())
+	}
+	assert.Equal(t, []ecs.EntityID{moving}, visited, "entities removed after the query was created are skipped")
+
+	pos, _ := mapper.Get(moving)
+	assert.Equal(t, 2.0, pos.X)
+	assert.Equal(t, 0.0, generic.NewMap[position](em).Get(stopped).X)
+
+	frozenQuery := generic.NewFilter1[position]().With(generic.T[frozen]()).Query(em)
+	assert.True(t, frozenQuery.Next())
+	assert.Equal(t, stopped, frozenQuery.Entity())
+	frozenQuery.Close()
+	assert.False(t, frozenQuery.Next())
+}
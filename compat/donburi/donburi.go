@@ -0,0 +1,270 @@
+// Package donburi mirrors the core API of github.com/yohamta/donburi on top of ecs.EntityManager,
+// so projects built on donburi can move to this ECS one system at a time.
+//
+// Switch a file over by replacing its donburi imports with this package and its filter subpackage,
+// keeping the package names:
+//
+//	import (
+//		"github.com/samix73/ebiten-ecs/compat/donburi"
+//		"github.com/samix73/ebiten-ecs/compat/donburi/filter"
+//	)
+//
+// Component types, entries and queries then work as before, while Wrap exposes an existing
+// ecs.EntityManager as a World so shimmed code and native systems share the same entities.
+// The cmd/ecsmigrate tool rewrites the imports and lists the calls the shims do not cover.
+package donburi
+
+import (
+	"iter"
+	"reflect"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/compat/donburi/filter"
+)
+
+// Entity identifies an entity, as donburi.Entity.
+type Entity = ecs.EntityID
+
+// Null is the invalid entity.
+const Null Entity = ecs.UndefinedID
+
+// IComponentType is implemented by every ComponentType.
+type IComponentType interface {
+	filter.ComponentType
+	Typ() reflect.Type
+
+	add(em *ecs.EntityManager, entityID Entity)
+}
+
+// ComponentType is a handle on component type T, as created by donburi.NewComponentType.
+type ComponentType[T any] struct {
+	defaultValue *T
+}
+
+// NewComponentType creates a handle on component type T. If a default value of type T or *T is given,
+// components added through the handle start as a copy of it.
+func NewComponentType[T any](defaultValue ...any) *ComponentType[T] {
+	c := &ComponentType[T]{}
+	if len(defaultValue) == 0 {
+		return c
+	}
+
+	switch value := defaultValue[0].(type) {
+	case T:
+		c.defaultValue = &value
+	case *T:
+		c.defaultValue = value
+	}
+
+	return c
+}
+
+// Typ returns the component type.
+func (c *ComponentType[T]) Typ() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// Zero returns a zero T.
+func (c *ComponentType[T]) Zero() any {
+	var zero T
+	return zero
+}
+
+func (c *ComponentType[T]) add(em *ecs.EntityManager, entityID Entity) {
+	component := ecs.AddComponent[T](em, entityID)
+	if component != nil && c.defaultValue != nil {
+		*component = *c.defaultValue
+	}
+}
+
+// Get returns the entry's component. It panics if the entry does not have it.
+func (c *ComponentType[T]) Get(entry *Entry) *T {
+	return ecs.MustGetComponent[T](entry.world.EntityManager(), entry.id)
+}
+
+// GetValue returns a copy of the entry's component. It panics if the entry does not have it.
+func (c *ComponentType[T]) GetValue(entry *Entry) T {
+	return *c.Get(entry)
+}
+
+// Set sets the entry's component to a copy of value, adding the component if needed.
+func (c *ComponentType[T]) Set(entry *Entry, value *T) {
+	c.SetValue(entry, *value)
+}
+
+// SetValue sets the entry's component to value, adding the component if needed.
+func (c *ComponentType[T]) SetValue(entry *Entry, value T) {
+	ecs.SetComponent(entry.world.EntityManager(), entry.id, value)
+}
+
+// Each calls fn for every entity with the component.
+func (c *ComponentType[T]) Each(w World, fn func(*Entry)) {
+	for entityID := range ecs.Query[T](w.EntityManager()) {
+		fn(w.Entry(entityID))
+	}
+}
+
+// First returns the first entity with the component.
+func (c *ComponentType[T]) First(w World) (*Entry, bool) {
+	entityID, ok := ecs.First(ecs.Query[T](w.EntityManager()))
+	if !ok {
+		return nil, false
+	}
+
+	return w.Entry(entityID), true
+}
+
+// MustFirst returns the first entity with the component. It panics if there is none.
+func (c *ComponentType[T]) MustFirst(w World) *Entry {
+	entry, ok := c.First(w)
+	if !ok {
+		panic("donburi.ComponentType.MustFirst: no entity has component " + c.Typ().String())
+	}
+
+	return entry
+}
+
+// World is the subset of donburi.World implemented on top of an ecs.EntityManager.
+type World interface {
+	// Create creates an entity with the given components.
+	Create(componentTypes ...IComponentType) Entity
+	// Entry returns the entry of an entity.
+	Entry(entityID Entity) *Entry
+	// Remove removes an entity.
+	Remove(entityID Entity)
+	// Valid reports whether the entity exists.
+	Valid(entityID Entity) bool
+	// EntityManager returns the underlying entity manager.
+	EntityManager() *ecs.EntityManager
+}
+
+type world struct {
+	em *ecs.EntityManager
+}
+
+// NewWorld creates a World backed by a new ecs.EntityManager.
+func NewWorld() World {
+	return Wrap(ecs.NewEntityManager())
+}
+
+// Wrap returns a World backed by em.
+func Wrap(em *ecs.EntityManager) World {
+	return &world{em: em}
+}
+
+func (w *world) Create(componentTypes ...IComponentType) Entity {
+	entityID := w.em.NewEntity()
+	for _, componentType := range componentTypes {
+		componentType.add(w.em, entityID)
+	}
+
+	return entityID
+}
+
+func (w *world) Entry(entityID Entity) *Entry {
+	return &Entry{world: w, id: entityID}
+}
+
+func (w *world) Remove(entityID Entity) {
+	w.em.Remove(entityID)
+}
+
+func (w *world) Valid(entityID Entity) bool {
+	return w.em.Exists(entityID)
+}
+
+func (w *world) EntityManager() *ecs.EntityManager {
+	return w.em
+}
+
+// Entry is an entity of a World, as donburi.Entry.
+type Entry struct {
+	world World
+	id    Entity
+}
+
+// Entity returns the entry's entity.
+func (e *Entry) Entity() Entity {
+	return e.id
+}
+
+// World returns the entry's world.
+func (e *Entry) World() World {
+	return e.world
+}
+
+// Valid reports whether the entity exists.
+func (e *Entry) Valid() bool {
+	return e.world.Valid(e.id)
+}
+
+// HasComponent reports whether the entity has the component.
+func (e *Entry) HasComponent(componentType IComponentType) bool {
+	return e.world.EntityManager().HasComponent(e.id, componentType.Zero())
+}
+
+// AddComponent adds the component, set to the component type's default value.
+func (e *Entry) AddComponent(componentType IComponentType) {
+	componentType.add(e.world.EntityManager(), e.id)
+}
+
+// RemoveComponent removes the component.
+func (e *Entry) RemoveComponent(componentType IComponentType) {
+	e.world.EntityManager().RemoveComponent(e.id, componentType.Zero())
+}
+
+// Remove removes the entity.
+func (e *Entry) Remove() {
+	e.world.Remove(e.id)
+}
+
+// Query selects entities with a filter, as donburi.Query.
+type Query struct {
+	filter filter.LayoutFilter
+}
+
+// NewQuery creates a query over the entities passing the filter.
+// The filter must require at least one component, see filter.LayoutFilter.
+func NewQuery(filter filter.LayoutFilter) *Query {
+	return &Query{filter: filter}
+}
+
+// Iter returns the sequence of matching entities, so shimmed queries can be ranged over like native ones.
+func (q *Query) Iter(w World) iter.Seq[Entity] {
+	em := w.EntityManager()
+
+	return func(yield func(Entity) bool) {
+		candidates, ok := q.filter.Candidates(em)
+		if !ok {
+			return
+		}
+
+		for entityID := range candidates {
+			if q.filter.MatchesEntity(em, entityID) && !yield(entityID) {
+				return
+			}
+		}
+	}
+}
+
+// Each calls fn for every matching entity.
+func (q *Query) Each(w World, fn func(*Entry)) {
+	for entityID := range q.Iter(w) {
+		fn(w.Entry(entityID))
+	}
+}
+
+// Count returns the number of matching entities.
+func (q *Query) Count(w World) int {
+	return ecs.Count(q.Iter(w))
+}
+
+// First returns the first matching entity.
+func (q *Query) First(w World) (*Entry, bool) {
+	entityID, ok := ecs.First(q.Iter(w))
+	if !ok {
+		return nil, false
+	}
+
+	return w.Entry(entityID), true
+}
@@ -0,0 +1,68 @@
+package donburi_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/compat/donburi"
+	"github.com/samix73/ebiten-ecs/compat/donburi/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type position struct{ X, Y float64 }
+
+type velocity struct{ X, Y float64 }
+
+type frozen struct{}
+
+var (
+	Position = donburi.NewComponentType[position]()
+	Velocity = donburi.NewComponentType[velocity](velocity{X: 1})
+	Frozen   = donburi.NewComponentType[frozen]()
+)
+
+func TestWorld(t *testing.T) {
+	em := ecs.NewEntityManager()
+	w := donburi.Wrap(em)
+
+	moving := w.Create(Position, Velocity)
+	stopped := w.Create(Position, Velocity, Frozen)
+	still := w.Create(Position)
+
+	entry := w.Entry(moving)
+	assert.Equal(t, velocity{X: 1}, Velocity.GetValue(entry), "components start as the default value")
+
+	Position.SetValue(entry, position{X: 3})
+	assert.Equal(t, 3.0, ecs.MustGetComponent[position](em, moving).X, "shims share the native storage")
+
+	query := donburi.NewQuery(filter.And(
+		filter.Contains(Position, Velocity),
+		filter.Not(filter.Contains(Frozen)),
+	))
+	assert.Equal(t, []ecs.EntityID{moving}, slices.Collect(query.Iter(w)))
+
+	query.Each(w, func(entry *donburi.Entry) {
+		pos, vel := Position.Get(entry), Velocity.Get(entry)
+		pos.X += vel.X
+	})
+	assert.Equal(t, 4.0, Position.Get(entry).X)
+
+	either := donburi.NewQuery(filter.Or(filter.Contains(Frozen), filter.Contains(Velocity)))
+	assert.ElementsMatch(t, []ecs.EntityID{moving, stopped}, slices.Collect(either.Iter(w)))
+	assert.Equal(t, 0, donburi.NewQuery(filter.Not(filter.Contains(Frozen))).Count(w))
+
+	stillEntry := w.Entry(still)
+	assert.False(t, stillEntry.HasComponent(Velocity))
+	stillEntry.AddComponent(Frozen)
+	assert.True(t, stillEntry.HasComponent(Frozen))
+
+	first, ok := Velocity.First(w)
+	require.True(t, ok)
+	assert.Contains(t, []ecs.EntityID{moving, stopped}, first.Entity())
+
+	entry.Remove()
+	assert.False(t, entry.Valid())
+	assert.True(t, stillEntry.Valid())
+}
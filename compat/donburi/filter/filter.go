@@ -0,0 +1,143 @@
+// Package filter mirrors the query filters of donburi's filter package on top of ecs.EntityManager.
+// See package donburi for how the shims are meant to be used.
+package filter
+
+import (
+	"iter"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// ComponentType is a component type that filters can test for, implemented by donburi.ComponentType.
+type ComponentType interface {
+	// Zero returns a zero value of the component, as passed to ecs.EntityManager.HasComponent.
+	Zero() any
+}
+
+// LayoutFilter selects entities by the components they have.
+type LayoutFilter interface {
+	// MatchesEntity reports whether the entity passes the filter.
+	MatchesEntity(em *ecs.EntityManager, entityID ecs.EntityID) bool
+	// Candidates returns a superset of the matching entities that queries iterate and test with MatchesEntity.
+	// It returns false if the filter requires no component, such as Not, in which case queries match nothing.
+	Candidates(em *ecs.EntityManager) (iter.Seq[ecs.EntityID], bool)
+}
+
+type contains []ComponentType
+
+// Contains matches entities that have all the component types.
+func Contains(componentTypes ...ComponentType) LayoutFilter {
+	return contains(componentTypes)
+}
+
+func (f contains) MatchesEntity(em *ecs.EntityManager, entityID ecs.EntityID) bool {
+	for _, componentType := range f {
+		if !em.HasComponent(entityID, componentType.Zero()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f contains) Candidates(em *ecs.EntityManager) (iter.Seq[ecs.EntityID], bool) {
+	if len(f) == 0 {
+		return nil, false
+	}
+
+	required := make([]any, len(f))
+	for i, componentType := range f {
+		required[i] = componentType.Zero()
+	}
+
+	return em.Query(required...), true
+}
+
+type not struct {
+	filter LayoutFilter
+}
+
+// Not matches entities that do not pass the filter.
+func Not(filter LayoutFilter) LayoutFilter {
+	return not{filter: filter}
+}
+
+func (f not) MatchesEntity(em *ecs.EntityManager, entityID ecs.EntityID) bool {
+	return !f.filter.MatchesEntity(em, entityID)
+}
+
+func (f not) Candidates(*ecs.EntityManager) (iter.Seq[ecs.EntityID], bool) {
+	return nil, false
+}
+
+type and []LayoutFilter
+
+// And matches entities that pass all the filters.
+func And(filters ...LayoutFilter) LayoutFilter {
+	return and(filters)
+}
+
+func (f and) MatchesEntity(em *ecs.EntityManager, entityID ecs.EntityID) bool {
+	for _, filter := range f {
+		if !filter.MatchesEntity(em, entityID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f and) Candidates(em *ecs.EntityManager) (iter.Seq[ecs.EntityID], bool) {
+	for _, filter := range f {
+		if candidates, ok := filter.Candidates(em); ok {
+			return candidates, true
+		}
+	}
+
+	return nil, false
+}
+
+type or []LayoutFilter
+
+// Or matches entities that pass any of the filters.
+func Or(filters ...LayoutFilter) LayoutFilter {
+	return or(filters)
+}
+
+func (f or) MatchesEntity(em *ecs.EntityManager, entityID ecs.EntityID) bool {
+	for _, filter := range f {
+		if filter.MatchesEntity(em, entityID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Candidates returns the union of the candidates of the filters, each entity once.
+func (f or) Candidates(em *ecs.EntityManager) (iter.Seq[ecs.EntityID], bool) {
+	branches := make([]iter.Seq[ecs.EntityID], len(f))
+	for i, filter := range f {
+		candidates, ok := filter.Candidates(em)
+		if !ok {
+			return nil, false
+		}
+		branches[i] = candidates
+	}
+
+	return func(yield func(ecs.EntityID) bool) {
+		seen := make(map[ecs.EntityID]struct{})
+		for _, candidates := range branches {
+			for entityID := range candidates {
+				if _, ok := seen[entityID]; ok {
+					continue
+				}
+				seen[entityID] = struct{}{}
+
+				if !yield(entityID) {
+					return
+				}
+			}
+		}
+	}, len(branches) > 0
+}
@@ -2,6 +2,7 @@ package ecs
 
 import (
 	"iter"
+	"slices"
 	"sync"
 )
 
@@ -9,6 +10,34 @@ type Component interface {
 	Reset()
 }
 
+// componentStorage is the storage of all components of a single type.
+type componentStorage interface {
+	Add(entityID EntityID) any
+	Remove(entityID EntityID)
+	Get(entityID EntityID) (any, bool)
+	Count() int
+	Entities() iter.Seq[EntityID]
+	// ids returns the IDs of the entities with a component, in storage order.
+	// The slice must not be modified.
+	ids() []EntityID
+	// entry returns the entity and component stored at index, for index in [0, Count()).
+	entry(index int) (EntityID, any)
+	Reserve(n int)
+	Teardown()
+	// save copies the components in storage order, and load replaces them with a copy, see EntityManager.Snapshot.
+	save() any
+	load(s storageSnapshot)
+	// stats returns the statistics of the storage, without its type.
+	stats() ComponentStats
+}
+
+var (
+	_ componentStorage = (*ComponentContainer)(nil)
+	_ componentStorage = (*InlineContainer[struct{}])(nil)
+)
+
+// ComponentContainer is the default component storage: components are pooled and stored by pointer,
+// so pointers returned by GetComponent stay valid until the component is removed.
 type ComponentContainer struct {
 	pool sync.Pool
 
@@ -16,16 +45,25 @@ type ComponentContainer struct {
 	entityIDs  []EntityID
 
 	componentLookupMap map[EntityID]int
+
+	// copier copies the components for snapshots; reflection is used if it is nil.
+	copier valueCopier
+	// allocations counts the components created by the pool.
+	allocations int
 }
 
 func NewComponentContainer(newFn func() any) *ComponentContainer {
-	return &ComponentContainer{
-		pool: sync.Pool{New: func() any { return newFn() }},
-
+	c := &ComponentContainer{
 		components:         make([]any, 0, 1024),
 		entityIDs:          make([]EntityID, 0, 1024),
 		componentLookupMap: make(map[EntityID]int),
 	}
+	c.pool.New = func() any {
+		c.allocations++
+		return newFn()
+	}
+
+	return c
 }
 
 func (c *ComponentContainer) Add(entityID EntityID) any {
@@ -46,6 +84,12 @@ func (c *ComponentContainer) Add(entityID EntityID) any {
 	return component
 }
 
+// Reserve grows the container so that n more components can be added without reallocating.
+func (c *ComponentContainer) Reserve(n int) {
+	c.components = slices.Grow(c.components, n)
+	c.entityIDs = slices.Grow(c.entityIDs, n)
+}
+
 func (c *ComponentContainer) Remove(entityID EntityID) {
 	indexToRemove, ok := c.componentLookupMap[entityID]
 	if !ok {
@@ -92,6 +136,14 @@ func (c *ComponentContainer) Get(entityID EntityID) (any, bool) {
 	return c.components[index], true
 }
 
+func (c *ComponentContainer) ids() []EntityID {
+	return c.entityIDs
+}
+
+func (c *ComponentContainer) entry(index int) (EntityID, any) {
+	return c.entityIDs[index], c.components[index]
+}
+
 func (c *ComponentContainer) Count() int {
 	return len(c.components)
 }
@@ -0,0 +1,139 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnregisteredComponent is returned when a component type that was not registered with RegisterComponent
+// must be identified by name or ID, e.g. to be serialized.
+var ErrUnregisteredComponent = errors.New("unregistered component")
+
+// ComponentTypeID identifies a component type registered with RegisterComponent. IDs start at 1.
+type ComponentTypeID uint32
+
+// registeredComponent is a component type registered with RegisterComponent.
+type registeredComponent struct {
+	id         ComponentTypeID
+	name       string
+	typ        reflect.Type
+	newStorage func() componentStorage
+}
+
+var componentRegistry = struct {
+	sync.RWMutex
+	byType map[reflect.Type]*registeredComponent
+	byName map[string]*registeredComponent
+	byID   []*registeredComponent
+}{
+	byType: make(map[reflect.Type]*registeredComponent),
+	byName: make(map[string]*registeredComponent),
+}
+
+// RegisterComponent registers component type C under name, e.g. "game.Transform", and returns its ID.
+// Names identify components in serialized data across versions of a game, and IDs identify them compactly
+// within a version: they are assigned in registration order, so programs registering the same components
+// in the same order, such as a server and its clients built from the same code, agree on them.
+// Registered components also keep the same mask bit in every EntityManager, and their names can be used
+// in scene files, see SceneRegistry.LoadScene.
+//
+// Registering a type again under the same name returns its ID. RegisterComponent panics if the name is taken
+// by another type or the type is registered under another name. Components are usually registered in init functions:
+//
+//	var TransformID = ecs.RegisterComponent[Transform]("game.Transform")
+func RegisterComponent[C any](name string) ComponentTypeID {
+	componentType := reflect.TypeFor[C]()
+
+	componentRegistry.Lock()
+	defer componentRegistry.Unlock()
+
+	if registered, ok := componentRegistry.byType[componentType]; ok {
+		if registered.name != name {
+			panic(fmt.Sprintf("ecs.RegisterComponent: %s is already registered as %q", componentType, registered.name))
+		}
+
+		return registered.id
+	}
+
+	if registered, ok := componentRegistry.byName[name]; ok {
+		panic(fmt.Sprintf("ecs.RegisterComponent: %q is already registered for %s", name, registered.typ))
+	}
+
+	registered := &registeredComponent{
+		id:   ComponentTypeID(len(componentRegistry.byID) + 1),
+		name: name,
+		typ:  componentType,
+		newStorage: func() componentStorage {
+			return newComponentContainer[C]()
+		},
+	}
+
+	componentRegistry.byType[componentType] = registered
+	componentRegistry.byName[name] = registered
+	componentRegistry.byID = append(componentRegistry.byID, registered)
+
+	return registered.id
+}
+
+func registeredComponentOf(componentType reflect.Type) (*registeredComponent, bool) {
+	componentRegistry.RLock()
+	defer componentRegistry.RUnlock()
+
+	registered, ok := componentRegistry.byType[componentType]
+	return registered, ok
+}
+
+// registeredComponentCount returns the number of registered component types.
+func registeredComponentCount() int {
+	componentRegistry.RLock()
+	defer componentRegistry.RUnlock()
+
+	return len(componentRegistry.byID)
+}
+
+// ComponentTypeIDOf returns the ID of a registered component type, or an error wrapping ErrUnregisteredComponent.
+func ComponentTypeIDOf(componentType reflect.Type) (ComponentTypeID, error) {
+	registered, ok := registeredComponentOf(componentType)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnregisteredComponent, componentType)
+	}
+
+	return registered.id, nil
+}
+
+// ComponentName returns the name of a registered component type, or an error wrapping ErrUnregisteredComponent.
+func ComponentName(componentType reflect.Type) (string, error) {
+	registered, ok := registeredComponentOf(componentType)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnregisteredComponent, componentType)
+	}
+
+	return registered.name, nil
+}
+
+// ComponentTypeByID returns the component type registered with the ID.
+func ComponentTypeByID(id ComponentTypeID) (reflect.Type, bool) {
+	componentRegistry.RLock()
+	defer componentRegistry.RUnlock()
+
+	if id == 0 || int(id) > len(componentRegistry.byID) {
+		return nil, false
+	}
+
+	return componentRegistry.byID[id-1].typ, true
+}
+
+// ComponentTypeByName returns the component type registered under the name.
+func ComponentTypeByName(name string) (reflect.Type, bool) {
+	componentRegistry.RLock()
+	defer componentRegistry.RUnlock()
+
+	registered, ok := componentRegistry.byName[name]
+	if !ok {
+		return nil, false
+	}
+
+	return registered.typ, true
+}
@@ -0,0 +1,107 @@
+package ecs_test
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registeredHealth struct {
+	Current, Max int
+}
+
+type registeredArmor struct {
+	Value int
+}
+
+var (
+	registeredHealthID = ecs.RegisterComponent[registeredHealth]("test.Health")
+	registeredArmorID  = ecs.RegisterComponent[registeredArmor]("test.Armor")
+)
+
+func TestRegisterComponent(t *testing.T) {
+	assert.NotZero(t, registeredHealthID)
+	assert.Equal(t, registeredHealthID+1, registeredArmorID, "IDs are assigned in registration order")
+	assert.Equal(t, registeredHealthID, ecs.RegisterComponent[registeredHealth]("test.Health"), "registering again returns the ID")
+
+	assert.PanicsWithValue(t, `ecs.RegisterComponent: ecs_test.registeredHealth is already registered as "test.Health"`, func() {
+		ecs.RegisterComponent[registeredHealth]("test.Life")
+	})
+	assert.PanicsWithValue(t, `ecs.RegisterComponent: "test.Health" is already registered for ecs_test.registeredHealth`, func() {
+		ecs.RegisterComponent[CameraComponent]("test.Health")
+	})
+
+	healthType := reflect.TypeFor[registeredHealth]()
+
+	id, err := ecs.ComponentTypeIDOf(healthType)
+	require.NoError(t, err)
+	assert.Equal(t, registeredHealthID, id)
+
+	name, err := ecs.ComponentName(healthType)
+	require.NoError(t, err)
+	assert.Equal(t, "test.Health", name)
+
+	typ, ok := ecs.ComponentTypeByID(registeredHealthID)
+	assert.True(t, ok)
+	assert.Equal(t, healthType, typ)
+
+	typ, ok = ecs.ComponentTypeByName("test.Armor")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeFor[registeredArmor](), typ)
+
+	_, ok = ecs.ComponentTypeByID(0)
+	assert.False(t, ok)
+	_, ok = ecs.ComponentTypeByName("test.Mana")
+	assert.False(t, ok)
+
+	_, err = ecs.ComponentTypeIDOf(reflect.TypeFor[CameraComponent]())
+	assert.ErrorIs(t, err, ecs.ErrUnregisteredComponent)
+	_, err = ecs.ComponentName(reflect.TypeFor[CameraComponent]())
+	assert.ErrorIs(t, err, ecs.ErrUnregisteredComponent)
+}
+
+func TestRegisteredComponentBits(t *testing.T) {
+	em := ecs.NewEntityManager()
+	entityID := em.NewEntity()
+
+	// Storages are created for the unregistered camera first, then for the registered components
+	// in reverse ID order, but registered components keep the bits of their IDs.
+	ecs.AddComponent[CameraComponent](em, entityID)
+	ecs.AddComponent[registeredArmor](em, entityID)
+	ecs.AddComponent[registeredHealth](em, entityID).Max = 5
+
+	assert.Equal(t, []reflect.Type{
+		reflect.TypeFor[registeredHealth](),
+		reflect.TypeFor[registeredArmor](),
+		reflect.TypeFor[CameraComponent](),
+	}, em.ComponentTypes(entityID))
+
+	assert.Equal(t, 1, ecs.Count(ecs.Query3[CameraComponent, registeredArmor, registeredHealth](em)))
+	health, armor, ok := ecs.GetComponents2[registeredHealth, registeredArmor](em, entityID)
+	require.True(t, ok)
+	assert.Equal(t, 5, health.Max)
+	assert.Zero(t, armor.Value)
+
+	snapshot := em.Snapshot()
+	health.Max = 1
+	em.Restore(snapshot)
+	assert.Equal(t, 5, health.Max)
+}
+
+func TestRegisteredComponentScene(t *testing.T) {
+	fsys := fstest.MapFS{
+		"level.json": {Data: []byte(`{"entities": [{"name": "knight", "components": {"test.Health": {"Current": 4, "Max": 4}}}]}`)},
+	}
+
+	em := ecs.NewEntityManager()
+	scene, err := ecs.NewSceneRegistry().LoadScene(em, fsys, "level.json")
+	require.NoError(t, err)
+
+	health, ok := ecs.GetComponent[registeredHealth](em, scene.Named["knight"])
+	require.True(t, ok)
+	assert.Equal(t, registeredHealth{Current: 4, Max: 4}, *health)
+}
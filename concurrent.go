@@ -0,0 +1,65 @@
+package ecs
+
+import "sync"
+
+// NewConcurrentEntityManager creates an EntityManager that background goroutines, e.g. streaming assets or
+// receiving network messages, can read and stage changes to while the game runs.
+//
+// The goroutine running the world, usually the game loop, owns the EntityManager: it is the only one changing it
+// directly, and holds its structural lock during SystemManager.Update, while flushing the command buffer and
+// during every structural change made outside an update. Other goroutines:
+//   - read entities and components inside Read, which blocks while the lock is held and must not change anything;
+//   - stage structural changes with Commands, which the owner applies at its next flush, using
+//     CommandBuffer.NewEntity to create entities they then refer to.
+//
+// Reads by the owner never lock; they can run alongside the queries inside Read, which share the cached
+// query plans and the query metrics with them. A plain EntityManager behaves the same but takes no lock,
+// and its Read provides no protection.
+func NewConcurrentEntityManager() *EntityManager {
+	em := NewEntityManager()
+	em.structure = new(sync.RWMutex)
+
+	return em
+}
+
+// Concurrent reports whether the EntityManager was created by NewConcurrentEntityManager.
+func (em *EntityManager) Concurrent() bool {
+	return em.structure != nil
+}
+
+// Read calls fn with the EntityManager locked for reading, so that fn, e.g. on a background goroutine,
+// can safely query entities and read their components. Pointers to components must not be kept after fn
+// returns. See NewConcurrentEntityManager.
+func (em *EntityManager) Read(fn func()) {
+	if em.structure != nil {
+		em.structure.RLock()
+		defer em.structure.RUnlock()
+	}
+
+	fn()
+}
+
+// noUnlock is returned by lockStructure for EntityManagers that are not concurrent.
+var noUnlock = func() {}
+
+// lockStructure takes the structural lock of a concurrent EntityManager and returns the function releasing it.
+// The lock is reentrant for the owner goroutine, the only one allowed to take it.
+func (em *EntityManager) lockStructure() func() {
+	if em.structure == nil {
+		return noUnlock
+	}
+
+	if em.structureDepth == 0 {
+		em.structure.Lock()
+	}
+	em.structureDepth++
+
+	return em.unlockStructure
+}
+
+func (em *EntityManager) unlockStructure() {
+	em.structureDepth--
+	if em.structureDepth == 0 {
+		em.structure.Unlock()
+	}
+}
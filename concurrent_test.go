@@ -0,0 +1,135 @@
+package ecs_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestConcurrentEntityManager(t *testing.T) {
+	em := ecs.NewConcurrentEntityManager()
+	require.True(t, em.Concurrent())
+	assert.False(t, ecs.NewEntityManager().Concurrent())
+
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+	require.NoError(t, sm.Add(ecs.NewSystem(ecs.SystemOptions{ID: ecs.NextSystemID()}, func(s *ecs.FuncSystem) error {
+		for entityID := range ecs.Query[TransformComponent](s.EntityManager()) {
+			ecs.MustGetComponent[TransformComponent](s.EntityManager(), entityID).Rotation++
+		}
+
+		return nil
+	})))
+
+	// A background goroutine streams entities in while the game updates.
+	const streamed = 50
+	var wg sync.WaitGroup
+	staged := make(chan ecs.EntityID, streamed)
+	wg.Go(func() {
+		for range streamed {
+			entityID := em.Commands().NewEntity()
+			ecs.DeferAddComponent(em.Commands(), entityID, TransformComponent{Rotation: -1})
+			staged <- entityID
+
+			em.Read(func() {
+				for entityID := range ecs.Query[TransformComponent](em) {
+					_ = ecs.MustGetComponent[TransformComponent](em, entityID).Rotation
+				}
+			})
+		}
+		close(staged)
+	})
+
+	for range streamed {
+		require.NoError(t, sm.Update())
+	}
+	wg.Wait()
+	em.Commands().Flush(em)
+
+	for entityID := range staged {
+		assert.True(t, em.Exists(entityID))
+		assert.GreaterOrEqual(t, ecs.MustGetComponent[TransformComponent](em, entityID).Rotation, -1.0)
+	}
+	assert.Equal(t, streamed, ecs.Count(ecs.Query[TransformComponent](em)))
+}
+
+func TestConcurrentQueries(t *testing.T) {
+	em := ecs.NewConcurrentEntityManager()
+	em.SetStrict(true)
+	em.SetQueryMetricsEnabled(true)
+	for range 10 {
+		ecs.AddComponent[TransformComponent](em, em.NewEntity())
+	}
+
+	// Readers and the owner share the query plans and metrics.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 100 {
+				em.Read(func() {
+					assert.Equal(t, 10, ecs.Count(ecs.Query[TransformComponent](em)))
+					_ = ecs.Count(ecs.Where(em, ecs.Query[TransformComponent](em), func(*TransformComponent) bool { return true }))
+				})
+			}
+		})
+	}
+	for range 100 {
+		assert.Equal(t, 10, ecs.Count(ecs.Query[TransformComponent](em)))
+		_ = em.QueryMetrics()
+	}
+	wg.Wait()
+
+	// A query iterated inside Read delays the owner's structural changes rather than making them panic.
+	iterating := make(chan struct{})
+	wg.Go(func() {
+		em.Read(func() {
+			for range ecs.Query[TransformComponent](em) {
+				close(iterating)
+				time.Sleep(10 * time.Millisecond)
+				break
+			}
+		})
+	})
+	<-iterating
+	assert.NotPanics(t, func() { em.NewEntity() })
+	wg.Wait()
+}
+
+func TestConcurrentSpatialQueries(t *testing.T) {
+	em := ecs.NewConcurrentEntityManager()
+	for i := range 20 {
+		newPositionedEntity(em, float64(i*10), 0)
+	}
+
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+	require.NoError(t, sm.Add(ecs.NewIteratingSystem(0, func(_ ecs.EntityID, tr *transform.Transform) error {
+		tr.Position[1]++
+		return nil
+	})))
+
+	// Readers query the spatial index while the owner moves the entities and queries it too.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 100 {
+				em.Read(func() {
+					assert.Equal(t, 20, ecs.Count(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{100, 0}, 1000)))
+					assert.Equal(t, 20, ecs.Count(ecs.QueryWithinBounds[transform.Transform](em, f64.Vec2{-10, -10}, f64.Vec2{1000, 1000})))
+				})
+			}
+		})
+	}
+	for range 100 {
+		require.NoError(t, sm.Update())
+		assert.Equal(t, 20, ecs.Count(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{100, 0}, 1000)))
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, ecs.Count(ecs.QueryWithinBounds[transform.Transform](em, f64.Vec2{0, 100}, f64.Vec2{0, 100})),
+		"the index is rebuilt after each update")
+}
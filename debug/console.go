@@ -0,0 +1,501 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.DrawableSystem = (*Console)(nil)
+
+// maxConsoleOutput is the number of output lines a Console keeps.
+const maxConsoleOutput = 1000
+
+// ErrUnknownCommand is returned by Console.Execute for a command that is not registered.
+var ErrUnknownCommand = errors.New("unknown command")
+
+// ConsoleCommand runs a console command with its arguments and returns its output.
+type ConsoleCommand func(c *Console, args []string) (string, error)
+
+type consoleCommand struct {
+	usage string
+	run   ConsoleCommand
+}
+
+// Console is an in-game developer console. It is hidden until ToggleKey (` by default) is pressed. While
+// visible, typed text goes to its input line: Enter runs the line, and Up and Down browse the history.
+// The built-in commands are:
+//
+//	help                                   lists the commands
+//	list [component]                       lists the entities, or those with the component
+//	get <entity> <component> [field]       prints a component or one of its fields
+//	set <entity> <component> <field> <value>
+//	spawn <prefab>                         spawns a prefab of Registry
+//	load <scene>                           loads a scene file from Scenes with Registry
+//	systems                                lists the systems
+//	toggle <system>                        enables or disables a system other than the console, by ID or name
+//
+// Components are named as registered with ecs.RegisterComponent, and fields by their dotted path, e.g.
+// Position.0. Arguments containing spaces are quoted. Games add their own commands with Register. Like the Overlay, the console keeps running while the game is paused.
+type Console struct {
+	*ecs.BaseSystem
+
+	sm       *ecs.SystemManager
+	commands map[string]consoleCommand
+	visible  bool
+
+	input   []rune
+	history []string
+	// recalled is the position in history of the input line, len(history) when typing a new line.
+	recalled int
+	output   []string
+
+	// ToggleKey shows and hides the console.
+	ToggleKey ebiten.Key
+	// Registry resolves the prefab and component names of spawn and load, ecs.DefaultSceneRegistry by default.
+	Registry *ecs.SceneRegistry
+	// Scenes is the file system load reads scene files from.
+	Scenes fs.FS
+	// Lines is the number of output lines shown.
+	Lines int
+	// X and Y are the screen position of the panel.
+	X, Y int
+}
+
+// NewConsole creates a hidden Console whose systems and toggle commands act on sm.
+func NewConsole(id ecs.SystemID, priority int, sm *ecs.SystemManager) *Console {
+	c := &Console{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		sm:         sm,
+		commands:   make(map[string]consoleCommand),
+		ToggleKey:  ebiten.KeyGraveAccent,
+		Registry:   ecs.DefaultSceneRegistry,
+		Lines:      12,
+		X:          16,
+		Y:          16,
+	}
+	c.SetAlwaysRun(true)
+
+	c.Register("help", "help", (*Console).help)
+	c.Register("list", "list [component]", (*Console).list)
+	c.Register("get", "get <entity> <component> [field]", (*Console).get)
+	c.Register("set", "set <entity> <component> <field> <value>", (*Console).set)
+	c.Register("spawn", "spawn <prefab>", (*Console).spawn)
+	c.Register("load", "load <scene>", (*Console).load)
+	c.Register("systems", "systems", (*Console).systems)
+	c.Register("toggle", "toggle <system>", (*Console).toggle)
+
+	return c
+}
+
+// Register adds a command, replacing any command of the same name. usage is shown by help.
+func (c *Console) Register(name, usage string, command ConsoleCommand) {
+	c.commands[name] = consoleCommand{usage: usage, run: command}
+}
+
+// Visible reports whether the console is drawn and handles input.
+func (c *Console) Visible() bool {
+	return c.visible
+}
+
+// SetVisible shows or hides the console.
+func (c *Console) SetVisible(visible bool) {
+	c.visible = visible
+}
+
+// Output returns the last lines printed by the commands, up to 1000, oldest first.
+func (c *Console) Output() []string {
+	return c.output
+}
+
+// History returns the lines executed, oldest first.
+func (c *Console) History() []string {
+	return c.history
+}
+
+// Execute runs a command line as if it was typed, adds it to the history and prints its output or error.
+func (c *Console) Execute(line string) (string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", nil
+	}
+
+	c.history = append(c.history, line)
+	c.recalled = len(c.history)
+	c.print("> " + line)
+
+	output, err := c.run(line)
+	if err != nil {
+		c.print("error: " + err.Error())
+		return "", err
+	}
+
+	if output != "" {
+		c.print(output)
+	}
+
+	return output, nil
+}
+
+func (c *Console) run(line string) (string, error) {
+	args, err := splitArgs(line)
+	if err != nil {
+		return "", err
+	}
+
+	command, ok := c.commands[args[0]]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", args[0], ErrUnknownCommand)
+	}
+
+	return command.run(c, args[1:])
+}
+
+func (c *Console) print(text string) {
+	c.output = append(c.output, strings.Split(text, "\n")...)
+	if len(c.output) > maxConsoleOutput {
+		c.output = slices.Clone(c.output[len(c.output)-maxConsoleOutput:])
+	}
+}
+
+// splitArgs splits a command line at spaces, keeping double-quoted arguments together.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] != '"' {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			args, line = append(args, line[:end]), line[end:]
+			continue
+		}
+
+		prefix, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated quote in %s", line)
+		}
+
+		arg, _ := strconv.Unquote(prefix)
+		args, line = append(args, arg), line[len(prefix):]
+	}
+
+	return args, nil
+}
+
+// Update handles the toggle key and, while visible, the input line.
+func (c *Console) Update() error {
+	if inpututil.IsKeyJustPressed(c.ToggleKey) {
+		c.visible = !c.visible
+		// The toggle key also types a character, which does not belong to the input.
+		return nil
+	}
+
+	if !c.visible {
+		return nil
+	}
+
+	c.input = ebiten.AppendInputChars(c.input)
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		line := string(c.input)
+		c.input = c.input[:0]
+		_, _ = c.Execute(line)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(c.input) > 0:
+		c.input = c.input[:len(c.input)-1]
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) && c.recalled > 0:
+		c.recalled--
+		c.input = []rune(c.history[c.recalled])
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) && c.recalled < len(c.history):
+		c.recalled++
+		c.input = c.input[:0]
+		if c.recalled < len(c.history) {
+			c.input = []rune(c.history[c.recalled])
+		}
+	}
+
+	return nil
+}
+
+// Draw draws the last output lines and the input line.
+func (c *Console) Draw(screen *ebiten.Image) {
+	if !c.visible {
+		return
+	}
+
+	lines := c.output[max(len(c.output)-c.Lines, 0):]
+	lines = append(slices.Clone(lines), "> "+string(c.input)+"_")
+
+	width := 40
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+
+	vector.DrawFilledRect(screen, float32(c.X-4), float32(c.Y-4),
+		float32(width*glyphWidth+8), float32(len(lines)*lineHeight+8), PanelBackground, false)
+	ebitenutil.DebugPrintAt(screen, strings.Join(lines, "\n"), c.X, c.Y)
+}
+
+func (c *Console) help(args []string) (string, error) {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	usages := make([]string, 0, len(names))
+	for _, name := range names {
+		usages = append(usages, c.commands[name].usage)
+	}
+
+	return strings.Join(usages, "\n"), nil
+}
+
+func (c *Console) list(args []string) (string, error) {
+	em := c.EntityManager()
+
+	var componentType reflect.Type
+	if len(args) > 0 {
+		var err error
+		if componentType, err = c.componentType(args[0]); err != nil {
+			return "", err
+		}
+	}
+
+	var lines []string
+	for _, entityID := range em.Entities() {
+		if componentType != nil {
+			if _, ok := em.GetComponentByType(entityID, componentType); !ok {
+				continue
+			}
+		}
+
+		var names []string
+		for _, componentType := range em.ComponentTypes(entityID) {
+			name, err := ecs.ComponentName(componentType)
+			if err != nil {
+				name = componentType.String()
+			}
+			names = append(names, name)
+		}
+		lines = append(lines, fmt.Sprintf("%d: %s", entityID, strings.Join(names, ", ")))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *Console) get(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", errors.New("usage: get <entity> <component> [field]")
+	}
+
+	value, err := c.field(args[0], args[1], args[2:])
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%+v", value), nil
+}
+
+func (c *Console) set(args []string) (string, error) {
+	if len(args) != 4 {
+		return "", errors.New("usage: set <entity> <component> <field> <value>")
+	}
+
+	value, err := c.field(args[0], args[1], args[2:3])
+	if err != nil {
+		return "", err
+	}
+
+	if !value.CanSet() {
+		return "", fmt.Errorf("field %s cannot be set", args[2])
+	}
+
+	if err := setFromString(value, args[3]); err != nil {
+		return "", fmt.Errorf("field %s: %w", args[2], err)
+	}
+
+	return fmt.Sprintf("%s = %+v", args[2], value), nil
+}
+
+// field returns the component of an entity, or one of its fields if a path is given.
+func (c *Console) field(entityArg, componentName string, path []string) (reflect.Value, error) {
+	entityID, err := parseEntity(entityArg)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	componentType, err := c.componentType(componentName)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	component, ok := c.EntityManager().GetComponentByType(entityID, componentType)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("entity %d has no %s", entityID, componentName)
+	}
+
+	value := reflect.ValueOf(component).Elem()
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	field, ok := fieldByPath(value, strings.Split(path[0], "."))
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%s has no exported field %s", componentName, path[0])
+	}
+
+	return field, nil
+}
+
+// setFromString parses text as a value of the kind of v and sets it.
+func setFromString(v reflect.Value, text string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot set a %s", v.Type())
+	}
+
+	return nil
+}
+
+func (c *Console) spawn(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("usage: spawn <prefab>")
+	}
+
+	prefab, ok := c.Registry.Prefab(args[0])
+	if !ok {
+		return "", fmt.Errorf("%s: %w", args[0], ecs.ErrUnknownScenePrefab)
+	}
+
+	return fmt.Sprintf("spawned entity %d", prefab.Spawn(c.EntityManager())), nil
+}
+
+func (c *Console) load(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("usage: load <scene>")
+	}
+
+	if c.Scenes == nil {
+		return "", errors.New("no scene file system, see Console.Scenes")
+	}
+
+	scene, err := c.Registry.LoadScene(c.EntityManager(), c.Scenes, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("loaded %d entities", len(scene.Entities)), nil
+}
+
+func (c *Console) systems(args []string) (string, error) {
+	var lines []string
+	for system := range c.sm.Systems() {
+		state := "enabled"
+		if toggler, ok := system.(interface{ Enabled() bool }); ok && !toggler.Enabled() {
+			state = "disabled"
+		}
+
+		lines = append(lines, fmt.Sprintf("%d %s: %s", system.ID(), systemName(system), state))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *Console) toggle(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("usage: toggle <system>")
+	}
+
+	for system := range c.sm.Systems() {
+		if strconv.FormatUint(uint64(system.ID()), 10) != args[0] && systemName(system) != args[0] {
+			continue
+		}
+
+		if system.ID() == c.ID() {
+			return "", errors.New("the console cannot toggle itself")
+		}
+
+		toggler, ok := system.(interface {
+			Enabled() bool
+			SetEnabled(enabled bool)
+		})
+		if !ok {
+			return "", fmt.Errorf("system %s cannot be toggled", args[0])
+		}
+
+		toggler.SetEnabled(!toggler.Enabled())
+		if toggler.Enabled() {
+			return fmt.Sprintf("enabled %s", args[0]), nil
+		}
+
+		return fmt.Sprintf("disabled %s", args[0]), nil
+	}
+
+	return "", fmt.Errorf("no system %s", args[0])
+}
+
+// componentType returns the component type registered for the console under name.
+func (c *Console) componentType(name string) (reflect.Type, error) {
+	componentType, ok := ecs.ComponentTypeByName(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ecs.ErrUnknownSceneComponent)
+	}
+
+	return componentType, nil
+}
+
+func parseEntity(text string) (ecs.EntityID, error) {
+	id, err := strconv.ParseUint(text, 10, 64)
+	if err != nil {
+		return ecs.UndefinedID, fmt.Errorf("invalid entity %q", text)
+	}
+
+	return ecs.EntityID(id), nil
+}
+
+// systemName returns the name of the system, or its type if it has none.
+func systemName(system ecs.System) string {
+	if named, ok := system.(interface{ Name() string }); ok && named.Name() != "" {
+		return named.Name()
+	}
+
+	return reflect.TypeOf(system).String()
+}
@@ -0,0 +1,90 @@
+package debug_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/debug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type consoleHealth struct {
+	Current int
+	Name    string
+}
+
+func init() {
+	ecs.RegisterComponent[consoleHealth]("console.Health")
+}
+
+func TestConsole(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	console := debug.NewConsole(ecs.NextSystemID(), 0, sm)
+	require.NoError(t, sm.Add(console))
+	console.SetName("console")
+
+	registry := ecs.NewSceneRegistry()
+	registry.RegisterPrefab(ecs.NewPrefab("goblin", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[consoleHealth](em, entityID).Current = 3
+	}))
+	console.Registry = registry
+	console.Scenes = fstest.MapFS{"level.json": {Data: []byte(`{"entities": [{"prefab": "goblin"}, {"prefab": "goblin"}]}`)}}
+
+	output, err := console.Execute("spawn goblin")
+	require.NoError(t, err)
+	goblin := em.Entities()[0]
+	assert.Equal(t, "spawned entity "+itoa(goblin), output)
+
+	output, err = console.Execute(`set ` + itoa(goblin) + ` console.Health Name "big goblin"`)
+	require.NoError(t, err)
+	assert.Equal(t, "Name = big goblin", output)
+	_, err = console.Execute("set " + itoa(goblin) + " console.Health Current 7")
+	require.NoError(t, err)
+	assert.Equal(t, consoleHealth{Current: 7, Name: "big goblin"}, *ecs.MustGetComponent[consoleHealth](em, goblin))
+
+	output, err = console.Execute("get " + itoa(goblin) + " console.Health Current")
+	require.NoError(t, err)
+	assert.Equal(t, "7", output)
+
+	output, err = console.Execute("list console.Health")
+	require.NoError(t, err)
+	assert.Equal(t, itoa(goblin)+": console.Health, ecs.PrefabInstance", output)
+
+	output, err = console.Execute("load level.json")
+	require.NoError(t, err)
+	assert.Equal(t, "loaded 2 entities", output)
+	assert.Len(t, em.Entities(), 3)
+
+	_, err = console.Execute("toggle console")
+	assert.Error(t, err, "the console cannot disable itself")
+	assert.True(t, console.Enabled())
+
+	spinner := ecs.NewSystem(ecs.SystemOptions{Name: "spinner"}, func(*ecs.FuncSystem) error { return nil })
+	require.NoError(t, sm.Add(spinner))
+	output, err = console.Execute("toggle spinner")
+	require.NoError(t, err)
+	assert.Equal(t, "disabled spinner", output)
+	assert.False(t, spinner.Enabled())
+
+	console.Register("echo", "echo <text>", func(c *debug.Console, args []string) (string, error) {
+		return args[0], nil
+	})
+	output, err = console.Execute(`echo "hello world"`)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", output)
+
+	_, err = console.Execute("teleport")
+	assert.ErrorIs(t, err, debug.ErrUnknownCommand)
+	assert.Equal(t, []string{"> teleport", "error: teleport: unknown command"}, console.Output()[len(console.Output())-2:])
+	assert.Len(t, console.History(), 10)
+
+	for range 2000 {
+		console.Execute("help")
+	}
+	assert.LessOrEqual(t, len(console.Output()), 1000, "the output is capped")
+}
@@ -0,0 +1,62 @@
+package debug
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.DrawableSystem = (*FrameStepper)(nil)
+
+// FrameStepper is a debug control for frame-step debugging, see ecs.Game.SetFrameStepping: ToggleKey (F3 by
+// default) pauses and resumes the simulation, and StepKey (F4 by default) advances it by exactly one tick,
+// pausing it first if needed. While stepping, "STEP" and the game tick are drawn in the top-left corner.
+// Like the Overlay, it keeps running while the game is paused.
+type FrameStepper struct {
+	*ecs.BaseSystem
+
+	// ToggleKey turns frame stepping on and off.
+	ToggleKey ebiten.Key
+	// StepKey advances the simulation by one tick.
+	StepKey ebiten.Key
+}
+
+// NewFrameStepper creates a FrameStepper.
+func NewFrameStepper(id ecs.SystemID, priority int) *FrameStepper {
+	s := &FrameStepper{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		ToggleKey:  ebiten.KeyF3,
+		StepKey:    ebiten.KeyF4,
+	}
+	s.SetAlwaysRun(true)
+
+	return s
+}
+
+// Update handles the keys.
+func (s *FrameStepper) Update() error {
+	game := s.Game()
+
+	if inpututil.IsKeyJustPressed(s.ToggleKey) {
+		game.SetFrameStepping(!game.FrameStepping())
+	}
+
+	if inpututil.IsKeyJustPressed(s.StepKey) {
+		game.StepOnce()
+	}
+
+	return nil
+}
+
+// Draw shows the tick while stepping.
+func (s *FrameStepper) Draw(screen *ebiten.Image) {
+	game := s.Game()
+	if game == nil || !game.FrameStepping() {
+		return
+	}
+
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("STEP #%d", game.Clock().Tick()), 16, 16)
+}
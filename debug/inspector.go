@@ -0,0 +1,413 @@
+package debug
+
+import (
+	"cmp"
+	"fmt"
+	"image/color"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.DrawableSystem = (*Inspector)(nil)
+
+const (
+	// inspectorListWidth is the width in characters of the entity list.
+	inspectorListWidth = 16
+	// maxValueLength truncates long field values, e.g. slices.
+	maxValueLength = 60
+)
+
+// PanelBackground is the color of the panels drawn behind the Inspector and the StatsOverlay.
+var PanelBackground = color.RGBA{A: 0xc0}
+
+// InspectedField is a field of a component of the inspected entity.
+type InspectedField struct {
+	Component reflect.Type
+	// Path is the dotted path of the field within the component, e.g. "Position.0", or "" for the component itself.
+	Path  string
+	Value reflect.Value
+	// Editable reports whether the field is an exported number that can be edited in place.
+	Editable bool
+}
+
+func (f InspectedField) String() string {
+	value := fmt.Sprint(f.Value)
+	if f.Value.Kind() == reflect.Float32 || f.Value.Kind() == reflect.Float64 {
+		value = strconv.FormatFloat(f.Value.Float(), 'g', 6, 64)
+	}
+
+	if len(value) > maxValueLength {
+		value = value[:maxValueLength-3] + "..."
+	}
+
+	return f.Path + " = " + value
+}
+
+// Inspector is a debug panel listing the entities of its EntityManager and the components and field values
+// of the selected one. It is hidden until ToggleKey (F1 by default) is pressed. While visible:
+//
+//   - PageDown and PageUp, or clicking the entity list, select the next or previous entity;
+//   - ] and [ select the next or previous editable field, if Editable is set;
+//   - = and - increase or decrease the selected field by Step, or ten times Step with Shift.
+//
+// Like the Overlay, it keeps running while the game is paused.
+type Inspector struct {
+	*ecs.BaseSystem
+
+	visible  bool
+	selected ecs.EntityID
+	field    int
+
+	// ToggleKey shows and hides the inspector.
+	ToggleKey ebiten.Key
+	// Editable enables editing numeric fields.
+	Editable bool
+	// Step is the amount a field changes by per key press.
+	Step float64
+	// Rows is the number of entities shown in the list.
+	Rows int
+	// X and Y are the screen position of the panel.
+	X, Y int
+}
+
+// NewInspector creates a hidden Inspector.
+func NewInspector(id ecs.SystemID, priority int) *Inspector {
+	i := &Inspector{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		ToggleKey:  ebiten.KeyF1,
+		Step:       1,
+		Rows:       20,
+		X:          16,
+		Y:          48,
+	}
+	i.SetAlwaysRun(true)
+
+	return i
+}
+
+// Visible reports whether the inspector is drawn and handles input.
+func (i *Inspector) Visible() bool {
+	return i.visible
+}
+
+// SetVisible shows or hides the inspector.
+func (i *Inspector) SetVisible(visible bool) {
+	i.visible = visible
+}
+
+// Selected returns the inspected entity, or false if there is none.
+func (i *Inspector) Selected() (ecs.EntityID, bool) {
+	if !i.EntityManager().Exists(i.selected) {
+		return ecs.UndefinedID, false
+	}
+
+	return i.selected, true
+}
+
+// Select inspects the entity.
+func (i *Inspector) Select(entityID ecs.EntityID) {
+	if entityID != i.selected {
+		i.field = 0
+	}
+	i.selected = entityID
+}
+
+// Cycle selects the entity offset positions after the selected one in ID order, wrapping around.
+func (i *Inspector) Cycle(offset int) {
+	entities := i.EntityManager().Entities()
+	if len(entities) == 0 {
+		return
+	}
+
+	index, found := slices.BinarySearch(entities, i.selected)
+	if !found && offset > 0 {
+		// The entity after the removed selection is already at index.
+		offset--
+	}
+
+	n := len(entities)
+	i.Select(entities[((index+offset)%n+n)%n])
+}
+
+// Fields returns the fields of the selected entity's components, ordered by component type name.
+// Structs and arrays are expanded so their numeric elements can be edited.
+func (i *Inspector) Fields() []InspectedField {
+	entityID, ok := i.Selected()
+	if !ok {
+		return nil
+	}
+
+	type entry struct {
+		typ       reflect.Type
+		component any
+	}
+
+	var components []entry
+	for typ, component := range i.EntityManager().Components(entityID) {
+		components = append(components, entry{typ, component})
+	}
+	slices.SortFunc(components, func(a, b entry) int { return cmp.Compare(a.typ.String(), b.typ.String()) })
+
+	var fields []InspectedField
+	for _, c := range components {
+		fields = appendFields(fields, c.typ, "", reflect.ValueOf(c.component).Elem(), true)
+	}
+
+	return fields
+}
+
+// appendFields appends the leaves of value. exported tells whether value was reached through exported fields only.
+func appendFields(fields []InspectedField, component reflect.Type, path string, value reflect.Value, exported bool) []InspectedField {
+	join := func(step string) string {
+		if path == "" {
+			return step
+		}
+
+		return path + "." + step
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.NumField() == 0 {
+			return append(fields, InspectedField{Component: component, Path: path, Value: value})
+		}
+
+		for n := range value.NumField() {
+			field := value.Type().Field(n)
+			fields = appendFields(fields, component, join(field.Name), value.Field(n), exported && field.IsExported())
+		}
+
+		return fields
+	case reflect.Array:
+		if value.Len() <= 4 {
+			for n := range value.Len() {
+				fields = appendFields(fields, component, join(strconv.Itoa(n)), value.Index(n), exported)
+			}
+
+			return fields
+		}
+	}
+
+	return append(fields, InspectedField{
+		Component: component,
+		Path:      path,
+		Value:     value,
+		Editable:  exported && value.CanSet() && isNumber(value.Kind()),
+	})
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// editableFields returns the indices in fields of the editable fields.
+func editableFields(fields []InspectedField) []int {
+	var indices []int
+	for n, field := range fields {
+		if field.Editable {
+			indices = append(indices, n)
+		}
+	}
+
+	return indices
+}
+
+// SelectField selects the editable field offset positions after the selected one, wrapping around.
+func (i *Inspector) SelectField(offset int) {
+	n := len(editableFields(i.Fields()))
+	if n == 0 {
+		i.field = 0
+		return
+	}
+
+	i.field = ((i.field+offset)%n + n) % n
+}
+
+// SelectedField returns the selected editable field, or false if the entity has none or Editable is not set.
+func (i *Inspector) SelectedField() (InspectedField, bool) {
+	if !i.Editable {
+		return InspectedField{}, false
+	}
+
+	fields := i.Fields()
+	editable := editableFields(fields)
+	if len(editable) == 0 {
+		return InspectedField{}, false
+	}
+
+	return fields[editable[min(i.field, len(editable)-1)]], true
+}
+
+// Adjust adds delta to the selected field, truncated for integers and saturated to their range.
+func (i *Inspector) Adjust(delta float64) {
+	field, ok := i.SelectedField()
+	if !ok {
+		return
+	}
+
+	value := field.Value
+	switch {
+	case value.CanInt():
+		v := float64(value.Int()) + delta
+		if !value.OverflowInt(int64(v)) {
+			value.SetInt(int64(v))
+		}
+	case value.CanUint():
+		v := max(float64(value.Uint())+delta, 0)
+		if !value.OverflowUint(uint64(v)) {
+			value.SetUint(uint64(v))
+		}
+	case value.CanFloat():
+		value.SetFloat(value.Float() + delta)
+	}
+}
+
+// Lines returns the text of the details panel: the selected entity followed by its components and fields.
+func (i *Inspector) Lines() []string {
+	entityID, ok := i.Selected()
+	if !ok {
+		return []string{"No entity selected (PageDown)"}
+	}
+
+	em := i.EntityManager()
+
+	header := fmt.Sprintf("Entity %d", entityID)
+	if !em.Active(entityID) {
+		header += " (inactive)"
+	}
+	if tags := em.Tags(entityID); len(tags) > 0 {
+		header += fmt.Sprintf(" tags: %v", tags)
+	}
+
+	lines := []string{header}
+
+	selected, hasSelected := i.SelectedField()
+
+	var component reflect.Type
+	for _, field := range i.Fields() {
+		if field.Component != component {
+			component = field.Component
+			lines = append(lines, component.String())
+		}
+
+		if field.Path == "" {
+			continue
+		}
+
+		marker := "  "
+		if hasSelected && field.Component == selected.Component && field.Path == selected.Path {
+			marker = "> "
+		}
+		lines = append(lines, marker+field.String())
+	}
+
+	return lines
+}
+
+// listWindow returns the entities shown in the list, a window of Rows entities around the selected one.
+func (i *Inspector) listWindow() []ecs.EntityID {
+	entities := i.EntityManager().Entities()
+	rows := max(i.Rows, 1)
+	if len(entities) <= rows {
+		return entities
+	}
+
+	index, _ := slices.BinarySearch(entities, i.selected)
+	start := min(max(index-rows/2, 0), len(entities)-rows)
+
+	return entities[start : start+rows]
+}
+
+// Update handles the toggle key and, while visible, the selection and editing keys.
+func (i *Inspector) Update() error {
+	if inpututil.IsKeyJustPressed(i.ToggleKey) {
+		i.visible = !i.visible
+	}
+
+	if !i.visible {
+		return nil
+	}
+
+	if _, ok := i.Selected(); !ok {
+		i.Cycle(1)
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyPageDown):
+		i.Cycle(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyPageUp):
+		i.Cycle(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketRight):
+		i.SelectField(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft):
+		i.SelectField(-1)
+	}
+
+	step := i.Step
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		step *= 10
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEqual):
+		i.Adjust(step)
+	case inpututil.IsKeyJustPressed(ebiten.KeyMinus):
+		i.Adjust(-step)
+	}
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		row := (y - i.Y) / lineHeight
+		entities := i.listWindow()
+		if x >= i.X && x < i.X+inspectorListWidth*glyphWidth && y >= i.Y && row < len(entities) {
+			i.Select(entities[row])
+		}
+	}
+
+	return nil
+}
+
+// Draw draws the entity list and the details of the selected entity.
+func (i *Inspector) Draw(screen *ebiten.Image) {
+	if !i.visible {
+		return
+	}
+
+	entities := i.listWindow()
+	lines := i.Lines()
+
+	detailsX := i.X + (inspectorListWidth+2)*glyphWidth
+	width := inspectorListWidth + 2
+	for _, line := range lines {
+		width = max(width, inspectorListWidth+2+len(line))
+	}
+	height := max(len(entities), len(lines))
+
+	vector.DrawFilledRect(screen, float32(i.X-4), float32(i.Y-4),
+		float32(width*glyphWidth+8), float32(height*lineHeight+8), PanelBackground, false)
+
+	for row, entityID := range entities {
+		label := fmt.Sprintf("  %d", entityID)
+		if entityID == i.selected {
+			label = fmt.Sprintf("> %d", entityID)
+		}
+		ebitenutil.DebugPrintAt(screen, label, i.X, i.Y+row*lineHeight)
+	}
+
+	ebitenutil.DebugPrintAt(screen, strings.Join(lines, "\n"), detailsX, i.Y)
+}
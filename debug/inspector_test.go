@@ -0,0 +1,110 @@
+package debug_test
+
+import (
+	"strconv"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/debug"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+type stats struct {
+	Health uint8
+	Speed  float32
+	Name   string
+	secret int
+}
+
+func TestInspector(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	inspector := debug.NewInspector(ecs.NextID(), 0)
+	sm.Add(inspector)
+
+	first := em.NewEntity()
+	player := em.NewEntity()
+	ecs.AddComponent[transform.Transform](em, player).Position = f64.Vec2{1, 2}
+	*ecs.AddComponent[stats](em, player) = stats{Health: 250, Speed: 1.5, Name: "hero", secret: 7}
+	em.Tag(player, "player")
+	last := em.NewEntity()
+	em.SetActive(last, false)
+
+	_, ok := inspector.Selected()
+	assert.False(t, ok)
+
+	inspector.Cycle(1)
+	selected, _ := inspector.Selected()
+	assert.Equal(t, first, selected)
+	inspector.Cycle(-1)
+	selected, _ = inspector.Selected()
+	assert.Equal(t, last, selected)
+	assert.Equal(t, []string{"Entity " + itoa(last) + " (inactive)"}, inspector.Lines())
+
+	inspector.Select(player)
+	assert.Equal(t, []string{
+		"Entity " + itoa(player) + " tags: [player]",
+		"debug_test.stats",
+		"  Health = 250",
+		"  Speed = 1.5",
+		"  Name = hero",
+		"  secret = 7",
+		"transform.Transform",
+		"  Position.0 = 1",
+		"  Position.1 = 2",
+		"  Rotation = 0",
+		"  Scale.0 = 1",
+		"  Scale.1 = 1",
+	}, inspector.Lines())
+
+	// Fields can only be edited once enabled.
+	_, ok = inspector.SelectedField()
+	assert.False(t, ok)
+	inspector.Editable = true
+
+	field, ok := inspector.SelectedField()
+	require.True(t, ok)
+	assert.Equal(t, "Health", field.Path)
+	assert.Contains(t, inspector.Lines(), "> Health = 250")
+
+	// Integers saturate instead of overflowing.
+	inspector.Adjust(10)
+	assert.Equal(t, uint8(250), ecs.MustGetComponent[stats](em, player).Health)
+	inspector.Adjust(-50)
+	assert.Equal(t, uint8(200), ecs.MustGetComponent[stats](em, player).Health)
+
+	inspector.SelectField(1)
+	inspector.Adjust(0.5)
+	assert.Equal(t, float32(2), ecs.MustGetComponent[stats](em, player).Speed)
+
+	// Strings and unexported fields are skipped.
+	inspector.SelectField(1)
+	field, _ = inspector.SelectedField()
+	assert.Equal(t, "Position.0", field.Path)
+	inspector.Adjust(-3)
+	assert.Equal(t, f64.Vec2{-2, 2}, ecs.MustGetComponent[transform.Transform](em, player).Position)
+
+	inspector.SelectField(-3)
+	field, _ = inspector.SelectedField()
+	assert.Equal(t, "Scale.1", field.Path)
+
+	// Removing the selected entity selects the next one when cycling.
+	em.Remove(player)
+	assert.Equal(t, []string{"No entity selected (PageDown)"}, inspector.Lines())
+	inspector.Cycle(1)
+	selected, _ = inspector.Selected()
+	assert.Equal(t, last, selected)
+
+	game.Time().Advance(1.0 / 60)
+	require.NoError(t, sm.Update())
+	assert.False(t, inspector.Visible())
+}
+
+func itoa(entityID ecs.EntityID) string {
+	return strconv.FormatUint(uint64(entityID), 10)
+}
@@ -0,0 +1,125 @@
+package debug
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.DrawableSystem = (*Overlay)(nil)
+
+const (
+	lineHeight      = 16
+	sparklineWidth  = 80
+	sparklineHeight = 12
+	sparklineGap    = 8
+	// glyphWidth is the width of a character of the ebitenutil debug font.
+	glyphWidth = 6
+)
+
+// SparklineColor is the color of the sparklines drawn by the Overlay.
+var SparklineColor = color.RGBA{R: 0x40, G: 0xe0, B: 0x80, A: 0xff}
+
+// Overlay is a system that samples its watches every tick and draws them in the top-left corner
+// of the screen, followed by a sparkline for plotted watches.
+// It keeps running while the game is paused so values can be inspected.
+type Overlay struct {
+	*ecs.BaseSystem
+
+	watches *Watches
+	visible bool
+
+	// X and Y are the screen position of the first line.
+	X, Y int
+}
+
+// NewOverlay creates a visible Overlay showing the given watches.
+func NewOverlay(id ecs.SystemID, priority int, watches *Watches) *Overlay {
+	o := &Overlay{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		watches:    watches,
+		visible:    true,
+		X:          16,
+		Y:          48,
+	}
+	o.SetAlwaysRun(true)
+
+	return o
+}
+
+// Watches returns the watches shown by the overlay.
+func (o *Overlay) Watches() *Watches {
+	return o.watches
+}
+
+// Visible reports whether the overlay is drawn.
+func (o *Overlay) Visible() bool {
+	return o.visible
+}
+
+// SetVisible shows or hides the overlay. Watches are sampled either way so sparklines stay continuous.
+func (o *Overlay) SetVisible(visible bool) {
+	o.visible = visible
+}
+
+// Update samples every watch.
+func (o *Overlay) Update() error {
+	o.watches.Sample()
+	return nil
+}
+
+// Draw prints the watches and their sparklines.
+func (o *Overlay) Draw(screen *ebiten.Image) {
+	if !o.visible {
+		return
+	}
+
+	for i, w := range o.watches.All() {
+		line := w.String()
+		y := o.Y + i*lineHeight
+		ebitenutil.DebugPrintAt(screen, line, o.X, y)
+
+		if history := w.History(); len(history) > 1 {
+			x := o.X + len(line)*glyphWidth + sparklineGap
+			drawSparkline(screen, history, float32(x), float32(y+2))
+		}
+	}
+}
+
+// drawSparkline plots values scaled to their own range, leaving gaps at NaN samples.
+func drawSparkline(screen *ebiten.Image, values []float64, x, y float32) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+
+	if math.IsInf(lo, 1) {
+		return
+	}
+
+	span := hi - lo
+	point := func(i int) (float32, float32) {
+		px := x + float32(i)*sparklineWidth/float32(len(values)-1)
+		if span == 0 {
+			return px, y + sparklineHeight/2
+		}
+
+		return px, y + sparklineHeight - float32((values[i]-lo)/span)*sparklineHeight
+	}
+
+	for i := 1; i < len(values); i++ {
+		if math.IsNaN(values[i-1]) || math.IsNaN(values[i]) {
+			continue
+		}
+
+		x0, y0 := point(i - 1)
+		x1, y1 := point(i)
+		vector.StrokeLine(screen, x0, y0, x1, y1, 1, SparklineColor, false)
+	}
+}
@@ -0,0 +1,156 @@
+package debug
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.DrawableSystem = (*StatsOverlay)(nil)
+
+// memStatsInterval is the interval between two reads of the runtime memory statistics,
+// which briefly stop the world.
+const memStatsInterval = time.Second
+
+// StatsOverlay is a system drawing, in the top-right corner of the screen, the TPS and FPS, the entity
+// and component counts of its EntityManager, garbage collector statistics and the update durations of the
+// systems of a SystemManager, whose timings it enables. ToggleKey (F2 by default) shows and hides it.
+// Like the Overlay, it keeps running while the game is paused.
+type StatsOverlay struct {
+	*ecs.BaseSystem
+
+	systems *ecs.SystemManager
+	visible bool
+
+	memStats runtime.MemStats
+	// memStatsRead is the unscaled game time, in seconds, at which memStats was last read.
+	memStatsRead float64
+	hasMemStats  bool
+
+	// ToggleKey shows and hides the overlay.
+	ToggleKey ebiten.Key
+	// Rows is the number of slowest systems and largest component storages shown.
+	Rows int
+	// Margin is the distance in pixels to the top and right edges of the screen.
+	Margin int
+}
+
+// NewStatsOverlay creates a visible StatsOverlay showing the timings of the systems of sm.
+func NewStatsOverlay(id ecs.SystemID, priority int, sm *ecs.SystemManager) *StatsOverlay {
+	sm.SetTimingsEnabled(true)
+
+	o := &StatsOverlay{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		systems:    sm,
+		visible:    true,
+		ToggleKey:  ebiten.KeyF2,
+		Rows:       8,
+		Margin:     16,
+	}
+	o.SetAlwaysRun(true)
+
+	return o
+}
+
+// Visible reports whether the overlay is drawn.
+func (o *StatsOverlay) Visible() bool {
+	return o.visible
+}
+
+// SetVisible shows or hides the overlay.
+func (o *StatsOverlay) SetVisible(visible bool) {
+	o.visible = visible
+}
+
+// Update handles the toggle key and refreshes the memory statistics once per second of unscaled game time
+// while visible.
+func (o *StatsOverlay) Update() error {
+	if inpututil.IsKeyJustPressed(o.ToggleKey) {
+		o.visible = !o.visible
+	}
+
+	now := o.Time().UnscaledTotal()
+	if o.visible && (!o.hasMemStats || now-o.memStatsRead >= memStatsInterval.Seconds()) {
+		runtime.ReadMemStats(&o.memStats)
+		o.memStatsRead, o.hasMemStats = now, true
+	}
+
+	return nil
+}
+
+// Lines returns the text of the overlay.
+func (o *StatsOverlay) Lines() []string {
+	stats := o.EntityManager().Stats()
+
+	lines := []string{
+		fmt.Sprintf("TPS %.1f  FPS %.1f", ebiten.ActualTPS(), ebiten.ActualFPS()),
+		fmt.Sprintf("Entities %d (%d inactive)", stats.Entities, stats.Inactive),
+	}
+
+	if o.hasMemStats {
+		var lastPause time.Duration
+		if o.memStats.NumGC > 0 {
+			lastPause = time.Duration(o.memStats.PauseNs[(o.memStats.NumGC+255)%256])
+		}
+
+		lines = append(lines, fmt.Sprintf("Heap %.1f MB, GC %d (last pause %s)",
+			float64(o.memStats.HeapAlloc)/(1<<20), o.memStats.NumGC, lastPause))
+	}
+
+	components := slices.Clone(stats.Components)
+	slices.SortStableFunc(components, func(a, b ecs.ComponentStats) int { return cmp.Compare(b.Count, a.Count) })
+	if len(components) > 0 {
+		lines = append(lines, "Components")
+	}
+	for _, c := range components[:min(len(components), o.Rows)] {
+		lines = append(lines, fmt.Sprintf("  %s %d/%d", c.Type, c.Count, c.Capacity))
+	}
+
+	timings := o.systems.Timings()
+	slices.SortStableFunc(timings, func(a, b ecs.SystemTiming) int { return cmp.Compare(b.Average, a.Average) })
+	if len(timings) > 0 {
+		lines = append(lines, "Systems")
+	}
+	for _, t := range timings[:min(len(timings), o.Rows)] {
+		name := t.Name
+		if name == "" {
+			name = fmt.Sprintf("system %d", t.ID)
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s %s (max %s)", name, formatDuration(t.Average), formatDuration(t.Max)))
+	}
+
+	return lines
+}
+
+// formatDuration prints durations in milliseconds with a fixed precision, so the columns do not jitter.
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
+
+// Draw prints the statistics in the top-right corner of the screen.
+func (o *StatsOverlay) Draw(screen *ebiten.Image) {
+	if !o.visible {
+		return
+	}
+
+	lines := o.Lines()
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+
+	x := screen.Bounds().Dx() - o.Margin - width*glyphWidth
+	vector.DrawFilledRect(screen, float32(x-4), float32(o.Margin-4),
+		float32(width*glyphWidth+8), float32(len(lines)*lineHeight+8), PanelBackground, false)
+	ebitenutil.DebugPrintAt(screen, strings.Join(lines, "\n"), x, o.Margin)
+}
@@ -0,0 +1,40 @@
+package debug_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/debug"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsOverlay(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	overlay := debug.NewStatsOverlay(ecs.NextID(), 1000, sm)
+	overlay.SetName("stats")
+	sm.Add(overlay)
+	assert.True(t, sm.TimingsEnabled())
+
+	for range 3 {
+		ecs.AddComponent[transform.Transform](em, em.NewEntity())
+	}
+	em.SetActive(em.NewEntity(), false)
+
+	game.Time().Advance(1.0 / 60)
+	require.NoError(t, sm.Update())
+
+	lines := overlay.Lines()
+	require.GreaterOrEqual(t, len(lines), 7)
+	assert.Contains(t, lines[0], "TPS")
+	assert.Equal(t, "Entities 4 (1 inactive)", lines[1])
+	assert.Contains(t, lines[2], "Heap")
+	assert.Equal(t, "Components", lines[3])
+	assert.Contains(t, lines[4], "transform.Transform 3/")
+	assert.Equal(t, "Systems", lines[5])
+	assert.Contains(t, lines[6], "stats ")
+}
@@ -0,0 +1,234 @@
+// Package debug provides in-game debugging tools: watch expressions on component fields
+// and queries, shown live by the Overlay system, and the entity Inspector.
+package debug
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Expr is a watch expression. It returns the current value, or false if the value is unavailable,
+// e.g. because the watched entity was removed.
+type Expr func() (any, bool)
+
+// Func watches the value returned by fn.
+func Func(fn func() any) Expr {
+	return func() (any, bool) {
+		return fn(), true
+	}
+}
+
+// Field watches a field of the entity's component C. The path is a dotted list of struct field
+// names and array or slice indices, e.g. "Zoom", "Position.0" or "Body.Velocity.1";
+// an empty path watches the whole component.
+func Field[C any](em *ecs.EntityManager, entityID ecs.EntityID, path string) Expr {
+	var steps []string
+	if path != "" {
+		steps = strings.Split(path, ".")
+	}
+
+	return func() (any, bool) {
+		component, ok := ecs.GetComponent[C](em, entityID)
+		if !ok {
+			return nil, false
+		}
+
+		value, ok := fieldByPath(reflect.ValueOf(component).Elem(), steps)
+		if !ok {
+			return nil, false
+		}
+
+		return value.Interface(), true
+	}
+}
+
+// Count watches the number of entities yielded by the query, which is re-issued on every evaluation.
+func Count(query func() iter.Seq[ecs.EntityID]) Expr {
+	return func() (any, bool) {
+		return ecs.Count(query()), true
+	}
+}
+
+// CountOf watches the number of entities with component C.
+func CountOf[C any](em *ecs.EntityManager) Expr {
+	return Count(func() iter.Seq[ecs.EntityID] {
+		return ecs.Query[C](em)
+	})
+}
+
+func fieldByPath(value reflect.Value, steps []string) (reflect.Value, bool) {
+	for _, step := range steps {
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+
+		switch value.Kind() {
+		case reflect.Struct:
+			field, ok := value.Type().FieldByName(step)
+			if !ok || !field.IsExported() {
+				return reflect.Value{}, false
+			}
+			value = value.FieldByIndex(field.Index)
+		case reflect.Array, reflect.Slice:
+			index, err := strconv.Atoi(step)
+			if err != nil || index < 0 || index >= value.Len() {
+				return reflect.Value{}, false
+			}
+			value = value.Index(index)
+		default:
+			return reflect.Value{}, false
+		}
+	}
+
+	return value, true
+}
+
+// Watch is a labelled watch expression together with its latest value and, if plotted, its history.
+type Watch struct {
+	Label string
+
+	expr    Expr
+	value   any
+	ok      bool
+	history []float64
+	next    int
+	samples int
+}
+
+// Plot records the last n numeric values of the watch so the overlay can draw them as a sparkline.
+// A non-positive n disables plotting.
+func (w *Watch) Plot(n int) *Watch {
+	if n <= 0 {
+		w.history = nil
+	} else {
+		w.history = make([]float64, n)
+	}
+	w.next = 0
+	w.samples = 0
+
+	return w
+}
+
+// Sample evaluates the expression and appends its numeric value to the history.
+func (w *Watch) Sample() {
+	w.value, w.ok = w.expr()
+
+	if w.history == nil {
+		return
+	}
+
+	sample := math.NaN()
+	if w.ok {
+		sample = toFloat(w.value)
+	}
+
+	w.history[w.next] = sample
+	w.next = (w.next + 1) % len(w.history)
+	w.samples = min(w.samples+1, len(w.history))
+}
+
+// Value returns the value of the last Sample, or false if it was unavailable.
+func (w *Watch) Value() (any, bool) {
+	return w.value, w.ok
+}
+
+// History returns the plotted values from oldest to newest. Non-numeric or unavailable samples are NaN.
+func (w *Watch) History() []float64 {
+	history := make([]float64, 0, w.samples)
+	start := (w.next - w.samples + len(w.history)) % max(len(w.history), 1)
+	for i := range w.samples {
+		history = append(history, w.history[(start+i)%len(w.history)])
+	}
+
+	return history
+}
+
+func (w *Watch) String() string {
+	if !w.ok {
+		return w.Label + ": <unavailable>"
+	}
+
+	switch v := w.value.(type) {
+	case float32, float64:
+		return fmt.Sprintf("%s: %.3f", w.Label, v)
+	default:
+		return fmt.Sprintf("%s: %v", w.Label, v)
+	}
+}
+
+// toFloat converts numeric and boolean values to float64, and anything else to NaN.
+func toFloat(value any) float64 {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+		return 0
+	default:
+		return math.NaN()
+	}
+}
+
+// Watches is an ordered set of watch expressions.
+type Watches struct {
+	watches []*Watch
+}
+
+// NewWatches creates an empty set of watches.
+func NewWatches() *Watches {
+	return &Watches{}
+}
+
+// Add registers a watch expression under the label, replacing any watch with the same label.
+func (ws *Watches) Add(label string, expr Expr) *Watch {
+	w := &Watch{Label: label, expr: expr}
+
+	for i, existing := range ws.watches {
+		if existing.Label == label {
+			ws.watches[i] = w
+			return w
+		}
+	}
+
+	ws.watches = append(ws.watches, w)
+
+	return w
+}
+
+// Remove unregisters the watch with the label.
+func (ws *Watches) Remove(label string) {
+	for i, w := range ws.watches {
+		if w.Label == label {
+			ws.watches = append(ws.watches[:i], ws.watches[i+1:]...)
+			return
+		}
+	}
+}
+
+// All returns the watches in registration order.
+func (ws *Watches) All() []*Watch {
+	return ws.watches
+}
+
+// Sample evaluates every watch.
+func (ws *Watches) Sample() {
+	for _, w := range ws.watches {
+		w.Sample()
+	}
+}
@@ -0,0 +1,46 @@
+package debug_test
+
+import (
+	"math"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/debug"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatches(t *testing.T) {
+	em := ecs.NewEntityManager()
+	player := em.NewEntity()
+	tr := ecs.AddComponent[transform.Transform](em, player)
+
+	watches := debug.NewWatches()
+	x := watches.Add("player.x", debug.Field[transform.Transform](em, player, "Position.0")).Plot(3)
+	count := watches.Add("transforms", debug.CountOf[transform.Transform](em))
+	bad := watches.Add("bad", debug.Field[transform.Transform](em, player, "Missing"))
+
+	for i := range 4 {
+		tr.Position[0] = float64(i)
+		watches.Sample()
+	}
+
+	value, ok := x.Value()
+	require.True(t, ok)
+	assert.Equal(t, 3.0, value)
+	assert.Equal(t, []float64{1, 2, 3}, x.History())
+	assert.Equal(t, "player.x: 3.000", x.String())
+
+	assert.Equal(t, "transforms: 1", count.String())
+	assert.Equal(t, "bad: <unavailable>", bad.String())
+
+	em.Remove(player)
+	watches.Sample()
+	_, ok = x.Value()
+	assert.False(t, ok)
+	assert.True(t, math.IsNaN(x.History()[2]))
+
+	watches.Remove("bad")
+	assert.Len(t, watches.All(), 2)
+}
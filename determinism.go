@@ -0,0 +1,184 @@
+package ecs
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+)
+
+// Rand is a seeded pseudo-random number generator. Given the same seed and the same calls, it produces
+// the same numbers on every platform, which the global math/rand functions do not guarantee.
+// Its state can be saved with MarshalBinary, e.g. alongside a replay or a save file.
+type Rand struct {
+	*rand.Rand
+
+	source *rand.PCG
+	seed   uint64
+}
+
+// NewRand creates a generator seeded with seed.
+func NewRand(seed uint64) *Rand {
+	source := rand.NewPCG(seed, seed)
+
+	return &Rand{
+		Rand:   rand.New(source),
+		source: source,
+		seed:   seed,
+	}
+}
+
+// Seed returns the seed the generator was created or last reseeded with.
+func (r *Rand) Seed() uint64 {
+	return r.seed
+}
+
+// Reseed restarts the generator from seed.
+func (r *Rand) Reseed(seed uint64) {
+	r.source.Seed(seed, seed)
+	r.seed = seed
+}
+
+// MarshalBinary encodes the state of the generator.
+func (r *Rand) MarshalBinary() ([]byte, error) {
+	state, err := r.source.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return binary.LittleEndian.AppendUint64(state, r.seed), nil
+}
+
+// UnmarshalBinary restores a state encoded by MarshalBinary.
+func (r *Rand) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return errors.New("ecs.Rand.UnmarshalBinary: state too short")
+	}
+
+	split := len(data) - 8
+	if err := r.source.UnmarshalBinary(data[:split]); err != nil {
+		return fmt.Errorf("ecs.Rand.UnmarshalBinary source.UnmarshalBinary error: %w", err)
+	}
+	r.seed = binary.LittleEndian.Uint64(data[split:])
+
+	return nil
+}
+
+// Checksum hashes the entities of em, their active state, tags and components, to detect peers of a lockstep
+// game or a replay diverging. Entities are hashed in ID order and components in type name order, so the
+// checksum only depends on the world state. Component values are hashed field by field, including unexported
+// fields; pointers, functions, channels and unsafe pointers only contribute whether they are nil, since their
+// addresses differ between processes, and maps are hashed independently of their iteration order.
+func Checksum(em *EntityManager) uint64 {
+	h := fnv.New64a()
+
+	var types []reflect.Type
+	for _, entityID := range em.Entities() {
+		writeUint64(h, uint64(entityID))
+		writeBool(h, em.Active(entityID))
+
+		tags := em.Tags(entityID)
+		writeUint64(h, uint64(len(tags)))
+		for _, tag := range tags {
+			writeString(h, string(tag))
+		}
+
+		types = slices.AppendSeq(types[:0], em.entityComponentTypes(entityID))
+		slices.SortFunc(types, func(a, b reflect.Type) int { return cmp.Compare(a.String(), b.String()) })
+
+		for _, componentType := range types {
+			component, ok := em.componentContainers[componentType].Get(entityID)
+			if !ok {
+				continue
+			}
+
+			writeString(h, componentType.String())
+			hashValue(h, reflect.ValueOf(component))
+		}
+	}
+
+	return h.Sum64()
+}
+
+func writeUint64(h hash.Hash64, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	h.Write(buf[:])
+}
+
+func writeBool(h hash.Hash64, v bool) {
+	if v {
+		writeUint64(h, 1)
+	} else {
+		writeUint64(h, 0)
+	}
+}
+
+func writeString(h hash.Hash64, s string) {
+	writeUint64(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+// hashValue writes v to h. Components are stored as pointers, so the top level pointer is followed.
+func hashValue(h hash.Hash64, v reflect.Value) {
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	hashField(h, v)
+}
+
+func hashField(h hash.Hash64, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		writeBool(h, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint64(h, math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeUint64(h, math.Float64bits(real(c)))
+		writeUint64(h, math.Float64bits(imag(c)))
+	case reflect.String:
+		writeString(h, v.String())
+	case reflect.Array, reflect.Slice:
+		writeUint64(h, uint64(v.Len()))
+		for i := range v.Len() {
+			hashField(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			hashField(h, v.Field(i))
+		}
+	case reflect.Map:
+		// Sum the hashes of the entries, which does not depend on the iteration order.
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := fnv.New64a()
+			hashField(entry, iter.Key())
+			hashField(entry, iter.Value())
+			sum += entry.Sum64()
+		}
+		writeUint64(h, uint64(v.Len()))
+		writeUint64(h, sum)
+	case reflect.Interface:
+		writeBool(h, v.IsNil())
+		if !v.IsNil() {
+			elem := v.Elem()
+			writeString(h, elem.Type().String())
+			hashField(h, elem)
+		}
+	case reflect.Pointer, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		writeBool(h, v.IsNil())
+	}
+}
@@ -0,0 +1,103 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRand(t *testing.T) {
+	a, b := ecs.NewRand(42), ecs.NewRand(42)
+	for range 10 {
+		assert.Equal(t, a.Uint64(), b.Uint64())
+	}
+	assert.Equal(t, uint64(42), a.Seed())
+
+	state, err := a.MarshalBinary()
+	require.NoError(t, err)
+	want := []int{a.IntN(100), a.IntN(100), a.IntN(100)}
+
+	restored := ecs.NewRand(0)
+	require.NoError(t, restored.UnmarshalBinary(state))
+	assert.Equal(t, want, []int{restored.IntN(100), restored.IntN(100), restored.IntN(100)})
+	assert.Equal(t, uint64(42), restored.Seed())
+	assert.Error(t, restored.UnmarshalBinary(nil))
+
+	restored.Reseed(42)
+	fresh := ecs.NewRand(42)
+	assert.Equal(t, fresh.Float64(), restored.Float64())
+}
+
+func TestDeterministicGame(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{Deterministic: true, Seed: 7})
+	game.Time().SetFixedStep(0.25)
+	require.NoError(t, game.SetActiveWorld(&hookWorld{events: new([]string), name: "world"}))
+	assert.True(t, game.Deterministic())
+
+	require.NoError(t, game.Update())
+	require.NoError(t, game.Update())
+	assert.Equal(t, 0.25, game.Time().UnscaledDelta())
+	assert.Equal(t, 0.5, game.Time().Total())
+
+	sm := game.ActiveWorld().(*hookWorld).SystemManager()
+	assert.Equal(t, uint64(2), sm.Tick())
+	assert.Equal(t, uint64(7), sm.Rand().Seed())
+
+	// Systems of equal priority run in ID order regardless of the order they are added in.
+	var order []ecs.SystemID
+	record := func(s *ecs.FuncSystem) error {
+		order = append(order, s.ID())
+		return nil
+	}
+
+	first, second := ecs.NextID(), ecs.NextID()
+	systemB := ecs.NewSystem(ecs.SystemOptions{ID: second, Priority: 1}, record)
+	systemA := ecs.NewSystem(ecs.SystemOptions{ID: first, Priority: 1}, record)
+	sm.Add(systemB)
+	sm.Add(systemA)
+	assert.Same(t, sm.Rand(), systemA.Rand())
+
+	require.NoError(t, game.Update())
+	assert.Equal(t, []ecs.SystemID{first, second}, order)
+}
+
+type checksummed struct {
+	Position [2]float64
+	Names    map[string]int
+	Target   *int
+	hidden   int
+}
+
+func (c *checksummed) Reset() { *c = checksummed{} }
+
+func TestChecksum(t *testing.T) {
+	em := ecs.NewEntityManager()
+	entityID := em.NewEntity()
+	component := ecs.AddComponent[checksummed](em, entityID)
+	component.Names = map[string]int{"a": 1, "b": 2, "c": 3}
+	ecs.AddComponent[NameComponent](em, entityID)
+
+	sum := ecs.Checksum(em)
+	assert.Equal(t, sum, ecs.Checksum(em), "maps are hashed independently of iteration order")
+
+	component.Target = new(int)
+	assert.NotEqual(t, sum, ecs.Checksum(em))
+	component.Target = new(int)
+	changed := ecs.Checksum(em)
+	assert.Equal(t, changed, ecs.Checksum(em), "pointer addresses are ignored")
+
+	component.hidden = 1
+	assert.NotEqual(t, changed, ecs.Checksum(em))
+	component.hidden = 0
+
+	em.SetActive(entityID, false)
+	assert.NotEqual(t, changed, ecs.Checksum(em))
+	em.SetActive(entityID, true)
+
+	em.Tag(entityID, "player")
+	assert.NotEqual(t, changed, ecs.Checksum(em))
+	em.Untag(entityID, "player")
+	assert.Equal(t, changed, ecs.Checksum(em))
+}
@@ -0,0 +1,113 @@
+package ecstest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// DiffEntities compares entity aID of a with entity bID of b, reporting a as got and b as want, and
+// returns their differences, one per line, or "" if they are equal: their active state, their tags and
+// their components, matched by registered name and compared with reflect.DeepEqual, so that unexported
+// fields count too. Entities of different EntityManagers can be compared, e.g. to verify that an entity
+// survives a save and load round-trip.
+func DiffEntities(a *ecs.EntityManager, aID ecs.EntityID, b *ecs.EntityManager, bID ecs.EntityID) string {
+	switch {
+	case !a.Exists(aID) && !b.Exists(bID):
+		return ""
+	case !a.Exists(aID):
+		return fmt.Sprintf("got entity %d does not exist", aID)
+	case !b.Exists(bID):
+		return fmt.Sprintf("want entity %d does not exist", bID)
+	}
+
+	return strings.Join(diffEntity("", a, aID, b, bID), "\n")
+}
+
+// DiffWorlds compares the entities of a and b, reporting a as got and b as want, pairing them in ID
+// order like Dump, and returns their differences as DiffEntities does, prefixed with the entity number, or
+// "" if the worlds are equal, e.g. to verify that two runs of a deterministic simulation did not diverge.
+func DiffWorlds(a, b *ecs.EntityManager) string {
+	aEntities, bEntities := a.Entities(), b.Entities()
+
+	var diffs []string
+	for index := range max(len(aEntities), len(bEntities)) {
+		switch {
+		case index >= len(aEntities):
+			diffs = append(diffs, fmt.Sprintf("%s: missing entity", entityHeader(b, bEntities[index], index)))
+		case index >= len(bEntities):
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected entity", entityHeader(a, aEntities[index], index)))
+		default:
+			diffs = append(diffs, diffEntity(entityName(index)+" ", a, aEntities[index], b, bEntities[index])...)
+		}
+	}
+
+	return strings.Join(diffs, "\n")
+}
+
+// AssertEntityEqual fails the test with the DiffEntities of the two entities if they differ.
+func AssertEntityEqual(t testing.TB, em1 *ecs.EntityManager, id1 ecs.EntityID, em2 *ecs.EntityManager, id2 ecs.EntityID) bool {
+	t.Helper()
+
+	if diff := DiffEntities(em1, id1, em2, id2); diff != "" {
+		t.Errorf("ecstest.AssertEntityEqual: entity %d differs from entity %d:\n%s", id1, id2, diff)
+		return false
+	}
+
+	return true
+}
+
+// diffEntity returns the differences between two existing entities, each prefixed with prefix.
+func diffEntity(prefix string, a *ecs.EntityManager, aID ecs.EntityID, b *ecs.EntityManager, bID ecs.EntityID) []string {
+	var diffs []string
+	if gotActive, wantActive := a.Active(aID), b.Active(bID); gotActive != wantActive {
+		diffs = append(diffs, fmt.Sprintf("%sactive: got %t, want %t", prefix, gotActive, wantActive))
+	}
+
+	if gotTags, wantTags := sortedTags(a, aID), sortedTags(b, bID); !slices.Equal(gotTags, wantTags) {
+		diffs = append(diffs, fmt.Sprintf("%stags: got %v, want %v", prefix, gotTags, wantTags))
+	}
+
+	gotComponents, wantComponents := namedComponents(a, aID), namedComponents(b, bID)
+	for _, name := range sortedUnion(gotComponents, wantComponents) {
+		got, inGot := gotComponents[name]
+		want, inWant := wantComponents[name]
+		if inGot && inWant && reflect.DeepEqual(got, want) {
+			continue
+		}
+
+		gotValue, wantValue := componentValue(got), componentValue(want)
+		if inGot && inWant && gotValue == wantValue {
+			// The difference is in fields that are not encoded.
+			gotValue, wantValue = fmt.Sprintf("%+v", reflect.ValueOf(got).Elem()), fmt.Sprintf("%+v", reflect.ValueOf(want).Elem())
+		}
+
+		diffs = append(diffs, componentDiff(prefix+name, gotValue, inGot, wantValue, inWant))
+	}
+
+	return diffs
+}
+
+func sortedTags(em *ecs.EntityManager, entityID ecs.EntityID) []string {
+	names := make([]string, 0)
+	for _, tag := range em.Tags(entityID) {
+		names = append(names, string(tag))
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// namedComponents returns the components of the entity by name.
+func namedComponents(em *ecs.EntityManager, entityID ecs.EntityID) map[string]any {
+	components := make(map[string]any)
+	for componentType, component := range em.Components(entityID) {
+		components[componentName(componentType)] = component
+	}
+
+	return components
+}
@@ -0,0 +1,261 @@
+// Package ecstest helps write regression tests for gameplay logic: build a world in a test, step it a
+// number of ticks deterministically, and compare a canonical dump of its entities with a golden file.
+//
+//	func TestCombat(t *testing.T) {
+//		w := ecstest.NewWorld(t, combat.NewDamageSystem())
+//		spawnArena(w.EntityManager())
+//
+//		w.Step(60)
+//		ecstest.AssertGolden(t, w.EntityManager(), "testdata/combat.golden")
+//	}
+//
+// Run the tests with ECSTEST_UPDATE=1 to write the golden files instead of comparing them.
+package ecstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden write golden files when set to 1.
+const UpdateEnv = "ECSTEST_UPDATE"
+
+// World is a world built for a test, active in a deterministic headless Game.
+type World struct {
+	*ecs.BaseWorld
+
+	t       testing.TB
+	game    *ecs.Game
+	systems []ecs.System
+}
+
+// NewWorld creates a World with the systems, in a Game with GameConfig.Deterministic set, so that every
+// Step lasts exactly one fixed step and systems of equal priority run in ID order. It fails the test if the
+// systems cannot be added.
+func NewWorld(t testing.TB, systems ...ecs.System) *World {
+	t.Helper()
+
+	w := &World{t: t, game: ecs.NewGame(&ecs.GameConfig{Deterministic: true}), systems: systems}
+	if err := w.game.SetActiveWorld(w); err != nil {
+		t.Fatalf("ecstest.NewWorld: %v", err)
+	}
+
+	return w
+}
+
+// Init creates the entity and system managers of the world.
+func (w *World) Init(g *ecs.Game) error {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+	for _, system := range w.systems {
+		if err := sm.Add(system); err != nil {
+			return err
+		}
+	}
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+// Game returns the game running the world.
+func (w *World) Game() *ecs.Game {
+	return w.game
+}
+
+// Step updates the game n times, failing the test if an update returns an error.
+func (w *World) Step(n int) {
+	w.t.Helper()
+
+	for i := range n {
+		if err := w.Game().Update(); err != nil {
+			w.t.Fatalf("ecstest.World.Step: tick %d of %d: %v", i+1, n, err)
+		}
+	}
+}
+
+// Dump returns a canonical text dump of the entities of em: one line per entity, followed by one line per
+// component, sorted by name, with its exported fields as JSON. Entities are numbered #1, #2... in ID
+// order rather than by ID, so that dumps do not depend on the IDs allocated by other tests. The
+// ecs.EntityID and ecs.Ref fields of components are dumped as these numbers too: "removed" stands for
+// the ID of a removed entity, and "stale" for a reference that became invalid. Components are named
+// as registered with ecs.RegisterComponent, or else by their Go type.
+func Dump(em *ecs.EntityManager) string {
+	entityIDs := em.Entities()
+	names := newEntityNames(em, entityIDs)
+
+	var b strings.Builder
+	for index, entityID := range entityIDs {
+		b.WriteString(entityHeader(em, entityID, index))
+		b.WriteByte('\n')
+
+		lines := make([]string, 0)
+		for componentType, component := range em.Components(entityID) {
+			lines = append(lines, "  "+componentName(componentType)+" "+componentValue(names.remap(component)))
+		}
+		slices.Sort(lines)
+
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.String()
+}
+
+// entityHeader returns the dump line of the entity at index in ID order: its number, whether it is
+// inactive and its sorted tags.
+func entityHeader(em *ecs.EntityManager, entityID ecs.EntityID, index int) string {
+	header := entityName(index)
+	if !em.Active(entityID) {
+		header += " inactive"
+	}
+	if tags := sortedTags(em, entityID); len(tags) > 0 {
+		header += " tags=" + strings.Join(tags, ",")
+	}
+
+	return header
+}
+
+func componentName(componentType reflect.Type) string {
+	if name, err := ecs.ComponentName(componentType); err == nil {
+		return name
+	}
+
+	return componentType.String()
+}
+
+// componentValue returns the component as JSON, or formatted with %+v if it cannot be encoded.
+func componentValue(component any) string {
+	data, err := json.Marshal(component)
+	if err != nil {
+		return fmt.Sprintf("%+v", reflect.Indirect(reflect.ValueOf(component)))
+	}
+
+	return string(data)
+}
+
+// Diff compares two dumps component by component and returns the differences, one per line, or "" if they
+// are equal.
+func Diff(got, want string) string {
+	gotEntities, wantEntities := parseDump(got), parseDump(want)
+
+	var diffs []string
+	for index := range max(len(gotEntities), len(wantEntities)) {
+		switch {
+		case index >= len(gotEntities):
+			diffs = append(diffs, fmt.Sprintf("%s: missing entity", wantEntities[index].header))
+			continue
+		case index >= len(wantEntities):
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected entity", gotEntities[index].header))
+			continue
+		}
+
+		g, w := gotEntities[index], wantEntities[index]
+		if g.header != w.header {
+			diffs = append(diffs, fmt.Sprintf("%s: got %q, want %q", entityName(index), g.header, w.header))
+		}
+
+		for _, name := range sortedUnion(g.components, w.components) {
+			gotValue, inGot := g.components[name]
+			wantValue, inWant := w.components[name]
+			if !inGot || !inWant || gotValue != wantValue {
+				diffs = append(diffs, componentDiff(entityName(index)+" "+name, gotValue, inGot, wantValue, inWant))
+			}
+		}
+	}
+
+	return strings.Join(diffs, "\n")
+}
+
+func entityName(index int) string {
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// componentDiff describes a component that differs, is missing from got or is unexpected in got.
+func componentDiff(prefix, gotValue string, inGot bool, wantValue string, inWant bool) string {
+	switch {
+	case !inGot:
+		return fmt.Sprintf("%s: missing component, want %s", prefix, wantValue)
+	case !inWant:
+		return fmt.Sprintf("%s: unexpected component %s", prefix, gotValue)
+	default:
+		return fmt.Sprintf("%s:\n    got  %s\n    want %s", prefix, gotValue, wantValue)
+	}
+}
+
+type dumpedEntity struct {
+	header     string
+	components map[string]string
+}
+
+func parseDump(dump string) []dumpedEntity {
+	var entities []dumpedEntity
+	for line := range strings.Lines(dump) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			continue
+		}
+
+		if component, ok := strings.CutPrefix(line, "  "); ok && len(entities) > 0 {
+			name, value, _ := strings.Cut(component, " ")
+			entities[len(entities)-1].components[name] = value
+			continue
+		}
+
+		entities = append(entities, dumpedEntity{header: line, components: make(map[string]string)})
+	}
+
+	return entities
+}
+
+func sortedUnion[V any](a, b map[string]V) []string {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// AssertGolden compares the Dump of em with the golden file at path and fails the test with the Diff
+// if they differ. With ECSTEST_UPDATE=1 in the environment, it writes the golden file instead.
+func AssertGolden(t testing.TB, em *ecs.EntityManager, path string) {
+	t.Helper()
+
+	got := Dump(em)
+	if os.Getenv(UpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("ecstest.AssertGolden: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("ecstest.AssertGolden: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ecstest.AssertGolden: %v (run with %s=1 to create it)", err, UpdateEnv)
+	}
+
+	if diff := Diff(got, string(want)); diff != "" {
+		t.Errorf("ecstest.AssertGolden: world differs from %s (run with %s=1 to update it):\n%s", path, UpdateEnv, diff)
+	}
+}
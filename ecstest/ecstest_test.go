@@ -0,0 +1,142 @@
+package ecstest_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/ecstest"
+	"github.com/stretchr/testify/assert"
+)
+
+type Health struct {
+	Current int
+	Max     int
+}
+
+type Poisoned struct {
+	Damage int
+}
+
+func init() {
+	ecs.RegisterComponent[Health]("ecstest_test.Health")
+	ecs.RegisterComponent[Poisoned]("ecstest_test.Poisoned")
+}
+
+func poison(s *ecs.FuncSystem) error {
+	em := s.EntityManager()
+	for _, entityID := range slices.Collect(ecs.Query[Poisoned](em)) {
+		health := ecs.MustGetComponent[Health](em, entityID)
+		health.Current -= ecs.MustGetComponent[Poisoned](em, entityID).Damage
+		if health.Current <= 0 {
+			em.Remove(entityID)
+		}
+	}
+
+	return nil
+}
+
+func TestWorld(t *testing.T) {
+	w := ecstest.NewWorld(t, ecs.NewSystem(ecs.SystemOptions{ID: ecs.NextSystemID()}, poison))
+	em := w.EntityManager()
+
+	for _, damage := range []int{1, 4, 0} {
+		entityID := em.NewEntity()
+		*ecs.AddComponent[Health](em, entityID) = Health{Current: 10, Max: 10}
+		if damage > 0 {
+			ecs.AddComponent[Poisoned](em, entityID).Damage = damage
+		}
+	}
+	em.Tag(em.Entities()[2], "boss")
+
+	w.Step(3)
+	assert.Equal(t, uint64(3), w.Game().Time().Tick())
+	ecstest.AssertGolden(t, em, "testdata/poison.golden")
+
+	diff := ecstest.Diff(ecstest.Dump(em), "#1\n  ecstest_test.Health {\"Current\":10,\"Max\":10}\n")
+	lines := strings.Split(diff, "\n")
+	assert.Equal(t, "#1 ecstest_test.Health:", lines[0])
+	assert.Contains(t, diff, "#1 ecstest_test.Poisoned: unexpected component {\"Damage\":1}")
+	assert.Contains(t, diff, "#2 tags=boss: unexpected entity")
+	assert.Empty(t, ecstest.Diff(ecstest.Dump(em), ecstest.Dump(em)))
+}
+
+type Homing struct {
+	Target ecs.Ref
+	Owner  ecs.EntityID
+	Path   []ecs.EntityID
+}
+
+func TestDumpEntityIDs(t *testing.T) {
+	spawn := func(offset int) *ecs.EntityManager {
+		em := ecs.NewEntityManager()
+		for range offset {
+			em.Remove(em.NewEntity())
+		}
+
+		owner, target := em.NewEntity(), em.NewEntity()
+		gone := em.NewEntity()
+		em.Remove(gone)
+		*ecs.AddComponent[Homing](em, owner) = Homing{Target: em.Ref(target), Owner: owner, Path: []ecs.EntityID{target, gone}}
+		return em
+	}
+
+	dump := ecstest.Dump(spawn(0))
+	assert.Contains(t, dump, `{"Target":"#2","Owner":"#1","Path":["#2","removed"]}`)
+	assert.Equal(t, dump, ecstest.Dump(spawn(5)), "dumps do not depend on the IDs")
+}
+
+type cooldown struct {
+	Ready     bool
+	remaining int
+}
+
+func TestDiffWorlds(t *testing.T) {
+	spawn := func(remaining int) (*ecs.EntityManager, ecs.EntityID) {
+		em := ecs.NewEntityManager()
+		entityID := em.NewEntity()
+		*ecs.AddComponent[Health](em, entityID) = Health{Current: 5, Max: 10}
+		*ecs.AddComponent[cooldown](em, entityID) = cooldown{remaining: remaining}
+		return em, entityID
+	}
+
+	a, aID := spawn(3)
+	b, bID := spawn(3)
+	assert.True(t, ecstest.AssertEntityEqual(t, a, aID, b, bID))
+	assert.Empty(t, ecstest.DiffWorlds(a, b))
+
+	c, cID := spawn(2)
+	ecs.MustGetComponent[Health](c, cID).Current = 4
+	ecs.AddComponent[Poisoned](c, cID).Damage = 1
+	c.Tag(cID, "boss")
+	c.NewEntity()
+
+	diff := ecstest.DiffEntities(a, aID, c, cID)
+	assert.Equal(t, strings.Join([]string{
+		"tags: got [], want [boss]",
+		"ecstest_test.Health:\n    got  {\"Current\":5,\"Max\":10}\n    want {\"Current\":4,\"Max\":10}",
+		"ecstest_test.Poisoned: missing component, want {\"Damage\":1}",
+		"ecstest_test.cooldown:\n    got  {Ready:false remaining:3}\n    want {Ready:false remaining:2}",
+	}, "\n"), diff)
+
+	diff = ecstest.DiffWorlds(a, c)
+	assert.Contains(t, diff, "#1 ecstest_test.Poisoned: missing component")
+	assert.Contains(t, diff, "#2: missing entity")
+
+	recorder := &recordingTB{TB: t}
+	assert.False(t, ecstest.AssertEntityEqual(recorder, a, aID, c, cID))
+	assert.Contains(t, recorder.errors, "ecstest_test.Poisoned: missing component")
+}
+
+// recordingTB records the errors of a test instead of failing it.
+type recordingTB struct {
+	testing.TB
+
+	errors string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors += fmt.Sprintf(format, args...)
+}
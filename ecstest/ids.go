@@ -0,0 +1,186 @@
+package ecstest
+
+import (
+	"reflect"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var (
+	entityIDType = reflect.TypeFor[ecs.EntityID]()
+	refType      = reflect.TypeFor[ecs.Ref]()
+	anyType      = reflect.TypeFor[any]()
+)
+
+// entityNames names the entities of a dump, so that the entity IDs and references stored in components
+// are dumped as the entity numbers rather than as IDs.
+type entityNames struct {
+	em    *ecs.EntityManager
+	names map[ecs.EntityID]string
+}
+
+func newEntityNames(em *ecs.EntityManager, entityIDs []ecs.EntityID) *entityNames {
+	names := make(map[ecs.EntityID]string, len(entityIDs))
+	for index, entityID := range entityIDs {
+		names[entityID] = entityName(index)
+	}
+
+	return &entityNames{em: em, names: names}
+}
+
+// entity returns the dump value of an entity ID: its number, 0 for ecs.UndefinedID, or "removed".
+func (n *entityNames) entity(entityID ecs.EntityID) any {
+	if entityID == ecs.UndefinedID {
+		return 0
+	}
+
+	if name, ok := n.names[entityID]; ok {
+		return name
+	}
+
+	return "removed"
+}
+
+// ref returns the dump value of a reference: the number of its entity, null for the zero Ref, or "stale".
+func (n *entityNames) ref(ref ecs.Ref) any {
+	if ref == (ecs.Ref{}) {
+		return nil
+	}
+
+	if entityID, ok := ref.Get(n.em); ok {
+		return n.entity(entityID)
+	}
+
+	return "stale"
+}
+
+// remap returns a copy of the component whose entity IDs and references are replaced by their dump values,
+// or the component itself if its type contains none.
+func (n *entityNames) remap(component any) any {
+	value := reflect.ValueOf(component).Elem()
+
+	dumpType, ok := remappedType(value.Type(), make(map[reflect.Type]bool))
+	if !ok {
+		return component
+	}
+
+	remapped := reflect.New(dumpType).Elem()
+	n.copy(remapped, value)
+
+	return remapped.Interface()
+}
+
+// remappedType returns t with the entity IDs and references replaced by any, keeping the exported fields
+// of structs and flattening the embedded ones like encoding/json, or false if t contains none.
+// Recursive types are not remapped below their first level.
+func remappedType(t reflect.Type, visiting map[reflect.Type]bool) (reflect.Type, bool) {
+	if t == entityIDType || t == refType {
+		return anyType, true
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if elem, ok := remappedType(t.Elem(), visiting); ok {
+			return reflect.PointerTo(elem), true
+		}
+	case reflect.Slice:
+		if elem, ok := remappedType(t.Elem(), visiting); ok {
+			return reflect.SliceOf(elem), true
+		}
+	case reflect.Array:
+		if elem, ok := remappedType(t.Elem(), visiting); ok {
+			return reflect.ArrayOf(t.Len(), elem), true
+		}
+	case reflect.Map:
+		if elem, ok := remappedType(t.Elem(), visiting); ok {
+			return reflect.MapOf(t.Key(), elem), true
+		}
+	case reflect.Struct:
+		if visiting[t] {
+			return t, false
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		var fields []reflect.StructField
+		if changed := appendRemappedFields(&fields, t, visiting); changed {
+			return reflect.StructOf(fields), true
+		}
+	}
+
+	return t, false
+}
+
+// appendRemappedFields appends the remapped exported fields of the struct type t to fields, with those of
+// its embedded structs, and reports whether any of them contains entity IDs or references.
+func appendRemappedFields(fields *[]reflect.StructField, t reflect.Type, visiting map[reflect.Type]bool) bool {
+	changed := false
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			changed = appendRemappedFields(fields, field.Type, visiting) || changed
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldType, ok := remappedType(field.Type, visiting)
+		changed = changed || ok
+		*fields = append(*fields, reflect.StructField{Name: field.Name, Type: fieldType, Tag: field.Tag})
+	}
+
+	return changed
+}
+
+// copy copies src to dst, a value of its remapped type, replacing the entity IDs and references.
+func (n *entityNames) copy(dst, src reflect.Value) {
+	switch {
+	case src.Type() == entityIDType:
+		if name := n.entity(ecs.EntityID(src.Uint())); name != nil {
+			dst.Set(reflect.ValueOf(name))
+		}
+		return
+	case src.Type() == refType:
+		if name := n.ref(src.Interface().(ecs.Ref)); name != nil {
+			dst.Set(reflect.ValueOf(name))
+		}
+		return
+	case src.Type() == dst.Type():
+		dst.Set(src)
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if !src.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+			n.copy(dst.Elem(), src.Elem())
+		}
+	case reflect.Slice:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+			for i := range src.Len() {
+				n.copy(dst.Index(i), src.Index(i))
+			}
+		}
+	case reflect.Array:
+		for i := range src.Len() {
+			n.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if !src.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+			for iter := src.MapRange(); iter.Next(); {
+				elem := reflect.New(dst.Type().Elem()).Elem()
+				n.copy(elem, iter.Value())
+				dst.SetMapIndex(iter.Key(), elem)
+			}
+		}
+	case reflect.Struct:
+		for i := range dst.NumField() {
+			n.copy(dst.Field(i), src.FieldByName(dst.Type().Field(i).Name))
+		}
+	}
+}
@@ -5,105 +5,322 @@ import (
 	"iter"
 	"reflect"
 	"slices"
+	"sync"
+	"sync/atomic"
 )
 
 type EntityID = ID
 
 type EntityManager struct {
-	entities                  map[EntityID]struct{}
-	componentContainers       map[reflect.Type]*ComponentContainer
-	entityComponentSignatures map[EntityID]map[reflect.Type]struct{}
+	entities            map[EntityID]struct{}
+	componentContainers map[reflect.Type]componentStorage
+
+	// componentBits maps each component type with a storage to its bit in entityMasks,
+	// and componentTypes maps the bits back to the types.
+	componentBits  map[reflect.Type]int
+	componentTypes []reflect.Type
+	entityMasks    map[EntityID]componentMask
+
+	tags map[Tag]*tagSet
+
+	// relations stores the relations between entities by relation type, see Relate.
+	relations map[reflect.Type]*relationStore
+
+	// generations counts the times each entity was recycled by a Pool, see Ref.
+	// Entities that were never recycled have no entry.
+	generations map[EntityID]uint32
+
+	// spatialIndexes index the positions of components by type, see QueryWithinRadius.
+	spatialIndexes map[reflect.Type]*spatialIndex
+
+	// inactive holds the entities hidden from queries by SetActive.
+	inactive map[EntityID]struct{}
+
+	commands *CommandBuffer
+
+	// storageVersion is incremented whenever a component storage is created, invalidating queryPlans.
+	storageVersion uint64
+	queryPlans     map[queryKey]*queryPlan
+	queryMetrics   map[string]*QueryMetrics
+
+	queryMetricsByPC map[uintptr]*QueryMetrics
+	// queryLock guards queryPlans and the query metrics of a concurrent EntityManager, which the queries
+	// inside Read and the owner's reads update at the same time.
+	queryLock sync.Mutex
+
+	// structuralLocks counts the running ParallelEach calls, during which structural changes panic.
+	structuralLocks atomic.Int32
+
+	logger *structuralLogger
+	strict bool
+	// iterating counts the queries being iterated in strict mode, during which structural changes panic.
+	iterating atomic.Int32
+
+	// structure is the structural lock of a concurrent EntityManager, held structureDepth times by the
+	// owner goroutine, see NewConcurrentEntityManager.
+	structure      *sync.RWMutex
+	structureDepth int
+
+	watches []*Watch
+	// pools are the Pools of the EntityManager, refilled by BaseWorld.Reset.
+	pools []*Pool
 }
 
 func NewEntityManager() *EntityManager {
 	return &EntityManager{
-		entities:                  make(map[EntityID]struct{}),
-		componentContainers:       make(map[reflect.Type]*ComponentContainer),
-		entityComponentSignatures: make(map[EntityID]map[reflect.Type]struct{}),
+		entities:            make(map[EntityID]struct{}),
+		componentContainers: make(map[reflect.Type]componentStorage),
+		componentBits:       make(map[reflect.Type]int),
+		entityMasks:         make(map[EntityID]componentMask),
+		queryPlans:          make(map[queryKey]*queryPlan),
+		commands:            NewCommandBuffer(),
 	}
 }
 
+// Commands returns the command buffer of the EntityManager, used to defer structural changes
+// while iterating. The SystemManager flushes it after each system's Update.
+func (em *EntityManager) Commands() *CommandBuffer {
+	return em.commands
+}
+
 func (em *EntityManager) NewEntity() EntityID {
+	defer em.beginStructural("EntityManager.NewEntity")()
+
 	id := NextID()
-	em.entities[id] = struct{}{}
-	em.entityComponentSignatures[id] = make(map[reflect.Type]struct{})
+	em.createEntity(id)
 
 	return id
 }
 
-func (em *EntityManager) HasComponent(entityID EntityID, componentType any) bool {
+// createEntity creates an entity with an ID allocated by NextID.
+func (em *EntityManager) createEntity(id EntityID) {
+	em.entities[id] = struct{}{}
+	em.entityMasks[id] = nil
+	em.logEntity("entity created", id)
+}
+
+// SetActive enables or disables an entity. Inactive entities keep their components,
+// which GetComponent still returns, but are skipped by all queries unless the query
+// includes them explicitly with IncludeInactive. Entities are active when created.
+func (em *EntityManager) SetActive(entityID EntityID, active bool) {
+	defer em.beginStructural("EntityManager.SetActive")()
+
+	if !em.checkExists("EntityManager.SetActive", entityID) {
+		return
+	}
+
+	if active {
+		delete(em.inactive, entityID)
+		return
+	}
+
+	if em.inactive == nil {
+		em.inactive = make(map[EntityID]struct{})
+	}
+	em.inactive[entityID] = struct{}{}
+}
+
+// Active reports whether the entity is active. Removed entities are not active.
+func (em *EntityManager) Active(entityID EntityID) bool {
 	if _, exists := em.entities[entityID]; !exists {
 		return false
 	}
 
-	if _, exists := em.entityComponentSignatures[entityID][reflect.TypeOf(componentType)]; !exists {
+	_, inactive := em.inactive[entityID]
+	return !inactive
+}
+
+// Exists reports whether the entity has been created and not removed, whether it is active or not.
+func (em *EntityManager) Exists(entityID EntityID) bool {
+	_, exists := em.entities[entityID]
+	return exists
+}
+
+// Entities returns the IDs of all entities, active or not, in ascending order.
+func (em *EntityManager) Entities() []EntityID {
+	entityIDs := make([]EntityID, 0, len(em.entities))
+	for entityID := range em.entities {
+		entityIDs = append(entityIDs, entityID)
+	}
+	slices.Sort(entityIDs)
+
+	return entityIDs
+}
+
+// Components returns the component types of the entity and pointers to its components, for tools that
+// handle components without knowing their types. Components registered with RegisterComponent come first,
+// by ID, followed by the others in the order their storages were created.
+func (em *EntityManager) Components(entityID EntityID) iter.Seq2[reflect.Type, any] {
+	return func(yield func(reflect.Type, any) bool) {
+		for componentType := range em.entityComponentTypes(entityID) {
+			component, ok := em.componentContainers[componentType].Get(entityID)
+			if ok && !yield(componentType, component) {
+				return
+			}
+		}
+	}
+}
+
+// hidden reports whether the entity is inactive, without checking that it exists.
+func (em *EntityManager) hidden(entityID EntityID) bool {
+	if len(em.inactive) == 0 {
 		return false
 	}
 
-	return true
+	_, inactive := em.inactive[entityID]
+	return inactive
 }
 
-func (em *EntityManager) Remove(entityID EntityID) {
+func (em *EntityManager) HasComponent(entityID EntityID, componentType any) bool {
 	if _, exists := em.entities[entityID]; !exists {
+		return false
+	}
+
+	return em.hasComponentType(entityID, reflect.TypeOf(componentType))
+}
+
+func (em *EntityManager) Remove(entityID EntityID) {
+	defer em.beginStructural("EntityManager.Remove")()
+
+	if !em.checkExists("EntityManager.Remove", entityID) {
 		return
 	}
 
-	for componentType := range em.entityComponentSignatures[entityID] {
-		if container, exists := em.componentContainers[componentType]; exists {
-			container.Remove(entityID)
-		}
+	for bit := range em.entityMasks[entityID].all() {
+		em.componentContainers[em.componentTypes[bit]].Remove(entityID)
 	}
 
-	delete(em.entityComponentSignatures, entityID)
+	delete(em.entityMasks, entityID)
+	delete(em.inactive, entityID)
+	em.untagAll(entityID)
+	em.removeRelations(entityID)
+	delete(em.generations, entityID)
 	delete(em.entities, entityID)
+	em.logEntity("entity removed", entityID)
 }
 
 func (em *EntityManager) RemoveComponent(entityID EntityID, componentType any) {
+	em.removeComponent(entityID, reflect.TypeOf(componentType))
+}
+
+// AddComponentByType is AddComponent for a component type known only at run time, e.g. by serialization,
+// scripting or editors: it adds a component of componentType, a component type rather than a pointer to one,
+// to the entity and returns a pointer to it, or returns the existing component. It returns nil if the entity
+// does not exist.
+func (em *EntityManager) AddComponentByType(entityID EntityID, componentType reflect.Type) any {
+	defer em.beginStructural("EntityManager.AddComponentByType")()
+
+	if !em.checkExists("EntityManager.AddComponentByType", entityID) {
+		return nil
+	}
+
+	if em.hasComponentType(entityID, componentType) {
+		component, _ := em.componentContainers[componentType].Get(entityID)
+		return component
+	}
+
+	return em.addComponent(entityID, componentType, em.storageOf(componentType))
+}
+
+// GetComponentByType is GetComponent for a component type known only at run time: it returns a pointer
+// to the entity's component of componentType, or false if the entity does not have one.
+func (em *EntityManager) GetComponentByType(entityID EntityID, componentType reflect.Type) (any, bool) {
 	if _, exists := em.entities[entityID]; !exists {
-		return
+		return nil, false
 	}
 
-	refType := reflect.TypeOf(componentType)
+	container, exists := em.componentContainers[componentType]
+	if !exists {
+		return nil, false
+	}
+
+	return container.Get(entityID)
+}
+
+// RemoveComponentByType removes the entity's component of componentType, if it has one.
+func (em *EntityManager) RemoveComponentByType(entityID EntityID, componentType reflect.Type) {
+	em.removeComponent(entityID, componentType)
+}
+
+// ComponentTypes returns the types of the entity's components, in the order of Components,
+// or nil if the entity does not exist.
+func (em *EntityManager) ComponentTypes(entityID EntityID) []reflect.Type {
+	if _, exists := em.entities[entityID]; !exists {
+		return nil
+	}
+
+	return slices.Collect(em.entityComponentTypes(entityID))
+}
 
-	if _, exists := em.entityComponentSignatures[entityID][refType]; !exists {
+func (em *EntityManager) removeComponent(entityID EntityID, refType reflect.Type) {
+	defer em.beginStructural("RemoveComponent")()
+
+	if !em.checkExists("RemoveComponent", entityID) {
 		return
 	}
 
-	container, exists := em.componentContainers[refType]
-	if !exists {
+	if !em.hasComponentType(entityID, refType) {
 		return
 	}
 
-	container.Remove(entityID)
-	delete(em.entityComponentSignatures[entityID], refType)
+	em.componentContainers[refType].Remove(entityID)
+	em.clearComponentBit(entityID, refType)
+	em.logComponent("component removed", entityID, refType)
 }
 
 // Query returns a sequence of EntityIDs that match the specified component types.
 func (em *EntityManager) Query(componentTypes ...any) iter.Seq[EntityID] {
+	var buf [maxCachedQueryTypes]reflect.Type
+	types := buf[:0]
+	for _, componentType := range componentTypes {
+		types = append(types, reflect.TypeOf(componentType))
+	}
+
+	return em.query(types...)
+}
+
+// query returns a sequence of EntityIDs that have all the component types.
+// Apart from the returned iterator it does not allocate once the query plan is cached,
+// and ranging over the iterator is allocation-free.
+func (em *EntityManager) query(componentTypes ...reflect.Type) iter.Seq[EntityID] {
+	return em.queryEntities(false, componentTypes)
+}
+
+// queryEntities is query with the option to include inactive entities.
+func (em *EntityManager) queryEntities(includeInactive bool, componentTypes []reflect.Type) iter.Seq[EntityID] {
+	return em.guardIteration(em.matchEntities(includeInactive, componentTypes))
+}
+
+// matchEntities returns the entities with all the component types, see queryEntities.
+func (em *EntityManager) matchEntities(includeInactive bool, componentTypes []reflect.Type) iter.Seq[EntityID] {
 	zeroIter := func(yield func(EntityID) bool) {}
 
 	if len(componentTypes) == 0 {
 		return zeroIter
 	}
 
-	// If only one component type is specified, return entities with that component
-	if len(componentTypes) == 1 {
-		componentContainer, exists := em.componentContainers[reflect.TypeOf(componentTypes[0])]
-		if !exists {
-			return zeroIter
-		}
-
-		return componentContainer.Entities()
+	// Pre-check: if any component type doesn't exist, return empty iterator
+	plan := em.queryContainers(componentTypes)
+	if !plan.complete {
+		return zeroIter
 	}
 
-	// Pre-check: if any component type doesn't exist, return empty iterator
-	containers := make([]*ComponentContainer, len(componentTypes))
-	for i, componentType := range componentTypes {
-		container, exists := em.componentContainers[reflect.TypeOf(componentType)]
-		if !exists {
-			return zeroIter
+	containers := plan.containers
+
+	// If only one component type is specified, return entities with that component
+	if len(containers) == 1 {
+		container := containers[0]
+		return func(yield func(EntityID) bool) {
+			for _, entityID := range container.ids() {
+				if !includeInactive && em.hidden(entityID) {
+					continue
+				}
+
+				if !yield(entityID) {
+					break
+				}
+			}
 		}
-		containers[i] = container
 	}
 
 	// Find the container with the smallest number of entities to start with
@@ -127,113 +344,220 @@ func (em *EntityManager) Query(componentTypes ...any) iter.Seq[EntityID] {
 		}
 	}
 
-	// Start with the smallest set and filter iteratively
+	// Start with the smallest set and test the other components with a single mask AND
 	smallestContainer := containers[smallestIdx]
-	otherContainers := make([]*ComponentContainer, 0, len(containers)-1)
-	for i, container := range containers {
-		if i != smallestIdx {
-			otherContainers = append(otherContainers, container)
-		}
-	}
+	mask := plan.mask
 
 	return func(yield func(EntityID) bool) {
-		for entityID := range smallestContainer.Entities() {
-			// Check if this entity exists in all other containers
-			hasAllComponents := true
-			for _, container := range otherContainers {
-				if _, exists := container.Get(entityID); !exists {
-					hasAllComponents = false
-					break
-				}
+		for _, entityID := range smallestContainer.ids() {
+			if !em.entityMasks[entityID].containsAll(mask) {
+				continue
 			}
 
-			if hasAllComponents {
-				if !yield(entityID) {
-					break
-				}
+			if !includeInactive && em.hidden(entityID) {
+				continue
+			}
+
+			if !yield(entityID) {
+				break
 			}
 		}
 	}
 }
 
 func (em *EntityManager) Teardown() {
+	defer em.beginStructural("EntityManager.Teardown")()
+
+	em.closeWatches()
+
 	for _, container := range em.componentContainers {
 		container.Teardown()
 	}
 
 	em.entities = nil
-	em.entityComponentSignatures = nil
+	em.entityMasks = nil
 	em.componentContainers = nil
+	em.componentBits = nil
+	em.componentTypes = nil
+	em.tags = nil
+	em.relations = nil
+	em.generations = nil
+	em.spatialIndexes = nil
+	em.inactive = nil
+	em.commands.Reset()
+	clear(em.queryPlans)
+	em.pools = nil
+	em.queryMetrics = nil
+	em.queryMetricsByPC = nil
 }
 
+// AddComponent adds a new component C to the entity and returns it, or nil if the entity does not exist.
+// If the entity already has C, the existing component is returned unchanged; AddOrGetComponent tells
+// the two cases apart, and SetComponent overwrites the component's value.
+//
+// In strict mode, see SetStrict, adding a component the entity already has panics instead.
 func AddComponent[C any](em *EntityManager, entityID EntityID) *C {
-	if _, exists := em.entities[entityID]; !exists {
-		return nil
+	component, added := AddOrGetComponent[C](em, entityID)
+	if !added && component != nil && em.strict {
+		panic(fmt.Sprintf("ecs.AddComponent: entity %d already has %s", entityID, reflect.TypeFor[C]()))
+	}
+
+	return component
+}
+
+// AddOrGetComponent returns the component C of the entity, adding it first if the entity does not
+// have one, and reports whether it was added. It returns nil and false if the entity does not exist.
+func AddOrGetComponent[C any](em *EntityManager, entityID EntityID) (*C, bool) {
+	defer em.beginStructural("AddComponent")()
+
+	if !em.checkExists("AddComponent", entityID) {
+		return nil, false
 	}
 
-	var zero C
 	// Check if the component type is already registered for this entity
-	componentType := reflect.TypeOf(zero)
-	if _, exists := em.entityComponentSignatures[entityID][componentType]; exists {
-		return MustGetComponent[C](em, entityID)
+	componentType := reflect.TypeFor[C]()
+	if em.hasComponentType(entityID, componentType) {
+		return MustGetComponent[C](em, entityID), false
 	}
 
+	return em.addComponent(entityID, componentType, componentContainer[C](em)).(*C), true
+}
+
+// addComponent adds a component of componentType, which the existing entity does not have, to its storage.
+func (em *EntityManager) addComponent(entityID EntityID, componentType reflect.Type, storage componentStorage) any {
+	em.checkRequired(entityID, componentType)
+
+	component := storage.Add(entityID)
+	em.setComponentBit(entityID, componentType)
+	em.logComponent("component added", entityID, componentType)
+	em.addRequired(entityID, componentType)
+
+	return component
+}
+
+// SetComponent sets the component C of the entity to value, adding the component if the entity does
+// not have one, and returns it. It returns nil if the entity does not exist.
+func SetComponent[C any](em *EntityManager, entityID EntityID, value C) *C {
+	component, _ := AddOrGetComponent[C](em, entityID)
+	if component != nil {
+		*component = value
+	}
+
+	return component
+}
+
+// componentContainer returns the container of component type C, creating it if needed.
+func componentContainer[C any](em *EntityManager) componentStorage {
+	componentType := reflect.TypeFor[C]()
+
 	container, exists := em.componentContainers[componentType]
 	if !exists {
+		container = newComponentContainer[C]()
+		em.registerStorage(componentType, container)
+	}
+
+	return container
+}
+
+// newComponentContainer creates the default storage of component type C.
+func newComponentContainer[C any]() *ComponentContainer {
+	container := NewComponentContainer(func() any {
+		var c C
+		return &c
+	})
+	container.copier = typedCopier[C]{}
+
+	return container
+}
+
+// storageOf returns the storage of a component type known only at run time, creating it if needed.
+// Components of unregistered types stored this way are copied by reflection in snapshots.
+func (em *EntityManager) storageOf(componentType reflect.Type) componentStorage {
+	container, exists := em.componentContainers[componentType]
+	if exists {
+		return container
+	}
+
+	if registered, ok := registeredComponentOf(componentType); ok {
+		container = registered.newStorage()
+	} else {
 		container = NewComponentContainer(func() any {
-			var c C
-			return &c
+			return reflect.New(componentType).Interface()
 		})
-		em.componentContainers[componentType] = container
 	}
+	em.registerStorage(componentType, container)
 
-	component := container.Add(entityID)
-	em.entityComponentSignatures[entityID][componentType] = struct{}{}
-
-	return component.(*C)
+	return container
 }
 
 func RemoveComponent[C any](em *EntityManager, entityID EntityID) {
-	var zero C
-	em.RemoveComponent(entityID, reflect.TypeOf(zero))
+	em.removeComponent(entityID, reflect.TypeFor[C]())
 }
 
 func Query[C any](em *EntityManager) iter.Seq[EntityID] {
-	var zero C
-	return em.Query(zero)
+	return em.query(reflect.TypeFor[C]())
 }
 
 func Query2[C1, C2 any](em *EntityManager) iter.Seq[EntityID] {
-	var zero1 C1
-	var zero2 C2
-	return em.Query(zero1, zero2)
+	return em.query(reflect.TypeFor[C1](), reflect.TypeFor[C2]())
 }
 
 func Query3[C1, C2, C3 any](em *EntityManager) iter.Seq[EntityID] {
-	var zero1 C1
-	var zero2 C2
-	var zero3 C3
-	return em.Query(zero1, zero2, zero3)
+	return em.query(reflect.TypeFor[C1](), reflect.TypeFor[C2](), reflect.TypeFor[C3]())
 }
 
 func HasComponent[C any](em *EntityManager, entityID EntityID) bool {
-	var zero C
-	return em.HasComponent(entityID, zero)
+	if _, exists := em.entities[entityID]; !exists {
+		return false
+	}
+
+	return em.hasComponentType(entityID, reflect.TypeFor[C]())
 }
 
 func GetComponent[C any](em *EntityManager, entityID EntityID) (*C, bool) {
-	var zero C
-	componentType := reflect.TypeOf(zero)
-
 	if _, exists := em.entities[entityID]; !exists {
 		return nil, false
 	}
 
-	if _, exists := em.entityComponentSignatures[entityID][componentType]; !exists {
-		return nil, false
+	return storedComponent[C](em, entityID)
+}
+
+// GetComponents2 returns the components A and B of the entity, looking the entity up once.
+// It returns false, and no components, unless the entity has both.
+func GetComponents2[A, B any](em *EntityManager, entityID EntityID) (*A, *B, bool) {
+	if _, exists := em.entities[entityID]; !exists {
+		return nil, nil, false
 	}
 
-	container, exists := em.componentContainers[componentType]
+	a, okA := storedComponent[A](em, entityID)
+	b, okB := storedComponent[B](em, entityID)
+	if !okA || !okB {
+		return nil, nil, false
+	}
+
+	return a, b, true
+}
+
+// GetComponents3 returns the components A, B and C of the entity, looking the entity up once.
+// It returns false, and no components, unless the entity has all three.
+func GetComponents3[A, B, C any](em *EntityManager, entityID EntityID) (*A, *B, *C, bool) {
+	if _, exists := em.entities[entityID]; !exists {
+		return nil, nil, nil, false
+	}
+
+	a, okA := storedComponent[A](em, entityID)
+	b, okB := storedComponent[B](em, entityID)
+	c, okC := storedComponent[C](em, entityID)
+	if !okA || !okB || !okC {
+		return nil, nil, nil, false
+	}
+
+	return a, b, c, true
+}
+
+// storedComponent returns the component C of an existing entity.
+func storedComponent[C any](em *EntityManager, entityID EntityID) (*C, bool) {
+	container, exists := em.componentContainers[reflect.TypeFor[C]()]
 	if !exists {
 		return nil, false
 	}
@@ -265,7 +589,12 @@ func First(iterator iter.Seq[EntityID]) (EntityID, bool) {
 }
 
 func Count(it iter.Seq[EntityID]) int {
-	return len(slices.Collect(it))
+	count := 0
+	for range it {
+		count++
+	}
+
+	return count
 }
 
 func evaluateFilter[C any](em *EntityManager, entityID EntityID, filter Filter[C]) bool {
@@ -287,9 +616,16 @@ func QueryWith[C any](em *EntityManager, filter Filter[C]) iter.Seq[EntityID] {
 		return Query[C](em)
 	}
 
+	record := em.recordFilter()
+
 	return func(yield func(EntityID) bool) {
 		for entityID := range Query[C](em) {
-			if evaluateFilter(em, entityID, filter) {
+			accepted := evaluateFilter(em, entityID, filter)
+			if record != nil {
+				record(accepted)
+			}
+
+			if accepted {
 				if !yield(entityID) {
 					break
 				}
@@ -304,9 +640,16 @@ func QueryWith2[C1, C2 any](em *EntityManager, filter1 Filter[C1], filter2 Filte
 		return Query2[C1, C2](em)
 	}
 
+	record := em.recordFilter()
+
 	return func(yield func(EntityID) bool) {
 		for entityID := range Query2[C1, C2](em) {
-			if evaluateFilter(em, entityID, filter1) && evaluateFilter(em, entityID, filter2) {
+			accepted := evaluateFilter(em, entityID, filter1) && evaluateFilter(em, entityID, filter2)
+			if record != nil {
+				record(accepted)
+			}
+
+			if accepted {
 				if !yield(entityID) {
 					break
 				}
@@ -321,11 +664,18 @@ func QueryWith3[C1, C2, C3 any](em *EntityManager, filter1 Filter[C1], filter2 F
 		return Query3[C1, C2, C3](em)
 	}
 
+	record := em.recordFilter()
+
 	return func(yield func(EntityID) bool) {
 		for entityID := range Query3[C1, C2, C3](em) {
-			if evaluateFilter(em, entityID, filter1) &&
+			accepted := evaluateFilter(em, entityID, filter1) &&
 				evaluateFilter(em, entityID, filter2) &&
-				evaluateFilter(em, entityID, filter3) {
+				evaluateFilter(em, entityID, filter3)
+			if record != nil {
+				record(accepted)
+			}
+
+			if accepted {
 				if !yield(entityID) {
 					break
 				}
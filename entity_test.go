@@ -1,6 +1,7 @@
 package ecs_test
 
 import (
+	"reflect"
 	"slices"
 	"testing"
 
@@ -81,6 +82,114 @@ func TestEntityCreation(t *testing.T) {
 	assert.NotEqual(t, empty, ecs.UndefinedID)
 }
 
+func TestEntitiesAndComponents(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	player := NewPlayerEntity(t, em)
+	camera := NewCameraEntity(t, em)
+	empty := NewEmptyEntity(t, em)
+
+	em.SetActive(camera, false)
+	assert.Equal(t, []ecs.EntityID{player, camera, empty}, em.Entities())
+
+	ecs.AddComponent[CameraComponent](em, player)
+	var types []reflect.Type
+	for componentType, component := range em.Components(player) {
+		types = append(types, componentType)
+		assert.Equal(t, reflect.PointerTo(componentType), reflect.TypeOf(component))
+	}
+	assert.Equal(t, []reflect.Type{reflect.TypeFor[TransformComponent](), reflect.TypeFor[CameraComponent]()}, types)
+}
+
+func TestGetComponents(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	player := NewPlayerEntity(t, em)
+	camera := NewCameraEntity(t, em)
+	ecs.MustGetComponent[CameraComponent](em, camera).Zoom = 2
+
+	transform, cam, ok := ecs.GetComponents2[TransformComponent, CameraComponent](em, camera)
+	assert.True(t, ok)
+	assert.Same(t, ecs.MustGetComponent[TransformComponent](em, camera), transform)
+	assert.Equal(t, 2.0, cam.Zoom)
+
+	transform, cam, ok = ecs.GetComponents2[TransformComponent, CameraComponent](em, player)
+	assert.False(t, ok, "the player has no camera")
+	assert.Nil(t, transform)
+	assert.Nil(t, cam)
+
+	_, _, _, ok = ecs.GetComponents3[TransformComponent, CameraComponent, NameComponent](em, camera)
+	assert.False(t, ok, "NameComponent has no storage")
+
+	ecs.AddComponent[NameComponent](em, camera).Name = "main"
+	_, _, name, ok := ecs.GetComponents3[TransformComponent, CameraComponent, NameComponent](em, camera)
+	assert.True(t, ok)
+	assert.Equal(t, "main", name.Name)
+
+	em.Remove(camera)
+	_, _, ok = ecs.GetComponents2[TransformComponent, CameraComponent](em, camera)
+	assert.False(t, ok)
+}
+
+func TestAddOrSetComponent(t *testing.T) {
+	em := ecs.NewEntityManager()
+	entityID := em.NewEntity()
+
+	camera, added := ecs.AddOrGetComponent[CameraComponent](em, entityID)
+	assert.True(t, added)
+	camera.Zoom = 3
+
+	existing, added := ecs.AddOrGetComponent[CameraComponent](em, entityID)
+	assert.False(t, added)
+	assert.Same(t, camera, existing)
+	assert.Same(t, camera, ecs.AddComponent[CameraComponent](em, entityID), "AddComponent returns the existing component")
+	assert.Equal(t, 3.0, camera.Zoom)
+
+	assert.Same(t, camera, ecs.SetComponent(em, entityID, CameraComponent{Zoom: 0.5}))
+	assert.Equal(t, 0.5, camera.Zoom)
+
+	transform := ecs.SetComponent(em, entityID, TransformComponent{Rotation: 1})
+	assert.Equal(t, 1.0, ecs.MustGetComponent[TransformComponent](em, entityID).Rotation)
+	assert.Same(t, transform, ecs.MustGetComponent[TransformComponent](em, entityID))
+
+	em.Remove(entityID)
+	assert.Nil(t, ecs.SetComponent(em, entityID, CameraComponent{}))
+	component, added := ecs.AddOrGetComponent[CameraComponent](em, entityID)
+	assert.Nil(t, component)
+	assert.False(t, added)
+}
+
+func TestComponentByType(t *testing.T) {
+	em := ecs.NewEntityManager()
+	entityID := em.NewEntity()
+
+	cameraType := reflect.TypeFor[CameraComponent]()
+	camera, ok := em.AddComponentByType(entityID, cameraType).(*CameraComponent)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, camera.Zoom, "the component is initialized")
+	assert.Same(t, camera, ecs.MustGetComponent[CameraComponent](em, entityID))
+	assert.Same(t, camera, em.AddComponentByType(entityID, cameraType), "the existing component is returned")
+	assert.Equal(t, 1, ecs.Count(ecs.Query[CameraComponent](em)))
+
+	nameType := reflect.TypeFor[NameComponent]()
+	em.AddComponentByType(entityID, nameType).(*NameComponent).Name = "dynamic"
+	assert.Equal(t, "dynamic", ecs.MustGetComponent[NameComponent](em, entityID).Name, "generic access sees storages created at run time")
+
+	component, ok := em.GetComponentByType(entityID, nameType)
+	assert.True(t, ok)
+	assert.Equal(t, &NameComponent{Name: "dynamic"}, component)
+	assert.Equal(t, []reflect.Type{cameraType, nameType}, em.ComponentTypes(entityID))
+
+	em.RemoveComponentByType(entityID, cameraType)
+	_, ok = em.GetComponentByType(entityID, cameraType)
+	assert.False(t, ok)
+	assert.Equal(t, []reflect.Type{nameType}, em.ComponentTypes(entityID))
+
+	em.Remove(entityID)
+	assert.Nil(t, em.AddComponentByType(entityID, cameraType))
+	assert.Nil(t, em.ComponentTypes(entityID))
+}
+
 func BenchmarkQueryEntities(b *testing.B) {
 	em := ecs.NewEntityManager()
 
@@ -150,4 +259,24 @@ func BenchmarkQueryEntities(b *testing.B) {
 			}
 		}
 	})
+
+	b.Run("Query2 + GetComponents2", func(b *testing.B) {
+		for b.Loop() {
+			for entityID := range ecs.Query2[TransformComponent, CameraComponent](em) {
+				if _, _, ok := ecs.GetComponents2[TransformComponent, CameraComponent](em, entityID); !ok {
+					b.Fatalf("Expected components for entity %d", entityID)
+				}
+			}
+		}
+	})
+}
+
+func TestQueryAfterTeardown(t *testing.T) {
+	em := ecs.NewEntityManager()
+	ecs.AddComponent[TransformComponent](em, em.NewEntity())
+	assert.Equal(t, 1, ecs.Count(ecs.Query[TransformComponent](em)))
+
+	em.Teardown()
+	assert.Zero(t, ecs.Count(ecs.Query[TransformComponent](em)))
+	assert.Zero(t, ecs.Count(ecs.Query2[TransformComponent, CameraComponent](em)))
 }
@@ -0,0 +1,73 @@
+package ecs
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// ErrorPolicy decides what a SystemManager does when a system's Update returns an error.
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyAbort stops the Update and returns the error, stopping the game unless an error world
+	// is registered, see Game.SetErrorWorld. It is the default policy.
+	ErrorPolicyAbort ErrorPolicy = iota
+	// ErrorPolicyContinue logs the error and goes on with the next system, e.g. for cosmetic systems
+	// whose failure should not crash the game loop.
+	ErrorPolicyContinue
+	// ErrorPolicyDisable logs the error and disables the failing system, see BaseSystem.SetEnabled,
+	// and goes on with the next system.
+	ErrorPolicyDisable
+)
+
+// SystemErrorHandler is called for every error returned by the Update of a system.
+type SystemErrorHandler func(system System, err error)
+
+// SetErrorPolicy sets what the SystemManager does when a system's Update returns an error.
+// The errors the policy continues after are logged to the game's logger, see Game.SetLogger,
+// or to slog.Default() if it has none.
+func (sm *SystemManager) SetErrorPolicy(policy ErrorPolicy) {
+	sm.errorPolicy = policy
+}
+
+// ErrorPolicy returns the policy set with SetErrorPolicy, ErrorPolicyAbort by default.
+func (sm *SystemManager) ErrorPolicy() ErrorPolicy {
+	return sm.errorPolicy
+}
+
+// OnSystemError sets the handler called with the errors returned by the systems of every world, whatever
+// the ErrorPolicy of their SystemManager, e.g. to report them to a crash service. The handler replaces the
+// log of the errors the policy continues after. A nil handler removes it.
+func (g *Game) OnSystemError(handler SystemErrorHandler) {
+	g.onSystemError = handler
+}
+
+// handleError applies the error policy to the error returned by the system's Update,
+// and returns the error if the Update must stop.
+func (sm *SystemManager) handleError(system System, err error) error {
+	var handler SystemErrorHandler
+	if sm.game != nil {
+		handler = sm.game.onSystemError
+	}
+
+	if handler != nil {
+		handler(system, err)
+	}
+
+	err = fmt.Errorf("error updating system %d: %w", system.ID(), err)
+
+	switch sm.errorPolicy {
+	case ErrorPolicyContinue:
+	case ErrorPolicyDisable:
+		system.baseSystem().SetEnabled(false)
+	default:
+		return err
+	}
+
+	if handler == nil {
+		errorLogger(sm.game).Error("system failed, continuing",
+			slog.String("system", describeSystem(system)), slog.Any("error", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package ecs_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorPolicy(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+
+	var failures []ecs.SystemID
+	game.OnSystemError(func(system ecs.System, err error) {
+		assert.ErrorIs(t, err, assert.AnError)
+		failures = append(failures, system.ID())
+	})
+
+	var events []string
+	flaky := ecs.NewSystem(ecs.SystemOptions{Priority: 0}, func(*ecs.FuncSystem) error {
+		events = append(events, "flaky")
+		return assert.AnError
+	})
+	next := ecs.NewSystem(ecs.SystemOptions{Priority: 1}, func(*ecs.FuncSystem) error {
+		events = append(events, "next")
+		return nil
+	})
+	require.NoError(t, sm.Add(flaky, next))
+
+	require.ErrorIs(t, sm.Update(), assert.AnError)
+	assert.Equal(t, []string{"flaky"}, events, "errors abort the update by default")
+
+	sm.SetErrorPolicy(ecs.ErrorPolicyContinue)
+	events = nil
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"flaky", "next"}, events)
+
+	sm.SetErrorPolicy(ecs.ErrorPolicyDisable)
+	events = nil
+	require.NoError(t, sm.Update())
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"flaky", "next", "next"}, events, "failing systems are disabled")
+	assert.False(t, flaky.Enabled())
+	assert.Equal(t, []ecs.SystemID{flaky.ID(), flaky.ID(), flaky.ID()}, failures)
+
+	flaky.SetEnabled(true)
+	events = nil
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"flaky", "next"}, events)
+}
+
+func TestErrorPolicyLogsToGameLogger(t *testing.T) {
+	var buf bytes.Buffer
+	game := ecs.NewGame(&ecs.GameConfig{})
+	game.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+	sm.SetErrorPolicy(ecs.ErrorPolicyContinue)
+	require.NoError(t, sm.Add(ecs.NewSystem(ecs.SystemOptions{}, func(*ecs.FuncSystem) error {
+		return assert.AnError
+	})))
+
+	require.NoError(t, sm.Update())
+	assert.Contains(t, buf.String(), "system failed, continuing")
+	assert.Contains(t, buf.String(), assert.AnError.Error())
+}
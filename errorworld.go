@@ -0,0 +1,143 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// ErrWorldPanic wraps a panic recovered from a world's Update when an error world is registered.
+var ErrWorldPanic = errors.New("world panicked")
+
+// ErrorWorldFunc creates the world the Game switches to when the active world fails.
+// failed is the world whose Update returned the error; it has already been torn down.
+type ErrorWorldFunc func(failed World, err error) World
+
+// SetErrorWorld registers the factory of the world shown when the active world's Update returns an
+// error or panics. Instead of stopping the game, the error is logged to the game's logger, or to
+// slog.Default() if it has none, every world on the stack is torn down and the error world becomes
+// the only active world. If the error world fails too, Update returns the error. Pass nil to let
+// errors stop the game again.
+//
+//	g.SetErrorWorld(ecs.DefaultErrorWorld)
+func (g *Game) SetErrorWorld(fn ErrorWorldFunc) {
+	g.errorWorld = fn
+}
+
+// updateWorld updates the world, turning panics into errors if an error world is registered.
+func (g *Game) updateWorld(world World) (err error) {
+	if g.errorWorld != nil && world != g.failedOver {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%w: %v\n%s", ErrWorldPanic, r, debug.Stack())
+			}
+		}()
+	}
+
+	return world.Update()
+}
+
+// failOver replaces the world stack with the error world after the failed world returned err.
+func (g *Game) failOver(failed World, err error) error {
+	if g.errorWorld == nil || failed == g.failedOver {
+		return fmt.Errorf("ecs.Game.Update activeWorld.Update error: %w", err)
+	}
+
+	errorLogger(g).Error("world failed, switching to the error world",
+		slog.String("world", fmt.Sprintf("%T", failed)), slog.Any("error", err))
+
+	stopWorld(failed)
+	for len(g.worlds) > 0 {
+		world := g.worlds[len(g.worlds)-1]
+		g.worlds = g.worlds[:len(g.worlds)-1]
+		world.Teardown()
+	}
+
+	errorWorld := g.errorWorld(failed, err)
+	if initErr := g.initWorld(errorWorld); initErr != nil {
+		return fmt.Errorf("ecs.Game.Update g.initWorld error: %w", errors.Join(initErr, err))
+	}
+
+	g.worlds = append(g.worlds, errorWorld)
+	g.failedOver = errorWorld
+	startWorld(errorWorld)
+
+	return nil
+}
+
+// ErrorWorld is a world that shows an error message and optionally lets the player retry,
+// e.g. by restarting the failed world or reloading the last save.
+type ErrorWorld struct {
+	*BaseWorld
+
+	// Err is the error shown to the player.
+	Err error
+	// Retry is called when the player presses Enter. If it is nil, no retry is offered.
+	// An error returned by Retry replaces Err.
+	Retry func(g *Game) error
+}
+
+// NewErrorWorld creates an ErrorWorld showing err and calling retry when the player presses Enter.
+func NewErrorWorld(err error, retry func(g *Game) error) *ErrorWorld {
+	return &ErrorWorld{Err: err, Retry: retry}
+}
+
+// DefaultErrorWorld is an ErrorWorldFunc whose error world retries by starting a new instance
+// of the failed world's type, like Game.RestartActiveWorld.
+func DefaultErrorWorld(failed World, err error) World {
+	typ := reflect.TypeOf(failed).Elem()
+
+	return NewErrorWorld(err, func(g *Game) error {
+		if err := g.SetActiveWorld(reflect.New(typ).Interface().(World)); err != nil {
+			return fmt.Errorf("ecs.DefaultErrorWorld g.SetActiveWorld error: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (w *ErrorWorld) Init(g *Game) error {
+	em := NewEntityManager()
+	sm := NewSystemManager(em, g)
+	if err := sm.Add(&errorScreenSystem{BaseSystem: NewBaseSystem(NextSystemID(), 0), world: w}); err != nil {
+		return fmt.Errorf("ecs.ErrorWorld.Init sm.Add error: %w", err)
+	}
+
+	w.BaseWorld = NewBaseWorld(em, sm)
+
+	return nil
+}
+
+// errorScreenSystem draws the error of its ErrorWorld and handles the retry key.
+type errorScreenSystem struct {
+	*BaseSystem
+
+	world *ErrorWorld
+}
+
+func (s *errorScreenSystem) Update() error {
+	if s.world.Retry == nil || !inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		return nil
+	}
+
+	if err := s.world.Retry(s.Game()); err != nil {
+		s.world.Err = err
+	}
+
+	return nil
+}
+
+func (s *errorScreenSystem) Draw(screen *ebiten.Image) {
+	message := fmt.Sprintf("The game stopped because of an error:\n\n%v", s.world.Err)
+	if s.world.Retry != nil {
+		message += "\n\nPress Enter to retry."
+	}
+
+	ebitenutil.DebugPrintAt(screen, message, 16, 64)
+}
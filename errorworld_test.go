@@ -0,0 +1,79 @@
+package ecs_test
+
+import (
+	"errors"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorWorld(t *testing.T) {
+	errBroken := errors.New("broken save")
+
+	failing := func(game *ecs.Game, events *[]string, fail func() error) *hookWorld {
+		world := &hookWorld{events: events, name: "gameplay"}
+		require.NoError(t, game.SetActiveWorld(world))
+		world.SystemManager().AddFunc(1, func(*ecs.EntityManager, *ecs.Game) error { return fail() })
+
+		return world
+	}
+
+	t.Run("without error world", func(t *testing.T) {
+		var events []string
+		game := ecs.NewGame(&ecs.GameConfig{})
+		failing(game, &events, func() error { return errBroken })
+
+		assert.ErrorIs(t, game.Update(), errBroken)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var events []string
+		game := ecs.NewGame(&ecs.GameConfig{})
+
+		var failedWorld ecs.World
+		game.SetErrorWorld(func(failed ecs.World, err error) ecs.World {
+			failedWorld = failed
+			return ecs.NewErrorWorld(err, nil)
+		})
+
+		menu := &hookWorld{events: &events, name: "menu"}
+		require.NoError(t, game.SetActiveWorld(menu))
+		require.NoError(t, game.PushWorld(&hookWorld{events: &events, name: "gameplay"}))
+		game.ActiveWorld().(*hookWorld).SystemManager().AddFunc(1, func(*ecs.EntityManager, *ecs.Game) error {
+			return errBroken
+		})
+		gameplay := game.ActiveWorld()
+		events = nil
+
+		require.NoError(t, game.Update())
+		assert.Same(t, gameplay, failedWorld)
+		assert.Equal(t, []string{"gameplay:update", "gameplay:stop", "gameplay:teardown", "menu:teardown"}, events)
+
+		errorWorld, ok := game.ActiveWorld().(*ecs.ErrorWorld)
+		require.True(t, ok)
+		assert.ErrorIs(t, errorWorld.Err, errBroken)
+		require.NoError(t, game.Update())
+		require.NoError(t, game.PopWorld())
+		assert.Nil(t, game.ActiveWorld())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var events []string
+		game := ecs.NewGame(&ecs.GameConfig{})
+		game.SetErrorWorld(ecs.DefaultErrorWorld)
+		failing(game, &events, func() error { panic("nil map") })
+
+		require.NoError(t, game.Update())
+
+		errorWorld, ok := game.ActiveWorld().(*ecs.ErrorWorld)
+		require.True(t, ok)
+		assert.ErrorIs(t, errorWorld.Err, ecs.ErrWorldPanic)
+		assert.ErrorContains(t, errorWorld.Err, "nil map")
+
+		// Retrying starts a fresh instance of the failed world.
+		require.NoError(t, errorWorld.Retry(game))
+		assert.IsType(t, &hookWorld{}, game.ActiveWorld())
+	})
+}
@@ -38,6 +38,8 @@ func Not[C any](filter Filter[C]) Filter[C] {
 
 // Where filters entities based on a component filter
 func Where[C any](em *EntityManager, seq iter.Seq[EntityID], filter Filter[C]) iter.Seq[EntityID] {
+	record := em.recordFilter()
+
 	return func(yield func(EntityID) bool) {
 		for id := range seq {
 			comp, ok := GetComponent[C](em, id)
@@ -45,7 +47,12 @@ func Where[C any](em *EntityManager, seq iter.Seq[EntityID], filter Filter[C]) i
 				continue
 			}
 
-			if filter(comp) {
+			accepted := filter(comp)
+			if record != nil {
+				record(accepted)
+			}
+
+			if accepted {
 				if !yield(id) {
 					break
 				}
@@ -5,6 +5,7 @@ import (
 
 	ecs "github.com/samix73/ebiten-ecs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func highZoomFilter(c *CameraComponent) bool {
@@ -90,3 +91,42 @@ func TestWhere(t *testing.T) {
 	assert.Len(t, gotCameras, 1)
 	assert.Equal(t, camera3, gotCameras[0])
 }
+
+type filterStats struct {
+	Speed float32
+}
+
+type filterUnit struct {
+	filterStats
+	Name     string
+	Level    int
+	Alive    bool
+	Position [2]float64
+	Target   *filterStats
+}
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ecs.ParseFilter[CameraComponent]("Zoom > 1.0 && Zoom < 3.0")
+	require.NoError(t, err)
+	assert.True(t, filter(&CameraComponent{Zoom: 1.5}))
+	assert.False(t, filter(&CameraComponent{Zoom: 3}))
+
+	orc := &filterUnit{filterStats: filterStats{Speed: 2}, Name: "orc", Level: -2, Alive: true, Position: [2]float64{5, 1}}
+	for expr, want := range map[string]bool{
+		`Name == "orc" && Alive`:             true,
+		`!Alive || Level >= 0`:               false,
+		`Level == -2 && Position[0] > 4`:     true,
+		`Speed >= 2 && !(Position[1] > 1)`:   true,
+		`Target.Speed == 0 && Level < Speed`: true,
+		`Alive == false`:                     false,
+	} {
+		filter, err := ecs.ParseFilter[filterUnit](expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, filter(orc), expr)
+	}
+
+	for _, expr := range []string{`Missing > 1`, `Name > 1`, `Level`, `Level > `, `(Alive`, `Position[2] == 0`, `Alive && "x`} {
+		_, err := ecs.ParseFilter[filterUnit](expr)
+		assert.ErrorIs(t, err, ecs.ErrInvalidFilter, expr)
+	}
+}
@@ -0,0 +1,456 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidFilter is returned by ParseFilter for an expression it cannot compile.
+var ErrInvalidFilter = errors.New("invalid filter expression")
+
+// ParseFilter compiles a filter expression over the exported fields of component type C, so that filters
+// can live in config files, scenes and consoles rather than Go code:
+//
+//	filter, err := ecs.ParseFilter[Camera]("Zoom > 1.0 && Zoom < 3.0")
+//	for id := range ecs.Where(em, ecs.Query[Camera](em), filter) { ... }
+//
+// Expressions compare fields with numbers, "strings", true and false, or with other fields, using
+// ==, !=, <, <=, > and >=, and combine comparisons with &&, || and !, grouped with parentheses. A bool field
+// is a comparison by itself. Fields of nested structs are reached with dots and elements of arrays and
+// slices with constant indices, e.g. Position[0] or Stats.Speed; nil pointers read as zero values.
+// Numbers of any kind compare as float64. Unknown fields and mismatched types are reported when parsing.
+func ParseFilter[C any](expr string) (Filter[C], error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.ParseFilter %q: %w", expr, err)
+	}
+
+	p := &filterParser{typ: reflect.TypeFor[C](), tokens: tokens}
+	predicate, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q: %w", p.tokens[p.pos].text, ErrInvalidFilter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ecs.ParseFilter %q: %w", expr, err)
+	}
+
+	return func(component *C) bool {
+		return predicate(reflect.ValueOf(component).Elem())
+	}, nil
+}
+
+// MustParseFilter is ParseFilter for expressions known to be valid. It panics on error.
+func MustParseFilter[C any](expr string) Filter[C] {
+	filter, err := ParseFilter[C](expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return filter
+}
+
+type filterTokenKind int
+
+const (
+	filterIdent filterTokenKind = iota
+	filterNumber
+	filterString
+	filterOperator
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+// filterOperators are the operators of filter expressions, two-character ones first.
+var filterOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"}
+
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string: %w", ErrInvalidFilter)
+			}
+
+			text, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("string %s: %w", expr[i:end+1], ErrInvalidFilter)
+			}
+			tokens = append(tokens, filterToken{kind: filterString, text: text})
+			i = end + 1
+		case unicode.IsDigit(c) || c == '.' || c == '-' && i+1 < len(expr) && unicode.IsDigit(rune(expr[i+1])) && expectsOperand(tokens):
+			end := i + 1
+			for end < len(expr) && (unicode.IsDigit(rune(expr[end])) || strings.ContainsRune(".eE_", rune(expr[end])) ||
+				(expr[end] == '-' || expr[end] == '+') && (expr[end-1] == 'e' || expr[end-1] == 'E')) {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: filterNumber, text: expr[i:end]})
+			i = end
+		case unicode.IsLetter(c) || c == '_':
+			end := i + 1
+			for end < len(expr) && (unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end])) || strings.ContainsRune("_.[]", rune(expr[end]))) {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: filterIdent, text: expr[i:end]})
+			i = end
+		default:
+			operator := ""
+			for _, candidate := range filterOperators {
+				if strings.HasPrefix(expr[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected %q: %w", c, ErrInvalidFilter)
+			}
+
+			tokens = append(tokens, filterToken{kind: filterOperator, text: operator})
+			i += len(operator)
+		}
+	}
+
+	return tokens, nil
+}
+
+// expectsOperand reports whether the next token is an operand rather than an operator, so that a minus
+// sign starts a negative number.
+func expectsOperand(tokens []filterToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+
+	last := tokens[len(tokens)-1]
+	return last.kind == filterOperator && last.text != ")"
+}
+
+// filterPredicate evaluates a compiled expression on a component.
+type filterPredicate func(component reflect.Value) bool
+
+// filterOperand is a compiled operand; exactly one of its functions is set, depending on its type.
+type filterOperand struct {
+	number  func(component reflect.Value) float64
+	str     func(component reflect.Value) string
+	boolean func(component reflect.Value) bool
+}
+
+func (o filterOperand) typeName() string {
+	switch {
+	case o.number != nil:
+		return "number"
+	case o.str != nil:
+		return "string"
+	default:
+		return "bool"
+	}
+}
+
+type filterParser struct {
+	typ    reflect.Type
+	tokens []filterToken
+	pos    int
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *filterParser) accept(op string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == filterOperator && p.tokens[p.pos].text == op {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *filterParser) parseOr() (filterPredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(component reflect.Value) bool { return l(component) || right(component) }
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterPredicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(component reflect.Value) bool { return l(component) && right(component) }
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterPredicate, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(component reflect.Value) bool { return !operand(component) }, nil
+	}
+
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ): %w", ErrInvalidFilter)
+		}
+
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterPredicate, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := ""
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == filterOperator {
+		switch text := p.tokens[p.pos].text; text {
+		case "==", "!=", "<", "<=", ">", ">=":
+			op = text
+			p.pos++
+		}
+	}
+
+	if op == "" {
+		if left.boolean == nil {
+			return nil, fmt.Errorf("%s operand without comparison: %w", left.typeName(), ErrInvalidFilter)
+		}
+
+		return left.boolean, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case left.number != nil && right.number != nil:
+		return compareFilter(op, left.number, right.number), nil
+	case left.str != nil && right.str != nil:
+		return compareFilter(op, left.str, right.str), nil
+	case left.boolean != nil && right.boolean != nil && (op == "==" || op == "!="):
+		equal := op == "=="
+		return func(component reflect.Value) bool {
+			return (left.boolean(component) == right.boolean(component)) == equal
+		}, nil
+	default:
+		return nil, fmt.Errorf("cannot compare %s %s %s: %w", left.typeName(), op, right.typeName(), ErrInvalidFilter)
+	}
+}
+
+func compareFilter[T float64 | string](op string, left, right func(reflect.Value) T) filterPredicate {
+	return func(component reflect.Value) bool {
+		l, r := left(component), right(component)
+		switch op {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		default:
+			return l >= r
+		}
+	}
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	if p.pos >= len(p.tokens) {
+		return filterOperand{}, fmt.Errorf("unexpected end: %w", ErrInvalidFilter)
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case filterNumber:
+		number, err := strconv.ParseFloat(strings.ReplaceAll(token.text, "_", ""), 64)
+		if err != nil {
+			return filterOperand{}, fmt.Errorf("number %q: %w", token.text, ErrInvalidFilter)
+		}
+
+		return filterOperand{number: func(reflect.Value) float64 { return number }}, nil
+	case filterString:
+		return filterOperand{str: func(reflect.Value) string { return token.text }}, nil
+	case filterIdent:
+		switch token.text {
+		case "true", "false":
+			value := token.text == "true"
+			return filterOperand{boolean: func(reflect.Value) bool { return value }}, nil
+		}
+
+		return p.fieldOperand(token.text)
+	default:
+		return filterOperand{}, fmt.Errorf("unexpected %q: %w", token.text, ErrInvalidFilter)
+	}
+}
+
+// filterStep reads a field, or an element if field is negative, of a struct, array or slice value.
+type filterStep struct {
+	field int
+	index int
+}
+
+// fieldOperand compiles the path of a field of the component, e.g. Stats.Speed or Position[0].
+func (p *filterParser) fieldOperand(path string) (filterOperand, error) {
+	var steps []filterStep
+	typ := p.typ
+
+	for rest := path; rest != ""; {
+		typ = derefType(typ)
+		if strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return filterOperand{}, fmt.Errorf("field %s: missing ]: %w", path, ErrInvalidFilter)
+			}
+
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 || typ.Kind() != reflect.Array && typ.Kind() != reflect.Slice ||
+				typ.Kind() == reflect.Array && index >= typ.Len() {
+				return filterOperand{}, fmt.Errorf("field %s: invalid index %s: %w", path, rest[:end+1], ErrInvalidFilter)
+			}
+
+			steps = append(steps, filterStep{field: -1, index: index})
+			typ = typ.Elem()
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+			continue
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		name := rest
+		if end >= 0 {
+			name, rest = rest[:end], strings.TrimPrefix(rest[end:], ".")
+		} else {
+			rest = ""
+		}
+
+		if typ.Kind() != reflect.Struct {
+			return filterOperand{}, fmt.Errorf("field %s: %s has no field %s: %w", path, typ, name, ErrInvalidFilter)
+		}
+
+		field, ok := typ.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return filterOperand{}, fmt.Errorf("field %s: %s has no exported field %s: %w", path, typ, name, ErrInvalidFilter)
+		}
+
+		// Promoted fields of embedded structs are reached through the embedded fields.
+		for _, index := range field.Index {
+			steps = append(steps, filterStep{field: index})
+		}
+		typ = field.Type
+	}
+
+	typ = derefType(typ)
+	read := func(component reflect.Value) reflect.Value {
+		v := component
+		for _, step := range steps {
+			if v = derefValue(v); !v.IsValid() {
+				break
+			}
+
+			if step.field >= 0 {
+				v = v.Field(step.field)
+			} else if step.index < v.Len() {
+				v = v.Index(step.index)
+			} else {
+				v = reflect.Value{}
+				break
+			}
+		}
+
+		if v.IsValid() {
+			v = derefValue(v)
+		}
+		if !v.IsValid() {
+			return reflect.Zero(typ)
+		}
+
+		return v
+	}
+
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return filterOperand{number: func(component reflect.Value) float64 { return float64(read(component).Int()) }}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return filterOperand{number: func(component reflect.Value) float64 { return float64(read(component).Uint()) }}, nil
+	case reflect.Float32, reflect.Float64:
+		return filterOperand{number: func(component reflect.Value) float64 { return read(component).Float() }}, nil
+	case reflect.String:
+		return filterOperand{str: func(component reflect.Value) string { return read(component).String() }}, nil
+	case reflect.Bool:
+		return filterOperand{boolean: func(component reflect.Value) bool { return read(component).Bool() }}, nil
+	default:
+		return filterOperand{}, fmt.Errorf("field %s: cannot compare %s: %w", path, typ, ErrInvalidFilter)
+	}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// derefValue follows pointers, returning the zero Value for a nil pointer.
+func derefValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
@@ -0,0 +1,51 @@
+package ecs
+
+// SetFrameStepping turns frame-step debugging on or off. While on, the simulation is paused, like with
+// Pause, except for one Update per StepOnce call, so physics and animation glitches can be followed tick
+// by tick. Drawing and the systems marked with BaseSystem.SetAlwaysRun, such as debug controls, keep running.
+// Turning it off resumes the game, unless it was already paused when frame stepping was turned on.
+func (g *Game) SetFrameStepping(enabled bool) {
+	g.frameStepping = enabled
+	g.pendingSteps = 0
+
+	switch {
+	case enabled && !g.Paused():
+		g.Pause()
+		g.pausedByStepping = true
+	case !enabled && g.pausedByStepping:
+		g.Resume()
+		g.pausedByStepping = false
+	}
+}
+
+// FrameStepping reports whether frame-step debugging is on, see SetFrameStepping.
+func (g *Game) FrameStepping() bool {
+	return g.frameStepping
+}
+
+// StepOnce makes the next Update advance the simulation by exactly one tick, turning frame-step debugging
+// on if it is off. Calls before that Update queue further ticks, one per Update.
+func (g *Game) StepOnce() {
+	if !g.frameStepping {
+		g.SetFrameStepping(true)
+	}
+
+	g.pendingSteps++
+}
+
+// beginStep resumes the simulation for the Update if a step is pending, returning the function that
+// pauses it again.
+func (g *Game) beginStep() (end func()) {
+	if !g.frameStepping || g.pendingSteps == 0 {
+		return func() {}
+	}
+
+	g.pendingSteps--
+	g.Resume()
+
+	return func() {
+		if g.frameStepping {
+			g.Pause()
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ecs
+
+import (
+	"iter"
+	"reflect"
+	"time"
+)
+
+// SystemOptions declares a system created with NewSystem.
+type SystemOptions struct {
+	// ID is the system ID. A new ID is generated if it is UndefinedID.
+	ID SystemID
+	// Name is the name of the system used in logs and statistics.
+	Name     string
+	Phase    Phase
+	Priority int
+	// AlwaysRun keeps the system updating while the game is paused, see BaseSystem.SetAlwaysRun.
+	AlwaysRun bool
+	// Before and After are the IDs of the systems this system runs before and after,
+	// see BaseSystem.Before and BaseSystem.After.
+	Before, After []SystemID
+	// Interval and TickInterval schedule the system to run less often than every tick,
+	// see BaseSystem.SetInterval and BaseSystem.SetTickInterval.
+	Interval     time.Duration
+	TickInterval int
+	// Query are the component types, given as zero values, of the entities returned by FuncSystem.Query.
+	Query []any
+	// Access declares the component and event types the system uses.
+	Access SystemAccess
+	// Startup is called once before the first update of the system, see StartupSystem.
+	Startup func(s *FuncSystem) error
+}
+
+// FuncSystem is a system whose behavior is a plain update function, created with NewSystem.
+type FuncSystem struct {
+	*BaseSystem
+
+	query   []reflect.Type
+	startup func(s *FuncSystem) error
+	update  func(s *FuncSystem) error
+}
+
+// NewSystem creates a system from its options and update function, without declaring a new type:
+//
+//	sm.Add(ecs.NewSystem(ecs.SystemOptions{
+//		Phase:  ecs.PhasePostUpdate,
+//		Query:  []any{Transform{}, Velocity{}},
+//		Access: ecs.SystemAccess{Reads: ecs.Types(Velocity{}), Writes: ecs.Types(Transform{})},
+//	}, func(s *ecs.FuncSystem) error {
+//		for id := range s.Query() { ... }
+//		return nil
+//	}))
+func NewSystem(opts SystemOptions, update func(s *FuncSystem) error) *FuncSystem {
+	s := &FuncSystem{
+		BaseSystem: NewBaseSystem(opts.ID, opts.Priority),
+		query:      Types(opts.Query...),
+		startup:    opts.Startup,
+		update:     update,
+	}
+	s.SetName(opts.Name)
+	s.SetPhase(opts.Phase)
+	s.SetAlwaysRun(opts.AlwaysRun)
+	s.Before(opts.Before...)
+	s.After(opts.After...)
+	s.SetInterval(opts.Interval)
+	s.SetTickInterval(opts.TickInterval)
+	s.SetAccess(opts.Access)
+
+	return s
+}
+
+// Query returns the entities that have all the component types of SystemOptions.Query.
+func (s *FuncSystem) Query() iter.Seq[EntityID] {
+	return s.EntityManager().query(s.query...)
+}
+
+// Startup calls the startup function of SystemOptions, if any.
+func (s *FuncSystem) Startup() error {
+	if s.startup == nil {
+		return nil
+	}
+
+	return s.startup(s)
+}
+
+// Update calls the update function.
+func (s *FuncSystem) Update() error {
+	if s.update == nil {
+		return nil
+	}
+
+	return s.update(s)
+}
@@ -0,0 +1,219 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSystem(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	for range 3 {
+		entityID := em.NewEntity()
+		ecs.AddComponent[TransformComponent](em, entityID)
+		ecs.AddComponent[CameraComponent](em, entityID)
+	}
+	ecs.AddComponent[TransformComponent](em, em.NewEntity())
+
+	var order []string
+	record := func(name string) func(*ecs.FuncSystem) error {
+		return func(*ecs.FuncSystem) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	mover := ecs.NewSystem(ecs.SystemOptions{
+		Query: []any{TransformComponent{}, CameraComponent{}},
+		Access: ecs.SystemAccess{
+			Reads:  ecs.Types(CameraComponent{}),
+			Writes: ecs.Types(TransformComponent{}),
+		},
+	}, func(s *ecs.FuncSystem) error {
+		order = append(order, "mover")
+		for entityID := range s.Query() {
+			ecs.MustGetComponent[TransformComponent](em, entityID).Rotation++
+		}
+		return nil
+	})
+
+	sm.Add(
+		ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhasePostUpdate, Priority: -10}, record("cleanup")),
+		mover,
+		ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhasePreUpdate, Priority: 10}, record("input")),
+	)
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"input", "mover", "cleanup"}, order)
+	assert.Equal(t, 3, ecs.Count(ecs.QueryWith(em, func(tr *TransformComponent) bool { return tr.Rotation == 1 })))
+
+	assert.Equal(t, ecs.PhaseUpdate, mover.Phase())
+	assert.Equal(t, ecs.Types(TransformComponent{}), mover.Access().Writes)
+	assert.NotEqual(t, ecs.UndefinedID, mover.ID())
+}
+
+func TestAddFunc(t *testing.T) {
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(em, game)
+
+	ecs.AddComponent[TransformComponent](em, em.NewEntity())
+
+	var order []int
+	first, err := sm.AddFunc(1, func(em *ecs.EntityManager, g *ecs.Game) error {
+		assert.Same(t, game, g)
+		for entityID := range ecs.Query[TransformComponent](em) {
+			ecs.MustGetComponent[TransformComponent](em, entityID).Rotation++
+		}
+		order = append(order, 1)
+		return nil
+	})
+	require.NoError(t, err)
+	first.SetName("spin")
+
+	second, err := sm.AddFunc(0, func(*ecs.EntityManager, *ecs.Game) error {
+		order = append(order, 0)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []int{0, 1}, order)
+	assert.Equal(t, "spin", first.Name())
+	assert.NotEqual(t, first.ID(), second.ID())
+}
+
+func TestStartupSystem(t *testing.T) {
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(em, game)
+
+	var events []string
+	newSystem := func(name string, priority int, err error) *ecs.FuncSystem {
+		return ecs.NewSystem(ecs.SystemOptions{
+			Priority: priority,
+			Startup: func(*ecs.FuncSystem) error {
+				events = append(events, name+":startup")
+				return err
+			},
+		}, func(*ecs.FuncSystem) error {
+			events = append(events, name+":update")
+			return nil
+		})
+	}
+
+	sm.Add(newSystem("spawner", 10, nil), newSystem("loader", 0, nil))
+	game.Pause()
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"loader:startup", "spawner:startup"}, events, "systems start up even while paused")
+
+	game.Resume()
+	events = nil
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"loader:update", "spawner:update"}, events, "systems start up once")
+
+	sm.Add(newSystem("broken", 5, assert.AnError), newSystem("late", 20, nil))
+	events = nil
+	require.ErrorIs(t, sm.Update(), assert.AnError)
+	assert.Equal(t, []string{"broken:startup"}, events)
+
+	events = nil
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"late:startup", "loader:update", "broken:update", "spawner:update", "late:update"}, events)
+}
+
+func TestSystemOrder(t *testing.T) {
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(em, game)
+
+	var events []string
+	record := func(name string) func(*ecs.FuncSystem) error {
+		return func(*ecs.FuncSystem) error {
+			events = append(events, name)
+			return nil
+		}
+	}
+
+	physics := ecs.NewSystem(ecs.SystemOptions{Name: "physics", Priority: 10}, record("physics"))
+	camera := ecs.NewSystem(ecs.SystemOptions{Name: "camera", After: []ecs.SystemID{physics.ID()}}, record("camera"))
+	input := ecs.NewSystem(ecs.SystemOptions{Name: "input", Priority: 20, Before: []ecs.SystemID{physics.ID()}}, record("input"))
+	audio := ecs.NewSystem(ecs.SystemOptions{Name: "audio", Priority: 5}, record("audio"))
+
+	require.NoError(t, sm.Add(camera, physics, input, audio))
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"audio", "input", "physics", "camera"}, events, "constraints override priorities")
+
+	cycle := ecs.NewSystem(ecs.SystemOptions{Name: "cycle", After: []ecs.SystemID{camera.ID()}, Before: []ecs.SystemID{input.ID()}}, record("cycle"))
+	err := sm.Add(cycle)
+	require.ErrorIs(t, err, ecs.ErrSystemOrder)
+	assert.ErrorContains(t, err, "(cycle)")
+
+	late := ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhasePreUpdate, After: []ecs.SystemID{physics.ID()}}, record("late"))
+	require.ErrorIs(t, sm.Add(late), ecs.ErrSystemOrder, "constraints cannot contradict phases")
+
+	events = nil
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"audio", "input", "physics", "camera"}, events, "failed systems are not added")
+}
+
+func TestSystemManagerLookup(t *testing.T) {
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), ecs.NewGame(&ecs.GameConfig{}))
+
+	var events []string
+	newSystem := func(name string, priority int) *ecs.FuncSystem {
+		return ecs.NewSystem(ecs.SystemOptions{Name: name, Priority: priority}, func(*ecs.FuncSystem) error {
+			events = append(events, name)
+			return nil
+		})
+	}
+
+	// IDs increase against the priorities, so the update order is not the ID order.
+	c, b, a := newSystem("c", 2), newSystem("b", 1), newSystem("a", 0)
+	require.NoError(t, sm.Add(c, b, a))
+
+	system, ok := sm.Get(b.ID())
+	require.True(t, ok)
+	assert.Same(t, b, system)
+	assert.True(t, sm.Has(c.ID()))
+
+	var names []string
+	for system := range sm.Systems() {
+		names = append(names, system.(*ecs.FuncSystem).Name())
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	sm.Remove(c.ID())
+	assert.False(t, sm.Has(c.ID()))
+	_, ok = sm.Get(c.ID())
+	assert.False(t, ok)
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []string{"a", "b"}, events, "removing keeps the order of the other systems")
+}
+
+func TestSystemIDs(t *testing.T) {
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), ecs.NewGame(&ecs.GameConfig{}))
+
+	a, b := ecs.NewBaseSystem(ecs.UndefinedID, 0), ecs.NewBaseSystem(ecs.UndefinedID, 0)
+	assert.NotEqual(t, ecs.UndefinedID, a.ID())
+	assert.NotEqual(t, a.ID(), b.ID(), "IDs are allocated automatically")
+
+	id := ecs.NextSystemID()
+	first := ecs.NewSystem(ecs.SystemOptions{ID: id}, nil)
+	require.NoError(t, sm.Add(first))
+
+	require.ErrorIs(t, sm.Add(ecs.NewSystem(ecs.SystemOptions{ID: id}, nil)), ecs.ErrDuplicateSystem)
+
+	other := ecs.NewSystem(ecs.SystemOptions{}, nil)
+	require.ErrorIs(t, sm.Add(other, other), ecs.ErrDuplicateSystem)
+	assert.False(t, sm.Has(other.ID()), "no system of a failed Add is added")
+
+	system, ok := sm.Get(id)
+	require.True(t, ok)
+	assert.Same(t, first, system)
+}
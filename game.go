@@ -1,9 +1,13 @@
 package ecs
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"math"
+	"io/fs"
+	"os"
 	"reflect"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
@@ -15,35 +19,189 @@ type GameConfig struct {
 	Title                     string
 	ScreenWidth, ScreenHeight int
 	Fullscreen                bool
+	// Resizable lets the player resize the window. It is implied by a virtual resolution.
+	Resizable bool
+	// TPS is the number of updates per second, ebiten.DefaultTPS if zero.
+	TPS int
+	// DisableVsync turns vertical synchronization off, e.g. to measure the frame rate.
+	DisableVsync bool
+	// CursorMode is the mode of the mouse cursor, visible by default.
+	CursorMode ebiten.CursorModeType
+
+	// VirtualWidth and VirtualHeight declare the resolution the worlds are drawn at, scaled into the
+	// window and letterboxed, see Screen. The window is then resizable, and ScreenWidth and ScreenHeight
+	// default to the virtual resolution.
+	VirtualWidth, VirtualHeight int
+	// ScaleMode selects how the virtual resolution is scaled into the window.
+	ScaleMode ScaleMode
+
+	// AssetsFS is the file system the game Assets are read from, e.g. an embed.FS.
+	// It defaults to the current working directory.
+	AssetsFS fs.FS
+
+	// Deterministic makes every Update advance time by exactly Time.FixedStep instead of the frame
+	// duration, and runs systems of equal priority in ascending ID order, so the same inputs always
+	// produce the same world, e.g. for lockstep multiplayer and replays.
+	Deterministic bool
+
+	// Seed seeds the random number generator of every SystemManager, see SystemManager.Rand.
+	Seed uint64
 }
 
 type Game struct {
-	cfg         *GameConfig
-	activeWorld World
-	timeScale   float64
+	cfg    *GameConfig
+	worlds []World
+	// layers are the worlds running alongside the world stack, by order, see AddWorld.
+	layers    []layeredWorld
+	time      *Time
+	clock     *Clock
+	resources *Resources
+	assets    *Assets
+	screen    *Screen
+
+	beforeUpdate, afterUpdate hooks[UpdateHook]
+	beforeDraw, afterDraw     hooks[DrawHook]
+
+	focusGained, focusLost hooks[LifecycleHook]
+	closeRequested         hooks[CloseHook]
+	windowState            func() WindowState
+	// unfocused is the focus of the window at the previous Update, inverted so the window starts focused.
+	unfocused        bool
+	pauseOnFocusLoss bool
+	pausedByFocus    bool
+	exiting          bool
+
+	// frameStepping pauses the simulation except for pendingSteps updates, see SetFrameStepping.
+	frameStepping    bool
+	pendingSteps     int
+	pausedByStepping bool
+
+	// ctx is cancelled when the game shuts down, see Context.
+	ctx      context.Context
+	cancel   context.CancelFunc
+	shutdown hooks[ShutdownHook]
+
+	// headlessTPS is the tick rate while the game is run by RunHeadless, zero otherwise.
+	headlessTPS int
+
+	logger *structuralLogger
+
+	onSystemError SystemErrorHandler
+
+	errorWorld ErrorWorldFunc
+	// failedOver is the error world the game switched to after a failure, if it is still running.
+	failedOver World
 }
 
-func NewGame(cfg *GameConfig) *Game {
-	return &Game{
+// NewGame creates a game from cfg, which may be nil, and the options applied over it. cfg is copied.
+func NewGame(cfg *GameConfig, opts ...GameOption) *Game {
+	config := &GameConfig{}
+	if cfg != nil {
+		*config = *cfg
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+	cfg = config
+
+	g := &Game{
 		cfg:       cfg,
-		timeScale: 1.0,
+		time:      NewTime(),
+		clock:     NewClock(),
+		resources: NewResources(),
+		screen:    NewScreen(),
+	}
+
+	g.screen.SetVirtualResolution(cfg.VirtualWidth, cfg.VirtualHeight)
+	g.screen.SetScaleMode(cfg.ScaleMode)
+
+	assetsFS := cfg.AssetsFS
+	if assetsFS == nil {
+		assetsFS = os.DirFS(".")
 	}
+	g.assets = NewAssets(assetsFS)
+
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+
+	SetResource(g.resources, g.time)
+	SetResource(g.resources, g.clock)
+	SetResource(g.resources, g.assets)
+	SetResource(g.resources, g.screen)
+
+	return g
+}
+
+// Time returns the timing information of the current tick.
+func (g *Game) Time() *Time {
+	return g.time
+}
+
+// Clock returns the simulation clock, which advances once per Update by the scaled delta time.
+func (g *Game) Clock() *Clock {
+	return g.clock
+}
+
+// Assets returns the game asset manager.
+func (g *Game) Assets() *Assets {
+	return g.assets
+}
+
+// Screen returns the mapping of the virtual resolution to the window.
+func (g *Game) Screen() *Screen {
+	return g.screen
+}
+
+// Resources returns the game-wide resource store.
+func (g *Game) Resources() *Resources {
+	return g.resources
+}
+
+// Pause freezes the simulation: scaled time and the Clock stop advancing, and only systems marked
+// with BaseSystem.SetAlwaysRun are updated. Drawing continues, so pause menus can be shown.
+func (g *Game) Pause() {
+	g.time.SetPaused(true)
+}
+
+// Resume continues a paused simulation.
+func (g *Game) Resume() {
+	g.time.SetPaused(false)
+}
+
+// Paused reports whether the game is paused.
+func (g *Game) Paused() bool {
+	return g.time.Paused()
+}
+
+// Deterministic reports whether the game runs in deterministic mode, see GameConfig.Deterministic.
+func (g *Game) Deterministic() bool {
+	return g.cfg.Deterministic
 }
 
 func (g *Game) TimeScale() float64 {
-	return g.timeScale
+	return g.time.TimeScale()
 }
 
 func (g *Game) SetTimeScale(scale float64) {
-	g.timeScale = math.Max(scale, 0)
+	g.time.SetTimeScale(scale)
 }
 
 func (g *Game) Config() GameConfig {
 	return *g.cfg
 }
 
+// ActiveWorld returns the world on top of the world stack, or nil if there is none.
+// Only the active world is updated and drawn.
+func (g *Game) ActiveWorld() World {
+	if len(g.worlds) == 0 {
+		return nil
+	}
+
+	return g.worlds[len(g.worlds)-1]
+}
+
 func (g *Game) RestartActiveWorld() error {
-	typ := reflect.TypeOf(g.activeWorld).Elem()
+	typ := reflect.TypeOf(g.ActiveWorld()).Elem()
 	newWorld := reflect.New(typ).Interface().(World)
 
 	if err := g.SetActiveWorld(newWorld); err != nil {
@@ -53,28 +211,153 @@ func (g *Game) RestartActiveWorld() error {
 	return nil
 }
 
+// SetActiveWorld replaces the active world: the current active world, if any, is torn down
+// and the new world is initialized in its place. Worlds below it on the stack are left untouched.
 func (g *Game) SetActiveWorld(world World) error {
-	if g.activeWorld != nil {
-		g.activeWorld.Teardown()
+	if active := g.ActiveWorld(); active != nil {
+		stopWorld(active)
+		active.Teardown()
+		g.worlds = g.worlds[:len(g.worlds)-1]
+	}
+
+	if err := g.initWorld(world); err != nil {
+		return fmt.Errorf("ecs.Game.SetActiveWorld g.initWorld error: %w", err)
+	}
+
+	g.worlds = append(g.worlds, world)
+	startWorld(world)
+	g.logWorld("world set active", world)
+
+	return nil
+}
+
+// PushWorld initializes the world and makes it active on top of the current one, e.g. a pause menu over gameplay.
+// The previous world is stopped but not torn down, and resumes when the pushed world is popped.
+func (g *Game) PushWorld(world World) error {
+	previous := g.ActiveWorld()
+	if previous != nil {
+		stopWorld(previous)
+	}
+
+	if err := g.initWorld(world); err != nil {
+		if previous != nil {
+			startWorld(previous)
+		}
+
+		return fmt.Errorf("ecs.Game.PushWorld g.initWorld error: %w", err)
+	}
+
+	g.worlds = append(g.worlds, world)
+	startWorld(world)
+	g.logWorld("world pushed", world)
+
+	return nil
+}
+
+// PopWorld tears down the active world and resumes the world below it.
+func (g *Game) PopWorld() error {
+	active := g.ActiveWorld()
+	if active == nil {
+		return errors.New("ecs.Game.PopWorld: world stack is empty")
+	}
+
+	stopWorld(active)
+	active.Teardown()
+	g.worlds = g.worlds[:len(g.worlds)-1]
+	g.logWorld("world popped", active)
+
+	if next := g.ActiveWorld(); next != nil {
+		startWorld(next)
 	}
 
+	return nil
+}
+
+// initWorld initializes the world and then its systems, see SystemIniter. If a system fails,
+// the world is torn down.
+func (g *Game) initWorld(world World) error {
 	if err := world.Init(g); err != nil {
-		return fmt.Errorf("ecs.Game.SetActiveWorld world.Init error: %w", err)
+		return fmt.Errorf("ecs.Game.initWorld world.Init error: %w", err)
 	}
 
-	g.activeWorld = world
+	if sm := world.baseWorld().SystemManager(); sm != nil {
+		if err := sm.initSystems(world); err != nil {
+			world.Teardown()
+			return fmt.Errorf("ecs.Game.initWorld sm.initSystems error: %w", err)
+		}
+	}
 
 	return nil
 }
 
+func startWorld(world World) {
+	if sm := world.baseWorld().SystemManager(); sm != nil {
+		sm.startWorld(world)
+	}
+}
+
+func stopWorld(world World) {
+	if sm := world.baseWorld().SystemManager(); sm != nil {
+		sm.stopWorld()
+	}
+}
+
 func (g *Game) DeltaTime() float64 {
 	return 1.0 / float64(ebiten.TPS()) * g.TimeScale()
 }
 
+// tickDuration returns the unscaled duration of an Update.
+func (g *Game) tickDuration() float64 {
+	if g.cfg.Deterministic {
+		return g.time.FixedStep()
+	}
+
+	if g.headlessTPS > 0 {
+		return 1.0 / float64(g.headlessTPS)
+	}
+
+	// With ebiten.SyncWithFPS, TPS is negative and the game updates once per frame.
+	tps := float64(ebiten.TPS())
+	if tps <= 0 {
+		tps = ebiten.ActualFPS()
+	}
+
+	if tps <= 0 {
+		tps = ebiten.DefaultTPS
+	}
+
+	return 1.0 / tps
+}
+
 func (g *Game) Start() error {
-	ebiten.SetWindowSize(g.cfg.ScreenWidth, g.cfg.ScreenHeight)
+	width, height := g.cfg.ScreenWidth, g.cfg.ScreenHeight
+	resizable := g.cfg.Resizable
+	if virtualWidth, virtualHeight, ok := g.screen.VirtualResolution(); ok {
+		if width == 0 || height == 0 {
+			width, height = virtualWidth, virtualHeight
+		}
+
+		resizable = true
+	}
+
+	if resizable {
+		ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	}
+
+	if g.cfg.TPS > 0 {
+		ebiten.SetTPS(g.cfg.TPS)
+	}
+
+	ebiten.SetWindowSize(width, height)
 	ebiten.SetFullscreen(g.cfg.Fullscreen)
 	ebiten.SetWindowTitle(g.cfg.Title)
+	ebiten.SetVsyncEnabled(!g.cfg.DisableVsync)
+	ebiten.SetCursorMode(g.cfg.CursorMode)
+
+	if g.windowState == nil {
+		g.windowState = EbitenWindowState
+	}
+	ebiten.SetWindowClosingHandled(true)
 
 	if err := ebiten.RunGameWithOptions(g, nil); err != nil {
 		return fmt.Errorf("ecs.Game.Start ebiten.RunGameWithOptions error: %w", err)
@@ -83,28 +366,80 @@ func (g *Game) Start() error {
 	return nil
 }
 
+// Layout returns the window size when a virtual resolution is declared, see Screen,
+// and GameConfig.ScreenWidth and ScreenHeight otherwise.
 func (g *Game) Layout(outsideWidth int, outsideHeight int) (screenWidth int, screenHeight int) {
+	if _, _, ok := g.screen.VirtualResolution(); ok {
+		g.screen.layout(outsideWidth, outsideHeight)
+		return outsideWidth, outsideHeight
+	}
+
+	return g.cfg.ScreenWidth, g.cfg.ScreenHeight
+}
+
+// ScreenSize returns the size of the screen the worlds draw onto, in pixels: the virtual resolution
+// if one is declared, GameConfig.ScreenWidth and ScreenHeight otherwise.
+// Screen-space elements, such as ui.RectTransform, are placed relative to it.
+func (g *Game) ScreenSize() (width, height int) {
+	if width, height, ok := g.screen.VirtualResolution(); ok {
+		return width, height
+	}
+
 	return g.cfg.ScreenWidth, g.cfg.ScreenHeight
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
-	if g.activeWorld == nil {
+	g.runDrawHooks(&g.beforeDraw, screen)
+	defer g.runDrawHooks(&g.afterDraw, screen)
+
+	if g.ActiveWorld() == nil && len(g.layers) == 0 {
 		return
 	}
 
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("FPS: %.2f", ebiten.ActualFPS()), 16, 32)
+	canvas := screen
+	if _, _, ok := g.screen.VirtualResolution(); ok {
+		canvas = g.screen.canvas()
+		defer g.screen.present(screen)
+	}
+
+	ebitenutil.DebugPrintAt(canvas, fmt.Sprintf("FPS: %.2f", ebiten.ActualFPS()), 16, 32)
 
-	g.activeWorld.Draw(screen)
+	for world := range g.Worlds() {
+		world.Draw(canvas)
+	}
 }
 
 func (g *Game) Update() error {
-	if g.activeWorld == nil {
-		return nil
+	if err := g.pollWindow(); err != nil {
+		return err
 	}
 
-	if err := g.activeWorld.Update(); err != nil {
-		return fmt.Errorf("ecs.Game.Update activeWorld.Update error: %w", err)
+	if err := g.runUpdateHooks(&g.beforeUpdate); err != nil {
+		return err
 	}
 
-	return nil
+	if g.ActiveWorld() != nil || len(g.layers) > 0 {
+		defer g.beginStep()()
+
+		g.time.Advance(g.tickDuration())
+		if !g.time.Paused() {
+			g.clock.Advance(g.time.DeltaDuration())
+		}
+	}
+
+	for world := range g.Worlds() {
+		if err := g.updateWorld(world); err != nil {
+			if !slices.ContainsFunc(g.layers, func(l layeredWorld) bool { return l.world == world }) {
+				if err := g.failOver(world, err); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			return fmt.Errorf("ecs.Game.Update world.Update error: %w", err)
+		}
+	}
+
+	return g.runUpdateHooks(&g.afterUpdate)
 }
@@ -0,0 +1,57 @@
+package ecs
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// GameOption configures a Game created with NewGame, on top of its GameConfig:
+//
+//	g := ecs.NewGame(nil, ecs.WithTitle("Dungeon"), ecs.WithWindowSize(960, 540), ecs.WithTPS(30))
+type GameOption func(cfg *GameConfig)
+
+// WithTitle sets the window title.
+func WithTitle(title string) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.Title = title
+	}
+}
+
+// WithWindowSize sets the window size.
+func WithWindowSize(width, height int) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.ScreenWidth, cfg.ScreenHeight = width, height
+	}
+}
+
+// WithTPS sets the number of updates per second, see ebiten.SetTPS.
+func WithTPS(tps int) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.TPS = tps
+	}
+}
+
+// WithVsync enables or disables vertical synchronization, enabled by default.
+func WithVsync(enabled bool) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.DisableVsync = !enabled
+	}
+}
+
+// WithFullscreen starts the game in fullscreen or windowed mode.
+func WithFullscreen(fullscreen bool) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.Fullscreen = fullscreen
+	}
+}
+
+// WithResizable lets the player resize the window.
+func WithResizable(resizable bool) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.Resizable = resizable
+	}
+}
+
+// WithCursorMode sets the mode of the mouse cursor, e.g. ebiten.CursorModeCaptured for mouse look.
+func WithCursorMode(mode ebiten.CursorModeType) GameOption {
+	return func(cfg *GameConfig) {
+		cfg.CursorMode = mode
+	}
+}
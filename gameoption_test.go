@@ -0,0 +1,37 @@
+package ecs_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGameOptions(t *testing.T) {
+	cfg := &ecs.GameConfig{Title: "Config", Seed: 7}
+	g := ecs.NewGame(cfg,
+		ecs.WithTitle("Dungeon"),
+		ecs.WithWindowSize(960, 540),
+		ecs.WithTPS(30),
+		ecs.WithVsync(false),
+		ecs.WithFullscreen(true),
+		ecs.WithResizable(true),
+		ecs.WithCursorMode(ebiten.CursorModeCaptured),
+	)
+
+	assert.Equal(t, ecs.GameConfig{
+		Title:        "Dungeon",
+		ScreenWidth:  960,
+		ScreenHeight: 540,
+		Fullscreen:   true,
+		Resizable:    true,
+		TPS:          30,
+		DisableVsync: true,
+		CursorMode:   ebiten.CursorModeCaptured,
+		Seed:         7,
+	}, g.Config())
+	assert.Equal(t, "Config", cfg.Title, "the config is copied")
+
+	assert.Equal(t, ecs.GameConfig{Title: "Bare"}, ecs.NewGame(nil, ecs.WithTitle("Bare")).Config())
+}
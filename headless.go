@@ -0,0 +1,68 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DefaultTPS is the tick rate of headless games run as fast as possible, see Game.RunHeadless.
+const DefaultTPS = 60
+
+// RunHeadless creates a Game with a default configuration, makes world its active world and runs it
+// headless at tps ticks per second, see Game.RunHeadless.
+func RunHeadless(world World, tps int) error {
+	g := NewGame(&GameConfig{})
+	if err := g.SetActiveWorld(world); err != nil {
+		return fmt.Errorf("ecs.RunHeadless g.SetActiveWorld error: %w", err)
+	}
+
+	return g.RunHeadless(tps)
+}
+
+// RunHeadless runs the game without a window, a graphics context or ebiten.RunGame: it updates the game
+// tps times per second of wall time and never draws, for dedicated servers and gameplay tests in CI.
+// Fixed update systems run as usual. If tps is zero or negative, ticks run back to back as fast as possible,
+// each covering 1/DefaultTPS of game time, so tests are not slowed down by the wall clock.
+//
+// The game runs until an update returns an error. Like with ebiten.RunGame, a world or hook returning
+// ebiten.Termination stops the game and RunHeadless returns nil. The worlds are left as they are on return,
+// so tests can inspect them.
+//
+// The package still links Ebiten, whose initialization needs a display on Linux even if nothing is drawn:
+// run headless binaries and tests under a virtual display such as Xvfb, e.g. with xvfb-run.
+func (g *Game) RunHeadless(tps int) error {
+	g.headlessTPS = tps
+	if tps <= 0 {
+		g.headlessTPS = DefaultTPS
+	}
+
+	defer func() { g.headlessTPS = 0 }()
+
+	var ticker *time.Ticker
+	if tps > 0 {
+		ticker = time.NewTicker(time.Second / time.Duration(tps))
+		defer ticker.Stop()
+	}
+
+	for {
+		if err := g.Update(); err != nil {
+			if errors.Is(err, ebiten.Termination) {
+				return nil
+			}
+
+			return fmt.Errorf("ecs.Game.RunHeadless g.Update error: %w", err)
+		}
+
+		if ticker != nil {
+			<-ticker.C
+		}
+	}
+}
+
+// Headless reports whether the game is being run by RunHeadless.
+func (g *Game) Headless() bool {
+	return g.headlessTPS > 0
+}
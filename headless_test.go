@@ -0,0 +1,69 @@
+package ecs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headlessWorld struct {
+	*ecs.BaseWorld
+
+	ticks    int
+	fixed    int
+	stopAt   int
+	failWith error
+	headless bool
+}
+
+func (w *headlessWorld) Init(g *ecs.Game) error {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+	sm.Add(ecs.NewSystem(ecs.SystemOptions{}, func(s *ecs.FuncSystem) error {
+		w.ticks++
+		w.headless = s.Game().Headless()
+		if w.ticks == w.stopAt {
+			if w.failWith != nil {
+				return w.failWith
+			}
+
+			return ebiten.Termination
+		}
+
+		return nil
+	}))
+	sm.Add(ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhaseFixedUpdate}, func(s *ecs.FuncSystem) error {
+		w.fixed++
+		return nil
+	}))
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+func TestRunHeadless(t *testing.T) {
+	world := &headlessWorld{stopAt: 30}
+	require.NoError(t, ecs.RunHeadless(world, 0))
+	assert.Equal(t, 30, world.ticks)
+	assert.True(t, world.headless)
+	// Each tick covers one fixed step at the default tick rate.
+	assert.Equal(t, 30, world.fixed)
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	game.Time().SetFixedStep(1.0 / 50)
+	world = &headlessWorld{stopAt: 5}
+	require.NoError(t, game.SetActiveWorld(world))
+	require.NoError(t, game.RunHeadless(100))
+	assert.False(t, game.Headless())
+	assert.InDelta(t, 0.05, game.Time().Total(), 1e-9)
+	assert.Equal(t, 2, world.fixed)
+
+	failure := errors.New("boom")
+	world = &headlessWorld{stopAt: 3, failWith: failure}
+	assert.ErrorIs(t, ecs.RunHeadless(world, 0), failure)
+}
@@ -0,0 +1,88 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// UpdateHook is called by Game.Update before or after the active world is updated.
+type UpdateHook func(g *Game) error
+
+// DrawHook is called by Game.Draw before or after the active world is drawn.
+type DrawHook func(g *Game, screen *ebiten.Image)
+
+// hooks is a list of hooks that can be removed by the function returned when they were added.
+// Hooks may add and remove hooks while they run: the list is copied on write, so the running
+// iteration is not disturbed, and removed hooks are skipped.
+type hooks[F any] struct {
+	entries []*hookEntry[F]
+}
+
+type hookEntry[F any] struct {
+	fn      F
+	removed bool
+}
+
+func (h *hooks[F]) add(fn F) (remove func()) {
+	entry := &hookEntry[F]{fn: fn}
+	h.entries = append(slices.Clip(h.entries), entry)
+
+	return func() {
+		entry.removed = true
+		h.entries = slices.DeleteFunc(slices.Clone(h.entries), func(e *hookEntry[F]) bool { return e == entry })
+	}
+}
+
+// all returns the hooks; entries removed after the call are marked removed.
+func (h *hooks[F]) all() []*hookEntry[F] {
+	return h.entries
+}
+
+// BeforeUpdate registers a hook called at the start of every Update, before the active world is updated,
+// e.g. to start a profiler span or poll the network. Hooks run in registration order, even while the game
+// is paused or has no active world. It returns a function that removes the hook.
+func (g *Game) BeforeUpdate(hook UpdateHook) (remove func()) {
+	return g.beforeUpdate.add(hook)
+}
+
+// AfterUpdate registers a hook called at the end of every Update, after the active world is updated,
+// e.g. to flush network messages or record the frame. It returns a function that removes the hook.
+func (g *Game) AfterUpdate(hook UpdateHook) (remove func()) {
+	return g.afterUpdate.add(hook)
+}
+
+// BeforeDraw registers a hook called at the start of every Draw, before the active world is drawn.
+// It returns a function that removes the hook.
+func (g *Game) BeforeDraw(hook DrawHook) (remove func()) {
+	return g.beforeDraw.add(hook)
+}
+
+// AfterDraw registers a hook called at the end of every Draw, after the active world is drawn,
+// e.g. to capture the screen. It returns a function that removes the hook.
+func (g *Game) AfterDraw(hook DrawHook) (remove func()) {
+	return g.afterDraw.add(hook)
+}
+
+func (g *Game) runUpdateHooks(h *hooks[UpdateHook]) error {
+	for _, entry := range h.all() {
+		if entry.removed {
+			continue
+		}
+
+		if err := entry.fn(g); err != nil {
+			return fmt.Errorf("ecs.Game.Update hook error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (g *Game) runDrawHooks(h *hooks[DrawHook], screen *ebiten.Image) {
+	for _, entry := range h.all() {
+		if !entry.removed {
+			entry.fn(g, screen)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ecs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameHooks(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	var events []string
+	require.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "world"}))
+	events = nil
+
+	removeBefore := game.BeforeUpdate(func(*ecs.Game) error {
+		events = append(events, "before update")
+		return nil
+	})
+	game.AfterUpdate(func(*ecs.Game) error {
+		events = append(events, "after update")
+		return nil
+	})
+
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"before update", "world:update", "after update"}, events)
+
+	events = nil
+	removeBefore()
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"world:update", "after update"}, events)
+
+	errFlush := errors.New("flush failed")
+	game.AfterUpdate(func(*ecs.Game) error { return errFlush })
+	assert.ErrorIs(t, game.Update(), errFlush)
+}
+
+func TestGameDrawHooks(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	var events []string
+	game.BeforeDraw(func(*ecs.Game, *ebiten.Image) { events = append(events, "before draw") })
+	game.AfterDraw(func(*ecs.Game, *ebiten.Image) { events = append(events, "after draw") })
+
+	// Draw hooks run even without an active world.
+	game.Draw(nil)
+	assert.Equal(t, []string{"before draw", "after draw"}, events)
+}
+
+func TestGameHooksRemovedWhileRunning(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	var events []string
+	var removeOnce, removeLast func()
+	removeOnce = game.BeforeUpdate(func(*ecs.Game) error {
+		events = append(events, "once")
+		removeOnce()
+		return nil
+	})
+	game.BeforeUpdate(func(*ecs.Game) error {
+		events = append(events, "every")
+		removeLast()
+		return nil
+	})
+	removeLast = game.BeforeUpdate(func(*ecs.Game) error {
+		events = append(events, "removed")
+		return nil
+	})
+
+	require.NoError(t, game.Update())
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"once", "every", "every"}, events)
+}
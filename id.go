@@ -11,6 +11,16 @@ func NextID() ID {
 	return ID(nextID.Add(1))
 }
 
+// NextSystemID generates and returns a new unique system ID, so that systems of different packages
+// never collide, e.g. in a package-level variable:
+//
+//	var PhysicsSystemID = ecs.NextSystemID()
+//
+// Systems created with NewBaseSystem and UndefinedID get one automatically.
+func NextSystemID() SystemID {
+	return NextID()
+}
+
 // ID represents a unique identifier for entities and systems within the ECS framework.
 type ID uint64
 
@@ -0,0 +1,247 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"unsafe"
+)
+
+var (
+	// ErrNotPOD is returned when a component type with pointers is registered for inline storage.
+	ErrNotPOD = errors.New("component type is not plain old data")
+	// ErrStorageExists is returned when the storage of a component type is configured after it was created.
+	ErrStorageExists = errors.New("component storage already exists")
+)
+
+// UseInlineStorage stores component type C inline: the values live back to back in a single buffer
+// whose elements are aligned to alignment bytes (0 uses the natural alignment of C), instead of
+// being pooled and referenced through pointers. This removes a pointer indirection and keeps small,
+// hot components such as transforms in contiguous cache lines.
+//
+// Inline components must be plain old data: C may not contain pointers, slices, maps, strings,
+// interfaces, channels or functions, and ErrNotPOD is returned otherwise.
+//
+// Because values move when the buffer grows or when a component is removed, pointers returned by
+// GetComponent and AddComponent for inline components are only valid until the next
+// AddComponent or RemoveComponent of type C. Do not keep them across structural changes.
+//
+// UseInlineStorage must be called before the first component of type C is added.
+func UseInlineStorage[C any](em *EntityManager, alignment int) error {
+	defer em.beginStructural("UseInlineStorage")()
+
+	componentType := reflect.TypeFor[C]()
+
+	if _, exists := em.componentContainers[componentType]; exists {
+		return fmt.Errorf("ecs.UseInlineStorage %s: %w", componentType, ErrStorageExists)
+	}
+
+	container, err := NewInlineContainer[C](alignment)
+	if err != nil {
+		return fmt.Errorf("ecs.UseInlineStorage NewInlineContainer error: %w", err)
+	}
+
+	em.registerStorage(componentType, container)
+
+	return nil
+}
+
+// InlineContainer stores plain old data components of type C by value in an aligned buffer.
+// See UseInlineStorage.
+type InlineContainer[C any] struct {
+	buf    []byte
+	base   int
+	stride int
+	align  int
+
+	entityIDs          []EntityID
+	componentLookupMap map[EntityID]int
+}
+
+// NewInlineContainer creates an InlineContainer whose elements are aligned to alignment bytes.
+// alignment must be zero or a power of two no smaller than the natural alignment of C.
+func NewInlineContainer[C any](alignment int) (*InlineContainer[C], error) {
+	componentType := reflect.TypeFor[C]()
+
+	if !isPOD(componentType) {
+		return nil, fmt.Errorf("ecs.NewInlineContainer %s: %w", componentType, ErrNotPOD)
+	}
+
+	natural := componentType.Align()
+	if alignment == 0 {
+		alignment = natural
+	}
+
+	if alignment&(alignment-1) != 0 || alignment < natural {
+		return nil, fmt.Errorf("ecs.NewInlineContainer: alignment %d of %s must be a power of two no smaller than %d", alignment, componentType, natural)
+	}
+
+	size := int(componentType.Size())
+
+	c := &InlineContainer[C]{
+		stride:             (size + alignment - 1) &^ (alignment - 1),
+		align:              alignment,
+		componentLookupMap: make(map[EntityID]int),
+	}
+	c.Reserve(64)
+
+	return c, nil
+}
+
+// Stride returns the distance in bytes between two consecutive components.
+func (c *InlineContainer[C]) Stride() int {
+	return c.stride
+}
+
+func (c *InlineContainer[C]) capacity() int {
+	if c.stride == 0 {
+		return int(^uint(0) >> 1)
+	}
+
+	return (len(c.buf) - c.base) / c.stride
+}
+
+func (c *InlineContainer[C]) at(index int) *C {
+	return (*C)(unsafe.Pointer(&c.buf[c.base+index*c.stride]))
+}
+
+// Reserve grows the buffer so that n more components can be added without reallocating.
+func (c *InlineContainer[C]) Reserve(n int) {
+	count := len(c.entityIDs)
+	if c.buf != nil && count+n <= c.capacity() {
+		return
+	}
+
+	capacity := max(count+n, 2*count)
+	buf := make([]byte, capacity*c.stride+c.align)
+
+	base := 0
+	if addr := uintptr(unsafe.Pointer(unsafe.SliceData(buf))); addr%uintptr(c.align) != 0 {
+		base = c.align - int(addr%uintptr(c.align))
+	}
+
+	copy(buf[base:], c.buf[c.base:c.base+count*c.stride])
+
+	c.buf = buf
+	c.base = base
+}
+
+// Add appends a zeroed component for the entity and returns a pointer to it,
+// or nil if the entity already has one.
+func (c *InlineContainer[C]) Add(entityID EntityID) any {
+	if _, ok := c.componentLookupMap[entityID]; ok {
+		return nil
+	}
+
+	c.Reserve(1)
+
+	index := len(c.entityIDs)
+	c.entityIDs = append(c.entityIDs, entityID)
+	c.componentLookupMap[entityID] = index
+
+	component := c.at(index)
+	var zero C
+	*component = zero
+
+	if initable, ok := any(component).(interface{ Init() }); ok {
+		initable.Init()
+	}
+
+	return component
+}
+
+// Remove deletes the entity's component, moving the last component into its slot.
+func (c *InlineContainer[C]) Remove(entityID EntityID) {
+	indexToRemove, ok := c.componentLookupMap[entityID]
+	if !ok {
+		return
+	}
+
+	lastIndex := len(c.entityIDs) - 1
+	if lastIndex != indexToRemove {
+		*c.at(indexToRemove) = *c.at(lastIndex)
+		c.entityIDs[indexToRemove] = c.entityIDs[lastIndex]
+
+		c.componentLookupMap[c.entityIDs[indexToRemove]] = indexToRemove
+	}
+
+	c.entityIDs = c.entityIDs[:lastIndex]
+	delete(c.componentLookupMap, entityID)
+}
+
+// Get returns a pointer to the entity's component.
+func (c *InlineContainer[C]) Get(entityID EntityID) (any, bool) {
+	index, ok := c.componentLookupMap[entityID]
+	if !ok {
+		return nil, false
+	}
+
+	return c.at(index), true
+}
+
+func (c *InlineContainer[C]) ids() []EntityID {
+	return c.entityIDs
+}
+
+func (c *InlineContainer[C]) entry(index int) (EntityID, any) {
+	return c.entityIDs[index], c.at(index)
+}
+
+// Count returns the number of stored components.
+func (c *InlineContainer[C]) Count() int {
+	return len(c.entityIDs)
+}
+
+// Entities returns the IDs of the entities with a component, in storage order.
+func (c *InlineContainer[C]) Entities() iter.Seq[EntityID] {
+	return func(yield func(EntityID) bool) {
+		for _, entityID := range c.entityIDs {
+			if !yield(entityID) {
+				break
+			}
+		}
+	}
+}
+
+// All returns the entities and pointers to their components, in storage order.
+func (c *InlineContainer[C]) All() iter.Seq2[EntityID, *C] {
+	return func(yield func(EntityID, *C) bool) {
+		for i, entityID := range c.entityIDs {
+			if !yield(entityID, c.at(i)) {
+				break
+			}
+		}
+	}
+}
+
+// Teardown releases the buffer.
+func (c *InlineContainer[C]) Teardown() {
+	c.buf = nil
+	c.base = 0
+	c.entityIDs = nil
+	c.componentLookupMap = nil
+}
+
+// isPOD reports whether values of typ contain no pointers the garbage collector must track.
+func isPOD(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isPOD(typ.Elem())
+	case reflect.Struct:
+		for i := range typ.NumField() {
+			if !isPOD(typ.Field(i).Type) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
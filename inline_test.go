@@ -0,0 +1,80 @@
+package ecs_test
+
+import (
+	"testing"
+	"unsafe"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+type NameComponent struct {
+	Name string
+}
+
+func TestUseInlineStorage(t *testing.T) {
+	em := ecs.NewEntityManager()
+	require.NoError(t, ecs.UseInlineStorage[TransformComponent](em, 64))
+
+	entities := make([]ecs.EntityID, 0, 200)
+	for i := range 200 {
+		entityID := NewPlayerEntity(t, em)
+		tr := ecs.MustGetComponent[TransformComponent](em, entityID)
+		assert.Zero(t, uintptr(unsafe.Pointer(tr))%64)
+		tr.Position = f64.Vec2{float64(i), float64(-i)}
+
+		entities = append(entities, entityID)
+	}
+
+	for i := 0; i < len(entities); i += 2 {
+		ecs.RemoveComponent[TransformComponent](em, entities[i])
+	}
+
+	assert.Equal(t, 100, ecs.Count(ecs.Query[TransformComponent](em)))
+	for i := 1; i < len(entities); i += 2 {
+		tr, ok := ecs.GetComponent[TransformComponent](em, entities[i])
+		require.True(t, ok)
+		assert.Equal(t, f64.Vec2{float64(i), float64(-i)}, tr.Position)
+	}
+}
+
+func TestUseInlineStorageErrors(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	assert.ErrorIs(t, ecs.UseInlineStorage[NameComponent](em, 0), ecs.ErrNotPOD)
+	assert.Error(t, ecs.UseInlineStorage[CameraComponent](em, 3))
+
+	NewCameraEntity(t, em)
+	assert.ErrorIs(t, ecs.UseInlineStorage[CameraComponent](em, 0), ecs.ErrStorageExists)
+}
+
+func BenchmarkInlineStorage(b *testing.B) {
+	for _, inline := range []bool{false, true} {
+		name := "Pooled"
+		if inline {
+			name = "Inline"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			em := ecs.NewEntityManager()
+			if inline {
+				if err := ecs.UseInlineStorage[TransformComponent](em, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			for range 100_000 {
+				NewPlayerEntity(b, em)
+			}
+
+			for b.Loop() {
+				for entityID := range ecs.Query[TransformComponent](em) {
+					tr, _ := ecs.GetComponent[TransformComponent](em, entityID)
+					tr.Position[0]++
+				}
+			}
+		})
+	}
+}
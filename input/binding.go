@@ -0,0 +1,184 @@
+package input
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Binding is a physical input that can drive an action.
+// Value returns the current strength of the input in the range [0, 1];
+// digital inputs return either 0 or 1.
+type Binding interface {
+	Value() float64
+}
+
+// BindingFunc adapts a function to the Binding interface.
+// It is mostly useful for tests and for custom input sources.
+type BindingFunc func() float64
+
+// Value calls f.
+func (f BindingFunc) Value() float64 {
+	return f()
+}
+
+// Key binds a keyboard key.
+type Key ebiten.Key
+
+// Value returns 1 while the key is held.
+func (k Key) Value() float64 {
+	return boolValue(ebiten.IsKeyPressed(ebiten.Key(k)))
+}
+
+// MouseButton binds a mouse button.
+type MouseButton ebiten.MouseButton
+
+// Value returns 1 while the mouse button is held.
+func (b MouseButton) Value() float64 {
+	return boolValue(ebiten.IsMouseButtonPressed(ebiten.MouseButton(b)))
+}
+
+// GamepadButton binds a button on any connected gamepad with a standard layout.
+type GamepadButton ebiten.StandardGamepadButton
+
+// Value returns 1 while the button is held on any connected gamepad.
+func (b GamepadButton) Value() float64 {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButton(b)) {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// DefaultDeadzone is the deadzone of the analog bindings whose Deadzone is zero.
+const DefaultDeadzone = 0.2
+
+// GamepadAxis binds one direction of an axis on any connected gamepad with a standard layout,
+// e.g. {Axis: ebiten.StandardGamepadAxisLeftStickHorizontal, Direction: -1} for the left stick pushed left.
+type GamepadAxis struct {
+	Axis ebiten.StandardGamepadAxis
+	// Direction is 1 for the positive direction of the axis, right or down, and -1 for the negative one.
+	// Zero is treated as 1.
+	Direction float64
+	// Deadzone is the value below which the axis reads 0, see ApplyDeadzone. Zero is treated as DefaultDeadzone;
+	// a negative deadzone disables it.
+	Deadzone float64
+}
+
+// Value returns how far the axis is pushed in the direction on any connected gamepad, the farthest if several are.
+func (a GamepadAxis) Value() float64 {
+	direction := a.Direction
+	if direction == 0 {
+		direction = 1
+	}
+
+	value := 0.0
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		value = max(value, ApplyDeadzone(direction*ebiten.StandardGamepadAxisValue(id, a.Axis), a.deadzone()))
+	}
+
+	return value
+}
+
+func (a GamepadAxis) deadzone() float64 {
+	if a.Deadzone == 0 {
+		return DefaultDeadzone
+	}
+
+	return max(a.Deadzone, 0)
+}
+
+// ApplyDeadzone returns 0 for values up to deadzone, and rescales the values above it to [0, 1],
+// so that worn sticks resting slightly off-center do not drift and the full range stays reachable.
+func ApplyDeadzone(value, deadzone float64) float64 {
+	if value <= deadzone {
+		return 0
+	}
+
+	if deadzone >= 1 {
+		return 1
+	}
+
+	return min((value-deadzone)/(1-deadzone), 1)
+}
+
+// TouchRegion binds a rectangle of the virtual screen; it is pressed while any touch is inside it.
+type TouchRegion image.Rectangle
+
+// Value returns 1 while a touch is inside the region.
+func (r TouchRegion) Value() float64 {
+	for _, id := range ebiten.AppendTouchIDs(nil) {
+		x, y := TouchPosition(id)
+		if image.Pt(int(math.Floor(x)), int(math.Floor(y))).In(image.Rectangle(r)) {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// TouchCount binds a number of simultaneous touches in a rectangle of the virtual screen, e.g. a two-finger press.
+type TouchCount struct {
+	Region image.Rectangle
+	// Touches is the number of touches needed. Zero is treated as 1.
+	Touches int
+}
+
+// Value returns 1 while at least Touches touches are inside the region.
+func (c TouchCount) Value() float64 {
+	touches := 0
+	for _, id := range ebiten.AppendTouchIDs(nil) {
+		x, y := TouchPosition(id)
+		if image.Pt(int(math.Floor(x)), int(math.Floor(y))).In(c.Region) {
+			touches++
+		}
+	}
+
+	return boolValue(touches >= max(c.Touches, 1))
+}
+
+// AnyOf is pressed while any of its bindings is pressed; its value is the strongest of them.
+type AnyOf []Binding
+
+// Value returns the maximum value of the bindings.
+func (a AnyOf) Value() float64 {
+	value := 0.0
+	for _, binding := range a {
+		value = max(value, binding.Value())
+	}
+
+	return value
+}
+
+// AllOf is pressed only while all of its bindings are pressed, e.g. Ctrl+S; its value is the weakest of them.
+type AllOf []Binding
+
+// Value returns the minimum value of the bindings.
+func (a AllOf) Value() float64 {
+	if len(a) == 0 {
+		return 0
+	}
+
+	return slices.MinFunc(a, func(x, y Binding) int {
+		switch vx, vy := x.Value(), y.Value(); {
+		case vx < vy:
+			return -1
+		case vx > vy:
+			return 1
+		default:
+			return 0
+		}
+	}).Value()
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
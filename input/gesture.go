@@ -0,0 +1,273 @@
+package input
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Tap is passed to the GestureSystem's handler when a touch is lifted quickly without moving.
+type Tap struct {
+	X, Y float64
+}
+
+// LongPress is passed to the GestureSystem's handler when a touch is held still for the long press duration.
+// The touch does not produce a Tap or a Swipe afterwards.
+type LongPress struct {
+	X, Y     float64
+	Duration time.Duration
+}
+
+// Swipe is passed to the GestureSystem's handler when a touch is lifted after moving quickly.
+type Swipe struct {
+	StartX, StartY float64
+	X, Y           float64
+	// VelocityX and VelocityY are the velocity of the touch when lifted, in virtual pixels per second.
+	VelocityX, VelocityY float64
+}
+
+// Pinch is passed to the GestureSystem's handler on every update two touches move relative to each other.
+// The touches do not produce any other gesture.
+type Pinch struct {
+	// X and Y are the point halfway between the touches.
+	X, Y float64
+	// Scale is the distance between the touches divided by their distance when the pinch started:
+	// above 1 when spreading, e.g. to zoom in.
+	Scale float64
+	// Velocity is the rate of change of Scale, per second.
+	Velocity float64
+}
+
+// GestureThresholds tune the recognition of gestures. Distances are in virtual pixels.
+type GestureThresholds struct {
+	// Slop is the distance a touch may move and still be a Tap or a LongPress.
+	Slop float64
+	// TapDuration is the longest touch recognized as a Tap.
+	TapDuration time.Duration
+	// LongPressDuration is how long a touch must be held still to be a LongPress.
+	LongPressDuration time.Duration
+	// SwipeDistance and SwipeVelocity are the minimum distance and speed of a Swipe.
+	SwipeDistance, SwipeVelocity float64
+}
+
+// DefaultGestureThresholds are the thresholds of the GestureSystem unless changed with SetThresholds.
+var DefaultGestureThresholds = GestureThresholds{
+	Slop:              10,
+	TapDuration:       250 * time.Millisecond,
+	LongPressDuration: 500 * time.Millisecond,
+	SwipeDistance:     30,
+	SwipeVelocity:     200,
+}
+
+// gestureTouch is the state of a touch followed by the GestureSystem.
+type gestureTouch struct {
+	Touch
+	startX, startY       float64
+	start                time.Duration
+	velocityX, velocityY float64
+	moved                bool
+	// consumed touches have been part of a LongPress or a Pinch, and produce no other gesture.
+	consumed bool
+	seen     bool
+}
+
+// GestureSystem recognizes taps, long presses, swipes and pinches from the touches, and calls the handler
+// of each gesture, so that mobile games do not need their own touch state machines. Like the input System,
+// it should run before the systems reacting to the gestures.
+type GestureSystem struct {
+	*ecs.BaseSystem
+
+	thresholds GestureThresholds
+	source     func(dst []Touch) []Touch
+
+	onTap       func(Tap)
+	onLongPress func(LongPress)
+	onSwipe     func(Swipe)
+	onPinch     func(Pinch)
+
+	now           time.Duration
+	touches       []Touch
+	tracked       []*gestureTouch
+	pinchDistance float64
+	pinchScale    float64
+}
+
+// NewGestureSystem creates a new GestureSystem with the given ID and priority, with DefaultGestureThresholds,
+// reading the touches with AppendTouches.
+func NewGestureSystem(id ecs.SystemID, priority int) *GestureSystem {
+	return &GestureSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		thresholds: DefaultGestureThresholds,
+		source:     AppendTouches,
+	}
+}
+
+// SetThresholds sets the thresholds of the recognition.
+func (s *GestureSystem) SetThresholds(thresholds GestureThresholds) {
+	s.thresholds = thresholds
+}
+
+// SetTouchSource replaces the source of the touches, AppendTouches by default.
+func (s *GestureSystem) SetTouchSource(source func(dst []Touch) []Touch) {
+	s.source = source
+}
+
+// OnTap sets the handler called for every Tap. A nil handler removes it.
+func (s *GestureSystem) OnTap(handler func(Tap)) {
+	s.onTap = handler
+	s.updateAccess()
+}
+
+// OnLongPress sets the handler called for every LongPress. A nil handler removes it.
+func (s *GestureSystem) OnLongPress(handler func(LongPress)) {
+	s.onLongPress = handler
+	s.updateAccess()
+}
+
+// OnSwipe sets the handler called for every Swipe. A nil handler removes it.
+func (s *GestureSystem) OnSwipe(handler func(Swipe)) {
+	s.onSwipe = handler
+	s.updateAccess()
+}
+
+// OnPinch sets the handler called for every Pinch. A nil handler removes it.
+func (s *GestureSystem) OnPinch(handler func(Pinch)) {
+	s.onPinch = handler
+	s.updateAccess()
+}
+
+func (s *GestureSystem) updateAccess() {
+	access := s.Access()
+	access.Produces = nil
+	if s.onTap != nil {
+		access.Produces = append(access.Produces, ecs.Types(Tap{})...)
+	}
+	if s.onLongPress != nil {
+		access.Produces = append(access.Produces, ecs.Types(LongPress{})...)
+	}
+	if s.onSwipe != nil {
+		access.Produces = append(access.Produces, ecs.Types(Swipe{})...)
+	}
+	if s.onPinch != nil {
+		access.Produces = append(access.Produces, ecs.Types(Pinch{})...)
+	}
+	s.SetAccess(access)
+}
+
+// Update follows the touches with the game's delta time and calls the handlers of the recognized gestures.
+func (s *GestureSystem) Update() error {
+	dt := tickDuration()
+	if t := s.Time(); t != nil {
+		dt = t.DeltaDuration()
+	}
+	s.now += dt
+
+	s.touches = s.source(s.touches[:0])
+	for _, tracked := range s.tracked {
+		tracked.seen = false
+	}
+
+	for _, touch := range s.touches {
+		s.follow(touch, dt)
+	}
+
+	s.tracked = slices.DeleteFunc(s.tracked, func(tracked *gestureTouch) bool {
+		if !tracked.seen {
+			s.lift(tracked)
+		}
+
+		return !tracked.seen
+	})
+
+	if len(s.tracked) == 2 {
+		s.pinch(dt)
+	} else {
+		s.pinchDistance = 0
+	}
+
+	for _, tracked := range s.tracked {
+		if len(s.tracked) == 1 && !tracked.moved && !tracked.consumed && s.now-tracked.start >= s.thresholds.LongPressDuration {
+			tracked.consumed = true
+			if s.onLongPress != nil {
+				s.onLongPress(LongPress{X: tracked.X, Y: tracked.Y, Duration: s.now - tracked.start})
+			}
+		}
+	}
+
+	return nil
+}
+
+// follow moves the tracked touch, or starts tracking it.
+func (s *GestureSystem) follow(touch Touch, dt time.Duration) {
+	index := slices.IndexFunc(s.tracked, func(tracked *gestureTouch) bool { return tracked.ID == touch.ID })
+	if index < 0 {
+		s.tracked = append(s.tracked, &gestureTouch{Touch: touch, startX: touch.X, startY: touch.Y, start: s.now, seen: true})
+		return
+	}
+
+	tracked := s.tracked[index]
+	if seconds := dt.Seconds(); seconds > 0 {
+		// The velocity is smoothed over the last updates, so that a single jittery update does not decide a swipe.
+		tracked.velocityX = (tracked.velocityX + (touch.X-tracked.X)/seconds) / 2
+		tracked.velocityY = (tracked.velocityY + (touch.Y-tracked.Y)/seconds) / 2
+	}
+
+	tracked.Touch = touch
+	tracked.seen = true
+	if math.Hypot(touch.X-tracked.startX, touch.Y-tracked.startY) > s.thresholds.Slop {
+		tracked.moved = true
+	}
+}
+
+// lift recognizes the Tap or Swipe of a lifted touch.
+func (s *GestureSystem) lift(tracked *gestureTouch) {
+	if tracked.consumed {
+		return
+	}
+
+	if !tracked.moved {
+		if s.now-tracked.start <= s.thresholds.TapDuration && s.onTap != nil {
+			s.onTap(Tap{X: tracked.X, Y: tracked.Y})
+		}
+		return
+	}
+
+	distance := math.Hypot(tracked.X-tracked.startX, tracked.Y-tracked.startY)
+	speed := math.Hypot(tracked.velocityX, tracked.velocityY)
+	if distance >= s.thresholds.SwipeDistance && speed >= s.thresholds.SwipeVelocity && s.onSwipe != nil {
+		s.onSwipe(Swipe{
+			StartX: tracked.startX, StartY: tracked.startY,
+			X: tracked.X, Y: tracked.Y,
+			VelocityX: tracked.velocityX, VelocityY: tracked.velocityY,
+		})
+	}
+}
+
+// pinch consumes the two tracked touches and reports their change of distance.
+func (s *GestureSystem) pinch(dt time.Duration) {
+	a, b := s.tracked[0], s.tracked[1]
+	a.consumed, b.consumed = true, true
+
+	distance := math.Hypot(a.X-b.X, a.Y-b.Y)
+	if s.pinchDistance == 0 {
+		s.pinchDistance, s.pinchScale = max(distance, 1), 1
+		return
+	}
+
+	scale := distance / s.pinchDistance
+	if scale == s.pinchScale {
+		return
+	}
+
+	velocity := 0.0
+	if seconds := dt.Seconds(); seconds > 0 {
+		velocity = (scale - s.pinchScale) / seconds
+	}
+	s.pinchScale = scale
+
+	if s.onPinch != nil {
+		s.onPinch(Pinch{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2, Scale: scale, Velocity: velocity})
+	}
+}
@@ -0,0 +1,242 @@
+// Package input maps physical inputs (keys, mouse and gamepad buttons, gamepad axes, touch regions
+// and on-screen TouchControls) to named actions such as "jump" or "fire".
+//
+// Actions are declared once with Bind, updated every tick by the input System
+// and queried anywhere with Pressed, JustPressed and JustReleased:
+//
+//	input.Bind("jump", input.Key(ebiten.KeySpace), input.GamepadButton(ebiten.StandardGamepadButtonRightBottom))
+//	sm.Add(input.NewSystem(inputSystemID, -100, nil))
+//
+//	if input.JustPressed("jump") { ... }
+package input
+
+import (
+	"iter"
+	"maps"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DefaultThreshold is the value from which an action is considered pressed: values at or above it press it.
+const DefaultThreshold = 0.5
+
+// Default is the Actions used by the package-level functions and by a System created with nil actions.
+var Default = NewActions()
+
+type actionState struct {
+	bindings []Binding
+	buffer   time.Duration
+
+	value       float64
+	pressed     bool
+	prevPressed bool
+	heldTicks   int
+
+	// buffered is set when the action is pressed, until it is consumed or pressedAt is older than the buffer.
+	buffered  bool
+	pressedAt time.Duration
+}
+
+// Actions holds a set of named actions and their current state.
+type Actions struct {
+	actions   map[string]*actionState
+	sequences map[string]*sequenceState
+	threshold float64
+
+	// now is the time elapsed over all the updates.
+	now time.Duration
+}
+
+// NewActions creates an empty set of actions.
+func NewActions() *Actions {
+	return &Actions{
+		actions:   make(map[string]*actionState),
+		sequences: make(map[string]*sequenceState),
+		threshold: DefaultThreshold,
+	}
+}
+
+// SetThreshold sets the value from which actions are considered pressed, inclusive.
+func (a *Actions) SetThreshold(threshold float64) {
+	a.threshold = threshold
+}
+
+// Bind adds bindings to the named action, declaring the action if needed.
+func (a *Actions) Bind(action string, bindings ...Binding) {
+	state := a.state(action)
+	state.bindings = append(state.bindings, bindings...)
+}
+
+// SetBuffer sets how long a press of the action stays Buffered, declaring the action if needed,
+// e.g. so that a jump pressed shortly before landing still jumps.
+func (a *Actions) SetBuffer(action string, window time.Duration) {
+	a.state(action).buffer = window
+}
+
+func (a *Actions) state(action string) *actionState {
+	state, ok := a.actions[action]
+	if !ok {
+		state = &actionState{}
+		a.actions[action] = state
+	}
+
+	return state
+}
+
+// Unbind removes the action and all its bindings.
+func (a *Actions) Unbind(action string) {
+	delete(a.actions, action)
+}
+
+// Actions returns the names of the declared actions.
+func (a *Actions) Actions() iter.Seq[string] {
+	return maps.Keys(a.actions)
+}
+
+// Update polls the bindings of every action, like UpdateDelta with a tick of 1/ebiten.TPS() seconds,
+// or of a frame with ebiten.SyncWithFPS. Within a game, prefer UpdateDelta with the game's delta time,
+// as the input System does.
+func (a *Actions) Update() {
+	a.UpdateDelta(tickDuration())
+}
+
+// tickDuration returns the duration of a tick at ebiten.TPS(), or of a frame with ebiten.SyncWithFPS,
+// for updates without a game.
+func tickDuration() time.Duration {
+	tps := float64(ebiten.TPS())
+	if tps <= 0 {
+		tps = ebiten.ActualFPS()
+	}
+
+	if tps <= 0 {
+		tps = ebiten.DefaultTPS
+	}
+
+	return time.Duration(float64(time.Second) / tps)
+}
+
+// UpdateDelta polls the bindings of every action and detects the sequences, dt after the previous update.
+// It is called once per tick by the input System, with the game's delta time.
+func (a *Actions) UpdateDelta(dt time.Duration) {
+	a.now += dt
+
+	for _, state := range a.actions {
+		value := 0.0
+		for _, binding := range state.bindings {
+			value = max(value, binding.Value())
+		}
+
+		state.value = value
+		state.prevPressed = state.pressed
+		state.pressed = value >= a.threshold
+
+		if state.pressed {
+			state.heldTicks++
+		} else {
+			state.heldTicks = 0
+		}
+
+		if state.pressed && !state.prevPressed {
+			state.buffered, state.pressedAt = true, a.now
+		} else if a.now-state.pressedAt > state.buffer {
+			state.buffered = false
+		}
+	}
+
+	for _, sequence := range a.sequences {
+		sequence.update(a)
+	}
+}
+
+// Pressed reports whether the action is currently held.
+func (a *Actions) Pressed(action string) bool {
+	state, ok := a.actions[action]
+	return ok && state.pressed
+}
+
+// JustPressed reports whether the action started being held this tick.
+func (a *Actions) JustPressed(action string) bool {
+	state, ok := a.actions[action]
+	return ok && state.pressed && !state.prevPressed
+}
+
+// JustReleased reports whether the action stopped being held this tick.
+func (a *Actions) JustReleased(action string) bool {
+	state, ok := a.actions[action]
+	return ok && !state.pressed && state.prevPressed
+}
+
+// Value returns the current strength of the action in the range [0, 1].
+func (a *Actions) Value(action string) float64 {
+	state, ok := a.actions[action]
+	if !ok {
+		return 0
+	}
+
+	return state.value
+}
+
+// Buffered reports whether the action was pressed within its buffer window, see SetBuffer, and the press
+// has not been consumed. Without a buffer, it is the same as JustPressed until consumed.
+func (a *Actions) Buffered(action string) bool {
+	state, ok := a.actions[action]
+	return ok && state.buffered
+}
+
+// Consume clears the buffered press of the action, so that it triggers once.
+func (a *Actions) Consume(action string) {
+	if state, ok := a.actions[action]; ok {
+		state.buffered = false
+	}
+}
+
+// HeldTicks returns the number of consecutive ticks the action has been held, or 0 if it is released.
+func (a *Actions) HeldTicks(action string) int {
+	state, ok := a.actions[action]
+	if !ok {
+		return 0
+	}
+
+	return state.heldTicks
+}
+
+// Bind adds bindings to the named action of the Default actions.
+func Bind(action string, bindings ...Binding) {
+	Default.Bind(action, bindings...)
+}
+
+// Pressed reports whether the action of the Default actions is currently held.
+func Pressed(action string) bool {
+	return Default.Pressed(action)
+}
+
+// JustPressed reports whether the action of the Default actions started being held this tick.
+func JustPressed(action string) bool {
+	return Default.JustPressed(action)
+}
+
+// JustReleased reports whether the action of the Default actions stopped being held this tick.
+func JustReleased(action string) bool {
+	return Default.JustReleased(action)
+}
+
+// Buffered reports whether the action of the Default actions was pressed within its buffer window.
+func Buffered(action string) bool {
+	return Default.Buffered(action)
+}
+
+// Consume clears the buffered press of the action of the Default actions.
+func Consume(action string) {
+	Default.Consume(action)
+}
+
+// Performed reports whether the sequence of the Default actions was completed this tick.
+func Performed(sequence string) bool {
+	return Default.Performed(sequence)
+}
+
+// Value returns the current strength of the action of the Default actions.
+func Value(action string) float64 {
+	return Default.Value(action)
+}
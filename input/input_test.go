@@ -0,0 +1,276 @@
+package input_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/input"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestActions(t *testing.T) {
+	held := false
+	key := input.BindingFunc(func() float64 {
+		if held {
+			return 1
+		}
+		return 0
+	})
+
+	actions := input.NewActions()
+	actions.Bind("jump", key)
+
+	actions.Update()
+	assert.False(t, actions.Pressed("jump"))
+	assert.False(t, actions.JustPressed("jump"))
+
+	held = true
+	actions.Update()
+	assert.True(t, actions.Pressed("jump"))
+	assert.True(t, actions.JustPressed("jump"))
+	assert.Equal(t, 1, actions.HeldTicks("jump"))
+
+	actions.Update()
+	assert.True(t, actions.Pressed("jump"))
+	assert.False(t, actions.JustPressed("jump"))
+	assert.Equal(t, 2, actions.HeldTicks("jump"))
+
+	held = false
+	actions.Update()
+	assert.False(t, actions.Pressed("jump"))
+	assert.True(t, actions.JustReleased("jump"))
+	assert.Equal(t, 0, actions.HeldTicks("jump"))
+
+	assert.False(t, actions.Pressed("fire"))
+}
+
+func TestActionsSyncWithFPS(t *testing.T) {
+	ebiten.SetTPS(ebiten.SyncWithFPS)
+	defer ebiten.SetTPS(ebiten.DefaultTPS)
+
+	held := true
+	actions := input.NewActions()
+	actions.Bind("jump", input.BindingFunc(func() float64 {
+		if held {
+			return 1
+		}
+		return 0
+	}))
+	actions.SetBuffer("jump", 100*time.Millisecond)
+
+	actions.Update()
+	held = false
+	assert.True(t, actions.Buffered("jump"))
+
+	for range 10 {
+		actions.Update()
+	}
+	assert.False(t, actions.Buffered("jump"), "the buffer expires with a positive tick duration")
+}
+
+func TestCombinators(t *testing.T) {
+	on := input.BindingFunc(func() float64 { return 1 })
+	half := input.BindingFunc(func() float64 { return 0.25 })
+
+	assert.Equal(t, 1.0, input.AnyOf{on, half}.Value())
+	assert.Equal(t, 0.25, input.AllOf{on, half}.Value())
+	assert.Equal(t, 0.0, input.AllOf{}.Value())
+}
+
+func TestDeadzone(t *testing.T) {
+	assert.Equal(t, 0.0, input.ApplyDeadzone(0.1, 0.2))
+	assert.InDelta(t, 0.5, input.ApplyDeadzone(0.6, 0.2), 1e-9)
+	assert.Equal(t, 1.0, input.ApplyDeadzone(1.5, 0.2))
+
+	axis := input.GamepadAxis{Axis: ebiten.StandardGamepadAxisLeftStickHorizontal, Direction: -1}
+	assert.Equal(t, 0.0, axis.Value(), "axes read 0 without gamepads")
+}
+
+func TestTouchControls(t *testing.T) {
+	var touches []input.Touch
+	controls := input.NewTouchControls(ecs.NextID(), -200)
+	controls.SetTouchSource(func(dst []input.Touch) []input.Touch { return append(dst, touches...) })
+
+	stick := controls.AddJoystick(&input.VirtualJoystick{Center: f64.Vec2{50, 200}, Radius: 40})
+	fire := controls.AddButton(&input.VirtualButton{Center: f64.Vec2{280, 200}, Radius: 20})
+
+	actions := input.NewActions()
+	actions.Bind("right", stick.Right())
+	actions.Bind("left", stick.Left())
+	actions.Bind("fire", fire)
+
+	update := func() {
+		require.NoError(t, controls.Update())
+		actions.Update()
+	}
+
+	touches = []input.Touch{{ID: 1, X: 90, Y: 200}, {ID: 2, X: 285, Y: 205}}
+	update()
+	assert.True(t, stick.Active())
+	x, y := stick.Axis()
+	assert.Equal(t, [2]float64{1, 0}, [2]float64{x, y})
+	assert.True(t, actions.Pressed("right"))
+	assert.False(t, actions.Pressed("left"))
+	assert.True(t, actions.JustPressed("fire"))
+
+	touches = []input.Touch{{ID: 1, X: 0, Y: 200}}
+	update()
+	assert.Equal(t, 1.0, actions.Value("left"), "the stick follows its touch outside of the base")
+	assert.True(t, actions.JustReleased("fire"))
+
+	touches = []input.Touch{{ID: 1, X: 280, Y: 200}}
+	update()
+	assert.Zero(t, fire.Value(), "touches driving a joystick do not press buttons")
+
+	touches = []input.Touch{{ID: 3, X: 52, Y: 200}}
+	update()
+	x, _ = stick.Axis()
+	assert.Zero(t, x, "within the deadzone the stick reads 0")
+
+	touches = nil
+	update()
+	assert.False(t, stick.Active())
+
+	controls.Draw(ebiten.NewImage(320, 240))
+}
+
+func TestBuffer(t *testing.T) {
+	held := false
+	actions := input.NewActions()
+	actions.Bind("jump", input.BindingFunc(func() float64 { return boolToValue(held) }))
+	actions.SetBuffer("jump", 100*time.Millisecond)
+
+	tick := 40 * time.Millisecond
+	held = true
+	actions.UpdateDelta(tick)
+	assert.True(t, actions.Buffered("jump"))
+
+	held = false
+	actions.UpdateDelta(tick)
+	actions.UpdateDelta(tick)
+	assert.True(t, actions.Buffered("jump"), "presses stay buffered within the window")
+
+	actions.UpdateDelta(tick)
+	assert.False(t, actions.Buffered("jump"), "presses expire after the window")
+
+	held = true
+	actions.UpdateDelta(tick)
+	actions.Consume("jump")
+	assert.False(t, actions.Buffered("jump"), "consumed presses trigger once")
+
+	actions.SetBuffer("jump", 0)
+	held = false
+	actions.UpdateDelta(tick)
+	held = true
+	actions.UpdateDelta(tick)
+	assert.True(t, actions.Buffered("jump"))
+	actions.UpdateDelta(tick)
+	assert.False(t, actions.Buffered("jump"), "without a buffer, only the tick of the press counts")
+}
+
+func TestSequence(t *testing.T) {
+	held := map[string]bool{}
+	actions := input.NewActions()
+	for _, action := range []string{"down", "forward", "punch"} {
+		actions.Bind(action, input.BindingFunc(func() float64 { return boolToValue(held[action]) }))
+	}
+	actions.BindSequence("fireball", input.Sequence{
+		Steps:  [][]string{{"down"}, {"down", "forward"}, {"forward", "punch"}},
+		Window: 100 * time.Millisecond,
+	})
+
+	tick := 20 * time.Millisecond
+	press := func(names ...string) {
+		clear(held)
+		for _, name := range names {
+			held[name] = true
+		}
+		actions.UpdateDelta(tick)
+	}
+
+	press("down")
+	press("down", "forward")
+	press("forward")
+	assert.False(t, actions.Performed("fireball"))
+	press("forward", "punch")
+	assert.True(t, actions.Performed("fireball"))
+	press()
+	assert.False(t, actions.Performed("fireball"), "sequences are performed for one tick")
+
+	press("down")
+	press("down", "forward")
+	for range 6 {
+		press("forward")
+	}
+	press("forward", "punch")
+	assert.False(t, actions.Performed("fireball"), "steps must follow within the window")
+
+	press("down", "forward")
+	assert.False(t, actions.Performed("fireball"), "steps must be performed in order")
+}
+
+func boolToValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestGestureSystem(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+
+	var touches []input.Touch
+	gestures := input.NewGestureSystem(ecs.NextID(), -100)
+	gestures.SetTouchSource(func(dst []input.Touch) []input.Touch { return append(dst, touches...) })
+	sm.Add(gestures)
+
+	var taps []input.Tap
+	var swipes []input.Swipe
+	var pinches []input.Pinch
+	var longPresses []input.LongPress
+	gestures.OnTap(func(tap input.Tap) { taps = append(taps, tap) })
+	gestures.OnSwipe(func(swipe input.Swipe) { swipes = append(swipes, swipe) })
+	gestures.OnPinch(func(pinch input.Pinch) { pinches = append(pinches, pinch) })
+	gestures.OnLongPress(func(longPress input.LongPress) { longPresses = append(longPresses, longPress) })
+
+	step := func(next ...input.Touch) {
+		touches = next
+		game.Time().Advance(0.05)
+		require.NoError(t, sm.Update())
+	}
+
+	step(input.Touch{ID: 1, X: 100, Y: 100})
+	step(input.Touch{ID: 1, X: 103, Y: 100})
+	step()
+	assert.Equal(t, []input.Tap{{X: 103, Y: 100}}, taps)
+
+	step(input.Touch{ID: 2, X: 100, Y: 100})
+	step(input.Touch{ID: 2, X: 140, Y: 100})
+	step(input.Touch{ID: 2, X: 180, Y: 100})
+	step()
+	require.Len(t, swipes, 1)
+	assert.Equal(t, 180.0, swipes[0].X)
+	assert.Greater(t, swipes[0].VelocityX, 200.0)
+	assert.Len(t, taps, 1, "swipes are not taps")
+
+	for range 12 {
+		step(input.Touch{ID: 3, X: 50, Y: 50})
+	}
+	step()
+	require.Len(t, longPresses, 1)
+	assert.GreaterOrEqual(t, longPresses[0].Duration, 500*time.Millisecond)
+	assert.Len(t, taps, 1, "long presses are not taps")
+
+	step(input.Touch{ID: 4, X: 100, Y: 100}, input.Touch{ID: 5, X: 200, Y: 100})
+	step(input.Touch{ID: 4, X: 50, Y: 100}, input.Touch{ID: 5, X: 250, Y: 100})
+	step()
+	require.Len(t, pinches, 1)
+	assert.Equal(t, input.Pinch{X: 150, Y: 100, Scale: 2, Velocity: 20}, pinches[0])
+	assert.Len(t, taps, 1, "pinches are not taps")
+	assert.Len(t, swipes, 1, "pinches are not swipes")
+}
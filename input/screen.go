@@ -0,0 +1,30 @@
+package input
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// screen converts window positions to the virtual resolution of the game. It is set by the System.
+var screen *ecs.Screen
+
+// CursorPosition returns the position of the mouse cursor on the virtual screen of the game,
+// see ecs.Screen, or in the window until a System has run.
+func CursorPosition() (x, y float64) {
+	cx, cy := ebiten.CursorPosition()
+	return toVirtual(cx, cy)
+}
+
+// TouchPosition returns the position of the touch on the virtual screen of the game, like CursorPosition.
+func TouchPosition(id ebiten.TouchID) (x, y float64) {
+	tx, ty := ebiten.TouchPosition(id)
+	return toVirtual(tx, ty)
+}
+
+func toVirtual(x, y int) (float64, float64) {
+	if screen == nil {
+		return float64(x), float64(y)
+	}
+
+	return screen.ScreenToVirtual(float64(x), float64(y))
+}
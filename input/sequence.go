@@ -0,0 +1,82 @@
+package input
+
+import "time"
+
+// Sequence is a series of steps performed in order, such as a fighting game move:
+//
+//	input.Sequence{Steps: [][]string{{"down"}, {"down", "forward"}, {"forward", "punch"}}, Window: 200 * time.Millisecond}
+//
+// Each step is a chord of actions held together, and is performed on the tick the last of them is pressed.
+type Sequence struct {
+	Steps [][]string
+	// Window is the longest time allowed between two steps before the sequence starts over.
+	Window time.Duration
+}
+
+type sequenceState struct {
+	sequence Sequence
+
+	next      int
+	steppedAt time.Duration
+	performed bool
+}
+
+// BindSequence declares the named sequence of actions, replacing any sequence of the same name.
+// It is detected every update; see Performed.
+func (a *Actions) BindSequence(name string, sequence Sequence) {
+	a.sequences[name] = &sequenceState{sequence: sequence}
+}
+
+// UnbindSequence removes the named sequence.
+func (a *Actions) UnbindSequence(name string) {
+	delete(a.sequences, name)
+}
+
+// Performed reports whether the named sequence was completed this tick.
+func (a *Actions) Performed(name string) bool {
+	state, ok := a.sequences[name]
+	return ok && state.performed
+}
+
+// update advances the sequence by at most one step. A step that does not follow in time restarts it, and
+// so does its first step performed out of order.
+func (s *sequenceState) update(a *Actions) {
+	s.performed = false
+	steps := s.sequence.Steps
+	if len(steps) == 0 {
+		return
+	}
+
+	if s.next > 0 && a.now-s.steppedAt > s.sequence.Window {
+		s.next = 0
+	}
+
+	switch {
+	case chordPerformed(a, steps[s.next]):
+		s.next++
+	case s.next > 0 && chordPerformed(a, steps[0]):
+		s.next = 1
+	default:
+		return
+	}
+
+	s.steppedAt = a.now
+	if s.next == len(steps) {
+		s.performed = true
+		s.next = 0
+	}
+}
+
+// chordPerformed reports whether all the actions are held and one of them was just pressed.
+func chordPerformed(a *Actions, actions []string) bool {
+	justPressed := false
+	for _, action := range actions {
+		if !a.Pressed(action) {
+			return false
+		}
+
+		justPressed = justPressed || a.JustPressed(action)
+	}
+
+	return justPressed
+}
@@ -0,0 +1,43 @@
+package input
+
+import ecs "github.com/samix73/ebiten-ecs"
+
+// System updates a set of Actions once per tick.
+// It should run before any system that reads actions, i.e. with a low priority.
+type System struct {
+	*ecs.BaseSystem
+
+	actions *Actions
+}
+
+// NewSystem creates a new input System updating actions, or Default if actions is nil.
+func NewSystem(id ecs.SystemID, priority int, actions *Actions) *System {
+	if actions == nil {
+		actions = Default
+	}
+
+	return &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		actions:    actions,
+	}
+}
+
+// Actions returns the actions updated by the system.
+func (s *System) Actions() *Actions {
+	return s.actions
+}
+
+// Update polls the bindings of all actions, with the positions converted to the virtual screen of the game,
+// and times the buffers and sequences with the game's delta time.
+func (s *System) Update() error {
+	game := s.Game()
+	if game == nil {
+		s.actions.Update()
+		return nil
+	}
+
+	screen = game.Screen()
+	s.actions.UpdateDelta(game.Time().DeltaDuration())
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package input
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	ecs "github.com/samix73/ebiten-ecs"
+	"golang.org/x/image/math/f64"
+)
+
+var _ ecs.DrawableSystem = (*TouchControls)(nil)
+
+// Default colors of the on-screen controls.
+var (
+	ControlColor       = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x60}
+	ControlActiveColor = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xa0}
+)
+
+// Touch is a touch on the virtual screen.
+type Touch struct {
+	ID   ebiten.TouchID
+	X, Y float64
+}
+
+// AppendTouches appends the current touches, in virtual screen coordinates, to dst and returns the extended slice.
+// It is the default touch source of the TouchControls.
+func AppendTouches(dst []Touch) []Touch {
+	for _, id := range ebiten.AppendTouchIDs(nil) {
+		x, y := TouchPosition(id)
+		dst = append(dst, Touch{ID: id, X: x, Y: y})
+	}
+
+	return dst
+}
+
+// VirtualJoystick is an on-screen stick: a touch starting inside its base drives it until it is lifted,
+// even if it slides out of the base. Its directions are bindings, so it drives the same actions as a gamepad:
+//
+//	input.Bind("left", input.Key(ebiten.KeyA), stick.Left())
+type VirtualJoystick struct {
+	// Center and Radius are the base of the stick, in virtual screen coordinates.
+	Center f64.Vec2
+	Radius float64
+	// Deadzone is the fraction of Radius within which the stick reads 0, see ApplyDeadzone.
+	// Zero is treated as DefaultDeadzone.
+	Deadzone float64
+
+	touch  ebiten.TouchID
+	active bool
+	x, y   float64
+}
+
+// Axis returns the position of the stick, each coordinate in [-1, 1] with the deadzone applied.
+func (j *VirtualJoystick) Axis() (x, y float64) {
+	return j.x, j.y
+}
+
+// Active reports whether a touch is driving the stick.
+func (j *VirtualJoystick) Active() bool {
+	return j.active
+}
+
+// Left returns the binding of the stick pushed left.
+func (j *VirtualJoystick) Left() Binding {
+	return BindingFunc(func() float64 { return max(-j.x, 0) })
+}
+
+// Right returns the binding of the stick pushed right.
+func (j *VirtualJoystick) Right() Binding {
+	return BindingFunc(func() float64 { return max(j.x, 0) })
+}
+
+// Up returns the binding of the stick pushed up.
+func (j *VirtualJoystick) Up() Binding {
+	return BindingFunc(func() float64 { return max(-j.y, 0) })
+}
+
+// Down returns the binding of the stick pushed down.
+func (j *VirtualJoystick) Down() Binding {
+	return BindingFunc(func() float64 { return max(j.y, 0) })
+}
+
+// move sets the stick position from a touch at (x, y).
+func (j *VirtualJoystick) move(x, y float64) {
+	dx, dy := (x-j.Center[0])/j.Radius, (y-j.Center[1])/j.Radius
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		j.x, j.y = 0, 0
+		return
+	}
+
+	deadzone := j.Deadzone
+	if deadzone == 0 {
+		deadzone = DefaultDeadzone
+	}
+
+	scale := ApplyDeadzone(length, deadzone) / length
+	j.x, j.y = dx*scale, dy*scale
+}
+
+func (j *VirtualJoystick) release() {
+	j.active = false
+	j.x, j.y = 0, 0
+}
+
+// VirtualButton is an on-screen button, pressed while a touch not driving a joystick is inside it.
+// It is a Binding.
+type VirtualButton struct {
+	// Center and Radius are the circle of the button, in virtual screen coordinates.
+	Center f64.Vec2
+	Radius float64
+
+	pressed bool
+}
+
+// Value returns 1 while the button is pressed.
+func (b *VirtualButton) Value() float64 {
+	return boolValue(b.pressed)
+}
+
+// TouchControls is a system updating and drawing on-screen joysticks and buttons for touch screens.
+// It must run before the input System, i.e. with a lower priority, so that actions see the controls
+// of the same tick, and be drawn over the game.
+type TouchControls struct {
+	*ecs.BaseSystem
+
+	joysticks []*VirtualJoystick
+	buttons   []*VirtualButton
+	source    func(dst []Touch) []Touch
+	visible   bool
+
+	touches []Touch
+}
+
+// NewTouchControls creates a visible TouchControls system without controls, reading the touches with AppendTouches.
+func NewTouchControls(id ecs.SystemID, priority int) *TouchControls {
+	return &TouchControls{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		source:     AppendTouches,
+		visible:    true,
+	}
+}
+
+// AddJoystick adds the joystick to the controls and returns it.
+func (c *TouchControls) AddJoystick(joystick *VirtualJoystick) *VirtualJoystick {
+	c.joysticks = append(c.joysticks, joystick)
+	return joystick
+}
+
+// AddButton adds the button to the controls and returns it.
+func (c *TouchControls) AddButton(button *VirtualButton) *VirtualButton {
+	c.buttons = append(c.buttons, button)
+	return button
+}
+
+// SetTouchSource replaces the source of the touches, AppendTouches by default, e.g. to drive the controls
+// with the mouse on desktop or with recorded input.
+func (c *TouchControls) SetTouchSource(source func(dst []Touch) []Touch) {
+	c.source = source
+}
+
+// SetVisible shows or hides the controls, e.g. when no touch screen is used. Hidden controls still work.
+func (c *TouchControls) SetVisible(visible bool) {
+	c.visible = visible
+}
+
+// Update moves the joysticks with their touches and presses the buttons under the other touches.
+func (c *TouchControls) Update() error {
+	c.touches = c.source(c.touches[:0])
+
+	for _, joystick := range c.joysticks {
+		if !joystick.active {
+			continue
+		}
+
+		if touch, ok := c.find(joystick.touch); ok {
+			joystick.move(touch.X, touch.Y)
+		} else {
+			joystick.release()
+		}
+	}
+
+	for _, touch := range c.touches {
+		if c.claimed(touch.ID) {
+			continue
+		}
+
+		for _, joystick := range c.joysticks {
+			if !joystick.active && math.Hypot(touch.X-joystick.Center[0], touch.Y-joystick.Center[1]) <= joystick.Radius {
+				joystick.touch, joystick.active = touch.ID, true
+				joystick.move(touch.X, touch.Y)
+				break
+			}
+		}
+	}
+
+	for _, button := range c.buttons {
+		button.pressed = false
+		for _, touch := range c.touches {
+			if !c.claimed(touch.ID) && math.Hypot(touch.X-button.Center[0], touch.Y-button.Center[1]) <= button.Radius {
+				button.pressed = true
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// Draw draws the joysticks with their knob, and the buttons, highlighted while used.
+func (c *TouchControls) Draw(screen *ebiten.Image) {
+	if !c.visible {
+		return
+	}
+
+	for _, joystick := range c.joysticks {
+		base := controlColor(joystick.active)
+		vector.DrawFilledCircle(screen, float32(joystick.Center[0]), float32(joystick.Center[1]), float32(joystick.Radius), base, true)
+
+		knobX := joystick.Center[0] + joystick.x*joystick.Radius
+		knobY := joystick.Center[1] + joystick.y*joystick.Radius
+		vector.DrawFilledCircle(screen, float32(knobX), float32(knobY), float32(joystick.Radius/2), ControlActiveColor, true)
+	}
+
+	for _, button := range c.buttons {
+		vector.DrawFilledCircle(screen, float32(button.Center[0]), float32(button.Center[1]), float32(button.Radius), controlColor(button.pressed), true)
+	}
+}
+
+func (c *TouchControls) find(id ebiten.TouchID) (Touch, bool) {
+	for _, touch := range c.touches {
+		if touch.ID == id {
+			return touch, true
+		}
+	}
+
+	return Touch{}, false
+}
+
+// claimed reports whether the touch drives a joystick.
+func (c *TouchControls) claimed(id ebiten.TouchID) bool {
+	for _, joystick := range c.joysticks {
+		if joystick.active && joystick.touch == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func controlColor(active bool) color.Color {
+	if active {
+		return ControlActiveColor
+	}
+
+	return ControlColor
+}
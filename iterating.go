@@ -0,0 +1,63 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NewIteratingSystem creates a system calling fn for each entity with the component A, every update,
+// for the common case of a system that only loops over a query:
+//
+//	sm.Add(ecs.NewIteratingSystem(0, func(id ecs.EntityID, lifetime *Lifetime) error {
+//		lifetime.Remaining--
+//		return nil
+//	}))
+//
+// The system gets a new ID, runs in PhaseUpdate and declares it writes A; like any FuncSystem, it can be
+// configured before it is added. An error returned by fn stops the loop and is returned by the update.
+func NewIteratingSystem[A any](priority int, fn func(id EntityID, a *A) error) *FuncSystem {
+	return newIteratingSystem(priority, []reflect.Type{reflect.TypeFor[A]()}, func(em *EntityManager, id EntityID) error {
+		return fn(id, MustGetComponent[A](em, id))
+	})
+}
+
+// NewIteratingSystem2 creates a system calling fn for each entity with the components A and B, every update,
+// see NewIteratingSystem:
+//
+//	sm.Add(ecs.NewIteratingSystem2(0, func(id ecs.EntityID, tr *transform.Transform, v *Velocity) error {
+//		tr.Position[0] += v.X
+//		return nil
+//	}))
+func NewIteratingSystem2[A, B any](priority int, fn func(id EntityID, a *A, b *B) error) *FuncSystem {
+	return newIteratingSystem(priority, []reflect.Type{reflect.TypeFor[A](), reflect.TypeFor[B]()}, func(em *EntityManager, id EntityID) error {
+		a, b, _ := GetComponents2[A, B](em, id)
+		return fn(id, a, b)
+	})
+}
+
+// NewIteratingSystem3 creates a system calling fn for each entity with the components A, B and C, every update,
+// see NewIteratingSystem.
+func NewIteratingSystem3[A, B, C any](priority int, fn func(id EntityID, a *A, b *B, c *C) error) *FuncSystem {
+	types := []reflect.Type{reflect.TypeFor[A](), reflect.TypeFor[B](), reflect.TypeFor[C]()}
+	return newIteratingSystem(priority, types, func(em *EntityManager, id EntityID) error {
+		a, b, c, _ := GetComponents3[A, B, C](em, id)
+		return fn(id, a, b, c)
+	})
+}
+
+// newIteratingSystem creates a FuncSystem querying the component types and calling each for every entity.
+func newIteratingSystem(priority int, types []reflect.Type, each func(em *EntityManager, id EntityID) error) *FuncSystem {
+	s := NewSystem(SystemOptions{Priority: priority, Access: SystemAccess{Writes: types}}, func(s *FuncSystem) error {
+		em := s.EntityManager()
+		for id := range s.Query() {
+			if err := each(em, id); err != nil {
+				return fmt.Errorf("entity %d: %w", id, err)
+			}
+		}
+
+		return nil
+	})
+	s.query = types
+
+	return s
+}
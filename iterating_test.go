@@ -0,0 +1,46 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIteratingSystem(t *testing.T) {
+	type Position struct{ X float64 }
+	type Velocity struct{ X float64 }
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	moving := em.NewEntity()
+	ecs.AddComponent[Position](em, moving)
+	ecs.AddComponent[Velocity](em, moving).X = 2
+
+	still := em.NewEntity()
+	ecs.AddComponent[Position](em, still).X = 5
+
+	accelerate := ecs.NewIteratingSystem(0, func(id ecs.EntityID, v *Velocity) error {
+		v.X++
+		return nil
+	})
+	move := ecs.NewIteratingSystem2(1, func(id ecs.EntityID, p *Position, v *Velocity) error {
+		assert.Equal(t, moving, id)
+		p.X += v.X
+		return nil
+	})
+	require.NoError(t, sm.Add(accelerate, move))
+	assert.Equal(t, ecs.Types(Position{}, Velocity{}), move.Access().Writes)
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, 3.0, ecs.MustGetComponent[Position](em, moving).X)
+	assert.Equal(t, 5.0, ecs.MustGetComponent[Position](em, still).X)
+
+	failing := ecs.NewIteratingSystem3(2, func(ecs.EntityID, *Position, *Velocity, *Position) error {
+		return assert.AnError
+	})
+	require.NoError(t, sm.Add(failing))
+	assert.ErrorIs(t, sm.Update(), assert.AnError)
+}
@@ -0,0 +1,117 @@
+package ecs
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LifecycleHook is called by Game.Update when the window gains or loses the focus.
+type LifecycleHook func(g *Game)
+
+// CloseHook is called by Game.Update when the player asks to close the window. It returns whether the
+// game may close; a hook refusing it can close the game later with Game.Exit, e.g. after a save prompt.
+type CloseHook func(g *Game) bool
+
+// WindowState is the state of the window polled by Game.Update for the lifecycle hooks.
+type WindowState struct {
+	Focused bool
+	// Closing is set on the update the player asks to close the window.
+	Closing bool
+}
+
+// EbitenWindowState returns the state of the window run by Game.Start.
+func EbitenWindowState() WindowState {
+	return WindowState{Focused: ebiten.IsFocused(), Closing: ebiten.IsWindowBeingClosed()}
+}
+
+// OnFocusGained registers a hook called on the update the window gains the focus, including when a mobile
+// app resumes from the background. It returns a function that removes the hook.
+func (g *Game) OnFocusGained(hook LifecycleHook) (remove func()) {
+	return g.focusGained.add(hook)
+}
+
+// OnFocusLost registers a hook called on the update the window loses the focus. Ebiten does not update
+// suspended mobile apps, so their hooks run on the first update after resuming, before the OnFocusGained
+// hooks. It returns a function that removes the hook.
+func (g *Game) OnFocusLost(hook LifecycleHook) (remove func()) {
+	return g.focusLost.add(hook)
+}
+
+// OnCloseRequested registers a hook called when the player asks to close the window. The game closes
+// unless a hook refuses it. It returns a function that removes the hook.
+func (g *Game) OnCloseRequested(hook CloseHook) (remove func()) {
+	return g.closeRequested.add(hook)
+}
+
+// SetPauseOnFocusLoss pauses the game while its window is not focused, e.g. when the player switches
+// to another application. The game is only resumed if it was paused because of the focus loss.
+func (g *Game) SetPauseOnFocusLoss(enabled bool) {
+	g.pauseOnFocusLoss = enabled
+}
+
+// SetWindowStateSource replaces the source of the window state polled every Update, EbitenWindowState
+// while the game is run by Start, e.g. to drive the lifecycle hooks from tests or a platform layer.
+// Headless games have no source unless one is set.
+func (g *Game) SetWindowStateSource(source func() WindowState) {
+	g.windowState = source
+}
+
+// Exit makes the next Update return ebiten.Termination, closing the game.
+func (g *Game) Exit() {
+	g.exiting = true
+}
+
+// pollWindow runs the lifecycle hooks for the changes of the window state, and returns ebiten.Termination
+// if the game must close, including when its context is cancelled.
+func (g *Game) pollWindow() error {
+	if g.exiting || g.ctx.Err() != nil {
+		return ebiten.Termination
+	}
+
+	if g.windowState == nil {
+		return nil
+	}
+
+	state := g.windowState()
+	if state.Focused == g.unfocused {
+		g.unfocused = !state.Focused
+		g.focusChanged(state.Focused)
+	}
+
+	if state.Closing && g.closeAllowed() {
+		return ebiten.Termination
+	}
+
+	return nil
+}
+
+func (g *Game) focusChanged(focused bool) {
+	hooks := &g.focusLost
+	if focused {
+		hooks = &g.focusGained
+	}
+
+	switch {
+	case !g.pauseOnFocusLoss:
+	case !focused && !g.Paused():
+		g.Pause()
+		g.pausedByFocus = true
+	case focused && g.pausedByFocus:
+		g.Resume()
+		g.pausedByFocus = false
+	}
+
+	for _, entry := range hooks.all() {
+		entry.fn(g)
+	}
+}
+
+func (g *Game) closeAllowed() bool {
+	allowed := true
+	for _, entry := range g.closeRequested.all() {
+		if !entry.fn(g) {
+			allowed = false
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,54 @@
+package ecs_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	state := ecs.WindowState{Focused: true}
+	game.SetWindowStateSource(func() ecs.WindowState { return state })
+	game.SetPauseOnFocusLoss(true)
+
+	var events []string
+	game.OnFocusLost(func(*ecs.Game) { events = append(events, "lost") })
+	game.OnFocusGained(func(*ecs.Game) { events = append(events, "gained") })
+
+	saved := false
+	game.OnCloseRequested(func(*ecs.Game) bool {
+		events = append(events, "close")
+		return saved
+	})
+
+	require.NoError(t, game.Update())
+	assert.Empty(t, events, "the window starts focused")
+
+	state.Focused = false
+	require.NoError(t, game.Update())
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"lost"}, events)
+	assert.True(t, game.Paused())
+
+	state.Focused = true
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"lost", "gained"}, events)
+	assert.False(t, game.Paused())
+
+	events = nil
+	state.Closing = true
+	require.NoError(t, game.Update(), "a hook can refuse closing")
+	assert.Equal(t, []string{"close"}, events)
+
+	saved = true
+	assert.ErrorIs(t, game.Update(), ebiten.Termination)
+
+	state.Closing = false
+	game.Exit()
+	assert.ErrorIs(t, game.Update(), ebiten.Termination)
+}
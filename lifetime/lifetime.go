@@ -0,0 +1,88 @@
+// Package lifetime despawns entities after a time or a number of ticks, for bullets, particles,
+// floating damage numbers and anything else that should clean itself up.
+package lifetime
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Lifetime is the component counting down an entity's remaining life.
+// If Ticks is positive the entity lives that many more updates, otherwise it lives Seconds more seconds
+// of scaled game time, so it does not age while the game is paused.
+// A Lifetime with both fields zero expires on the next update.
+type Lifetime struct {
+	Seconds float64
+	Ticks   int
+}
+
+func (l *Lifetime) Reset() {
+	l.Seconds = 0
+	l.Ticks = 0
+}
+
+// Expired is passed to the System's expiry handler when an entity's lifetime runs out.
+type Expired struct {
+	EntityID ecs.EntityID
+}
+
+// System counts down every Lifetime and removes the entities whose lifetime has run out.
+// Removals go through the EntityManager's command buffer, which the SystemManager flushes after Update,
+// so the entity and its components are still readable by the expiry handler.
+type System struct {
+	*ecs.BaseSystem
+
+	onExpired func(Expired)
+}
+
+// NewSystem creates a new lifetime System with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int) *System {
+	s := &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+	s.SetAccess(ecs.SystemAccess{Writes: ecs.Types(Lifetime{})})
+
+	return s
+}
+
+// OnExpired sets a handler called for every entity whose lifetime runs out, before it is removed,
+// e.g. to spawn an explosion where a rocket timed out. A nil handler removes it.
+func (s *System) OnExpired(handler func(Expired)) {
+	s.onExpired = handler
+
+	access := s.Access()
+	access.Produces = nil
+	if handler != nil {
+		access.Produces = ecs.Types(Expired{})
+	}
+	s.SetAccess(access)
+}
+
+// Update counts down all lifetimes.
+func (s *System) Update() error {
+	em := s.EntityManager()
+	dt := s.Time().Delta()
+
+	for entityID := range ecs.Query[Lifetime](em) {
+		lifetime := ecs.MustGetComponent[Lifetime](em, entityID)
+
+		if lifetime.Ticks > 0 {
+			lifetime.Ticks--
+			if lifetime.Ticks > 0 {
+				continue
+			}
+		} else {
+			lifetime.Seconds -= dt
+			if lifetime.Seconds > 0 {
+				continue
+			}
+		}
+
+		if s.onExpired != nil {
+			s.onExpired(Expired{EntityID: entityID})
+		}
+
+		em.Commands().Remove(entityID)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package lifetime_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/lifetime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	system := lifetime.NewSystem(ecs.NextID(), 0)
+	sm.Add(system)
+
+	var expired []ecs.EntityID
+	system.OnExpired(func(e lifetime.Expired) {
+		assert.True(t, ecs.HasComponent[lifetime.Lifetime](em, e.EntityID), "components are readable when expiring")
+		expired = append(expired, e.EntityID)
+	})
+
+	bullet := em.NewEntity()
+	ecs.AddComponent[lifetime.Lifetime](em, bullet).Seconds = 1
+
+	particle := em.NewEntity()
+	ecs.AddComponent[lifetime.Lifetime](em, particle).Ticks = 2
+
+	step := func() {
+		game.Time().Advance(0.4)
+		require.NoError(t, sm.Update())
+	}
+
+	step()
+	assert.Empty(t, expired)
+
+	step()
+	assert.Equal(t, []ecs.EntityID{particle}, expired)
+	assert.False(t, em.Active(particle))
+	assert.True(t, em.Active(bullet))
+
+	game.Time().SetPaused(true)
+	step()
+	assert.True(t, em.Active(bullet), "paused time does not age entities")
+
+	game.Time().SetPaused(false)
+	step()
+	assert.Equal(t, []ecs.EntityID{particle, bullet}, expired)
+	assert.False(t, em.Active(bullet))
+}
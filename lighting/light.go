@@ -0,0 +1,79 @@
+// Package lighting draws 2D lights over the scene: ambient, point and cone lights accumulate into a light map
+// that darkens everything drawn before it, and lights can cast hard shadows behind collision.Collider shapes.
+package lighting
+
+import (
+	"fmt"
+	"image/color"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+)
+
+func init() {
+	ecs.Require[Light, transform.Transform]()
+}
+
+// Kind is the shape of a Light.
+type Kind int
+
+const (
+	// Point lights shine in every direction up to their Radius.
+	Point Kind = iota
+	// Cone lights shine up to their Radius within Angle radians around the rotation of their transform.
+	Cone
+	// Ambient lights brighten the whole scene uniformly, wherever they are.
+	Ambient
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Point:
+		return "point"
+	case Cone:
+		return "cone"
+	case Ambient:
+		return "ambient"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Light lights the scene from its entity's transform.Transform, which is added with the light if missing.
+// The brightness of point and cone lights falls off linearly from the light to its Radius.
+type Light struct {
+	Kind  Kind
+	Color color.Color
+	// Intensity scales the color. Zero is treated as 1.
+	Intensity float64
+
+	Radius float64
+	// Angle is the full width of a cone light, in radians.
+	Angle float64
+
+	// Shadows makes the light stop at the collision.Collider shapes of other entities.
+	Shadows bool
+	// Disabled lights are skipped.
+	Disabled bool
+}
+
+func (l *Light) Reset() {
+	*l = Light{}
+}
+
+// rgb returns the color of the light scaled by its intensity, white if it has no color.
+func (l *Light) rgb() (r, g, b float32) {
+	intensity := l.Intensity
+	if intensity == 0 {
+		intensity = 1
+	}
+
+	clr := l.Color
+	if clr == nil {
+		clr = color.White
+	}
+
+	cr, cg, cb, _ := clr.RGBA()
+
+	return float32(float64(cr) / 0xffff * intensity), float32(float64(cg) / 0xffff * intensity), float32(float64(cb) / 0xffff * intensity)
+}
@@ -0,0 +1,108 @@
+package lighting_test
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/lighting"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func spawnLight(em *ecs.EntityManager, light lighting.Light, x, y float64) ecs.EntityID {
+	entityID := em.NewEntity()
+	ecs.SetComponent(em, entityID, light)
+	ecs.MustGetComponent[transform.Transform](em, entityID).Position = f64.Vec2{x, y}
+
+	return entityID
+}
+
+func spawnWall(em *ecs.EntityManager, x, y, width, height float64) ecs.EntityID {
+	entityID := em.NewEntity()
+	ecs.SetComponent(em, entityID, collision.Collider{Width: width, Height: height, Static: true})
+	ecs.AddComponent[transform.Transform](em, entityID).Position = f64.Vec2{x, y}
+
+	return entityID
+}
+
+func distance(a, b f64.Vec2) float64 {
+	return math.Hypot(a[0]-b[0], a[1]-b[1])
+}
+
+func TestLightPolygon(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	origin := f64.Vec2{0, 0}
+	point := spawnLight(em, lighting.Light{Kind: lighting.Point, Radius: 100}, 0, 0)
+	for _, p := range lighting.LightPolygon(em, point) {
+		assert.InDelta(t, 100, distance(origin, p), 1e-9)
+	}
+
+	cone := spawnLight(em, lighting.Light{Kind: lighting.Cone, Radius: 100, Angle: math.Pi / 2}, 0, 0)
+	polygon := lighting.LightPolygon(em, cone)
+	require.NotEmpty(t, polygon)
+	assert.Equal(t, origin, polygon[0])
+	assert.Equal(t, origin, polygon[len(polygon)-1])
+	for _, p := range polygon[1 : len(polygon)-1] {
+		assert.GreaterOrEqual(t, p[0], 70.0, "the cone faces the rotation of its transform")
+	}
+
+	ambient := spawnLight(em, lighting.Light{Kind: lighting.Ambient}, 0, 0)
+	assert.Empty(t, lighting.LightPolygon(em, ambient))
+}
+
+func TestLightShadows(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	// A wall from x=50 to x=60, covering the light's right side between y=-20 and y=20.
+	spawnWall(em, 50, -20, 10, 40)
+	spawnWall(em, 1000, 1000, 10, 10) // too far to matter
+
+	origin := f64.Vec2{0, 0}
+	torch := spawnLight(em, lighting.Light{Kind: lighting.Point, Radius: 100, Shadows: true}, 0, 0)
+	ecs.SetComponent(em, torch, collision.Collider{Width: 8, Height: 8})
+
+	shadowed := 0
+	for _, p := range lighting.LightPolygon(em, torch) {
+		d := distance(origin, p)
+		if d < 100-1e-9 {
+			shadowed++
+			assert.InDelta(t, 50, p[0], 1e-6, "rays stop at the wall, not at the light's own collider")
+			assert.LessOrEqual(t, math.Abs(p[1]), 20+1e-6)
+		}
+	}
+	assert.Greater(t, shadowed, 2)
+
+	ecs.MustGetComponent[lighting.Light](em, torch).Shadows = false
+	for _, p := range lighting.LightPolygon(em, torch) {
+		assert.InDelta(t, 100, distance(origin, p), 1e-9)
+	}
+}
+
+func TestSystem(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	system := lighting.NewSystem(1, 0)
+	sm.Add(system)
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+	assert.Empty(t, rec.Ops(), "the scene is unlit without lights")
+
+	spawnLight(em, lighting.Light{Kind: lighting.Ambient, Color: color.Gray{Y: 0x40}}, 0, 0)
+	spawnLight(em, lighting.Light{Kind: lighting.Point, Radius: 64, Shadows: true}, 100, 100)
+	spawnWall(em, 120, 80, 10, 40)
+
+	sm.DrawCanvas(rec)
+	ops := rec.Ops()
+	require.Len(t, ops, 1)
+	assert.Equal(t, render.OpDrawImage, ops[0].Kind)
+	assert.Equal(t, lighting.Multiply, ops[0].Blend, "the scene is multiplied by the light map")
+	assert.Equal(t, rec.ImageID(system.LightMap()), ops[0].Image)
+}
@@ -0,0 +1,177 @@
+package lighting
+
+import (
+	"math"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// lightSegments is the number of rim points of a full circle of light without shadows.
+const lightSegments = 64
+
+// ellipseSegments is the number of sides of the polygon approximating elliptic colliders.
+const ellipseSegments = 16
+
+// segment is an edge of an occluding collider, in world space.
+type segment struct {
+	a, b f64.Vec2
+	// owner is the entity of the collider, which does not shadow its own lights.
+	owner ecs.EntityID
+}
+
+// occluders appends the edges of the colliders of em to segments.
+func occluders(em *ecs.EntityManager, segments []segment) []segment {
+	for entityID := range ecs.Query2[collision.Collider, transform.Transform](em) {
+		collider := ecs.MustGetComponent[collision.Collider](em, entityID)
+		position := ecs.MustGetComponent[transform.Transform](em, entityID).Position
+
+		var points []f64.Vec2
+		switch collider.Shape {
+		case collision.ShapeRect:
+			points = []f64.Vec2{{0, 0}, {collider.Width, 0}, {collider.Width, collider.Height}, {0, collider.Height}}
+		case collision.ShapeEllipse:
+			rx, ry := collider.Width/2, collider.Height/2
+			points = make([]f64.Vec2, ellipseSegments)
+			for i := range points {
+				angle := 2 * math.Pi * float64(i) / ellipseSegments
+				points[i] = f64.Vec2{rx + rx*math.Cos(angle), ry + ry*math.Sin(angle)}
+			}
+		case collision.ShapePolygon:
+			points = collider.Points
+		}
+
+		for i, p := range points {
+			q := points[(i+1)%len(points)]
+			segments = append(segments, segment{
+				a:     f64.Vec2{position[0] + p[0], position[1] + p[1]},
+				b:     f64.Vec2{position[0] + q[0], position[1] + q[1]},
+				owner: entityID,
+			})
+		}
+	}
+
+	return segments
+}
+
+// LightPolygon returns the outline of the area lit by the point or cone light of the entity, in world space
+// and by increasing angle: the points at its radius, or where its rays first hit a collider if it casts
+// shadows. Cone lights start and end with their position. It returns nil for ambient and disabled lights.
+func LightPolygon(em *ecs.EntityManager, entityID ecs.EntityID) []f64.Vec2 {
+	light, ok := ecs.GetComponent[Light](em, entityID)
+	if !ok {
+		return nil
+	}
+
+	var segments []segment
+	if light.Shadows {
+		segments = occluders(em, nil)
+	}
+
+	var p polygon
+	p.build(light, entityID, ecs.MustGetComponent[transform.Transform](em, entityID), segments)
+
+	return p.points
+}
+
+// polygon computes light outlines, reusing its buffers.
+type polygon struct {
+	angles []float64
+	points []f64.Vec2
+	nearby []segment
+}
+
+func (p *polygon) build(light *Light, entityID ecs.EntityID, tr *transform.Transform, segments []segment) {
+	p.points = p.points[:0]
+	if light.Disabled || light.Kind == Ambient || !(light.Radius > 0) {
+		return
+	}
+
+	origin := tr.Position
+
+	start, span := 0.0, 2*math.Pi
+	if light.Kind == Cone {
+		span = min(max(light.Angle, 0), 2*math.Pi)
+		start = tr.Rotation - span/2
+	}
+
+	// Only the occluders overlapping the bounding box of the light can shadow it.
+	p.nearby = p.nearby[:0]
+	for _, seg := range segments {
+		if seg.owner == entityID ||
+			max(seg.a[0], seg.b[0]) < origin[0]-light.Radius || min(seg.a[0], seg.b[0]) > origin[0]+light.Radius ||
+			max(seg.a[1], seg.b[1]) < origin[1]-light.Radius || min(seg.a[1], seg.b[1]) > origin[1]+light.Radius {
+			continue
+		}
+
+		p.nearby = append(p.nearby, seg)
+	}
+
+	// Rays are cast at regular angles, and on both sides of the corners of the occluders for sharp edges.
+	steps := max(int(math.Ceil(lightSegments*span/(2*math.Pi))), 1)
+	p.angles = p.angles[:0]
+	for i := range steps + 1 {
+		p.angles = append(p.angles, start+span*float64(i)/float64(steps))
+	}
+
+	const epsilon = 1e-4
+	for _, seg := range p.nearby {
+		for _, corner := range [2]f64.Vec2{seg.a, seg.b} {
+			angle := math.Atan2(corner[1]-origin[1], corner[0]-origin[0])
+			for _, offset := range [2]float64{-epsilon, epsilon} {
+				if a, ok := withinSpan(angle+offset, start, span); ok {
+					p.angles = append(p.angles, a)
+				}
+			}
+		}
+	}
+
+	slices.Sort(p.angles)
+	p.angles = slices.Compact(p.angles)
+
+	if light.Kind == Cone {
+		p.points = append(p.points, origin)
+	}
+
+	for _, angle := range p.angles {
+		direction := f64.Vec2{math.Cos(angle), math.Sin(angle)}
+
+		distance := light.Radius
+		for _, seg := range p.nearby {
+			if t, ok := raySegment(origin, direction, seg.a, seg.b); ok && t < distance {
+				distance = t
+			}
+		}
+
+		p.points = append(p.points, f64.Vec2{origin[0] + direction[0]*distance, origin[1] + direction[1]*distance})
+	}
+
+	if light.Kind == Cone {
+		p.points = append(p.points, origin)
+	}
+}
+
+// withinSpan returns angle shifted by a multiple of 2π into [start, start+span], or false if it is outside.
+func withinSpan(angle, start, span float64) (float64, bool) {
+	angle = start + math.Mod(math.Mod(angle-start, 2*math.Pi)+2*math.Pi, 2*math.Pi)
+	return angle, angle <= start+span
+}
+
+// raySegment returns the distance along the ray from origin in direction, a unit vector, to the segment from a to b.
+func raySegment(origin, direction, a, b f64.Vec2) (float64, bool) {
+	edge := f64.Vec2{b[0] - a[0], b[1] - a[1]}
+
+	denominator := direction[0]*edge[1] - direction[1]*edge[0]
+	if denominator == 0 {
+		return 0, false
+	}
+
+	dx, dy := a[0]-origin[0], a[1]-origin[1]
+	t := (dx*edge[1] - dy*edge[0]) / denominator
+	u := (dx*direction[1] - dy*direction[0]) / denominator
+
+	return t, t >= 0 && u >= 0 && u <= 1
+}
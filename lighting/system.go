@@ -0,0 +1,175 @@
+package lighting
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+)
+
+var _ ecs.CanvasSystem = (*System)(nil)
+
+// Multiply is the blend multiplying the scene by the light map.
+var Multiply = ebiten.Blend{
+	BlendFactorSourceRGB:        ebiten.BlendFactorZero,
+	BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+	BlendFactorDestinationRGB:   ebiten.BlendFactorSourceColor,
+	BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+	BlendOperationRGB:           ebiten.BlendOperationAdd,
+	BlendOperationAlpha:         ebiten.BlendOperationAdd,
+}
+
+// System lights everything drawn before it, so it must be drawn after the render systems and before
+// the render.PostProcessSystem. Each frame, it fills a light map with the sum of the ambient lights,
+// adds the point and cone lights, seen through the active render.Camera, and multiplies the canvas by it.
+// Without any enabled light, the scene is left unlit.
+type System struct {
+	*ecs.BaseSystem
+
+	lightMap *ebiten.Image
+	white    *ebiten.Image
+
+	segments []segment
+	polygon  polygon
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// NewSystem creates a new lighting System with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int) *System {
+	return &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+}
+
+// Update does nothing; lights are drawn in DrawCanvas.
+func (s *System) Update() error {
+	return nil
+}
+
+// DrawCanvas lights the canvas.
+func (s *System) DrawCanvas(canvas ecs.Canvas) {
+	em := s.EntityManager()
+
+	var ambientR, ambientG, ambientB float32
+	lit, shadows := false, false
+	for entityID := range ecs.Query2[Light, transform.Transform](em) {
+		light := ecs.MustGetComponent[Light](em, entityID)
+		if light.Disabled {
+			continue
+		}
+
+		lit = true
+		shadows = shadows || light.Shadows
+
+		if light.Kind == Ambient {
+			r, g, b := light.rgb()
+			ambientR, ambientG, ambientB = ambientR+r, ambientG+g, ambientB+b
+		}
+	}
+
+	if !lit {
+		return
+	}
+
+	bounds := canvas.Bounds()
+	s.allocate(bounds.Dx(), bounds.Dy())
+	s.lightMap.Fill(color.RGBA64{R: channel(ambientR), G: channel(ambientG), B: channel(ambientB), A: 0xffff})
+
+	s.segments = s.segments[:0]
+	if shadows {
+		s.segments = occluders(em, s.segments)
+	}
+
+	view := render.ActiveView(em, bounds.Dx(), bounds.Dy())
+	for entityID := range ecs.Query2[Light, transform.Transform](em) {
+		light := ecs.MustGetComponent[Light](em, entityID)
+
+		var segments []segment
+		if light.Shadows {
+			segments = s.segments
+		}
+
+		tr := ecs.MustGetComponent[transform.Transform](em, entityID)
+		s.polygon.build(light, entityID, tr, segments)
+		if len(s.polygon.points) == 0 {
+			continue
+		}
+
+		s.drawLight(light, tr, &view)
+	}
+
+	canvas.DrawImage(s.lightMap, &ebiten.DrawImageOptions{Blend: Multiply})
+}
+
+// drawLight adds the light, outlined by s.polygon, to the light map as a triangle fan around its position,
+// whose colors fade linearly with the distance.
+func (s *System) drawLight(light *Light, tr *transform.Transform, view *render.View) {
+	rim := s.polygon.points
+	if light.Kind == Cone {
+		rim = rim[1 : len(rim)-1]
+	}
+
+	r, g, b := light.rgb()
+	vertex := func(x, y float64, brightness float32) ebiten.Vertex {
+		dstX, dstY := view.GeoM.Apply(x, y)
+		return ebiten.Vertex{
+			DstX: float32(dstX), DstY: float32(dstY),
+			SrcX: 1, SrcY: 1,
+			ColorR: r * brightness, ColorG: g * brightness, ColorB: b * brightness, ColorA: 1,
+		}
+	}
+
+	origin := tr.Position
+	s.vertices = append(s.vertices[:0], vertex(origin[0], origin[1], 1))
+	s.indices = s.indices[:0]
+	for i, point := range rim {
+		distance := math.Hypot(point[0]-origin[0], point[1]-origin[1])
+		brightness := float32(max(1-distance/light.Radius, 0))
+
+		s.vertices = append(s.vertices, vertex(point[0], point[1], brightness))
+		if i > 0 {
+			s.indices = append(s.indices, 0, uint16(i), uint16(i+1))
+		}
+	}
+
+	s.lightMap.DrawTriangles(s.vertices, s.indices, s.white, &ebiten.DrawTrianglesOptions{Blend: ebiten.BlendLighter})
+}
+
+// Teardown deallocates the light map.
+func (s *System) Teardown() {
+	if s.lightMap != nil {
+		s.lightMap.Deallocate()
+		s.lightMap = nil
+	}
+}
+
+// LightMap returns the light map of the last frame, or nil if nothing was lit yet.
+func (s *System) LightMap() *ebiten.Image {
+	return s.lightMap
+}
+
+// allocate creates the light map, or recreates it when the canvas was resized.
+func (s *System) allocate(width, height int) {
+	if s.white == nil {
+		s.white = ebiten.NewImage(3, 3)
+		s.white.Fill(color.White)
+	}
+
+	if s.lightMap != nil && s.lightMap.Bounds().Dx() == width && s.lightMap.Bounds().Dy() == height {
+		return
+	}
+
+	if s.lightMap != nil {
+		s.lightMap.Deallocate()
+	}
+	s.lightMap = ebiten.NewImage(width, height)
+}
+
+// channel converts a color channel in [0, 1], clamped, to 16 bits.
+func channel(c float32) uint16 {
+	return uint16(min(max(c, 0), 1) * 0xffff)
+}
@@ -0,0 +1,141 @@
+package ecs
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// LogSubsystem is a category of structural changes logged by a Game or EntityManager with a logger,
+// recorded in the "subsystem" attribute of every log record.
+type LogSubsystem string
+
+const (
+	// LogEntities logs created and removed entities.
+	LogEntities LogSubsystem = "entities"
+	// LogComponents logs added and removed components, except those removed with their entity.
+	LogComponents LogSubsystem = "components"
+	// LogSystems logs added and removed systems.
+	LogSystems LogSubsystem = "systems"
+	// LogWorlds logs world switches.
+	LogWorlds LogSubsystem = "worlds"
+)
+
+// structuralLogger logs the structural changes of the enabled subsystems at debug level.
+// A nil structuralLogger logs nothing.
+type structuralLogger struct {
+	logger *slog.Logger
+	// subsystems are the enabled subsystems, all of them if empty.
+	subsystems []LogSubsystem
+}
+
+func newStructuralLogger(logger *slog.Logger, subsystems []LogSubsystem) *structuralLogger {
+	if logger == nil {
+		return nil
+	}
+
+	return &structuralLogger{logger: logger, subsystems: slices.Clone(subsystems)}
+}
+
+// enabled reports whether changes of the subsystem are logged. Callers check it before building
+// the attributes of a record, so disabled logging costs no allocation.
+func (l *structuralLogger) enabled(subsystem LogSubsystem) bool {
+	if l == nil {
+		return false
+	}
+
+	if len(l.subsystems) > 0 && !slices.Contains(l.subsystems, subsystem) {
+		return false
+	}
+
+	return l.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func (l *structuralLogger) log(subsystem LogSubsystem, msg string, attrs ...slog.Attr) {
+	l.logger.LogAttrs(context.Background(), slog.LevelDebug, msg,
+		append([]slog.Attr{slog.String("subsystem", string(subsystem))}, attrs...)...)
+}
+
+// SetLogger logs the structural changes of the EntityManager, entities created and removed and
+// components added and removed, to logger at debug level, limited to the given subsystems if any.
+// A nil logger turns logging off.
+func (em *EntityManager) SetLogger(logger *slog.Logger, subsystems ...LogSubsystem) {
+	em.logger = newStructuralLogger(logger, subsystems)
+}
+
+// Logger returns the logger set with SetLogger, or nil.
+func (em *EntityManager) Logger() *slog.Logger {
+	if em.logger == nil {
+		return nil
+	}
+
+	return em.logger.logger
+}
+
+// SetLogger logs world switches and the systems added to and removed from the worlds' SystemManagers
+// to logger at debug level, limited to the given subsystems if any. EntityManagers passed to
+// NewSystemManager afterwards without a logger of their own inherit it, see EntityManager.SetLogger.
+// A nil logger turns logging off.
+func (g *Game) SetLogger(logger *slog.Logger, subsystems ...LogSubsystem) {
+	g.logger = newStructuralLogger(logger, subsystems)
+}
+
+// Logger returns the logger set with SetLogger, or nil.
+func (g *Game) Logger() *slog.Logger {
+	if g.logger == nil {
+		return nil
+	}
+
+	return g.logger.logger
+}
+
+// errorLogger returns the logger of g, or slog.Default() without a game or logger,
+// for the errors that are logged rather than returned.
+func errorLogger(g *Game) *slog.Logger {
+	if g != nil && g.Logger() != nil {
+		return g.Logger()
+	}
+
+	return slog.Default()
+}
+
+// logger returns the structural logger of the SystemManager's game, if any.
+func (sm *SystemManager) logger() *structuralLogger {
+	if sm.game == nil {
+		return nil
+	}
+
+	return sm.game.logger
+}
+
+func (em *EntityManager) logComponent(msg string, entityID EntityID, componentType reflect.Type) {
+	if em.logger.enabled(LogComponents) {
+		em.logger.log(LogComponents, msg, slog.Uint64("entity", uint64(entityID)),
+			slog.String("component", componentType.String()))
+	}
+}
+
+func (em *EntityManager) logEntity(msg string, entityID EntityID) {
+	if em.logger.enabled(LogEntities) {
+		em.logger.log(LogEntities, msg, slog.Uint64("entity", uint64(entityID)))
+	}
+}
+
+func (sm *SystemManager) logSystem(msg string, system System) {
+	if logger := sm.logger(); logger.enabled(LogSystems) {
+		attrs := []slog.Attr{slog.Uint64("system", uint64(system.ID())), slog.Int("priority", system.Priority())}
+		if name := system.baseSystem().name; name != "" {
+			attrs = append(attrs, slog.String("name", name))
+		}
+
+		logger.log(LogSystems, msg, attrs...)
+	}
+}
+
+func (g *Game) logWorld(msg string, world World) {
+	if g.logger.enabled(LogWorlds) {
+		g.logger.log(LogWorlds, msg, slog.String("world", reflect.TypeOf(world).String()),
+			slog.Int("depth", len(g.worlds)))
+	}
+}
@@ -0,0 +1,95 @@
+package ecs_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a debug logger writing timeless text records to buf.
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
+func logLines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestEntityManagerLogger(t *testing.T) {
+	var buf bytes.Buffer
+	em := ecs.NewEntityManager()
+	em.SetLogger(newTestLogger(&buf))
+
+	entityID := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, entityID)
+	ecs.RemoveComponent[TransformComponent](em, entityID)
+	ecs.AddComponent[CameraComponent](em, entityID)
+	em.Remove(entityID)
+
+	assert.Equal(t, []string{
+		fmt.Sprintf("level=DEBUG msg=\"entity created\" subsystem=entities entity=%d", entityID),
+		fmt.Sprintf("level=DEBUG msg=\"component added\" subsystem=components entity=%d component=ecs_test.TransformComponent", entityID),
+		fmt.Sprintf("level=DEBUG msg=\"component removed\" subsystem=components entity=%d component=ecs_test.TransformComponent", entityID),
+		fmt.Sprintf("level=DEBUG msg=\"component added\" subsystem=components entity=%d component=ecs_test.CameraComponent", entityID),
+		fmt.Sprintf("level=DEBUG msg=\"entity removed\" subsystem=entities entity=%d", entityID),
+	}, logLines(&buf))
+
+	buf.Reset()
+	em.SetLogger(newTestLogger(&buf), ecs.LogEntities)
+
+	entityID = em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, entityID)
+
+	assert.Equal(t, []string{
+		fmt.Sprintf("level=DEBUG msg=\"entity created\" subsystem=entities entity=%d", entityID),
+	}, logLines(&buf), "only the entities subsystem is logged")
+
+	buf.Reset()
+	em.SetLogger(nil)
+	em.NewEntity()
+
+	assert.Empty(t, buf.String())
+	assert.Nil(t, em.Logger())
+}
+
+func TestGameLogger(t *testing.T) {
+	var buf bytes.Buffer
+	game := ecs.NewGame(&ecs.GameConfig{})
+	game.SetLogger(newTestLogger(&buf), ecs.LogSystems, ecs.LogWorlds, ecs.LogEntities)
+	require.NotNil(t, game.Logger())
+
+	require.NoError(t, game.SetActiveWorld(&hookWorld{name: "level"}))
+	require.NoError(t, game.PushWorld(&hookWorld{name: "pause"}))
+	require.NoError(t, game.PopWorld())
+
+	lines := logLines(&buf)
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[0], `msg="system added" subsystem=systems`)
+	assert.Equal(t, `level=DEBUG msg="world set active" subsystem=worlds world=*ecs_test.hookWorld depth=1`, lines[1])
+	assert.Contains(t, lines[2], `msg="system added" subsystem=systems`)
+	assert.Equal(t, `level=DEBUG msg="world pushed" subsystem=worlds world=*ecs_test.hookWorld depth=2`, lines[3])
+	assert.Equal(t, `level=DEBUG msg="world popped" subsystem=worlds world=*ecs_test.hookWorld depth=1`, lines[4])
+
+	buf.Reset()
+	world := game.ActiveWorld().(*hookWorld)
+	entityID := world.EntityManager().NewEntity()
+
+	assert.Equal(t, []string{
+		fmt.Sprintf("level=DEBUG msg=\"entity created\" subsystem=entities entity=%d", entityID),
+	}, logLines(&buf), "the world's EntityManager inherits the game logger")
+}
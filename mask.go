@@ -0,0 +1,152 @@
+package ecs
+
+import (
+	"iter"
+	"math/bits"
+	"reflect"
+)
+
+// componentMask is a bitset of component types. Each component type is assigned a bit
+// when its storage is created, so testing whether an entity has a set of components
+// is a single AND per 64 types instead of one lookup per type.
+type componentMask []uint64
+
+func (m componentMask) has(bit int) bool {
+	word := bit / 64
+	return word < len(m) && m[word]&(1<<(bit%64)) != 0
+}
+
+func (m *componentMask) set(bit int) {
+	word := bit / 64
+	for len(*m) <= word {
+		*m = append(*m, 0)
+	}
+
+	(*m)[word] |= 1 << (bit % 64)
+}
+
+func (m componentMask) clear(bit int) {
+	if word := bit / 64; word < len(m) {
+		m[word] &^= 1 << (bit % 64)
+	}
+}
+
+// containsAll reports whether every bit of other is set in m.
+func (m componentMask) containsAll(other componentMask) bool {
+	for i, word := range other {
+		if word == 0 {
+			continue
+		}
+
+		if i >= len(m) || m[i]&word != word {
+			return false
+		}
+	}
+
+	return true
+}
+
+// intersects reports whether m and other have a bit in common.
+func (m componentMask) intersects(other componentMask) bool {
+	for i := range min(len(m), len(other)) {
+		if m[i]&other[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// all returns the set bits in increasing order.
+func (m componentMask) all() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, word := range m {
+			for word != 0 {
+				bit := bits.TrailingZeros64(word)
+				if !yield(i*64 + bit) {
+					return
+				}
+				word &^= 1 << bit
+			}
+		}
+	}
+}
+
+// registerStorage adds the storage of a component type and assigns the type its mask bit.
+// Types registered with RegisterComponent get the bit of their ID, ID-1, so their bits are the same
+// in every EntityManager, unless the bit was taken by a type registered after its storage was created.
+// Other types get the first free bit after the registered types.
+func (em *EntityManager) registerStorage(componentType reflect.Type, storage componentStorage) {
+	bit := registeredComponentCount()
+	if registered, ok := registeredComponentOf(componentType); ok {
+		bit = int(registered.id) - 1
+	}
+
+	for bit < len(em.componentTypes) && em.componentTypes[bit] != nil {
+		bit++
+	}
+
+	for len(em.componentTypes) <= bit {
+		em.componentTypes = append(em.componentTypes, nil)
+	}
+
+	em.componentContainers[componentType] = storage
+	em.componentBits[componentType] = bit
+	em.componentTypes[bit] = componentType
+	em.storageVersion++
+}
+
+// hasComponentType reports whether the entity has a component of the type.
+func (em *EntityManager) hasComponentType(entityID EntityID, componentType reflect.Type) bool {
+	bit, ok := em.componentBits[componentType]
+	if !ok {
+		return false
+	}
+
+	return em.entityMasks[entityID].has(bit)
+}
+
+// setComponentBit records that the entity has a component of the type, whose storage must exist.
+func (em *EntityManager) setComponentBit(entityID EntityID, componentType reflect.Type) {
+	mask := em.entityMasks[entityID]
+	mask.set(em.componentBits[componentType])
+	em.entityMasks[entityID] = mask
+
+	// The component is not in the spatial index yet.
+	em.invalidateSpatialIndex(componentType)
+}
+
+// clearComponentBit records that the entity no longer has a component of the type.
+func (em *EntityManager) clearComponentBit(entityID EntityID, componentType reflect.Type) {
+	if bit, ok := em.componentBits[componentType]; ok {
+		em.entityMasks[entityID].clear(bit)
+	}
+}
+
+// entityComponentTypes returns the component types of the entity in mask bit order, see registerStorage:
+// registered types by ID, then the others in the order their storages were created.
+func (em *EntityManager) entityComponentTypes(entityID EntityID) iter.Seq[reflect.Type] {
+	return func(yield func(reflect.Type) bool) {
+		for bit := range em.entityMasks[entityID].all() {
+			if !yield(em.componentTypes[bit]) {
+				return
+			}
+		}
+	}
+}
+
+// maskOf returns the mask of the component types.
+// It returns false if any of the types has no storage, and so no bit, yet.
+func (em *EntityManager) maskOf(componentTypes []reflect.Type) (componentMask, bool) {
+	var mask componentMask
+	for _, componentType := range componentTypes {
+		bit, ok := em.componentBits[componentType]
+		if !ok {
+			return nil, false
+		}
+
+		mask.set(bit)
+	}
+
+	return mask, true
+}
@@ -0,0 +1,50 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type wide[T any] struct{}
+
+func wideComponent[T any](em *ecs.EntityManager, entityID ecs.EntityID) {
+	ecs.AddComponent[wide[T]](em, entityID)
+}
+
+// TestQueryManyComponentTypes registers more component types than fit in a single mask word.
+func TestQueryManyComponentTypes(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	adders := []func(*ecs.EntityManager, ecs.EntityID){
+		wideComponent[[1]byte], wideComponent[[2]byte], wideComponent[[3]byte], wideComponent[[4]byte], wideComponent[[5]byte], wideComponent[[6]byte], wideComponent[[7]byte], wideComponent[[8]byte], wideComponent[[9]byte], wideComponent[[10]byte],
+		wideComponent[[11]byte], wideComponent[[12]byte], wideComponent[[13]byte], wideComponent[[14]byte], wideComponent[[15]byte], wideComponent[[16]byte], wideComponent[[17]byte], wideComponent[[18]byte], wideComponent[[19]byte], wideComponent[[20]byte],
+		wideComponent[[21]byte], wideComponent[[22]byte], wideComponent[[23]byte], wideComponent[[24]byte], wideComponent[[25]byte], wideComponent[[26]byte], wideComponent[[27]byte], wideComponent[[28]byte], wideComponent[[29]byte], wideComponent[[30]byte],
+		wideComponent[[31]byte], wideComponent[[32]byte], wideComponent[[33]byte], wideComponent[[34]byte], wideComponent[[35]byte], wideComponent[[36]byte], wideComponent[[37]byte], wideComponent[[38]byte], wideComponent[[39]byte], wideComponent[[40]byte],
+		wideComponent[[41]byte], wideComponent[[42]byte], wideComponent[[43]byte], wideComponent[[44]byte], wideComponent[[45]byte], wideComponent[[46]byte], wideComponent[[47]byte], wideComponent[[48]byte], wideComponent[[49]byte], wideComponent[[50]byte],
+		wideComponent[[51]byte], wideComponent[[52]byte], wideComponent[[53]byte], wideComponent[[54]byte], wideComponent[[55]byte], wideComponent[[56]byte], wideComponent[[57]byte], wideComponent[[58]byte], wideComponent[[59]byte], wideComponent[[60]byte],
+		wideComponent[[61]byte], wideComponent[[62]byte], wideComponent[[63]byte], wideComponent[[64]byte], wideComponent[[65]byte], wideComponent[[66]byte], wideComponent[[67]byte], wideComponent[[68]byte], wideComponent[[69]byte], wideComponent[[70]byte],
+	}
+
+	full := em.NewEntity()
+	for _, add := range adders {
+		add(em, full)
+	}
+	ecs.AddComponent[TransformComponent](em, full)
+	ecs.AddComponent[CameraComponent](em, full)
+
+	partial := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, partial)
+	ecs.AddComponent[CameraComponent](em, partial)
+
+	assert.ElementsMatch(t, []ecs.EntityID{full, partial}, slices.Collect(ecs.Query2[TransformComponent, CameraComponent](em)))
+	assert.ElementsMatch(t, []ecs.EntityID{full}, slices.Collect(ecs.Query3[wide[[1]byte], wide[[70]byte], CameraComponent](em)))
+	assert.ElementsMatch(t, []ecs.EntityID{partial}, slices.Collect(ecs.NewQuery[CameraComponent](em).Without(wide[[65]byte]{}).Iter()))
+
+	ecs.RemoveComponent[wide[[70]byte]](em, full)
+	assert.False(t, ecs.HasComponent[wide[[70]byte]](em, full))
+	assert.True(t, ecs.HasComponent[wide[[69]byte]](em, full))
+	assert.Empty(t, slices.Collect(ecs.Query3[wide[[1]byte], wide[[70]byte], CameraComponent](em)))
+}
@@ -0,0 +1,73 @@
+package ecs
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// layeredWorld is a world added with Game.AddWorld.
+type layeredWorld struct {
+	world World
+	order int
+}
+
+// AddWorld initializes the world and runs it alongside the world stack, with its own EntityManager and
+// SystemManager but the game's resources, e.g. a UI world that persists across level changes. Every Update
+// and Draw, the worlds run in ascending order: those with a negative order before the active world of the
+// stack, the others after it, so drawn over it. Worlds of the same order run in the order they were added.
+func (g *Game) AddWorld(world World, order int) error {
+	if err := g.initWorld(world); err != nil {
+		return fmt.Errorf("ecs.Game.AddWorld g.initWorld error: %w", err)
+	}
+
+	index, _ := slices.BinarySearchFunc(g.layers, order+1, func(l layeredWorld, order int) int {
+		return l.order - order
+	})
+	g.layers = slices.Insert(g.layers, index, layeredWorld{world: world, order: order})
+	startWorld(world)
+	g.logWorld("world added", world)
+
+	return nil
+}
+
+// RemoveWorld stops and tears down a world added with AddWorld.
+func (g *Game) RemoveWorld(world World) {
+	index := slices.IndexFunc(g.layers, func(l layeredWorld) bool { return l.world == world })
+	if index < 0 {
+		return
+	}
+
+	g.layers = slices.Delete(g.layers, index, index+1)
+	stopWorld(world)
+	world.Teardown()
+	g.logWorld("world removed", world)
+}
+
+// Worlds returns the running worlds in update order: the worlds added with AddWorld, and the active world
+// of the stack at order 0.
+func (g *Game) Worlds() iter.Seq[World] {
+	return func(yield func(World) bool) {
+		// The active world is looked up when its turn comes, in case a world before it switched it.
+		stackDone := false
+		yieldActive := func() bool {
+			stackDone = true
+			active := g.ActiveWorld()
+			return active == nil || yield(active)
+		}
+
+		for _, layer := range slices.Clone(g.layers) {
+			if !stackDone && layer.order >= 0 && !yieldActive() {
+				return
+			}
+
+			if !yield(layer.world) {
+				return
+			}
+		}
+
+		if !stackDone {
+			yieldActive()
+		}
+	}
+}
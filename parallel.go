@@ -0,0 +1,118 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minParallelChunk is the smallest number of components handed to a worker at once,
+// so the scheduling overhead stays small compared to the work per chunk.
+const minParallelChunk = 256
+
+// ParallelEach calls fn for every active entity with component C, splitting the component storage into
+// chunks that are processed by a pool of workers goroutines (GOMAXPROCS if workers <= 0).
+// It returns once every component has been visited.
+//
+// No structural changes can occur during the call: creating or removing entities and adding or
+// removing components panics until ParallelEach returns. fn may modify the component it is given
+// and read other components with GetComponent, but must not issue queries, which are not safe for
+// concurrent use, nor write to state shared with other invocations without synchronization.
+//
+// A panic in fn is re-raised in the calling goroutine after all workers have stopped.
+func ParallelEach[C any](em *EntityManager, workers int, fn func(EntityID, *C)) {
+	container, exists := em.componentContainers[reflect.TypeFor[C]()]
+	if !exists {
+		return
+	}
+
+	count := container.Count()
+	if count == 0 {
+		return
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	em.structuralLocks.Add(1)
+	defer em.structuralLocks.Add(-1)
+
+	// A few chunks per worker balances uneven per-entity costs.
+	chunkSize := max(minParallelChunk, (count+4*workers-1)/(4*workers))
+	chunks := (count + chunkSize - 1) / chunkSize
+	workers = min(workers, chunks)
+
+	each := func(start, end int) {
+		for i := start; i < end; i++ {
+			entityID, component := container.entry(i)
+			if em.hidden(entityID) {
+				continue
+			}
+
+			fn(entityID, component.(*C))
+		}
+	}
+
+	if workers == 1 {
+		each(0, count)
+		return
+	}
+
+	var (
+		wg        sync.WaitGroup
+		nextChunk atomic.Int64
+		panicOnce sync.Once
+		panicked  any
+	)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { panicked = r })
+					// Stop the other workers from picking up new chunks.
+					nextChunk.Store(int64(chunks))
+				}
+			}()
+
+			for {
+				chunk := int(nextChunk.Add(1) - 1)
+				if chunk >= chunks {
+					return
+				}
+
+				start := chunk * chunkSize
+				each(start, min(start+chunkSize, count))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if panicked != nil {
+		panic(panicked)
+	}
+}
+
+// beginStructural panics if a structural change is attempted while ParallelEach is running,
+// or in strict mode while a query is iterated, see SetStrict. Otherwise it takes the structural lock
+// of a concurrent EntityManager and returns the function releasing it, see NewConcurrentEntityManager.
+func (em *EntityManager) beginStructural(op string) func() {
+	if em.structuralLocks.Load() > 0 {
+		panic(fmt.Sprintf("ecs.%s: structural change during ParallelEach", op))
+	}
+
+	// Holding the structural lock, only the owner's own iterations are counted, not those inside Read.
+	unlock := em.lockStructure()
+	if em.iterating.Load() > 0 {
+		unlock()
+		panic(fmt.Sprintf("ecs.%s: structural change while iterating a query, defer it with EntityManager.Commands", op))
+	}
+
+	return unlock
+}
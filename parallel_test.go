@@ -0,0 +1,48 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelEach(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	const n = 10_000
+	for i := range n {
+		ecs.AddComponent[TransformComponent](em, em.NewEntity()).Rotation = float64(i)
+	}
+
+	ecs.ParallelEach(em, 4, func(_ ecs.EntityID, tr *TransformComponent) {
+		tr.Position[0] = tr.Rotation * 2
+	})
+
+	for id := range ecs.Query[TransformComponent](em) {
+		tr := ecs.MustGetComponent[TransformComponent](em, id)
+		assert.Equal(t, tr.Rotation*2, tr.Position[0])
+	}
+
+	assert.PanicsWithValue(t, "ecs.EntityManager.NewEntity: structural change during ParallelEach", func() {
+		ecs.ParallelEach(em, 4, func(ecs.EntityID, *TransformComponent) {
+			em.NewEntity()
+		})
+	})
+
+	// The lock is released once ParallelEach returns, even after a panic.
+	assert.NotPanics(t, func() { em.NewEntity() })
+}
+
+func BenchmarkParallelEach(b *testing.B) {
+	em := ecs.NewEntityManager()
+	for range 1_000_000 {
+		ecs.AddComponent[TransformComponent](em, em.NewEntity())
+	}
+
+	for b.Loop() {
+		ecs.ParallelEach(em, 0, func(_ ecs.EntityID, tr *TransformComponent) {
+			tr.Rotation += 0.1
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package pathfind
+
+import (
+	"container/heap"
+	"image"
+	"slices"
+)
+
+// FindPath returns the shortest path of nodes from one node to another, both included, using A*.
+// It returns false if to cannot be reached.
+func FindPath(g Graph, from, to image.Point) ([]image.Point, bool) {
+	s := newSearch(g, from, to)
+	s.expand(-1)
+
+	return s.path, s.found
+}
+
+type openNode struct {
+	node     image.Point
+	priority float64
+}
+
+type openSet []openNode
+
+func (o openSet) Len() int           { return len(o) }
+func (o openSet) Less(i, j int) bool { return o[i].priority < o[j].priority }
+func (o openSet) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
+func (o *openSet) Push(x any)        { *o = append(*o, x.(openNode)) }
+func (o *openSet) Pop() any {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+
+	return n
+}
+
+// search is an A* search that can be advanced a few nodes at a time.
+type search struct {
+	graph    Graph
+	goal     image.Point
+	open     openSet
+	cameFrom map[image.Point]image.Point
+	cost     map[image.Point]float64
+	edges    []Edge
+
+	done  bool
+	found bool
+	path  []image.Point
+}
+
+func newSearch(g Graph, from, to image.Point) *search {
+	s := &search{
+		graph:    g,
+		goal:     to,
+		cameFrom: make(map[image.Point]image.Point),
+		cost:     map[image.Point]float64{from: 0},
+	}
+	heap.Push(&s.open, openNode{node: from, priority: g.Heuristic(from, to)})
+
+	return s
+}
+
+// expand expands up to budget nodes, or as many as needed if budget is negative.
+// It returns the number of nodes expanded and whether the search is over.
+func (s *search) expand(budget int) (int, bool) {
+	expanded := 0
+	for !s.done && s.open.Len() > 0 && (budget < 0 || expanded < budget) {
+		current := heap.Pop(&s.open).(openNode)
+		if current.priority > s.cost[current.node]+s.graph.Heuristic(current.node, s.goal) {
+			// A stale entry for a node that was reached more cheaply since it was pushed.
+			continue
+		}
+
+		expanded++
+		if current.node == s.goal {
+			s.finish(true)
+			break
+		}
+
+		s.edges = s.graph.Neighbors(current.node, s.edges[:0])
+		for _, edge := range s.edges {
+			cost := s.cost[current.node] + edge.Cost
+			if known, ok := s.cost[edge.To]; ok && known <= cost {
+				continue
+			}
+
+			s.cost[edge.To] = cost
+			s.cameFrom[edge.To] = current.node
+			heap.Push(&s.open, openNode{node: edge.To, priority: cost + s.graph.Heuristic(edge.To, s.goal)})
+		}
+	}
+
+	if !s.done && s.open.Len() == 0 {
+		s.finish(false)
+	}
+
+	return expanded, s.done
+}
+
+func (s *search) finish(found bool) {
+	s.done = true
+	s.found = found
+	if !found {
+		return
+	}
+
+	s.path = []image.Point{s.goal}
+	for node := s.goal; ; {
+		previous, ok := s.cameFrom[node]
+		if !ok {
+			break
+		}
+		s.path = append(s.path, previous)
+		node = previous
+	}
+	slices.Reverse(s.path)
+}
@@ -0,0 +1,231 @@
+// Package pathfind finds paths with A* over navigation graphs, such as a Grid built from a Tiled map,
+// and moves entities along them.
+package pathfind
+
+import (
+	"image"
+	"math"
+
+	"github.com/samix73/ebiten-ecs/tilemap"
+	"golang.org/x/image/math/f64"
+)
+
+// Edge is a move from a node of a Graph to a neighbor.
+type Edge struct {
+	To   image.Point
+	Cost float64
+}
+
+// Graph is a navigation graph searched by FindPath. Grid is the built-in implementation;
+// games can plug in their own, e.g. a waypoint graph or a hex grid.
+type Graph interface {
+	// Neighbors appends the edges leaving node to edges and returns the result.
+	Neighbors(node image.Point, edges []Edge) []Edge
+	// Heuristic estimates the cost from a to b. It must never overestimate it for paths to be shortest.
+	Heuristic(a, b image.Point) float64
+}
+
+// Grid is a navigation grid of equally sized cells that are either blocked or walkable with a cost.
+type Grid struct {
+	Width, Height         int
+	CellWidth, CellHeight float64
+	// Diagonal allows diagonal moves, except across the corners of blocked cells.
+	Diagonal bool
+
+	// costs holds the cost of entering each cell in row-major order; blocked cells are +Inf.
+	costs []float64
+}
+
+var _ Graph = (*Grid)(nil)
+
+// NewGrid creates a grid of width x height walkable cells of cost 1.
+func NewGrid(width, height int, cellWidth, cellHeight float64) *Grid {
+	costs := make([]float64, width*height)
+	for i := range costs {
+		costs[i] = 1
+	}
+
+	return &Grid{
+		Width:      width,
+		Height:     height,
+		CellWidth:  cellWidth,
+		CellHeight: cellHeight,
+		costs:      costs,
+	}
+}
+
+// GridFromMap builds a grid with one cell per tile of m. Cells are blocked if they hold a tile of a layer
+// with the "collision" property, a tile whose tileset entry has it, or overlap a collision object,
+// following the same tilemap.CollisionProperty rules as tilemap.Spawner.
+// Walkable tiles with a "cost" property use it as the cost of entering the cell.
+func GridFromMap(m *tilemap.Map) *Grid {
+	g := NewGrid(m.Width, m.Height, float64(m.TileWidth), float64(m.TileHeight))
+
+	for _, layer := range m.Layers {
+		layerCollision := layer.Properties.Bool(tilemap.CollisionProperty, false)
+
+		for y := range layer.Height {
+			for x := range layer.Width {
+				gid := layer.TileAt(x, y)
+				if gid == 0 {
+					continue
+				}
+
+				blocked, cost := layerCollision, 0.0
+				if tileset, ok := m.TilesetFor(gid); ok {
+					if tile, ok := tileset.Tiles[tilemap.GID(gid)-tileset.FirstGID]; ok {
+						blocked = tile.Properties.Bool(tilemap.CollisionProperty, blocked)
+						cost = tile.Properties.Float("cost", 0)
+					}
+				}
+
+				switch {
+				case blocked:
+					g.SetBlocked(x, y, true)
+				case cost > 0:
+					g.SetCost(x, y, cost)
+				}
+			}
+		}
+	}
+
+	for _, group := range m.ObjectGroups {
+		groupCollision := group.Properties.Bool(tilemap.CollisionProperty, false)
+
+		for _, object := range group.Objects {
+			if !object.Properties.Bool(tilemap.CollisionProperty, groupCollision) {
+				continue
+			}
+
+			g.BlockRect(object.X+group.OffsetX, object.Y+group.OffsetY, object.Width, object.Height)
+		}
+	}
+
+	return g
+}
+
+// In reports whether the cell is inside the grid.
+func (g *Grid) In(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.Width && y < g.Height
+}
+
+// Blocked reports whether the cell is blocked. Cells outside the grid are blocked.
+func (g *Grid) Blocked(x, y int) bool {
+	return !g.In(x, y) || math.IsInf(g.costs[y*g.Width+x], 1)
+}
+
+// SetBlocked blocks or unblocks a cell. Unblocked cells have cost 1.
+func (g *Grid) SetBlocked(x, y int, blocked bool) {
+	if !g.In(x, y) {
+		return
+	}
+
+	if blocked {
+		g.costs[y*g.Width+x] = math.Inf(1)
+	} else {
+		g.costs[y*g.Width+x] = 1
+	}
+}
+
+// Cost returns the cost of entering the cell, +Inf if it is blocked.
+func (g *Grid) Cost(x, y int) float64 {
+	if !g.In(x, y) {
+		return math.Inf(1)
+	}
+
+	return g.costs[y*g.Width+x]
+}
+
+// SetCost sets the cost of entering a cell, e.g. higher for mud. Costs below 1 make the heuristic
+// overestimate, so paths may no longer be the shortest.
+func (g *Grid) SetCost(x, y int, cost float64) {
+	if g.In(x, y) {
+		g.costs[y*g.Width+x] = cost
+	}
+}
+
+// BlockRect blocks the cells overlapping a rectangle in world coordinates.
+func (g *Grid) BlockRect(x, y, width, height float64) {
+	minX, minY := int(math.Floor(x/g.CellWidth)), int(math.Floor(y/g.CellHeight))
+	maxX, maxY := int(math.Ceil((x+width)/g.CellWidth)), int(math.Ceil((y+height)/g.CellHeight))
+
+	for cy := max(minY, 0); cy < min(maxY, g.Height); cy++ {
+		for cx := max(minX, 0); cx < min(maxX, g.Width); cx++ {
+			g.SetBlocked(cx, cy, true)
+		}
+	}
+}
+
+// CellAt returns the cell containing a world position.
+func (g *Grid) CellAt(position f64.Vec2) image.Point {
+	return image.Pt(int(math.Floor(position[0]/g.CellWidth)), int(math.Floor(position[1]/g.CellHeight)))
+}
+
+// CellCenter returns the world position of the center of a cell.
+func (g *Grid) CellCenter(cell image.Point) f64.Vec2 {
+	return f64.Vec2{(float64(cell.X) + 0.5) * g.CellWidth, (float64(cell.Y) + 0.5) * g.CellHeight}
+}
+
+var (
+	straightMoves = []image.Point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	diagonalMoves = []image.Point{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+)
+
+func (g *Grid) Neighbors(node image.Point, edges []Edge) []Edge {
+	for _, move := range straightMoves {
+		next := node.Add(move)
+		if !g.Blocked(next.X, next.Y) {
+			edges = append(edges, Edge{To: next, Cost: g.Cost(next.X, next.Y)})
+		}
+	}
+
+	if !g.Diagonal {
+		return edges
+	}
+
+	for _, move := range diagonalMoves {
+		next := node.Add(move)
+		if g.Blocked(next.X, next.Y) || g.Blocked(node.X+move.X, node.Y) || g.Blocked(node.X, node.Y+move.Y) {
+			continue
+		}
+
+		edges = append(edges, Edge{To: next, Cost: math.Sqrt2 * g.Cost(next.X, next.Y)})
+	}
+
+	return edges
+}
+
+// Heuristic returns the octile distance when diagonal moves are allowed, the Manhattan distance otherwise.
+func (g *Grid) Heuristic(a, b image.Point) float64 {
+	dx, dy := math.Abs(float64(a.X-b.X)), math.Abs(float64(a.Y-b.Y))
+	if !g.Diagonal {
+		return dx + dy
+	}
+
+	return max(dx, dy) + (math.Sqrt2-1)*min(dx, dy)
+}
+
+// FindPath returns the waypoints from one world position to another: the centers of the cells along
+// the shortest path, excluding the start cell, ending with to itself. It returns false if to cannot be reached.
+func (g *Grid) FindPath(from, to f64.Vec2) ([]f64.Vec2, bool) {
+	cells, ok := FindPath(g, g.CellAt(from), g.CellAt(to))
+	if !ok {
+		return nil, false
+	}
+
+	return g.waypoints(cells, to), true
+}
+
+// waypoints converts a path of cells to world positions, replacing the goal cell by the exact goal.
+func (g *Grid) waypoints(cells []image.Point, goal f64.Vec2) []f64.Vec2 {
+	if len(cells) <= 1 {
+		return []f64.Vec2{goal}
+	}
+
+	waypoints := make([]f64.Vec2, 0, len(cells)-1)
+	for _, cell := range cells[1 : len(cells)-1] {
+		waypoints = append(waypoints, g.CellCenter(cell))
+	}
+
+	return append(waypoints, goal)
+}
@@ -0,0 +1,125 @@
+package pathfind_test
+
+import (
+	"image"
+	"testing"
+	"testing/fstest"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/pathfind"
+	"github.com/samix73/ebiten-ecs/tilemap"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+// testMap is a 5x3 map with a wall of tiles in column 2, open at the bottom,
+// and a collision object covering cell (4, 0).
+const testMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="5" height="3" tilewidth="16" tileheight="16" infinite="0">
+ <tileset firstgid="1" name="tiles" tilewidth="16" tileheight="16" tilecount="2" columns="2">
+  <tile id="1">
+   <properties>
+    <property name="collision" type="bool" value="true"/>
+   </properties>
+  </tile>
+ </tileset>
+ <layer id="1" name="ground" width="5" height="3">
+  <data encoding="csv">
+1,1,2,1,0,
+1,1,2,1,1,
+1,1,1,1,1
+</data>
+ </layer>
+ <objectgroup id="2" name="walls">
+  <properties>
+   <property name="collision" type="bool" value="true"/>
+  </properties>
+  <object id="1" x="64" y="0" width="16" height="16"/>
+ </objectgroup>
+</map>`
+
+func testGrid(t *testing.T) *pathfind.Grid {
+	m, err := tilemap.Load(fstest.MapFS{"level.tmx": {Data: []byte(testMap)}}, "level.tmx")
+	require.NoError(t, err)
+
+	return pathfind.GridFromMap(m)
+}
+
+func TestGridFromMap(t *testing.T) {
+	grid := testGrid(t)
+
+	assert.True(t, grid.Blocked(2, 0))
+	assert.True(t, grid.Blocked(2, 1))
+	assert.False(t, grid.Blocked(2, 2))
+	assert.True(t, grid.Blocked(4, 0), "collision objects block the cells they overlap")
+	assert.True(t, grid.Blocked(-1, 0), "cells outside the grid are blocked")
+}
+
+func TestFindPath(t *testing.T) {
+	grid := testGrid(t)
+
+	path, ok := pathfind.FindPath(grid, image.Pt(0, 0), image.Pt(3, 0))
+	require.True(t, ok)
+	assert.Len(t, path, 8)
+	assert.Equal(t, image.Pt(2, 2), path[4], "the path goes through the gap")
+
+	grid.Diagonal = true
+	path, ok = pathfind.FindPath(grid, image.Pt(0, 0), image.Pt(3, 0))
+	require.True(t, ok)
+	assert.Len(t, path, 7, "diagonal moves shorten the path but do not cut the wall corners")
+
+	grid.SetBlocked(2, 2, true)
+	_, ok = pathfind.FindPath(grid, image.Pt(0, 0), image.Pt(3, 0))
+	assert.False(t, ok)
+
+	waypoints, ok := grid.FindPath(f64.Vec2{8, 8}, f64.Vec2{10, 40})
+	require.True(t, ok)
+	assert.Equal(t, []f64.Vec2{{8, 24}, {10, 40}}, waypoints, "cell centers, ending with the exact goal")
+}
+
+func TestSystems(t *testing.T) {
+	grid := testGrid(t)
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	planner := pathfind.NewPlannerSystem(ecs.NextID(), 0, grid, 4)
+	sm.Add(planner, pathfind.NewFollowSystem(ecs.NextID(), 1))
+
+	goblin := em.NewEntity()
+	tr := ecs.AddComponent[transform.Transform](em, goblin)
+	tr.Position = f64.Vec2{8, 8}
+	ecs.AddComponent[pathfind.PathRequest](em, goblin).Goal = f64.Vec2{56, 8}
+
+	walled := em.NewEntity()
+	ecs.AddComponent[transform.Transform](em, walled).Position = f64.Vec2{8, 8}
+	ecs.AddComponent[pathfind.PathRequest](em, walled).Goal = f64.Vec2{72, 8}
+
+	step := func() {
+		game.Time().Advance(0.5)
+		require.NoError(t, sm.Update())
+	}
+
+	step()
+	assert.Equal(t, 2, planner.Pending(), "searches are spread over several updates")
+	assert.False(t, ecs.HasComponent[pathfind.PathFollow](em, goblin))
+
+	for range 10 {
+		step()
+	}
+	assert.Zero(t, planner.Pending())
+	assert.False(t, ecs.HasComponent[pathfind.PathRequest](em, goblin))
+
+	follow := ecs.MustGetComponent[pathfind.PathFollow](em, goblin)
+	assert.False(t, follow.Unreachable)
+	assert.Equal(t, f64.Vec2{56, 8}, follow.Waypoints[len(follow.Waypoints)-1])
+	assert.True(t, ecs.MustGetComponent[pathfind.PathFollow](em, walled).Unreachable)
+
+	follow.Speed = 1000
+	step()
+	assert.True(t, follow.Arrived())
+	assert.Equal(t, f64.Vec2{56, 8}, tr.Position)
+}
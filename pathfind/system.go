@@ -0,0 +1,203 @@
+package pathfind
+
+import (
+	"math"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// PathRequest asks the PlannerSystem for a path from the entity's transform.Transform position to Goal.
+// The request is removed once the path has been found, and the result stored in the entity's PathFollow,
+// which is added if needed.
+type PathRequest struct {
+	Goal f64.Vec2
+}
+
+func (r *PathRequest) Reset() {
+	r.Goal = f64.Vec2{}
+}
+
+// PathFollow moves an entity along a path with the FollowSystem.
+type PathFollow struct {
+	Waypoints []f64.Vec2
+	// Next is the index of the waypoint the entity is heading to.
+	Next int
+	// Speed is in pixels per second.
+	Speed float64
+	// Unreachable is set by the PlannerSystem when the last requested goal could not be reached.
+	Unreachable bool
+}
+
+func (p *PathFollow) Reset() {
+	p.Waypoints = nil
+	p.Next = 0
+	p.Speed = 0
+	p.Unreachable = false
+}
+
+// Arrived reports whether the entity has reached the last waypoint.
+func (p *PathFollow) Arrived() bool {
+	return p.Next >= len(p.Waypoints)
+}
+
+// PlannerSystem resolves PathRequests on a Grid in the background: searches are spread over several updates,
+// expanding at most a budget of cells per update across all pending requests, so requests by many entities
+// at once do not cause a frame spike.
+type PlannerSystem struct {
+	*ecs.BaseSystem
+
+	grid   *Grid
+	budget int
+
+	pending  map[ecs.EntityID]*plan
+	order    []ecs.EntityID
+	finished []ecs.EntityID
+}
+
+type plan struct {
+	search *search
+	goal   f64.Vec2
+}
+
+// NewPlannerSystem creates a PlannerSystem searching grid, expanding at most budget cells per update.
+func NewPlannerSystem(id ecs.SystemID, priority int, grid *Grid, budget int) *PlannerSystem {
+	s := &PlannerSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		grid:       grid,
+		budget:     max(budget, 1),
+		pending:    make(map[ecs.EntityID]*plan),
+	}
+	s.SetAccess(ecs.SystemAccess{
+		Reads:  ecs.Types(transform.Transform{}),
+		Writes: ecs.Types(PathRequest{}, PathFollow{}),
+	})
+
+	return s
+}
+
+// Pending returns the number of requests being searched.
+func (s *PlannerSystem) Pending() int {
+	return len(s.pending)
+}
+
+// Update starts searches for new requests, then advances the pending searches in request order.
+func (s *PlannerSystem) Update() error {
+	em := s.EntityManager()
+
+	for entityID := range ecs.Query2[PathRequest, transform.Transform](em) {
+		request := ecs.MustGetComponent[PathRequest](em, entityID)
+
+		if p, ok := s.pending[entityID]; ok && p.goal == request.Goal {
+			continue
+		}
+
+		// A new request, or the goal changed since the search started.
+		if _, ok := s.pending[entityID]; !ok {
+			s.order = append(s.order, entityID)
+		}
+
+		from := ecs.MustGetComponent[transform.Transform](em, entityID).Position
+		s.pending[entityID] = &plan{
+			search: newSearch(s.grid, s.grid.CellAt(from), s.grid.CellAt(request.Goal)),
+			goal:   request.Goal,
+		}
+	}
+
+	budget := s.budget
+	s.finished = s.finished[:0]
+
+	for _, entityID := range s.order {
+		if budget == 0 {
+			break
+		}
+
+		p := s.pending[entityID]
+		if !ecs.HasComponent[PathRequest](em, entityID) {
+			// The request was removed, or the entity with it.
+			s.finished = append(s.finished, entityID)
+			continue
+		}
+
+		expanded, done := p.search.expand(budget)
+		budget -= expanded
+		if !done {
+			continue
+		}
+
+		s.finished = append(s.finished, entityID)
+		s.complete(em, entityID, p)
+	}
+
+	for _, entityID := range s.finished {
+		delete(s.pending, entityID)
+	}
+
+	s.order = slices.DeleteFunc(s.order, func(entityID ecs.EntityID) bool {
+		_, ok := s.pending[entityID]
+		return !ok
+	})
+
+	return nil
+}
+
+func (s *PlannerSystem) complete(em *ecs.EntityManager, entityID ecs.EntityID, p *plan) {
+	follow := ecs.AddComponent[PathFollow](em, entityID)
+	follow.Next = 0
+	follow.Unreachable = !p.search.found
+
+	follow.Waypoints = follow.Waypoints[:0]
+	if p.search.found {
+		follow.Waypoints = append(follow.Waypoints, s.grid.waypoints(p.search.path, p.goal)...)
+	}
+
+	ecs.DeferRemoveComponent[PathRequest](em.Commands(), entityID)
+}
+
+// FollowSystem moves entities with a PathFollow and a transform.Transform along their waypoints.
+type FollowSystem struct {
+	*ecs.BaseSystem
+}
+
+// NewFollowSystem creates a new FollowSystem with the given ID and priority.
+func NewFollowSystem(id ecs.SystemID, priority int) *FollowSystem {
+	s := &FollowSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+	s.SetAccess(ecs.SystemAccess{Writes: ecs.Types(PathFollow{}, transform.Transform{})})
+
+	return s
+}
+
+// Update moves all followers.
+func (s *FollowSystem) Update() error {
+	em := s.EntityManager()
+	dt := s.Time().Delta()
+
+	for entityID := range ecs.Query2[PathFollow, transform.Transform](em) {
+		follow := ecs.MustGetComponent[PathFollow](em, entityID)
+		tr := ecs.MustGetComponent[transform.Transform](em, entityID)
+
+		distance := follow.Speed * dt
+		for distance > 0 && !follow.Arrived() {
+			target := follow.Waypoints[follow.Next]
+			dx, dy := target[0]-tr.Position[0], target[1]-tr.Position[1]
+			remaining := math.Hypot(dx, dy)
+
+			if remaining <= distance {
+				tr.Position = target
+				distance -= remaining
+				follow.Next++
+				continue
+			}
+
+			tr.Position[0] += dx / remaining * distance
+			tr.Position[1] += dy / remaining * distance
+			distance = 0
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Phase is a stage of the update loop. All systems of a phase are updated before those of the next one.
+type Phase int
+
+const (
+	// PhasePreUpdate runs before gameplay, e.g. input gathering.
+	PhasePreUpdate Phase = iota - 2
+	// PhaseFixedUpdate runs at a fixed rate, zero or more times per update, with Time.Delta set to
+	// Time.FixedStep, e.g. physics. See SystemManager.Update.
+	PhaseFixedUpdate
+	// PhaseUpdate is the default phase of gameplay systems.
+	PhaseUpdate
+	// PhasePostUpdate runs after gameplay, e.g. transform propagation and cleanup.
+	PhasePostUpdate
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhasePreUpdate:
+		return "PreUpdate"
+	case PhaseFixedUpdate:
+		return "FixedUpdate"
+	case PhaseUpdate:
+		return "Update"
+	case PhasePostUpdate:
+		return "PostUpdate"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+}
+
+// SystemAccess declares which component and event types a system uses.
+// It documents the data flow between systems for schedulers and tools, and is not enforced.
+type SystemAccess struct {
+	// Reads are the component types the system only reads.
+	Reads []reflect.Type
+	// Writes are the component types the system modifies.
+	Writes []reflect.Type
+	// Consumes are the event types the system handles.
+	Consumes []reflect.Type
+	// Produces are the event types the system emits.
+	Produces []reflect.Type
+}
+
+// Types returns the reflect.Type of each value, so access sets can be declared with zero values:
+//
+//	ecs.SystemAccess{Reads: ecs.Types(Velocity{}), Writes: ecs.Types(Transform{})}
+func Types(values ...any) []reflect.Type {
+	types := make([]reflect.Type, len(values))
+	for i, value := range values {
+		types[i] = reflect.TypeOf(value)
+	}
+
+	return types
+}
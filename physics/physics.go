@@ -0,0 +1,226 @@
+// Package physics moves entities with simple 2D kinematics: velocity, acceleration, gravity and drag,
+// integrated with semi-implicit Euler in the fixed update phase. Entities with a non-static
+// collision.Collider are stopped by static colliders, such as the collision objects of a Tiled map.
+package physics
+
+import (
+	"math"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// Velocity is the component making an entity move. It requires a transform.Transform.
+type Velocity struct {
+	// Linear is in pixels per second.
+	Linear f64.Vec2
+	// Max limits the speed in pixels per second. Zero means unlimited.
+	Max float64
+	// Drag is the fraction of the velocity lost per second, e.g. 0.5 halves it every second
+	// without acceleration.
+	Drag float64
+}
+
+func (v *Velocity) Reset() {
+	*v = Velocity{}
+}
+
+// Acceleration is a constant acceleration, e.g. thrust, applied to the Velocity of the entity.
+type Acceleration struct {
+	// Linear is in pixels per second squared.
+	Linear f64.Vec2
+}
+
+func (a *Acceleration) Reset() {
+	a.Linear = f64.Vec2{}
+}
+
+// Gravity makes the MovementSystem's gravity apply to the entity.
+type Gravity struct {
+	// Scale multiplies the gravity. Zero is treated as 1.
+	Scale float64
+}
+
+func (g *Gravity) Reset() {
+	g.Scale = 0
+}
+
+// Contacts records the sides on which a body with a Collider was stopped by a static collider during the last step.
+// The MovementSystem updates it for the entities that have it.
+type Contacts struct {
+	Floor, Ceiling, Left, Right bool
+}
+
+func (c *Contacts) Reset() {
+	*c = Contacts{}
+}
+
+// MovementSystem integrates Velocity, Acceleration and Gravity and moves the entities' transforms.
+// It runs in ecs.PhaseFixedUpdate.
+//
+// Bodies with a non-static collision.Collider move one axis at a time and are pushed out of the static
+// colliders they overlap, using their bounding boxes. This suits tile-sized steps; fast bodies can
+// tunnel through thin colliders.
+type MovementSystem struct {
+	*ecs.BaseSystem
+
+	gravity f64.Vec2
+	solids  []aabb
+}
+
+// NewMovementSystem creates a MovementSystem with the gravity acceleration in pixels per second squared,
+// e.g. f64.Vec2{0, 900} for a platformer.
+func NewMovementSystem(id ecs.SystemID, priority int, gravity f64.Vec2) *MovementSystem {
+	s := &MovementSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		gravity:    gravity,
+	}
+	s.SetPhase(ecs.PhaseFixedUpdate)
+	s.SetAccess(ecs.SystemAccess{
+		Reads:  ecs.Types(Acceleration{}, Gravity{}, collision.Collider{}),
+		Writes: ecs.Types(Velocity{}, Contacts{}, transform.Transform{}),
+	})
+
+	return s
+}
+
+// Gravity returns the gravity acceleration.
+func (s *MovementSystem) Gravity() f64.Vec2 {
+	return s.gravity
+}
+
+// SetGravity sets the gravity acceleration.
+func (s *MovementSystem) SetGravity(gravity f64.Vec2) {
+	s.gravity = gravity
+}
+
+// Update moves all entities with a Velocity.
+func (s *MovementSystem) Update() error {
+	em := s.EntityManager()
+	dt := s.Time().Delta()
+
+	s.solids = s.solids[:0]
+	for entityID := range ecs.Query2[collision.Collider, transform.Transform](em) {
+		if collider := ecs.MustGetComponent[collision.Collider](em, entityID); collider.Static {
+			s.solids = append(s.solids, bounds(collider, ecs.MustGetComponent[transform.Transform](em, entityID)))
+		}
+	}
+
+	for entityID := range ecs.Query2[Velocity, transform.Transform](em) {
+		velocity := ecs.MustGetComponent[Velocity](em, entityID)
+		tr := ecs.MustGetComponent[transform.Transform](em, entityID)
+
+		s.integrate(em, entityID, velocity, dt)
+
+		collider, ok := ecs.GetComponent[collision.Collider](em, entityID)
+		if !ok || collider.Static {
+			tr.Position[0] += velocity.Linear[0] * dt
+			tr.Position[1] += velocity.Linear[1] * dt
+			continue
+		}
+
+		var contacts Contacts
+
+		tr.Position[0] += velocity.Linear[0] * dt
+		if s.resolve(collider, tr, 0, velocity.Linear[0]) {
+			contacts.Right = velocity.Linear[0] > 0
+			contacts.Left = velocity.Linear[0] < 0
+			velocity.Linear[0] = 0
+		}
+
+		tr.Position[1] += velocity.Linear[1] * dt
+		if s.resolve(collider, tr, 1, velocity.Linear[1]) {
+			contacts.Floor = velocity.Linear[1] > 0
+			contacts.Ceiling = velocity.Linear[1] < 0
+			velocity.Linear[1] = 0
+		}
+
+		if c, ok := ecs.GetComponent[Contacts](em, entityID); ok {
+			*c = contacts
+		}
+	}
+
+	return nil
+}
+
+// integrate applies acceleration, gravity, drag and the speed limit to the velocity.
+func (s *MovementSystem) integrate(em *ecs.EntityManager, entityID ecs.EntityID, velocity *Velocity, dt float64) {
+	if acceleration, ok := ecs.GetComponent[Acceleration](em, entityID); ok {
+		velocity.Linear[0] += acceleration.Linear[0] * dt
+		velocity.Linear[1] += acceleration.Linear[1] * dt
+	}
+
+	if gravity, ok := ecs.GetComponent[Gravity](em, entityID); ok {
+		scale := gravity.Scale
+		if scale == 0 {
+			scale = 1
+		}
+
+		velocity.Linear[0] += s.gravity[0] * scale * dt
+		velocity.Linear[1] += s.gravity[1] * scale * dt
+	}
+
+	if velocity.Drag > 0 {
+		damping := math.Pow(1-min(velocity.Drag, 1), dt)
+		velocity.Linear[0] *= damping
+		velocity.Linear[1] *= damping
+	}
+
+	if velocity.Max > 0 {
+		if speed := math.Hypot(velocity.Linear[0], velocity.Linear[1]); speed > velocity.Max {
+			velocity.Linear[0] *= velocity.Max / speed
+			velocity.Linear[1] *= velocity.Max / speed
+		}
+	}
+}
+
+type aabb struct {
+	minX, minY, maxX, maxY float64
+}
+
+func (a aabb) overlaps(b aabb) bool {
+	return a.minX < b.maxX && a.maxX > b.minX && a.minY < b.maxY && a.maxY > b.minY
+}
+
+func bounds(collider *collision.Collider, tr *transform.Transform) aabb {
+	minX, minY, maxX, maxY := collider.Bounds()
+
+	return aabb{
+		minX: tr.Position[0] + minX,
+		minY: tr.Position[1] + minY,
+		maxX: tr.Position[0] + maxX,
+		maxY: tr.Position[1] + maxY,
+	}
+}
+
+// resolve pushes the body out of the solids it overlaps along the axis, against its direction of motion.
+// It reports whether the body hit a solid.
+func (s *MovementSystem) resolve(collider *collision.Collider, tr *transform.Transform, axis int, velocity float64) bool {
+	if velocity == 0 {
+		return false
+	}
+
+	hit := false
+	for _, solid := range s.solids {
+		box := bounds(collider, tr)
+		if !box.overlaps(solid) {
+			continue
+		}
+
+		hit = true
+		switch {
+		case axis == 0 && velocity > 0:
+			tr.Position[0] -= box.maxX - solid.minX
+		case axis == 0:
+			tr.Position[0] += solid.maxX - box.minX
+		case velocity > 0:
+			tr.Position[1] -= box.maxY - solid.minY
+		default:
+			tr.Position[1] += solid.maxY - box.minY
+		}
+	}
+
+	return hit
+}
@@ -0,0 +1,87 @@
+package physics_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/physics"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func newWorld(t *testing.T, gravity f64.Vec2) (*ecs.EntityManager, func(seconds float64)) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	game.Time().SetFixedStep(0.1)
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	system := physics.NewMovementSystem(ecs.NextID(), 0, gravity)
+	assert.Equal(t, ecs.PhaseFixedUpdate, system.Phase())
+	sm.Add(system)
+
+	return em, func(seconds float64) {
+		game.Time().Advance(seconds)
+		require.NoError(t, sm.Update())
+	}
+}
+
+func TestMovement(t *testing.T) {
+	em, step := newWorld(t, f64.Vec2{0, 10})
+
+	ball := em.NewEntity()
+	tr := ecs.AddComponent[transform.Transform](em, ball)
+	ecs.AddComponent[physics.Gravity](em, ball)
+	velocity := ecs.AddComponent[physics.Velocity](em, ball)
+	velocity.Linear = f64.Vec2{5, 0}
+
+	step(0.1)
+	// Semi-implicit Euler: the velocity is updated before the position.
+	assert.InDelta(t, 1, velocity.Linear[1], 1e-9)
+	assert.InDelta(t, 0.5, tr.Position[0], 1e-9)
+	assert.InDelta(t, 0.1, tr.Position[1], 1e-9)
+
+	ecs.MustGetComponent[physics.Gravity](em, ball).Scale = -1
+	step(0.1)
+	assert.InDelta(t, 0, velocity.Linear[1], 1e-9)
+
+	rocket := em.NewEntity()
+	ecs.AddComponent[transform.Transform](em, rocket)
+	ecs.AddComponent[physics.Acceleration](em, rocket).Linear = f64.Vec2{1000, 0}
+	rocketVelocity := ecs.AddComponent[physics.Velocity](em, rocket)
+	rocketVelocity.Max = 30
+
+	step(0.1)
+	assert.InDelta(t, 30, rocketVelocity.Linear[0], 1e-9, "the speed is limited")
+
+	ecs.RemoveComponent[physics.Acceleration](em, rocket)
+	rocketVelocity.Drag = 0.5
+	step(0.5)
+	step(0.5)
+	assert.InDelta(t, 15, rocketVelocity.Linear[0], 1e-9, "drag halves the velocity every second")
+}
+
+func TestCollision(t *testing.T) {
+	em, step := newWorld(t, f64.Vec2{0, 100})
+
+	floor := em.NewEntity()
+	ecs.AddComponent[transform.Transform](em, floor).Position = f64.Vec2{0, 20}
+	*ecs.AddComponent[collision.Collider](em, floor) = collision.Collider{Width: 100, Height: 10, Static: true}
+
+	crate := em.NewEntity()
+	tr := ecs.AddComponent[transform.Transform](em, crate)
+	*ecs.AddComponent[collision.Collider](em, crate) = collision.Collider{Width: 10, Height: 10}
+	ecs.AddComponent[physics.Gravity](em, crate)
+	velocity := ecs.AddComponent[physics.Velocity](em, crate)
+	contacts := ecs.AddComponent[physics.Contacts](em, crate)
+
+	step(0.5)
+	step(0.5)
+	assert.Equal(t, 10.0, tr.Position[1], "the crate rests on the floor")
+	assert.Zero(t, velocity.Linear[1])
+	assert.True(t, contacts.Floor)
+	assert.False(t, contacts.Left || contacts.Right || contacts.Ceiling)
+}
@@ -0,0 +1,145 @@
+package ecs
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Pool recycles the entities of a prefab, e.g. bullets and particles, instead of creating and
+// removing them. Pooled entities are inactive, and so hidden from queries, until acquired.
+type Pool struct {
+	em     *EntityManager
+	prefab *Prefab
+
+	free  []EntityID
+	inUse map[EntityID]struct{}
+	// baseline is the component mask of a freshly spawned instance; components added
+	// to an entity while it was in use are removed on release.
+	baseline componentMask
+}
+
+// NewPool creates a pool of prefab instances in em and pre-instantiates n of them.
+func NewPool(em *EntityManager, prefab *Prefab, n int) *Pool {
+	p := &Pool{
+		em:     em,
+		prefab: prefab,
+		free:   make([]EntityID, 0, n),
+		inUse:  make(map[EntityID]struct{}),
+	}
+	p.Grow(n)
+	em.pools = append(em.pools, p)
+
+	return p
+}
+
+// Grow pre-instantiates n more entities.
+func (p *Pool) Grow(n int) {
+	for range n {
+		entityID := p.prefab.Spawn(p.em)
+		if p.baseline == nil {
+			p.baseline = append(componentMask(nil), p.em.entityMasks[entityID]...)
+		}
+
+		p.em.SetActive(entityID, false)
+		p.free = append(p.free, entityID)
+	}
+}
+
+// Acquire returns an active entity initialized by the prefab, reusing a released one if possible.
+// The pool grows by one entity when it is empty. Pooled entities removed from the EntityManager,
+// e.g. by BaseWorld.Reset, are discarded.
+func (p *Pool) Acquire() EntityID {
+	p.discardRemoved()
+	if len(p.free) == 0 {
+		p.Grow(1)
+	}
+
+	entityID := p.free[len(p.free)-1]
+	p.free = p.free[:len(p.free)-1]
+
+	p.em.SetActive(entityID, true)
+	p.prefab.Apply(p.em, entityID)
+	p.inUse[entityID] = struct{}{}
+
+	return entityID
+}
+
+// Release returns an acquired entity to the pool: components added since it was acquired are
+// removed, the others are Reset (or zeroed if they do not implement Component), its tags and
+// relations, from and to it, are removed, and the entity becomes inactive. References to the entity taken with EntityManager.Ref become invalid.
+// Releasing an entity that is not in use has no effect.
+// To release an entity while iterating a query, use CommandBuffer.Release.
+func (p *Pool) Release(entityID EntityID) {
+	if _, ok := p.inUse[entityID]; !ok {
+		return
+	}
+	delete(p.inUse, entityID)
+
+	if !p.em.Exists(entityID) {
+		// The entity was removed while in use.
+		return
+	}
+
+	for componentType := range p.em.entityComponentTypes(entityID) {
+		if bit := p.em.componentBits[componentType]; !p.baseline.has(bit) {
+			p.em.removeComponent(entityID, componentType)
+			continue
+		}
+
+		component, _ := p.em.componentContainers[componentType].Get(entityID)
+		resetComponent(component)
+	}
+
+	p.em.untagAll(entityID)
+	p.em.removeRelations(entityID)
+	p.em.SetActive(entityID, false)
+	p.em.recycle(entityID)
+	p.free = append(p.free, entityID)
+}
+
+// Available returns the number of entities ready to be acquired.
+func (p *Pool) Available() int {
+	p.discardRemoved()
+
+	return len(p.free)
+}
+
+// InUse returns the number of acquired entities.
+func (p *Pool) InUse() int {
+	p.discardRemoved()
+
+	return len(p.inUse)
+}
+
+// refill replaces the pooled entities that were removed from the EntityManager with fresh free ones.
+func (p *Pool) refill() {
+	size := len(p.free) + len(p.inUse)
+	p.discardRemoved()
+	p.Grow(size - len(p.free) - len(p.inUse))
+}
+
+// discardRemoved forgets the pooled entities that were removed from the EntityManager.
+func (p *Pool) discardRemoved() {
+	p.free = slices.DeleteFunc(p.free, func(entityID EntityID) bool {
+		return !p.em.Exists(entityID)
+	})
+
+	for entityID := range p.inUse {
+		if !p.em.Exists(entityID) {
+			delete(p.inUse, entityID)
+		}
+	}
+}
+
+// resetComponent resets a component to the state of a newly added one.
+func resetComponent(component any) {
+	if resetter, ok := component.(Component); ok {
+		resetter.Reset()
+	} else {
+		reflect.ValueOf(component).Elem().SetZero()
+	}
+
+	if initable, ok := component.(interface{ Init() }); ok {
+		initable.Init()
+	}
+}
@@ -0,0 +1,116 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	bullet := ecs.NewPrefab("bullet", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[TransformComponent](em, entityID).Rotation = 1
+	})
+
+	pool := ecs.NewPool(em, bullet, 2)
+	assert.Equal(t, 2, pool.Available())
+	assert.Zero(t, ecs.Count(ecs.Query[TransformComponent](em)), "pooled entities are inactive")
+
+	first := pool.Acquire()
+	assert.True(t, em.Active(first))
+	assert.Equal(t, 1, pool.InUse())
+
+	tr := ecs.MustGetComponent[TransformComponent](em, first)
+	tr.Position = [2]float64{10, 20}
+	ecs.AddComponent[FrozenComponent](em, first)
+
+	target := em.NewEntity()
+	em.Tag(first, "fired")
+	ecs.Relate[Targets](em, first, target)
+	ecs.Relate[Targets](em, target, first)
+
+	pool.Release(first)
+	assert.False(t, em.Active(first))
+	assert.False(t, ecs.HasComponent[FrozenComponent](em, first))
+	assert.Empty(t, em.Tags(first), "tags are removed on release")
+	assert.False(t, ecs.HasRelation[Targets](em, first, target), "relations are removed on release")
+	assert.False(t, ecs.HasRelation[Targets](em, target, first))
+
+	// The released entity is recycled and re-initialized by the prefab.
+	again := pool.Acquire()
+	assert.Equal(t, first, again)
+	tr = ecs.MustGetComponent[TransformComponent](em, again)
+	assert.Zero(t, tr.Position)
+	assert.Equal(t, 1.0, tr.Rotation)
+
+	// The pool grows when empty.
+	pool.Acquire()
+	pool.Acquire()
+	assert.Equal(t, 3, pool.InUse())
+	assert.Zero(t, pool.Available())
+}
+
+func TestPoolRemovedEntities(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	bullet := ecs.NewPrefab("bullet", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[TransformComponent](em, entityID).Rotation = 1
+	})
+
+	pool := ecs.NewPool(em, bullet, 2)
+	inUse := pool.Acquire()
+	free := em.Entities()
+	for _, entityID := range free {
+		em.Remove(entityID)
+	}
+
+	assert.Zero(t, pool.Available(), "removed entities are discarded")
+	assert.Zero(t, pool.InUse())
+	assert.NotPanics(t, func() { pool.Release(inUse) })
+
+	entityID := pool.Acquire()
+	assert.NotContains(t, free, entityID, "the pool grows with a fresh entity")
+	assert.Equal(t, 1.0, ecs.MustGetComponent[TransformComponent](em, entityID).Rotation)
+
+	removed := em.NewEntity()
+	em.Remove(removed)
+	assert.NotPanics(t, func() { bullet.Apply(em, removed) })
+	assert.False(t, em.Exists(removed))
+}
+
+func TestCommandBuffer(t *testing.T) {
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(em, game)
+
+	bullet := ecs.NewPrefab("bullet", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[TransformComponent](em, entityID)
+	})
+	pool := ecs.NewPool(em, bullet, 4)
+	for range 3 {
+		pool.Acquire()
+	}
+
+	spawned := 0
+	sm.AddFunc(0, func(em *ecs.EntityManager, _ *ecs.Game) error {
+		for entityID := range ecs.Query[TransformComponent](em) {
+			// Structural changes are deferred until the system returns.
+			em.Commands().Release(pool, entityID)
+			ecs.DeferAddComponent(em.Commands(), entityID, CameraComponent{Zoom: 3})
+		}
+		em.Commands().Spawn(bullet, func(ecs.EntityID) { spawned++ })
+
+		assert.Equal(t, 7, em.Commands().Len())
+		return nil
+	})
+
+	require.NoError(t, sm.Update())
+	assert.Zero(t, em.Commands().Len())
+	assert.Equal(t, 1, spawned)
+	assert.Zero(t, pool.InUse())
+	assert.Equal(t, 1, ecs.Count(ecs.Query[TransformComponent](em)))
+	assert.Equal(t, 3, ecs.Count(ecs.NewQuery[CameraComponent](em).IncludeInactive().Iter()))
+}
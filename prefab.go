@@ -0,0 +1,57 @@
+package ecs
+
+// Prefab is a named recipe for building an entity.
+// Spawning a prefab creates a new entity and runs the build function on it,
+// which is expected to add and configure the entity's components.
+type Prefab struct {
+	name  string
+	build func(em *EntityManager, entityID EntityID)
+}
+
+// NewPrefab creates a new Prefab with the given name and build function.
+func NewPrefab(name string, build func(em *EntityManager, entityID EntityID)) *Prefab {
+	return &Prefab{
+		name:  name,
+		build: build,
+	}
+}
+
+// Name returns the name of the prefab.
+func (p *Prefab) Name() string {
+	return p.name
+}
+
+// Spawn creates a new entity in the EntityManager and applies the prefab to it.
+func (p *Prefab) Spawn(em *EntityManager) EntityID {
+	entityID := em.NewEntity()
+	p.Apply(em, entityID)
+
+	return entityID
+}
+
+// Apply runs the prefab's build function on an existing entity
+// and marks the entity as an instance of the prefab. It does nothing if the entity does not exist.
+func (p *Prefab) Apply(em *EntityManager, entityID EntityID) {
+	if !em.Exists(entityID) {
+		return
+	}
+
+	if p.build != nil {
+		p.build(em, entityID)
+	}
+
+	instance, ok := GetComponent[PrefabInstance](em, entityID)
+	if !ok {
+		instance = AddComponent[PrefabInstance](em, entityID)
+	}
+	instance.Prefab = p
+}
+
+// PrefabInstance records the prefab an entity was spawned from. It is added by Prefab.Spawn and Prefab.Apply.
+type PrefabInstance struct {
+	Prefab *Prefab
+}
+
+func (p *PrefabInstance) Reset() {
+	p.Prefab = nil
+}
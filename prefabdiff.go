@@ -0,0 +1,170 @@
+package ecs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrNotPrefabInstance is returned by DiffPrefab for entities that were not spawned from a prefab.
+var ErrNotPrefabInstance = errors.New("entity is not a prefab instance")
+
+var prefabInstanceType = reflect.TypeFor[PrefabInstance]()
+
+// FieldDiff is a component field whose live value differs from the prefab's.
+type FieldDiff struct {
+	Component reflect.Type
+	// Path is the dotted path of the field within the component, e.g. "Position" or "Body.Mass".
+	Path   string
+	Prefab any
+	Live   any
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s.%s: %v -> %v", d.Component, d.Path, d.Prefab, d.Live)
+}
+
+// PrefabDiff describes how a live entity has diverged from its prefab.
+type PrefabDiff struct {
+	EntityID EntityID
+	Prefab   *Prefab
+	// Added are the component types the entity has but the prefab does not.
+	Added []reflect.Type
+	// Removed are the component types the prefab has but the entity does not.
+	Removed []reflect.Type
+	// Fields are the exported component fields whose values differ.
+	Fields []FieldDiff
+}
+
+// Empty reports whether the entity matches its prefab.
+func (d *PrefabDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Fields) == 0
+}
+
+func (d *PrefabDiff) String() string {
+	if d.Empty() {
+		return fmt.Sprintf("entity %d matches prefab %q", d.EntityID, d.Prefab.Name())
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "entity %d differs from prefab %q:", d.EntityID, d.Prefab.Name())
+	for _, t := range d.Added {
+		fmt.Fprintf(&b, "\n  + %s", t)
+	}
+	for _, t := range d.Removed {
+		fmt.Fprintf(&b, "\n  - %s", t)
+	}
+	for _, field := range d.Fields {
+		fmt.Fprintf(&b, "\n  ~ %s", field)
+	}
+
+	return b.String()
+}
+
+// DiffPrefab compares a live entity against the prefab it was spawned from.
+// It returns ErrNotPrefabInstance if the entity has no PrefabInstance component.
+func DiffPrefab(em *EntityManager, entityID EntityID) (*PrefabDiff, error) {
+	instance, ok := GetComponent[PrefabInstance](em, entityID)
+	if !ok || instance.Prefab == nil {
+		return nil, fmt.Errorf("ecs.DiffPrefab entity %d: %w", entityID, ErrNotPrefabInstance)
+	}
+
+	return instance.Prefab.Diff(em, entityID), nil
+}
+
+// Diff compares a live entity against a fresh instance of the prefab.
+// The reference instance is built in a scratch EntityManager, so build functions
+// that depend on other entities of em see an empty world.
+func (p *Prefab) Diff(em *EntityManager, entityID EntityID) *PrefabDiff {
+	scratch := NewEntityManager()
+	defer scratch.Teardown()
+
+	referenceID := p.Spawn(scratch)
+
+	diff := &PrefabDiff{EntityID: entityID, Prefab: p}
+
+	live := sortedComponentTypes(em, entityID)
+	reference := sortedComponentTypes(scratch, referenceID)
+
+	for _, t := range live {
+		if !slices.Contains(reference, t) {
+			diff.Added = append(diff.Added, t)
+		}
+	}
+
+	for _, t := range reference {
+		if !slices.Contains(live, t) {
+			diff.Removed = append(diff.Removed, t)
+			continue
+		}
+
+		want, _ := scratch.componentContainers[t].Get(referenceID)
+		got, _ := em.componentContainers[t].Get(entityID)
+		diff.Fields = diffFields(diff.Fields, t, "", reflect.ValueOf(want).Elem(), reflect.ValueOf(got).Elem())
+	}
+
+	return diff
+}
+
+// sortedComponentTypes returns the entity's component types, excluding PrefabInstance, sorted by name.
+func sortedComponentTypes(em *EntityManager, entityID EntityID) []reflect.Type {
+	var types []reflect.Type
+	for t := range em.entityComponentTypes(entityID) {
+		if t != prefabInstanceType {
+			types = append(types, t)
+		}
+	}
+
+	slices.SortFunc(types, func(a, b reflect.Type) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+
+	return types
+}
+
+// diffFields appends the differing exported fields of two values of the component type.
+// Structs are compared field by field, pointers by identity and everything else deeply.
+func diffFields(diffs []FieldDiff, component reflect.Type, path string, want, got reflect.Value) []FieldDiff {
+	switch want.Kind() {
+	case reflect.Struct:
+		for i := range want.NumField() {
+			field := want.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+
+			diffs = diffFields(diffs, component, fieldPath, want.Field(i), got.Field(i))
+		}
+
+		return diffs
+	case reflect.Func:
+		return diffs
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		if want.Pointer() == got.Pointer() {
+			return diffs
+		}
+	default:
+		if reflect.DeepEqual(want.Interface(), got.Interface()) {
+			return diffs
+		}
+	}
+
+	if path == "" {
+		path = component.Name()
+	}
+
+	return append(diffs, FieldDiff{
+		Component: component,
+		Path:      path,
+		Prefab:    want.Interface(),
+		Live:      got.Interface(),
+	})
+}
@@ -0,0 +1,41 @@
+package ecs_test
+
+import (
+	"reflect"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPrefab(t *testing.T) {
+	goblin := ecs.NewPrefab("goblin", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[TransformComponent](em, entityID).Rotation = 1
+		ecs.AddComponent[CameraComponent](em, entityID).Zoom = 2
+	})
+
+	em := ecs.NewEntityManager()
+
+	pristine := goblin.Spawn(em)
+	diff, err := ecs.DiffPrefab(em, pristine)
+	require.NoError(t, err)
+	assert.True(t, diff.Empty(), diff.String())
+
+	odd := goblin.Spawn(em)
+	ecs.MustGetComponent[TransformComponent](em, odd).Position = [2]float64{3, 4}
+	ecs.RemoveComponent[CameraComponent](em, odd)
+	ecs.AddComponent[FrozenComponent](em, odd)
+
+	diff, err = ecs.DiffPrefab(em, odd)
+	require.NoError(t, err)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []reflect.Type{reflect.TypeFor[FrozenComponent]()}, diff.Added)
+	assert.Equal(t, []reflect.Type{reflect.TypeFor[CameraComponent]()}, diff.Removed)
+	require.Len(t, diff.Fields, 1)
+	assert.Equal(t, "Position", diff.Fields[0].Path)
+	assert.Contains(t, diff.String(), "+ ecs_test.FrozenComponent")
+
+	_, err = ecs.DiffPrefab(em, em.NewEntity())
+	assert.ErrorIs(t, err, ecs.ErrNotPrefabInstance)
+}
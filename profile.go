@@ -0,0 +1,89 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// SetProfileLabels turns on pprof labels for system updates and draws: while a system runs, the goroutine is
+// labeled with "ecs.system", the system name or "system <id>" for unnamed systems, and "ecs.call", "update"
+// or "draw", so CPU profiles attribute samples to systems, e.g. with go tool pprof -tagfocus ecs.system=physics.
+// Labeling allocates a little on every call, so it is off by default.
+//
+// Regardless of this setting, while a runtime/trace is being recorded every Update and Draw of the
+// SystemManager is a trace task in which each system runs in a region named after it.
+func (sm *SystemManager) SetProfileLabels(enabled bool) {
+	sm.profileLabels = enabled
+}
+
+// ProfileLabels reports whether system calls are labeled for pprof, see SetProfileLabels.
+func (sm *SystemManager) ProfileLabels() bool {
+	return sm.profileLabels
+}
+
+// instrumented reports whether system calls must go through instrument.
+func (sm *SystemManager) instrumented() bool {
+	return sm.profileLabels || trace.IsEnabled()
+}
+
+// beginTrace starts the trace task of an Update or Draw, returning the function ending it.
+func (sm *SystemManager) beginTrace(name string) (end func()) {
+	if !trace.IsEnabled() {
+		return func() {}
+	}
+
+	ctx, task := trace.NewTask(context.Background(), name)
+	sm.traceContext = ctx
+
+	return func() {
+		task.End()
+		sm.traceContext = nil
+	}
+}
+
+// instrument calls fn, which runs the system, within a trace region and with pprof labels as enabled.
+func (sm *SystemManager) instrument(system System, call string, fn func()) {
+	label := systemName(system.ID(), system.baseSystem().name)
+
+	run := fn
+	if trace.IsEnabled() {
+		ctx := sm.traceContext
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		run = func() { trace.WithRegion(ctx, label, fn) }
+	}
+
+	if !sm.profileLabels {
+		run()
+		return
+	}
+
+	pprof.Do(context.Background(), pprof.Labels("ecs.system", label, "ecs.call", call), func(context.Context) {
+		run()
+	})
+}
+
+// updateSystem calls the system's Update, instrumented if needed.
+func (sm *SystemManager) updateSystem(system System) error {
+	if !sm.instrumented() {
+		return system.Update()
+	}
+
+	var err error
+	sm.instrument(system, "update", func() { err = system.Update() })
+
+	return err
+}
+
+// systemName returns the name of a system for display, falling back to its ID for unnamed systems.
+func systemName(id SystemID, name string) string {
+	if name == "" {
+		return fmt.Sprintf("system %d", id)
+	}
+
+	return name
+}
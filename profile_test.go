@@ -0,0 +1,84 @@
+package ecs_test
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"runtime/trace"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// labelSystem records the goroutine profile labels it runs with.
+type labelSystem struct {
+	*ecs.BaseSystem
+
+	profile string
+}
+
+func (s *labelSystem) Update() error {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return err
+	}
+	s.profile = buf.String()
+
+	return nil
+}
+
+func TestProfileLabels(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+
+	system := &labelSystem{BaseSystem: ecs.NewBaseSystem(ecs.NextID(), 0)}
+	system.SetName("physics")
+	sm.Add(system)
+
+	require.NoError(t, sm.Update())
+	assert.NotContains(t, system.profile, `"ecs.system":"physics"`)
+
+	sm.SetProfileLabels(true)
+	assert.True(t, sm.ProfileLabels())
+
+	require.NoError(t, sm.Update())
+	assert.Contains(t, system.profile, `"ecs.call":"update"`)
+	assert.Contains(t, system.profile, `"ecs.system":"physics"`)
+}
+
+func TestTraceRegions(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("a trace is already being recorded")
+	}
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+
+	system := &labelSystem{BaseSystem: ecs.NewBaseSystem(ecs.NextID(), 0)}
+	system.SetName("pathfinding")
+	sm.Add(system)
+
+	var buf bytes.Buffer
+	require.NoError(t, trace.Start(&buf))
+	err := sm.Update()
+	trace.Stop()
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "ecs.SystemManager.Update")
+	assert.Contains(t, buf.String(), "pathfinding")
+}
+
+func TestUninstrumentedUpdateAllocs(t *testing.T) {
+	if validatingUpdates {
+		t.Skip("builds with the ecsdebug tag validate the EntityManager after every update")
+	}
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+	sm.Add(&hookSystem{BaseSystem: ecs.NewBaseSystem(ecs.NextID(), 0)})
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = sm.Update()
+	}))
+}
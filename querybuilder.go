@@ -0,0 +1,305 @@
+package ecs
+
+import (
+	"iter"
+	"reflect"
+)
+
+// queryBuilder holds the component constraints shared by the fluent query builders.
+type queryBuilder struct {
+	em          *EntityManager
+	with        []reflect.Type
+	without     []reflect.Type
+	withTags    []Tag
+	withoutTags []Tag
+
+	includeInactive bool
+}
+
+func (b *queryBuilder) addWith(componentTypes []any) {
+	b.with = append(b.with, Types(componentTypes...)...)
+}
+
+func (b *queryBuilder) addWithout(componentTypes []any) {
+	b.without = append(b.without, Types(componentTypes...)...)
+}
+
+// seq returns the entities with all the component types, including inactive ones if requested.
+func (b *queryBuilder) seq(componentTypes ...reflect.Type) iter.Seq[EntityID] {
+	return b.em.queryEntities(b.includeInactive, componentTypes)
+}
+
+// iter filters the entities of seq by the builder constraints and the accept predicate.
+// The With and Without constraints are tested on the entity masks before any component is read.
+func (b *queryBuilder) iter(seq iter.Seq[EntityID], filtered bool, accept func(EntityID) bool) iter.Seq[EntityID] {
+	if !filtered && len(b.with) == 0 && len(b.without) == 0 && len(b.withTags) == 0 && len(b.withoutTags) == 0 {
+		return seq
+	}
+
+	withMask, ok := b.em.maskOf(b.with)
+	if !ok {
+		// A required component type has never been added to any entity.
+		return func(yield func(EntityID) bool) {}
+	}
+
+	var withoutMask componentMask
+	for _, componentType := range b.without {
+		if bit, ok := b.em.componentBits[componentType]; ok {
+			withoutMask.set(bit)
+		}
+	}
+
+	var record func(bool)
+	if filtered {
+		record = b.em.recordFilter()
+	}
+
+	return func(yield func(EntityID) bool) {
+		for entityID := range seq {
+			mask := b.em.entityMasks[entityID]
+			if !mask.containsAll(withMask) || mask.intersects(withoutMask) || !b.matchesTags(entityID) {
+				continue
+			}
+
+			if filtered {
+				accepted := accept(entityID)
+				if record != nil {
+					record(accepted)
+				}
+
+				if !accepted {
+					continue
+				}
+			}
+
+			if !yield(entityID) {
+				break
+			}
+		}
+	}
+}
+
+// matchesTags reports whether the entity has all the required tags and none of the excluded ones.
+func (b *queryBuilder) matchesTags(entityID EntityID) bool {
+	for _, tag := range b.withTags {
+		if !b.em.HasTag(entityID, tag) {
+			return false
+		}
+	}
+
+	for _, tag := range b.withoutTags {
+		if b.em.HasTag(entityID, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// combineFilter appends filter to the existing one with logical AND.
+func combineFilter[C any](existing, filter Filter[C]) Filter[C] {
+	if existing == nil {
+		return filter
+	}
+
+	if filter == nil {
+		return existing
+	}
+
+	return And(existing, filter)
+}
+
+// QueryBuilder builds a query over entities with component A.
+// Constraints are evaluated lazily when the sequence returned by Iter is ranged over.
+//
+//	for id := range ecs.NewQuery[Enemy](em).Filter(isAlive).Without(Frozen{}).Iter() { ... }
+type QueryBuilder[A any] struct {
+	queryBuilder
+	filterA Filter[A]
+}
+
+// NewQuery starts building a query over entities with component A.
+func NewQuery[A any](em *EntityManager) *QueryBuilder[A] {
+	return &QueryBuilder[A]{queryBuilder: queryBuilder{em: em}}
+}
+
+// Filter adds a filter on component A. Multiple filters are combined with logical AND.
+func (q *QueryBuilder[A]) Filter(filter Filter[A]) *QueryBuilder[A] {
+	q.filterA = combineFilter(q.filterA, filter)
+	return q
+}
+
+// With additionally requires the given component types, passed as zero values as in EntityManager.Query.
+func (q *QueryBuilder[A]) With(componentTypes ...any) *QueryBuilder[A] {
+	q.addWith(componentTypes)
+	return q
+}
+
+// Without excludes entities that have any of the given component types, passed as zero values as in EntityManager.Query.
+func (q *QueryBuilder[A]) Without(componentTypes ...any) *QueryBuilder[A] {
+	q.addWithout(componentTypes)
+	return q
+}
+
+// WithTag additionally requires the given tags.
+func (q *QueryBuilder[A]) WithTag(tags ...Tag) *QueryBuilder[A] {
+	q.withTags = append(q.withTags, tags...)
+	return q
+}
+
+// WithoutTag excludes entities that have any of the given tags.
+func (q *QueryBuilder[A]) WithoutTag(tags ...Tag) *QueryBuilder[A] {
+	q.withoutTags = append(q.withoutTags, tags...)
+	return q
+}
+
+// IncludeInactive makes the query also return entities disabled with EntityManager.SetActive.
+func (q *QueryBuilder[A]) IncludeInactive() *QueryBuilder[A] {
+	q.includeInactive = true
+	return q
+}
+
+// Iter returns the sequence of matching entities.
+func (q *QueryBuilder[A]) Iter() iter.Seq[EntityID] {
+	return q.iter(q.seq(reflect.TypeFor[A]()), q.filterA != nil, func(entityID EntityID) bool {
+		return evaluateFilter(q.em, entityID, q.filterA)
+	})
+}
+
+// QueryBuilder2 builds a query over entities with components A and B.
+//
+//	ecs.NewQuery2[Transform, Velocity](em).FilterB(isMoving).Without(Static{}).Iter()
+type QueryBuilder2[A, B any] struct {
+	queryBuilder
+	filterA Filter[A]
+	filterB Filter[B]
+}
+
+// NewQuery2 starts building a query over entities with components A and B.
+func NewQuery2[A, B any](em *EntityManager) *QueryBuilder2[A, B] {
+	return &QueryBuilder2[A, B]{queryBuilder: queryBuilder{em: em}}
+}
+
+// FilterA adds a filter on component A. Multiple filters are combined with logical AND.
+func (q *QueryBuilder2[A, B]) FilterA(filter Filter[A]) *QueryBuilder2[A, B] {
+	q.filterA = combineFilter(q.filterA, filter)
+	return q
+}
+
+// FilterB adds a filter on component B. Multiple filters are combined with logical AND.
+func (q *QueryBuilder2[A, B]) FilterB(filter Filter[B]) *QueryBuilder2[A, B] {
+	q.filterB = combineFilter(q.filterB, filter)
+	return q
+}
+
+// With additionally requires the given component types, passed as zero values as in EntityManager.Query.
+func (q *QueryBuilder2[A, B]) With(componentTypes ...any) *QueryBuilder2[A, B] {
+	q.addWith(componentTypes)
+	return q
+}
+
+// Without excludes entities that have any of the given component types, passed as zero values as in EntityManager.Query.
+func (q *QueryBuilder2[A, B]) Without(componentTypes ...any) *QueryBuilder2[A, B] {
+	q.addWithout(componentTypes)
+	return q
+}
+
+// WithTag additionally requires the given tags.
+func (q *QueryBuilder2[A, B]) WithTag(tags ...Tag) *QueryBuilder2[A, B] {
+	q.withTags = append(q.withTags, tags...)
+	return q
+}
+
+// WithoutTag excludes entities that have any of the given tags.
+func (q *QueryBuilder2[A, B]) WithoutTag(tags ...Tag) *QueryBuilder2[A, B] {
+	q.withoutTags = append(q.withoutTags, tags...)
+	return q
+}
+
+// IncludeInactive makes the query also return entities disabled with EntityManager.SetActive.
+func (q *QueryBuilder2[A, B]) IncludeInactive() *QueryBuilder2[A, B] {
+	q.includeInactive = true
+	return q
+}
+
+// Iter returns the sequence of matching entities.
+func (q *QueryBuilder2[A, B]) Iter() iter.Seq[EntityID] {
+	filtered := q.filterA != nil || q.filterB != nil
+
+	return q.iter(q.seq(reflect.TypeFor[A](), reflect.TypeFor[B]()), filtered, func(entityID EntityID) bool {
+		return evaluateFilter(q.em, entityID, q.filterA) &&
+			evaluateFilter(q.em, entityID, q.filterB)
+	})
+}
+
+// QueryBuilder3 builds a query over entities with components A, B and C.
+type QueryBuilder3[A, B, C any] struct {
+	queryBuilder
+	filterA Filter[A]
+	filterB Filter[B]
+	filterC Filter[C]
+}
+
+// NewQuery3 starts building a query over entities with components A, B and C.
+func NewQuery3[A, B, C any](em *EntityManager) *QueryBuilder3[A, B, C] {
+	return &QueryBuilder3[A, B, C]{queryBuilder: queryBuilder{em: em}}
+}
+
+// FilterA adds a filter on component A. Multiple filters are combined with logical AND.
+func (q *QueryBuilder3[A, B, C]) FilterA(filter Filter[A]) *QueryBuilder3[A, B, C] {
+	q.filterA = combineFilter(q.filterA, filter)
+	return q
+}
+
+// FilterB adds a filter on component B. Multiple filters are combined with logical AND.
+func (q *QueryBuilder3[A, B, C]) FilterB(filter Filter[B]) *QueryBuilder3[A, B, C] {
+	q.filterB = combineFilter(q.filterB, filter)
+	return q
+}
+
+// FilterC adds a filter on component C. Multiple filters are combined with logical AND.
+func (q *QueryBuilder3[A, B, C]) FilterC(filter Filter[C]) *QueryBuilder3[A, B, C] {
+	q.filterC = combineFilter(q.filterC, filter)
+	return q
+}
+
+// With additionally requires the given component types, passed as zero values as in EntityManager.Query.
+func (q *QueryBuilder3[A, B, C]) With(componentTypes ...any) *QueryBuilder3[A, B, C] {
+	q.addWith(componentTypes)
+	return q
+}
+
+// Without excludes entities that have any of the given component types, passed as zero values as in EntityManager.Query.
+func (q *QueryBuilder3[A, B, C]) Without(componentTypes ...any) *QueryBuilder3[A, B, C] {
+	q.addWithout(componentTypes)
+	return q
+}
+
+// WithTag additionally requires the given tags.
+func (q *QueryBuilder3[A, B, C]) WithTag(tags ...Tag) *QueryBuilder3[A, B, C] {
+	q.withTags = append(q.withTags, tags...)
+	return q
+}
+
+// WithoutTag excludes entities that have any of the given tags.
+func (q *QueryBuilder3[A, B, C]) WithoutTag(tags ...Tag) *QueryBuilder3[A, B, C] {
+	q.withoutTags = append(q.withoutTags, tags...)
+	return q
+}
+
+// IncludeInactive makes the query also return entities disabled with EntityManager.SetActive.
+func (q *QueryBuilder3[A, B, C]) IncludeInactive() *QueryBuilder3[A, B, C] {
+	q.includeInactive = true
+	return q
+}
+
+// Iter returns the sequence of matching entities.
+func (q *QueryBuilder3[A, B, C]) Iter() iter.Seq[EntityID] {
+	filtered := q.filterA != nil || q.filterB != nil || q.filterC != nil
+
+	return q.iter(q.seq(reflect.TypeFor[A](), reflect.TypeFor[B](), reflect.TypeFor[C]()), filtered, func(entityID EntityID) bool {
+		return evaluateFilter(q.em, entityID, q.filterA) &&
+			evaluateFilter(q.em, entityID, q.filterB) &&
+			evaluateFilter(q.em, entityID, q.filterC)
+	})
+}
@@ -0,0 +1,56 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	newCamera := func(zoom, x float64, frozen bool) ecs.EntityID {
+		id := em.NewEntity()
+		ecs.AddComponent[CameraComponent](em, id).Zoom = zoom
+		ecs.AddComponent[TransformComponent](em, id).Position[0] = x
+		if frozen {
+			ecs.AddComponent[FrozenComponent](em, id)
+		}
+
+		return id
+	}
+
+	near := newCamera(2, 10, false)
+	far := newCamera(2, 500, false)
+	frozen := newCamera(2, 10, true)
+	newCamera(0.5, 10, false)
+
+	zoomed := func(c *CameraComponent) bool { return c.Zoom > 1 }
+	onScreen := func(tr *TransformComponent) bool { return tr.Position[0] < 100 }
+
+	got := slices.Collect(ecs.NewQuery2[CameraComponent, TransformComponent](em).
+		FilterA(zoomed).
+		FilterB(onScreen).
+		Without(FrozenComponent{}).
+		Iter())
+	assert.ElementsMatch(t, []ecs.EntityID{near}, got)
+
+	got = slices.Collect(ecs.NewQuery2[CameraComponent, TransformComponent](em).FilterA(zoomed).Iter())
+	assert.ElementsMatch(t, []ecs.EntityID{near, far, frozen}, got)
+
+	got = slices.Collect(ecs.NewQuery[CameraComponent](em).
+		Filter(zoomed).
+		Filter(func(c *CameraComponent) bool { return c.Zoom < 3 }).
+		With(FrozenComponent{}).
+		Iter())
+	assert.ElementsMatch(t, []ecs.EntityID{frozen}, got)
+
+	got = slices.Collect(ecs.NewQuery3[CameraComponent, TransformComponent, FrozenComponent](em).
+		FilterB(onScreen).
+		Iter())
+	assert.ElementsMatch(t, []ecs.EntityID{frozen}, got)
+
+	assert.Equal(t, 4, ecs.Count(ecs.NewQuery[CameraComponent](em).Iter()))
+}
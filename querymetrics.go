@@ -0,0 +1,237 @@
+package ecs
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// maxCachedQueryTypes is the largest number of component types a cached query plan can hold.
+const maxCachedQueryTypes = 4
+
+type queryKey struct {
+	n     int
+	types [maxCachedQueryTypes]reflect.Type
+}
+
+// queryPlan holds the resolved component storages of a query and the mask of its component types.
+type queryPlan struct {
+	version    uint64
+	containers []componentStorage
+	mask       componentMask
+	complete   bool
+}
+
+// queryContainers resolves the storages of the component types, reusing the cached plan
+// as long as no component storage has been created since it was built.
+// The plan is not complete if any of the component types has no storage.
+func (em *EntityManager) queryContainers(componentTypes []reflect.Type) *queryPlan {
+	em.lockQueries()
+	defer em.unlockQueries()
+
+	key := queryKey{n: len(componentTypes)}
+	cacheable := len(componentTypes) <= maxCachedQueryTypes
+	if cacheable {
+		copy(key.types[:], componentTypes)
+
+		if plan, ok := em.queryPlans[key]; ok && plan.version == em.storageVersion {
+			em.recordQuery(true)
+			return plan
+		}
+	}
+
+	plan := &queryPlan{
+		version:    em.storageVersion,
+		containers: make([]componentStorage, len(componentTypes)),
+		complete:   true,
+	}
+
+	for i, componentType := range componentTypes {
+		container, exists := em.componentContainers[componentType]
+		if !exists {
+			plan.complete = false
+			break
+		}
+		plan.containers[i] = container
+		plan.mask.set(em.componentBits[componentType])
+	}
+
+	if cacheable {
+		em.queryPlans[key] = plan
+	}
+	em.recordQuery(false)
+
+	return plan
+}
+
+// QueryMetrics are the statistics of the queries and filters issued from a single call site.
+type QueryMetrics struct {
+	// Site identifies the caller as "function (file:line)".
+	Site string
+	// Executions is the number of queries issued from the site.
+	Executions uint64
+	// CacheHits is the number of queries that reused a cached query plan.
+	CacheHits uint64
+	// CacheRebuilds is the number of queries that had to resolve their component storages.
+	CacheRebuilds uint64
+	// FilterEvaluations is the number of entities tested by filters created at the site.
+	FilterEvaluations uint64
+	// FilterRejections is the number of entities rejected by filters created at the site.
+	FilterRejections uint64
+}
+
+// HitRate returns the fraction of queries that reused a cached plan.
+func (m QueryMetrics) HitRate() float64 {
+	if m.Executions == 0 {
+		return 0
+	}
+
+	return float64(m.CacheHits) / float64(m.Executions)
+}
+
+// RejectionRate returns the fraction of entities rejected by filters.
+// A high rejection rate suggests the filter should be replaced by a component or tag.
+func (m QueryMetrics) RejectionRate() float64 {
+	if m.FilterEvaluations == 0 {
+		return 0
+	}
+
+	return float64(m.FilterRejections) / float64(m.FilterEvaluations)
+}
+
+func (m QueryMetrics) String() string {
+	return fmt.Sprintf("%s: %d queries (%.0f%% cached), %d filtered (%.0f%% rejected)",
+		m.Site, m.Executions, m.HitRate()*100, m.FilterEvaluations, m.RejectionRate()*100)
+}
+
+// SetQueryMetricsEnabled turns per call site query metrics on or off.
+// Collecting metrics walks the stack on every query, so it is meant for profiling sessions only.
+func (em *EntityManager) SetQueryMetricsEnabled(enabled bool) {
+	em.lockQueries()
+	defer em.unlockQueries()
+
+	if !enabled {
+		em.queryMetrics = nil
+		em.queryMetricsByPC = nil
+		return
+	}
+
+	if em.queryMetrics == nil {
+		em.queryMetrics = make(map[string]*QueryMetrics)
+		em.queryMetricsByPC = make(map[uintptr]*QueryMetrics)
+	}
+}
+
+// QueryMetrics returns a snapshot of the collected metrics, sorted by site.
+func (em *EntityManager) QueryMetrics() []QueryMetrics {
+	em.lockQueries()
+	defer em.unlockQueries()
+
+	metrics := make([]QueryMetrics, 0, len(em.queryMetrics))
+	for _, m := range em.queryMetrics {
+		metrics = append(metrics, *m)
+	}
+
+	slices.SortFunc(metrics, func(a, b QueryMetrics) int {
+		return cmp.Compare(a.Site, b.Site)
+	})
+
+	return metrics
+}
+
+// ResetQueryMetrics clears the collected metrics, keeping collection enabled if it was.
+func (em *EntityManager) ResetQueryMetrics() {
+	em.lockQueries()
+	defer em.unlockQueries()
+
+	if em.queryMetrics != nil {
+		clear(em.queryMetrics)
+		clear(em.queryMetricsByPC)
+	}
+}
+
+func (em *EntityManager) recordQuery(hit bool) {
+	m := em.callerMetrics()
+	if m == nil {
+		return
+	}
+
+	m.Executions++
+	if hit {
+		m.CacheHits++
+	} else {
+		m.CacheRebuilds++
+	}
+}
+
+// recordFilter returns a function that records one filter evaluation for the caller's site,
+// or nil if metrics are disabled.
+func (em *EntityManager) recordFilter() func(accepted bool) {
+	em.lockQueries()
+	m := em.callerMetrics()
+	em.unlockQueries()
+	if m == nil {
+		return nil
+	}
+
+	return func(accepted bool) {
+		em.lockQueries()
+		defer em.unlockQueries()
+
+		m.FilterEvaluations++
+		if !accepted {
+			m.FilterRejections++
+		}
+	}
+}
+
+// lockQueries locks the query plans and metrics of a concurrent EntityManager, see NewConcurrentEntityManager.
+func (em *EntityManager) lockQueries() {
+	if em.structure != nil {
+		em.queryLock.Lock()
+	}
+}
+
+func (em *EntityManager) unlockQueries() {
+	if em.structure != nil {
+		em.queryLock.Unlock()
+	}
+}
+
+var ecsPackagePrefix = reflect.TypeFor[EntityManager]().PkgPath() + "."
+
+// callerMetrics returns the metrics of the first caller outside this package, or nil if metrics are disabled.
+func (em *EntityManager) callerMetrics() *QueryMetrics {
+	if em.queryMetrics == nil {
+		return nil
+	}
+
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, ecsPackagePrefix) && more {
+			continue
+		}
+
+		if m, ok := em.queryMetricsByPC[frame.PC]; ok {
+			return m
+		}
+
+		// Several calls on the same line, e.g. Where(em, Query[C](em), f), share their metrics.
+		site := fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		m, ok := em.queryMetrics[site]
+		if !ok {
+			m = &QueryMetrics{Site: site}
+			em.queryMetrics[site] = m
+		}
+		em.queryMetricsByPC[frame.PC] = m
+
+		return m
+	}
+}
@@ -0,0 +1,52 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMetrics(t *testing.T) {
+	em := ecs.NewEntityManager()
+	em.SetQueryMetricsEnabled(true)
+
+	for range 4 {
+		NewCameraEntity(t, em)
+	}
+	cameraID, ok := ecs.First(ecs.Query[CameraComponent](em))
+	require.True(t, ok)
+	ecs.MustGetComponent[CameraComponent](em, cameraID).Zoom = 2
+	em.ResetQueryMetrics()
+
+	for range 3 {
+		ecs.Count(ecs.Query2[CameraComponent, TransformComponent](em))
+	}
+
+	zoomed := ecs.Where(em, ecs.Query[CameraComponent](em), func(c *CameraComponent) bool { return c.Zoom > 1 })
+	assert.Equal(t, 1, ecs.Count(zoomed))
+
+	metrics := em.QueryMetrics()
+	require.Len(t, metrics, 2)
+
+	queries, filter := metrics[0], metrics[1]
+	if queries.FilterEvaluations > filter.FilterEvaluations {
+		queries, filter = filter, queries
+	}
+
+	assert.Contains(t, queries.Site, "querymetrics_test.go")
+	assert.Equal(t, uint64(3), queries.Executions)
+	assert.Equal(t, uint64(1), queries.CacheRebuilds)
+	assert.Equal(t, uint64(2), queries.CacheHits)
+
+	assert.Equal(t, uint64(1), filter.Executions)
+	assert.Equal(t, uint64(1), filter.CacheHits)
+	assert.Equal(t, uint64(4), filter.FilterEvaluations)
+	assert.Equal(t, uint64(3), filter.FilterRejections)
+	assert.InDelta(t, 0.75, filter.RejectionRate(), 1e-9)
+
+	em.SetQueryMetricsEnabled(false)
+	ecs.Count(ecs.Query[CameraComponent](em))
+	assert.Empty(t, em.QueryMetrics())
+}
@@ -0,0 +1,50 @@
+package ecs
+
+// Ref is a weak reference to an entity, for components that point at other entities, such as a target
+// or a parent. Unlike a plain EntityID, which keeps pointing at an entity recycled by a Pool, a Ref
+// also records the generation of the entity and becomes invalid when it is removed or recycled:
+//
+//	type Homing struct {
+//		Target ecs.Ref
+//	}
+//
+//	homing.Target = em.Ref(enemy)
+//	if target, ok := homing.Target.Get(em); ok { ... }
+//
+// The zero Ref is invalid.
+type Ref struct {
+	Entity     EntityID
+	Generation uint32
+}
+
+// Ref returns a reference to the entity. The reference of an entity that does not exist is invalid.
+func (em *EntityManager) Ref(entityID EntityID) Ref {
+	if !em.Exists(entityID) {
+		return Ref{}
+	}
+
+	return Ref{Entity: entityID, Generation: em.generations[entityID]}
+}
+
+// Get returns the referenced entity, or false if it was removed or recycled since the reference was taken.
+func (r Ref) Get(em *EntityManager) (EntityID, bool) {
+	if r.Entity == UndefinedID || !em.Exists(r.Entity) || em.generations[r.Entity] != r.Generation {
+		return UndefinedID, false
+	}
+
+	return r.Entity, true
+}
+
+// Generation returns the number of times the entity was recycled by a Pool, invalidating its references.
+func (em *EntityManager) Generation(entityID EntityID) uint32 {
+	return em.generations[entityID]
+}
+
+// recycle invalidates the references to an entity that is reused as a new one.
+func (em *EntityManager) recycle(entityID EntityID) {
+	if em.generations == nil {
+		em.generations = make(map[EntityID]uint32)
+	}
+
+	em.generations[entityID]++
+}
@@ -0,0 +1,60 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type HomingComponent struct {
+	Target ecs.Ref
+}
+
+func TestRef(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	enemy := NewPlayerEntity(t, em)
+	missile := NewEmptyEntity(t, em)
+	ecs.AddComponent[HomingComponent](em, missile).Target = em.Ref(enemy)
+
+	homing := ecs.MustGetComponent[HomingComponent](em, missile)
+	target, ok := homing.Target.Get(em)
+	assert.True(t, ok)
+	assert.Equal(t, enemy, target)
+
+	em.Remove(enemy)
+	_, ok = homing.Target.Get(em)
+	assert.False(t, ok, "references to removed entities are invalid")
+
+	_, ok = ecs.Ref{}.Get(em)
+	assert.False(t, ok, "the zero Ref is invalid")
+	assert.Equal(t, ecs.Ref{}, em.Ref(enemy))
+}
+
+func TestRefPool(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	bullet := ecs.NewPrefab("bullet", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[TransformComponent](em, entityID)
+	})
+	pool := ecs.NewPool(em, bullet, 1)
+
+	first := pool.Acquire()
+	ref := em.Ref(first)
+	snapshot := em.Snapshot()
+
+	pool.Release(first)
+	again := pool.Acquire()
+	assert.Equal(t, first, again)
+	assert.Equal(t, uint32(1), em.Generation(again))
+
+	_, ok := ref.Get(em)
+	assert.False(t, ok, "references to recycled entities are invalid")
+	_, ok = em.Ref(again).Get(em)
+	assert.True(t, ok)
+
+	em.Restore(snapshot)
+	_, ok = ref.Get(em)
+	assert.True(t, ok, "generations are restored with snapshots")
+}
@@ -0,0 +1,187 @@
+package ecs
+
+import (
+	"iter"
+	"reflect"
+)
+
+// relationStore stores the pairs of entities related by a relation type, indexed in both directions
+// so either side can be cleaned up when it is removed.
+type relationStore struct {
+	targets map[EntityID]*tagSet
+	sources map[EntityID]*tagSet
+}
+
+func newRelationStore() *relationStore {
+	return &relationStore{
+		targets: make(map[EntityID]*tagSet),
+		sources: make(map[EntityID]*tagSet),
+	}
+}
+
+// link adds target to the set of source in index.
+func link(index map[EntityID]*tagSet, source, target EntityID) {
+	set, ok := index[source]
+	if !ok {
+		set = &tagSet{index: make(map[EntityID]int)}
+		index[source] = set
+	}
+
+	set.add(target)
+}
+
+// unlink removes target from the set of source in index, dropping the set once empty.
+func unlink(index map[EntityID]*tagSet, source, target EntityID) {
+	set, ok := index[source]
+	if !ok {
+		return
+	}
+
+	set.remove(target)
+	if len(set.entityIDs) == 0 {
+		delete(index, source)
+	}
+}
+
+func (s *relationStore) add(source, target EntityID) {
+	link(s.targets, source, target)
+	link(s.sources, target, source)
+}
+
+func (s *relationStore) remove(source, target EntityID) {
+	unlink(s.targets, source, target)
+	unlink(s.sources, target, source)
+}
+
+// removeEntity removes the relations of an entity, on either side.
+func (s *relationStore) removeEntity(entityID EntityID) {
+	if targets, ok := s.targets[entityID]; ok {
+		for _, target := range targets.entityIDs {
+			unlink(s.sources, target, entityID)
+		}
+		delete(s.targets, entityID)
+	}
+
+	if sources, ok := s.sources[entityID]; ok {
+		for _, source := range sources.entityIDs {
+			unlink(s.targets, source, entityID)
+		}
+		delete(s.sources, entityID)
+	}
+}
+
+func relationStoreOf[R any](em *EntityManager) (*relationStore, bool) {
+	store, ok := em.relations[reflect.TypeFor[R]()]
+	return store, ok
+}
+
+// Relate relates source to target with relation R, a type naming the relation, such as an empty struct:
+//
+//	type Targets struct{}
+//
+//	ecs.Relate[Targets](em, attacker, victim)
+//	for victim := range ecs.Related[Targets](em, attacker) { ... }
+//
+// An entity can be related to any number of entities, and relations are removed with either entity,
+// so they never refer to removed entities like EntityIDs stored in components can.
+// Relating entities that do not exist, or that are already related, has no effect.
+func Relate[R any](em *EntityManager, source, target EntityID) {
+	defer em.beginStructural("Relate")()
+
+	if !em.checkExists("Relate", source) || !em.checkExists("Relate", target) {
+		return
+	}
+
+	store, ok := relationStoreOf[R](em)
+	if !ok {
+		if em.relations == nil {
+			em.relations = make(map[reflect.Type]*relationStore)
+		}
+
+		store = newRelationStore()
+		em.relations[reflect.TypeFor[R]()] = store
+	}
+
+	store.add(source, target)
+}
+
+// Unrelate removes the relation R from source to target.
+func Unrelate[R any](em *EntityManager, source, target EntityID) {
+	defer em.beginStructural("Unrelate")()
+
+	if store, ok := relationStoreOf[R](em); ok {
+		store.remove(source, target)
+	}
+}
+
+// UnrelateAll removes every relation R from source.
+func UnrelateAll[R any](em *EntityManager, source EntityID) {
+	defer em.beginStructural("UnrelateAll")()
+
+	store, ok := relationStoreOf[R](em)
+	if !ok {
+		return
+	}
+
+	if targets, ok := store.targets[source]; ok {
+		for _, target := range targets.entityIDs {
+			unlink(store.sources, target, source)
+		}
+		delete(store.targets, source)
+	}
+}
+
+// HasRelation reports whether source is related to target with relation R.
+func HasRelation[R any](em *EntityManager, source, target EntityID) bool {
+	store, ok := relationStoreOf[R](em)
+	if !ok {
+		return false
+	}
+
+	targets, ok := store.targets[source]
+	return ok && targets.has(target)
+}
+
+// Related returns the entities source is related to with relation R, active or not, in no particular order.
+// Like queries, the relations must not change while the sequence is iterated; defer changes with the command buffer.
+func Related[R any](em *EntityManager, source EntityID) iter.Seq[EntityID] {
+	store, ok := relationStoreOf[R](em)
+	if !ok {
+		return func(yield func(EntityID) bool) {}
+	}
+
+	return em.guardIteration(relatedIn(store.targets, source))
+}
+
+// RelatedTo returns the entities related to target with relation R, e.g. the attackers targeting a victim.
+// See Related.
+func RelatedTo[R any](em *EntityManager, target EntityID) iter.Seq[EntityID] {
+	store, ok := relationStoreOf[R](em)
+	if !ok {
+		return func(yield func(EntityID) bool) {}
+	}
+
+	return em.guardIteration(relatedIn(store.sources, target))
+}
+
+func relatedIn(index map[EntityID]*tagSet, entityID EntityID) iter.Seq[EntityID] {
+	return func(yield func(EntityID) bool) {
+		set, ok := index[entityID]
+		if !ok {
+			return
+		}
+
+		for _, related := range set.entityIDs {
+			if !yield(related) {
+				return
+			}
+		}
+	}
+}
+
+// removeRelations removes the relations of a removed or released entity.
+func (em *EntityManager) removeRelations(entityID EntityID) {
+	for _, store := range em.relations {
+		store.removeEntity(entityID)
+	}
+}
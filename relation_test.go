@@ -0,0 +1,76 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type Targets struct{}
+
+type DockedTo struct{}
+
+func TestRelations(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	attacker := NewPlayerEntity(t, em)
+	victim := NewPlayerEntity(t, em)
+	bystander := NewPlayerEntity(t, em)
+
+	ecs.Relate[Targets](em, attacker, victim)
+	ecs.Relate[Targets](em, attacker, bystander)
+	ecs.Relate[Targets](em, attacker, victim)
+	ecs.Relate[Targets](em, bystander, victim)
+	ecs.Relate[DockedTo](em, attacker, bystander)
+	ecs.Relate[Targets](em, attacker, ecs.UndefinedID)
+
+	assert.ElementsMatch(t, []ecs.EntityID{victim, bystander}, slices.Collect(ecs.Related[Targets](em, attacker)))
+	assert.ElementsMatch(t, []ecs.EntityID{attacker, bystander}, slices.Collect(ecs.RelatedTo[Targets](em, victim)))
+	assert.Equal(t, []ecs.EntityID{bystander}, slices.Collect(ecs.Related[DockedTo](em, attacker)), "relation types are independent")
+	assert.True(t, ecs.HasRelation[Targets](em, attacker, victim))
+	assert.False(t, ecs.HasRelation[Targets](em, victim, attacker), "relations are directed")
+
+	ecs.Unrelate[Targets](em, attacker, bystander)
+	assert.Equal(t, []ecs.EntityID{victim}, slices.Collect(ecs.Related[Targets](em, attacker)))
+	assert.Empty(t, slices.Collect(ecs.RelatedTo[Targets](em, bystander)))
+
+	em.Remove(victim)
+	assert.Empty(t, slices.Collect(ecs.Related[Targets](em, attacker)), "relations to removed entities are removed")
+	assert.Empty(t, slices.Collect(ecs.Related[Targets](em, bystander)))
+
+	em.Remove(attacker)
+	assert.Empty(t, slices.Collect(ecs.RelatedTo[DockedTo](em, bystander)), "relations from removed entities are removed")
+}
+
+func TestUnrelateAll(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	ship := NewEmptyEntity(t, em)
+	stations := []ecs.EntityID{NewEmptyEntity(t, em), NewEmptyEntity(t, em)}
+	for _, station := range stations {
+		ecs.Relate[DockedTo](em, ship, station)
+	}
+
+	ecs.UnrelateAll[DockedTo](em, ship)
+	assert.Empty(t, slices.Collect(ecs.Related[DockedTo](em, ship)))
+	for _, station := range stations {
+		assert.Empty(t, slices.Collect(ecs.RelatedTo[DockedTo](em, station)))
+	}
+}
+
+func TestRelationsSnapshot(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	attacker := NewEmptyEntity(t, em)
+	victim := NewEmptyEntity(t, em)
+	ecs.Relate[Targets](em, attacker, victim)
+
+	snapshot := em.Snapshot()
+	em.Remove(victim)
+	em.Restore(snapshot)
+
+	assert.True(t, ecs.HasRelation[Targets](em, attacker, victim))
+	assert.Equal(t, []ecs.EntityID{attacker}, slices.Collect(ecs.RelatedTo[Targets](em, victim)))
+}
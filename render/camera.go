@@ -0,0 +1,234 @@
+package render
+
+import (
+	"cmp"
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+func init() {
+	ecs.Require[Camera, transform.Transform]()
+}
+
+// Camera views the world from its entity's transform.Transform, which is added with the camera if missing:
+// the transform's position is drawn at the center of its viewport, and its rotation turns the view.
+// The RenderSystem and the tilemap renderer draw the world once per camera, in ascending entity ID order,
+// e.g. twice for a split screen, or in world space if there is none, see AppendCameraPasses.
+// The lighting and post-processing systems apply to the first camera, see ActiveCamera.
+type Camera struct {
+	// Zoom scales the view, 2 showing everything twice as large. Zero is treated as 1.
+	Zoom float64
+	// Viewport is the part of the canvas, or of the Target, the camera draws into.
+	// The zero Viewport covers all of it.
+	Viewport Viewport
+	// Target is the image the camera draws into instead of the canvas, e.g. a minimap shown by a Sprite.
+	// It is not cleared by the render systems: clear it before they draw, e.g. in a Game.BeforeDraw hook.
+	Target *ebiten.Image
+	// PostProcess is the chain of full-screen shader passes applied to the view by the PostProcessSystem, in order.
+	PostProcess []ShaderPass
+}
+
+// Init sets the zoom to 1.
+func (c *Camera) Init() {
+	c.Zoom = 1
+}
+
+func (c *Camera) Reset() {
+	*c = Camera{}
+}
+
+// Viewport is a rectangle of a canvas, as fractions of its size: {{0, 0}, {0.5, 1}} is its left half.
+type Viewport struct {
+	Min, Max f64.Vec2
+}
+
+// Rect returns the viewport within bounds, in pixels. The zero Viewport returns bounds.
+func (v Viewport) Rect(bounds image.Rectangle) image.Rectangle {
+	if v == (Viewport{}) {
+		return bounds
+	}
+
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	return image.Rect(
+		bounds.Min.X+int(v.Min[0]*width), bounds.Min.Y+int(v.Min[1]*height),
+		bounds.Min.X+int(v.Max[0]*width), bounds.Min.Y+int(v.Max[1]*height),
+	).Intersect(bounds)
+}
+
+// View maps world space to a canvas.
+type View struct {
+	// GeoM transforms world coordinates into canvas coordinates.
+	GeoM ebiten.GeoM
+	// Min and Max are the corners of the axis-aligned bounding box of the visible part of the world.
+	Min, Max f64.Vec2
+}
+
+// ToWorld converts a position on the canvas, such as the cursor, to world space.
+func (v *View) ToWorld(x, y float64) f64.Vec2 {
+	inverse := v.GeoM
+	inverse.Invert()
+
+	wx, wy := inverse.Apply(x, y)
+	return f64.Vec2{wx, wy}
+}
+
+// ToCanvas converts a position in world space to the canvas.
+func (v *View) ToCanvas(point f64.Vec2) (x, y float64) {
+	return v.GeoM.Apply(point[0], point[1])
+}
+
+// Contains reports whether the rectangle from minPoint to maxPoint, in world space, overlaps the view.
+func (v *View) Contains(minPoint, maxPoint f64.Vec2) bool {
+	return minPoint[0] <= v.Max[0] && maxPoint[0] >= v.Min[0] && minPoint[1] <= v.Max[1] && maxPoint[1] >= v.Min[1]
+}
+
+// ActiveCamera returns the first active entity with a Camera, in ascending ID order.
+func ActiveCamera(em *ecs.EntityManager) (ecs.EntityID, bool) {
+	camera, found := ecs.UndefinedID, false
+	for entityID := range ecs.Query2[Camera, transform.Transform](em) {
+		if !found || entityID < camera {
+			camera, found = entityID, true
+		}
+	}
+
+	return camera, found
+}
+
+// ActiveView returns the view of the active camera on a canvas of width x height pixels,
+// or the identity view of the canvas if there is no camera.
+func ActiveView(em *ecs.EntityManager, width, height int) View {
+	entityID, ok := ActiveCamera(em)
+	if !ok {
+		return CameraView(nil, nil, width, height)
+	}
+
+	return CameraView(ecs.MustGetComponent[Camera](em, entityID), ecs.MustGetComponent[transform.Transform](em, entityID), width, height)
+}
+
+// CameraView returns the view of camera at tr on a canvas of width x height pixels.
+// A nil camera returns the identity view of the canvas.
+func CameraView(camera *Camera, tr *transform.Transform, width, height int) View {
+	return ViewportView(camera, tr, image.Rect(0, 0, width, height))
+}
+
+// ViewportView returns the view of camera at tr drawing into the rectangle rect of a canvas,
+// whose center shows the camera position. A nil camera returns the identity view of the canvas, limited to rect.
+func ViewportView(camera *Camera, tr *transform.Transform, rect image.Rectangle) View {
+	var view View
+	if camera != nil {
+		zoom := camera.Zoom
+		if zoom == 0 {
+			zoom = 1
+		}
+
+		view.GeoM.Translate(-tr.Position[0], -tr.Position[1])
+		view.GeoM.Rotate(-tr.Rotation)
+		view.GeoM.Scale(zoom, zoom)
+		view.GeoM.Translate(float64(rect.Min.X)+float64(rect.Dx())/2, float64(rect.Min.Y)+float64(rect.Dy())/2)
+	}
+
+	inverse := view.GeoM
+	inverse.Invert()
+	view.Min, view.Max = transformedBounds(&inverse, float64(rect.Min.X), float64(rect.Min.Y), float64(rect.Max.X), float64(rect.Max.Y))
+
+	return view
+}
+
+// CameraPass is the world drawn through one camera: the view, and the canvas it is drawn onto.
+type CameraPass struct {
+	// Camera is the camera entity, or ecs.UndefinedID for the identity view used without cameras.
+	Camera ecs.EntityID
+	View   View
+	// Canvas is the viewport of the camera on its target or on the canvas the passes were created for,
+	// clipped to the viewport when it is an *ebiten.Image. Its Bounds are the viewport rectangle,
+	// in the coordinates of the whole canvas, which the View maps to.
+	Canvas ecs.Canvas
+}
+
+// AppendCameraPasses appends to passes the passes of the cameras drawing onto canvas or into their Target,
+// in ascending entity ID order, and returns the extended slice. Without cameras, it appends the identity
+// view of canvas. Cameras with an empty viewport are skipped.
+func AppendCameraPasses(passes []CameraPass, em *ecs.EntityManager, canvas ecs.Canvas) []CameraPass {
+	start, found := len(passes), false
+	for entityID := range ecs.Query2[Camera, transform.Transform](em) {
+		camera := ecs.MustGetComponent[Camera](em, entityID)
+		found = true
+
+		var target ecs.Canvas = canvas
+		if camera.Target != nil {
+			target = camera.Target
+		}
+
+		rect := camera.Viewport.Rect(target.Bounds())
+		if rect.Empty() {
+			continue
+		}
+
+		passes = append(passes, CameraPass{
+			Camera: entityID,
+			View:   ViewportView(camera, ecs.MustGetComponent[transform.Transform](em, entityID), rect),
+			Canvas: viewportCanvas(target, rect),
+		})
+	}
+
+	if !found {
+		passes = append(passes, CameraPass{
+			Camera: ecs.UndefinedID,
+			View:   ViewportView(nil, nil, canvas.Bounds()),
+			Canvas: canvas,
+		})
+	}
+
+	slices.SortFunc(passes[start:], func(a, b CameraPass) int {
+		return cmp.Compare(a.Camera, b.Camera)
+	})
+
+	return passes
+}
+
+// viewportCanvas returns the part rect of canvas: a sub-image of an *ebiten.Image, which clips the draws to rect,
+// or canvas with rect as bounds otherwise.
+func viewportCanvas(canvas ecs.Canvas, rect image.Rectangle) ecs.Canvas {
+	if rect == canvas.Bounds() {
+		return canvas
+	}
+
+	if img, ok := canvas.(*ebiten.Image); ok {
+		return img.SubImage(rect).(*ebiten.Image)
+	}
+
+	return boundedCanvas{Canvas: canvas, bounds: rect}
+}
+
+// boundedCanvas draws onto a Canvas that cannot be clipped, such as a Recorder, reporting bounds as its Bounds.
+type boundedCanvas struct {
+	ecs.Canvas
+	bounds image.Rectangle
+}
+
+func (c boundedCanvas) Bounds() image.Rectangle {
+	return c.bounds
+}
+
+// transformedBounds returns the axis-aligned bounding box of the rectangle from (minX, minY) to (maxX, maxY)
+// transformed by geoM.
+func transformedBounds(geoM *ebiten.GeoM, minX, minY, maxX, maxY float64) (minPoint, maxPoint f64.Vec2) {
+	corners := [4][2]float64{{minX, minY}, {maxX, minY}, {minX, maxY}, {maxX, maxY}}
+	for i, corner := range corners {
+		x, y := geoM.Apply(corner[0], corner[1])
+		if i == 0 {
+			minPoint, maxPoint = f64.Vec2{x, y}, f64.Vec2{x, y}
+			continue
+		}
+
+		minPoint = f64.Vec2{min(minPoint[0], x), min(minPoint[1], y)}
+		maxPoint = f64.Vec2{max(maxPoint[0], x), max(maxPoint[1], y)}
+	}
+
+	return minPoint, maxPoint
+}
@@ -0,0 +1,30 @@
+package render
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// Material draws the entity's Sprite with a Kage shader instead of copying its image. The shader reads
+// the sprite's region with imageSrc0At, and the images of Images with imageSrc1At to imageSrc3At.
+// Ebiten requires the source images of a shader to have the same size, so the RenderSystem crops Images
+// to the size of the region, or of each part of nine-slice and tiled sprites, from their top-left corner.
+// Sprites with an image smaller than that are not drawn.
+type Material struct {
+	Shader *ebiten.Shader
+	// Uniforms are the values of the shader's uniform variables, by name.
+	Uniforms map[string]any
+	// Images are the additional source images of the shader, such as a noise texture.
+	Images [3]*ebiten.Image
+}
+
+func (m *Material) Reset() {
+	*m = Material{}
+}
+
+// ShaderPass is a full-screen shader pass of a Camera's post-processing chain. The shader reads
+// the output of the previous pass, or the scene for the first pass, with imageSrc0At.
+type ShaderPass struct {
+	Shader *ebiten.Shader
+	// Uniforms are the values of the shader's uniform variables, by name.
+	Uniforms map[string]any
+	// Disabled passes are skipped.
+	Disabled bool
+}
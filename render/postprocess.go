@@ -0,0 +1,107 @@
+package render
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.CanvasSystem = (*PostProcessSystem)(nil)
+
+// PostProcessSystem applies the PostProcess passes of the active Camera to everything drawn before it,
+// so it must be drawn after the other render systems, with a higher priority. The scene is copied into
+// an offscreen target, each pass renders the previous target into the next one, and the last pass renders
+// onto the canvas. The scene can only be read from an *ebiten.Image canvas: on other canvases, such as
+// a Recorder, the passes start from a cleared target.
+type PostProcessSystem struct {
+	*ecs.BaseSystem
+
+	// targets are the offscreen targets the passes render into, alternately.
+	targets [2]*ebiten.Image
+	passes  []ShaderPass
+	op      ebiten.DrawRectShaderOptions
+}
+
+// NewPostProcessSystem creates a new PostProcessSystem with the given ID and priority.
+func NewPostProcessSystem(id ecs.SystemID, priority int) *PostProcessSystem {
+	return &PostProcessSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+}
+
+// Update does nothing; the passes are applied in DrawCanvas.
+func (s *PostProcessSystem) Update() error {
+	return nil
+}
+
+// DrawCanvas applies the post-processing passes of the active camera to canvas.
+func (s *PostProcessSystem) DrawCanvas(canvas ecs.Canvas) {
+	em := s.EntityManager()
+
+	entityID, ok := ActiveCamera(em)
+	if !ok {
+		return
+	}
+
+	s.passes = s.passes[:0]
+	for _, pass := range ecs.MustGetComponent[Camera](em, entityID).PostProcess {
+		if !pass.Disabled && pass.Shader != nil {
+			s.passes = append(s.passes, pass)
+		}
+	}
+
+	if len(s.passes) == 0 {
+		return
+	}
+
+	bounds := canvas.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	s.allocate(width, height)
+
+	source := s.targets[0]
+	source.Clear()
+	if screen, ok := canvas.(*ebiten.Image); ok {
+		source.DrawImage(screen, &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy})
+	}
+
+	for i, pass := range s.passes {
+		s.op.Blend = ebiten.BlendCopy
+		s.op.Uniforms = pass.Uniforms
+		s.op.Images[0] = source
+
+		if i == len(s.passes)-1 {
+			canvas.DrawRectShader(width, height, pass.Shader, &s.op)
+			break
+		}
+
+		destination := s.targets[(i+1)%2]
+		destination.DrawRectShader(width, height, pass.Shader, &s.op)
+		source = destination
+	}
+
+	s.op.Images[0] = nil
+}
+
+// Teardown deallocates the offscreen targets.
+func (s *PostProcessSystem) Teardown() {
+	for i, target := range s.targets {
+		if target != nil {
+			target.Deallocate()
+			s.targets[i] = nil
+		}
+	}
+}
+
+// allocate creates the offscreen targets, or recreates them when the canvas was resized.
+func (s *PostProcessSystem) allocate(width, height int) {
+	for i, target := range s.targets {
+		if target != nil && target.Bounds().Dx() == width && target.Bounds().Dy() == height {
+			continue
+		}
+
+		if target != nil {
+			target.Deallocate()
+		}
+
+		s.targets[i] = ebiten.NewImage(width, height)
+	}
+}
@@ -0,0 +1,117 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tintShader = `//kage:unit pixels
+package main
+
+var Tint vec4
+
+func Fragment(dst vec4, src vec2, color vec4) vec4 {
+	return imageSrc0At(src) * Tint
+}
+`
+
+func newTintShader(t *testing.T) *ebiten.Shader {
+	t.Helper()
+
+	shader, err := ebiten.NewShader([]byte(tintShader))
+	require.NoError(t, err)
+
+	return shader
+}
+
+func TestMaterial(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	sm.Add(render.NewRenderSystem(1, 0))
+
+	shader := newTintShader(t)
+	img := ebiten.NewImage(16, 8)
+	plain := spawnSprite(em, img, 0, 0)
+	tinted := spawnSprite(em, img, 10, 20)
+	ecs.AddComponent[render.Material](em, tinted).Shader = shader
+	ecs.MustGetComponent[render.Material](em, tinted).Uniforms = map[string]any{"Tint": []float32{1, 0, 0, 1}}
+	ecs.AddComponent[render.Material](em, plain)
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+
+	ops := rec.Ops()
+	require.Len(t, ops, 2)
+	assert.Equal(t, render.OpDrawImage, ops[0].Kind, "materials without a shader are ignored")
+	assert.Equal(t, render.OpDrawRectShader, ops[1].Kind)
+	assert.Equal(t, rec.ShaderID(shader), ops[1].Shader)
+	assert.Equal(t, 16, ops[1].Width)
+	assert.Equal(t, 8, ops[1].Height)
+	assert.Equal(t, [6]float64{1, 0, 10, 0, 1, 20}, ops[1].GeoM)
+	assert.Equal(t, map[string]any{"Tint": []float32{1, 0, 0, 1}}, ops[1].Uniforms)
+}
+
+func TestMaterialImages(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	sm.Add(render.NewRenderSystem(1, 0))
+
+	shader := newTintShader(t)
+	noise := ebiten.NewImage(32, 32)
+
+	sprite := spawnSprite(em, ebiten.NewImage(16, 8), 10, 20)
+	material := ecs.AddComponent[render.Material](em, sprite)
+	material.Shader = shader
+	material.Uniforms = map[string]any{"Tint": []float32{1, 1, 1, 1}}
+	material.Images[0] = noise
+
+	assert.NotPanics(t, func() { sm.DrawCanvas(ebiten.NewImage(320, 240)) },
+		"the secondary images are cropped to the size of the sprite")
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+	require.Len(t, rec.Ops(), 1)
+	assert.NotZero(t, rec.Ops()[0].Images[1])
+
+	material.Images[0] = ebiten.NewImage(8, 8)
+	rec.Reset()
+	assert.NotPanics(t, func() { sm.DrawCanvas(ebiten.NewImage(320, 240)) })
+	sm.DrawCanvas(rec)
+	assert.Empty(t, rec.Ops(), "sprites with a smaller secondary image are skipped")
+}
+
+func TestPostProcessSystem(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	sm.Add(render.NewRenderSystem(1, 0), render.NewPostProcessSystem(2, 1))
+
+	spawnSprite(em, ebiten.NewImage(4, 4), 0, 0)
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+	require.Len(t, rec.Ops(), 1, "nothing to do without a camera")
+
+	shader := newTintShader(t)
+	camera := em.NewEntity()
+	ecs.AddComponent[render.Camera](em, camera).PostProcess = []render.ShaderPass{
+		{Shader: shader, Uniforms: map[string]any{"Tint": []float32{1, 1, 1, 0.5}}},
+		{Shader: shader, Disabled: true},
+		{Shader: shader, Uniforms: map[string]any{"Tint": []float32{0, 1, 0, 1}}},
+	}
+
+	rec.Reset()
+	sm.DrawCanvas(rec)
+
+	ops := rec.Ops()
+	require.Len(t, ops, 2, "only the last pass draws onto the canvas")
+	assert.Equal(t, render.OpDrawRectShader, ops[1].Kind)
+	assert.Equal(t, 320, ops[1].Width)
+	assert.Equal(t, 240, ops[1].Height)
+	assert.Equal(t, map[string]any{"Tint": []float32{0, 1, 0, 1}}, ops[1].Uniforms)
+	assert.NotZero(t, ops[1].Images[0], "the last pass reads the previous target")
+}
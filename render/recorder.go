@@ -0,0 +1,308 @@
+// Package render provides the rendering building blocks of the ECS: drawing surfaces,
+// draw call recording for headless tests, and the built-in render components and systems.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+var _ ecs.Canvas = (*Recorder)(nil)
+
+// OpKind identifies the kind of a recorded draw operation.
+type OpKind int
+
+const (
+	OpFill OpKind = iota
+	OpDrawImage
+	OpDrawRectShader
+	OpDrawTriangles
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpFill:
+		return "Fill"
+	case OpDrawImage:
+		return "DrawImage"
+	case OpDrawRectShader:
+		return "DrawRectShader"
+	case OpDrawTriangles:
+		return "DrawTriangles"
+	default:
+		return fmt.Sprintf("OpKind(%d)", int(k))
+	}
+}
+
+// Op is a single recorded draw operation.
+// Images and shaders are identified by IDs assigned by the Recorder in the order they are first seen,
+// so two recordings of the same deterministic drawing code compare equal.
+type Op struct {
+	Kind OpKind
+
+	// Image is the ID of the source image, or 0 if the operation has none.
+	Image int
+	// Source is the bounds of the source image, which identifies the region of sub-images.
+	Source image.Rectangle
+	// Shader is the ID of the shader, or 0 if the operation has none.
+	Shader int
+	// Images are the IDs of the shader source images.
+	Images [4]int
+
+	GeoM       [6]float64
+	ColorScale [4]float32
+	Blend      ebiten.Blend
+	Filter     ebiten.Filter
+	Color      color.RGBA
+
+	Width, Height int
+	Uniforms      map[string]any
+	Vertices      []ebiten.Vertex
+	Indices       []uint16
+
+	img     *ebiten.Image
+	shader  *ebiten.Shader
+	options any
+}
+
+// Equal reports whether two operations are identical, ignoring the underlying image and shader pointers.
+func (o Op) Equal(other Op) bool {
+	return o.Kind == other.Kind &&
+		o.Image == other.Image &&
+		o.Source == other.Source &&
+		o.Shader == other.Shader &&
+		o.Images == other.Images &&
+		o.GeoM == other.GeoM &&
+		o.ColorScale == other.ColorScale &&
+		o.Blend == other.Blend &&
+		o.Filter == other.Filter &&
+		o.Color == other.Color &&
+		o.Width == other.Width &&
+		o.Height == other.Height &&
+		fmt.Sprint(o.Uniforms) == fmt.Sprint(other.Uniforms) &&
+		slices.Equal(o.Vertices, other.Vertices) &&
+		slices.Equal(o.Indices, other.Indices)
+}
+
+func (o Op) String() string {
+	var b strings.Builder
+	b.WriteString(o.Kind.String())
+
+	switch o.Kind {
+	case OpFill:
+		fmt.Fprintf(&b, " color=%v", o.Color)
+	case OpDrawImage:
+		fmt.Fprintf(&b, " image=%d source=%v geom=%v scale=%v filter=%v", o.Image, o.Source, o.GeoM, o.ColorScale, o.Filter)
+	case OpDrawRectShader:
+		fmt.Fprintf(&b, " shader=%d size=%dx%d images=%v geom=%v scale=%v uniforms=%v", o.Shader, o.Width, o.Height, o.Images, o.GeoM, o.ColorScale, o.Uniforms)
+	case OpDrawTriangles:
+		fmt.Fprintf(&b, " image=%d vertices=%d indices=%d", o.Image, len(o.Vertices), len(o.Indices))
+	}
+
+	return b.String()
+}
+
+// Recorder is a Canvas that records draw operations instead of executing them.
+// Recorded operations can be compared against expectations or replayed onto a real image.
+// Systems drawn with SystemManager.DrawCanvas must implement ecs.CanvasSystem, or have a RenderTarget,
+// to be recorded: plain DrawableSystems are skipped.
+type Recorder struct {
+	bounds image.Rectangle
+	ops    []Op
+
+	imageIDs  map[*ebiten.Image]int
+	shaderIDs map[*ebiten.Shader]int
+}
+
+// NewRecorder creates a Recorder whose Bounds are width x height.
+func NewRecorder(width, height int) *Recorder {
+	return &Recorder{
+		bounds:    image.Rect(0, 0, width, height),
+		imageIDs:  make(map[*ebiten.Image]int),
+		shaderIDs: make(map[*ebiten.Shader]int),
+	}
+}
+
+// Ops returns a copy of the recorded operations, which stays unchanged by later draws and Reset.
+func (r *Recorder) Ops() []Op {
+	return slices.Clone(r.ops)
+}
+
+// Reset discards the recorded operations and image and shader IDs.
+func (r *Recorder) Reset() {
+	r.ops = r.ops[:0]
+	clear(r.imageIDs)
+	clear(r.shaderIDs)
+}
+
+// ImageID returns the ID assigned to img, or 0 if img has not been drawn.
+func (r *Recorder) ImageID(img *ebiten.Image) int {
+	return r.imageIDs[img]
+}
+
+// ShaderID returns the ID assigned to shader, or 0 if shader has not been used.
+func (r *Recorder) ShaderID(shader *ebiten.Shader) int {
+	return r.shaderIDs[shader]
+}
+
+func (r *Recorder) imageID(img *ebiten.Image) int {
+	if img == nil {
+		return 0
+	}
+
+	id, ok := r.imageIDs[img]
+	if !ok {
+		id = len(r.imageIDs) + 1
+		r.imageIDs[img] = id
+	}
+
+	return id
+}
+
+func (r *Recorder) shaderID(shader *ebiten.Shader) int {
+	if shader == nil {
+		return 0
+	}
+
+	id, ok := r.shaderIDs[shader]
+	if !ok {
+		id = len(r.shaderIDs) + 1
+		r.shaderIDs[shader] = id
+	}
+
+	return id
+}
+
+// Bounds returns the bounds the Recorder was created with.
+func (r *Recorder) Bounds() image.Rectangle {
+	return r.bounds
+}
+
+// Fill records a fill operation.
+func (r *Recorder) Fill(clr color.Color) {
+	r.ops = append(r.ops, Op{
+		Kind:  OpFill,
+		Color: color.RGBAModel.Convert(clr).(color.RGBA),
+	})
+}
+
+// DrawImage records a DrawImage operation.
+func (r *Recorder) DrawImage(img *ebiten.Image, options *ebiten.DrawImageOptions) {
+	if options == nil {
+		options = &ebiten.DrawImageOptions{}
+	}
+
+	op := Op{
+		Kind:       OpDrawImage,
+		Image:      r.imageID(img),
+		GeoM:       geoMElements(options.GeoM),
+		ColorScale: colorScaleElements(options.ColorScale),
+		Blend:      options.Blend,
+		Filter:     options.Filter,
+		img:        img,
+		options:    *options,
+	}
+	if img != nil {
+		op.Source = img.Bounds()
+	}
+
+	r.ops = append(r.ops, op)
+}
+
+// DrawRectShader records a DrawRectShader operation.
+func (r *Recorder) DrawRectShader(width, height int, shader *ebiten.Shader, options *ebiten.DrawRectShaderOptions) {
+	if options == nil {
+		options = &ebiten.DrawRectShaderOptions{}
+	}
+
+	op := Op{
+		Kind:       OpDrawRectShader,
+		Shader:     r.shaderID(shader),
+		Width:      width,
+		Height:     height,
+		GeoM:       geoMElements(options.GeoM),
+		ColorScale: colorScaleElements(options.ColorScale),
+		Blend:      options.Blend,
+		Uniforms:   options.Uniforms,
+		shader:     shader,
+		options:    *options,
+	}
+	for i, img := range options.Images {
+		op.Images[i] = r.imageID(img)
+	}
+
+	r.ops = append(r.ops, op)
+}
+
+// DrawTriangles records a DrawTriangles operation. The vertex and index slices are copied.
+func (r *Recorder) DrawTriangles(vertices []ebiten.Vertex, indices []uint16, img *ebiten.Image, options *ebiten.DrawTrianglesOptions) {
+	if options == nil {
+		options = &ebiten.DrawTrianglesOptions{}
+	}
+
+	r.ops = append(r.ops, Op{
+		Kind:     OpDrawTriangles,
+		Image:    r.imageID(img),
+		Blend:    options.Blend,
+		Filter:   options.Filter,
+		Vertices: slices.Clone(vertices),
+		Indices:  slices.Clone(indices),
+		img:      img,
+		options:  *options,
+	})
+}
+
+// Replay executes the recorded operations on canvas.
+func (r *Recorder) Replay(canvas ecs.Canvas) {
+	for _, op := range r.ops {
+		switch op.Kind {
+		case OpFill:
+			canvas.Fill(op.Color)
+		case OpDrawImage:
+			options := op.options.(ebiten.DrawImageOptions)
+			canvas.DrawImage(op.img, &options)
+		case OpDrawRectShader:
+			options := op.options.(ebiten.DrawRectShaderOptions)
+			canvas.DrawRectShader(op.Width, op.Height, op.shader, &options)
+		case OpDrawTriangles:
+			options := op.options.(ebiten.DrawTrianglesOptions)
+			canvas.DrawTriangles(op.Vertices, op.Indices, op.img, &options)
+		}
+	}
+}
+
+// Diff compares two recordings and returns a human readable description of the differences,
+// or an empty string if they are equal.
+func Diff(want, got []Op) string {
+	var b strings.Builder
+
+	for i := range max(len(want), len(got)) {
+		switch {
+		case i >= len(want):
+			fmt.Fprintf(&b, "op %d: unexpected %s\n", i, got[i])
+		case i >= len(got):
+			fmt.Fprintf(&b, "op %d: missing %s\n", i, want[i])
+		case !want[i].Equal(got[i]):
+			fmt.Fprintf(&b, "op %d:\n\twant %s\n\tgot  %s\n", i, want[i], got[i])
+		}
+	}
+
+	return b.String()
+}
+
+func geoMElements(g ebiten.GeoM) [6]float64 {
+	return [6]float64{
+		g.Element(0, 0), g.Element(0, 1), g.Element(0, 2),
+		g.Element(1, 0), g.Element(1, 1), g.Element(1, 2),
+	}
+}
+
+func colorScaleElements(c ebiten.ColorScale) [4]float32 {
+	return [4]float32{c.R(), c.G(), c.B(), c.A()}
+}
@@ -0,0 +1,100 @@
+package render_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/stretchr/testify/assert"
+)
+
+type markerSystem struct {
+	*ecs.BaseSystem
+
+	img *ebiten.Image
+	x   float64
+}
+
+func (s *markerSystem) Update() error {
+	s.x++
+	return nil
+}
+
+func (s *markerSystem) DrawCanvas(canvas ecs.Canvas) {
+	canvas.Fill(color.Black)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(s.x, 10)
+	canvas.DrawImage(s.img, op)
+}
+
+func TestRecorder(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	system := &markerSystem{BaseSystem: ecs.NewBaseSystem(1, 0), img: ebiten.NewImage(4, 4)}
+	sm.Add(system)
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+
+	ops := rec.Ops()
+	if assert.Len(t, ops, 2) {
+		assert.Equal(t, render.OpFill, ops[0].Kind)
+		assert.Equal(t, render.OpDrawImage, ops[1].Kind)
+		assert.Equal(t, 1, ops[1].Image)
+		assert.Equal(t, rec.ImageID(system.img), ops[1].Image)
+		assert.Equal(t, [6]float64{1, 0, 0, 0, 1, 10}, ops[1].GeoM)
+	}
+
+	want := append([]render.Op(nil), ops...)
+
+	rec.Reset()
+	sm.DrawCanvas(rec)
+	assert.Empty(t, render.Diff(want, rec.Ops()))
+
+	assert.NoError(t, sm.Update())
+	rec.Reset()
+	sm.DrawCanvas(rec)
+	assert.NotEmpty(t, render.Diff(want, rec.Ops()))
+}
+
+func TestRenderTarget(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	system := &markerSystem{BaseSystem: ecs.NewBaseSystem(ecs.UndefinedID, 0), img: ebiten.NewImage(4, 4)}
+	system.SetRenderTarget(&ecs.RenderTarget{Width: 80, Height: 60, X: 5, Y: 10, Scale: 4})
+	assert.NoError(t, sm.Add(system))
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+
+	img, ok := sm.RenderTargetImage(system.ID())
+	if assert.True(t, ok) {
+		assert.Equal(t, image.Rect(0, 0, 80, 60), img.Bounds())
+	}
+
+	ops := rec.Ops()
+	if assert.Len(t, ops, 1, "the system draws into its target, which is composited") {
+		assert.Equal(t, rec.ImageID(img), ops[0].Image)
+		assert.Equal(t, [6]float64{4, 0, 5, 0, 4, 10}, ops[0].GeoM)
+		assert.Equal(t, ebiten.FilterNearest, ops[0].Filter)
+	}
+
+	system.RenderTarget().Offscreen = true
+	rec.Reset()
+	sm.DrawCanvas(rec)
+	assert.Empty(t, rec.Ops())
+	assert.Len(t, ops, 1, "Ops returns a copy that Reset leaves unchanged")
+
+	system.SetRenderTarget(nil)
+	rec.Reset()
+	sm.DrawCanvas(rec)
+	assert.Len(t, rec.Ops(), 2, "without a target, the system draws onto the canvas again")
+	_, ok = sm.RenderTargetImage(system.ID())
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"fmt"
+	"image"
+)
+
+// SpriteMode is the way a Sprite is drawn.
+type SpriteMode int
+
+const (
+	// ModeSimple draws the Source region of the image once.
+	ModeSimple SpriteMode = iota
+	// ModeNineSlice stretches the Source region to DrawSize without distorting its borders,
+	// for panels and frames. The borders are set by Slice.
+	ModeNineSlice
+	// ModeTiled repeats the Source region to fill DrawSize, cutting the last row and column.
+	ModeTiled
+)
+
+func (m SpriteMode) String() string {
+	switch m {
+	case ModeSimple:
+		return "simple"
+	case ModeNineSlice:
+		return "nine-slice"
+	case ModeTiled:
+		return "tiled"
+	default:
+		return fmt.Sprintf("SpriteMode(%d)", int(m))
+	}
+}
+
+// SpritePart is a piece of a nine-slice or tiled sprite: the region Source of the sprite's Source region,
+// relative to its top-left corner, is drawn scaled to the size of Destination, in sprite space.
+type SpritePart struct {
+	Source      image.Rectangle
+	Destination image.Rectangle
+}
+
+// AppendParts appends the pieces drawn for the sprite to parts: one for simple sprites, and up to nine for
+// nine-slice sprites or as many as needed to fill DrawSize for tiled sprites. Empty pieces are skipped.
+func (s *Sprite) AppendParts(parts []SpritePart) []SpritePart {
+	size := s.SourceRect().Size()
+
+	switch s.Mode {
+	case ModeNineSlice:
+		left, top, right, bottom := s.Slice[0], s.Slice[1], s.Slice[2], s.Slice[3]
+		srcX := sliceEdges(size.X, left, right)
+		srcY := sliceEdges(size.Y, top, bottom)
+
+		// Borders wider than DrawSize are shrunk proportionally.
+		left, right = fitBorders(s.DrawSize.X, left, right)
+		top, bottom = fitBorders(s.DrawSize.Y, top, bottom)
+		dstX := sliceEdges(s.DrawSize.X, left, right)
+		dstY := sliceEdges(s.DrawSize.Y, top, bottom)
+
+		for row := range 3 {
+			for column := range 3 {
+				part := SpritePart{
+					Source:      image.Rect(srcX[column], srcY[row], srcX[column+1], srcY[row+1]),
+					Destination: image.Rect(dstX[column], dstY[row], dstX[column+1], dstY[row+1]),
+				}
+				if !part.Source.Empty() && !part.Destination.Empty() {
+					parts = append(parts, part)
+				}
+			}
+		}
+	case ModeTiled:
+		if size.X <= 0 || size.Y <= 0 {
+			return parts
+		}
+
+		for y := 0; y < s.DrawSize.Y; y += size.Y {
+			for x := 0; x < s.DrawSize.X; x += size.X {
+				width, height := min(size.X, s.DrawSize.X-x), min(size.Y, s.DrawSize.Y-y)
+				parts = append(parts, SpritePart{
+					Source:      image.Rect(0, 0, width, height),
+					Destination: image.Rect(x, y, x+width, y+height),
+				})
+			}
+		}
+	default:
+		if size.X > 0 && size.Y > 0 {
+			parts = append(parts, SpritePart{Source: image.Rectangle{Max: size}, Destination: image.Rectangle{Max: size}})
+		}
+	}
+
+	return parts
+}
+
+// sliceEdges returns the coordinates of the edges of the three slices of length with the given borders.
+func sliceEdges(length, first, last int) [4]int {
+	first, last = max(first, 0), max(last, 0)
+	return [4]int{0, min(first, length), max(length-last, min(first, length)), length}
+}
+
+// fitBorders shrinks the borders proportionally if they do not fit in length.
+func fitBorders(length, first, last int) (int, int) {
+	first, last = max(first, 0), max(last, 0)
+	if first+last <= length || first+last == 0 {
+		return first, last
+	}
+
+	shrunk := first * length / (first + last)
+	return shrunk, length - shrunk
+}
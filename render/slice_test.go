@@ -0,0 +1,73 @@
+package render_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNineSliceParts(t *testing.T) {
+	sprite := &render.Sprite{
+		Image:    ebiten.NewImage(64, 64),
+		Source:   image.Rect(16, 16, 40, 40),
+		Mode:     render.ModeNineSlice,
+		DrawSize: image.Pt(100, 50),
+		Slice:    [4]int{4, 6, 8, 2},
+	}
+
+	parts := sprite.AppendParts(nil)
+	require.Len(t, parts, 9)
+	assert.Equal(t, render.SpritePart{Source: image.Rect(0, 0, 4, 6), Destination: image.Rect(0, 0, 4, 6)}, parts[0], "corners are not scaled")
+	assert.Equal(t, render.SpritePart{Source: image.Rect(4, 6, 16, 22), Destination: image.Rect(4, 6, 92, 48)}, parts[4], "the center is stretched")
+	assert.Equal(t, render.SpritePart{Source: image.Rect(16, 22, 24, 24), Destination: image.Rect(92, 48, 100, 50)}, parts[8])
+
+	sprite.DrawSize = image.Pt(6, 50)
+	parts = sprite.AppendParts(nil)
+	assert.Len(t, parts, 6, "borders wider than the sprite are shrunk, leaving no center column")
+	assert.Equal(t, image.Rect(0, 0, 2, 6), parts[0].Destination)
+	assert.Equal(t, image.Rect(2, 0, 6, 6), parts[1].Destination)
+}
+
+func TestTiledParts(t *testing.T) {
+	sprite := &render.Sprite{
+		Image:    ebiten.NewImage(16, 16),
+		Mode:     render.ModeTiled,
+		DrawSize: image.Pt(40, 20),
+	}
+
+	parts := sprite.AppendParts(nil)
+	require.Len(t, parts, 6)
+	assert.Equal(t, render.SpritePart{Source: image.Rect(0, 0, 16, 16), Destination: image.Rect(16, 0, 32, 16)}, parts[1])
+	assert.Equal(t, render.SpritePart{Source: image.Rect(0, 0, 8, 4), Destination: image.Rect(32, 16, 40, 20)}, parts[5], "the last tiles are cut")
+
+	width, height := sprite.Size()
+	assert.Equal(t, 40, width)
+	assert.Equal(t, 20, height)
+}
+
+func TestRenderSystemNineSlice(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	sm.Add(render.NewRenderSystem(1, 0))
+
+	panel := spawnSprite(em, ebiten.NewImage(12, 12), 10, 20)
+	sprite := ecs.MustGetComponent[render.Sprite](em, panel)
+	sprite.Mode = render.ModeNineSlice
+	sprite.DrawSize = image.Pt(100, 40)
+	sprite.Slice = [4]int{4, 4, 4, 4}
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+
+	ops := rec.Ops()
+	require.Len(t, ops, 9)
+	assert.Equal(t, image.Rect(0, 0, 4, 4), ops[0].Source)
+	assert.Equal(t, [6]float64{1, 0, 10, 0, 1, 20}, ops[0].GeoM)
+	assert.Equal(t, image.Rect(4, 4, 8, 8), ops[4].Source)
+	assert.Equal(t, [6]float64{23, 0, 14, 0, 8, 24}, ops[4].GeoM, "the center is scaled from 4x4 to 92x32")
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+func init() {
+	ecs.Require[Sprite, transform.Transform]()
+}
+
+// Sprite draws a region of an image at the entity's transform.Transform, which is added with the sprite if missing.
+type Sprite struct {
+	Image *ebiten.Image
+	// Source is the region of Image to draw, relative to its top-left corner even if Image is a sub-image,
+	// such as an atlas region. An empty rectangle draws the whole image.
+	Source image.Rectangle
+	// Origin is the pivot of the sprite in pixels, relative to the top-left corner of Source.
+	// The sprite is positioned, rotated and scaled around it.
+	Origin f64.Vec2
+
+	// Mode selects how the sprite fills DrawSize: ModeSimple sprites ignore it and draw Source once.
+	Mode SpriteMode
+	// DrawSize is the size of nine-slice and tiled sprites, in pixels before the transform's scale.
+	DrawSize image.Point
+	// Slice holds the widths of the left, top, right and bottom borders of nine-slice sprites, in pixels of
+	// Source. Corners are drawn unscaled, edges are stretched along their length and the center in both directions.
+	Slice [4]int
+
+	// Layer orders the sprites drawn by the RenderSystem: higher layers are drawn over lower ones.
+	Layer int
+
+	FlipX, FlipY bool
+	Hidden       bool
+	ColorScale   ebiten.ColorScale
+	Filter       ebiten.Filter
+}
+
+func (s *Sprite) Reset() {
+	*s = Sprite{}
+}
+
+// SourceRect returns the region of Image drawn by the sprite, relative to its top-left corner.
+func (s *Sprite) SourceRect() image.Rectangle {
+	if s.Source.Empty() && s.Image != nil {
+		return image.Rectangle{Max: s.Image.Bounds().Size()}
+	}
+
+	return s.Source
+}
+
+// Size returns the size of the drawn region in pixels: DrawSize for nine-slice and tiled sprites,
+// the size of Source otherwise.
+func (s *Sprite) Size() (width, height int) {
+	if s.Mode != ModeSimple {
+		return s.DrawSize.X, s.DrawSize.Y
+	}
+
+	rect := s.SourceRect()
+	return rect.Dx(), rect.Dy()
+}
+
+// SubImage returns the region of Image drawn by the sprite.
+func (s *Sprite) SubImage() *ebiten.Image {
+	if s.Image == nil {
+		return nil
+	}
+
+	if s.Source.Empty() {
+		return s.Image
+	}
+
+	return s.Image.SubImage(s.Source.Add(s.Image.Bounds().Min)).(*ebiten.Image)
+}
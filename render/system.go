@@ -0,0 +1,313 @@
+package render
+
+import (
+	"cmp"
+	"image"
+	"log/slog"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+var _ ecs.CanvasSystem = (*RenderSystem)(nil)
+
+// DefaultCullMargin is the distance, in world units, by which sprites may extend beyond the position of
+// their transform without being culled, unless changed with RenderSystem.SetCullMargin.
+const DefaultCullMargin = 256
+
+// NoCull marks sprites that are drawn even when their position is far outside the view,
+// such as backgrounds larger than the cull margin.
+type NoCull struct{}
+
+// ScreenSpace marks sprites positioned in screen space, such as HUD elements: they are drawn
+// over the world sprites of their layer, without the camera's view and never culled.
+type ScreenSpace struct{}
+
+// RenderSystem draws every entity with a Sprite and a transform.Transform, through each Camera.
+// Sprites outside a camera's view are culled: the candidates are the entities positioned in the view
+// extended by the cull margin, found with ecs.QueryWithinBounds, then the sprites whose bounding box
+// does not overlap the view are skipped.
+//
+// Nine-slice and tiled sprites are drawn in parts, see Sprite.AppendParts. Sprites with a Material are drawn
+// with its shader.
+//
+// Sprites are drawn by ascending Sprite.Layer, then within a layer in ascending entity ID order,
+// or from top to bottom for the layers sorted by Y with SetYSort.
+type RenderSystem struct {
+	*ecs.BaseSystem
+
+	culling    bool
+	cullMargin float64
+	ySort      map[int]bool
+
+	drawWorld, drawScreen bool
+
+	passes    []CameraPass
+	entityIDs []ecs.EntityID
+	commands  []drawCommand
+	parts     []SpritePart
+	// spriteGeoM transforms the sprite being drawn from sprite space to the canvas.
+	spriteGeoM ebiten.GeoM
+	op         ebiten.DrawImageOptions
+	shaderOp   ebiten.DrawRectShaderOptions
+	// warnedMaterial is set once a sprite was skipped for a Material image smaller than the sprite.
+	warnedMaterial bool
+}
+
+// drawCommand is a sprite to draw in the current frame, sorted by layer and y.
+type drawCommand struct {
+	sprite   *Sprite
+	tr       *transform.Transform
+	material *Material
+	// screenSpace sprites are drawn without the view, over the world sprites of their layer.
+	screenSpace bool
+	// y is the sort key within the layer: the position of the transform for Y-sorted layers, zero otherwise.
+	y float64
+}
+
+// NewRenderSystem creates a new RenderSystem with the given ID and priority.
+func NewRenderSystem(id ecs.SystemID, priority int) *RenderSystem {
+	return &RenderSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		culling:    true,
+		cullMargin: DefaultCullMargin,
+		drawWorld:  true,
+		drawScreen: true,
+	}
+}
+
+// SetSpaces selects whether the system draws the sprites in world space and the ScreenSpace sprites.
+// Both are drawn by default; two systems can draw them separately, e.g. to draw the HUD after the
+// lighting.System so that it is not darkened.
+func (s *RenderSystem) SetSpaces(world, screen bool) {
+	s.drawWorld, s.drawScreen = world, screen
+}
+
+// SetCulling enables or disables culling. It is enabled by default.
+func (s *RenderSystem) SetCulling(enabled bool) {
+	s.culling = enabled
+}
+
+// SetCullMargin sets how far sprites may extend beyond their position without being culled.
+// Sprites extending further may disappear before they leave the view unless they have a NoCull component.
+func (s *RenderSystem) SetCullMargin(margin float64) {
+	s.cullMargin = margin
+}
+
+// SetYSort enables or disables sorting the sprites of a layer by the Y position of their transform,
+// so that in top-down games the sprites lower on screen overlap the ones behind them. Set the Origin
+// of such sprites at their feet, which is the point sorted.
+func (s *RenderSystem) SetYSort(layer int, enabled bool) {
+	if enabled {
+		if s.ySort == nil {
+			s.ySort = make(map[int]bool)
+		}
+		s.ySort[layer] = true
+	} else {
+		delete(s.ySort, layer)
+	}
+}
+
+// Update does nothing; sprites are drawn in DrawCanvas.
+func (s *RenderSystem) Update() error {
+	return nil
+}
+
+// DrawCanvas draws all visible sprites, once per camera pass, see AppendCameraPasses. With a single pass
+// on the whole canvas, ScreenSpace sprites are drawn in it, over the world sprites of their layer; with
+// several, such as a split screen, they are drawn over all the passes.
+func (s *RenderSystem) DrawCanvas(canvas ecs.Canvas) {
+	em := s.EntityManager()
+
+	s.passes = AppendCameraPasses(s.passes[:0], em, canvas)
+	single := len(s.passes) == 1 && s.passes[0].Canvas == canvas
+	if s.drawWorld {
+		for _, pass := range s.passes {
+			s.drawPass(em, pass.Canvas, &pass.View, true, single && s.drawScreen)
+		}
+	}
+
+	if s.drawScreen && (!single || !s.drawWorld) {
+		view := ViewportView(nil, nil, canvas.Bounds())
+		s.drawPass(em, canvas, &view, false, true)
+	}
+}
+
+// drawPass draws the visible world sprites through view, and the ScreenSpace sprites, onto canvas.
+func (s *RenderSystem) drawPass(em *ecs.EntityManager, canvas ecs.Canvas, view *View, world, screen bool) {
+	s.entityIDs = s.entityIDs[:0]
+	switch {
+	case !world:
+	case s.culling:
+		minPoint := f64.Vec2{view.Min[0] - s.cullMargin, view.Min[1] - s.cullMargin}
+		maxPoint := f64.Vec2{view.Max[0] + s.cullMargin, view.Max[1] + s.cullMargin}
+		for entityID := range ecs.QueryWithinBounds[transform.Transform](em, minPoint, maxPoint) {
+			if ecs.HasComponent[Sprite](em, entityID) && !ecs.HasComponent[NoCull](em, entityID) && !ecs.HasComponent[ScreenSpace](em, entityID) {
+				s.entityIDs = append(s.entityIDs, entityID)
+			}
+		}
+
+		for entityID := range ecs.NewQuery2[Sprite, transform.Transform](em).With(NoCull{}).Without(ScreenSpace{}).Iter() {
+			s.entityIDs = append(s.entityIDs, entityID)
+		}
+	default:
+		for entityID := range ecs.NewQuery2[Sprite, transform.Transform](em).Without(ScreenSpace{}).Iter() {
+			s.entityIDs = append(s.entityIDs, entityID)
+		}
+	}
+
+	if screen {
+		for entityID := range ecs.Query3[Sprite, transform.Transform, ScreenSpace](em) {
+			s.entityIDs = append(s.entityIDs, entityID)
+		}
+	}
+
+	slices.Sort(s.entityIDs)
+
+	s.commands = s.commands[:0]
+	for _, entityID := range s.entityIDs {
+		sprite := ecs.MustGetComponent[Sprite](em, entityID)
+		if sprite.Hidden || sprite.Image == nil {
+			continue
+		}
+
+		tr := ecs.MustGetComponent[transform.Transform](em, entityID)
+		screenSpace := ecs.HasComponent[ScreenSpace](em, entityID)
+		if s.culling && !screenSpace && !ecs.HasComponent[NoCull](em, entityID) {
+			SpriteGeoM(&s.op.GeoM, sprite, tr)
+
+			width, height := sprite.Size()
+			if !view.Contains(transformedBounds(&s.op.GeoM, 0, 0, float64(width), float64(height))) {
+				continue
+			}
+		}
+
+		command := drawCommand{sprite: sprite, tr: tr, screenSpace: screenSpace}
+		if material, ok := ecs.GetComponent[Material](em, entityID); ok && material.Shader != nil {
+			command.material = material
+		}
+		if s.ySort[sprite.Layer] {
+			command.y = tr.Position[1]
+		}
+		s.commands = append(s.commands, command)
+	}
+
+	// The commands are in ascending ID order, which the stable sort keeps for equal keys.
+	slices.SortStableFunc(s.commands, func(a, b drawCommand) int {
+		return cmp.Or(
+			cmp.Compare(a.sprite.Layer, b.sprite.Layer),
+			compareBool(a.screenSpace, b.screenSpace),
+			cmp.Compare(a.y, b.y),
+		)
+	})
+
+	for _, command := range s.commands {
+		SpriteGeoM(&s.spriteGeoM, command.sprite, command.tr)
+		if !command.screenSpace {
+			s.spriteGeoM.Concat(view.GeoM)
+		}
+
+		if command.sprite.Mode == ModeSimple {
+			s.op.GeoM = s.spriteGeoM
+			s.draw(canvas, command, command.sprite.SubImage())
+			continue
+		}
+
+		source := command.sprite.SourceRect().Add(command.sprite.Image.Bounds().Min)
+		s.parts = command.sprite.AppendParts(s.parts[:0])
+		for _, part := range s.parts {
+			s.op.GeoM.Reset()
+			s.op.GeoM.Scale(
+				float64(part.Destination.Dx())/float64(part.Source.Dx()),
+				float64(part.Destination.Dy())/float64(part.Source.Dy()),
+			)
+			s.op.GeoM.Translate(float64(part.Destination.Min.X), float64(part.Destination.Min.Y))
+			s.op.GeoM.Concat(s.spriteGeoM)
+
+			s.draw(canvas, command, command.sprite.Image.SubImage(part.Source.Add(source.Min)).(*ebiten.Image))
+		}
+	}
+}
+
+// draw draws img, the sprite of command or a part of it, with s.op.GeoM.
+func (s *RenderSystem) draw(canvas ecs.Canvas, command drawCommand, img *ebiten.Image) {
+	if material := command.material; material != nil {
+		s.shaderOp.GeoM = s.op.GeoM
+		s.shaderOp.ColorScale = command.sprite.ColorScale
+		s.shaderOp.Uniforms = material.Uniforms
+		s.shaderOp.Images[0] = img
+
+		size := img.Bounds().Size()
+		for i, source := range material.Images {
+			s.shaderOp.Images[i+1] = nil
+			if source == nil {
+				continue
+			}
+
+			bounds := source.Bounds()
+			if bounds.Dx() < size.X || bounds.Dy() < size.Y {
+				s.warnSmallMaterialImage()
+				return
+			}
+
+			s.shaderOp.Images[i+1] = source.SubImage(image.Rectangle{Min: bounds.Min, Max: bounds.Min.Add(size)}).(*ebiten.Image)
+		}
+
+		canvas.DrawRectShader(size.X, size.Y, material.Shader, &s.shaderOp)
+		return
+	}
+
+	s.op.ColorScale = command.sprite.ColorScale
+	s.op.Filter = command.sprite.Filter
+
+	canvas.DrawImage(img, &s.op)
+}
+
+// warnSmallMaterialImage logs that a sprite was not drawn because an image of its Material is smaller
+// than the sprite, once per system.
+func (s *RenderSystem) warnSmallMaterialImage() {
+	if s.warnedMaterial || s.Game() == nil || s.Game().Logger() == nil {
+		return
+	}
+	s.warnedMaterial = true
+
+	s.Game().Logger().Warn("sprite skipped: a material image is smaller than the sprite",
+		slog.Uint64("system", uint64(s.ID())))
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// SpriteGeoM sets geoM to the transformation that draws sprite at tr:
+// the sprite is flipped, scaled and rotated around its Origin, then moved to the transform position.
+func SpriteGeoM(geoM *ebiten.GeoM, sprite *Sprite, tr *transform.Transform) {
+	geoM.Reset()
+
+	width, height := sprite.Size()
+	if sprite.FlipX {
+		geoM.Scale(-1, 1)
+		geoM.Translate(float64(width), 0)
+	}
+
+	if sprite.FlipY {
+		geoM.Scale(1, -1)
+		geoM.Translate(0, float64(height))
+	}
+
+	geoM.Translate(-sprite.Origin[0], -sprite.Origin[1])
+	geoM.Scale(tr.Scale[0], tr.Scale[1])
+	geoM.Rotate(tr.Rotation)
+	geoM.Translate(tr.Position[0], tr.Position[1])
+}
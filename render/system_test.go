@@ -0,0 +1,172 @@
+package render_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func spawnSprite(em *ecs.EntityManager, img *ebiten.Image, x, y float64) ecs.EntityID {
+	entityID := em.NewEntity()
+	ecs.AddComponent[render.Sprite](em, entityID).Image = img
+	ecs.MustGetComponent[transform.Transform](em, entityID).Position = f64.Vec2{x, y}
+
+	return entityID
+}
+
+func drawnTranslations(t *testing.T, sm *ecs.SystemManager) [][2]float64 {
+	t.Helper()
+
+	rec := render.NewRecorder(320, 240)
+	sm.DrawCanvas(rec)
+
+	var translations [][2]float64
+	for _, op := range rec.Ops() {
+		require.Equal(t, render.OpDrawImage, op.Kind)
+		translations = append(translations, [2]float64{op.GeoM[2], op.GeoM[5]})
+	}
+
+	return translations
+}
+
+func TestRenderSystemCulling(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	system := render.NewRenderSystem(1, 0)
+	sm.Add(system)
+
+	img := ebiten.NewImage(16, 16)
+	spawnSprite(em, img, 10, 10)
+	spawnSprite(em, img, -10, -10)
+	spawnSprite(em, img, 400, 10)
+	background := spawnSprite(em, ebiten.NewImage(1000, 1000), -200, -200)
+
+	assert.Equal(t, [][2]float64{{10, 10}, {-10, -10}, {-200, -200}}, drawnTranslations(t, sm),
+		"partially visible sprites are drawn")
+
+	system.SetCullMargin(16)
+	assert.Equal(t, [][2]float64{{10, 10}, {-10, -10}}, drawnTranslations(t, sm),
+		"sprites positioned farther than the margin are culled")
+
+	ecs.AddComponent[render.NoCull](em, background)
+	assert.Equal(t, [][2]float64{{10, 10}, {-10, -10}, {-200, -200}}, drawnTranslations(t, sm))
+
+	system.SetCulling(false)
+	assert.Len(t, drawnTranslations(t, sm), 4)
+}
+
+func TestRenderSystemCamera(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	sm.Add(render.NewRenderSystem(1, 0))
+
+	img := ebiten.NewImage(16, 16)
+	spawnSprite(em, img, 10, 10)
+	spawnSprite(em, img, 1000, 1000)
+
+	camera := em.NewEntity()
+	assert.Equal(t, 1.0, ecs.AddComponent[render.Camera](em, camera).Zoom)
+	ecs.MustGetComponent[transform.Transform](em, camera).Position = f64.Vec2{1000, 1000}
+
+	assert.Equal(t, [][2]float64{{160, 120}}, drawnTranslations(t, sm), "the camera position is drawn at the center")
+
+	ecs.MustGetComponent[render.Camera](em, camera).Zoom = 2
+	view := render.ActiveView(em, 320, 240)
+	assert.Equal(t, f64.Vec2{920, 940}, view.Min)
+	assert.Equal(t, f64.Vec2{1080, 1060}, view.Max)
+	assert.Equal(t, f64.Vec2{1010, 1005}, view.ToWorld(180, 130))
+	x, y := view.ToCanvas(f64.Vec2{1010, 1005})
+	assert.Equal(t, [2]float64{180, 130}, [2]float64{x, y})
+
+	em.SetActive(camera, false)
+	_, ok := render.ActiveCamera(em)
+	assert.False(t, ok, "inactive cameras are ignored")
+	assert.Equal(t, [][2]float64{{10, 10}}, drawnTranslations(t, sm))
+}
+
+func TestRenderSystemLayers(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	system := render.NewRenderSystem(1, 0)
+	sm.Add(system)
+
+	img := ebiten.NewImage(16, 16)
+	front := spawnSprite(em, img, 0, 0)
+	ecs.MustGetComponent[render.Sprite](em, front).Layer = 1
+	spawnSprite(em, img, 10, 50)
+	spawnSprite(em, img, 20, 30)
+	background := spawnSprite(em, img, 30, 90)
+	ecs.MustGetComponent[render.Sprite](em, background).Layer = -1
+
+	assert.Equal(t, [][2]float64{{30, 90}, {10, 50}, {20, 30}, {0, 0}}, drawnTranslations(t, sm),
+		"sprites are drawn by layer, then by ID")
+
+	system.SetYSort(0, true)
+	assert.Equal(t, [][2]float64{{30, 90}, {20, 30}, {10, 50}, {0, 0}}, drawnTranslations(t, sm),
+		"sprites of Y-sorted layers are drawn from top to bottom")
+
+	system.SetYSort(0, false)
+	assert.Equal(t, [][2]float64{{30, 90}, {10, 50}, {20, 30}, {0, 0}}, drawnTranslations(t, sm))
+}
+
+func TestRenderSystemScreenSpace(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	system := render.NewRenderSystem(1, 0)
+	sm.Add(system)
+
+	camera := em.NewEntity()
+	ecs.AddComponent[render.Camera](em, camera)
+	ecs.MustGetComponent[transform.Transform](em, camera).Position = f64.Vec2{1000, 1000}
+
+	img := ebiten.NewImage(16, 16)
+	hud := spawnSprite(em, img, 10, 10)
+	ecs.AddComponent[render.ScreenSpace](em, hud)
+	spawnSprite(em, img, 1000, 1000)
+
+	assert.Equal(t, [][2]float64{{160, 120}, {10, 10}}, drawnTranslations(t, sm),
+		"screen-space sprites ignore the camera and are drawn over the world sprites of their layer")
+
+	system.SetSpaces(true, false)
+	assert.Equal(t, [][2]float64{{160, 120}}, drawnTranslations(t, sm))
+
+	system.SetSpaces(false, true)
+	assert.Equal(t, [][2]float64{{10, 10}}, drawnTranslations(t, sm))
+}
+
+func TestRenderSystemSplitScreen(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, nil)
+	sm.Add(render.NewRenderSystem(1, 0))
+
+	left := em.NewEntity()
+	ecs.AddComponent[render.Camera](em, left).Viewport = render.Viewport{Max: f64.Vec2{0.5, 1}}
+
+	right := em.NewEntity()
+	ecs.AddComponent[render.Camera](em, right).Viewport = render.Viewport{Min: f64.Vec2{0.5, 0}, Max: f64.Vec2{1, 1}}
+	ecs.MustGetComponent[transform.Transform](em, right).Position = f64.Vec2{1000, 1000}
+
+	img := ebiten.NewImage(16, 16)
+	spawnSprite(em, img, 0, 0)
+	spawnSprite(em, img, 1000, 1000)
+	hud := spawnSprite(em, img, 10, 10)
+	ecs.AddComponent[render.ScreenSpace](em, hud)
+
+	assert.Equal(t, [][2]float64{{80, 120}, {240, 120}, {10, 10}}, drawnTranslations(t, sm),
+		"each camera draws the sprites it sees into its viewport, then the screen-space sprites are drawn once")
+
+	minimap := ebiten.NewImage(64, 64)
+	ecs.MustGetComponent[render.Camera](em, right).Target = minimap
+	passes := render.AppendCameraPasses(nil, em, render.NewRecorder(320, 240))
+	require.Len(t, passes, 2)
+	assert.Equal(t, left, passes[0].Camera)
+	assert.Equal(t, image.Rect(0, 0, 160, 240), passes[0].Canvas.Bounds())
+	assert.Equal(t, image.Rect(32, 0, 64, 64), passes[1].Canvas.Bounds(), "viewports are relative to the camera target")
+}
@@ -0,0 +1,136 @@
+package replication
+
+import (
+	"errors"
+	"fmt"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Client applies the snapshots of a Server to an EntityManager.
+// Every replicated entity gets a local entity with a NetworkID component; their IDs are mapped with
+// LocalID and ServerID, or ResolveNetwork and NetworkID.
+type Client struct {
+	em       *ecs.EntityManager
+	registry *Registry
+	history  history
+	state    worldState
+	tick     uint64
+	local    map[ecs.EntityID]ecs.EntityID
+	server   map[ecs.EntityID]ecs.EntityID
+}
+
+// NewClient creates a Client applying snapshots to em with the component types of registry.
+func NewClient(em *ecs.EntityManager, registry *Registry) *Client {
+	return &Client{
+		em:       em,
+		registry: registry,
+		state:    make(worldState),
+		local:    make(map[ecs.EntityID]ecs.EntityID),
+		server:   make(map[ecs.EntityID]ecs.EntityID),
+	}
+}
+
+// Tick returns the tick of the last applied snapshot.
+func (c *Client) Tick() uint64 {
+	return c.tick
+}
+
+// LocalID returns the local entity of a server entity.
+func (c *Client) LocalID(serverID ecs.EntityID) (ecs.EntityID, bool) {
+	localID, ok := c.local[serverID]
+	return localID, ok
+}
+
+// ServerID returns the server entity of a local entity.
+func (c *Client) ServerID(localID ecs.EntityID) (ecs.EntityID, bool) {
+	serverID, ok := c.server[localID]
+	return serverID, ok
+}
+
+// Apply decodes a snapshot and updates the local entities, returning the tick to acknowledge to the server.
+// Snapshots older than the last applied one are ignored and return the current tick.
+func (c *Client) Apply(data []byte) (uint64, error) {
+	if len(data) > 0 && data[0] == snapshotCompressed {
+		if c.registry.compressor == nil {
+			return 0, fmt.Errorf("replication.Client.Apply compressed snapshot without compressor: %w", ErrMalformedSnapshot)
+		}
+
+		decompressed, err := c.registry.compressor.Decompress(data[1:])
+		if err != nil {
+			return 0, fmt.Errorf("replication.Client.Apply Decompress error: %w", errors.Join(ErrMalformedSnapshot, err))
+		}
+		data = decompressed
+	}
+
+	s, err := decodeSnapshot(data, c.registry.Len())
+	if err != nil {
+		return 0, fmt.Errorf("replication.Client.Apply decodeSnapshot error: %w", err)
+	}
+
+	if s.tick <= c.tick {
+		return c.tick, nil
+	}
+
+	var baseline worldState
+	if s.baseline != 0 {
+		var ok bool
+		if baseline, ok = c.history.get(s.baseline); !ok {
+			return 0, fmt.Errorf("replication.Client.Apply tick %d baseline %d: %w", s.tick, s.baseline, ErrUnknownBaseline)
+		}
+	}
+
+	next := s.apply(baseline)
+	if err := c.sync(next); err != nil {
+		return 0, fmt.Errorf("replication.Client.Apply tick %d sync error: %w", s.tick, err)
+	}
+
+	c.history.put(s.tick, next)
+	c.state = next
+	c.tick = s.tick
+
+	return s.tick, nil
+}
+
+// sync updates the local entities from the current state to next.
+func (c *Client) sync(next worldState) error {
+	for serverID := range c.state {
+		if _, ok := next[serverID]; ok {
+			continue
+		}
+
+		if localID, ok := c.local[serverID]; ok {
+			c.em.Remove(localID)
+			delete(c.local, serverID)
+			delete(c.server, localID)
+		}
+	}
+
+	for serverID, components := range next {
+		localID, ok := c.local[serverID]
+		if !ok {
+			localID = c.em.NewEntity()
+			ecs.AddComponent[Replicated](c.em, localID)
+			*ecs.AddComponent[NetworkID](c.em, localID) = NetworkID(serverID)
+			c.local[serverID] = localID
+			c.server[localID] = serverID
+		}
+
+		old := c.state[serverID]
+		for typeIndex, componentType := range c.registry.types {
+			data, set := components[typeIndex]
+			previous, had := old[typeIndex]
+
+			switch {
+			case set && (!had || string(previous) != string(data)):
+				if err := componentType.decode(c.em, localID, data); err != nil {
+					return err
+				}
+			case !set && had:
+				componentType.remove(c.em, localID)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package replication
+
+import ecs "github.com/samix73/ebiten-ecs"
+
+// NetworkID identifies a replicated entity on the server and all its clients, which allocate different
+// EntityIDs: it is the entity's EntityID on the server. Clients add it as a component to the local
+// entities of replicated entities. Gameplay code referring to remote entities, e.g. in components or
+// input messages, stores NetworkIDs and resolves them with a Resolver.
+type NetworkID uint64
+
+// Resolver translates between NetworkIDs and the EntityIDs of one side. Server and Client implement it,
+// so that gameplay code shared by both sides resolves entities the same way.
+type Resolver interface {
+	// ResolveNetwork returns the entity of netID, or false if it is not replicated here.
+	ResolveNetwork(netID NetworkID) (ecs.EntityID, bool)
+	// NetworkID returns the NetworkID of a replicated entity, or false if it is not replicated.
+	NetworkID(entityID ecs.EntityID) (NetworkID, bool)
+}
+
+var (
+	_ Resolver = (*Server)(nil)
+	_ Resolver = (*Client)(nil)
+)
+
+// ResolveNetwork returns the entity of netID, which is the entity with the same ID if it is replicated.
+func (s *Server) ResolveNetwork(netID NetworkID) (ecs.EntityID, bool) {
+	entityID := ecs.EntityID(netID)
+	return entityID, ecs.HasComponent[Replicated](s.em, entityID)
+}
+
+// NetworkID returns the NetworkID of a replicated entity, which is its own ID.
+func (s *Server) NetworkID(entityID ecs.EntityID) (NetworkID, bool) {
+	return NetworkID(entityID), ecs.HasComponent[Replicated](s.em, entityID)
+}
+
+// ResolveNetwork returns the local entity of netID, see LocalID.
+func (c *Client) ResolveNetwork(netID NetworkID) (ecs.EntityID, bool) {
+	return c.LocalID(ecs.EntityID(netID))
+}
+
+// NetworkID returns the NetworkID of a local entity, see ServerID.
+func (c *Client) NetworkID(entityID ecs.EntityID) (NetworkID, bool) {
+	serverID, ok := c.ServerID(entityID)
+	return NetworkID(serverID), ok
+}
@@ -0,0 +1,118 @@
+// Package replication synchronizes entities from a server EntityManager to clients with snapshots.
+//
+// The server marks entities with the Replicated component and encodes, every tick and for every client,
+// a snapshot of their registered components. Snapshots are delta-compressed against the last snapshot
+// the client acknowledged, and limited to the entities in the client's area of interest.
+// Clients apply snapshots to their own EntityManager, creating local entities for the server's.
+// Transport is left to the game: snapshots and acknowledgements are plain values and byte slices.
+//
+//	// Both sides.
+//	registry := replication.NewRegistry()
+//	replication.Register[transform.Transform](registry, "transform")
+//	replication.Register[Health](registry, "health")
+//
+//	// Server, every tick and for every client.
+//	data, err := server.Snapshot(clientID, tick)
+//	send(clientID, data)
+//	// When the client acknowledges a tick:
+//	server.Ack(clientID, ackedTick)
+//
+//	// Client, for every received snapshot.
+//	tick, err := client.Apply(data)
+//	sendAck(tick)
+package replication
+
+import (
+	"fmt"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/codec"
+)
+
+// Replicated marks an entity to be replicated to clients.
+type Replicated struct{}
+
+func (r *Replicated) Reset() {}
+
+// Codec encodes replicated components. Marshal must be deterministic so unchanged components
+// encode to the same bytes and are left out of delta snapshots. codec.Protobuf encodes components
+// much smaller and faster than JSON.
+type Codec = codec.Codec
+
+// JSON is the default Codec, encoding the exported fields of components with encoding/json.
+var JSON Codec = codec.JSON
+
+// componentType is a replicated component type.
+type componentType struct {
+	name   string
+	encode func(em *ecs.EntityManager, entityID ecs.EntityID) ([]byte, bool, error)
+	decode func(em *ecs.EntityManager, entityID ecs.EntityID, data []byte) error
+	remove func(em *ecs.EntityManager, entityID ecs.EntityID)
+}
+
+// Registry lists the replicated component types. The server and its clients must register
+// the same types in the same order, since snapshots identify types by their registration index.
+type Registry struct {
+	codec      Codec
+	compressor codec.Compressor
+	types      []*componentType
+	names      map[string]int
+}
+
+// NewRegistry creates an empty Registry using the JSON codec.
+func NewRegistry() *Registry {
+	return NewRegistryWithCodec(JSON)
+}
+
+// NewRegistryWithCodec creates an empty Registry using codec.
+func NewRegistryWithCodec(codec Codec) *Registry {
+	return &Registry{codec: codec, names: make(map[string]int)}
+}
+
+// SetCompressor makes snapshots compressed with compressor, e.g. codec.NewDeflate(flate.BestSpeed).
+// The server and its clients must use the same compressor. A nil compressor, the default, disables compression.
+func (r *Registry) SetCompressor(compressor codec.Compressor) {
+	r.compressor = compressor
+}
+
+// Register adds component type C to the registry under name, used in error messages.
+// It panics if the name is already registered.
+func Register[C any](r *Registry, name string) {
+	if _, exists := r.names[name]; exists {
+		panic(fmt.Sprintf("replication.Register: component %q already registered", name))
+	}
+
+	r.names[name] = len(r.types)
+	r.types = append(r.types, &componentType{
+		name: name,
+		encode: func(em *ecs.EntityManager, entityID ecs.EntityID) ([]byte, bool, error) {
+			component, ok := ecs.GetComponent[C](em, entityID)
+			if !ok {
+				return nil, false, nil
+			}
+
+			data, err := r.codec.Marshal(component)
+			if err != nil {
+				return nil, false, fmt.Errorf("replication.Registry.encode %s codec.Marshal error: %w", name, err)
+			}
+
+			return data, true, nil
+		},
+		decode: func(em *ecs.EntityManager, entityID ecs.EntityID, data []byte) error {
+			component := ecs.AddComponent[C](em, entityID)
+			if err := r.codec.Unmarshal(data, component); err != nil {
+				return fmt.Errorf("replication.Registry.decode %s codec.Unmarshal error: %w", name, err)
+			}
+
+			return nil
+		},
+		remove: func(em *ecs.EntityManager, entityID ecs.EntityID) {
+			ecs.RemoveComponent[C](em, entityID)
+		},
+	})
+}
+
+// Len returns the number of registered component types.
+func (r *Registry) Len() int {
+	return len(r.types)
+}
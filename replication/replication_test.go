@@ -0,0 +1,255 @@
+package replication_test
+
+import (
+	"compress/flate"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/codec"
+	"github.com/samix73/ebiten-ecs/replication"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+type Health struct {
+	Current int
+	Max     int
+}
+
+func (h *Health) Reset() { *h = Health{} }
+
+func newRegistry() *replication.Registry {
+	registry := replication.NewRegistry()
+	replication.Register[transform.Transform](registry, "transform")
+	replication.Register[Health](registry, "health")
+
+	return registry
+}
+
+func spawn(em *ecs.EntityManager, x, y float64) ecs.EntityID {
+	entityID := em.NewEntity()
+	ecs.AddComponent[replication.Replicated](em, entityID)
+	ecs.AddComponent[transform.Transform](em, entityID).Position = f64.Vec2{x, y}
+
+	return entityID
+}
+
+func TestReplication(t *testing.T) {
+	serverEM := ecs.NewEntityManager()
+	server := replication.NewServer(serverEM, newRegistry())
+	server.AddClient(1)
+
+	clientEM := ecs.NewEntityManager()
+	client := replication.NewClient(clientEM, newRegistry())
+
+	player := spawn(serverEM, 10, 20)
+	*ecs.AddComponent[Health](serverEM, player) = Health{Current: 3, Max: 5}
+	spawn(serverEM, 30, 40)
+	serverEM.NewEntity() // Not replicated.
+
+	full, err := server.Snapshot(1, 1)
+	require.NoError(t, err)
+
+	tick, err := client.Apply(full)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), tick)
+	assert.Equal(t, 2, ecs.Count(ecs.Query[replication.Replicated](clientEM)))
+
+	local, ok := client.LocalID(player)
+	require.True(t, ok)
+	serverID, ok := client.ServerID(local)
+	require.True(t, ok)
+	assert.Equal(t, player, serverID)
+
+	netID, ok := server.NetworkID(player)
+	require.True(t, ok)
+	assert.Equal(t, netID, *ecs.MustGetComponent[replication.NetworkID](clientEM, local))
+	resolved, ok := client.ResolveNetwork(netID)
+	require.True(t, ok)
+	assert.Equal(t, local, resolved)
+	resolved, ok = server.ResolveNetwork(netID)
+	require.True(t, ok)
+	assert.Equal(t, player, resolved)
+	assert.Equal(t, Health{Current: 3, Max: 5}, *ecs.MustGetComponent[Health](clientEM, local))
+	assert.Equal(t, f64.Vec2{10, 20}, ecs.MustGetComponent[transform.Transform](clientEM, local).Position)
+
+	require.NoError(t, server.Ack(1, tick))
+
+	// Only the changed component is sent against the acknowledged baseline.
+	ecs.MustGetComponent[Health](serverEM, player).Current = 2
+	delta, err := server.Snapshot(1, 2)
+	require.NoError(t, err)
+	assert.Less(t, len(delta), len(full))
+
+	tick, err = client.Apply(delta)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), tick)
+	assert.Equal(t, 2, ecs.MustGetComponent[Health](clientEM, local).Current)
+
+	// Unchanged state produces an empty delta.
+	require.NoError(t, server.Ack(1, tick))
+	empty, err := server.Snapshot(1, 3)
+	require.NoError(t, err)
+	tick, err = client.Apply(empty)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), tick)
+
+	// Removed components and entities are removed on the client.
+	ecs.RemoveComponent[Health](serverEM, player)
+	serverEM.Remove(player)
+	_, err = client.Apply(mustSnapshot(t, server, 1, 4))
+	require.NoError(t, err)
+	assert.False(t, clientEM.Exists(local))
+	_, ok = client.LocalID(player)
+	assert.False(t, ok)
+	assert.Equal(t, 1, ecs.Count(ecs.Query[replication.Replicated](clientEM)))
+}
+
+func mustSnapshot(t *testing.T, server *replication.Server, clientID replication.ClientID, tick uint64) []byte {
+	t.Helper()
+
+	data, err := server.Snapshot(clientID, tick)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestCompressedSnapshots(t *testing.T) {
+	serverRegistry, clientRegistry := newRegistry(), newRegistry()
+	serverRegistry.SetCompressor(codec.NewDeflate(flate.BestSpeed))
+	clientRegistry.SetCompressor(codec.NewDeflate(flate.BestSpeed))
+
+	serverEM := ecs.NewEntityManager()
+	server := replication.NewServer(serverEM, serverRegistry)
+	server.AddClient(1)
+	server.SetFullSnapshotInterval(3)
+
+	clientEM := ecs.NewEntityManager()
+	client := replication.NewClient(clientEM, clientRegistry)
+
+	for i := range 50 {
+		spawn(serverEM, float64(i), 0)
+	}
+
+	var sizes []int
+	for tick := uint64(1); tick <= 4; tick++ {
+		data := mustSnapshot(t, server, 1, tick)
+		sizes = append(sizes, len(data))
+
+		applied, err := client.Apply(data)
+		require.NoError(t, err)
+		require.NoError(t, server.Ack(1, applied))
+	}
+	assert.Equal(t, 50, ecs.Count(ecs.Query[replication.Replicated](clientEM)))
+
+	// Ticks 2 and 3 are deltas against the acknowledged baseline, tick 4 is complete again.
+	assert.Less(t, sizes[1], sizes[0])
+	assert.Equal(t, sizes[1], sizes[2])
+	assert.Greater(t, sizes[3], sizes[2])
+
+	uncompressed := replication.NewServer(serverEM, newRegistry())
+	uncompressed.AddClient(1)
+	assert.Less(t, sizes[0], len(mustSnapshot(t, uncompressed, 1, 1)))
+
+	_, err := replication.NewClient(ecs.NewEntityManager(), newRegistry()).Apply(mustSnapshot(t, server, 1, 5))
+	require.ErrorIs(t, err, replication.ErrMalformedSnapshot)
+}
+
+func TestLostSnapshots(t *testing.T) {
+	serverEM := ecs.NewEntityManager()
+	server := replication.NewServer(serverEM, newRegistry())
+	server.AddClient(1)
+
+	clientEM := ecs.NewEntityManager()
+	client := replication.NewClient(clientEM, newRegistry())
+
+	entityID := spawn(serverEM, 0, 0)
+	tick, err := client.Apply(mustSnapshot(t, server, 1, 1))
+	require.NoError(t, err)
+	require.NoError(t, server.Ack(1, tick))
+
+	// Snapshots 2 and 3 are lost; 4 is still a delta against the acknowledged tick 1.
+	for tick := uint64(2); tick <= 4; tick++ {
+		ecs.MustGetComponent[transform.Transform](serverEM, entityID).Translate(1, 0)
+		data := mustSnapshot(t, server, 1, tick)
+		if tick < 4 {
+			continue
+		}
+
+		_, err := client.Apply(data)
+		require.NoError(t, err)
+	}
+
+	local, _ := client.LocalID(entityID)
+	assert.Equal(t, f64.Vec2{3, 0}, ecs.MustGetComponent[transform.Transform](clientEM, local).Position)
+
+	// Late snapshots are ignored.
+	tick, err = client.Apply(mustSnapshot(t, server, 1, 5))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), tick)
+	stale, err := client.Apply(mustSnapshot(t, server, 1, 3))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), stale)
+
+	// A delta against a baseline the client never applied is rejected.
+	other := replication.NewClient(ecs.NewEntityManager(), newRegistry())
+	_, err = other.Apply(mustSnapshot(t, server, 1, 6))
+	require.ErrorIs(t, err, replication.ErrUnknownBaseline)
+}
+
+func TestInterest(t *testing.T) {
+	serverEM := ecs.NewEntityManager()
+	server := replication.NewServer(serverEM, newRegistry())
+	server.AddClient(1)
+	server.Margin = 10
+	require.NoError(t, server.SetInterest(1, replication.Area{Max: f64.Vec2{100, 100}}))
+	require.ErrorIs(t, server.SetInterest(2, replication.Area{}), replication.ErrUnknownClient)
+
+	clientEM := ecs.NewEntityManager()
+	client := replication.NewClient(clientEM, newRegistry())
+
+	near := spawn(serverEM, 105, 50)
+	far := spawn(serverEM, 500, 50)
+	global := serverEM.NewEntity()
+	ecs.AddComponent[replication.Replicated](serverEM, global)
+
+	tick, err := client.Apply(mustSnapshot(t, server, 1, 1))
+	require.NoError(t, err)
+	require.NoError(t, server.Ack(1, tick))
+
+	for _, entityID := range []ecs.EntityID{near, global} {
+		_, ok := client.LocalID(entityID)
+		assert.True(t, ok)
+	}
+	_, ok := client.LocalID(far)
+	assert.False(t, ok)
+
+	// Entities leaving the area are removed from the client, entities entering it are created.
+	ecs.MustGetComponent[transform.Transform](serverEM, near).Position = f64.Vec2{200, 50}
+	ecs.MustGetComponent[transform.Transform](serverEM, far).Position = f64.Vec2{50, 50}
+	_, err = client.Apply(mustSnapshot(t, server, 1, 2))
+	require.NoError(t, err)
+
+	_, ok = client.LocalID(near)
+	assert.False(t, ok)
+	local, ok := client.LocalID(far)
+	require.True(t, ok)
+	assert.Equal(t, f64.Vec2{50, 50}, ecs.MustGetComponent[transform.Transform](clientEM, local).Position)
+}
+
+func TestMalformedSnapshot(t *testing.T) {
+	server := replication.NewServer(ecs.NewEntityManager(), newRegistry())
+	server.AddClient(1)
+	_, err := server.Snapshot(2, 1)
+	require.ErrorIs(t, err, replication.ErrUnknownClient)
+
+	data := mustSnapshot(t, server, 1, 1)
+	client := replication.NewClient(ecs.NewEntityManager(), newRegistry())
+
+	_, err = client.Apply(nil)
+	require.ErrorIs(t, err, replication.ErrMalformedSnapshot)
+	_, err = client.Apply(data[:len(data)-1])
+	require.ErrorIs(t, err, replication.ErrMalformedSnapshot)
+}
@@ -0,0 +1,226 @@
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// HistorySize is the number of snapshots kept per client as delta baselines.
+// Acknowledgements older than that are ignored and the next snapshot is complete.
+const HistorySize = 32
+
+// ErrUnknownClient is returned for a client that was not added to the Server.
+var ErrUnknownClient = errors.New("unknown client")
+
+// ClientID identifies a client of the Server. The game chooses the IDs, e.g. from its connections.
+type ClientID uint64
+
+// Area is an axis-aligned rectangle in world space.
+type Area struct {
+	Min, Max f64.Vec2
+}
+
+// Empty reports whether the area has no extent.
+func (a Area) Empty() bool {
+	return a.Max[0] <= a.Min[0] || a.Max[1] <= a.Min[1]
+}
+
+// Contains reports whether p is inside the area.
+func (a Area) Contains(p f64.Vec2) bool {
+	return p[0] >= a.Min[0] && p[0] < a.Max[0] && p[1] >= a.Min[1] && p[1] < a.Max[1]
+}
+
+// Grow returns the area extended by margin on every side.
+func (a Area) Grow(margin float64) Area {
+	return Area{
+		Min: f64.Vec2{a.Min[0] - margin, a.Min[1] - margin},
+		Max: f64.Vec2{a.Max[0] + margin, a.Max[1] + margin},
+	}
+}
+
+// history is a ring of the last world states sent to or received by a peer.
+type history struct {
+	ticks  [HistorySize]uint64
+	states [HistorySize]worldState
+}
+
+func (h *history) put(tick uint64, state worldState) {
+	i := tick % HistorySize
+	h.ticks[i] = tick
+	h.states[i] = state
+}
+
+func (h *history) get(tick uint64) (worldState, bool) {
+	i := tick % HistorySize
+	if tick == 0 || h.ticks[i] != tick {
+		return nil, false
+	}
+
+	return h.states[i], true
+}
+
+type serverClient struct {
+	interest Area
+	acked    uint64
+	lastFull uint64
+	history  history
+}
+
+// Server encodes snapshots of the replicated entities of an EntityManager.
+type Server struct {
+	em       *ecs.EntityManager
+	registry *Registry
+	clients  map[ClientID]*serverClient
+
+	fullInterval uint64
+
+	// Margin extends every interest area, so entities are created on clients shortly before they come into view.
+	Margin float64
+}
+
+// NewServer creates a Server replicating the entities of em with the component types of registry.
+func NewServer(em *ecs.EntityManager, registry *Registry) *Server {
+	return &Server{
+		em:       em,
+		registry: registry,
+		clients:  make(map[ClientID]*serverClient),
+	}
+}
+
+// AddClient starts replicating to a client. Its first snapshot is complete.
+func (s *Server) AddClient(clientID ClientID) {
+	s.clients[clientID] = &serverClient{}
+}
+
+// RemoveClient stops replicating to a client.
+func (s *Server) RemoveClient(clientID ClientID) {
+	delete(s.clients, clientID)
+}
+
+// Clients returns the IDs of the clients in ascending order.
+func (s *Server) Clients() []ClientID {
+	ids := make([]ClientID, 0, len(s.clients))
+	for clientID := range s.clients {
+		ids = append(ids, clientID)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// SetInterest limits the entities replicated to a client to those whose transform.Transform is inside area,
+// typically the client's camera view. Entities without a transform are always replicated.
+// An empty area, the default, replicates every entity.
+func (s *Server) SetInterest(clientID ClientID, area Area) error {
+	client, ok := s.clients[clientID]
+	if !ok {
+		return fmt.Errorf("replication.Server.SetInterest %d: %w", clientID, ErrUnknownClient)
+	}
+
+	client.interest = area
+
+	return nil
+}
+
+// SetFullSnapshotInterval makes every client receive a complete snapshot at least every ticks ticks, even
+// when it keeps acknowledging, so that a client recovers from corrupted state and a newly sent baseline
+// bounds how far back deltas reach. Zero, the default, sends complete snapshots only when there is no
+// acknowledged baseline.
+func (s *Server) SetFullSnapshotInterval(ticks uint64) {
+	s.fullInterval = ticks
+}
+
+// Ack records that a client applied the snapshot of tick, making it the baseline of the next snapshots.
+// Acknowledgements of ticks older than the current baseline or no longer in the history are ignored.
+func (s *Server) Ack(clientID ClientID, tick uint64) error {
+	client, ok := s.clients[clientID]
+	if !ok {
+		return fmt.Errorf("replication.Server.Ack %d: %w", clientID, ErrUnknownClient)
+	}
+
+	if tick <= client.acked {
+		return nil
+	}
+	if _, ok := client.history.get(tick); ok {
+		client.acked = tick
+	}
+
+	return nil
+}
+
+// Snapshot encodes the replicated entities in the interest area of a client at tick, which must increase
+// with every call. The snapshot is a delta against the last acknowledged one, or complete if there is none
+// or a complete one is due, see SetFullSnapshotInterval. It is compressed if the registry has a compressor.
+func (s *Server) Snapshot(clientID ClientID, tick uint64) ([]byte, error) {
+	client, ok := s.clients[clientID]
+	if !ok {
+		return nil, fmt.Errorf("replication.Server.Snapshot %d: %w", clientID, ErrUnknownClient)
+	}
+	if tick == 0 {
+		return nil, fmt.Errorf("replication.Server.Snapshot %d: tick must be positive", clientID)
+	}
+
+	current, err := s.capture(client.interest)
+	if err != nil {
+		return nil, fmt.Errorf("replication.Server.Snapshot %d capture error: %w", clientID, err)
+	}
+
+	baselineTick := client.acked
+	baseline, ok := client.history.get(baselineTick)
+	if !ok || s.fullInterval > 0 && tick-client.lastFull >= s.fullInterval {
+		baselineTick, baseline = 0, nil
+	}
+
+	if baselineTick == 0 {
+		client.lastFull = tick
+	}
+	client.history.put(tick, current)
+
+	data := diff(tick, baselineTick, baseline, current).encode()
+	if s.registry.compressor == nil {
+		return data, nil
+	}
+
+	compressed, err := s.registry.compressor.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("replication.Server.Snapshot %d Compress error: %w", clientID, err)
+	}
+
+	return append([]byte{snapshotCompressed}, compressed...), nil
+}
+
+// capture encodes the replicated entities in area.
+func (s *Server) capture(area Area) (worldState, error) {
+	if !area.Empty() {
+		area = area.Grow(s.Margin)
+	}
+
+	state := make(worldState)
+	for entityID := range ecs.Query[Replicated](s.em) {
+		if !area.Empty() {
+			if t, ok := ecs.GetComponent[transform.Transform](s.em, entityID); ok && !area.Contains(t.Position) {
+				continue
+			}
+		}
+
+		components := make(entityState)
+		for typeIndex, componentType := range s.registry.types {
+			data, ok, err := componentType.encode(s.em, entityID)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				components[typeIndex] = data
+			}
+		}
+
+		state[entityID] = components
+	}
+
+	return state, nil
+}
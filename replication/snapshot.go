@@ -0,0 +1,236 @@
+package replication
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// snapshotVersion is the first byte of encoded snapshots.
+const snapshotVersion = 1
+
+// snapshotCompressed is the first byte of compressed snapshots, followed by the compressed encoding.
+const snapshotCompressed = 2
+
+// ErrMalformedSnapshot is returned when decoding a truncated or corrupted snapshot.
+var ErrMalformedSnapshot = errors.New("malformed snapshot")
+
+// ErrUnknownBaseline is returned by Client.Apply when a delta snapshot refers to a baseline
+// the client no longer has; the server sends a full snapshot once it stops receiving acknowledgements.
+var ErrUnknownBaseline = errors.New("unknown snapshot baseline")
+
+// worldState is the encoded components of the replicated entities, by server entity and type index.
+type worldState map[ecs.EntityID]entityState
+
+type entityState map[int][]byte
+
+func (w worldState) clone() worldState {
+	clone := make(worldState, len(w))
+	for entityID, components := range w {
+		clone[entityID] = maps.Clone(components)
+	}
+
+	return clone
+}
+
+// entityDelta is the change of one entity between two world states.
+type entityDelta struct {
+	id      ecs.EntityID
+	set     map[int][]byte
+	removed []int
+}
+
+// snapshot is the change from a baseline world state, identified by its tick, to a new one.
+// A baseline of zero means the snapshot is complete.
+type snapshot struct {
+	tick     uint64
+	baseline uint64
+	removed  []ecs.EntityID
+	entities []entityDelta
+}
+
+// diff returns the snapshot turning baseline into current.
+func diff(tick, baselineTick uint64, baseline, current worldState) *snapshot {
+	s := &snapshot{tick: tick, baseline: baselineTick}
+
+	for entityID := range baseline {
+		if _, ok := current[entityID]; !ok {
+			s.removed = append(s.removed, entityID)
+		}
+	}
+
+	for entityID, components := range current {
+		old, existed := baseline[entityID]
+		delta := entityDelta{id: entityID}
+
+		for typeIndex, data := range components {
+			if previous, ok := old[typeIndex]; !ok || string(previous) != string(data) {
+				if delta.set == nil {
+					delta.set = make(map[int][]byte)
+				}
+				delta.set[typeIndex] = data
+			}
+		}
+
+		for typeIndex := range old {
+			if _, ok := components[typeIndex]; !ok {
+				delta.removed = append(delta.removed, typeIndex)
+			}
+		}
+
+		// New entities are sent even without components, so the client creates them.
+		if !existed || len(delta.set) > 0 || len(delta.removed) > 0 {
+			s.entities = append(s.entities, delta)
+		}
+	}
+
+	return s
+}
+
+// apply returns the world state obtained by applying the snapshot to baseline, which is not modified.
+func (s *snapshot) apply(baseline worldState) worldState {
+	state := baseline.clone()
+
+	for _, entityID := range s.removed {
+		delete(state, entityID)
+	}
+
+	for _, delta := range s.entities {
+		components, ok := state[delta.id]
+		if !ok {
+			components = make(entityState, len(delta.set))
+			state[delta.id] = components
+		}
+
+		for _, typeIndex := range delta.removed {
+			delete(components, typeIndex)
+		}
+
+		maps.Copy(components, delta.set)
+	}
+
+	return state
+}
+
+// encode serializes the snapshot with unsigned varints.
+func (s *snapshot) encode() []byte {
+	buf := []byte{snapshotVersion}
+	buf = binary.AppendUvarint(buf, s.tick)
+	buf = binary.AppendUvarint(buf, s.baseline)
+
+	buf = binary.AppendUvarint(buf, uint64(len(s.removed)))
+	for _, entityID := range s.removed {
+		buf = binary.AppendUvarint(buf, uint64(entityID))
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(s.entities)))
+	for _, delta := range s.entities {
+		buf = binary.AppendUvarint(buf, uint64(delta.id))
+
+		buf = binary.AppendUvarint(buf, uint64(len(delta.set)))
+		for typeIndex, data := range delta.set {
+			buf = binary.AppendUvarint(buf, uint64(typeIndex))
+			buf = binary.AppendUvarint(buf, uint64(len(data)))
+			buf = append(buf, data...)
+		}
+
+		buf = binary.AppendUvarint(buf, uint64(len(delta.removed)))
+		for _, typeIndex := range delta.removed {
+			buf = binary.AppendUvarint(buf, uint64(typeIndex))
+		}
+	}
+
+	return buf
+}
+
+// decoder reads unsigned varints, remembering the first error.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = ErrMalformedSnapshot
+		return 0
+	}
+	d.buf = d.buf[n:]
+
+	return v
+}
+
+// count reads a length that must fit in the rest of the buffer, one byte per item at least.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.buf)) {
+		d.err = ErrMalformedSnapshot
+		return 0
+	}
+
+	return int(n)
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	data := d.buf[:n:n]
+	d.buf = d.buf[n:]
+
+	return data
+}
+
+func decodeSnapshot(data []byte, types int) (*snapshot, error) {
+	if len(data) == 0 || data[0] != snapshotVersion {
+		return nil, fmt.Errorf("replication.decodeSnapshot version: %w", ErrMalformedSnapshot)
+	}
+
+	d := &decoder{buf: data[1:]}
+	s := &snapshot{tick: d.uvarint(), baseline: d.uvarint()}
+
+	typeIndex := func() int {
+		index := d.uvarint()
+		if index >= uint64(types) {
+			d.err = ErrMalformedSnapshot
+		}
+
+		return int(index)
+	}
+
+	for range d.count() {
+		s.removed = append(s.removed, ecs.EntityID(d.uvarint()))
+	}
+
+	for range d.count() {
+		delta := entityDelta{id: ecs.EntityID(d.uvarint())}
+
+		if n := d.count(); n > 0 {
+			delta.set = make(map[int][]byte, n)
+			for range n {
+				index := typeIndex()
+				delta.set[index] = d.bytes(d.count())
+			}
+		}
+
+		for range d.count() {
+			delta.removed = append(delta.removed, typeIndex())
+		}
+
+		s.entities = append(s.entities, delta)
+	}
+
+	if d.err != nil {
+		return nil, fmt.Errorf("replication.decodeSnapshot: %w", d.err)
+	}
+
+	return s, nil
+}
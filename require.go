@@ -0,0 +1,138 @@
+package ecs
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// requirement is a component type required by another, with the function adding it to an entity.
+type requirement struct {
+	componentType reflect.Type
+	add           func(em *EntityManager, entityID EntityID)
+}
+
+var (
+	requirementsMu sync.RWMutex
+	requirements   = make(map[reflect.Type][]requirement)
+	// hasRequirements skips the registry lookup in AddComponent until a requirement is declared.
+	hasRequirements atomic.Bool
+)
+
+// Require declares that component C requires component R: adding C to an entity also adds R if the entity
+// does not have it yet, initialized like by AddComponent, and so on for the requirements of R. In strict mode,
+// see EntityManager.SetStrict, adding C to an entity without R panics instead. CheckRequirements reports the
+// entities that lost a required component afterwards.
+// Requirements are global, and are usually declared next to the component types, e.g. in an init function:
+//
+//	func init() {
+//		ecs.Require[Sprite, Transform]()
+//	}
+func Require[C, R any]() {
+	componentType, required := reflect.TypeFor[C](), reflect.TypeFor[R]()
+	if componentType == required {
+		return
+	}
+
+	requirementsMu.Lock()
+	defer requirementsMu.Unlock()
+
+	if slices.ContainsFunc(requirements[componentType], func(r requirement) bool { return r.componentType == required }) {
+		return
+	}
+
+	requirements[componentType] = append(requirements[componentType], requirement{
+		componentType: required,
+		add: func(em *EntityManager, entityID EntityID) {
+			AddComponent[R](em, entityID)
+		},
+	})
+	hasRequirements.Store(true)
+}
+
+// RequiredComponents returns the component types directly required by componentType, in declaration order.
+func RequiredComponents(componentType reflect.Type) []reflect.Type {
+	requirementsMu.RLock()
+	defer requirementsMu.RUnlock()
+
+	types := make([]reflect.Type, 0, len(requirements[componentType]))
+	for _, r := range requirements[componentType] {
+		types = append(types, r.componentType)
+	}
+
+	return types
+}
+
+// requirementsOf returns the requirements of componentType.
+func requirementsOf(componentType reflect.Type) []requirement {
+	if !hasRequirements.Load() {
+		return nil
+	}
+
+	requirementsMu.RLock()
+	defer requirementsMu.RUnlock()
+
+	return requirements[componentType]
+}
+
+// checkRequired panics in strict mode if the entity is missing a component required by componentType,
+// before the component is added.
+func (em *EntityManager) checkRequired(entityID EntityID, componentType reflect.Type) {
+	if !em.strict {
+		return
+	}
+
+	for _, r := range requirementsOf(componentType) {
+		if !em.hasComponentType(entityID, r.componentType) {
+			panic(fmt.Sprintf("ecs.AddComponent: entity %d: %s requires %s", entityID, componentType, r.componentType))
+		}
+	}
+}
+
+// addRequired adds the components required by componentType that the entity is missing.
+func (em *EntityManager) addRequired(entityID EntityID, componentType reflect.Type) {
+	for _, r := range requirementsOf(componentType) {
+		if !em.hasComponentType(entityID, r.componentType) {
+			r.add(em, entityID)
+		}
+	}
+}
+
+// RequirementViolation is an entity missing a component required by one of its components.
+type RequirementViolation struct {
+	Entity    EntityID
+	Component reflect.Type
+	Missing   reflect.Type
+}
+
+func (v RequirementViolation) String() string {
+	return fmt.Sprintf("entity %d: %s requires %s", v.Entity, v.Component, v.Missing)
+}
+
+// CheckRequirements returns the entities missing a component required by one of their components,
+// e.g. because the required component was removed, ordered by entity and component type name.
+func (em *EntityManager) CheckRequirements() []RequirementViolation {
+	var violations []RequirementViolation
+	for entityID := range em.entities {
+		for componentType := range em.entityComponentTypes(entityID) {
+			for _, r := range requirementsOf(componentType) {
+				if !em.hasComponentType(entityID, r.componentType) {
+					violations = append(violations, RequirementViolation{Entity: entityID, Component: componentType, Missing: r.componentType})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(violations, func(a, b RequirementViolation) int {
+		return cmp.Or(
+			cmp.Compare(a.Entity, b.Entity),
+			cmp.Compare(a.Component.String(), b.Component.String()),
+			cmp.Compare(a.Missing.String(), b.Missing.String()),
+		)
+	})
+
+	return violations
+}
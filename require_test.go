@@ -0,0 +1,71 @@
+package ecs_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type requiringSprite struct {
+	Frame int
+}
+
+type requiringBody struct {
+	Mass float64
+}
+
+func (b *requiringBody) Init() {
+	b.Mass = 1
+}
+
+func init() {
+	ecs.Require[requiringSprite, TransformComponent]()
+	ecs.Require[requiringBody, requiringSprite]()
+	ecs.Require[requiringBody, requiringSprite]()
+}
+
+func TestRequire(t *testing.T) {
+	assert.Equal(t, []reflect.Type{reflect.TypeFor[requiringSprite]()}, ecs.RequiredComponents(reflect.TypeFor[requiringBody]()),
+		"requirements are declared once")
+
+	em := ecs.NewEntityManager()
+
+	entityID := em.NewEntity()
+	body := ecs.AddComponent[requiringBody](em, entityID)
+	assert.Equal(t, 1.0, body.Mass)
+	assert.True(t, ecs.HasComponent[requiringSprite](em, entityID), "the requirement is added")
+	assert.True(t, ecs.HasComponent[TransformComponent](em, entityID), "the requirement of the requirement is added")
+
+	positioned := NewPlayerEntity(t, em)
+	ecs.MustGetComponent[TransformComponent](em, positioned).Rotation = 2
+	ecs.AddComponent[requiringSprite](em, positioned)
+	assert.Equal(t, 2.0, ecs.MustGetComponent[TransformComponent](em, positioned).Rotation, "existing requirements are kept")
+
+	assert.Empty(t, em.CheckRequirements())
+
+	ecs.RemoveComponent[TransformComponent](em, positioned)
+	ecs.RemoveComponent[requiringSprite](em, entityID)
+	assert.Equal(t, []ecs.RequirementViolation{
+		{Entity: entityID, Component: reflect.TypeFor[requiringBody](), Missing: reflect.TypeFor[requiringSprite]()},
+		{Entity: positioned, Component: reflect.TypeFor[requiringSprite](), Missing: reflect.TypeFor[TransformComponent]()},
+	}, em.CheckRequirements())
+}
+
+func TestRequireStrict(t *testing.T) {
+	em := ecs.NewEntityManager()
+	em.SetStrict(true)
+	require.True(t, em.Strict())
+
+	entityID := em.NewEntity()
+	assert.PanicsWithValue(t, fmt.Sprintf("ecs.AddComponent: entity %d: ecs_test.requiringSprite requires ecs_test.TransformComponent", entityID), func() {
+		ecs.AddComponent[requiringSprite](em, entityID)
+	})
+	assert.False(t, ecs.HasComponent[requiringSprite](em, entityID), "the component is not added")
+
+	ecs.AddComponent[TransformComponent](em, entityID)
+	assert.NotNil(t, ecs.AddComponent[requiringSprite](em, entityID))
+}
@@ -0,0 +1,47 @@
+package ecs
+
+// Persistent marks the entities that survive BaseWorld.Reset with ResetOptions.KeepPersistent, e.g. the
+// player's progress or the music, and the only ones saved by a save.Saver set with SetPersistentOnly.
+type Persistent struct{}
+
+// ResetOptions configures BaseWorld.Reset.
+type ResetOptions struct {
+	// KeepPersistent keeps the entities with a Persistent component.
+	KeepPersistent bool
+}
+
+// Reset restarts the world in place, e.g. to retry a level instantly: every entity is removed, the pending
+// commands are dropped, and the systems are kept but started up again before their next update, see
+// StartupSystem, so that they spawn the level anew. Assets and resources are left loaded, and the Pools of
+// the world are refilled to their size with free entities. opts may be nil.
+func (w *BaseWorld) Reset(opts *ResetOptions) {
+	keepPersistent := opts != nil && opts.KeepPersistent
+
+	em := w.EntityManager()
+	em.Commands().Reset()
+	for _, entityID := range em.Entities() {
+		if keepPersistent && HasComponent[Persistent](em, entityID) {
+			continue
+		}
+
+		em.Remove(entityID)
+	}
+
+	for _, pool := range em.pools {
+		pool.refill()
+	}
+
+	w.SystemManager().restart()
+}
+
+// restart makes the systems start up again and clears their schedules, as if they were just added.
+func (sm *SystemManager) restart() {
+	for _, system := range sm.systems {
+		base := system.baseSystem()
+		base.startedUp = false
+		base.elapsed, base.ticks = 0, 0
+	}
+
+	sm.startupPending = len(sm.systems) > 0
+	sm.fixedAccumulator = 0
+}
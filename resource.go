@@ -0,0 +1,55 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Resources is a type-keyed store for singleton data shared by systems, such as the game Clock.
+// Each resource type can be stored at most once.
+type Resources struct {
+	values map[reflect.Type]any
+}
+
+// NewResources creates an empty resource store.
+func NewResources() *Resources {
+	return &Resources{
+		values: make(map[reflect.Type]any),
+	}
+}
+
+// SetResource stores the resource, replacing any existing resource of the same type.
+func SetResource[T any](r *Resources, resource *T) {
+	r.values[reflect.TypeFor[T]()] = resource
+}
+
+// GetResource returns the resource of type T, if present.
+func GetResource[T any](r *Resources) (*T, bool) {
+	resource, ok := r.values[reflect.TypeFor[T]()]
+	if !ok {
+		return nil, false
+	}
+
+	return resource.(*T), true
+}
+
+// MustGetResource returns the resource of type T, panicking if it is not present.
+func MustGetResource[T any](r *Resources) *T {
+	resource, ok := GetResource[T](r)
+	if !ok {
+		panic(fmt.Sprintf("Resource of type %s does not exist", reflect.TypeFor[T]()))
+	}
+
+	return resource
+}
+
+// HasResource reports whether a resource of type T is present.
+func HasResource[T any](r *Resources) bool {
+	_, ok := r.values[reflect.TypeFor[T]()]
+	return ok
+}
+
+// RemoveResource removes the resource of type T.
+func RemoveResource[T any](r *Resources) {
+	delete(r.values, reflect.TypeFor[T]())
+}
@@ -0,0 +1,356 @@
+// Package save writes the entities of an EntityManager to save files and loads them back, across releases
+// of the game that change component structs.
+//
+// Components are stored by the name they were registered under with ecs.RegisterComponent, as JSON.
+// A save records the game's save version and a schema hash of every component type it contains. Loading
+// an older save runs the migrations registered from its version up to the current one, which call
+// Data.UpdateSchema for the components they bring up to date; a save that cannot be brought up to date,
+// or whose components changed without a version bump or a migration, is refused with ErrIncompatibleSave
+// before any entity is created.
+//
+//	saver := save.NewSaver(2)
+//	saver.RegisterMigration(1, 2, func(data *save.Data) error {
+//		data.RenameComponent("game.HP", "game.Health")
+//		err := data.UpdateComponent("game.Health", func(fields map[string]any) error {
+//			fields["Current"], fields["Max"] = fields["HP"], fields["HP"]
+//			return nil
+//		})
+//		if err != nil {
+//			return err
+//		}
+//		return data.UpdateSchema("game.Health")
+//	})
+//
+//	err := saver.Save(file, em)
+//	entityIDs, err := saver.Load(file, em)
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/codec"
+)
+
+// FormatVersion is the version of the save file layout, independent of the game's save version.
+const FormatVersion = 1
+
+// ErrIncompatibleSave is returned by Saver.Load for a save it cannot load without corrupting state:
+// an unknown file format, a save from a newer version, a version without a migration path, or a
+// component whose schema changed without a new save version.
+var ErrIncompatibleSave = errors.New("incompatible save")
+
+// Data is the content of a save file, as seen by migrations.
+type Data struct {
+	Format  int `json:"format"`
+	Version int `json:"version"`
+	// Schemas maps the name of every component type in the save to its SchemaHash.
+	Schemas  map[string]string `json:"schemas"`
+	Entities []*Entity         `json:"entities"`
+}
+
+// Entity is a saved entity. Its ID is the one it had when saved; loading creates new entities.
+type Entity struct {
+	ID         ecs.EntityID               `json:"id"`
+	Inactive   bool                       `json:"inactive,omitempty"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// RenameComponent renames a component type in every entity, e.g. after renaming its registration.
+func (d *Data) RenameComponent(from, to string) {
+	for _, entity := range d.Entities {
+		if component, ok := entity.Components[from]; ok {
+			delete(entity.Components, from)
+			entity.Components[to] = component
+		}
+	}
+
+	if schema, ok := d.Schemas[from]; ok {
+		delete(d.Schemas, from)
+		d.Schemas[to] = schema
+	}
+}
+
+// RemoveComponent removes a component type from every entity.
+func (d *Data) RemoveComponent(name string) {
+	for _, entity := range d.Entities {
+		delete(entity.Components, name)
+	}
+
+	delete(d.Schemas, name)
+}
+
+// UpdateComponent calls update with the fields of every saved component named name, decoded as a JSON
+// object, and saves the fields back, e.g. to rename a field or fill in a new one.
+func (d *Data) UpdateComponent(name string, update func(fields map[string]any) error) error {
+	for _, entity := range d.Entities {
+		component, ok := entity.Components[name]
+		if !ok {
+			continue
+		}
+
+		fields := make(map[string]any)
+		if err := json.Unmarshal(component, &fields); err != nil {
+			return fmt.Errorf("save.Data.UpdateComponent %s json.Unmarshal error: %w", name, err)
+		}
+
+		if err := update(fields); err != nil {
+			return fmt.Errorf("save.Data.UpdateComponent %s update error: %w", name, err)
+		}
+
+		updated, err := json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("save.Data.UpdateComponent %s json.Marshal error: %w", name, err)
+		}
+		entity.Components[name] = updated
+	}
+
+	return nil
+}
+
+// UpdateSchema records that the saved components named name match the registered component type,
+// once a migration has brought them up to date, so that loading accepts them.
+func (d *Data) UpdateSchema(name string) error {
+	componentType, ok := ecs.ComponentTypeByName(name)
+	if !ok {
+		return fmt.Errorf("save.Data.UpdateSchema component %q: %w", name, ecs.ErrUnregisteredComponent)
+	}
+
+	if d.Schemas == nil {
+		d.Schemas = make(map[string]string)
+	}
+	d.Schemas[name] = SchemaHash(componentType)
+
+	return nil
+}
+
+// Migration upgrades the data of a save by one step, from one save version to a later one.
+type Migration func(data *Data) error
+
+type migration struct {
+	to      int
+	migrate Migration
+}
+
+// Saver writes and loads saves of one save version.
+type Saver struct {
+	version        int
+	migrations     map[int]migration
+	persistentOnly bool
+	compressor     codec.Compressor
+}
+
+// NewSaver creates a Saver writing saves of the given version. Bump the version, and register a
+// migration from the previous one, whenever a saved component changes.
+func NewSaver(version int) *Saver {
+	return &Saver{version: version, migrations: make(map[int]migration)}
+}
+
+// Version returns the save version written by the Saver.
+func (s *Saver) Version() int {
+	return s.version
+}
+
+// SetPersistentOnly makes the Saver save only the entities with an ecs.Persistent component, leaving out
+// transient ones such as particles and projectiles. Entities it loads are given an ecs.Persistent component,
+// so that they are saved again.
+func (s *Saver) SetPersistentOnly(persistentOnly bool) {
+	s.persistentOnly = persistentOnly
+}
+
+// SetCompressor makes the Saver compress the saves it writes, and decompress the saves it loads, with
+// compressor, e.g. codec.NewDeflate(flate.BestSpeed) to keep autosaves fast and small. A nil compressor,
+// the default, writes plain JSON.
+func (s *Saver) SetCompressor(compressor codec.Compressor) {
+	s.compressor = compressor
+}
+
+// RegisterMigration registers the migration upgrading saves of version from to version to.
+// Loading runs migrations one after the other until the save reaches the Saver's version.
+// It panics if from is not lower than to, or if a migration from that version is already registered.
+func (s *Saver) RegisterMigration(from, to int, migrate Migration) {
+	if from >= to {
+		panic(fmt.Sprintf("save.Saver.RegisterMigration: migration from %d to %d does not upgrade", from, to))
+	}
+
+	if existing, ok := s.migrations[from]; ok {
+		panic(fmt.Sprintf("save.Saver.RegisterMigration: migration from %d to %d already registered", from, existing.to))
+	}
+
+	s.migrations[from] = migration{to: to, migrate: migrate}
+}
+
+// Save writes the entities of em with their registered components to w. Components of unregistered
+// types are not saved, nor are entities without ecs.Persistent if SetPersistentOnly is set.
+func (s *Saver) Save(w io.Writer, em *ecs.EntityManager) error {
+	data := &Data{
+		Format:  FormatVersion,
+		Version: s.version,
+		Schemas: make(map[string]string),
+	}
+
+	for _, entityID := range em.Entities() {
+		if s.persistentOnly && !ecs.HasComponent[ecs.Persistent](em, entityID) {
+			continue
+		}
+
+		entity := &Entity{
+			ID:         entityID,
+			Inactive:   !em.Active(entityID),
+			Components: make(map[string]json.RawMessage),
+		}
+
+		for componentType, component := range em.Components(entityID) {
+			name, err := ecs.ComponentName(componentType)
+			if err != nil {
+				continue
+			}
+
+			encoded, err := json.Marshal(component)
+			if err != nil {
+				return fmt.Errorf("save.Saver.Save %s json.Marshal error: %w", name, err)
+			}
+
+			entity.Components[name] = encoded
+			data.Schemas[name] = SchemaHash(componentType)
+		}
+
+		data.Entities = append(data.Entities, entity)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("save.Saver.Save json.Marshal error: %w", err)
+	}
+
+	if s.compressor != nil {
+		if encoded, err = s.compressor.Compress(encoded); err != nil {
+			return fmt.Errorf("save.Saver.Save Compress error: %w", err)
+		}
+	}
+
+	if _, err := w.Write(encoded); err != nil {
+		return fmt.Errorf("save.Saver.Save Write error: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a save from r, migrates it to the Saver's version and creates its entities in em.
+// It returns the IDs of the new entities, in the order they were saved. Nothing is created if
+// the save is refused or fails to decode.
+func (s *Saver) Load(r io.Reader, em *ecs.EntityManager) ([]ecs.EntityID, error) {
+	encoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("save.Saver.Load ReadAll error: %w", err)
+	}
+
+	if s.compressor != nil {
+		if encoded, err = s.compressor.Decompress(encoded); err != nil {
+			return nil, fmt.Errorf("save.Saver.Load Decompress error: %w", err)
+		}
+	}
+
+	data := new(Data)
+	if err := json.Unmarshal(encoded, data); err != nil {
+		return nil, fmt.Errorf("save.Saver.Load json.Unmarshal error: %w", err)
+	}
+
+	if err := s.migrate(data); err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]reflect.Type, len(data.Schemas))
+	for _, entity := range data.Entities {
+		for name := range entity.Components {
+			if _, ok := types[name]; ok {
+				continue
+			}
+
+			componentType, ok := ecs.ComponentTypeByName(name)
+			if !ok {
+				return nil, fmt.Errorf("save.Saver.Load component %q: %w", name, ecs.ErrUnregisteredComponent)
+			}
+			types[name] = componentType
+		}
+	}
+
+	entityIDs := make([]ecs.EntityID, 0, len(data.Entities))
+	for _, entity := range data.Entities {
+		entityID := em.NewEntity()
+		entityIDs = append(entityIDs, entityID)
+
+		for _, name := range slices.Sorted(maps.Keys(entity.Components)) {
+			component := em.AddComponentByType(entityID, types[name])
+			if err := json.Unmarshal(entity.Components[name], component); err != nil {
+				for _, created := range entityIDs {
+					em.Remove(created)
+				}
+
+				return nil, fmt.Errorf("save.Saver.Load entity %d %s json.Unmarshal error: %w", entity.ID, name, err)
+			}
+		}
+
+		if s.persistentOnly {
+			ecs.AddComponent[ecs.Persistent](em, entityID)
+		}
+
+		if entity.Inactive {
+			em.SetActive(entityID, false)
+		}
+	}
+
+	return entityIDs, nil
+}
+
+// migrate brings data up to the Saver's version, or returns why it cannot.
+func (s *Saver) migrate(data *Data) error {
+	if data.Format != FormatVersion {
+		return fmt.Errorf("save.Saver.Load: file format %d, want %d: %w", data.Format, FormatVersion, ErrIncompatibleSave)
+	}
+
+	if data.Version > s.version {
+		return fmt.Errorf("save.Saver.Load: save version %d is newer than %d: %w", data.Version, s.version, ErrIncompatibleSave)
+	}
+
+	for data.Version < s.version {
+		step, ok := s.migrations[data.Version]
+		if !ok {
+			return fmt.Errorf("save.Saver.Load: no migration from save version %d: %w", data.Version, ErrIncompatibleSave)
+		}
+
+		if step.to > s.version {
+			return fmt.Errorf("save.Saver.Load: migration from save version %d goes past %d to %d: %w", data.Version, s.version, step.to, ErrIncompatibleSave)
+		}
+
+		if err := step.migrate(data); err != nil {
+			return fmt.Errorf("save.Saver.Load migration from %d to %d error: %w", data.Version, step.to, err)
+		}
+		data.Version = step.to
+	}
+
+	return s.checkSchemas(data)
+}
+
+// checkSchemas refuses a save, migrated to the current version, whose components do not match the registered
+// types, which means a component changed without a new save version, or without a migration updating it.
+func (s *Saver) checkSchemas(data *Data) error {
+	for _, name := range slices.Sorted(maps.Keys(data.Schemas)) {
+		componentType, ok := ecs.ComponentTypeByName(name)
+		if !ok {
+			return fmt.Errorf("save.Saver.Load component %q: %w", name, ecs.ErrUnregisteredComponent)
+		}
+
+		if hash := SchemaHash(componentType); hash != data.Schemas[name] {
+			return fmt.Errorf("save.Saver.Load: component %q does not match its schema in save version %d: %w", name, data.Version, ErrIncompatibleSave)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package save_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/codec"
+	"github.com/samix73/ebiten-ecs/save"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Health struct {
+	Current int
+	Max     int
+}
+
+type Name struct {
+	Value string
+}
+
+func init() {
+	ecs.RegisterComponent[Health]("save_test.Health")
+	ecs.RegisterComponent[Name]("save_test.Name")
+}
+
+func TestSaveLoad(t *testing.T) {
+	em := ecs.NewEntityManager()
+	player := em.NewEntity()
+	*ecs.AddComponent[Health](em, player) = Health{Current: 2, Max: 3}
+	*ecs.AddComponent[Name](em, player) = Name{Value: "player"}
+	hidden := em.NewEntity()
+	ecs.AddComponent[Name](em, hidden).Value = "hidden"
+	em.SetActive(hidden, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, save.NewSaver(1).Save(&buf, em))
+
+	loaded := ecs.NewEntityManager()
+	entityIDs, err := save.NewSaver(1).Load(bytes.NewReader(buf.Bytes()), loaded)
+	require.NoError(t, err)
+	require.Len(t, entityIDs, 2)
+
+	health, ok := ecs.GetComponent[Health](loaded, entityIDs[0])
+	require.True(t, ok)
+	assert.Equal(t, Health{Current: 2, Max: 3}, *health)
+	assert.False(t, loaded.Active(entityIDs[1]))
+
+	t.Run("migration", func(t *testing.T) {
+		// Version 1 stored health as a single HP field.
+		old := saveData(t, 1, map[string]string{"save_test.HP": "old"}, `{"HP": 5}`)
+
+		saver := save.NewSaver(2)
+		saver.RegisterMigration(1, 2, func(data *save.Data) error {
+			data.RenameComponent("save_test.HP", "save_test.Health")
+			err := data.UpdateComponent("save_test.Health", func(fields map[string]any) error {
+				fields["Current"], fields["Max"] = fields["HP"], fields["HP"]
+				delete(fields, "HP")
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			return data.UpdateSchema("save_test.Health")
+		})
+
+		em := ecs.NewEntityManager()
+		entityIDs, err := saver.Load(bytes.NewReader(old), em)
+		require.NoError(t, err)
+		require.Len(t, entityIDs, 1)
+
+		health, ok := ecs.GetComponent[Health](em, entityIDs[0])
+		require.True(t, ok)
+		assert.Equal(t, Health{Current: 5, Max: 5}, *health)
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		em := ecs.NewEntityManager()
+
+		newer := saveData(t, 3, nil, `{}`)
+		_, err := save.NewSaver(2).Load(bytes.NewReader(newer), em)
+		assert.ErrorIs(t, err, save.ErrIncompatibleSave)
+
+		unmigrated := saveData(t, 1, nil, `{}`)
+		_, err = save.NewSaver(2).Load(bytes.NewReader(unmigrated), em)
+		assert.ErrorIs(t, err, save.ErrIncompatibleSave)
+
+		changed := saveData(t, 2, map[string]string{"save_test.Health": "0000000000000000"}, `{"Current": 1}`)
+		_, err = save.NewSaver(2).Load(bytes.NewReader(changed), em)
+		assert.ErrorIs(t, err, save.ErrIncompatibleSave)
+
+		saver := save.NewSaver(2)
+		saver.RegisterMigration(1, 2, func(*save.Data) error { return nil })
+		forgotten := saveData(t, 1, map[string]string{"save_test.Health": "0000000000000000"}, `{"Current": 1}`)
+		_, err = saver.Load(bytes.NewReader(forgotten), em)
+		assert.ErrorIs(t, err, save.ErrIncompatibleSave, "migrations must update the schemas of the components they change")
+
+		assert.Empty(t, em.Entities())
+	})
+}
+
+func TestPersistentOnly(t *testing.T) {
+	em := ecs.NewEntityManager()
+	player := em.NewEntity()
+	ecs.AddComponent[ecs.Persistent](em, player)
+	ecs.AddComponent[Health](em, player).Current = 3
+	particle := em.NewEntity()
+	ecs.AddComponent[Name](em, particle).Value = "spark"
+
+	saver := save.NewSaver(1)
+	saver.SetPersistentOnly(true)
+	saver.SetCompressor(codec.NewDeflate(flate.BestSpeed))
+
+	var buf bytes.Buffer
+	require.NoError(t, saver.Save(&buf, em))
+
+	loaded := ecs.NewEntityManager()
+	entityIDs, err := saver.Load(bytes.NewReader(buf.Bytes()), loaded)
+	require.NoError(t, err)
+	require.Len(t, entityIDs, 1)
+
+	health, ok := ecs.GetComponent[Health](loaded, entityIDs[0])
+	require.True(t, ok)
+	assert.Equal(t, 3, health.Current)
+
+	_, err = save.NewSaver(1).Load(bytes.NewReader(buf.Bytes()), loaded)
+	assert.Error(t, err, "compressed saves are not JSON")
+	assert.True(t, ecs.HasComponent[ecs.Persistent](loaded, entityIDs[0]))
+}
+
+// saveData encodes a save of one entity with a component of every schema, all with the given fields.
+func saveData(t *testing.T, version int, schemas map[string]string, fields string) []byte {
+	t.Helper()
+
+	entity := &save.Entity{ID: 1, Components: make(map[string]json.RawMessage)}
+	for name := range schemas {
+		entity.Components[name] = json.RawMessage(fields)
+	}
+
+	data, err := json.Marshal(&save.Data{
+		Format:   save.FormatVersion,
+		Version:  version,
+		Schemas:  schemas,
+		Entities: []*save.Entity{entity},
+	})
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestSchemaHash(t *testing.T) {
+	type renamed struct {
+		Current int
+		Max     int
+	}
+	type retyped struct {
+		Current float64
+		Max     int
+	}
+
+	hash := save.SchemaHash(reflect.TypeFor[Health]())
+	assert.Equal(t, hash, save.SchemaHash(reflect.TypeFor[renamed]()))
+	assert.NotEqual(t, hash, save.SchemaHash(reflect.TypeFor[retyped]()))
+}
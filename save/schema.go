@@ -0,0 +1,80 @@
+package save
+
+import (
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// SchemaHash returns a hash of the layout of a component type: the names, JSON tags and kinds of its
+// exported fields, recursively. It changes when a field that is saved is added, removed, renamed or
+// retyped, and not when the type itself is renamed or unexported fields change.
+func SchemaHash(componentType reflect.Type) string {
+	var b strings.Builder
+	describe(&b, componentType, make(map[reflect.Type]bool))
+
+	h := fnv.New64a()
+	h.Write([]byte(b.String()))
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// describe writes a canonical description of t to b. Types already being described are written by name,
+// so that recursive types terminate.
+func describe(b *strings.Builder, t reflect.Type, visiting map[reflect.Type]bool) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		b.WriteByte('*')
+		describe(b, t.Elem(), visiting)
+	case reflect.Slice:
+		b.WriteString("[]")
+		describe(b, t.Elem(), visiting)
+	case reflect.Array:
+		fmt.Fprintf(b, "[%d]", t.Len())
+		describe(b, t.Elem(), visiting)
+	case reflect.Map:
+		b.WriteString("map[")
+		describe(b, t.Key(), visiting)
+		b.WriteByte(']')
+		describe(b, t.Elem(), visiting)
+	case reflect.Struct:
+		if visiting[t] {
+			b.WriteString(t.String())
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		b.WriteString("struct{")
+		for field := range fields(t) {
+			b.WriteString(field.Name)
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				fmt.Fprintf(b, " %q", tag)
+			}
+			b.WriteByte(' ')
+			describe(b, field.Type, visiting)
+			b.WriteByte(';')
+		}
+		b.WriteByte('}')
+	default:
+		b.WriteString(t.Kind().String())
+	}
+}
+
+// fields returns the exported fields of the struct type t, which are the ones encoded.
+func fields(t reflect.Type) iter.Seq[reflect.StructField] {
+	return func(yield func(reflect.StructField) bool) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+
+			if !yield(field) {
+				return
+			}
+		}
+	}
+}
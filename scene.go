@@ -0,0 +1,305 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownSceneComponent is returned when a scene uses a component name that is not registered.
+	ErrUnknownSceneComponent = errors.New("unknown scene component")
+	// ErrUnknownScenePrefab is returned when a scene uses a prefab name that is not registered.
+	ErrUnknownScenePrefab = errors.New("unknown scene prefab")
+)
+
+// SceneRegistry maps the component and prefab names used in scene files to component types and prefabs.
+type SceneRegistry struct {
+	// components add a component of the registered type to an entity and return it.
+	components map[string]func(em *EntityManager, entityID EntityID) any
+	prefabs    map[string]*Prefab
+}
+
+// NewSceneRegistry creates an empty SceneRegistry.
+func NewSceneRegistry() *SceneRegistry {
+	return &SceneRegistry{
+		components: make(map[string]func(em *EntityManager, entityID EntityID) any),
+		prefabs:    make(map[string]*Prefab),
+	}
+}
+
+// DefaultSceneRegistry is the registry used by LoadScene.
+var DefaultSceneRegistry = NewSceneRegistry()
+
+// RegisterSceneComponent makes component type C available to the scenes loaded with r under name.
+// Registering another type under the same name replaces it.
+func RegisterSceneComponent[C any](r *SceneRegistry, name string) {
+	r.components[name] = func(em *EntityManager, entityID EntityID) any {
+		component, _ := AddOrGetComponent[C](em, entityID)
+		return component
+	}
+}
+
+// component returns the function adding the component named name to an entity: the type registered with
+// RegisterSceneComponent, or else the type registered with RegisterComponent under that name.
+func (r *SceneRegistry) component(name string) (func(em *EntityManager, entityID EntityID) any, bool) {
+	if add, ok := r.components[name]; ok {
+		return add, true
+	}
+
+	componentType, ok := ComponentTypeByName(name)
+	if !ok {
+		return nil, false
+	}
+
+	return func(em *EntityManager, entityID EntityID) any {
+		return em.AddComponentByType(entityID, componentType)
+	}, true
+}
+
+// RegisterPrefab makes the prefab available to the scenes loaded with r under its name.
+func (r *SceneRegistry) RegisterPrefab(prefab *Prefab) {
+	r.prefabs[prefab.Name()] = prefab
+}
+
+// Prefab returns the prefab registered under name.
+func (r *SceneRegistry) Prefab(name string) (*Prefab, bool) {
+	prefab, ok := r.prefabs[name]
+	return prefab, ok
+}
+
+// sceneFile is the format of scene files.
+type sceneFile struct {
+	Entities []sceneEntity `json:"entities"`
+}
+
+type sceneEntity struct {
+	Name       string                     `json:"name"`
+	Prefab     string                     `json:"prefab"`
+	Tags       []Tag                      `json:"tags"`
+	Inactive   bool                       `json:"inactive"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// Scene is the result of loading a scene file.
+type Scene struct {
+	// Name is the path of the scene file.
+	Name string
+	// Entities are the entities of the scene, in the order they are listed in the file.
+	Entities []EntityID
+	// Named maps the names given to entities in the file to the entities.
+	Named map[string]EntityID
+}
+
+// Despawn removes the entities of the scene that still exist.
+func (s *Scene) Despawn(em *EntityManager) {
+	for _, entityID := range s.Entities {
+		em.Remove(entityID)
+	}
+}
+
+// LoadScene loads the scene file name from fsys into em, using DefaultSceneRegistry. See SceneRegistry.LoadScene.
+func LoadScene(em *EntityManager, fsys fs.FS, name string) (*Scene, error) {
+	return DefaultSceneRegistry.LoadScene(em, fsys, name)
+}
+
+// LoadScene loads the scene file name from fsys into em, so levels can be changed without recompiling.
+// Files ending in .yaml or .yml are read as YAML, others as JSON. A scene lists entities, each made of
+// an optional prefab, applied first, and components whose fields are set from the given values:
+//
+//	{
+//	  "entities": [
+//	    {
+//	      "name": "player",
+//	      "tags": ["player"],
+//	      "components": {
+//	        "transform": {"Position": [32, 48]},
+//	        "health": {"Current": 3, "Max": 3}
+//	      }
+//	    },
+//	    {"prefab": "goblin", "components": {"transform": {"Position": [200, 48]}}}
+//	  ]
+//	}
+//
+// Component names are those given to RegisterSceneComponent, or else to RegisterComponent.
+// Component values are decoded with encoding/json into the component as added by AddComponent, so fields
+// not listed keep the values set by Init or the prefab. Unknown component names, prefab names and fields are
+// errors, so typos are caught. If the scene cannot be loaded entirely, the entities it created are removed.
+func (r *SceneRegistry) LoadScene(em *EntityManager, fsys fs.FS, name string) (*Scene, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.SceneRegistry.LoadScene fs.ReadFile error: %w", err)
+	}
+
+	var file sceneFile
+	if err := decodeSceneFile(name, data, &file); err != nil {
+		return nil, fmt.Errorf("ecs.SceneRegistry.LoadScene %s: %w", name, err)
+	}
+
+	scene := &Scene{Name: name, Named: make(map[string]EntityID)}
+	for i, entity := range file.Entities {
+		entityID, err := r.spawn(em, entity)
+		if err != nil {
+			scene.Despawn(em)
+			return nil, fmt.Errorf("ecs.SceneRegistry.LoadScene %s entity %d: %w", name, i, err)
+		}
+
+		scene.Entities = append(scene.Entities, entityID)
+		if entity.Name != "" {
+			scene.Named[entity.Name] = entityID
+		}
+	}
+
+	return scene, nil
+}
+
+// decodeSceneFile decodes a JSON or YAML scene or prefab file into v. YAML is converted to JSON first,
+// so component fields are named the same way in both formats.
+func decodeSceneFile(name string, data []byte, v any) error {
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		var document any
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("yaml.Unmarshal error: %w", err)
+		}
+
+		converted, err := json.Marshal(document)
+		if err != nil {
+			return fmt.Errorf("json.Marshal error: %w", err)
+		}
+		data = converted
+	}
+
+	if err := strictUnmarshal(data, v); err != nil {
+		return fmt.Errorf("json.Unmarshal error: %w", err)
+	}
+
+	return nil
+}
+
+// strictUnmarshal decodes JSON into v, rejecting unknown fields.
+func strictUnmarshal(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(v)
+}
+
+func (r *SceneRegistry) spawn(em *EntityManager, entity sceneEntity) (EntityID, error) {
+	if err := r.validate(entity); err != nil {
+		return UndefinedID, err
+	}
+
+	entityID := em.NewEntity()
+	if err := r.apply(em, entityID, entity); err != nil {
+		em.Remove(entityID)
+		return UndefinedID, err
+	}
+
+	return entityID, nil
+}
+
+// validate checks the prefab and component names of the entity.
+func (r *SceneRegistry) validate(entity sceneEntity) error {
+	if _, ok := r.prefabs[entity.Prefab]; entity.Prefab != "" && !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownScenePrefab, entity.Prefab)
+	}
+
+	for name := range entity.Components {
+		if _, ok := r.component(name); !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownSceneComponent, name)
+		}
+	}
+
+	return nil
+}
+
+// apply applies the prefab, components, tags and active state of a validated entity to entityID.
+func (r *SceneRegistry) apply(em *EntityManager, entityID EntityID, entity sceneEntity) error {
+	if entity.Prefab != "" {
+		r.prefabs[entity.Prefab].Apply(em, entityID)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entity.Components)) {
+		add, _ := r.component(name)
+		component := add(em, entityID)
+		if err := strictUnmarshal(entity.Components[name], component); err != nil {
+			return fmt.Errorf("component %q: %w", name, err)
+		}
+	}
+
+	em.Tag(entityID, entity.Tags...)
+	if entity.Inactive {
+		em.SetActive(entityID, false)
+	}
+
+	return nil
+}
+
+// LoadPrefab loads a prefab file from fsys and registers the prefab, so scenes and code can spawn it.
+// A prefab file describes a single entity like the entities of scene files, and may itself be based on
+// another registered prefab; the prefab is named after its "name" field, or the file name without extensions:
+//
+//	{"name": "goblin", "tags": ["enemy"], "components": {"health": {"Current": 10, "Max": 10}}}
+//
+// If a prefab with that name is already registered, it is updated in place, so existing references to it,
+// e.g. in Pools, spawn the new version.
+func (r *SceneRegistry) LoadPrefab(fsys fs.FS, name string) (*Prefab, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.SceneRegistry.LoadPrefab fs.ReadFile error: %w", err)
+	}
+
+	var entity sceneEntity
+	if err := decodeSceneFile(name, data, &entity); err != nil {
+		return nil, fmt.Errorf("ecs.SceneRegistry.LoadPrefab %s: %w", name, err)
+	}
+
+	prefabName := entity.Name
+	if prefabName == "" {
+		prefabName = path.Base(name)
+		if i := strings.IndexByte(prefabName, '.'); i > 0 {
+			prefabName = prefabName[:i]
+		}
+	}
+
+	if entity.Prefab == prefabName {
+		return nil, fmt.Errorf("ecs.SceneRegistry.LoadPrefab %s: prefab %q is based on itself", name, prefabName)
+	}
+
+	// Catch invalid component values now rather than when the prefab is spawned.
+	if _, err := r.spawn(NewEntityManager(), entity); err != nil {
+		return nil, fmt.Errorf("ecs.SceneRegistry.LoadPrefab %s: %w", name, err)
+	}
+
+	build := func(em *EntityManager, entityID EntityID) {
+		// The entity was validated, so only prefabs based on a prefab whose file changed since can fail.
+		if err := r.apply(em, entityID, entity); err != nil {
+			logger := em.Logger()
+			if logger == nil {
+				logger = slog.Default()
+			}
+
+			logger.Error("prefab failed to apply", slog.String("prefab", prefabName), slog.Any("error", err))
+		}
+	}
+
+	prefab, ok := r.prefabs[prefabName]
+	if ok {
+		prefab.build = build
+	} else {
+		prefab = NewPrefab(prefabName, build)
+		r.prefabs[prefabName] = prefab
+	}
+
+	return prefab, nil
+}
@@ -0,0 +1,182 @@
+package ecs_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func newSceneRegistry() *ecs.SceneRegistry {
+	registry := ecs.NewSceneRegistry()
+	ecs.RegisterSceneComponent[TransformComponent](registry, "transform")
+	ecs.RegisterSceneComponent[CameraComponent](registry, "camera")
+	ecs.RegisterSceneComponent[NameComponent](registry, "name")
+	registry.RegisterPrefab(ecs.NewPrefab("camera", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[CameraComponent](em, entityID).Zoom = 4
+		ecs.AddComponent[NameComponent](em, entityID).Name = "camera"
+	}))
+
+	return registry
+}
+
+func TestLoadScene(t *testing.T) {
+	fsys := fstest.MapFS{
+		"level.scene.json": {Data: []byte(`{
+			"entities": [
+				{
+					"name": "player",
+					"tags": ["player"],
+					"components": {
+						"transform": {"Position": [32, 48]},
+						"name": {"Name": "hero"}
+					}
+				},
+				{"name": "camera", "prefab": "camera", "inactive": true, "components": {"camera": {"Zoom": 2}}}
+			]
+		}`)},
+		"level.scene.yaml": {Data: []byte(`
+entities:
+  - name: player
+    components:
+      transform:
+        Position: [1, 2]
+      camera: {}
+`)},
+	}
+
+	registry := newSceneRegistry()
+	em := ecs.NewEntityManager()
+
+	scene, err := registry.LoadScene(em, fsys, "level.scene.json")
+	require.NoError(t, err)
+	require.Len(t, scene.Entities, 2)
+
+	player := scene.Named["player"]
+	assert.Equal(t, scene.Entities[0], player)
+	assert.Equal(t, f64.Vec2{32, 48}, ecs.MustGetComponent[TransformComponent](em, player).Position)
+	assert.Equal(t, "hero", ecs.MustGetComponent[NameComponent](em, player).Name)
+	assert.True(t, em.HasTag(player, "player"))
+
+	// Scene values override the prefab's, other fields keep them.
+	camera := scene.Named["camera"]
+	assert.Equal(t, 2.0, ecs.MustGetComponent[CameraComponent](em, camera).Zoom)
+	assert.Equal(t, "camera", ecs.MustGetComponent[NameComponent](em, camera).Name)
+	assert.False(t, em.Active(camera))
+
+	scene.Despawn(em)
+	assert.False(t, em.Exists(player))
+
+	scene, err = registry.LoadScene(em, fsys, "level.scene.yaml")
+	require.NoError(t, err)
+	player = scene.Named["player"]
+	assert.Equal(t, f64.Vec2{1, 2}, ecs.MustGetComponent[TransformComponent](em, player).Position)
+	// Components without values keep the values set by Init.
+	assert.Equal(t, 1.0, ecs.MustGetComponent[CameraComponent](em, player).Zoom)
+}
+
+func TestLoadSceneErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"component.json": {Data: []byte(`{"entities": [{"components": {"transform": {}}}, {"components": {"sprite": {}}}]}`)},
+		"prefab.json":    {Data: []byte(`{"entities": [{"prefab": "goblin"}]}`)},
+		"field.json":     {Data: []byte(`{"entities": [{"components": {"camera": {"Zoon": 2}}}]}`)},
+		"syntax.yaml":    {Data: []byte("entities: [")},
+	}
+
+	registry := newSceneRegistry()
+	em := ecs.NewEntityManager()
+
+	_, err := registry.LoadScene(em, fsys, "component.json")
+	require.ErrorIs(t, err, ecs.ErrUnknownSceneComponent)
+	// The entities created before the error are removed.
+	assert.Zero(t, ecs.Count(ecs.Query[TransformComponent](em)))
+
+	_, err = registry.LoadScene(em, fsys, "prefab.json")
+	require.ErrorIs(t, err, ecs.ErrUnknownScenePrefab)
+
+	_, err = registry.LoadScene(em, fsys, "field.json")
+	require.ErrorContains(t, err, "Zoon")
+	assert.Zero(t, ecs.Count(ecs.Query[CameraComponent](em)))
+
+	_, err = registry.LoadScene(em, fsys, "syntax.yaml")
+	require.Error(t, err)
+
+	_, err = ecs.LoadScene(em, fsys, "missing.json")
+	require.Error(t, err)
+}
+
+func TestSceneWatcher(t *testing.T) {
+	fsys := fstest.MapFS{
+		"goblin.prefab.json": {Data: []byte(`{"components": {"camera": {"Zoom": 2}}}`)},
+		"level.scene.json":   {Data: []byte(`{"entities": [{"name": "player", "components": {"transform": {"Position": [1, 1]}}}, {"prefab": "goblin"}]}`)},
+	}
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	watcher := ecs.NewSceneWatcher(ecs.NextID(), 0, newSceneRegistry(), fsys)
+	watcher.SetInterval(1)
+	sm.Add(watcher)
+
+	var reloads []ecs.SceneReloaded
+	watcher.OnReload(func(e ecs.SceneReloaded) { reloads = append(reloads, e) })
+
+	prefab, err := watcher.WatchPrefab("goblin.prefab.json")
+	require.NoError(t, err)
+	assert.Equal(t, "goblin", prefab.Name())
+
+	scene, err := watcher.WatchScene("level.scene.json")
+	require.NoError(t, err)
+	player := scene.Named["player"]
+
+	step := func(seconds float64) {
+		game.Time().Advance(seconds)
+		require.NoError(t, sm.Update())
+	}
+
+	// Unchanged files are not reloaded.
+	step(1)
+	assert.Empty(t, reloads)
+
+	// Changes are picked up at the next check.
+	fsys["level.scene.json"] = &fstest.MapFile{Data: []byte(`{"entities": [{"name": "player", "components": {"transform": {"Position": [5, 5]}}}, {"prefab": "goblin"}]}`)}
+	step(0.5)
+	assert.Empty(t, reloads)
+	step(0.5)
+	require.Len(t, reloads, 1)
+	require.NoError(t, reloads[0].Err)
+	assert.False(t, em.Exists(player))
+
+	scene, ok := watcher.Scene("level.scene.json")
+	require.True(t, ok)
+	assert.Same(t, reloads[0].Scene, scene)
+	assert.Equal(t, f64.Vec2{5, 5}, ecs.MustGetComponent[TransformComponent](em, scene.Named["player"]).Position)
+	assert.Equal(t, 1, ecs.Count(ecs.Query[ecs.PrefabInstance](em)), "the previous goblin is despawned")
+
+	// A broken file keeps the previous version until it is fixed.
+	reloads = nil
+	fsys["level.scene.json"] = &fstest.MapFile{Data: []byte(`{"entities": [`)}
+	watcher.Check()
+	require.Len(t, reloads, 1)
+	assert.Error(t, reloads[0].Err)
+	assert.True(t, em.Exists(scene.Named["player"]))
+	watcher.Check()
+	assert.Len(t, reloads, 1)
+
+	// A changed prefab updates the prefab and respawns the scenes.
+	reloads = nil
+	fsys["level.scene.json"] = &fstest.MapFile{Data: []byte(`{"entities": [{"prefab": "goblin"}]}`)}
+	fsys["goblin.prefab.json"] = &fstest.MapFile{Data: []byte(`{"components": {"camera": {"Zoom": 3}}}`)}
+	watcher.Check()
+	require.Len(t, reloads, 2)
+	assert.Equal(t, "goblin.prefab.json", reloads[0].Name)
+	assert.Equal(t, "level.scene.json", reloads[1].Name)
+
+	goblin := reloads[1].Scene.Entities[0]
+	assert.Equal(t, 3.0, ecs.MustGetComponent[CameraComponent](em, goblin).Zoom)
+	assert.Equal(t, 3.0, ecs.MustGetComponent[CameraComponent](em, prefab.Spawn(em)).Zoom)
+}
@@ -0,0 +1,206 @@
+package ecs
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// DefaultSceneWatchInterval is the initial interval between two checks of a SceneWatcher, in seconds.
+const DefaultSceneWatchInterval = 0.5
+
+// SceneReloaded is reported by a SceneWatcher after it reloaded a changed scene or prefab file.
+type SceneReloaded struct {
+	// Name is the path of the file that changed.
+	Name string
+	// Scene is the reloaded scene, nil when a prefab file changed.
+	Scene *Scene
+	// Err is the error that prevented the reload, in which case the previous version is kept.
+	Err error
+}
+
+type watchedFile struct {
+	data  []byte
+	scene *Scene
+}
+
+// SceneWatcher hot reloads scene and prefab files during development: it checks the watched files
+// periodically and, when one changed, despawns the entities of the affected scenes and spawns them again
+// from the new version. A changed prefab file updates the prefab and reloads every watched scene.
+// It compares file contents rather than modification times, so it works with any fs.FS, e.g. os.DirFS
+// of the asset directory; it should not be added to release builds, whose embedded files never change.
+//
+// The watcher keeps running while the game is paused. Reload errors, e.g. a syntax error in the file
+// being edited, keep the previous version and are reported to the OnReload handler, or logged if there is none.
+type SceneWatcher struct {
+	*BaseSystem
+
+	registry *SceneRegistry
+	fsys     fs.FS
+	interval float64
+	elapsed  float64
+
+	scenes  map[string]*watchedFile
+	prefabs map[string]*watchedFile
+
+	onReload func(SceneReloaded)
+}
+
+// NewSceneWatcher creates a SceneWatcher loading files from fsys with registry.
+func NewSceneWatcher(id SystemID, priority int, registry *SceneRegistry, fsys fs.FS) *SceneWatcher {
+	w := &SceneWatcher{
+		BaseSystem: NewBaseSystem(id, priority),
+		registry:   registry,
+		fsys:       fsys,
+		interval:   DefaultSceneWatchInterval,
+		scenes:     make(map[string]*watchedFile),
+		prefabs:    make(map[string]*watchedFile),
+	}
+	w.SetAlwaysRun(true)
+
+	return w
+}
+
+// SetInterval sets the interval between two checks in seconds of unscaled time.
+func (w *SceneWatcher) SetInterval(seconds float64) {
+	w.interval = seconds
+}
+
+// OnReload sets the function called after every reload attempt.
+func (w *SceneWatcher) OnReload(handler func(SceneReloaded)) {
+	w.onReload = handler
+}
+
+// WatchScene loads the scene file name into the watcher's EntityManager and reloads it whenever it changes.
+// The watcher must have been added to a SystemManager.
+func (w *SceneWatcher) WatchScene(name string) (*Scene, error) {
+	data, err := fs.ReadFile(w.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.SceneWatcher.WatchScene fs.ReadFile error: %w", err)
+	}
+
+	scene, err := w.registry.LoadScene(w.EntityManager(), w.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.SceneWatcher.WatchScene w.registry.LoadScene error: %w", err)
+	}
+
+	w.scenes[name] = &watchedFile{data: data, scene: scene}
+
+	return scene, nil
+}
+
+// WatchPrefab loads and registers the prefab file name, see SceneRegistry.LoadPrefab, and reloads it whenever it changes.
+func (w *SceneWatcher) WatchPrefab(name string) (*Prefab, error) {
+	data, err := fs.ReadFile(w.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.SceneWatcher.WatchPrefab fs.ReadFile error: %w", err)
+	}
+
+	prefab, err := w.registry.LoadPrefab(w.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("ecs.SceneWatcher.WatchPrefab w.registry.LoadPrefab error: %w", err)
+	}
+
+	w.prefabs[name] = &watchedFile{data: data}
+
+	return prefab, nil
+}
+
+// Scene returns the current version of a watched scene, whose entities change on every reload.
+func (w *SceneWatcher) Scene(name string) (*Scene, bool) {
+	file, ok := w.scenes[name]
+	if !ok {
+		return nil, false
+	}
+
+	return file.scene, true
+}
+
+// Unwatch stops watching a scene or prefab file. The entities of a scene are left in place.
+func (w *SceneWatcher) Unwatch(name string) {
+	delete(w.scenes, name)
+	delete(w.prefabs, name)
+}
+
+// Update checks the watched files once per interval and reloads the changed ones.
+func (w *SceneWatcher) Update() error {
+	w.elapsed += w.Time().UnscaledDelta()
+	if w.elapsed < w.interval {
+		return nil
+	}
+	w.elapsed = 0
+
+	w.Check()
+
+	return nil
+}
+
+// Check reloads the watched files that changed since they were last loaded, without waiting for the interval.
+func (w *SceneWatcher) Check() {
+	prefabChanged := false
+	for _, name := range slices.Sorted(maps.Keys(w.prefabs)) {
+		file := w.prefabs[name]
+
+		data, changed := w.changed(name, file)
+		if !changed {
+			continue
+		}
+
+		_, err := w.registry.LoadPrefab(w.fsys, name)
+		if err == nil {
+			prefabChanged = true
+		}
+
+		// Don't retry a broken file until it changes again.
+		file.data = data
+		w.report(SceneReloaded{Name: name, Err: err})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(w.scenes)) {
+		file := w.scenes[name]
+
+		data, changed := w.changed(name, file)
+		if !changed && !prefabChanged {
+			continue
+		}
+
+		file.data = data
+		w.reload(name, file)
+	}
+}
+
+// changed reads a watched file and reports whether its contents changed.
+// A file that cannot be read, e.g. because an editor is replacing it, is considered unchanged.
+func (w *SceneWatcher) changed(name string, file *watchedFile) ([]byte, bool) {
+	data, err := fs.ReadFile(w.fsys, name)
+	if err != nil {
+		return file.data, false
+	}
+
+	return data, !bytes.Equal(data, file.data)
+}
+
+// reload replaces the entities of a scene with those of the current file, keeping them if it fails to load.
+func (w *SceneWatcher) reload(name string, file *watchedFile) {
+	scene, err := w.registry.LoadScene(w.EntityManager(), w.fsys, name)
+	if err == nil {
+		file.scene.Despawn(w.EntityManager())
+		file.scene = scene
+	}
+
+	w.report(SceneReloaded{Name: name, Scene: scene, Err: err})
+}
+
+func (w *SceneWatcher) report(event SceneReloaded) {
+	if w.onReload != nil {
+		w.onReload(event)
+		return
+	}
+
+	if event.Err != nil {
+		errorLogger(w.Game()).Error("scene reload failed", slog.String("file", event.Name), slog.Any("error", event.Err))
+	}
+}
@@ -0,0 +1,158 @@
+package ecs
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ScaleMode selects how the virtual screen is scaled into the window.
+type ScaleMode int
+
+const (
+	// ScaleFit scales the virtual screen by the largest factor that fits the window.
+	ScaleFit ScaleMode = iota
+	// ScaleInteger scales the virtual screen by the largest whole factor that fits the window, so that
+	// pixel art stays crisp. Windows smaller than the virtual screen fall back to ScaleFit.
+	ScaleInteger
+)
+
+// Screen maps the virtual resolution the worlds are drawn at to the window. When a virtual resolution
+// is declared, Game.Layout returns the window size, the active world is drawn onto an offscreen image
+// of the virtual size, and Game.Draw scales it into the window, centered and letterboxed. Without one,
+// the worlds are drawn at GameConfig.ScreenWidth and ScreenHeight and Ebiten scales them itself.
+//
+// The game Screen is available as a resource and through Game.Screen.
+type Screen struct {
+	width, height int
+	mode          ScaleMode
+	letterbox     color.Color
+
+	// outsideWidth and outsideHeight are the window size passed to the last Layout.
+	outsideWidth, outsideHeight int
+	scale                       float64
+	offsetX, offsetY            float64
+
+	target *ebiten.Image
+	op     ebiten.DrawImageOptions
+}
+
+// NewScreen creates a screen without a virtual resolution.
+func NewScreen() *Screen {
+	return &Screen{
+		letterbox: color.Black,
+	}
+}
+
+// SetVirtualResolution declares the resolution the worlds are drawn at. A zero width or height
+// removes the virtual resolution.
+func (s *Screen) SetVirtualResolution(width, height int) {
+	if width <= 0 || height <= 0 {
+		width, height = 0, 0
+	}
+
+	s.width, s.height = width, height
+	s.layout(s.outsideWidth, s.outsideHeight)
+}
+
+// VirtualResolution returns the declared virtual resolution, and whether there is one.
+func (s *Screen) VirtualResolution() (width, height int, ok bool) {
+	return s.width, s.height, s.width > 0
+}
+
+// SetScaleMode sets how the virtual screen is scaled into the window. It is ScaleFit by default.
+func (s *Screen) SetScaleMode(mode ScaleMode) {
+	s.mode = mode
+	s.layout(s.outsideWidth, s.outsideHeight)
+}
+
+// ScaleMode returns how the virtual screen is scaled into the window.
+func (s *Screen) ScaleMode() ScaleMode {
+	return s.mode
+}
+
+// SetLetterboxColor sets the color of the bars around the virtual screen. It is black by default.
+func (s *Screen) SetLetterboxColor(clr color.Color) {
+	s.letterbox = clr
+}
+
+// Scale returns the factor the virtual screen is scaled by as of the last layout, 1 without a virtual resolution.
+func (s *Screen) Scale() float64 {
+	if s.width == 0 || s.scale == 0 {
+		return 1
+	}
+
+	return s.scale
+}
+
+// ScreenToVirtual converts a position in the window, such as ebiten.CursorPosition, to the virtual screen.
+// Positions in the letterbox are outside of the virtual screen bounds.
+func (s *Screen) ScreenToVirtual(x, y float64) (vx, vy float64) {
+	if s.width == 0 || s.scale == 0 {
+		return x, y
+	}
+
+	return (x - s.offsetX) / s.scale, (y - s.offsetY) / s.scale
+}
+
+// VirtualToScreen converts a position on the virtual screen to the window.
+func (s *Screen) VirtualToScreen(vx, vy float64) (x, y float64) {
+	if s.width == 0 || s.scale == 0 {
+		return vx, vy
+	}
+
+	return vx*s.scale + s.offsetX, vy*s.scale + s.offsetY
+}
+
+// layout computes the scale and the position of the virtual screen in a window of the given size.
+func (s *Screen) layout(outsideWidth, outsideHeight int) {
+	s.outsideWidth, s.outsideHeight = outsideWidth, outsideHeight
+	if s.width == 0 || outsideWidth <= 0 || outsideHeight <= 0 {
+		s.scale, s.offsetX, s.offsetY = 0, 0, 0
+		return
+	}
+
+	s.scale = min(float64(outsideWidth)/float64(s.width), float64(outsideHeight)/float64(s.height))
+	if s.mode == ScaleInteger && s.scale >= 1 {
+		s.scale = math.Floor(s.scale)
+	}
+
+	s.offsetX = (float64(outsideWidth) - float64(s.width)*s.scale) / 2
+	s.offsetY = (float64(outsideHeight) - float64(s.height)*s.scale) / 2
+	if s.mode == ScaleInteger {
+		s.offsetX, s.offsetY = math.Floor(s.offsetX), math.Floor(s.offsetY)
+	}
+}
+
+// canvas returns the offscreen image of the virtual size the worlds are drawn onto, cleared.
+func (s *Screen) canvas() *ebiten.Image {
+	if s.target != nil && (s.target.Bounds().Dx() != s.width || s.target.Bounds().Dy() != s.height) {
+		s.target.Deallocate()
+		s.target = nil
+	}
+
+	if s.target == nil {
+		s.target = ebiten.NewImage(s.width, s.height)
+	}
+
+	s.target.Clear()
+
+	return s.target
+}
+
+// present draws the virtual screen into the window, letterboxed.
+func (s *Screen) present(screen *ebiten.Image) {
+	screen.Fill(s.letterbox)
+
+	s.op.GeoM.Reset()
+	s.op.GeoM.Scale(s.scale, s.scale)
+	s.op.GeoM.Translate(s.offsetX, s.offsetY)
+
+	s.op.Filter = ebiten.FilterLinear
+	if s.mode == ScaleInteger {
+		s.op.Filter = ebiten.FilterNearest
+	}
+
+	screen.DrawImage(s.target, &s.op)
+}
@@ -0,0 +1,46 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreen(t *testing.T) {
+	g := ecs.NewGame(&ecs.GameConfig{ScreenWidth: 640, ScreenHeight: 480})
+
+	width, height := g.Layout(1000, 600)
+	assert.Equal(t, [2]int{640, 480}, [2]int{width, height}, "without a virtual resolution, Ebiten scales the screen")
+
+	x, y := g.Screen().ScreenToVirtual(10, 20)
+	assert.Equal(t, [2]float64{10, 20}, [2]float64{x, y})
+
+	g.Screen().SetVirtualResolution(320, 180)
+	width, height = g.Layout(1000, 600)
+	assert.Equal(t, [2]int{1000, 600}, [2]int{width, height})
+	width, height = g.ScreenSize()
+	assert.Equal(t, [2]int{320, 180}, [2]int{width, height})
+
+	assert.Equal(t, 3.125, g.Screen().Scale())
+	x, y = g.Screen().ScreenToVirtual(500, 300)
+	assert.Equal(t, [2]float64{160, 90}, [2]float64{x, y})
+	x, y = g.Screen().VirtualToScreen(0, 0)
+	assert.Equal(t, [2]float64{0, 18.75}, [2]float64{x, y}, "the virtual screen is letterboxed vertically")
+}
+
+func TestScreenInteger(t *testing.T) {
+	g := ecs.NewGame(&ecs.GameConfig{VirtualWidth: 320, VirtualHeight: 180, ScaleMode: ecs.ScaleInteger})
+	g.Layout(1000, 600)
+
+	assert.Equal(t, 3.0, g.Screen().Scale())
+	x, y := g.Screen().ScreenToVirtual(20+30, 30+60)
+	assert.Equal(t, [2]float64{10, 20}, [2]float64{x, y})
+
+	g.Layout(160, 120)
+	assert.Equal(t, 0.5, g.Screen().Scale(), "windows smaller than the virtual screen are scaled down to fit")
+
+	screen, ok := ecs.GetResource[ecs.Screen](g.Resources())
+	assert.True(t, ok)
+	assert.Same(t, g.Screen(), screen)
+}
@@ -0,0 +1,37 @@
+// Package scripting runs Lua scripts on entities with gopher-lua, for modding and designer-authored
+// behavior without recompiling the game.
+//
+// The package is built with the lua build tag only, so that games without scripts do not link gopher-lua:
+//
+//	go build -tags lua ./...
+//
+// A ScriptSystem runs the script named by the Script component of every entity. Scripts are Lua chunks
+// returning a table with an update function, called every update with the entity and the delta time:
+//
+//	return {
+//		update = function(entity, dt)
+//			local health = ecs.get(entity, "game.Health")
+//			if health.Current <= 0 then
+//				ecs.publish("died", { entity = entity })
+//				ecs.remove(entity)
+//			end
+//		end,
+//	}
+//
+// Scripts reach the world through the ecs table:
+//
+//	ecs.spawn() -> entity
+//	ecs.remove(entity)
+//	ecs.exists(entity) -> boolean
+//	ecs.has(entity, component) -> boolean
+//	ecs.get(entity, component) -> table or nil
+//	ecs.set(entity, component, fields)   -- adds the component if needed and sets the given fields
+//	ecs.unset(entity, component)
+//	ecs.query(component, ...) -> array of the entities with all the components
+//	ecs.publish(name, payload)           -- calls the ScriptSystem's event handler
+//	ecs.dt                               -- the delta time of the update, in seconds
+//
+// Components are named as registered with ecs.RegisterComponent, and their exported fields are converted
+// to and from Lua tables through encoding/json. Tables with a sequence part are converted to arrays, and
+// the others to objects; tables mixing both, or containing themselves, raise an error.
+package scripting
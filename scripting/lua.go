@@ -0,0 +1,376 @@
+//go:build lua
+
+package scripting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	lua "github.com/yuin/gopher-lua"
+)
+
+var _ ecs.Teardowner = (*ScriptSystem)(nil)
+
+// ErrUnknownScript is returned by ScriptSystem.Update for an entity whose Script names a script that was
+// not loaded.
+var ErrUnknownScript = errors.New("unknown script")
+
+// Script makes the ScriptSystem run the named script on the entity every update.
+type Script struct {
+	Name string
+}
+
+func (s *Script) Reset() {
+	*s = Script{}
+}
+
+// ScriptSystem runs the scripts of the entities with a Script component, in entity order.
+type ScriptSystem struct {
+	*ecs.BaseSystem
+
+	state   *lua.LState
+	scripts map[string]*lua.LTable
+	onEvent func(name string, payload any)
+}
+
+// NewScriptSystem creates a new ScriptSystem with the given ID and priority and its own Lua state.
+func NewScriptSystem(id ecs.SystemID, priority int) *ScriptSystem {
+	s := &ScriptSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		state:      lua.NewState(),
+		scripts:    make(map[string]*lua.LTable),
+	}
+	s.state.SetGlobal("ecs", s.bindings())
+
+	return s
+}
+
+// LoadScript runs the Lua source and keeps the table it returns as the script named name, replacing any
+// script of that name, e.g. when a mod is reloaded.
+func (s *ScriptSystem) LoadScript(name, source string) error {
+	fn, err := s.state.LoadString(source)
+	if err != nil {
+		return fmt.Errorf("scripting.ScriptSystem.LoadScript %q LoadString error: %w", name, err)
+	}
+
+	if err := s.state.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}); err != nil {
+		return fmt.Errorf("scripting.ScriptSystem.LoadScript %q error: %w", name, err)
+	}
+
+	module := s.state.Get(-1)
+	s.state.Pop(1)
+
+	table, ok := module.(*lua.LTable)
+	if !ok {
+		return fmt.Errorf("scripting.ScriptSystem.LoadScript %q returned %s, not a table", name, module.Type())
+	}
+
+	s.scripts[name] = table
+
+	return nil
+}
+
+// OnEvent sets the handler called when a script publishes an event with ecs.publish, with the payload
+// converted to Go values: nil, bool, float64, string, []any or map[string]any. A nil handler removes it.
+func (s *ScriptSystem) OnEvent(handler func(name string, payload any)) {
+	s.onEvent = handler
+}
+
+// State returns the Lua state of the system, e.g. to add bindings of the game.
+func (s *ScriptSystem) State() *lua.LState {
+	return s.state
+}
+
+// Update calls the update function of the script of every entity with a Script component.
+func (s *ScriptSystem) Update() error {
+	em := s.EntityManager()
+	dt := 0.0
+	if t := s.Time(); t != nil {
+		dt = t.Delta()
+	}
+
+	s.state.GetGlobal("ecs").(*lua.LTable).RawSetString("dt", lua.LNumber(dt))
+
+	// Scripts may spawn and remove entities, which is not allowed while querying.
+	entityIDs := slices.Collect(ecs.Query[Script](em))
+	for _, entityID := range entityIDs {
+		script, ok := ecs.GetComponent[Script](em, entityID)
+		if !ok {
+			continue
+		}
+
+		module, ok := s.scripts[script.Name]
+		if !ok {
+			return fmt.Errorf("scripting.ScriptSystem.Update entity %d script %q: %w", entityID, script.Name, ErrUnknownScript)
+		}
+
+		update, ok := module.RawGetString("update").(*lua.LFunction)
+		if !ok {
+			continue
+		}
+
+		if err := s.state.CallByParam(lua.P{Fn: update, NRet: 0, Protect: true}, lua.LNumber(entityID), lua.LNumber(dt)); err != nil {
+			return fmt.Errorf("scripting.ScriptSystem.Update entity %d script %q error: %w", entityID, script.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Teardown closes the Lua state.
+func (s *ScriptSystem) Teardown() {
+	s.state.Close()
+}
+
+// bindings returns the ecs table of the scripts.
+func (s *ScriptSystem) bindings() *lua.LTable {
+	table := s.state.NewTable()
+	s.state.SetFuncs(table, map[string]lua.LGFunction{
+		"spawn":   s.spawn,
+		"remove":  s.remove,
+		"exists":  s.exists,
+		"has":     s.has,
+		"get":     s.get,
+		"set":     s.set,
+		"unset":   s.unset,
+		"query":   s.query,
+		"publish": s.publish,
+	})
+
+	return table
+}
+
+func (s *ScriptSystem) spawn(L *lua.LState) int {
+	L.Push(lua.LNumber(s.EntityManager().NewEntity()))
+	return 1
+}
+
+func (s *ScriptSystem) remove(L *lua.LState) int {
+	s.EntityManager().Remove(checkEntity(L, 1))
+	return 0
+}
+
+func (s *ScriptSystem) exists(L *lua.LState) int {
+	L.Push(lua.LBool(s.EntityManager().Exists(checkEntity(L, 1))))
+	return 1
+}
+
+func (s *ScriptSystem) has(L *lua.LState) int {
+	_, ok := s.EntityManager().GetComponentByType(checkEntity(L, 1), checkComponent(L, 2))
+	L.Push(lua.LBool(ok))
+	return 1
+}
+
+func (s *ScriptSystem) get(L *lua.LState) int {
+	component, ok := s.EntityManager().GetComponentByType(checkEntity(L, 1), checkComponent(L, 2))
+	if !ok {
+		L.Push(lua.LNil)
+		return 1
+	}
+
+	data, err := json.Marshal(component)
+	if err != nil {
+		L.RaiseError("ecs.get: %v", err)
+		return 0
+	}
+
+	var fields any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		L.RaiseError("ecs.get: %v", err)
+		return 0
+	}
+
+	L.Push(toLua(L, fields))
+	return 1
+}
+
+func (s *ScriptSystem) set(L *lua.LState) int {
+	entityID, componentType, fields := checkEntity(L, 1), checkComponent(L, 2), L.CheckTable(3)
+
+	component := s.EntityManager().AddComponentByType(entityID, componentType)
+	if component == nil {
+		L.ArgError(1, "entity does not exist")
+		return 0
+	}
+
+	value, err := toGo(fields)
+	if err != nil {
+		L.ArgError(3, err.Error())
+		return 0
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		L.RaiseError("ecs.set: %v", err)
+		return 0
+	}
+
+	if err := json.Unmarshal(data, component); err != nil {
+		L.RaiseError("ecs.set: %v", err)
+	}
+
+	return 0
+}
+
+func (s *ScriptSystem) unset(L *lua.LState) int {
+	s.EntityManager().RemoveComponentByType(checkEntity(L, 1), checkComponent(L, 2))
+	return 0
+}
+
+func (s *ScriptSystem) query(L *lua.LState) int {
+	componentTypes := make([]reflect.Type, 0, L.GetTop())
+	for i := 1; i <= L.GetTop(); i++ {
+		componentTypes = append(componentTypes, checkComponent(L, i))
+	}
+
+	em := s.EntityManager()
+	result := L.NewTable()
+	for _, entityID := range em.Entities() {
+		if !em.Active(entityID) {
+			continue
+		}
+
+		matches := true
+		for _, componentType := range componentTypes {
+			if _, ok := em.GetComponentByType(entityID, componentType); !ok {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			result.Append(lua.LNumber(entityID))
+		}
+	}
+
+	L.Push(result)
+	return 1
+}
+
+func (s *ScriptSystem) publish(L *lua.LState) int {
+	name := L.CheckString(1)
+	if s.onEvent == nil {
+		return 0
+	}
+
+	value, err := toGo(L.Get(2))
+	if err != nil {
+		L.ArgError(2, err.Error())
+		return 0
+	}
+	s.onEvent(name, value)
+
+	return 0
+}
+
+// checkEntity returns the entity ID argument n.
+func checkEntity(L *lua.LState, n int) ecs.EntityID {
+	return ecs.EntityID(L.CheckInt64(n))
+}
+
+// checkComponent returns the component type registered under the name argument n.
+func checkComponent(L *lua.LState, n int) reflect.Type {
+	name := L.CheckString(n)
+	componentType, ok := ecs.ComponentTypeByName(name)
+	if !ok {
+		L.ArgError(n, fmt.Sprintf("component %q is not registered", name))
+	}
+
+	return componentType
+}
+
+// toGo converts a Lua value to nil, bool, float64, string, []any or map[string]any. Tables with a
+// sequence part are arrays, and are refused if they have other keys too, as are tables containing themselves.
+func toGo(value lua.LValue) (any, error) {
+	return toGoVisiting(value, make(map[*lua.LTable]bool))
+}
+
+// toGoVisiting is toGo, visiting the tables being converted.
+func toGoVisiting(value lua.LValue, visiting map[*lua.LTable]bool) (any, error) {
+	switch value := value.(type) {
+	case lua.LBool:
+		return bool(value), nil
+	case lua.LNumber:
+		return float64(value), nil
+	case lua.LString:
+		return string(value), nil
+	case *lua.LTable:
+		if visiting[value] {
+			return nil, errors.New("table contains itself")
+		}
+		visiting[value] = true
+		defer delete(visiting, value)
+
+		if n := value.MaxN(); n > 0 {
+			mixed := false
+			value.ForEach(func(key, _ lua.LValue) {
+				index, ok := key.(lua.LNumber)
+				if i := int(index); !ok || float64(i) != float64(index) || i < 1 || i > n {
+					mixed = true
+				}
+			})
+			if mixed {
+				return nil, errors.New("table mixes array elements and fields")
+			}
+
+			array := make([]any, 0, n)
+			for i := 1; i <= n; i++ {
+				element, err := toGoVisiting(value.RawGetInt(i), visiting)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, element)
+			}
+
+			return array, nil
+		}
+
+		fields := make(map[string]any)
+		var err error
+		value.ForEach(func(key, field lua.LValue) {
+			if err != nil {
+				return
+			}
+			fields[key.String()], err = toGoVisiting(field, visiting)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return fields, nil
+	default:
+		return nil, nil
+	}
+}
+
+// toLua converts a value decoded by encoding/json to a Lua value.
+func toLua(L *lua.LState, value any) lua.LValue {
+	switch value := value.(type) {
+	case bool:
+		return lua.LBool(value)
+	case float64:
+		return lua.LNumber(value)
+	case string:
+		return lua.LString(value)
+	case []any:
+		table := L.CreateTable(len(value), 0)
+		for _, element := range value {
+			table.Append(toLua(L, element))
+		}
+
+		return table
+	case map[string]any:
+		table := L.CreateTable(0, len(value))
+		for key, field := range value {
+			table.RawSetString(key, toLua(L, field))
+		}
+
+		return table
+	default:
+		return lua.LNil
+	}
+}
@@ -0,0 +1,81 @@
+//go:build lua
+
+package scripting_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/scripting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Health struct {
+	Current int
+}
+
+func init() {
+	ecs.RegisterComponent[Health]("scripting_test.Health")
+}
+
+const poison = `
+return {
+	update = function(entity, dt)
+		local health = ecs.get(entity, "scripting_test.Health")
+		ecs.set(entity, "scripting_test.Health", { Current = health.Current - 1 })
+		if health.Current - 1 <= 0 then
+			ecs.publish("died", { entity = entity })
+			ecs.remove(entity)
+		end
+	end,
+}
+`
+
+func TestScriptSystem(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+	system := scripting.NewScriptSystem(ecs.NextSystemID(), 0)
+	require.NoError(t, sm.Add(system))
+	require.NoError(t, system.LoadScript("poison", poison))
+
+	var died []float64
+	system.OnEvent(func(name string, payload any) {
+		assert.Equal(t, "died", name)
+		died = append(died, payload.(map[string]any)["entity"].(float64))
+	})
+
+	entityID := em.NewEntity()
+	ecs.AddComponent[Health](em, entityID).Current = 2
+	ecs.AddComponent[scripting.Script](em, entityID).Name = "poison"
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, 1, ecs.MustGetComponent[Health](em, entityID).Current)
+
+	require.NoError(t, sm.Update())
+	assert.False(t, em.Exists(entityID))
+	assert.Equal(t, []float64{float64(entityID)}, died)
+
+	other := em.NewEntity()
+	ecs.AddComponent[scripting.Script](em, other).Name = "missing"
+	assert.ErrorIs(t, sm.Update(), scripting.ErrUnknownScript)
+}
+
+func TestScriptTableErrors(t *testing.T) {
+	for name, script := range map[string]string{
+		"cycle": `local t = {}; t.self = t; ecs.publish("cycle", t)`,
+		"mixed": `ecs.publish("mixed", { 1, 2, name = "pair" })`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			em := ecs.NewEntityManager()
+			sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+			system := scripting.NewScriptSystem(ecs.NextSystemID(), 0)
+			require.NoError(t, sm.Add(system))
+			require.NoError(t, system.LoadScript(name, "return { update = function(entity, dt) "+script+" end }"))
+			system.OnEvent(func(string, any) { t.Error("the event is not published") })
+
+			ecs.AddComponent[scripting.Script](em, em.NewEntity()).Name = name
+			assert.Error(t, sm.Update())
+		})
+	}
+}
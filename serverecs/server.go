@@ -0,0 +1,194 @@
+// Package serverecs runs a world as an authoritative dedicated server, so that the same ECS worlds run
+// on clients and on the server.
+//
+// The server runs headless at a fixed tick rate with deterministic system order. Every tick it receives
+// the clients' messages from a Transport, hands them to the systems in the Inbox resource, updates the
+// world, and sends every client a replication snapshot of the tick. Like ecs.Game.RunHeadless, it needs
+// a display on Linux, e.g. Xvfb, although it never opens a window:
+//
+//	err := serverecs.Run(&MatchWorld{}, 30, transport, registry)
+//
+//	// In a system of the world:
+//	inbox := ecs.MustGetResource[serverecs.Inbox](s.Game().Resources())
+//	for _, message := range inbox.Messages {
+//		switch message.Kind {
+//		case serverecs.Connected:
+//			spawnPlayer(message.Client)
+//		case serverecs.Input:
+//			applyInput(message.Client, message.Data)
+//		}
+//	}
+package serverecs
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/replication"
+)
+
+// ErrNoEntityManager is returned by NewServer for a world that does not expose its EntityManager,
+// i.e. that does not embed *ecs.BaseWorld.
+var ErrNoEntityManager = errors.New("world has no entity manager")
+
+// MessageKind is the kind of a Message.
+type MessageKind int
+
+const (
+	// Input carries the client's input for the server, in Data.
+	Input MessageKind = iota
+	// Connected is received when a client joins. The server starts sending it snapshots, complete until
+	// it acknowledges one.
+	Connected
+	// Disconnected is received when a client leaves. The server stops sending it snapshots.
+	Disconnected
+)
+
+// Message is a message received from a client.
+type Message struct {
+	Kind   MessageKind
+	Client replication.ClientID
+	// Ack is the tick of the last snapshot the client applied, see replication.Server.Ack. Zero acknowledges nothing.
+	Ack uint64
+	// Data is the input of an Input message, in the game's own encoding.
+	Data []byte
+}
+
+// Transport moves messages between the server and its clients over the game's network library.
+type Transport interface {
+	// Receive appends the messages received since the last call to dst and returns the extended slice.
+	// It must not block.
+	Receive(dst []Message) []Message
+	// Send sends a snapshot to a client.
+	Send(client replication.ClientID, snapshot []byte) error
+}
+
+// Inbox is the resource holding the messages received for the current tick. Messages are ordered by client,
+// and in the order they were received for each client, so that systems process them deterministically.
+type Inbox struct {
+	Messages []Message
+}
+
+// Inputs returns the data of the client's Input messages of the current tick.
+func (in *Inbox) Inputs(client replication.ClientID) [][]byte {
+	var inputs [][]byte
+	for _, message := range in.Messages {
+		if message.Kind == Input && message.Client == client {
+			inputs = append(inputs, message.Data)
+		}
+	}
+
+	return inputs
+}
+
+// Server runs a world as an authoritative server.
+type Server struct {
+	game        *ecs.Game
+	transport   Transport
+	replication *replication.Server
+	inbox       Inbox
+}
+
+// NewServer creates a deterministic headless Game with the world as its active world, replicating the
+// world's entities to the clients of transport with the component types of registry.
+func NewServer(world ecs.World, transport Transport, registry *replication.Registry) (*Server, error) {
+	s := &Server{
+		game:      ecs.NewGame(&ecs.GameConfig{Deterministic: true}),
+		transport: transport,
+	}
+	ecs.SetResource(s.game.Resources(), &s.inbox)
+
+	if err := s.game.SetActiveWorld(world); err != nil {
+		return nil, fmt.Errorf("serverecs.NewServer g.SetActiveWorld error: %w", err)
+	}
+
+	w, ok := world.(interface{ EntityManager() *ecs.EntityManager })
+	if !ok || w.EntityManager() == nil {
+		return nil, ErrNoEntityManager
+	}
+
+	s.replication = replication.NewServer(w.EntityManager(), registry)
+	s.game.BeforeUpdate(func(*ecs.Game) error { return s.receive() })
+	s.game.AfterUpdate(func(*ecs.Game) error { return s.broadcast() })
+
+	return s, nil
+}
+
+// Game returns the game running the world.
+func (s *Server) Game() *ecs.Game {
+	return s.game
+}
+
+// Replication returns the replication server, e.g. to set the clients' interest areas.
+func (s *Server) Replication() *replication.Server {
+	return s.replication
+}
+
+// Run runs the server at tps ticks per second until a system returns ebiten.Termination or an error,
+// see ecs.Game.RunHeadless.
+func (s *Server) Run(tps int) error {
+	return s.game.RunHeadless(tps)
+}
+
+// RunWithContext runs the server like Run until ctx is cancelled, and then shuts the game down,
+// see ecs.Game.RunHeadlessWithContext.
+func (s *Server) RunWithContext(ctx context.Context, tps int) error {
+	return s.game.RunHeadlessWithContext(ctx, tps)
+}
+
+// Run creates a Server for the world and runs it at tps ticks per second, see Server.Run.
+func Run(world ecs.World, tps int, transport Transport, registry *replication.Registry) error {
+	s, err := NewServer(world, transport, registry)
+	if err != nil {
+		return err
+	}
+
+	return s.Run(tps)
+}
+
+// receive fills the inbox with the messages of the tick and applies their connections and acknowledgements.
+func (s *Server) receive() error {
+	s.inbox.Messages = s.transport.Receive(s.inbox.Messages[:0])
+	slices.SortStableFunc(s.inbox.Messages, func(a, b Message) int { return cmp.Compare(a.Client, b.Client) })
+
+	for _, message := range s.inbox.Messages {
+		switch message.Kind {
+		case Connected:
+			s.replication.AddClient(message.Client)
+		case Disconnected:
+			s.replication.RemoveClient(message.Client)
+			continue
+		}
+
+		if message.Ack == 0 {
+			continue
+		}
+
+		if err := s.replication.Ack(message.Client, message.Ack); err != nil && !errors.Is(err, replication.ErrUnknownClient) {
+			return fmt.Errorf("serverecs.Server.receive Ack error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// broadcast sends the snapshot of the tick to every client.
+func (s *Server) broadcast() error {
+	tick := s.game.Time().Tick()
+	for _, client := range s.replication.Clients() {
+		snapshot, err := s.replication.Snapshot(client, tick)
+		if err != nil {
+			return fmt.Errorf("serverecs.Server.broadcast Snapshot error: %w", err)
+		}
+
+		if err := s.transport.Send(client, snapshot); err != nil {
+			return fmt.Errorf("serverecs.Server.broadcast Send %d error: %w", client, err)
+		}
+	}
+
+	return nil
+}
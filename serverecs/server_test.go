@@ -0,0 +1,108 @@
+package serverecs_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/replication"
+	"github.com/samix73/ebiten-ecs/serverecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+// loopback is a Transport delivering messages from one in-process client.
+type loopback struct {
+	pending []serverecs.Message
+	client  *replication.Client
+	ticks   []uint64
+}
+
+func (l *loopback) Receive(dst []serverecs.Message) []serverecs.Message {
+	dst = append(dst, l.pending...)
+	l.pending = l.pending[:0]
+
+	return dst
+}
+
+func (l *loopback) Send(client replication.ClientID, snapshot []byte) error {
+	tick, err := l.client.Apply(snapshot)
+	if err != nil {
+		return err
+	}
+
+	l.ticks = append(l.ticks, tick)
+	// The client acknowledges the snapshot and sends its next input.
+	l.pending = append(l.pending, serverecs.Message{Kind: serverecs.Input, Client: client, Ack: tick, Data: []byte("right")})
+
+	return nil
+}
+
+// matchWorld spawns a player for every connected client and moves it with its inputs.
+type matchWorld struct {
+	*ecs.BaseWorld
+
+	players map[replication.ClientID]ecs.EntityID
+}
+
+func (w *matchWorld) Init(g *ecs.Game) error {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+	w.players = make(map[replication.ClientID]ecs.EntityID)
+
+	if err := sm.Add(ecs.NewSystem(ecs.SystemOptions{}, func(s *ecs.FuncSystem) error {
+		inbox := ecs.MustGetResource[serverecs.Inbox](s.Game().Resources())
+		for _, message := range inbox.Messages {
+			if message.Kind == serverecs.Connected {
+				player := em.NewEntity()
+				ecs.AddComponent[replication.Replicated](em, player)
+				ecs.AddComponent[transform.Transform](em, player)
+				w.players[message.Client] = player
+			}
+		}
+
+		for client, player := range w.players {
+			for _, input := range inbox.Inputs(client) {
+				if string(input) == "right" {
+					ecs.MustGetComponent[transform.Transform](em, player).Translate(1, 0)
+				}
+			}
+		}
+
+		if s.Game().Time().Tick() == 5 {
+			return ebiten.Termination
+		}
+
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+func TestServer(t *testing.T) {
+	registry := replication.NewRegistry()
+	replication.Register[transform.Transform](registry, "transform")
+
+	clientEM := ecs.NewEntityManager()
+	transport := &loopback{
+		client:  replication.NewClient(clientEM, registry),
+		pending: []serverecs.Message{{Kind: serverecs.Connected, Client: 7}},
+	}
+
+	world := &matchWorld{}
+	require.NoError(t, serverecs.Run(world, 0, transport, registry))
+
+	// The game stopped during tick 5, before its snapshot.
+	assert.Equal(t, []uint64{1, 2, 3, 4}, transport.ticks)
+
+	local, ok := transport.client.LocalID(world.players[7])
+	require.True(t, ok)
+	// Inputs sent after the snapshots of ticks 1 to 3 were applied in ticks 2 to 4.
+	assert.Equal(t, f64.Vec2{3, 0}, ecs.MustGetComponent[transform.Transform](clientEM, local).Position)
+}
@@ -0,0 +1,76 @@
+package ecs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ShutdownHook is called by Game.Shutdown before the worlds are torn down.
+type ShutdownHook func(g *Game) error
+
+// Context returns the context of the game, cancelled when the game shuts down, so that systems can
+// stop their background work, such as asset loads and network connections, see BaseSystem.Context.
+func (g *Game) Context() context.Context {
+	return g.ctx
+}
+
+// RunWithContext runs the game like Start, until it ends or ctx is cancelled, and then shuts it down,
+// see Shutdown. The context of the game is derived from ctx.
+func (g *Game) RunWithContext(ctx context.Context) error {
+	g.bindContext(ctx)
+
+	return errors.Join(g.Start(), g.Shutdown())
+}
+
+// RunHeadlessWithContext runs the game like RunHeadless, until it ends or ctx is cancelled, and then
+// shuts it down, see Shutdown. The context of the game is derived from ctx.
+func (g *Game) RunHeadlessWithContext(ctx context.Context, tps int) error {
+	g.bindContext(ctx)
+
+	return errors.Join(g.RunHeadless(tps), g.Shutdown())
+}
+
+// OnShutdown registers a hook called by Shutdown while the worlds are still running, e.g. to write an autosave.
+// It returns a function that removes the hook.
+func (g *Game) OnShutdown(hook ShutdownHook) (remove func()) {
+	return g.shutdown.add(hook)
+}
+
+// Shutdown stops the game cleanly: the OnShutdown hooks run, then every world is stopped and torn down,
+// the added worlds and the world stack from the top, and the context of the game is cancelled.
+// The errors of the hooks are returned, after all of them ran.
+func (g *Game) Shutdown() error {
+	var errs []error
+	for _, entry := range g.shutdown.all() {
+		if err := entry.fn(g); err != nil {
+			errs = append(errs, fmt.Errorf("ecs.Game.Shutdown hook error: %w", err))
+		}
+	}
+
+	for _, layer := range slices.Backward(g.layers) {
+		stopWorld(layer.world)
+		layer.world.Teardown()
+	}
+	g.layers = nil
+
+	if active := g.ActiveWorld(); active != nil {
+		stopWorld(active)
+	}
+
+	for _, world := range slices.Backward(g.worlds) {
+		world.Teardown()
+	}
+	g.worlds = nil
+
+	g.cancel()
+
+	return errors.Join(errs...)
+}
+
+// bindContext derives the context of the game from parent.
+func (g *Game) bindContext(parent context.Context) {
+	g.cancel()
+	g.ctx, g.cancel = context.WithCancel(parent)
+}
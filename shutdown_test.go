@@ -0,0 +1,47 @@
+package ecs_test
+
+import (
+	"context"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithContext(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+	require.NoError(t, game.AddWorld(&hookWorld{events: &events, name: "ui"}, 1))
+	require.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "gameplay"}))
+	require.NoError(t, game.PushWorld(&hookWorld{events: &events, name: "pause"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := 0
+	game.AfterUpdate(func(*ecs.Game) error {
+		if ticks++; ticks == 3 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	game.OnShutdown(func(*ecs.Game) error {
+		events = append(events, "autosave")
+		return nil
+	})
+
+	events = nil
+	require.NoError(t, game.RunHeadlessWithContext(ctx, 0))
+	assert.Equal(t, 3, ticks)
+	assert.Equal(t, []string{
+		"pause:update", "ui:update",
+		"pause:update", "ui:update",
+		"pause:update", "ui:update",
+		"autosave",
+		"ui:stop", "ui:teardown",
+		"pause:stop", "pause:teardown", "gameplay:teardown",
+	}, events)
+	assert.Error(t, game.Context().Err())
+	assert.Nil(t, game.ActiveWorld())
+}
@@ -0,0 +1,280 @@
+package ecs
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Snapshot is an in-memory copy of the entities, components, tags, relations, generations and active states of an EntityManager,
+// taken with EntityManager.Snapshot and restored with EntityManager.Restore, e.g. for rollback netcode
+// or undo. It is opaque and can only be restored into the EntityManager it was taken from.
+type Snapshot struct {
+	em       *EntityManager
+	entities map[EntityID]struct{}
+	inactive map[EntityID]struct{}
+	// generations are the generations of the recycled entities, see Ref.
+	generations map[EntityID]uint32
+	tags        map[Tag][]EntityID
+	// relations maps each relation type to the targets of every source.
+	relations map[reflect.Type]map[EntityID][]EntityID
+	storages  map[reflect.Type]storageSnapshot
+
+	// maskIDs and masks are the component masks of the entities, sharing a single backing array.
+	maskIDs []EntityID
+	masks   []componentMask
+}
+
+// storageSnapshot is a copy of the components of a storage, in storage order.
+type storageSnapshot struct {
+	entityIDs []EntityID
+	values    any
+}
+
+// Snapshot copies the state of the EntityManager. Components are copied by value, so the slices,
+// maps and pointers they hold are shared with the snapshot: replace them instead of modifying them
+// in place if the changes must be rolled back.
+//
+// The cost is a copy of every component, so snapshots of worlds with a few thousand entities
+// take well under a millisecond.
+func (em *EntityManager) Snapshot() *Snapshot {
+	s := &Snapshot{
+		em:          em,
+		entities:    maps.Clone(em.entities),
+		maskIDs:     make([]EntityID, 0, len(em.entityMasks)),
+		masks:       make([]componentMask, 0, len(em.entityMasks)),
+		inactive:    maps.Clone(em.inactive),
+		generations: maps.Clone(em.generations),
+		tags:        make(map[Tag][]EntityID, len(em.tags)),
+		storages:    make(map[reflect.Type]storageSnapshot, len(em.componentContainers)),
+	}
+
+	words := 0
+	for _, mask := range em.entityMasks {
+		words += len(mask)
+	}
+
+	backing := make(componentMask, 0, words)
+	for entityID, mask := range em.entityMasks {
+		start := len(backing)
+		backing = append(backing, mask...)
+		s.maskIDs = append(s.maskIDs, entityID)
+		s.masks = append(s.masks, backing[start:len(backing):len(backing)])
+	}
+
+	for tag, set := range em.tags {
+		if len(set.entityIDs) > 0 {
+			s.tags[tag] = slices.Clone(set.entityIDs)
+		}
+	}
+
+	if len(em.relations) > 0 {
+		s.relations = make(map[reflect.Type]map[EntityID][]EntityID, len(em.relations))
+		for relationType, store := range em.relations {
+			targets := make(map[EntityID][]EntityID, len(store.targets))
+			for source, set := range store.targets {
+				targets[source] = slices.Clone(set.entityIDs)
+			}
+			s.relations[relationType] = targets
+		}
+	}
+
+	for componentType, storage := range em.componentContainers {
+		if storage.Count() == 0 {
+			continue
+		}
+
+		s.storages[componentType] = storageSnapshot{
+			entityIDs: slices.Clone(storage.ids()),
+			values:    storage.save(),
+		}
+	}
+
+	return s
+}
+
+// Restore returns the EntityManager to the state of the snapshot. Entities created since are removed
+// and removed entities come back with their IDs. Pointers to components of entities that exist both
+// before and after the restore stay valid and see the restored values.
+// Restore panics if the snapshot was taken from another EntityManager.
+//
+// State kept outside the EntityManager, such as Pool free lists and the command buffer, is not restored.
+func (em *EntityManager) Restore(s *Snapshot) {
+	defer em.beginStructural("EntityManager.Restore")()
+
+	if s.em != em {
+		panic("ecs.EntityManager.Restore: snapshot was taken from another EntityManager")
+	}
+
+	em.entities = maps.Clone(s.entities)
+	em.inactive = maps.Clone(s.inactive)
+	em.generations = maps.Clone(s.generations)
+
+	// Reuse the masks of the entities that still exist, as most do in a rollback.
+	for entityID := range em.entityMasks {
+		if _, ok := s.entities[entityID]; !ok {
+			delete(em.entityMasks, entityID)
+		}
+	}
+	for i, entityID := range s.maskIDs {
+		em.entityMasks[entityID] = append(em.entityMasks[entityID][:0], s.masks[i]...)
+	}
+
+	em.tags = make(map[Tag]*tagSet, len(s.tags))
+	for tag, entityIDs := range s.tags {
+		set := &tagSet{entityIDs: slices.Clone(entityIDs), index: make(map[EntityID]int, len(entityIDs))}
+		for i, entityID := range entityIDs {
+			set.index[entityID] = i
+		}
+		em.tags[tag] = set
+	}
+
+	em.relations = nil
+	if len(s.relations) > 0 {
+		em.relations = make(map[reflect.Type]*relationStore, len(s.relations))
+		for relationType, targets := range s.relations {
+			store := newRelationStore()
+			for source, entityIDs := range targets {
+				for _, target := range entityIDs {
+					store.add(source, target)
+				}
+			}
+			em.relations[relationType] = store
+		}
+	}
+
+	for componentType, storage := range em.componentContainers {
+		storage.load(s.storages[componentType])
+	}
+
+	em.InvalidateSpatialIndexes()
+}
+
+// valueCopier copies the components of a ComponentContainer by value.
+type valueCopier interface {
+	save(components []any) any
+	load(values any, index int, component any)
+}
+
+// typedCopier copies components of type C into a []C, with a single allocation.
+type typedCopier[C any] struct{}
+
+func (typedCopier[C]) save(components []any) any {
+	values := make([]C, len(components))
+	for i, component := range components {
+		values[i] = *component.(*C)
+	}
+
+	return values
+}
+
+func (typedCopier[C]) load(values any, index int, component any) {
+	*component.(*C) = values.([]C)[index]
+}
+
+// reflectCopier copies components of containers created with NewComponentContainer, whose type is unknown.
+type reflectCopier struct{}
+
+func (reflectCopier) save(components []any) any {
+	values := make([]reflect.Value, len(components))
+	for i, component := range components {
+		value := reflect.ValueOf(component).Elem()
+		values[i] = reflect.New(value.Type()).Elem()
+		values[i].Set(value)
+	}
+
+	return values
+}
+
+func (reflectCopier) load(values any, index int, component any) {
+	reflect.ValueOf(component).Elem().Set(values.([]reflect.Value)[index])
+}
+
+func (c *ComponentContainer) save() any {
+	copier := c.copier
+	if copier == nil {
+		copier = reflectCopier{}
+	}
+
+	return copier.save(c.components)
+}
+
+// load replaces the components with the saved ones, reusing the components of the entities that
+// have one in both, so pointers to them stay valid.
+func (c *ComponentContainer) load(s storageSnapshot) {
+	copier := c.copier
+	if copier == nil {
+		copier = reflectCopier{}
+	}
+
+	if slices.Equal(c.entityIDs, s.entityIDs) {
+		for i, component := range c.components {
+			copier.load(s.values, i, component)
+		}
+
+		return
+	}
+
+	components := make([]any, len(s.entityIDs), max(len(s.entityIDs), cap(c.components)))
+	lookup := make(map[EntityID]int, len(s.entityIDs))
+
+	for i, entityID := range s.entityIDs {
+		component, ok := c.Get(entityID)
+		if ok {
+			// Keep the component out of the loop below, which recycles the others.
+			c.components[c.componentLookupMap[entityID]] = nil
+		} else {
+			component = c.pool.Get()
+		}
+
+		copier.load(s.values, i, component)
+		components[i] = component
+		lookup[entityID] = i
+	}
+
+	for _, component := range c.components {
+		if component == nil {
+			continue
+		}
+
+		if typedComponent, ok := component.(Component); ok {
+			typedComponent.Reset()
+		}
+		c.pool.Put(component)
+	}
+
+	c.components = components
+	c.entityIDs = append(c.entityIDs[:0], s.entityIDs...)
+	c.componentLookupMap = lookup
+}
+
+func (c *InlineContainer[C]) save() any {
+	values := make([]C, len(c.entityIDs))
+	for i := range values {
+		values[i] = *c.at(i)
+	}
+
+	return values
+}
+
+func (c *InlineContainer[C]) load(s storageSnapshot) {
+	values, _ := s.values.([]C)
+
+	if slices.Equal(c.entityIDs, s.entityIDs) {
+		for i := range values {
+			*c.at(i) = values[i]
+		}
+
+		return
+	}
+
+	c.entityIDs = c.entityIDs[:0]
+	clear(c.componentLookupMap)
+	c.Reserve(len(s.entityIDs))
+
+	for i, entityID := range s.entityIDs {
+		*c.at(i) = values[i]
+		c.entityIDs = append(c.entityIDs, entityID)
+		c.componentLookupMap[entityID] = i
+	}
+}
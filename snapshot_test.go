@@ -0,0 +1,74 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	em := ecs.NewEntityManager()
+	require.NoError(t, ecs.UseInlineStorage[CameraComponent](em, 0))
+
+	player := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, player).Position = f64.Vec2{1, 2}
+	ecs.AddComponent[CameraComponent](em, player).Zoom = 2
+	em.Tag(player, "player")
+
+	enemy := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, enemy).Position = f64.Vec2{10, 20}
+	em.SetActive(enemy, false)
+
+	snapshot := em.Snapshot()
+	want := ecs.Checksum(em)
+	playerTransform := ecs.MustGetComponent[TransformComponent](em, player)
+
+	// Change everything the snapshot covers.
+	playerTransform.Position = f64.Vec2{5, 5}
+	ecs.MustGetComponent[CameraComponent](em, player).Zoom = 3
+	em.Untag(player, "player")
+	em.Remove(enemy)
+	spawned := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, spawned)
+	ecs.AddComponent[NameComponent](em, player).Name = "new"
+
+	em.Restore(snapshot)
+
+	assert.Equal(t, want, ecs.Checksum(em))
+	assert.False(t, em.Exists(spawned))
+	assert.True(t, em.Exists(enemy))
+	assert.False(t, em.Active(enemy))
+	assert.True(t, em.HasTag(player, "player"))
+	assert.False(t, ecs.HasComponent[NameComponent](em, player))
+	assert.Equal(t, 2.0, ecs.MustGetComponent[CameraComponent](em, player).Zoom)
+	assert.Equal(t, f64.Vec2{10, 20}, ecs.MustGetComponent[TransformComponent](em, enemy).Position)
+
+	// Pointers to components that survived the rollback see the restored values.
+	assert.Same(t, playerTransform, ecs.MustGetComponent[TransformComponent](em, player))
+	assert.Equal(t, f64.Vec2{1, 2}, playerTransform.Position)
+
+	// A snapshot can be restored several times.
+	playerTransform.Position = f64.Vec2{7, 7}
+	em.Restore(snapshot)
+	assert.Equal(t, want, ecs.Checksum(em))
+	assert.Equal(t, 1, ecs.Count(ecs.Query[CameraComponent](em)))
+
+	assert.Panics(t, func() { ecs.NewEntityManager().Restore(snapshot) })
+}
+
+func BenchmarkSnapshotRestore(b *testing.B) {
+	em := ecs.NewEntityManager()
+	for range 5000 {
+		entityID := em.NewEntity()
+		ecs.AddComponent[TransformComponent](em, entityID)
+		ecs.AddComponent[CameraComponent](em, entityID)
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		em.Restore(em.Snapshot())
+	}
+}
@@ -0,0 +1,244 @@
+package ecs
+
+import (
+	"iter"
+	"math"
+	"reflect"
+
+	"golang.org/x/image/math/f64"
+)
+
+// DefaultSpatialCellSize is the size of the cells of spatial indexes, in world units,
+// unless changed with SetSpatialCellSize.
+const DefaultSpatialCellSize = 64
+
+// Positioned is implemented by components with a position in world space, such as transform.Transform,
+// so that QueryWithinRadius and QueryWithinBounds can index them.
+type Positioned interface {
+	Point() f64.Vec2
+}
+
+// spatialCell is the coordinates of a cell of a spatialIndex.
+type spatialCell [2]int
+
+// spatialIndex is a uniform grid of the entities with a component type, by the cell of their position.
+// It is rebuilt lazily by the first query after it is invalidated. The spatial indexes of a concurrent
+// EntityManager are only built and rebuilt under its structural lock, see spatialIndexWritable.
+type spatialIndex struct {
+	cellSize float64
+	cells    map[spatialCell][]EntityID
+	// entityCells is the cell each entity was indexed in, so that full scans find the same entities as the cells.
+	entityCells map[EntityID]spatialCell
+	stale       bool
+	// point returns the position of a component, set by the first query.
+	point func(component any) f64.Vec2
+}
+
+func (idx *spatialIndex) cellOf(p f64.Vec2) spatialCell {
+	return spatialCell{int(math.Floor(p[0] / idx.cellSize)), int(math.Floor(p[1] / idx.cellSize))}
+}
+
+// rebuild indexes the entities of the storage, reusing the cells of the previous build.
+func (idx *spatialIndex) rebuild(storage componentStorage) {
+	for cell, entityIDs := range idx.cells {
+		if len(entityIDs) == 0 {
+			delete(idx.cells, cell)
+		} else {
+			idx.cells[cell] = entityIDs[:0]
+		}
+	}
+
+	clear(idx.entityCells)
+	for i := range storage.Count() {
+		entityID, component := storage.entry(i)
+		cell := idx.cellOf(idx.point(component))
+		idx.cells[cell] = append(idx.cells[cell], entityID)
+		idx.entityCells[entityID] = cell
+	}
+
+	idx.stale = false
+}
+
+// SetSpatialCellSize sets the cell size of the spatial index of component C, which should be about the size
+// of the areas usually queried: smaller cells make queries visit fewer entities but more cells.
+// It panics if size is not positive.
+func SetSpatialCellSize[C any](em *EntityManager, size float64) {
+	if !(size > 0) {
+		panic("ecs.SetSpatialCellSize: cell size must be positive")
+	}
+
+	defer em.lockStructure()()
+
+	idx := em.spatialIndexOf(reflect.TypeFor[C]())
+	idx.cellSize = size
+	idx.stale = true
+}
+
+// InvalidateSpatialIndexes makes the next spatial queries re-index the entities. The SystemManager
+// invalidates the indexes after each system's Update, so this is only needed to query the new positions
+// of entities moved earlier in the same Update, or when updating entities without a SystemManager.
+// Adding components invalidates the indexes as well.
+func (em *EntityManager) InvalidateSpatialIndexes() {
+	defer em.lockStructure()()
+
+	for _, idx := range em.spatialIndexes {
+		idx.stale = true
+	}
+}
+
+// spatialIndexWritable reports whether spatial queries can build and rebuild the spatial indexes: always
+// in a plain EntityManager, but in a concurrent one only while the owner holds the structural lock, as
+// readers inside Read may be using them otherwise. Spatial queries scan the entities when they cannot.
+func (em *EntityManager) spatialIndexWritable() bool {
+	return em.structure == nil || em.structureDepth > 0
+}
+
+// rebuildSpatialIndexes rebuilds the stale spatial indexes of a concurrent EntityManager, whose owner holds
+// the structural lock, so that the spatial queries inside Read use them.
+func (em *EntityManager) rebuildSpatialIndexes() {
+	if em.structure == nil {
+		return
+	}
+
+	for componentType, idx := range em.spatialIndexes {
+		storage, ok := em.componentContainers[componentType]
+		if ok && idx.stale && idx.point != nil {
+			idx.rebuild(storage)
+		}
+	}
+}
+
+func (em *EntityManager) spatialIndexOf(componentType reflect.Type) *spatialIndex {
+	idx, ok := em.spatialIndexes[componentType]
+	if !ok {
+		if em.spatialIndexes == nil {
+			em.spatialIndexes = make(map[reflect.Type]*spatialIndex)
+		}
+
+		idx = &spatialIndex{
+			cellSize:    DefaultSpatialCellSize,
+			cells:       make(map[spatialCell][]EntityID),
+			entityCells: make(map[EntityID]spatialCell),
+			stale:       true,
+		}
+		em.spatialIndexes[componentType] = idx
+	}
+
+	return idx
+}
+
+// invalidateSpatialIndex marks the spatial index of the component type, if any, as stale.
+func (em *EntityManager) invalidateSpatialIndex(componentType reflect.Type) {
+	if idx, ok := em.spatialIndexes[componentType]; ok {
+		idx.stale = true
+	}
+}
+
+// QueryWithinRadius returns the active entities with a component C positioned within radius of center,
+// such as the enemies in range of a tower:
+//
+//	for entityID := range ecs.QueryWithinRadius[transform.Transform](em, tower.Position, 200) { ... }
+//
+// Candidates are taken from a grid of the entities by position, built on first use, so only the entities
+// in the cells overlapping the circle are checked. See InvalidateSpatialIndexes for when positions are indexed.
+func QueryWithinRadius[C any, P interface {
+	*C
+	Positioned
+}](em *EntityManager, center f64.Vec2, radius float64) iter.Seq[EntityID] {
+	minPoint := f64.Vec2{center[0] - radius, center[1] - radius}
+	maxPoint := f64.Vec2{center[0] + radius, center[1] + radius}
+
+	return querySpatial[C, P](em, minPoint, maxPoint, func(p f64.Vec2) bool {
+		dx, dy := p[0]-center[0], p[1]-center[1]
+		return dx*dx+dy*dy <= radius*radius
+	})
+}
+
+// QueryWithinBounds returns the active entities with a component C positioned within the rectangle
+// from minPoint to maxPoint, edges included, such as the entities visible to a camera.
+// See QueryWithinRadius.
+func QueryWithinBounds[C any, P interface {
+	*C
+	Positioned
+}](em *EntityManager, minPoint, maxPoint f64.Vec2) iter.Seq[EntityID] {
+	return querySpatial[C, P](em, minPoint, maxPoint, func(p f64.Vec2) bool {
+		return p[0] >= minPoint[0] && p[0] <= maxPoint[0] && p[1] >= minPoint[1] && p[1] <= maxPoint[1]
+	})
+}
+
+// querySpatial yields the active entities with a component C in the cells overlapping the rectangle
+// from minPoint to maxPoint, whose position satisfies within.
+func querySpatial[C any, P interface {
+	*C
+	Positioned
+}](em *EntityManager, minPoint, maxPoint f64.Vec2, within func(f64.Vec2) bool) iter.Seq[EntityID] {
+	return func(yield func(EntityID) bool) {
+		componentType := reflect.TypeFor[C]()
+
+		storage, ok := em.componentContainers[componentType]
+		if !ok || !(minPoint[0] <= maxPoint[0] && minPoint[1] <= maxPoint[1]) {
+			return
+		}
+
+		idx, indexed := em.spatialIndexes[componentType]
+		if em.spatialIndexWritable() {
+			idx, indexed = em.spatialIndexOf(componentType), true
+			if idx.point == nil {
+				idx.point = func(component any) f64.Vec2 { return P(component.(*C)).Point() }
+			}
+			if idx.stale {
+				idx.rebuild(storage)
+			}
+		}
+
+		// match checks the current position, as the entity may have moved or lost its component since it was indexed.
+		match := func(entityID EntityID) bool {
+			if _, inactive := em.inactive[entityID]; inactive {
+				return false
+			}
+
+			component, ok := storage.Get(entityID)
+			return ok && within(P(component.(*C)).Point())
+		}
+
+		if !indexed || idx.stale {
+			for _, entityID := range storage.ids() {
+				if match(entityID) && !yield(entityID) {
+					return
+				}
+			}
+
+			return
+		}
+
+		minCell, maxCell := idx.cellOf(minPoint), idx.cellOf(maxPoint)
+		width, height := float64(maxCell[0]-minCell[0]+1), float64(maxCell[1]-minCell[1]+1)
+
+		// Scan the entities instead of the cells when the area covers more cells than there are entities,
+		// keeping those indexed in the cells, so that the results do not depend on the size of the area.
+		if width*height > float64(storage.Count()) {
+			for _, entityID := range storage.ids() {
+				cell, indexed := idx.entityCells[entityID]
+				if !indexed || cell[0] < minCell[0] || cell[0] > maxCell[0] || cell[1] < minCell[1] || cell[1] > maxCell[1] {
+					continue
+				}
+
+				if match(entityID) && !yield(entityID) {
+					return
+				}
+			}
+
+			return
+		}
+
+		for x := minCell[0]; x <= maxCell[0]; x++ {
+			for y := minCell[1]; y <= maxCell[1]; y++ {
+				for _, entityID := range idx.cells[spatialCell{x, y}] {
+					if match(entityID) && !yield(entityID) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
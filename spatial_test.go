@@ -0,0 +1,113 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func newPositionedEntity(em *ecs.EntityManager, x, y float64) ecs.EntityID {
+	entityID := em.NewEntity()
+	ecs.AddComponent[transform.Transform](em, entityID).Position = f64.Vec2{x, y}
+
+	return entityID
+}
+
+func TestQueryWithinRadius(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	near := newPositionedEntity(em, 10, 10)
+	edge := newPositionedEntity(em, 100, 0)
+	corner := newPositionedEntity(em, 90, 90)
+	far := newPositionedEntity(em, 500, -500)
+
+	within := slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{0, 0}, 100))
+	assert.ElementsMatch(t, []ecs.EntityID{near, edge}, within, "the corner of the circle's bounds is outside")
+
+	// Moves are only indexed once the index is invalidated, but the exact check always uses the current position.
+	ecs.MustGetComponent[transform.Transform](em, far).Position = f64.Vec2{0, 0}
+	ecs.MustGetComponent[transform.Transform](em, near).Position = f64.Vec2{200, 200}
+	assert.ElementsMatch(t, []ecs.EntityID{edge}, slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{0, 0}, 100)))
+	assert.Empty(t, slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{0, 0}, 10)),
+		"small areas, which visit the cells rather than every entity, see the same index")
+
+	em.InvalidateSpatialIndexes()
+	assert.ElementsMatch(t, []ecs.EntityID{edge, far}, slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{0, 0}, 100)))
+
+	added := newPositionedEntity(em, 1, 1)
+	em.SetActive(edge, false)
+	ecs.RemoveComponent[transform.Transform](em, far)
+	assert.ElementsMatch(t, []ecs.EntityID{added}, slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{0, 0}, 100)),
+		"added components are indexed, inactive entities and removed components are skipped")
+
+	ecs.SetSpatialCellSize[transform.Transform](em, 8)
+	assert.ElementsMatch(t, []ecs.EntityID{added, corner}, slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{45, 45}, 65)))
+	assert.Panics(t, func() { ecs.SetSpatialCellSize[transform.Transform](em, 0) })
+}
+
+func TestQueryWithinBounds(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	inside := newPositionedEntity(em, -50, 20)
+	onEdge := newPositionedEntity(em, 320, 240)
+	newPositionedEntity(em, 321, 0)
+
+	visible := slices.Collect(ecs.QueryWithinBounds[transform.Transform](em, f64.Vec2{-64, 0}, f64.Vec2{320, 240}))
+	assert.ElementsMatch(t, []ecs.EntityID{inside, onEdge}, visible)
+
+	huge := slices.Collect(ecs.QueryWithinBounds[transform.Transform](em, f64.Vec2{-1e9, -1e9}, f64.Vec2{1e9, 1e9}))
+	assert.Len(t, huge, 3, "areas larger than the entities are scanned")
+
+	assert.Empty(t, slices.Collect(ecs.QueryWithinBounds[transform.Transform](em, f64.Vec2{10, 10}, f64.Vec2{0, 0})))
+}
+
+func TestSpatialIndexSystemManager(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{}))
+
+	mover := newPositionedEntity(em, 0, 0)
+	require.Len(t, slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{0, 0}, 1)), 1)
+
+	var found []ecs.EntityID
+	sm.AddFunc(0, func(em *ecs.EntityManager, _ *ecs.Game) error {
+		ecs.MustGetComponent[transform.Transform](em, mover).Translate(1000, 0)
+		return nil
+	})
+	sm.AddFunc(1, func(em *ecs.EntityManager, _ *ecs.Game) error {
+		found = slices.Collect(ecs.QueryWithinRadius[transform.Transform](em, f64.Vec2{1000, 0}, 1))
+		return nil
+	})
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []ecs.EntityID{mover}, found, "the index is invalidated after each system")
+}
+
+func BenchmarkQueryWithinBounds(b *testing.B) {
+	em := ecs.NewEntityManager()
+	for i := range 10000 {
+		newPositionedEntity(em, float64(i%100)*32, float64(i/100)*32)
+	}
+
+	b.Run("Spatial", func(b *testing.B) {
+		for b.Loop() {
+			for range ecs.QueryWithinBounds[transform.Transform](em, f64.Vec2{0, 0}, f64.Vec2{320, 240}) {
+			}
+		}
+	})
+
+	b.Run("Filter", func(b *testing.B) {
+		for b.Loop() {
+			for entityID := range ecs.Query[transform.Transform](em) {
+				p := ecs.MustGetComponent[transform.Transform](em, entityID).Position
+				if p[0] >= 0 && p[0] <= 320 && p[1] >= 0 && p[1] <= 240 {
+					continue
+				}
+			}
+		}
+	})
+}
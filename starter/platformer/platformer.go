@@ -0,0 +1,215 @@
+// Package platformer is a starter world for side-scrolling platformers: a character that runs with
+// the arrow keys, A/D or a gamepad and jumps with Space or the bottom face button, standing on the
+// objects of a Tiled map marked with the "collision" property.
+//
+//	func main() {
+//		level, err := tilemap.Load(os.DirFS("assets"), "level1.tmx")
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//
+//		g := ecs.NewGame(&ecs.GameConfig{Title: "Platformer", ScreenWidth: 320, ScreenHeight: 240})
+//		if err := g.SetActiveWorld(platformer.NewWorld(platformer.Config{Map: level})); err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := g.Start(); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package platformer
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/animation"
+	"github.com/samix73/ebiten-ecs/audio"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/input"
+	"github.com/samix73/ebiten-ecs/physics"
+	"github.com/samix73/ebiten-ecs/starter"
+	"github.com/samix73/ebiten-ecs/tilemap"
+	"golang.org/x/image/math/f64"
+)
+
+// Actions bound by DefaultActions.
+const (
+	ActionLeft  = "left"
+	ActionRight = "right"
+	ActionJump  = "jump"
+)
+
+// DefaultActions binds running to the arrow keys, A/D and the gamepad d-pad,
+// and jumping to Space, the up arrow and the bottom face button.
+func DefaultActions() *input.Actions {
+	actions := input.NewActions()
+	actions.Bind(ActionLeft, input.Key(ebiten.KeyArrowLeft), input.Key(ebiten.KeyA), input.GamepadButton(ebiten.StandardGamepadButtonLeftLeft))
+	actions.Bind(ActionRight, input.Key(ebiten.KeyArrowRight), input.Key(ebiten.KeyD), input.GamepadButton(ebiten.StandardGamepadButtonLeftRight))
+	actions.Bind(ActionJump, input.Key(ebiten.KeySpace), input.Key(ebiten.KeyArrowUp), input.GamepadButton(ebiten.StandardGamepadButtonRightBottom))
+
+	return actions
+}
+
+// Config configures a platformer World. Zero fields use the defaults listed below.
+type Config struct {
+	// PlayerImage is the player's sprite. A 16x24 placeholder is used if it is nil.
+	PlayerImage *ebiten.Image
+	// PlayerStart is the initial position of the player.
+	PlayerStart f64.Vec2
+	// PlayerSpeed is the running speed in pixels per second, 120 by default.
+	PlayerSpeed float64
+	// JumpSpeed is the initial upward speed of a jump in pixels per second, 320 by default.
+	JumpSpeed float64
+	// Gravity is the downward acceleration in pixels per second squared, 900 by default.
+	Gravity float64
+
+	// Map is the level. Objects with the "collision" property are solid.
+	Map *tilemap.Map
+	// Spawner spawns the typed objects of Map.
+	Spawner *tilemap.Spawner
+
+	// Actions are the movement bindings, DefaultActions by default.
+	Actions *input.Actions
+
+	// Zoom is the zoom of the camera following the player, 1 by default.
+	Zoom float64
+	// NewPlayer plays the sounds and music of the world's audio.System, which is silent if it is nil.
+	NewPlayer audio.NewPlayerFunc
+}
+
+// Player marks the entity controlled by the actions.
+type Player struct {
+	// Speed is the running speed in pixels per second.
+	Speed float64
+	// JumpSpeed is the initial upward speed of a jump in pixels per second.
+	JumpSpeed float64
+}
+
+func (p *Player) Reset() {
+	p.Speed = 0
+	p.JumpSpeed = 0
+}
+
+// World is a platformer world with an input-driven player.
+type World struct {
+	*ecs.BaseWorld
+
+	cfg    Config
+	player ecs.EntityID
+}
+
+// NewWorld creates a platformer world from cfg.
+func NewWorld(cfg Config) *World {
+	return &World{cfg: cfg}
+}
+
+func (w *World) Init(g *ecs.Game) error {
+	if w.cfg.PlayerImage == nil {
+		w.cfg.PlayerImage = starter.PlaceholderImage(16, 24, starter.PlayerColor)
+	}
+
+	if w.cfg.PlayerSpeed == 0 {
+		w.cfg.PlayerSpeed = 120
+	}
+
+	if w.cfg.JumpSpeed == 0 {
+		w.cfg.JumpSpeed = 320
+	}
+
+	if w.cfg.Gravity == 0 {
+		w.cfg.Gravity = 900
+	}
+
+	if w.cfg.Actions == nil {
+		w.cfg.Actions = DefaultActions()
+	}
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+
+	inputSystem := input.NewSystem(ecs.NextSystemID(), 0, w.cfg.Actions)
+	inputSystem.SetPhase(ecs.PhasePreUpdate)
+
+	if err := sm.Add(
+		inputSystem,
+		NewControlSystem(ecs.NextSystemID(), 0, w.cfg.Actions),
+		physics.NewMovementSystem(ecs.NextSystemID(), 0, f64.Vec2{0, w.cfg.Gravity}),
+		animation.NewSystem(ecs.NextSystemID(), 10),
+	); err != nil {
+		return fmt.Errorf("platformer.World.Init sm.Add error: %w", err)
+	}
+
+	if err := starter.AddUISystem(sm); err != nil {
+		return fmt.Errorf("platformer.World.Init starter.AddUISystem error: %w", err)
+	}
+
+	if _, err := starter.AddAudioSystem(sm, w.cfg.NewPlayer); err != nil {
+		return fmt.Errorf("platformer.World.Init starter.AddAudioSystem error: %w", err)
+	}
+
+	if err := starter.AddRenderSystems(sm); err != nil {
+		return fmt.Errorf("platformer.World.Init starter.AddRenderSystems error: %w", err)
+	}
+
+	starter.SpawnMap(em, w.cfg.Map, w.cfg.Spawner)
+
+	w.player = starter.SpawnSprite(em, w.cfg.PlayerImage, w.cfg.PlayerStart)
+
+	player := ecs.AddComponent[Player](em, w.player)
+	player.Speed = w.cfg.PlayerSpeed
+	player.JumpSpeed = w.cfg.JumpSpeed
+	starter.FollowCamera(em, w.player, w.cfg.Zoom)
+
+	bounds := w.cfg.PlayerImage.Bounds()
+	collider := ecs.AddComponent[collision.Collider](em, w.player)
+	collider.Width = float64(bounds.Dx())
+	collider.Height = float64(bounds.Dy())
+
+	ecs.AddComponent[physics.Velocity](em, w.player)
+	ecs.AddComponent[physics.Gravity](em, w.player)
+	ecs.AddComponent[physics.Contacts](em, w.player)
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+// Player returns the player entity.
+func (w *World) Player() ecs.EntityID {
+	return w.player
+}
+
+// ControlSystem sets the physics.Velocity of Player entities from the actions.
+// Players jump only while their physics.Contacts report a floor.
+type ControlSystem struct {
+	*ecs.BaseSystem
+
+	actions *input.Actions
+}
+
+// NewControlSystem creates a ControlSystem reading actions.
+func NewControlSystem(id ecs.SystemID, priority int, actions *input.Actions) *ControlSystem {
+	return &ControlSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		actions:    actions,
+	}
+}
+
+func (s *ControlSystem) Update() error {
+	run := s.actions.Value(ActionRight) - s.actions.Value(ActionLeft)
+	jump := s.actions.JustPressed(ActionJump)
+
+	em := s.EntityManager()
+	for entityID := range ecs.Query3[Player, physics.Velocity, physics.Contacts](em) {
+		player := ecs.MustGetComponent[Player](em, entityID)
+		velocity := ecs.MustGetComponent[physics.Velocity](em, entityID)
+
+		velocity.Linear[0] = run * player.Speed
+		if jump && ecs.MustGetComponent[physics.Contacts](em, entityID).Floor {
+			velocity.Linear[1] = -player.JumpSpeed
+		}
+	}
+
+	return nil
+}
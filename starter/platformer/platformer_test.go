@@ -0,0 +1,67 @@
+package platformer_test
+
+import (
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/input"
+	"github.com/samix73/ebiten-ecs/physics"
+	"github.com/samix73/ebiten-ecs/starter/platformer"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestWorld(t *testing.T) {
+	jump := false
+
+	actions := input.NewActions()
+	actions.Bind(platformer.ActionRight, input.BindingFunc(func() float64 { return 1 }))
+	actions.Bind(platformer.ActionJump, input.BindingFunc(func() float64 {
+		if jump {
+			return 1
+		}
+		return 0
+	}))
+
+	world := platformer.NewWorld(platformer.Config{
+		PlayerStart: f64.Vec2{0, 50},
+		Actions:     actions,
+	})
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	require.NoError(t, game.SetActiveWorld(world))
+
+	em := world.EntityManager()
+
+	// A floor from x=-1000 to 1000 whose top is at y=100.
+	floor := em.NewEntity()
+	ecs.AddComponent[transform.Transform](em, floor).Position = f64.Vec2{-1000, 100}
+	collider := ecs.AddComponent[collision.Collider](em, floor)
+	collider.Width = 2000
+	collider.Height = 32
+	collider.Static = true
+
+	player := world.Player()
+	tr := ecs.MustGetComponent[transform.Transform](em, player)
+	velocity := ecs.MustGetComponent[physics.Velocity](em, player)
+	contacts := ecs.MustGetComponent[physics.Contacts](em, player)
+
+	for range 60 {
+		require.NoError(t, game.Update())
+	}
+
+	require.True(t, contacts.Floor)
+	assert.InDelta(t, 100-24, tr.Position[1], 1e-6, "the 24 pixel tall player stands on the floor")
+	assert.Greater(t, tr.Position[0], 0.0)
+
+	jump = true
+	require.NoError(t, game.Update())
+	assert.Less(t, velocity.Linear[1], 0.0)
+
+	require.NoError(t, game.Update())
+	assert.False(t, contacts.Floor)
+	assert.Less(t, tr.Position[1], 100-24.0)
+}
@@ -0,0 +1,114 @@
+// Package starter holds helpers shared by the genre starter worlds in its subpackages,
+// which assemble the built-in input, physics, camera, audio, UI and render systems into a playable
+// world with sensible defaults:
+//
+//   - topdown: a character moving in eight directions over an optional tilemap.
+//   - platformer: a character running and jumping on the solid objects of a tilemap.
+package starter
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/audio"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/tilemap"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/samix73/ebiten-ecs/ui"
+	"golang.org/x/image/math/f64"
+)
+
+// PlayerColor is the color of the placeholder player image.
+var PlayerColor = color.RGBA{R: 0xf0, G: 0xc0, B: 0x40, A: 0xff}
+
+// PlaceholderImage returns a width x height image filled with clr, used when no player image is configured.
+func PlaceholderImage(width, height int, clr color.Color) *ebiten.Image {
+	img := ebiten.NewImage(width, height)
+	img.Fill(clr)
+
+	return img
+}
+
+// SpawnMap spawns the layers, colliders and registered objects of m, if not nil.
+func SpawnMap(em *ecs.EntityManager, m *tilemap.Map, spawner *tilemap.Spawner) {
+	if m == nil {
+		return
+	}
+
+	if spawner == nil {
+		spawner = tilemap.NewSpawner()
+	}
+
+	spawner.Spawn(em, m)
+}
+
+// SpawnSprite creates an entity drawing img at position.
+func SpawnSprite(em *ecs.EntityManager, img *ebiten.Image, position f64.Vec2) ecs.EntityID {
+	entityID := em.NewEntity()
+
+	ecs.AddComponent[transform.Transform](em, entityID).Position = position
+	ecs.AddComponent[render.Sprite](em, entityID).Image = img
+
+	return entityID
+}
+
+// AddRenderSystems adds the tilemap and sprite render systems, drawing tile layers below sprites.
+func AddRenderSystems(sm *ecs.SystemManager) error {
+	tiles := tilemap.NewRenderSystem(ecs.NextSystemID(), 100)
+	sprites := render.NewRenderSystem(ecs.NextSystemID(), 100)
+	sprites.After(tiles.ID())
+
+	if err := sm.Add(tiles, sprites); err != nil {
+		return fmt.Errorf("starter.AddRenderSystems sm.Add error: %w", err)
+	}
+
+	return nil
+}
+
+// AddUISystem adds the ui.LayoutSystem, placing the HUD entities with a ui.RectTransform before they are drawn.
+func AddUISystem(sm *ecs.SystemManager) error {
+	if err := sm.Add(ui.NewLayoutSystem(ecs.NextSystemID(), 50)); err != nil {
+		return fmt.Errorf("starter.AddUISystem sm.Add error: %w", err)
+	}
+
+	return nil
+}
+
+// AddAudioSystem adds an audio.System playing through newPlayer, which registers itself as a resource of
+// the game when the world starts. Sounds and music are silent if newPlayer is nil.
+func AddAudioSystem(sm *ecs.SystemManager, newPlayer audio.NewPlayerFunc) (*audio.System, error) {
+	if newPlayer == nil {
+		newPlayer = func(io.Reader) (audio.Player, error) { return silentPlayer{}, nil }
+	}
+
+	system := audio.NewSystem(ecs.NextSystemID(), 50, newPlayer)
+	if err := sm.Add(system); err != nil {
+		return nil, fmt.Errorf("starter.AddAudioSystem sm.Add error: %w", err)
+	}
+
+	return system, nil
+}
+
+// silentPlayer is the audio.Player of AddAudioSystem without a NewPlayerFunc.
+type silentPlayer struct{}
+
+func (silentPlayer) Play()             {}
+func (silentPlayer) Pause()            {}
+func (silentPlayer) IsPlaying() bool   { return false }
+func (silentPlayer) Rewind() error     { return nil }
+func (silentPlayer) SetVolume(float64) {}
+func (silentPlayer) Close() error      { return nil }
+
+// FollowCamera makes the view follow the entity, with a render.Camera zoomed by zoom (1 if zero),
+// and makes the entity the audio.Listener of the spatial sounds.
+func FollowCamera(em *ecs.EntityManager, entityID ecs.EntityID, zoom float64) {
+	camera := ecs.AddComponent[render.Camera](em, entityID)
+	if zoom != 0 {
+		camera.Zoom = zoom
+	}
+
+	ecs.AddComponent[audio.Listener](em, entityID)
+}
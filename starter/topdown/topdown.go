@@ -0,0 +1,187 @@
+// Package topdown is a starter world for top-down games: a character moving in eight directions
+// with the arrow keys, WASD or a gamepad, drawn over an optional Tiled map.
+//
+//	func main() {
+//		g := ecs.NewGame(&ecs.GameConfig{Title: "Top-down", ScreenWidth: 320, ScreenHeight: 240})
+//		if err := g.SetActiveWorld(topdown.NewWorld(topdown.Config{})); err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := g.Start(); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package topdown
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/animation"
+	"github.com/samix73/ebiten-ecs/audio"
+	"github.com/samix73/ebiten-ecs/input"
+	"github.com/samix73/ebiten-ecs/starter"
+	"github.com/samix73/ebiten-ecs/tilemap"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// Actions bound by DefaultActions.
+const (
+	ActionLeft  = "left"
+	ActionRight = "right"
+	ActionUp    = "up"
+	ActionDown  = "down"
+)
+
+// DefaultActions binds the movement actions to the arrow keys, WASD and the gamepad d-pad.
+func DefaultActions() *input.Actions {
+	actions := input.NewActions()
+	actions.Bind(ActionLeft, input.Key(ebiten.KeyArrowLeft), input.Key(ebiten.KeyA), input.GamepadButton(ebiten.StandardGamepadButtonLeftLeft))
+	actions.Bind(ActionRight, input.Key(ebiten.KeyArrowRight), input.Key(ebiten.KeyD), input.GamepadButton(ebiten.StandardGamepadButtonLeftRight))
+	actions.Bind(ActionUp, input.Key(ebiten.KeyArrowUp), input.Key(ebiten.KeyW), input.GamepadButton(ebiten.StandardGamepadButtonLeftTop))
+	actions.Bind(ActionDown, input.Key(ebiten.KeyArrowDown), input.Key(ebiten.KeyS), input.GamepadButton(ebiten.StandardGamepadButtonLeftBottom))
+
+	return actions
+}
+
+// Config configures a top-down World. The zero value is a usable default.
+type Config struct {
+	// PlayerImage is the player's sprite. A 16x16 placeholder is used if it is nil.
+	PlayerImage *ebiten.Image
+	// PlayerStart is the initial position of the player.
+	PlayerStart f64.Vec2
+	// PlayerSpeed is the player's speed in pixels per second, 120 by default.
+	PlayerSpeed float64
+
+	// Map is an optional Tiled map drawn below the player.
+	Map *tilemap.Map
+	// Spawner spawns the typed objects of Map.
+	Spawner *tilemap.Spawner
+
+	// Actions are the movement bindings, DefaultActions by default.
+	Actions *input.Actions
+
+	// Zoom is the zoom of the camera following the player, 1 by default.
+	Zoom float64
+	// NewPlayer plays the sounds and music of the world's audio.System, which is silent if it is nil.
+	NewPlayer audio.NewPlayerFunc
+}
+
+// Player marks the entity controlled by the movement actions.
+type Player struct {
+	// Speed is the movement speed in pixels per second.
+	Speed float64
+}
+
+func (p *Player) Reset() {
+	p.Speed = 0
+}
+
+// World is a top-down world with an input-driven player.
+type World struct {
+	*ecs.BaseWorld
+
+	cfg    Config
+	player ecs.EntityID
+}
+
+// NewWorld creates a top-down world from cfg.
+func NewWorld(cfg Config) *World {
+	return &World{cfg: cfg}
+}
+
+func (w *World) Init(g *ecs.Game) error {
+	if w.cfg.PlayerImage == nil {
+		w.cfg.PlayerImage = starter.PlaceholderImage(16, 16, starter.PlayerColor)
+	}
+
+	if w.cfg.PlayerSpeed == 0 {
+		w.cfg.PlayerSpeed = 120
+	}
+
+	if w.cfg.Actions == nil {
+		w.cfg.Actions = DefaultActions()
+	}
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+
+	inputSystem := input.NewSystem(ecs.NextSystemID(), 0, w.cfg.Actions)
+	inputSystem.SetPhase(ecs.PhasePreUpdate)
+
+	if err := sm.Add(
+		inputSystem,
+		NewMovementSystem(ecs.NextSystemID(), 0, w.cfg.Actions),
+		animation.NewSystem(ecs.NextSystemID(), 10),
+	); err != nil {
+		return fmt.Errorf("topdown.World.Init sm.Add error: %w", err)
+	}
+
+	if err := starter.AddUISystem(sm); err != nil {
+		return fmt.Errorf("topdown.World.Init starter.AddUISystem error: %w", err)
+	}
+
+	if _, err := starter.AddAudioSystem(sm, w.cfg.NewPlayer); err != nil {
+		return fmt.Errorf("topdown.World.Init starter.AddAudioSystem error: %w", err)
+	}
+
+	if err := starter.AddRenderSystems(sm); err != nil {
+		return fmt.Errorf("topdown.World.Init starter.AddRenderSystems error: %w", err)
+	}
+
+	starter.SpawnMap(em, w.cfg.Map, w.cfg.Spawner)
+
+	w.player = starter.SpawnSprite(em, w.cfg.PlayerImage, w.cfg.PlayerStart)
+	ecs.AddComponent[Player](em, w.player).Speed = w.cfg.PlayerSpeed
+	starter.FollowCamera(em, w.player, w.cfg.Zoom)
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+// Player returns the player entity.
+func (w *World) Player() ecs.EntityID {
+	return w.player
+}
+
+// MovementSystem moves Player entities with the movement actions.
+// Diagonal movement is normalized so it is not faster than straight movement.
+type MovementSystem struct {
+	*ecs.BaseSystem
+
+	actions *input.Actions
+}
+
+// NewMovementSystem creates a MovementSystem reading actions.
+func NewMovementSystem(id ecs.SystemID, priority int, actions *input.Actions) *MovementSystem {
+	return &MovementSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		actions:    actions,
+	}
+}
+
+func (s *MovementSystem) Update() error {
+	dx := s.actions.Value(ActionRight) - s.actions.Value(ActionLeft)
+	dy := s.actions.Value(ActionDown) - s.actions.Value(ActionUp)
+
+	if length := math.Hypot(dx, dy); length > 1 {
+		dx, dy = dx/length, dy/length
+	}
+
+	if dx == 0 && dy == 0 {
+		return nil
+	}
+
+	dt := s.Time().Delta()
+	em := s.EntityManager()
+
+	for entityID := range ecs.Query2[Player, transform.Transform](em) {
+		speed := ecs.MustGetComponent[Player](em, entityID).Speed
+		ecs.MustGetComponent[transform.Transform](em, entityID).Translate(dx*speed*dt, dy*speed*dt)
+	}
+
+	return nil
+}
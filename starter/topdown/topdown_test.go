@@ -0,0 +1,54 @@
+package topdown_test
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/audio"
+	"github.com/samix73/ebiten-ecs/input"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/starter/topdown"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestWorld(t *testing.T) {
+	held := input.BindingFunc(func() float64 { return 1 })
+
+	actions := input.NewActions()
+	actions.Bind(topdown.ActionRight, held)
+	actions.Bind(topdown.ActionDown, held)
+
+	world := topdown.NewWorld(topdown.Config{
+		PlayerStart: f64.Vec2{10, 20},
+		PlayerSpeed: 100,
+		Actions:     actions,
+		Zoom:        2,
+	})
+
+	game := ecs.NewGame(&ecs.GameConfig{})
+	require.NoError(t, game.SetActiveWorld(world))
+
+	for range ebiten.TPS() {
+		require.NoError(t, game.Update())
+	}
+
+	tr := ecs.MustGetComponent[transform.Transform](world.EntityManager(), world.Player())
+
+	// Diagonal movement covers Speed pixels per second in total, not per axis.
+	step := 100 / 1.4142135623730951
+	assert.InDelta(t, 10+step, tr.Position[0], 1e-6)
+	assert.InDelta(t, 20+step, tr.Position[1], 1e-6)
+
+	camera, ok := ecs.GetComponent[render.Camera](world.EntityManager(), world.Player())
+	if assert.True(t, ok, "the camera follows the player") {
+		assert.Equal(t, 2.0, camera.Zoom)
+	}
+	assert.True(t, ecs.HasComponent[audio.Listener](world.EntityManager(), world.Player()))
+
+	_, ok = ecs.GetResource[audio.System](game.Resources())
+	assert.True(t, ok, "the audio system is registered as a resource")
+}
@@ -0,0 +1,166 @@
+package ecs
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// ComponentStats describes the storage of a component type.
+type ComponentStats struct {
+	Type reflect.Type
+	// Count is the number of components stored.
+	Count int
+	// Capacity is the number of components the storage can hold before growing.
+	Capacity int
+	// Allocations is the number of components allocated by the storage's pool since it was created;
+	// a number growing much faster than Count means components are not recycled. Always zero for inline storage.
+	Allocations int
+	// Inline reports whether the components are stored inline, see UseInlineStorage.
+	Inline bool
+}
+
+func (s ComponentStats) String() string {
+	return fmt.Sprintf("%s: %d/%d (%d allocated)", s.Type, s.Count, s.Capacity, s.Allocations)
+}
+
+// EntityStats is a summary of the contents of an EntityManager.
+type EntityStats struct {
+	Entities int
+	Inactive int
+	Tags     int
+	// Components are the statistics of every component storage, sorted by type name.
+	Components []ComponentStats
+}
+
+// Stats returns a summary of the entities and component storages of the EntityManager.
+func (em *EntityManager) Stats() EntityStats {
+	stats := EntityStats{
+		Entities:   len(em.entities),
+		Inactive:   len(em.inactive),
+		Tags:       len(em.tags),
+		Components: make([]ComponentStats, 0, len(em.componentContainers)),
+	}
+
+	for componentType, storage := range em.componentContainers {
+		componentStats := storage.stats()
+		componentStats.Type = componentType
+		stats.Components = append(stats.Components, componentStats)
+	}
+
+	slices.SortFunc(stats.Components, func(a, b ComponentStats) int {
+		return cmp.Compare(a.Type.String(), b.Type.String())
+	})
+
+	return stats
+}
+
+func (c *ComponentContainer) stats() ComponentStats {
+	return ComponentStats{
+		Count:       len(c.components),
+		Capacity:    cap(c.components),
+		Allocations: c.allocations,
+	}
+}
+
+func (c *InlineContainer[C]) stats() ComponentStats {
+	return ComponentStats{
+		Count:    len(c.entityIDs),
+		Capacity: c.capacity(),
+		Inline:   true,
+	}
+}
+
+// SystemTiming is the time a system takes to update.
+type SystemTiming struct {
+	ID   SystemID
+	Name string
+	// Last is the duration of the last update, including the fixed updates of the tick for fixed update systems.
+	Last time.Duration
+	// Average is an exponential moving average of the update durations.
+	Average time.Duration
+	// Max is the longest update duration since timings were enabled or reset.
+	Max time.Duration
+
+	// updated reports whether the system updated during the current tick.
+	updated bool
+}
+
+func (t SystemTiming) String() string {
+	return fmt.Sprintf("%s: %s (avg %s, max %s)", systemName(t.ID, t.Name), t.Last, t.Average, t.Max)
+}
+
+// timingSmoothing is the weight of the last update in SystemTiming.Average.
+const timingSmoothing = 0.1
+
+// SetTimingsEnabled turns the measurement of system update durations on or off.
+func (sm *SystemManager) SetTimingsEnabled(enabled bool) {
+	if !enabled {
+		sm.timings = nil
+		return
+	}
+
+	if sm.timings == nil {
+		sm.timings = make(map[SystemID]*SystemTiming)
+	}
+}
+
+// TimingsEnabled reports whether system update durations are measured.
+func (sm *SystemManager) TimingsEnabled() bool {
+	return sm.timings != nil
+}
+
+// Timings returns the measured update durations of the systems, in update order.
+// Systems that did not update since timings were enabled are left out.
+func (sm *SystemManager) Timings() []SystemTiming {
+	timings := make([]SystemTiming, 0, len(sm.timings))
+	for _, system := range sm.systems {
+		if timing, ok := sm.timings[system.ID()]; ok {
+			timing.Name = system.baseSystem().name
+			timings = append(timings, *timing)
+		}
+	}
+
+	return timings
+}
+
+// ResetTimings clears the measured durations, keeping measurement enabled if it was.
+func (sm *SystemManager) ResetTimings() {
+	if sm.timings != nil {
+		clear(sm.timings)
+	}
+}
+
+// beginTimings starts a tick of measurements: Last restarts from zero for every system.
+func (sm *SystemManager) beginTimings() {
+	for _, timing := range sm.timings {
+		timing.Last = 0
+		timing.updated = false
+	}
+}
+
+// recordTiming adds the duration of an update to the system's measurements.
+func (sm *SystemManager) recordTiming(systemID SystemID, elapsed time.Duration) {
+	timing, ok := sm.timings[systemID]
+	if !ok {
+		timing = &SystemTiming{ID: systemID, Average: elapsed}
+		sm.timings[systemID] = timing
+	}
+
+	timing.Last += elapsed
+	timing.updated = true
+	timing.Max = max(timing.Max, elapsed)
+}
+
+// endTimings folds the durations of the tick into the averages of the systems that updated.
+func (sm *SystemManager) endTimings() {
+	for _, timing := range sm.timings {
+		if !timing.updated {
+			continue
+		}
+
+		timing.Average += time.Duration(timingSmoothing * float64(timing.Last-timing.Average))
+	}
+}
@@ -0,0 +1,81 @@
+package ecs_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityStats(t *testing.T) {
+	em := ecs.NewEntityManager()
+	require.NoError(t, ecs.UseInlineStorage[CameraComponent](em, 0))
+
+	player := NewPlayerEntity(t, em)
+	NewPlayerEntity(t, em)
+	camera := NewCameraEntity(t, em)
+	em.SetActive(camera, false)
+	em.Tag(player, "player")
+
+	// Removed components are recycled rather than allocated again.
+	ecs.RemoveComponent[TransformComponent](em, player)
+	ecs.AddComponent[TransformComponent](em, player)
+
+	stats := em.Stats()
+	assert.Equal(t, 3, stats.Entities)
+	assert.Equal(t, 1, stats.Inactive)
+	assert.Equal(t, 1, stats.Tags)
+	require.Len(t, stats.Components, 2)
+
+	cameras, transforms := stats.Components[0], stats.Components[1]
+	assert.Equal(t, reflect.TypeFor[CameraComponent](), cameras.Type)
+	assert.True(t, cameras.Inline)
+	assert.Equal(t, 1, cameras.Count)
+	assert.GreaterOrEqual(t, cameras.Capacity, 1)
+
+	assert.Equal(t, reflect.TypeFor[TransformComponent](), transforms.Type)
+	assert.Equal(t, 3, transforms.Count)
+	// The pool may drop the recycled component during a garbage collection.
+	assert.GreaterOrEqual(t, transforms.Allocations, 3)
+	assert.LessOrEqual(t, transforms.Allocations, 4)
+	assert.GreaterOrEqual(t, transforms.Capacity, 3)
+}
+
+func TestSystemTimings(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+
+	slow := ecs.NewSystem(ecs.SystemOptions{Name: "slow"}, func(*ecs.FuncSystem) error {
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	})
+	fast := ecs.NewSystem(ecs.SystemOptions{Name: "fast", Priority: 1}, func(*ecs.FuncSystem) error { return nil })
+	sm.Add(slow, fast)
+
+	require.NoError(t, sm.Update())
+	assert.Empty(t, sm.Timings())
+
+	sm.SetTimingsEnabled(true)
+	assert.True(t, sm.TimingsEnabled())
+	require.NoError(t, sm.Update())
+	require.NoError(t, sm.Update())
+
+	timings := sm.Timings()
+	require.Len(t, timings, 2)
+	assert.Equal(t, "slow", timings[0].Name)
+	assert.Equal(t, slow.ID(), timings[0].ID)
+	assert.GreaterOrEqual(t, timings[0].Last, 2*time.Millisecond)
+	assert.GreaterOrEqual(t, timings[0].Average, 2*time.Millisecond)
+	assert.GreaterOrEqual(t, timings[0].Max, timings[0].Last)
+	assert.Contains(t, timings[0].String(), "slow: ")
+	assert.Less(t, timings[1].Average, timings[0].Average)
+
+	sm.ResetTimings()
+	assert.Empty(t, sm.Timings())
+	sm.SetTimingsEnabled(false)
+	require.NoError(t, sm.Update())
+	assert.Empty(t, sm.Timings())
+}
@@ -0,0 +1,53 @@
+package ecs
+
+import (
+	"fmt"
+	"iter"
+)
+
+// SetStrict turns strict mode on or off. In strict mode, the EntityManager panics, naming the entity and
+// component involved, on misuse it would otherwise silently handle:
+//   - adding a component to an entity without the components it requires, see Require;
+//   - adding a component to, removing, or changing an entity that does not exist, e.g. through a stale ID
+//     kept after the entity was removed;
+//   - adding a component the entity already has with AddComponent, rather than AddOrGetComponent or SetComponent;
+//   - making a structural change while a query, QueryTagged or Related is iterated, rather than deferring
+//     it with the command buffer, see Commands.
+//
+// Reading a removed entity, e.g. with GetComponent or Exists, is not misuse and does not panic.
+// Strict mode is meant for development and tests: it adds a check to every iteration.
+func (em *EntityManager) SetStrict(strict bool) {
+	em.strict = strict
+}
+
+// Strict reports whether the EntityManager is in strict mode, see SetStrict.
+func (em *EntityManager) Strict() bool {
+	return em.strict
+}
+
+// checkExists reports whether the entity exists, and panics in strict mode if it does not.
+func (em *EntityManager) checkExists(op string, entityID EntityID) bool {
+	if _, exists := em.entities[entityID]; exists {
+		return true
+	}
+
+	if em.strict {
+		panic(fmt.Sprintf("ecs.%s: entity %d does not exist", op, entityID))
+	}
+
+	return false
+}
+
+// guardIteration returns seq, which in strict mode makes structural changes panic while it is iterated.
+func (em *EntityManager) guardIteration(seq iter.Seq[EntityID]) iter.Seq[EntityID] {
+	if !em.strict {
+		return seq
+	}
+
+	return func(yield func(EntityID) bool) {
+		em.iterating.Add(1)
+		defer em.iterating.Add(-1)
+
+		seq(yield)
+	}
+}
@@ -0,0 +1,49 @@
+package ecs_test
+
+import (
+	"fmt"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrict(t *testing.T) {
+	em := ecs.NewEntityManager()
+	em.SetStrict(true)
+
+	entityID := NewPlayerEntity(t, em)
+	assert.PanicsWithValue(t, fmt.Sprintf("ecs.AddComponent: entity %d already has ecs_test.TransformComponent", entityID), func() {
+		ecs.AddComponent[TransformComponent](em, entityID)
+	})
+	assert.NotPanics(t, func() {
+		ecs.AddOrGetComponent[TransformComponent](em, entityID)
+		ecs.SetComponent(em, entityID, TransformComponent{Rotation: 1})
+	})
+
+	assert.PanicsWithValue(t, "ecs.EntityManager.Tag: structural change while iterating a query, defer it with EntityManager.Commands", func() {
+		for queried := range ecs.Query[TransformComponent](em) {
+			em.Tag(queried, "seen")
+		}
+	})
+	for queried := range ecs.Query[TransformComponent](em) {
+		em.Commands().Tag(queried, "seen")
+	}
+	em.Commands().Flush(em)
+	assert.True(t, em.HasTag(entityID, "seen"), "deferred changes are allowed")
+
+	em.Remove(entityID)
+	assert.PanicsWithValue(t, fmt.Sprintf("ecs.AddComponent: entity %d does not exist", entityID), func() {
+		ecs.AddComponent[TransformComponent](em, entityID)
+	})
+	assert.PanicsWithValue(t, fmt.Sprintf("ecs.EntityManager.Remove: entity %d does not exist", entityID), func() {
+		em.Remove(entityID)
+	})
+	assert.NotPanics(t, func() {
+		_, ok := ecs.GetComponent[TransformComponent](em, entityID)
+		assert.False(t, ok, "reading a removed entity is allowed")
+	})
+
+	em.SetStrict(false)
+	assert.Nil(t, ecs.AddComponent[TransformComponent](em, entityID))
+}
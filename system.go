@@ -1,12 +1,27 @@
 package ecs
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"log/slog"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// ErrDuplicateSystem is returned by SystemManager.Add for systems whose ID is already used by another system.
+var ErrDuplicateSystem = errors.New("duplicate system ID")
+
+// ErrSystemOrder is returned by SystemManager.Add when the Before and After constraints of the systems
+// cannot be satisfied, because they form a cycle or contradict the phases of the systems.
+var ErrSystemOrder = errors.New("unsatisfiable system order")
+
 // Teardowner is an interface that requires a Teardown method.
 type Teardowner interface {
 	Teardown()
@@ -25,6 +40,37 @@ type System interface {
 	baseSystem() *BaseSystem
 }
 
+// WorldStarter is an optional interface for systems that need to know when their world becomes active:
+// after the world is initialized, and again whenever it resumes after a world pushed on top of it is popped.
+// It is the place to subscribe to events or resume timers.
+type WorldStarter interface {
+	OnWorldStart(w World)
+}
+
+// WorldStopper is an optional interface for systems that need to know when their world stops being active:
+// before it is torn down, and whenever another world is pushed on top of it.
+// Unlike Teardown, the system may be started again afterwards.
+type WorldStopper interface {
+	OnWorldStop()
+}
+
+// SystemIniter is an optional interface for systems that set themselves up from their world, e.g. to look up
+// other systems or resources, or to load assets, and may fail. Once the world's Init returned, the SystemManager
+// calls Init on its systems in update order, before the world becomes active; an error tears the world down
+// and aborts the world switch. Systems added to an initialized world are initialized by SystemManager.Add.
+type SystemIniter interface {
+	Init(w World) error
+}
+
+// StartupSystem is an optional interface for systems with one-time setup, such as spawning the initial entities
+// or loading assets, instead of checking an initialized flag in every update. The SystemManager calls Startup
+// exactly once per system, at the beginning of its first Update after the system was added, before any system
+// is updated, even while the game is paused. Unlike OnWorldStart, it is not called again when the world resumes.
+// An error aborts the Update and is returned by it.
+type StartupSystem interface {
+	Startup() error
+}
+
 // DrawableSystem is an optional interface that systems can implement if they need to perform drawing operations.
 type DrawableSystem interface {
 	System
@@ -38,10 +84,39 @@ type BaseSystem struct {
 	priority      int
 	entityManager *EntityManager
 	game          *Game
+	alwaysRun     bool
+	phase         Phase
+	access        SystemAccess
+	name          string
+	rand          *Rand
+	// startedUp is set once Startup has been called, or is not implemented.
+	startedUp bool
+
+	// renderTarget is the offscreen image the system draws into, see SetRenderTarget.
+	renderTarget *RenderTarget
+	// disabled systems are not updated, see SetEnabled.
+	disabled bool
+
+	// before and after are the IDs of the systems this system runs before and after, see Before and After.
+	before, after []SystemID
+
+	// interval and tickInterval schedule the system, see SetInterval and SetTickInterval.
+	interval     time.Duration
+	tickInterval int
+	// elapsed and ticks are the scaled time and the ticks counted since the system last ran.
+	elapsed time.Duration
+	ticks   int
+	// sinceLast is the scaled time since the system last ran, and lastDelta the time covered by its current update.
+	sinceLast, lastDelta time.Duration
 }
 
 // NewBaseSystem creates a new BaseSystem with the given ID and priority.
+// A new ID is generated with NextSystemID if id is UndefinedID.
 func NewBaseSystem(id SystemID, priority int) *BaseSystem {
+	if id == UndefinedID {
+		id = NextSystemID()
+	}
+
 	return &BaseSystem{
 		id:       id,
 		priority: priority,
@@ -59,6 +134,140 @@ func (s *BaseSystem) Priority() int {
 	return s.priority
 }
 
+// AlwaysRun reports whether the system keeps updating while the game is paused.
+func (s *BaseSystem) AlwaysRun() bool {
+	return s.alwaysRun
+}
+
+// SetAlwaysRun marks the system as essential, so it keeps updating while the game is paused,
+// e.g. input and menu systems. Such systems see a zero Time.Delta while paused and should use
+// Time.UnscaledDelta instead.
+func (s *BaseSystem) SetAlwaysRun(alwaysRun bool) {
+	s.alwaysRun = alwaysRun
+}
+
+// Name returns the name of the system used in logs and statistics, or "" if it has none.
+func (s *BaseSystem) Name() string {
+	return s.name
+}
+
+// SetName sets the name of the system used in logs and statistics.
+func (s *BaseSystem) SetName(name string) {
+	s.name = name
+}
+
+// Phase returns the phase of the update loop the system runs in.
+func (s *BaseSystem) Phase() Phase {
+	return s.phase
+}
+
+// SetPhase sets the phase of the update loop the system runs in. Systems run phase by phase,
+// and by priority within a phase. It must be called before the system is added to a SystemManager.
+func (s *BaseSystem) SetPhase(phase Phase) {
+	s.phase = phase
+}
+
+// Enabled reports whether the system is updated, true unless disabled with SetEnabled.
+func (s *BaseSystem) Enabled() bool {
+	return !s.disabled
+}
+
+// SetEnabled enables or disables the system. Disabled systems are not updated, but are still drawn.
+// Systems failing under ErrorPolicyDisable are disabled, and can be enabled again.
+func (s *BaseSystem) SetEnabled(enabled bool) {
+	s.disabled = !enabled
+}
+
+// Before declares that the system runs before the systems with the given IDs, whatever their priorities,
+// e.g. an input system before the systems reading the actions of another package. It must be called before
+// the system is added to a SystemManager. Systems that are not in the same SystemManager are ignored.
+func (s *BaseSystem) Before(ids ...SystemID) {
+	s.before = append(s.before, ids...)
+}
+
+// After declares that the system runs after the systems with the given IDs, whatever their priorities,
+// e.g. a camera system after the physics system. It must be called before the system is added to a
+// SystemManager. Systems that are not in the same SystemManager are ignored.
+func (s *BaseSystem) After(ids ...SystemID) {
+	s.after = append(s.after, ids...)
+}
+
+// Interval returns the scaled time between two updates of the system, 0 if it runs every tick.
+func (s *BaseSystem) Interval() time.Duration {
+	return s.interval
+}
+
+// SetInterval makes the SystemManager update the system once every interval of scaled time instead of
+// every tick, e.g. time.Second/5 for AI re-planning at 5Hz or 30*time.Second for an autosave. The system
+// first runs once the interval has elapsed, and at most once per tick; time left over is carried to the
+// next interval, so the cadence does not drift. Zero runs the system every tick.
+//
+// Time().Delta() still covers a single tick when the system runs: integrate LastDelta instead,
+// the time since the system's previous update.
+func (s *BaseSystem) SetInterval(interval time.Duration) {
+	s.interval = max(interval, 0)
+	s.elapsed = 0
+}
+
+// TickInterval returns the number of ticks between two updates of the system, 0 if it runs every tick.
+func (s *BaseSystem) TickInterval() int {
+	return s.tickInterval
+}
+
+// SetTickInterval makes the SystemManager update the system once every n ticks instead of every tick,
+// starting with the n-th. Ticks are the updates of the system's phase, so fixed steps for a system in
+// PhaseFixedUpdate, and ticks skipped while the game is paused are not counted. Values below 2 run the
+// system every tick. Combined with SetInterval, the system runs when both are due. As with SetInterval,
+// integrate LastDelta rather than Time().Delta().
+func (s *BaseSystem) SetTickInterval(n int) {
+	s.tickInterval = max(n, 0)
+	s.ticks = 0
+}
+
+// LastDelta returns the scaled time covered by the system's current update: the time since its previous
+// update for systems scheduled with SetInterval or SetTickInterval, or the delta of the tick otherwise.
+func (s *BaseSystem) LastDelta() time.Duration {
+	return s.lastDelta
+}
+
+// due counts a tick of delta scaled time and reports whether the system is scheduled to run.
+func (s *BaseSystem) due(delta time.Duration) bool {
+	s.sinceLast += delta
+
+	if s.tickInterval > 1 {
+		s.ticks++
+		if s.ticks < s.tickInterval {
+			return false
+		}
+	}
+
+	if s.interval > 0 {
+		s.elapsed += delta
+		if s.elapsed < s.interval {
+			return false
+		}
+	}
+
+	s.ticks = 0
+	if s.interval > 0 {
+		s.elapsed = (s.elapsed - s.interval) % s.interval
+	}
+
+	s.lastDelta, s.sinceLast = s.sinceLast, 0
+
+	return true
+}
+
+// Access returns the component and event types the system declared it uses.
+func (s *BaseSystem) Access() SystemAccess {
+	return s.access
+}
+
+// SetAccess declares the component and event types the system uses.
+func (s *BaseSystem) SetAccess(access SystemAccess) {
+	s.access = access
+}
+
 // EntityManager returns the EntityManager associated with the system.
 func (s *BaseSystem) EntityManager() *EntityManager {
 	return s.entityManager
@@ -69,6 +278,31 @@ func (s *BaseSystem) Game() *Game {
 	return s.game
 }
 
+// Time returns the timing information of the current tick, or nil if the system is not attached to a Game.
+func (s *BaseSystem) Time() *Time {
+	if s.game == nil {
+		return nil
+	}
+
+	return s.game.Time()
+}
+
+// Context returns the context of the game, cancelled when it shuts down, or context.Background()
+// if the system is not attached to a Game, see Game.Context.
+func (s *BaseSystem) Context() context.Context {
+	if s.game == nil {
+		return context.Background()
+	}
+
+	return s.game.Context()
+}
+
+// Rand returns the random number generator of the SystemManager the system was added to,
+// or nil if it was not added to one.
+func (s *BaseSystem) Rand() *Rand {
+	return s.rand
+}
+
 func (s *BaseSystem) baseSystem() *BaseSystem {
 	return s
 }
@@ -79,24 +313,85 @@ func (s *BaseSystem) canUpdate() bool {
 
 // SystemManager manages a collection of systems within the ECS framework.
 // It is responsible for adding, removing, updating, and drawing systems.
-// The SystemManager ensures that systems are executed in order of their priority.
+// The SystemManager ensures that systems are executed phase by phase, in order of their priority within a phase.
 type SystemManager struct {
 	systems       []System
 	entityManager *EntityManager
 	game          *Game
+
+	// world is the world the systems run in while it is active, nil otherwise.
+	world World
+	// initialized is the world the systems were initialized for, see SystemIniter.
+	initialized World
+
+	// fixedAccumulator is the scaled time not yet consumed by fixed updates.
+	fixedAccumulator float64
+
+	tick uint64
+	rand *Rand
+
+	errorPolicy ErrorPolicy
+
+	// targets are the offscreen images of the systems with a RenderTarget.
+	targets map[SystemID]*ebiten.Image
+
+	// startupPending is set when systems were added since the last Update, see StartupSystem.
+	startupPending bool
+
+	// timings holds the measured update durations while enabled, see SetTimingsEnabled.
+	timings map[SystemID]*SystemTiming
+
+	profileLabels bool
+	// skippedDraws are the DrawableSystems already reported as skipped by DrawCanvas.
+	skippedDraws map[SystemID]struct{}
+	// traceContext is the context of the trace task of the current Update or Draw while tracing.
+	traceContext context.Context
 }
 
+// maxFixedSteps bounds the fixed updates run in a single update, so a slow frame does not cause
+// ever more fixed updates in the next ones; the remaining time is dropped.
+const maxFixedSteps = 8
+
 // NewSystemManager creates a new SystemManager with the provided EntityManager and Game instance.
 func NewSystemManager(entityManager *EntityManager, game *Game) *SystemManager {
+	var seed uint64
+	if game != nil {
+		seed = game.cfg.Seed
+
+		if entityManager != nil && entityManager.logger == nil {
+			entityManager.logger = game.logger
+		}
+	}
+
 	return &SystemManager{
 		systems:       make([]System, 0),
 		entityManager: entityManager,
 		game:          game,
+		rand:          NewRand(seed),
 	}
 }
 
-func (sm *SystemManager) sortSystems() {
+// Tick returns the number of times Update was called. Unlike Time.Tick, it only counts
+// the updates of this SystemManager's world, e.g. not those of a pause menu pushed over it.
+func (sm *SystemManager) Tick() uint64 {
+	return sm.tick
+}
+
+// Rand returns the random number generator of the systems, seeded with GameConfig.Seed.
+// Each SystemManager, and so each world, has its own generator, so the random numbers drawn
+// by a world do not depend on the other worlds.
+func (sm *SystemManager) Rand() *Rand {
+	return sm.rand
+}
+
+// sortSystems orders the systems phase by phase, then by their Before and After constraints,
+// then by priority, then by ID in deterministic mode.
+func (sm *SystemManager) sortSystems() error {
 	slices.SortStableFunc(sm.systems, func(a, b System) int {
+		if phaseA, phaseB := a.baseSystem().phase, b.baseSystem().phase; phaseA != phaseB {
+			return cmp.Compare(phaseA, phaseB)
+		}
+
 		if a.Priority() < b.Priority() {
 			return -1
 		}
@@ -105,16 +400,132 @@ func (sm *SystemManager) sortSystems() {
 			return 1
 		}
 
+		if sm.game != nil && sm.game.Deterministic() {
+			return cmp.Compare(a.ID(), b.ID())
+		}
+
 		return 0
 	})
+
+	sorted, err := orderSystems(sm.systems)
+	if err != nil {
+		return err
+	}
+	sm.systems = sorted
+
+	return nil
+}
+
+// orderSystems sorts the systems, already ordered by phase and priority, topologically by their Before
+// and After constraints, taking the first system in the given order among those whose dependencies ran.
+func orderSystems(systems []System) ([]System, error) {
+	index := make(map[SystemID]int, len(systems))
+	for i, system := range systems {
+		index[system.ID()] = i
+	}
+
+	successors := make([][]int, len(systems))
+	dependencies := make([]int, len(systems))
+	constrained := false
+	var err error
+	constrain := func(first, then int) {
+		if a, b := systems[first].baseSystem(), systems[then].baseSystem(); a.phase > b.phase {
+			err = errors.Join(err, fmt.Errorf("%w: system %s must run before system %s of an earlier phase",
+				ErrSystemOrder, describeSystem(systems[first]), describeSystem(systems[then])))
+			return
+		}
+
+		successors[first] = append(successors[first], then)
+		dependencies[then]++
+		constrained = true
+	}
+
+	for i, system := range systems {
+		for _, id := range system.baseSystem().before {
+			if j, ok := index[id]; ok {
+				constrain(i, j)
+			}
+		}
+
+		for _, id := range system.baseSystem().after {
+			if j, ok := index[id]; ok {
+				constrain(j, i)
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !constrained {
+		return systems, nil
+	}
+
+	sorted := make([]System, 0, len(systems))
+	done := make([]bool, len(systems))
+	for len(sorted) < len(systems) {
+		next := -1
+		for i := range systems {
+			if !done[i] && dependencies[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			var cycle []string
+			for i, system := range systems {
+				if !done[i] {
+					cycle = append(cycle, describeSystem(system))
+				}
+			}
+
+			return nil, fmt.Errorf("%w: cycle between systems %s", ErrSystemOrder, strings.Join(cycle, ", "))
+		}
+
+		done[next] = true
+		sorted = append(sorted, systems[next])
+		for _, successor := range successors[next] {
+			dependencies[successor]--
+		}
+	}
+
+	return sorted, nil
+}
+
+// describeSystem returns the ID of the system, followed by its name if it has one.
+func describeSystem(system System) string {
+	if name := system.baseSystem().name; name != "" {
+		return fmt.Sprintf("%d (%s)", system.ID(), name)
+	}
+
+	return strconv.FormatUint(uint64(system.ID()), 10)
 }
 
 // Add adds one or more systems to the SystemManager.
 // It ensures that each system has access to the EntityManager and Game instance.
-// After adding, it sorts the systems based on their priority.
-func (sm *SystemManager) Add(systems ...System) {
+// After adding, it sorts the systems by phase, Before and After constraints, and priority.
+// If a system ID is already used, or the constraints cannot be satisfied, none of the systems are added
+// and an error wrapping ErrDuplicateSystem or ErrSystemOrder is returned. If the world was initialized,
+// the added systems are initialized, see SystemIniter, and none are added if one fails: like a world whose
+// systems fail to initialize, the systems initialized so far and the failing one are torn down.
+func (sm *SystemManager) Add(systems ...System) error {
 	if len(systems) == 0 {
-		return
+		return nil
+	}
+
+	for i, system := range systems {
+		if sm.Has(system.ID()) || slices.ContainsFunc(systems[:i], func(s System) bool { return s.ID() == system.ID() }) {
+			return fmt.Errorf("ecs.SystemManager.Add error: system %s: %w", describeSystem(system), ErrDuplicateSystem)
+		}
+	}
+
+	previous := slices.Clone(sm.systems)
+	sm.systems = append(sm.systems, systems...)
+	if err := sm.sortSystems(); err != nil {
+		sm.systems = previous
+		return fmt.Errorf("ecs.SystemManager.Add sm.sortSystems error: %w", err)
 	}
 
 	for _, system := range systems {
@@ -125,62 +536,410 @@ func (sm *SystemManager) Add(systems ...System) {
 		if system.baseSystem().game == nil {
 			system.baseSystem().game = sm.game
 		}
+
+		system.baseSystem().rand = sm.rand
 	}
 
-	sm.systems = append(sm.systems, systems...)
+	if sm.initialized != nil {
+		for i, system := range systems {
+			if err := sm.initSystem(system); err != nil {
+				sm.systems = previous
+				for _, initialized := range systems[:i+1] {
+					if initialized, ok := initialized.(Teardowner); ok {
+						initialized.Teardown()
+					}
+				}
+
+				return fmt.Errorf("ecs.SystemManager.Add sm.initSystem error: %w", err)
+			}
+		}
+	}
 
-	sm.sortSystems()
-}
+	sm.startupPending = true
 
-// Remove removes a system from the SystemManager by its ID.
-// If the system implements the Teardowner interface, its Teardown method is called before removal.
-func (sm *SystemManager) Remove(systemID SystemID) {
-	indexToDelete, exists := slices.BinarySearchFunc(sm.systems, systemID, func(s System, id SystemID) int {
-		if s.ID() < id {
-			return -1
-		}
+	for _, system := range systems {
+		sm.logSystem("system added", system)
+	}
 
-		if s.ID() > id {
-			return 1
+	if sm.world == nil {
+		return nil
+	}
+
+	for _, system := range systems {
+		if starter, ok := system.(WorldStarter); ok {
+			starter.OnWorldStart(sm.world)
 		}
+	}
 
-		return 0
+	return nil
+}
+
+// AddFunc adds a system whose behavior is the function fn, for small systems that don't justify a type.
+// The system gets a new ID and runs in PhaseUpdate; the returned system can be used to name it.
+// The error is that of Add:
+//
+//	gravity, err := sm.AddFunc(10, func(em *ecs.EntityManager, g *ecs.Game) error {
+//		for id := range ecs.Query[Velocity](em) { ... }
+//		return nil
+//	})
+func (sm *SystemManager) AddFunc(priority int, fn func(em *EntityManager, g *Game) error) (*FuncSystem, error) {
+	system := NewSystem(SystemOptions{Priority: priority}, func(s *FuncSystem) error {
+		return fn(s.EntityManager(), s.Game())
 	})
 
-	if !exists {
+	if err := sm.Add(system); err != nil {
+		return nil, fmt.Errorf("ecs.SystemManager.AddFunc sm.Add error: %w", err)
+	}
+
+	return system, nil
+}
+
+// Get returns the system with the given ID.
+func (sm *SystemManager) Get(systemID SystemID) (System, bool) {
+	index := sm.index(systemID)
+	if index < 0 {
+		return nil, false
+	}
+
+	return sm.systems[index], true
+}
+
+// Has reports whether the SystemManager holds a system with the given ID.
+func (sm *SystemManager) Has(systemID SystemID) bool {
+	return sm.index(systemID) >= 0
+}
+
+// Systems returns the systems in update order, e.g. for a debug overlay or scripts.
+// Systems may be added or removed while iterating, which affects the following iterations.
+func (sm *SystemManager) Systems() iter.Seq[System] {
+	return func(yield func(System) bool) {
+		for _, system := range slices.Clone(sm.systems) {
+			if !yield(system) {
+				return
+			}
+		}
+	}
+}
+
+// index returns the position of the system with the given ID in the update order, or -1.
+func (sm *SystemManager) index(systemID SystemID) int {
+	return slices.IndexFunc(sm.systems, func(s System) bool { return s.ID() == systemID })
+}
+
+// Remove removes a system from the SystemManager by its ID, keeping the order of the other systems.
+// If the system implements the Teardowner interface, its Teardown method is called before removal.
+func (sm *SystemManager) Remove(systemID SystemID) {
+	indexToDelete := sm.index(systemID)
+	if indexToDelete < 0 {
 		return
 	}
 
 	systemToDelete := sm.systems[indexToDelete]
-	sm.systems[indexToDelete] = sm.systems[len(sm.systems)-1]
-	sm.systems = sm.systems[:len(sm.systems)-1]
+	sm.systems = slices.Delete(sm.systems, indexToDelete, indexToDelete+1)
+	sm.releaseTarget(systemID)
+	sm.logSystem("system removed", systemToDelete)
+
+	if stopper, ok := systemToDelete.(WorldStopper); ok && sm.world != nil {
+		stopper.OnWorldStop()
+	}
 
 	if systemToDelete, ok := systemToDelete.(Teardowner); ok {
 		systemToDelete.Teardown()
 	}
 }
 
+// initSystems calls Init on all systems that implement the SystemIniter interface, in update order.
+func (sm *SystemManager) initSystems(world World) error {
+	sm.initialized = world
+	for _, system := range sm.systems {
+		if err := sm.initSystem(system); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sm *SystemManager) initSystem(system System) error {
+	initer, ok := system.(SystemIniter)
+	if !ok {
+		return nil
+	}
+
+	if err := initer.Init(sm.initialized); err != nil {
+		return fmt.Errorf("error initializing system %s: %w", describeSystem(system), err)
+	}
+
+	return nil
+}
+
+// startWorld calls OnWorldStart on all systems that implement the WorldStarter interface.
+func (sm *SystemManager) startWorld(world World) {
+	if sm.world != nil {
+		return
+	}
+
+	sm.world = world
+	for _, system := range sm.systems {
+		if starter, ok := system.(WorldStarter); ok {
+			starter.OnWorldStart(world)
+		}
+	}
+}
+
+// stopWorld calls OnWorldStop on all systems that implement the WorldStopper interface.
+func (sm *SystemManager) stopWorld() {
+	if sm.world == nil {
+		return
+	}
+
+	sm.world = nil
+	for _, system := range sm.systems {
+		if stopper, ok := system.(WorldStopper); ok {
+			stopper.OnWorldStop()
+		}
+	}
+}
+
 // Update updates all systems managed by the SystemManager.
 // It calls the Update method of each system in order of their priority.
-// If any system returns an error during its update, the process is halted and the error is returned.
+// In deterministic mode, systems of equal priority are updated in ascending ID order.
+// Systems in PhaseFixedUpdate are updated once per elapsed Time.FixedStep of scaled time, so zero or more
+// times per update, with Time.Delta returning the fixed step.
+// While the game is paused, only systems marked with BaseSystem.SetAlwaysRun are updated.
+// Systems scheduled with BaseSystem.SetInterval or BaseSystem.SetTickInterval are skipped until they are due.
+// The command buffer of the system's EntityManager is flushed and its spatial indexes are invalidated
+// after each system, and the changes of its watched entities are dispatched at the end of the update,
+// see EntityManager.Watch.
+// Systems added since the previous update are started up first, see StartupSystem.
+// If any system returns an error during its update, the process is halted and the error is returned,
+// unless another ErrorPolicy is set with SetErrorPolicy. Disabled systems are skipped, see BaseSystem.SetEnabled.
+// In builds with the ecsdebug tag, the EntityManager is then validated, see EntityManager.Validate.
 func (sm *SystemManager) Update() error {
+	sm.tick++
+	paused := sm.game != nil && sm.game.Paused()
+
+	defer sm.beginTrace("ecs.SystemManager.Update")()
+
+	if sm.entityManager != nil {
+		// Background readers of a concurrent EntityManager wait for the update.
+		defer sm.entityManager.lockStructure()()
+		defer sm.entityManager.rebuildSpatialIndexes()
+	}
+
+	if sm.startupPending {
+		if err := sm.startup(); err != nil {
+			return err
+		}
+	}
+
+	if sm.entityManager != nil {
+		defer sm.entityManager.DispatchWatchEvents()
+	}
+
+	if sm.timings != nil {
+		sm.beginTimings()
+		defer sm.endTimings()
+	}
+
+	fixedStart := slices.IndexFunc(sm.systems, func(s System) bool { return s.baseSystem().phase >= PhaseFixedUpdate })
+	if fixedStart < 0 {
+		fixedStart = len(sm.systems)
+	}
+
+	fixedEnd := fixedStart + slices.IndexFunc(sm.systems[fixedStart:], func(s System) bool { return s.baseSystem().phase > PhaseFixedUpdate })
+	if fixedEnd < fixedStart {
+		fixedEnd = len(sm.systems)
+	}
+
+	if err := sm.updateSystems(sm.systems[:fixedStart], paused); err != nil {
+		return err
+	}
+
+	if fixedEnd > fixedStart && sm.game != nil {
+		if err := sm.updateFixed(sm.systems[fixedStart:fixedEnd], paused); err != nil {
+			return err
+		}
+	}
+
+	if err := sm.updateSystems(sm.systems[fixedEnd:], paused); err != nil {
+		return err
+	}
+
+	return sm.validate()
+}
+
+// startup calls Startup on the systems that have not been started up yet, in update order.
+// After an error, the remaining systems are started up in the next update.
+func (sm *SystemManager) startup() error {
 	for _, system := range sm.systems {
+		base := system.baseSystem()
+		if base.startedUp || !base.canUpdate() {
+			continue
+		}
+		base.startedUp = true
+
+		starter, ok := system.(StartupSystem)
+		if !ok {
+			continue
+		}
+
+		err := starter.Startup()
+
+		if em := base.entityManager; em != nil {
+			em.Commands().Flush(em)
+			em.InvalidateSpatialIndexes()
+		}
+
+		if err != nil {
+			return fmt.Errorf("error starting up system %d: %w", system.ID(), err)
+		}
+	}
+	sm.startupPending = false
+
+	return nil
+}
+
+// updateFixed runs the fixed update systems for every fixed step of time elapsed.
+func (sm *SystemManager) updateFixed(systems []System, paused bool) error {
+	t := sm.game.Time()
+	step := t.FixedStep()
+
+	// Tolerate rounding errors, so a frame delta equal to the fixed step always runs one fixed update.
+	const epsilon = 1e-9
+
+	sm.fixedAccumulator += t.Delta()
+
+	t.beginFixedStep()
+	defer t.endFixedStep()
+
+	for steps := 0; sm.fixedAccumulator+epsilon >= step; steps++ {
+		if steps == maxFixedSteps {
+			sm.fixedAccumulator = 0
+			break
+		}
+
+		sm.fixedAccumulator = max(sm.fixedAccumulator-step, 0)
+		if err := sm.updateSystems(systems, paused); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sm *SystemManager) updateSystems(systems []System, paused bool) error {
+	for _, system := range systems {
 		if !system.baseSystem().canUpdate() {
 			continue
 		}
 
-		if err := system.Update(); err != nil {
-			return fmt.Errorf("error updating system %d: %w", system.ID(), err)
+		if paused && !system.baseSystem().alwaysRun {
+			continue
+		}
+
+		if system.baseSystem().disabled {
+			continue
+		}
+
+		if !system.baseSystem().due(sm.game.Time().DeltaDuration()) {
+			continue
+		}
+
+		var start time.Time
+		if sm.timings != nil {
+			start = time.Now()
+		}
+
+		err := sm.updateSystem(system)
+
+		if sm.timings != nil {
+			sm.recordTiming(system.ID(), time.Since(start))
+		}
+
+		if em := system.baseSystem().entityManager; em != nil {
+			em.Commands().Flush(em)
+			em.InvalidateSpatialIndexes()
+		}
+
+		if err != nil {
+			if err := sm.handleError(system, err); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-// Draw calls the Draw method of all systems that implement the DrawableSystem interface.
+// Draw calls the Draw method of all systems that implement the DrawableSystem interface
+// and the DrawCanvas method of all systems that implement the CanvasSystem interface.
 func (sm *SystemManager) Draw(screen *ebiten.Image) {
+	sm.DrawCanvas(screen)
+}
+
+// DrawCanvas draws all systems onto the given Canvas.
+// Systems that only implement DrawableSystem are drawn only when the canvas is an *ebiten.Image,
+// or when they have a RenderTarget, which is then composited onto the canvas. On any other canvas,
+// such as a render.Recorder, they are skipped, so recordings miss what they draw; the first time a
+// system is skipped, a warning is logged to the game's logger, see Game.SetLogger. Implement
+// CanvasSystem, or set a RenderTarget, for systems whose drawing must be recorded.
+func (sm *SystemManager) DrawCanvas(canvas Canvas) {
+	_, isImage := canvas.(*ebiten.Image)
+
+	defer sm.beginTrace("ecs.SystemManager.Draw")()
+
 	for _, system := range sm.systems {
-		if system, ok := system.(DrawableSystem); ok {
+		_, isCanvasSystem := system.(CanvasSystem)
+		if _, isDrawable := system.(DrawableSystem); !isCanvasSystem && !isDrawable {
+			continue
+		}
+
+		if target := system.baseSystem().renderTarget; target != nil {
+			sm.drawTarget(system, target, canvas)
+			continue
+		}
+		sm.releaseTarget(system.ID())
+
+		if !isCanvasSystem && !isImage {
+			sm.warnSkippedDraw(system)
+			continue
+		}
+
+		sm.drawSystem(system, canvas)
+	}
+}
+
+// warnSkippedDraw logs that the DrawableSystem was not drawn onto a canvas that is not an *ebiten.Image,
+// once per system.
+func (sm *SystemManager) warnSkippedDraw(system System) {
+	if _, warned := sm.skippedDraws[system.ID()]; warned || sm.game == nil || sm.game.Logger() == nil {
+		return
+	}
+
+	if sm.skippedDraws == nil {
+		sm.skippedDraws = make(map[SystemID]struct{})
+	}
+	sm.skippedDraws[system.ID()] = struct{}{}
+
+	sm.game.Logger().Warn("drawable system skipped: the canvas is not an *ebiten.Image",
+		slog.Uint64("system", uint64(system.ID())))
+}
+
+// drawSystem draws the system onto canvas, which is an *ebiten.Image unless the system is a CanvasSystem.
+func (sm *SystemManager) drawSystem(system System, canvas Canvas) {
+	switch system := system.(type) {
+	case CanvasSystem:
+		if sm.instrumented() {
+			sm.instrument(system, "draw", func() { system.DrawCanvas(canvas) })
+		} else {
+			system.DrawCanvas(canvas)
+		}
+	case DrawableSystem:
+		screen := canvas.(*ebiten.Image)
+		if sm.instrumented() {
+			sm.instrument(system, "draw", func() { system.Draw(screen) })
+		} else {
 			system.Draw(screen)
 		}
 	}
@@ -188,10 +947,14 @@ func (sm *SystemManager) Draw(screen *ebiten.Image) {
 
 // Teardown calls the Teardown method of all systems that implement the Teardowner interface.
 func (sm *SystemManager) Teardown() {
+	sm.stopWorld()
+
 	for _, system := range sm.systems {
 		if system, ok := system.(Teardowner); ok {
 			system.Teardown()
 		}
+
+		sm.releaseTarget(system.ID())
 	}
 
 	sm.systems = nil
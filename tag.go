@@ -0,0 +1,129 @@
+package ecs
+
+import (
+	"iter"
+	"slices"
+)
+
+// Tag is a lightweight label used to classify entities without defining an empty component type.
+// Typed tags can be declared as constants:
+//
+//	const Enemy ecs.Tag = "enemy"
+type Tag string
+
+// tagSet stores the entities with a tag. Like a component container it keeps the IDs densely packed,
+// but it stores no component data.
+type tagSet struct {
+	entityIDs []EntityID
+	index     map[EntityID]int
+}
+
+func (s *tagSet) add(entityID EntityID) {
+	if _, ok := s.index[entityID]; ok {
+		return
+	}
+
+	s.index[entityID] = len(s.entityIDs)
+	s.entityIDs = append(s.entityIDs, entityID)
+}
+
+func (s *tagSet) remove(entityID EntityID) {
+	i, ok := s.index[entityID]
+	if !ok {
+		return
+	}
+
+	last := len(s.entityIDs) - 1
+	if i != last {
+		s.entityIDs[i] = s.entityIDs[last]
+		s.index[s.entityIDs[i]] = i
+	}
+
+	s.entityIDs = s.entityIDs[:last]
+	delete(s.index, entityID)
+}
+
+func (s *tagSet) has(entityID EntityID) bool {
+	_, ok := s.index[entityID]
+	return ok
+}
+
+// Tag adds the tags to the entity. Tagging an entity twice with the same tag has no effect.
+func (em *EntityManager) Tag(entityID EntityID, tags ...Tag) {
+	defer em.beginStructural("EntityManager.Tag")()
+
+	if !em.checkExists("EntityManager.Tag", entityID) {
+		return
+	}
+
+	if em.tags == nil {
+		em.tags = make(map[Tag]*tagSet)
+	}
+
+	for _, tag := range tags {
+		set, ok := em.tags[tag]
+		if !ok {
+			set = &tagSet{index: make(map[EntityID]int)}
+			em.tags[tag] = set
+		}
+
+		set.add(entityID)
+	}
+}
+
+// Untag removes the tags from the entity.
+func (em *EntityManager) Untag(entityID EntityID, tags ...Tag) {
+	defer em.beginStructural("EntityManager.Untag")()
+
+	for _, tag := range tags {
+		if set, ok := em.tags[tag]; ok {
+			set.remove(entityID)
+		}
+	}
+}
+
+// HasTag reports whether the entity has the tag.
+func (em *EntityManager) HasTag(entityID EntityID, tag Tag) bool {
+	set, ok := em.tags[tag]
+	return ok && set.has(entityID)
+}
+
+// Tags returns the tags of the entity, sorted.
+func (em *EntityManager) Tags(entityID EntityID) []Tag {
+	var tags []Tag
+	for tag, set := range em.tags {
+		if set.has(entityID) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+
+	return tags
+}
+
+// untagAll removes every tag of a removed or released entity.
+func (em *EntityManager) untagAll(entityID EntityID) {
+	for _, set := range em.tags {
+		set.remove(entityID)
+	}
+}
+
+// QueryTagged returns a sequence of the active entities with the tag.
+func QueryTagged(em *EntityManager, tag Tag) iter.Seq[EntityID] {
+	set, ok := em.tags[tag]
+	if !ok {
+		return func(yield func(EntityID) bool) {}
+	}
+
+	return em.guardIteration(func(yield func(EntityID) bool) {
+		for _, entityID := range set.entityIDs {
+			if em.hidden(entityID) {
+				continue
+			}
+
+			if !yield(entityID) {
+				break
+			}
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	enemyTag ecs.Tag = "enemy"
+	bossTag  ecs.Tag = "boss"
+)
+
+func TestTags(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	grunt := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, grunt)
+	em.Tag(grunt, enemyTag)
+
+	boss := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, boss)
+	em.Tag(boss, enemyTag, bossTag)
+
+	player := em.NewEntity()
+	ecs.AddComponent[TransformComponent](em, player)
+	em.Tag(player, "player")
+
+	assert.True(t, em.HasTag(boss, bossTag))
+	assert.False(t, em.HasTag(grunt, bossTag))
+	assert.Equal(t, []ecs.Tag{bossTag, enemyTag}, em.Tags(boss))
+	assert.ElementsMatch(t, []ecs.EntityID{grunt, boss}, slices.Collect(ecs.QueryTagged(em, enemyTag)))
+
+	minions := ecs.NewQuery[TransformComponent](em).WithTag(enemyTag).WithoutTag(bossTag).Iter()
+	assert.Equal(t, []ecs.EntityID{grunt}, slices.Collect(minions))
+
+	em.Untag(boss, enemyTag)
+	assert.Equal(t, []ecs.EntityID{grunt}, slices.Collect(ecs.QueryTagged(em, enemyTag)))
+
+	em.Remove(grunt)
+	assert.Empty(t, slices.Collect(ecs.QueryTagged(em, enemyTag)))
+	assert.Empty(t, slices.Collect(ecs.QueryTagged(em, "unknown")))
+}
@@ -0,0 +1,151 @@
+package tilemap
+
+// Neighbors is a bitmask of the cells around a cell that belong to the same terrain.
+type Neighbors uint8
+
+// Neighbor bits. The diagonal bits are only set by an AutoTiler with Corners, and only when both
+// adjacent sides are set, which reduces the 256 combinations to the 47 tiles of a blob tileset.
+const (
+	North Neighbors = 1 << iota
+	East
+	South
+	West
+	NorthEast
+	SouthEast
+	SouthWest
+	NorthWest
+
+	sides = North | East | South | West
+)
+
+// AutoTiler picks the tile of each terrain cell of a layer from its neighbors, so that edges and
+// corners stay correct when the terrain is modified at runtime, e.g. by destructible walls.
+// A cell belongs to the terrain when it holds Fill or any of the Tiles. Without Corners, Tiles holds
+// the 16 combinations of sides, as in a Tiled Wang edge set; with Corners, the 47 blob combinations.
+type AutoTiler struct {
+	// Tiles maps the neighbors of a cell to the global tile ID drawn there.
+	Tiles map[Neighbors]uint32
+	// Fill is drawn for the combinations missing from Tiles. Combinations with corners first fall back
+	// to their sides alone.
+	Fill uint32
+	// Corners enables the diagonal neighbors.
+	Corners bool
+	// BorderFilled makes the cells outside the layer count as terrain, so that the terrain extends
+	// seamlessly past the edges of the map.
+	BorderFilled bool
+}
+
+// Contains reports whether the raw global tile ID belongs to the terrain.
+func (a *AutoTiler) Contains(raw uint32) bool {
+	gid := GID(raw)
+	if gid == 0 {
+		return false
+	}
+
+	if gid == a.Fill {
+		return true
+	}
+
+	for _, tile := range a.Tiles {
+		if tile == gid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Neighbors returns the neighbors of the cell that belong to the terrain.
+func (a *AutoTiler) Neighbors(layer *Layer, x, y int) Neighbors {
+	var mask Neighbors
+	for _, offset := range neighborOffsets {
+		if a.filled(layer, x+offset.dx, y+offset.dy) {
+			mask |= offset.bit
+		}
+	}
+
+	if !a.Corners {
+		return mask & sides
+	}
+
+	for _, corner := range corners {
+		if mask&corner.sides != corner.sides {
+			mask &^= corner.bit
+		}
+	}
+
+	return mask
+}
+
+// Tile returns the global tile ID for a terrain cell with the given neighbors.
+func (a *AutoTiler) Tile(mask Neighbors) uint32 {
+	if tile, ok := a.Tiles[mask]; ok {
+		return tile
+	}
+
+	if tile, ok := a.Tiles[mask&sides]; ok {
+		return tile
+	}
+
+	return a.Fill
+}
+
+// Set adds the terrain to the cell, or clears it, and retiles the cell and its neighbors.
+// It reports whether the cell is in range.
+func (a *AutoTiler) Set(layer *Layer, x, y int, filled bool) bool {
+	raw := uint32(0)
+	if filled {
+		raw = a.Fill
+	}
+
+	if !layer.SetTileAt(x, y, raw) {
+		return false
+	}
+
+	a.Retile(layer, x, y)
+	for _, offset := range neighborOffsets {
+		a.Retile(layer, x+offset.dx, y+offset.dy)
+	}
+
+	return true
+}
+
+// Retile replaces the tile of the cell, if it belongs to the terrain, by the one matching its neighbors.
+func (a *AutoTiler) Retile(layer *Layer, x, y int) {
+	if !a.Contains(layer.TileAt(x, y)) {
+		return
+	}
+
+	layer.SetTileAt(x, y, a.Tile(a.Neighbors(layer, x, y)))
+}
+
+// RetileAll retiles every terrain cell of the layer, e.g. after loading a map painted with Fill only.
+func (a *AutoTiler) RetileAll(layer *Layer) {
+	for y := range layer.Height {
+		for x := range layer.Width {
+			a.Retile(layer, x, y)
+		}
+	}
+}
+
+func (a *AutoTiler) filled(layer *Layer, x, y int) bool {
+	if x < 0 || y < 0 || x >= layer.Width || y >= layer.Height {
+		return a.BorderFilled
+	}
+
+	return a.Contains(layer.TileAt(x, y))
+}
+
+var neighborOffsets = [...]struct {
+	dx, dy int
+	bit    Neighbors
+}{
+	{0, -1, North}, {1, 0, East}, {0, 1, South}, {-1, 0, West},
+	{1, -1, NorthEast}, {1, 1, SouthEast}, {-1, 1, SouthWest}, {-1, -1, NorthWest},
+}
+
+var corners = [...]struct {
+	bit, sides Neighbors
+}{
+	{NorthEast, North | East}, {SouthEast, South | East}, {SouthWest, South | West}, {NorthWest, North | West},
+}
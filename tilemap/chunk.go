@@ -0,0 +1,312 @@
+package tilemap
+
+import (
+	"cmp"
+	"image"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// DefaultChunkSize is the width and height of chunks in tiles, unless changed with ChunkSystem.SetChunkSize.
+const DefaultChunkSize = 32
+
+// Chunk is a square region of a map, streamed in as entities while it is near a camera.
+type Chunk struct {
+	// X and Y are the coordinates of the chunk, in chunks: chunk {1, 0} starts ChunkSize tiles right of the map origin.
+	X, Y int
+	// Layers hold the tiles of the chunk, one per layer of the map, in the same order. Changes made while
+	// the chunk is loaded are copied back to the map when it unloads.
+	Layers []*Layer
+	// Entities are removed when the chunk unloads: the layer entities, and the colliders and prefab
+	// instances of the objects positioned in the chunk. Load hooks may add their own.
+	Entities []ecs.Ref
+}
+
+// ChunkFunc is a hook called when a chunk is loaded or unloaded.
+type ChunkFunc func(em *ecs.EntityManager, chunk *Chunk)
+
+// ChunkSystem streams a map far larger than the screen: instead of spawning the whole map like Spawner.Spawn,
+// it splits it into chunks and only spawns the chunks in view of a render.Camera, extended by a margin.
+// Each loaded chunk has a TileLayer entity per layer, drawn by the RenderSystem, and the colliders and prefab
+// instances of its objects. Chunks farther than one chunk beyond the margin are unloaded and their entities removed.
+//
+// A generator hook, set with SetGenerator, is called on every chunk loaded, e.g. to fill its tiles procedurally.
+// With a generator, chunks are also loaded outside of the map bounds, so the world is unbounded.
+// Chunks are laid out for orthogonal maps; Y-sorted layers are spawned as TileLayers.
+type ChunkSystem struct {
+	*ecs.BaseSystem
+
+	m       *Map
+	spawner *Spawner
+
+	chunkSize  int
+	loadMargin float64
+	generate   ChunkFunc
+	unload     ChunkFunc
+
+	chunks map[image.Point]*Chunk
+	// wanted and kept are the chunks to load and to keep loaded, rebuilt every update.
+	wanted, kept map[image.Point]bool
+	points       []image.Point
+}
+
+// NewChunkSystem creates a new ChunkSystem with the given ID and priority, streaming m. Objects are spawned
+// with spawner, or only their colliders if it is nil.
+func NewChunkSystem(id ecs.SystemID, priority int, m *Map, spawner *Spawner) *ChunkSystem {
+	if spawner == nil {
+		spawner = NewSpawner()
+	}
+
+	return &ChunkSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		m:          m,
+		spawner:    spawner,
+		chunkSize:  DefaultChunkSize,
+		chunks:     make(map[image.Point]*Chunk),
+		wanted:     make(map[image.Point]bool),
+		kept:       make(map[image.Point]bool),
+	}
+}
+
+// SetChunkSize sets the width and height of chunks in tiles. It must be set before the first update.
+func (s *ChunkSystem) SetChunkSize(size int) {
+	s.chunkSize = max(size, 1)
+}
+
+// SetLoadMargin sets the distance beyond the view, in world units, within which chunks are loaded.
+func (s *ChunkSystem) SetLoadMargin(margin float64) {
+	s.loadMargin = margin
+}
+
+// SetGenerator sets the hook called after a chunk is loaded from the map and before its layers are spawned,
+// see ChunkSystem.
+func (s *ChunkSystem) SetGenerator(generate ChunkFunc) {
+	s.generate = generate
+}
+
+// SetUnloadHook sets the hook called before a chunk is unloaded, e.g. to save generated content.
+func (s *ChunkSystem) SetUnloadHook(unload ChunkFunc) {
+	s.unload = unload
+}
+
+// Chunk returns the loaded chunk at the given chunk coordinates.
+func (s *ChunkSystem) Chunk(x, y int) (*Chunk, bool) {
+	chunk, ok := s.chunks[image.Pt(x, y)]
+	return chunk, ok
+}
+
+// Chunks returns the loaded chunks, in no particular order.
+func (s *ChunkSystem) Chunks() iter.Seq[*Chunk] {
+	return maps.Values(s.chunks)
+}
+
+// Update loads the chunks coming into view and unloads the distant ones.
+func (s *ChunkSystem) Update() error {
+	em := s.EntityManager()
+
+	clear(s.wanted)
+	clear(s.kept)
+
+	width, height := s.Game().ScreenSize()
+	screen := image.Rect(0, 0, width, height)
+	found := false
+	for entityID := range ecs.Query2[render.Camera, transform.Transform](em) {
+		camera := ecs.MustGetComponent[render.Camera](em, entityID)
+		bounds := screen
+		if camera.Target != nil {
+			bounds = camera.Target.Bounds()
+		}
+
+		view := render.ViewportView(camera, ecs.MustGetComponent[transform.Transform](em, entityID), camera.Viewport.Rect(bounds))
+		s.addView(&view)
+		found = true
+	}
+
+	if !found {
+		view := render.ViewportView(nil, nil, screen)
+		s.addView(&view)
+	}
+
+	// Chunks are unloaded and loaded in row-major order, so that entity IDs are deterministic.
+	s.points = s.points[:0]
+	for point := range s.chunks {
+		if !s.kept[point] {
+			s.points = append(s.points, point)
+		}
+	}
+
+	for _, point := range sortPoints(s.points) {
+		s.unloadChunk(em, point)
+	}
+
+	s.points = s.points[:0]
+	for point := range s.wanted {
+		if _, ok := s.chunks[point]; !ok {
+			s.points = append(s.points, point)
+		}
+	}
+
+	for _, point := range sortPoints(s.points) {
+		s.loadChunk(em, point)
+	}
+
+	return nil
+}
+
+func sortPoints(points []image.Point) []image.Point {
+	slices.SortFunc(points, func(a, b image.Point) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+
+	return points
+}
+
+// Teardown copies the loaded chunks back to the map.
+func (s *ChunkSystem) Teardown() {
+	for _, chunk := range s.chunks {
+		s.store(chunk)
+	}
+	clear(s.chunks)
+}
+
+// addView marks the chunks overlapping the view, extended by the margin, to be loaded,
+// and those overlapping it extended by one more chunk to be kept.
+func (s *ChunkSystem) addView(view *render.View) {
+	chunkWidth, chunkHeight := s.chunkPixelSize()
+	s.addChunks(s.wanted, view, s.loadMargin)
+	s.addChunks(s.kept, view, s.loadMargin+max(chunkWidth, chunkHeight))
+}
+
+func (s *ChunkSystem) addChunks(chunks map[image.Point]bool, view *render.View, margin float64) {
+	chunkWidth, chunkHeight := s.chunkPixelSize()
+	minX := int(math.Floor((view.Min[0] - margin) / chunkWidth))
+	minY := int(math.Floor((view.Min[1] - margin) / chunkHeight))
+	maxX := int(math.Floor((view.Max[0] + margin) / chunkWidth))
+	maxY := int(math.Floor((view.Max[1] + margin) / chunkHeight))
+
+	if s.generate == nil {
+		columns, rows := s.chunkCount()
+		minX, minY = max(minX, 0), max(minY, 0)
+		maxX, maxY = min(maxX, columns-1), min(maxY, rows-1)
+	}
+
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			chunks[image.Pt(x, y)] = true
+		}
+	}
+}
+
+// loadChunk copies the tiles of the chunk from the map, calls the generator and spawns its entities.
+func (s *ChunkSystem) loadChunk(em *ecs.EntityManager, point image.Point) {
+	chunk := &Chunk{X: point.X, Y: point.Y}
+	originX, originY := point.X*s.chunkSize, point.Y*s.chunkSize
+
+	for _, layer := range s.m.Layers {
+		chunkLayer := &Layer{
+			ID:         layer.ID,
+			Name:       layer.Name,
+			Width:      s.chunkSize,
+			Height:     s.chunkSize,
+			OffsetX:    layer.OffsetX,
+			OffsetY:    layer.OffsetY,
+			Opacity:    layer.Opacity,
+			Visible:    layer.Visible,
+			Properties: layer.Properties,
+			Tiles:      make([]uint32, s.chunkSize*s.chunkSize),
+		}
+
+		for y := range s.chunkSize {
+			for x := range s.chunkSize {
+				chunkLayer.Tiles[y*s.chunkSize+x] = layer.TileAt(originX+x, originY+y)
+			}
+		}
+
+		chunk.Layers = append(chunk.Layers, chunkLayer)
+	}
+
+	if s.generate != nil {
+		s.generate(em, chunk)
+	}
+
+	chunkWidth, chunkHeight := s.chunkPixelSize()
+	for i, layer := range chunk.Layers {
+		entityID := em.NewEntity()
+
+		tr := ecs.AddComponent[transform.Transform](em, entityID)
+		tr.Position = f64.Vec2{float64(point.X)*chunkWidth + layer.OffsetX, float64(point.Y)*chunkHeight + layer.OffsetY}
+
+		tileLayer := ecs.AddComponent[TileLayer](em, entityID)
+		tileLayer.Map = s.m
+		tileLayer.Layer = layer
+		tileLayer.Order = i
+
+		chunk.Entities = append(chunk.Entities, em.Ref(entityID))
+	}
+
+	var entities []ecs.EntityID
+	for _, group := range s.m.ObjectGroups {
+		for _, object := range group.Objects {
+			position := s.m.ObjectPosition(group, object)
+			if int(math.Floor(position[0]/chunkWidth)) == point.X && int(math.Floor(position[1]/chunkHeight)) == point.Y {
+				entities = s.spawner.spawnObject(em, s.m, group, object, entities)
+			}
+		}
+	}
+
+	for _, entityID := range entities {
+		chunk.Entities = append(chunk.Entities, em.Ref(entityID))
+	}
+
+	s.chunks[point] = chunk
+}
+
+// unloadChunk calls the unload hook, removes the entities of the chunk and copies its tiles back to the map.
+func (s *ChunkSystem) unloadChunk(em *ecs.EntityManager, point image.Point) {
+	chunk := s.chunks[point]
+	if s.unload != nil {
+		s.unload(em, chunk)
+	}
+
+	for _, ref := range chunk.Entities {
+		if entityID, ok := ref.Get(em); ok {
+			em.Remove(entityID)
+		}
+	}
+
+	s.store(chunk)
+	delete(s.chunks, point)
+}
+
+// store copies the tiles of the chunk back to the layers of the map.
+func (s *ChunkSystem) store(chunk *Chunk) {
+	originX, originY := chunk.X*s.chunkSize, chunk.Y*s.chunkSize
+	for i, layer := range s.m.Layers {
+		if i >= len(chunk.Layers) {
+			break
+		}
+
+		for y := range s.chunkSize {
+			for x := range s.chunkSize {
+				layer.SetTileAt(originX+x, originY+y, chunk.Layers[i].TileAt(x, y))
+			}
+		}
+	}
+}
+
+// chunkPixelSize returns the size of a chunk in world units.
+func (s *ChunkSystem) chunkPixelSize() (width, height float64) {
+	return float64(s.chunkSize * max(s.m.TileWidth, 1)), float64(s.chunkSize * max(s.m.TileHeight, 1))
+}
+
+// chunkCount returns the number of chunks covering the map.
+func (s *ChunkSystem) chunkCount() (columns, rows int) {
+	return (s.m.Width + s.chunkSize - 1) / s.chunkSize, (s.m.Height + s.chunkSize - 1) / s.chunkSize
+}
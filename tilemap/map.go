@@ -0,0 +1,529 @@
+// Package tilemap loads Tiled (https://www.mapeditor.org) TMX maps and TSX tilesets
+// and turns them into entities: tile layers become render entities, object layers
+// marked for collision become colliders, and typed objects spawn registered prefabs.
+package tilemap
+
+import (
+	"fmt"
+	"image"
+	_ "image/png" // Tileset images are usually PNGs.
+	"io/fs"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/math/f64"
+)
+
+// Flags stored in the high bits of a global tile ID.
+const (
+	FlippedHorizontally uint32 = 0x80000000
+	FlippedVertically   uint32 = 0x40000000
+	FlippedDiagonally   uint32 = 0x20000000
+	RotatedHexagonal120 uint32 = 0x10000000
+
+	gidMask = ^(FlippedHorizontally | FlippedVertically | FlippedDiagonally | RotatedHexagonal120)
+)
+
+// GID strips the flip flags from a global tile ID.
+func GID(raw uint32) uint32 {
+	return raw & gidMask
+}
+
+// Properties holds the custom properties of a map element.
+type Properties map[string]string
+
+// String returns the property value, or def if the property is not set.
+func (p Properties) String(name, def string) string {
+	if v, ok := p[name]; ok {
+		return v
+	}
+
+	return def
+}
+
+// Bool returns the property value as a bool, or def if the property is not set or malformed.
+func (p Properties) Bool(name string, def bool) bool {
+	v, err := strconv.ParseBool(p[name])
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Float returns the property value as a float64, or def if the property is not set or malformed.
+func (p Properties) Float(name string, def float64) float64 {
+	v, err := strconv.ParseFloat(p[name], 64)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Int returns the property value as an int, or def if the property is not set or malformed.
+func (p Properties) Int(name string, def int) int {
+	v, err := strconv.Atoi(p[name])
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Tile holds the per-tile metadata declared in a tileset.
+type Tile struct {
+	ID         uint32
+	Type       string
+	Properties Properties
+
+	// Animation is the sequence of frames the tile cycles through, empty for static tiles.
+	Animation []Frame
+}
+
+// Frame is a frame of an animated tile.
+type Frame struct {
+	// TileID is the ID of the tile shown, local to the tileset.
+	TileID   uint32
+	Duration time.Duration
+}
+
+// Tileset is a set of equally sized tiles cut from a single image.
+type Tileset struct {
+	FirstGID   uint32
+	Name       string
+	TileWidth  int
+	TileHeight int
+	TileCount  int
+	Columns    int
+	Spacing    int
+	Margin     int
+	Properties Properties
+	Tiles      map[uint32]*Tile
+
+	ImageSource string
+	Image       *ebiten.Image
+}
+
+// Contains reports whether the global tile ID belongs to this tileset.
+func (ts *Tileset) Contains(gid uint32) bool {
+	gid = GID(gid)
+	return gid >= ts.FirstGID && gid < ts.FirstGID+uint32(ts.TileCount)
+}
+
+// SourceRect returns the rectangle of the tile within the tileset image.
+func (ts *Tileset) SourceRect(gid uint32) image.Rectangle {
+	local := int(GID(gid) - ts.FirstGID)
+	columns := max(ts.Columns, 1)
+
+	x := ts.Margin + (local%columns)*(ts.TileWidth+ts.Spacing)
+	y := ts.Margin + (local/columns)*(ts.TileHeight+ts.Spacing)
+
+	return image.Rect(x, y, x+ts.TileWidth, y+ts.TileHeight)
+}
+
+// Animate returns the raw global tile ID shown in place of raw after elapsed time, following the Animation
+// of its tile, with the flip flags of raw. Static tiles and tiles of other tilesets are returned unchanged.
+func (ts *Tileset) Animate(raw uint32, elapsed time.Duration) uint32 {
+	if !ts.Contains(raw) {
+		return raw
+	}
+
+	tile, ok := ts.Tiles[GID(raw)-ts.FirstGID]
+	if !ok || len(tile.Animation) == 0 {
+		return raw
+	}
+
+	var total time.Duration
+	for _, frame := range tile.Animation {
+		total += frame.Duration
+	}
+
+	if total <= 0 {
+		return raw
+	}
+
+	elapsed %= total
+	if elapsed < 0 {
+		elapsed += total
+	}
+
+	for _, frame := range tile.Animation {
+		if elapsed < frame.Duration {
+			return raw&^gidMask | (ts.FirstGID + frame.TileID)
+		}
+		elapsed -= frame.Duration
+	}
+
+	return raw
+}
+
+// Layer is a grid of tiles.
+type Layer struct {
+	ID         int
+	Name       string
+	Width      int
+	Height     int
+	OffsetX    float64
+	OffsetY    float64
+	Opacity    float64
+	Visible    bool
+	Properties Properties
+
+	// Tiles holds raw global tile IDs (including flip flags) in row-major order. Zero means empty.
+	Tiles []uint32
+}
+
+// TileAt returns the raw global tile ID at the given cell, or 0 if the cell is empty or out of range.
+func (l *Layer) TileAt(x, y int) uint32 {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return 0
+	}
+
+	return l.Tiles[y*l.Width+x]
+}
+
+// SetTileAt sets the raw global tile ID at the given cell, zero clearing it.
+// It reports whether the cell is in range.
+func (l *Layer) SetTileAt(x, y int, raw uint32) bool {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return false
+	}
+
+	l.Tiles[y*l.Width+x] = raw
+	return true
+}
+
+// Object is a shape or tile object placed on an object layer.
+type Object struct {
+	ID         int
+	Name       string
+	Type       string
+	X, Y       float64
+	Width      float64
+	Height     float64
+	Rotation   float64
+	GID        uint32
+	Visible    bool
+	Ellipse    bool
+	Point      bool
+	Polygon    []f64.Vec2
+	Polyline   []f64.Vec2
+	Properties Properties
+}
+
+// ObjectGroup is an object layer.
+type ObjectGroup struct {
+	ID         int
+	Name       string
+	OffsetX    float64
+	OffsetY    float64
+	Visible    bool
+	Properties Properties
+	Objects    []*Object
+}
+
+// Map is a parsed Tiled map with its tilesets resolved.
+type Map struct {
+	// Orientation is Orthogonal, Isometric, Staggered or Hexagonal, see CellOrigin.
+	Orientation string
+	// StaggerAxis ("x" or "y") and StaggerIndex ("odd" or "even") select the shifted columns or rows of
+	// staggered and hexagonal maps, and HexSideLength is the length of the flat side of hexagons in pixels.
+	StaggerAxis   string
+	StaggerIndex  string
+	HexSideLength int
+
+	RenderOrder  string
+	Width        int
+	Height       int
+	TileWidth    int
+	TileHeight   int
+	Properties   Properties
+	Tilesets     []*Tileset
+	Layers       []*Layer
+	ObjectGroups []*ObjectGroup
+}
+
+// TilesetFor returns the tileset that owns the global tile ID.
+func (m *Map) TilesetFor(gid uint32) (*Tileset, bool) {
+	gid = GID(gid)
+	if gid == 0 {
+		return nil, false
+	}
+
+	for i := len(m.Tilesets) - 1; i >= 0; i-- {
+		if m.Tilesets[i].FirstGID <= gid {
+			return m.Tilesets[i], m.Tilesets[i].Contains(gid)
+		}
+	}
+
+	return nil, false
+}
+
+// Layer returns the tile layer with the given name.
+func (m *Map) Layer(name string) (*Layer, bool) {
+	for _, layer := range m.Layers {
+		if layer.Name == name {
+			return layer, true
+		}
+	}
+
+	return nil, false
+}
+
+// ObjectGroup returns the object layer with the given name.
+func (m *Map) ObjectGroup(name string) (*ObjectGroup, bool) {
+	for _, group := range m.ObjectGroups {
+		if group.Name == name {
+			return group, true
+		}
+	}
+
+	return nil, false
+}
+
+// Load parses a TMX map from fsys, resolving external TSX tilesets and loading tileset images
+// relative to the map file. fsys may be an embed.FS.
+func Load(fsys fs.FS, name string) (*Map, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.Load fsys.Open error: %w", err)
+	}
+	defer f.Close()
+
+	raw, err := decodeTMX(f)
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.Load decodeTMX error: %w", err)
+	}
+
+	m, err := newMap(raw, fsys, path.Dir(name))
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.Load newMap error: %w", err)
+	}
+
+	return m, nil
+}
+
+func newMap(raw *tmxMap, fsys fs.FS, dir string) (*Map, error) {
+	m := &Map{
+		Orientation:   raw.Orientation,
+		StaggerAxis:   raw.StaggerAxis,
+		StaggerIndex:  raw.StaggerIndex,
+		HexSideLength: raw.HexSide,
+		RenderOrder:   raw.RenderOrder,
+		Width:         raw.Width,
+		Height:        raw.Height,
+		TileWidth:     raw.TileWidth,
+		TileHeight:    raw.TileHeight,
+		Properties:    newProperties(raw.Properties),
+	}
+
+	if raw.Infinite != 0 {
+		return nil, fmt.Errorf("tilemap.newMap: infinite maps are not supported")
+	}
+
+	for _, rawTileset := range raw.Tilesets {
+		tileset, err := loadTileset(rawTileset, fsys, dir)
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.newMap loadTileset error: %w", err)
+		}
+		m.Tilesets = append(m.Tilesets, tileset)
+	}
+
+	for _, rawLayer := range raw.Layers {
+		tiles, err := decodeTileData(rawLayer.Data.Encoding, rawLayer.Data.Compression, rawLayer.Data.Data)
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.newMap layer %q decodeTileData error: %w", rawLayer.Name, err)
+		}
+
+		if len(tiles) != rawLayer.Width*rawLayer.Height {
+			return nil, fmt.Errorf("tilemap.newMap: layer %q has %d tiles, expected %d", rawLayer.Name, len(tiles), rawLayer.Width*rawLayer.Height)
+		}
+
+		opacity := 1.0
+		if rawLayer.Opacity != nil {
+			opacity = *rawLayer.Opacity
+		}
+
+		m.Layers = append(m.Layers, &Layer{
+			ID:         rawLayer.ID,
+			Name:       rawLayer.Name,
+			Width:      rawLayer.Width,
+			Height:     rawLayer.Height,
+			OffsetX:    rawLayer.OffsetX,
+			OffsetY:    rawLayer.OffsetY,
+			Opacity:    opacity,
+			Visible:    visible(rawLayer.Visible),
+			Properties: newProperties(rawLayer.Properties),
+			Tiles:      tiles,
+		})
+	}
+
+	for _, rawGroup := range raw.ObjectGroups {
+		group := &ObjectGroup{
+			ID:         rawGroup.ID,
+			Name:       rawGroup.Name,
+			OffsetX:    rawGroup.OffsetX,
+			OffsetY:    rawGroup.OffsetY,
+			Visible:    visible(rawGroup.Visible),
+			Properties: newProperties(rawGroup.Properties),
+		}
+
+		for _, rawObject := range rawGroup.Objects {
+			object, err := newObject(rawObject)
+			if err != nil {
+				return nil, fmt.Errorf("tilemap.newMap object %d newObject error: %w", rawObject.ID, err)
+			}
+			group.Objects = append(group.Objects, object)
+		}
+
+		m.ObjectGroups = append(m.ObjectGroups, group)
+	}
+
+	return m, nil
+}
+
+func loadTileset(raw tmxTileset, fsys fs.FS, dir string) (*Tileset, error) {
+	firstGID := raw.FirstGID
+
+	if raw.Source != "" {
+		source := path.Join(dir, raw.Source)
+
+		f, err := fsys.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.loadTileset fsys.Open error: %w", err)
+		}
+		defer f.Close()
+
+		external, err := decodeTSX(f)
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.loadTileset decodeTSX error: %w", err)
+		}
+
+		raw = *external
+		dir = path.Dir(source)
+	}
+
+	ts := &Tileset{
+		FirstGID:    firstGID,
+		Name:        raw.Name,
+		TileWidth:   raw.TileWidth,
+		TileHeight:  raw.TileHeight,
+		TileCount:   raw.TileCount,
+		Columns:     raw.Columns,
+		Spacing:     raw.Spacing,
+		Margin:      raw.Margin,
+		Properties:  newProperties(raw.Properties),
+		Tiles:       make(map[uint32]*Tile, len(raw.Tiles)),
+		ImageSource: raw.Image.Source,
+	}
+
+	for _, rawTile := range raw.Tiles {
+		tile := &Tile{
+			ID:         rawTile.ID,
+			Type:       firstNonEmpty(rawTile.Class, rawTile.Type),
+			Properties: newProperties(rawTile.Properties),
+		}
+
+		for _, frame := range rawTile.Animation {
+			tile.Animation = append(tile.Animation, Frame{
+				TileID:   frame.TileID,
+				Duration: time.Duration(frame.Duration) * time.Millisecond,
+			})
+		}
+
+		ts.Tiles[rawTile.ID] = tile
+	}
+
+	if raw.Image.Source == "" {
+		return ts, nil
+	}
+
+	img, err := loadImage(fsys, path.Join(dir, raw.Image.Source))
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.loadTileset loadImage error: %w", err)
+	}
+	ts.Image = img
+
+	return ts, nil
+}
+
+func loadImage(fsys fs.FS, name string) (*ebiten.Image, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.loadImage fsys.Open error: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.loadImage image.Decode error: %w", err)
+	}
+
+	return ebiten.NewImageFromImage(img), nil
+}
+
+func newObject(raw tmxObject) (*Object, error) {
+	object := &Object{
+		ID:         raw.ID,
+		Name:       raw.Name,
+		Type:       firstNonEmpty(raw.Class, raw.Type),
+		X:          raw.X,
+		Y:          raw.Y,
+		Width:      raw.Width,
+		Height:     raw.Height,
+		Rotation:   raw.Rotation,
+		GID:        raw.GID,
+		Visible:    visible(raw.Visible),
+		Ellipse:    raw.Ellipse != nil,
+		Point:      raw.Point != nil,
+		Properties: newProperties(raw.Properties),
+	}
+
+	if raw.Polygon != nil {
+		points, err := parsePoints(raw.Polygon.Points)
+		if err != nil {
+			return nil, err
+		}
+		object.Polygon = points
+	}
+
+	if raw.Polyline != nil {
+		points, err := parsePoints(raw.Polyline.Points)
+		if err != nil {
+			return nil, err
+		}
+		object.Polyline = points
+	}
+
+	return object, nil
+}
+
+func newProperties(raw tmxProperties) Properties {
+	props := make(Properties, len(raw.Properties))
+	for _, p := range raw.Properties {
+		value := p.Value
+		if value == "" {
+			value = p.Text
+		}
+		props[p.Name] = value
+	}
+
+	return props
+}
+
+func visible(v *int) bool {
+	return v == nil || *v != 0
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,184 @@
+package tilemap
+
+import (
+	"math"
+
+	"golang.org/x/image/math/f64"
+)
+
+// Map orientations.
+const (
+	Orthogonal = "orthogonal"
+	Isometric  = "isometric"
+	Staggered  = "staggered"
+	Hexagonal  = "hexagonal"
+)
+
+// CellOrigin returns the top-left corner of the bounding box of the cell, in map pixels:
+// TileWidth x TileHeight boxes laid out in a grid for orthogonal maps, as diamonds whose top corner
+// is the tile {0, 0} for isometric maps, and in shifted rows or columns for staggered and hexagonal maps,
+// following Tiled. Tiles taller than the grid are drawn with their bottom-left corner at the bottom-left
+// corner of the box.
+func (m *Map) CellOrigin(x, y int) f64.Vec2 {
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+
+	switch m.Orientation {
+	case Isometric:
+		originX := float64(m.Height) * tw / 2
+		return f64.Vec2{float64(x-y)*tw/2 + originX - tw/2, float64(x+y) * th / 2}
+	case Staggered, Hexagonal:
+		p := m.stagger()
+		if p.staggerX {
+			point := f64.Vec2{float64(x) * p.columnWidth, float64(y) * (p.tileHeight + p.sideLengthY)}
+			if p.staggered(x) {
+				point[1] += p.rowHeight
+			}
+			return point
+		}
+
+		point := f64.Vec2{float64(x) * (p.tileWidth + p.sideLengthX), float64(y) * p.rowHeight}
+		if p.staggered(y) {
+			point[0] += p.columnWidth
+		}
+		return point
+	default:
+		return f64.Vec2{float64(x) * tw, float64(y) * th}
+	}
+}
+
+// CellCenter returns the center of the cell, in map pixels, e.g. to place an entity on it.
+func (m *Map) CellCenter(x, y int) f64.Vec2 {
+	origin := m.CellOrigin(x, y)
+	return f64.Vec2{origin[0] + float64(m.TileWidth)/2, origin[1] + float64(m.TileHeight)/2}
+}
+
+// CellAt returns the cell containing the point, in map pixels. The cell may be outside of the map.
+// Combined with render.View.ToWorld, it converts a position on the screen to a cell, e.g. under the cursor.
+func (m *Map) CellAt(point f64.Vec2) (x, y int) {
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+
+	switch m.Orientation {
+	case Isometric:
+		px := point[0] - float64(m.Height)*tw/2
+		tileY, tileX := point[1]/th, px/tw
+		return int(math.Floor(tileY + tileX)), int(math.Floor(tileY - tileX))
+	case Staggered, Hexagonal:
+		return m.staggeredCellAt(point)
+	default:
+		return int(math.Floor(point[0] / tw)), int(math.Floor(point[1] / th))
+	}
+}
+
+// ObjectPosition returns the position of the object in map pixels. Tiled stores the positions of objects on
+// isometric maps along the tile axes, in units of TileHeight; the other orientations store map pixels.
+func (m *Map) ObjectPosition(group *ObjectGroup, object *Object) f64.Vec2 {
+	x, y := object.X, object.Y
+	if m.Orientation == Isometric && m.TileHeight > 0 {
+		tw, th := float64(m.TileWidth), float64(m.TileHeight)
+		tileX, tileY := x/th, y/th
+		x, y = (tileX-tileY)*tw/2+float64(m.Height)*tw/2, (tileX+tileY)*th/2
+	}
+
+	return f64.Vec2{x + group.OffsetX, y + group.OffsetY}
+}
+
+// PixelSize returns the size of the bounding box of the map in pixels.
+func (m *Map) PixelSize() (width, height int) {
+	switch m.Orientation {
+	case Isometric:
+		return (m.Width + m.Height) * m.TileWidth / 2, (m.Width + m.Height) * m.TileHeight / 2
+	case Staggered, Hexagonal:
+		p := m.stagger()
+		if p.staggerX {
+			height := float64(m.Height) * (p.tileHeight + p.sideLengthY)
+			if m.Width > 1 {
+				height += p.rowHeight
+			}
+			return int(float64(m.Width)*p.columnWidth + p.sideOffsetX), int(height)
+		}
+
+		width := float64(m.Width) * (p.tileWidth + p.sideLengthX)
+		if m.Height > 1 {
+			width += p.columnWidth
+		}
+		return int(width), int(float64(m.Height)*p.rowHeight + p.sideOffsetY)
+	default:
+		return m.Width * m.TileWidth, m.Height * m.TileHeight
+	}
+}
+
+// staggerParams are the dimensions of the cells of staggered and hexagonal maps, as computed by Tiled.
+type staggerParams struct {
+	staggerX, staggerEven    bool
+	tileWidth, tileHeight    float64
+	sideLengthX, sideLengthY float64
+	sideOffsetX, sideOffsetY float64
+	columnWidth, rowHeight   float64
+}
+
+func (m *Map) stagger() staggerParams {
+	p := staggerParams{
+		staggerX:    m.StaggerAxis == "x",
+		staggerEven: m.StaggerIndex == "even",
+		tileWidth:   float64(m.TileWidth &^ 1),
+		tileHeight:  float64(m.TileHeight &^ 1),
+	}
+
+	if m.Orientation == Hexagonal {
+		if p.staggerX {
+			p.sideLengthX = float64(m.HexSideLength)
+		} else {
+			p.sideLengthY = float64(m.HexSideLength)
+		}
+	}
+
+	p.sideOffsetX = (p.tileWidth - p.sideLengthX) / 2
+	p.sideOffsetY = (p.tileHeight - p.sideLengthY) / 2
+	p.columnWidth = p.sideOffsetX + p.sideLengthX
+	p.rowHeight = p.sideOffsetY + p.sideLengthY
+
+	return p
+}
+
+// staggered reports whether the column, or row, at index i is shifted.
+func (p staggerParams) staggered(i int) bool {
+	return (i&1 == 1) != p.staggerEven
+}
+
+// staggeredCellAt returns the cell of a staggered or hexagonal map containing the point: among the cells
+// around the estimate from the grid, the one whose center is the closest, measured along the diamond
+// edges for staggered maps.
+func (m *Map) staggeredCellAt(point f64.Vec2) (x, y int) {
+	p := m.stagger()
+
+	var column, row int
+	if p.staggerX {
+		column = int(math.Floor(point[0] / p.columnWidth))
+		row = int(math.Floor(point[1] / (p.tileHeight + p.sideLengthY)))
+	} else {
+		column = int(math.Floor(point[0] / (p.tileWidth + p.sideLengthX)))
+		row = int(math.Floor(point[1] / p.rowHeight))
+	}
+
+	halfWidth, halfHeight := float64(m.TileWidth)/2, float64(m.TileHeight)/2
+	best := math.Inf(1)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			center := m.CellCenter(column+dx, row+dy)
+			offsetX, offsetY := point[0]-center[0], point[1]-center[1]
+
+			var distance float64
+			if m.Orientation == Staggered {
+				distance = math.Abs(offsetX)/halfWidth + math.Abs(offsetY)/halfHeight
+			} else {
+				distance = math.Hypot(offsetX, offsetY)
+			}
+
+			if distance < best {
+				best, x, y = distance, column+dx, row+dy
+			}
+		}
+	}
+
+	return x, y
+}
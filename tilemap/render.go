@@ -0,0 +1,156 @@
+package tilemap
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+)
+
+var _ ecs.DrawableSystem = (*RenderSystem)(nil)
+
+// RenderSystem draws every visible TileLayer entity. Animated tiles are drawn at the frame reached
+// after the scaled game time elapsed since the system was added, so they stop while the game is paused.
+type RenderSystem struct {
+	*ecs.BaseSystem
+
+	elapsed time.Duration
+
+	layers []layerEntry
+	passes []render.CameraPass
+	op     ebiten.DrawImageOptions
+}
+
+type layerEntry struct {
+	tileLayer *TileLayer
+	transform *transform.Transform
+}
+
+// NewRenderSystem creates a new RenderSystem with the given ID and priority.
+func NewRenderSystem(id ecs.SystemID, priority int) *RenderSystem {
+	return &RenderSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+}
+
+// Update advances the tile animations.
+func (s *RenderSystem) Update() error {
+	s.elapsed += s.Time().DeltaDuration()
+	return nil
+}
+
+// Draw draws the tile layers in map order, through each render.Camera, see render.AppendCameraPasses.
+func (s *RenderSystem) Draw(screen *ebiten.Image) {
+	em := s.EntityManager()
+
+	s.layers = s.layers[:0]
+	for entityID := range ecs.Query2[TileLayer, transform.Transform](em) {
+		s.layers = append(s.layers, layerEntry{
+			tileLayer: ecs.MustGetComponent[TileLayer](em, entityID),
+			transform: ecs.MustGetComponent[transform.Transform](em, entityID),
+		})
+	}
+
+	slices.SortStableFunc(s.layers, func(a, b layerEntry) int {
+		return cmp.Compare(a.tileLayer.Order, b.tileLayer.Order)
+	})
+
+	s.passes = render.AppendCameraPasses(s.passes[:0], em, screen)
+	for _, pass := range s.passes {
+		for _, entry := range s.layers {
+			if entry.tileLayer.Layer == nil || !entry.tileLayer.Layer.Visible {
+				continue
+			}
+
+			s.drawLayer(pass.Canvas, &pass.View, entry.tileLayer, entry.transform)
+		}
+	}
+}
+
+func (s *RenderSystem) drawLayer(canvas ecs.Canvas, view *render.View, tileLayer *TileLayer, tr *transform.Transform) {
+	m, layer := tileLayer.Map, tileLayer.Layer
+
+	// Rows are drawn from top to bottom. On maps staggered along x, the shifted columns of a row are lower,
+	// so they are drawn after the others.
+	var stagger staggerParams
+	if m.Orientation == Staggered || m.Orientation == Hexagonal {
+		stagger = m.stagger()
+	}
+
+	for y := range layer.Height {
+		for x := range layer.Width {
+			if !stagger.staggerX || !stagger.staggered(x) {
+				s.drawTile(canvas, view, m, layer, tr, x, y)
+			}
+		}
+
+		if !stagger.staggerX {
+			continue
+		}
+
+		for x := range layer.Width {
+			if stagger.staggered(x) {
+				s.drawTile(canvas, view, m, layer, tr, x, y)
+			}
+		}
+	}
+}
+
+func (s *RenderSystem) drawTile(canvas ecs.Canvas, view *render.View, m *Map, layer *Layer, tr *transform.Transform, x, y int) {
+	raw := layer.TileAt(x, y)
+	if raw == 0 {
+		return
+	}
+
+	tileset, ok := m.TilesetFor(raw)
+	if !ok || tileset.Image == nil {
+		return
+	}
+	raw = tileset.Animate(raw, s.elapsed)
+
+	origin := m.CellOrigin(x, y)
+
+	s.op.GeoM.Reset()
+	s.op.ColorScale.Reset()
+	applyFlip(&s.op.GeoM, raw, tileset.TileWidth, tileset.TileHeight)
+	// Tiles taller than the grid are anchored at the bottom-left corner of their cell.
+	s.op.GeoM.Translate(
+		origin[0]+tr.Position[0],
+		origin[1]+float64(m.TileHeight-tileset.TileHeight)+tr.Position[1],
+	)
+	s.op.GeoM.Concat(view.GeoM)
+	s.op.ColorScale.ScaleAlpha(float32(layer.Opacity))
+
+	tile := tileset.Image.SubImage(tileset.SourceRect(raw)).(*ebiten.Image)
+	canvas.DrawImage(tile, &s.op)
+}
+
+// applyFlip applies the Tiled flip flags of raw to geoM, pivoting around the tile center.
+func applyFlip(geoM *ebiten.GeoM, raw uint32, width, height int) {
+	if raw&(FlippedHorizontally|FlippedVertically|FlippedDiagonally) == 0 {
+		return
+	}
+
+	w, h := float64(width), float64(height)
+	geoM.Translate(-w/2, -h/2)
+
+	if raw&FlippedDiagonally != 0 {
+		geoM.Rotate(math.Pi / 2)
+		geoM.Scale(-1, 1)
+	}
+
+	if raw&FlippedHorizontally != 0 {
+		geoM.Scale(-1, 1)
+	}
+
+	if raw&FlippedVertically != 0 {
+		geoM.Scale(1, -1)
+	}
+
+	geoM.Translate(w/2, h/2)
+}
@@ -0,0 +1,233 @@
+package tilemap
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// CollisionProperty is the custom property that marks an object layer, or a single object,
+// as solid geometry.
+const CollisionProperty = "collision"
+
+// YSortProperty is the custom property that spawns a tile layer as one render.Sprite per tile instead of
+// a TileLayer, so that its tiles, such as walls and trees, are Y-sorted with the entities by the
+// render.RenderSystem. Animations are not played on these tiles.
+const YSortProperty = "ysort"
+
+// LayerProperty is the custom property of an object, an object layer or a Y-sorted tile layer holding the
+// render.Sprite Layer of the sprites spawned from it.
+const LayerProperty = "layer"
+
+// TileLayer is the component attached to the render entity of a tile layer.
+type TileLayer struct {
+	Map   *Map
+	Layer *Layer
+
+	// Order is the index of the layer in the map; lower layers are drawn first.
+	Order int
+}
+
+func (t *TileLayer) Reset() {
+	t.Map = nil
+	t.Layer = nil
+	t.Order = 0
+}
+
+// MapObject is attached to every entity spawned from a Tiled object,
+// giving systems access to the object's name and custom properties.
+type MapObject struct {
+	Object *Object
+	Group  *ObjectGroup
+}
+
+func (o *MapObject) Reset() {
+	o.Object = nil
+	o.Group = nil
+}
+
+// Spawner turns a Map into entities.
+type Spawner struct {
+	prefabs map[string]*ecs.Prefab
+}
+
+// NewSpawner creates a Spawner with no registered prefabs.
+func NewSpawner() *Spawner {
+	return &Spawner{
+		prefabs: make(map[string]*ecs.Prefab),
+	}
+}
+
+// Register maps a Tiled object type (class) to a prefab.
+// Objects of that type are spawned from the prefab and positioned at the object's location.
+func (s *Spawner) Register(objectType string, prefab *ecs.Prefab) {
+	s.prefabs[objectType] = prefab
+}
+
+// Spawn creates the entities for m in em and returns their IDs:
+//   - one entity per tile layer with a TileLayer and a transform.Transform,
+//   - one sprite entity per tile of the layers with the "ysort" property set,
+//   - one static collider entity per object in a layer or object with the "collision" property set,
+//   - one prefab instance per object whose type has been registered,
+//   - one sprite entity per tile object, the prefab instance if any.
+func (s *Spawner) Spawn(em *ecs.EntityManager, m *Map) []ecs.EntityID {
+	entities := make([]ecs.EntityID, 0, len(m.Layers))
+
+	for i, layer := range m.Layers {
+		if layer.Properties.Bool(YSortProperty, false) {
+			entities = spawnTiles(em, m, layer, entities)
+			continue
+		}
+
+		entityID := em.NewEntity()
+
+		tr := ecs.AddComponent[transform.Transform](em, entityID)
+		tr.Position = f64.Vec2{layer.OffsetX, layer.OffsetY}
+
+		tileLayer := ecs.AddComponent[TileLayer](em, entityID)
+		tileLayer.Map = m
+		tileLayer.Layer = layer
+		tileLayer.Order = i
+
+		entities = append(entities, entityID)
+	}
+
+	for _, group := range m.ObjectGroups {
+		for _, object := range group.Objects {
+			entities = s.spawnObject(em, m, group, object, entities)
+		}
+	}
+
+	return entities
+}
+
+// spawnObject creates the collider and the prefab instance or the tile sprite of the object, if any,
+// and appends their IDs to entities.
+func (s *Spawner) spawnObject(em *ecs.EntityManager, m *Map, group *ObjectGroup, object *Object, entities []ecs.EntityID) []ecs.EntityID {
+	if object.Properties.Bool(CollisionProperty, group.Properties.Bool(CollisionProperty, false)) {
+		entities = append(entities, spawnCollider(em, m, group, object))
+	}
+
+	prefab, ok := s.prefabs[object.Type]
+	if !ok && object.GID == 0 {
+		return entities
+	}
+
+	var entityID ecs.EntityID
+	if ok {
+		entityID = prefab.Spawn(em)
+	} else {
+		entityID = em.NewEntity()
+	}
+	place(em, entityID, m, group, object)
+
+	if object.GID != 0 {
+		addTileSprite(em, entityID, m, group, object)
+	}
+
+	return append(entities, entityID)
+}
+
+func spawnCollider(em *ecs.EntityManager, m *Map, group *ObjectGroup, object *Object) ecs.EntityID {
+	entityID := em.NewEntity()
+	place(em, entityID, m, group, object)
+
+	collider := ecs.AddComponent[collision.Collider](em, entityID)
+	collider.Static = true
+	collider.Width = object.Width
+	collider.Height = object.Height
+
+	switch {
+	case object.Ellipse:
+		collider.Shape = collision.ShapeEllipse
+	case len(object.Polygon) > 0:
+		collider.Shape = collision.ShapePolygon
+		collider.Points = object.Polygon
+	default:
+		collider.Shape = collision.ShapeRect
+	}
+
+	return entityID
+}
+
+// place positions the entity at the object's location and attaches the MapObject component.
+func place(em *ecs.EntityManager, entityID ecs.EntityID, m *Map, group *ObjectGroup, object *Object) {
+	tr := ecs.AddComponent[transform.Transform](em, entityID)
+	tr.Position = m.ObjectPosition(group, object)
+	tr.Rotation = object.Rotation
+
+	mapObject := ecs.AddComponent[MapObject](em, entityID)
+	mapObject.Object = object
+	mapObject.Group = group
+}
+
+// addTileSprite gives the entity of a tile object a render.Sprite drawing its tile, unless its prefab has one.
+// Tiled positions tile objects at their bottom-left corner, or bottom center on isometric maps, which is
+// where their transform is and the point Y-sorted; the object size scales the tile.
+func addTileSprite(em *ecs.EntityManager, entityID ecs.EntityID, m *Map, group *ObjectGroup, object *Object) {
+	if ecs.HasComponent[render.Sprite](em, entityID) {
+		return
+	}
+
+	tileset, ok := m.TilesetFor(object.GID)
+	if !ok || tileset.Image == nil {
+		return
+	}
+
+	sprite := ecs.AddComponent[render.Sprite](em, entityID)
+	setTileSprite(sprite, tileset, object.GID)
+	sprite.Layer = object.Properties.Int(LayerProperty, group.Properties.Int(LayerProperty, 0))
+	sprite.Hidden = !object.Visible || !group.Visible
+	if m.Orientation == Isometric {
+		sprite.Origin[0] = float64(tileset.TileWidth) / 2
+	}
+
+	if object.Width > 0 && object.Height > 0 {
+		tr := ecs.MustGetComponent[transform.Transform](em, entityID)
+		tr.Scale = f64.Vec2{object.Width / float64(tileset.TileWidth), object.Height / float64(tileset.TileHeight)}
+	}
+}
+
+// spawnTiles spawns a sprite entity per tile of a Y-sorted layer, positioned at the bottom-left corner
+// of its cell, and appends their IDs to entities.
+func spawnTiles(em *ecs.EntityManager, m *Map, layer *Layer, entities []ecs.EntityID) []ecs.EntityID {
+	spriteLayer := layer.Properties.Int(LayerProperty, 0)
+
+	for y := range layer.Height {
+		for x := range layer.Width {
+			raw := layer.TileAt(x, y)
+			tileset, ok := m.TilesetFor(raw)
+			if !ok || tileset.Image == nil {
+				continue
+			}
+
+			entityID := em.NewEntity()
+
+			origin := m.CellOrigin(x, y)
+			tr := ecs.AddComponent[transform.Transform](em, entityID)
+			tr.Position = f64.Vec2{origin[0] + layer.OffsetX, origin[1] + float64(m.TileHeight) + layer.OffsetY}
+
+			sprite := ecs.AddComponent[render.Sprite](em, entityID)
+			setTileSprite(sprite, tileset, raw)
+			sprite.Layer = spriteLayer
+			sprite.Hidden = !layer.Visible
+			sprite.ColorScale.ScaleAlpha(float32(layer.Opacity))
+
+			entities = append(entities, entityID)
+		}
+	}
+
+	return entities
+}
+
+// setTileSprite sets the sprite to draw the tile of the raw global tile ID, with its origin at the
+// bottom-left corner of the tile. Diagonal flips are not supported by sprites and are ignored.
+func setTileSprite(sprite *render.Sprite, tileset *Tileset, raw uint32) {
+	sprite.Image = tileset.Image
+	sprite.Source = tileset.SourceRect(raw).Sub(tileset.Image.Bounds().Min)
+	sprite.Origin = f64.Vec2{0, float64(tileset.TileHeight)}
+	sprite.FlipX = raw&FlippedHorizontally != 0
+	sprite.FlipY = raw&FlippedVertically != 0
+}
@@ -0,0 +1,314 @@
+package tilemap_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/collision"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/tilemap"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+const testMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="3" height="2" tilewidth="16" tileheight="16" infinite="0">
+ <tileset firstgid="1" source="tiles.tsx"/>
+ <layer id="1" name="ground" width="3" height="2">
+  <data encoding="csv">
+1,2,0,
+3,0,2147483652
+</data>
+ </layer>
+ <objectgroup id="2" name="walls">
+  <properties>
+   <property name="collision" type="bool" value="true"/>
+  </properties>
+  <object id="1" x="0" y="16" width="48" height="16"/>
+ </objectgroup>
+ <objectgroup id="3" name="actors">
+  <object id="2" name="goblin" type="enemy" x="32" y="8"/>
+  <object id="3" name="crate" type="prop" x="8" y="8"/>
+ </objectgroup>
+</map>`
+
+const testTileset = `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="tiles" tilewidth="16" tileheight="16" tilecount="4" columns="2">
+ <tile id="1" type="water">
+  <properties>
+   <property name="speed" type="float" value="0.5"/>
+  </properties>
+ </tile>
+ <tile id="2">
+  <animation>
+   <frame tileid="2" duration="100"/>
+   <frame tileid="3" duration="200"/>
+  </animation>
+ </tile>
+</tileset>`
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"maps/level.tmx": {Data: []byte(testMap)},
+		"maps/tiles.tsx": {Data: []byte(testTileset)},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	m, err := tilemap.Load(testFS(), "maps/level.tmx")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, m.Width)
+	assert.Equal(t, 2, m.Height)
+	require.Len(t, m.Tilesets, 1)
+	require.Len(t, m.Layers, 1)
+	require.Len(t, m.ObjectGroups, 2)
+
+	ground, ok := m.Layer("ground")
+	require.True(t, ok)
+	assert.Equal(t, uint32(1), ground.TileAt(0, 0))
+	assert.Equal(t, uint32(0), ground.TileAt(2, 0))
+	assert.Equal(t, tilemap.FlippedHorizontally|4, ground.TileAt(2, 1))
+	assert.Equal(t, uint32(4), tilemap.GID(ground.TileAt(2, 1)))
+
+	tileset, ok := m.TilesetFor(ground.TileAt(2, 1))
+	require.True(t, ok)
+	assert.Equal(t, "tiles", tileset.Name)
+	assert.Equal(t, 16, tileset.SourceRect(4).Min.X)
+	assert.Equal(t, 16, tileset.SourceRect(4).Min.Y)
+	assert.Equal(t, "water", tileset.Tiles[1].Type)
+	assert.Equal(t, 0.5, tileset.Tiles[1].Properties.Float("speed", 0))
+}
+
+func TestSpawner(t *testing.T) {
+	m, err := tilemap.Load(testFS(), "maps/level.tmx")
+	require.NoError(t, err)
+
+	em := ecs.NewEntityManager()
+
+	spawner := tilemap.NewSpawner()
+	spawner.Register("enemy", ecs.NewPrefab("goblin", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[transform.Transform](em, entityID)
+	}))
+
+	entities := spawner.Spawn(em, m)
+	assert.Len(t, entities, 3)
+
+	assert.Equal(t, 1, ecs.Count(ecs.Query[tilemap.TileLayer](em)))
+
+	colliderID, ok := ecs.First(ecs.Query[collision.Collider](em))
+	require.True(t, ok)
+	collider := ecs.MustGetComponent[collision.Collider](em, colliderID)
+	assert.True(t, collider.Static)
+	assert.Equal(t, 48.0, collider.Width)
+
+	enemies := 0
+	for entityID := range ecs.Query[tilemap.MapObject](em) {
+		object := ecs.MustGetComponent[tilemap.MapObject](em, entityID)
+		if object.Object.Type != "enemy" {
+			continue
+		}
+
+		enemies++
+		tr := ecs.MustGetComponent[transform.Transform](em, entityID)
+		assert.Equal(t, 32.0, tr.Position[0])
+		assert.Equal(t, 8.0, tr.Position[1])
+	}
+	assert.Equal(t, 1, enemies)
+}
+
+func TestAnimatedTiles(t *testing.T) {
+	m, err := tilemap.Load(testFS(), "maps/level.tmx")
+	require.NoError(t, err)
+
+	tileset := m.Tilesets[0]
+	require.Len(t, tileset.Tiles[2].Animation, 2)
+	assert.Equal(t, 200*time.Millisecond, tileset.Tiles[2].Animation[1].Duration)
+
+	raw := tilemap.FlippedHorizontally | 3
+	assert.Equal(t, raw, tileset.Animate(raw, 50*time.Millisecond))
+	assert.Equal(t, tilemap.FlippedHorizontally|4, tileset.Animate(raw, 150*time.Millisecond), "flip flags are kept")
+	assert.Equal(t, raw, tileset.Animate(raw, 300*time.Millisecond), "animations loop")
+	assert.Equal(t, uint32(1), tileset.Animate(1, 150*time.Millisecond), "static tiles are unchanged")
+}
+
+func TestAutoTiler(t *testing.T) {
+	layer := &tilemap.Layer{Width: 3, Height: 3, Tiles: make([]uint32, 9)}
+	tiler := &tilemap.AutoTiler{
+		Fill: 1,
+		Tiles: map[tilemap.Neighbors]uint32{
+			0:                            2,
+			tilemap.East:                 3,
+			tilemap.West:                 4,
+			tilemap.East | tilemap.West:  5,
+			tilemap.South | tilemap.East: 6,
+			tilemap.North | tilemap.West: 7,
+		},
+	}
+
+	require.True(t, tiler.Set(layer, 1, 1, true))
+	assert.Equal(t, uint32(2), layer.TileAt(1, 1), "an isolated cell")
+
+	tiler.Set(layer, 0, 1, true)
+	tiler.Set(layer, 2, 1, true)
+	assert.Equal(t, []uint32{3, 5, 4}, layer.Tiles[3:6], "neighbors are retiled")
+
+	tiler.Set(layer, 1, 0, true)
+	assert.Equal(t, uint32(1), layer.TileAt(1, 1), "missing combinations are filled")
+
+	tiler.Set(layer, 1, 0, false)
+	tiler.Set(layer, 2, 1, false)
+	assert.Equal(t, []uint32{3, 4, 0}, layer.Tiles[3:6], "removing terrain retiles its neighbors")
+	assert.False(t, tiler.Set(layer, 3, 0, true))
+
+	tiler.Corners = true
+	tiler.Tiles[tilemap.North|tilemap.East|tilemap.NorthEast] = 8
+	layer.Tiles = []uint32{1, 1, 0, 1, 1, 0, 0, 0, 0}
+	tiler.RetileAll(layer)
+	assert.Equal(t, uint32(6), layer.TileAt(0, 0))
+	assert.Equal(t, tilemap.North|tilemap.East|tilemap.NorthEast, tiler.Neighbors(layer, 0, 1))
+	assert.Equal(t, uint32(8), layer.TileAt(0, 1), "corners are set when both sides are")
+	assert.Equal(t, tilemap.North|tilemap.West|tilemap.NorthWest, tiler.Neighbors(layer, 1, 1))
+	assert.Equal(t, uint32(7), layer.TileAt(1, 1), "combinations with corners fall back to their sides")
+}
+
+func TestChunkSystem(t *testing.T) {
+	m, err := tilemap.Load(testFS(), "maps/level.tmx")
+	require.NoError(t, err)
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{ScreenWidth: 16, ScreenHeight: 16}))
+
+	spawner := tilemap.NewSpawner()
+	spawner.Register("enemy", ecs.NewPrefab("goblin", func(em *ecs.EntityManager, entityID ecs.EntityID) {}))
+	chunks := tilemap.NewChunkSystem(1, 0, m, spawner)
+	chunks.SetChunkSize(1)
+	sm.Add(chunks)
+
+	camera := em.NewEntity()
+	ecs.AddComponent[render.Camera](em, camera)
+	position := &ecs.MustGetComponent[transform.Transform](em, camera).Position
+	*position = f64.Vec2{8, 8}
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, 4, ecs.Count(ecs.Query[tilemap.TileLayer](em)), "the chunks in view are loaded")
+	assert.Equal(t, 1, ecs.Count(ecs.Query[collision.Collider](em)))
+	assert.Equal(t, 1, ecs.Count(ecs.Query[tilemap.MapObject](em)), "objects of unloaded chunks are not spawned")
+
+	*position = f64.Vec2{40, 8}
+	require.NoError(t, sm.Update())
+	_, ok := chunks.Chunk(0, 0)
+	assert.False(t, ok, "distant chunks are unloaded")
+	chunk, ok := chunks.Chunk(1, 0)
+	require.True(t, ok, "chunks within one chunk of the view are kept")
+	assert.Equal(t, 4, ecs.Count(ecs.Query[tilemap.TileLayer](em)))
+	assert.Equal(t, 0, ecs.Count(ecs.Query[collision.Collider](em)))
+	assert.Equal(t, 1, ecs.Count(ecs.Query[tilemap.MapObject](em)), "the goblin is spawned with its chunk")
+
+	chunk.Layers[0].SetTileAt(0, 0, 3)
+	*position = f64.Vec2{-100, -100}
+	require.NoError(t, sm.Update())
+	assert.Equal(t, 0, ecs.Count(ecs.Query[tilemap.TileLayer](em)))
+	assert.Equal(t, 0, ecs.Count(ecs.Query[tilemap.MapObject](em)))
+	assert.Equal(t, uint32(3), m.Layers[0].TileAt(1, 0), "changes are copied back to the map")
+
+	generated := 0
+	chunks.SetGenerator(func(em *ecs.EntityManager, chunk *tilemap.Chunk) {
+		generated++
+		chunk.Layers[0].SetTileAt(0, 0, 2)
+	})
+	require.NoError(t, sm.Update())
+	assert.Equal(t, 4, generated, "with a generator, chunks outside of the map are loaded")
+	chunk, ok = chunks.Chunk(-7, -7)
+	require.True(t, ok)
+	assert.Equal(t, uint32(2), chunk.Layers[0].TileAt(0, 0))
+}
+
+func TestProjection(t *testing.T) {
+	maps := []*tilemap.Map{
+		{Orientation: tilemap.Orthogonal, Width: 4, Height: 4, TileWidth: 16, TileHeight: 16},
+		{Orientation: tilemap.Isometric, Width: 4, Height: 4, TileWidth: 32, TileHeight: 16},
+		{Orientation: tilemap.Staggered, Width: 4, Height: 4, TileWidth: 32, TileHeight: 16, StaggerAxis: "y", StaggerIndex: "odd"},
+		{Orientation: tilemap.Hexagonal, Width: 4, Height: 4, TileWidth: 32, TileHeight: 28, StaggerAxis: "x", StaggerIndex: "even", HexSideLength: 16},
+	}
+
+	for _, m := range maps {
+		t.Run(m.Orientation, func(t *testing.T) {
+			for y := range m.Height {
+				for x := range m.Width {
+					cellX, cellY := m.CellAt(m.CellCenter(x, y))
+					assert.Equal(t, [2]int{x, y}, [2]int{cellX, cellY})
+				}
+			}
+		})
+	}
+
+	iso := maps[1]
+	assert.Equal(t, f64.Vec2{48, 0}, iso.CellOrigin(0, 0))
+	assert.Equal(t, f64.Vec2{64, 8}, iso.CellOrigin(1, 0))
+	width, height := iso.PixelSize()
+	assert.Equal(t, [2]int{128, 64}, [2]int{width, height})
+
+	position := iso.ObjectPosition(&tilemap.ObjectGroup{}, &tilemap.Object{X: 16, Y: 0})
+	assert.Equal(t, f64.Vec2{80, 8}, position, "isometric objects are placed along the tile axes")
+
+	staggered := maps[2]
+	assert.Equal(t, f64.Vec2{16, 8}, staggered.CellOrigin(0, 1))
+}
+
+func TestYSortedSpawn(t *testing.T) {
+	fsys := testFS()
+	fsys["maps/level.tmx"] = &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="isometric" width="2" height="2" tilewidth="32" tileheight="16">
+ <tileset firstgid="1" source="tiles.tsx"/>
+ <layer id="1" name="trees" width="2" height="2">
+  <properties>
+   <property name="ysort" type="bool" value="true"/>
+   <property name="layer" type="int" value="1"/>
+  </properties>
+  <data encoding="csv">
+1,0,
+0,2147483650
+</data>
+ </layer>
+ <objectgroup id="2" name="props">
+  <properties>
+   <property name="layer" type="int" value="1"/>
+  </properties>
+  <object id="1" gid="3" x="16" y="16" width="32" height="32"/>
+ </objectgroup>
+</map>`)}
+
+	m, err := tilemap.Load(fsys, "maps/level.tmx")
+	require.NoError(t, err)
+	m.Tilesets[0].Image = ebiten.NewImage(32, 32)
+
+	em := ecs.NewEntityManager()
+	entities := tilemap.NewSpawner().Spawn(em, m)
+	require.Len(t, entities, 3)
+	assert.Zero(t, ecs.Count(ecs.Query[tilemap.TileLayer](em)), "Y-sorted layers have no TileLayer")
+
+	first := ecs.MustGetComponent[render.Sprite](em, entities[0])
+	assert.Equal(t, 1, first.Layer)
+	assert.Equal(t, f64.Vec2{0, 16}, first.Origin)
+	assert.Equal(t, f64.Vec2{16, 16}, ecs.MustGetComponent[transform.Transform](em, entities[0]).Position)
+
+	flipped := ecs.MustGetComponent[render.Sprite](em, entities[1])
+	assert.True(t, flipped.FlipX)
+	assert.Equal(t, 16, flipped.Source.Min.X)
+
+	object := ecs.MustGetComponent[render.Sprite](em, entities[2])
+	assert.Equal(t, 1, object.Layer)
+	assert.Equal(t, f64.Vec2{8, 16}, object.Origin, "tile objects on isometric maps are anchored at their bottom center")
+	assert.Equal(t, 0, object.Source.Min.X)
+	assert.Equal(t, 16, object.Source.Min.Y)
+
+	tr := ecs.MustGetComponent[transform.Transform](em, entities[2])
+	assert.Equal(t, f64.Vec2{32, 16}, tr.Position)
+	assert.Equal(t, f64.Vec2{2, 2}, tr.Scale)
+}
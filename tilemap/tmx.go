@@ -0,0 +1,251 @@
+package tilemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/math/f64"
+)
+
+// Raw XML representation of the Tiled TMX/TSX formats.
+// These types mirror the file layout and are converted into the public Map types after parsing.
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+type tmxProperties struct {
+	Properties []tmxProperty `xml:"property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tmxFrame struct {
+	TileID   uint32 `xml:"tileid,attr"`
+	Duration int    `xml:"duration,attr"`
+}
+
+type tmxTile struct {
+	ID         uint32        `xml:"id,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Animation  []tmxFrame    `xml:"animation>frame"`
+}
+
+type tmxTileset struct {
+	FirstGID   uint32        `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	Image      tmxImage      `xml:"image"`
+	Properties tmxProperties `xml:"properties"`
+	Tiles      []tmxTile     `xml:"tile"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Data        string `xml:",chardata"`
+}
+
+type tmxLayer struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Data       tmxData       `xml:"data"`
+}
+
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Point      *struct{}     `xml:"point"`
+	Polygon    *tmxPoints    `xml:"polygon"`
+	Polyline   *tmxPoints    `xml:"polyline"`
+}
+
+type tmxObjectGroup struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Properties tmxProperties `xml:"properties"`
+	Objects    []tmxObject   `xml:"object"`
+}
+
+type tmxMap struct {
+	Version      string           `xml:"version,attr"`
+	Orientation  string           `xml:"orientation,attr"`
+	RenderOrder  string           `xml:"renderorder,attr"`
+	Width        int              `xml:"width,attr"`
+	Height       int              `xml:"height,attr"`
+	TileWidth    int              `xml:"tilewidth,attr"`
+	TileHeight   int              `xml:"tileheight,attr"`
+	Infinite     int              `xml:"infinite,attr"`
+	StaggerAxis  string           `xml:"staggeraxis,attr"`
+	StaggerIndex string           `xml:"staggerindex,attr"`
+	HexSide      int              `xml:"hexsidelength,attr"`
+	Properties   tmxProperties    `xml:"properties"`
+	Tilesets     []tmxTileset     `xml:"tileset"`
+	Layers       []tmxLayer       `xml:"layer"`
+	ObjectGroups []tmxObjectGroup `xml:"objectgroup"`
+}
+
+func decodeTMX(r io.Reader) (*tmxMap, error) {
+	var m tmxMap
+	if err := xml.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("tilemap.decodeTMX xml.Decode error: %w", err)
+	}
+
+	return &m, nil
+}
+
+func decodeTSX(r io.Reader) (*tmxTileset, error) {
+	var ts tmxTileset
+	if err := xml.NewDecoder(r).Decode(&ts); err != nil {
+		return nil, fmt.Errorf("tilemap.decodeTSX xml.Decode error: %w", err)
+	}
+
+	return &ts, nil
+}
+
+// decodeTileData decodes the contents of a <data> or <chunk> element into a slice of global tile IDs.
+func decodeTileData(encoding, compression, data string) ([]uint32, error) {
+	switch encoding {
+	case "csv":
+		fields := strings.Split(strings.TrimSpace(data), ",")
+		gids := make([]uint32, 0, len(fields))
+		for _, field := range fields {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("tilemap.decodeTileData strconv.ParseUint error: %w", err)
+			}
+			gids = append(gids, uint32(gid))
+		}
+
+		return gids, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.decodeTileData base64.DecodeString error: %w", err)
+		}
+
+		raw, err = decompress(compression, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(raw)%4 != 0 {
+			return nil, fmt.Errorf("tilemap.decodeTileData: tile data length %d is not a multiple of 4", len(raw))
+		}
+
+		gids := make([]uint32, len(raw)/4)
+		for i := range gids {
+			gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+
+		return gids, nil
+	default:
+		return nil, fmt.Errorf("tilemap.decodeTileData: unsupported encoding %q", encoding)
+	}
+}
+
+func decompress(compression string, raw []byte) ([]byte, error) {
+	var r io.ReadCloser
+	var err error
+
+	switch compression {
+	case "":
+		return raw, nil
+	case "zlib":
+		r, err = zlib.NewReader(bytes.NewReader(raw))
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(raw))
+	default:
+		return nil, fmt.Errorf("tilemap.decompress: unsupported compression %q", compression)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.decompress %s reader error: %w", compression, err)
+	}
+	defer r.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("tilemap.decompress io.ReadAll error: %w", err)
+	}
+
+	return out, nil
+}
+
+func parsePoints(points string) ([]f64.Vec2, error) {
+	fields := strings.Fields(points)
+	out := make([]f64.Vec2, 0, len(fields))
+	for _, field := range fields {
+		xs, ys, ok := strings.Cut(field, ",")
+		if !ok {
+			return nil, fmt.Errorf("tilemap.parsePoints: malformed point %q", field)
+		}
+
+		x, err := strconv.ParseFloat(xs, 64)
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.parsePoints strconv.ParseFloat error: %w", err)
+		}
+
+		y, err := strconv.ParseFloat(ys, 64)
+		if err != nil {
+			return nil, fmt.Errorf("tilemap.parsePoints strconv.ParseFloat error: %w", err)
+		}
+
+		out = append(out, f64.Vec2{x, y})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,122 @@
+package ecs
+
+import (
+	"math"
+	"time"
+)
+
+// Time describes the current tick: how much time it covers, how much time has passed overall,
+// and how fast the simulation runs. The game Time is updated at the start of every Update
+// and is available as a resource, through Game.Time and through BaseSystem.Time.
+type Time struct {
+	delta         float64
+	unscaledDelta float64
+	total         float64
+	unscaledTotal float64
+	tick          uint64
+	timeScale     float64
+	paused        bool
+
+	fixedStep float64
+	// frameDelta holds the scaled delta of the tick while Delta returns fixedStep during fixed updates.
+	frameDelta float64
+}
+
+// DefaultFixedStep is the initial fixed step of a Time, 60 updates per second.
+const DefaultFixedStep = 1.0 / 60
+
+// NewTime creates a Time at tick zero with a time scale of 1.
+func NewTime() *Time {
+	return &Time{
+		timeScale: 1.0,
+		fixedStep: DefaultFixedStep,
+	}
+}
+
+// Delta returns the scaled duration of the current tick in seconds.
+func (t *Time) Delta() float64 {
+	return t.delta
+}
+
+// DeltaDuration returns the scaled duration of the current tick.
+func (t *Time) DeltaDuration() time.Duration {
+	return time.Duration(t.delta * float64(time.Second))
+}
+
+// UnscaledDelta returns the duration of the current tick in seconds, ignoring the time scale.
+// It is useful for UI and debug tools that must keep running in slow motion.
+func (t *Time) UnscaledDelta() float64 {
+	return t.unscaledDelta
+}
+
+// Total returns the scaled time elapsed since the game started, in seconds.
+func (t *Time) Total() float64 {
+	return t.total
+}
+
+// UnscaledTotal returns the time elapsed since the game started in seconds, ignoring the time scale.
+func (t *Time) UnscaledTotal() float64 {
+	return t.unscaledTotal
+}
+
+// Tick returns the number of ticks since the game started.
+func (t *Time) Tick() uint64 {
+	return t.tick
+}
+
+// TimeScale returns the factor applied to the unscaled delta.
+func (t *Time) TimeScale() float64 {
+	return t.timeScale
+}
+
+// SetTimeScale sets the factor applied to the unscaled delta, e.g. 0.5 for slow motion.
+// Negative values are clamped to zero.
+func (t *Time) SetTimeScale(scale float64) {
+	t.timeScale = math.Max(scale, 0)
+}
+
+// Paused reports whether scaled time is frozen.
+func (t *Time) Paused() bool {
+	return t.paused
+}
+
+// SetPaused freezes or unfreezes scaled time. While paused, Delta is zero and Total does not advance,
+// but the unscaled values keep counting.
+func (t *Time) SetPaused(paused bool) {
+	t.paused = paused
+}
+
+// FixedStep returns the duration of a fixed update in seconds.
+func (t *Time) FixedStep() float64 {
+	return t.fixedStep
+}
+
+// SetFixedStep sets the duration of a fixed update in seconds. Non-positive values are ignored.
+func (t *Time) SetFixedStep(seconds float64) {
+	if seconds > 0 {
+		t.fixedStep = seconds
+	}
+}
+
+// beginFixedStep makes Delta return the fixed step until endFixedStep.
+func (t *Time) beginFixedStep() {
+	t.frameDelta = t.delta
+	t.delta = t.fixedStep
+}
+
+func (t *Time) endFixedStep() {
+	t.delta = t.frameDelta
+}
+
+// Advance starts a new tick lasting unscaledDelta seconds.
+func (t *Time) Advance(unscaledDelta float64) {
+	t.tick++
+	t.unscaledDelta = unscaledDelta
+	t.delta = unscaledDelta * t.timeScale
+	if t.paused {
+		t.delta = 0
+	}
+
+	t.unscaledTotal += t.unscaledDelta
+	t.total += t.delta
+}
@@ -0,0 +1,205 @@
+package ecs_test
+
+import (
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTime(t *testing.T) {
+	tm := ecs.NewTime()
+
+	tm.Advance(0.5)
+	assert.Equal(t, uint64(1), tm.Tick())
+	assert.Equal(t, 0.5, tm.Delta())
+	assert.Equal(t, 500*time.Millisecond, tm.DeltaDuration())
+
+	tm.SetTimeScale(0.5)
+	tm.Advance(0.5)
+	assert.Equal(t, 0.25, tm.Delta())
+	assert.Equal(t, 0.5, tm.UnscaledDelta())
+	assert.Equal(t, 0.75, tm.Total())
+	assert.Equal(t, 1.0, tm.UnscaledTotal())
+
+	tm.SetTimeScale(-1)
+	assert.Equal(t, 0.0, tm.TimeScale())
+}
+
+func TestGameTime(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+	assert.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "world"}))
+
+	game.SetTimeScale(2)
+	assert.NoError(t, game.Update())
+
+	assert.Same(t, game.Time(), ecs.MustGetResource[ecs.Time](game.Resources()))
+	assert.Equal(t, uint64(1), game.Time().Tick())
+	assert.InDelta(t, 2*game.Time().UnscaledDelta(), game.Time().Delta(), 1e-9)
+	assert.Equal(t, game.Time().DeltaDuration(), game.Clock().Now())
+}
+
+func TestGamePause(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+	assert.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "world"}))
+
+	menu := &hookSystem{BaseSystem: ecs.NewBaseSystem(ecs.NextID(), 0), events: &events, name: "menu"}
+	menu.SetAlwaysRun(true)
+	game.ActiveWorld().(*hookWorld).SystemManager().Add(menu)
+	events = events[:0]
+
+	game.Pause()
+	assert.NoError(t, game.Update())
+	assert.Equal(t, []string{"menu:update"}, events)
+	assert.Equal(t, 0.0, game.Time().Delta())
+	assert.NotZero(t, game.Time().UnscaledDelta())
+	assert.Zero(t, game.Clock().Tick())
+
+	events = events[:0]
+	game.Resume()
+	assert.NoError(t, game.Update())
+	assert.ElementsMatch(t, []string{"world:update", "menu:update"}, events)
+	assert.Equal(t, uint64(1), game.Clock().Tick())
+}
+
+func TestFixedUpdate(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	game.Time().SetFixedStep(0.1)
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	var events []string
+	var deltas []float64
+	record := func(name string) func(*ecs.FuncSystem) error {
+		return func(s *ecs.FuncSystem) error {
+			events = append(events, name)
+			deltas = append(deltas, s.Time().Delta())
+			return nil
+		}
+	}
+
+	sm.Add(
+		ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhaseUpdate}, record("update")),
+		ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhaseFixedUpdate}, record("fixed")),
+		ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhasePreUpdate}, record("pre")),
+	)
+
+	step := func(seconds float64) {
+		events, deltas = nil, nil
+		game.Time().Advance(seconds)
+		assert.NoError(t, sm.Update())
+	}
+
+	step(0.25)
+	assert.Equal(t, []string{"pre", "fixed", "fixed", "update"}, events)
+	assert.Equal(t, []float64{0.25, 0.1, 0.1, 0.25}, deltas)
+
+	step(0.04)
+	assert.Equal(t, []string{"pre", "update"}, events, "not enough time for a fixed step")
+
+	step(0.01)
+	assert.Equal(t, []string{"pre", "fixed", "update"}, events, "leftover time is carried over")
+
+	step(10)
+	assert.Len(t, events, 2+8, "fixed steps per update are bounded")
+}
+
+func TestScheduledSystems(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	game.Time().SetFixedStep(0.1)
+
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	runs := map[string]int{}
+	count := func(name string) func(*ecs.FuncSystem) error {
+		return func(*ecs.FuncSystem) error {
+			runs[name]++
+			return nil
+		}
+	}
+
+	sm.Add(
+		ecs.NewSystem(ecs.SystemOptions{Interval: 200 * time.Millisecond}, count("planner")),
+		ecs.NewSystem(ecs.SystemOptions{TickInterval: 3}, count("ticks")),
+		ecs.NewSystem(ecs.SystemOptions{Phase: ecs.PhaseFixedUpdate, TickInterval: 2}, count("fixed")),
+	)
+
+	step := func(seconds float64) {
+		game.Time().Advance(seconds)
+		require.NoError(t, sm.Update())
+	}
+
+	step(0.1)
+	assert.Empty(t, runs)
+
+	step(0.15)
+	step(0.1)
+	assert.Equal(t, map[string]int{"fixed": 1, "planner": 1, "ticks": 1}, runs, "fixed systems count fixed steps")
+
+	step(0.1)
+	assert.Equal(t, 2, runs["planner"], "leftover time is carried over")
+
+	game.Pause()
+	step(1)
+	game.Resume()
+	assert.Equal(t, map[string]int{"fixed": 2, "planner": 2, "ticks": 1}, runs, "paused ticks are not counted")
+
+	step(1)
+	assert.Equal(t, 3, runs["planner"], "a system runs at most once per tick")
+}
+
+func TestScheduledSystemLastDelta(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	sm := ecs.NewSystemManager(ecs.NewEntityManager(), game)
+
+	var deltas []float64
+	sm.Add(ecs.NewSystem(ecs.SystemOptions{Interval: 200 * time.Millisecond}, func(s *ecs.FuncSystem) error {
+		deltas = append(deltas, s.LastDelta().Seconds())
+		return nil
+	}))
+
+	for _, seconds := range []float64{0.1, 0.15, 0.1, 0.1} {
+		game.Time().Advance(seconds)
+		require.NoError(t, sm.Update())
+	}
+
+	require.Len(t, deltas, 2)
+	assert.InDelta(t, 0.25, deltas[0], 1e-6, "the time since the first tick")
+	assert.InDelta(t, 0.2, deltas[1], 1e-6, "the time since the previous update")
+}
+
+func TestFrameStepping(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+	require.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "world"}))
+
+	game.SetFrameStepping(true)
+	events = nil
+	require.NoError(t, game.Update())
+	assert.Empty(t, events, "the simulation is paused")
+
+	game.StepOnce()
+	game.StepOnce()
+	for range 3 {
+		require.NoError(t, game.Update())
+	}
+	assert.Equal(t, []string{"world:update", "world:update"}, events, "one update per step")
+	assert.Equal(t, uint64(2), game.Clock().Tick())
+	assert.True(t, game.Paused())
+
+	game.SetFrameStepping(false)
+	assert.False(t, game.Paused())
+
+	game.Pause()
+	game.SetFrameStepping(true)
+	game.StepOnce()
+	require.NoError(t, game.Update())
+	game.SetFrameStepping(false)
+	assert.True(t, game.Paused(), "a game paused before stepping stays paused")
+}
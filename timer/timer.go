@@ -0,0 +1,205 @@
+// Package timer provides Timer and Cooldown components counted down by a System with the game's
+// delta time, for spawn waves, invulnerability windows, ability cooldowns and the like.
+package timer
+
+import (
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+)
+
+// Timer is a component that counts up to Duration. A Repeat timer starts over when it completes,
+// otherwise it stops at Duration with Finished set.
+type Timer struct {
+	Duration time.Duration
+	Elapsed  time.Duration
+	Repeat   bool
+	// Finished is set when a one-shot timer has completed, or for the update in which a repeating timer completed.
+	Finished bool
+	// Paused stops the timer from advancing.
+	Paused bool
+}
+
+func (t *Timer) Reset() {
+	*t = Timer{}
+}
+
+// Restart starts the timer over.
+func (t *Timer) Restart() {
+	t.Elapsed = 0
+	t.Finished = false
+}
+
+// Remaining returns the time left before the timer completes.
+func (t *Timer) Remaining() time.Duration {
+	return max(t.Duration-t.Elapsed, 0)
+}
+
+// Progress returns the completed fraction of the timer, from 0 to 1.
+func (t *Timer) Progress() float64 {
+	if t.Duration <= 0 {
+		return 1
+	}
+
+	return min(float64(t.Elapsed)/float64(t.Duration), 1)
+}
+
+// advance moves the timer forward by dt and returns how many times it completed.
+func (t *Timer) advance(dt time.Duration) int {
+	if t.Paused || (t.Finished && !t.Repeat) {
+		return 0
+	}
+
+	t.Finished = false
+	t.Elapsed += dt
+	if t.Elapsed < t.Duration {
+		return 0
+	}
+
+	t.Finished = true
+	if !t.Repeat {
+		t.Elapsed = t.Duration
+		return 1
+	}
+
+	if t.Duration <= 0 {
+		t.Elapsed = 0
+		return 1
+	}
+
+	completed := int(t.Elapsed / t.Duration)
+	t.Elapsed %= t.Duration
+
+	return completed
+}
+
+// Cooldown is a component gating an action so it can run at most once per Duration.
+//
+//	if cooldown := ecs.MustGetComponent[timer.Cooldown](em, player); fire && cooldown.Trigger() {
+//		shoot()
+//	}
+type Cooldown struct {
+	Duration  time.Duration
+	Remaining time.Duration
+}
+
+func (c *Cooldown) Reset() {
+	*c = Cooldown{}
+}
+
+// Ready reports whether the cooldown has elapsed.
+func (c *Cooldown) Ready() bool {
+	return c.Remaining <= 0
+}
+
+// Trigger starts the cooldown if it is ready and reports whether it was.
+func (c *Cooldown) Trigger() bool {
+	if !c.Ready() {
+		return false
+	}
+
+	c.Remaining = c.Duration
+	return true
+}
+
+// Progress returns the elapsed fraction of the cooldown, from 0 to 1, e.g. to fill an ability icon.
+func (c *Cooldown) Progress() float64 {
+	if c.Duration <= 0 || c.Remaining <= 0 {
+		return 1
+	}
+
+	return 1 - float64(c.Remaining)/float64(c.Duration)
+}
+
+// TimerFinished is passed to the System's handler when a Timer completes.
+type TimerFinished struct {
+	EntityID ecs.EntityID
+	Timer    *Timer
+}
+
+// CooldownReady is passed to the System's handler when a Cooldown becomes ready again.
+type CooldownReady struct {
+	EntityID ecs.EntityID
+	Cooldown *Cooldown
+}
+
+// System advances every Timer and Cooldown with the game's scaled delta time.
+type System struct {
+	*ecs.BaseSystem
+
+	onTimerFinished func(TimerFinished)
+	onCooldownReady func(CooldownReady)
+}
+
+// NewSystem creates a new timer System with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int) *System {
+	s := &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+	s.SetAccess(ecs.SystemAccess{Writes: ecs.Types(Timer{}, Cooldown{})})
+
+	return s
+}
+
+// OnTimerFinished sets a handler called every time a Timer completes; a repeating timer that
+// completed several periods in one update calls it once per period. A nil handler removes it.
+func (s *System) OnTimerFinished(handler func(TimerFinished)) {
+	s.onTimerFinished = handler
+	s.updateAccess()
+}
+
+// OnCooldownReady sets a handler called when a Cooldown becomes ready. A nil handler removes it.
+func (s *System) OnCooldownReady(handler func(CooldownReady)) {
+	s.onCooldownReady = handler
+	s.updateAccess()
+}
+
+func (s *System) updateAccess() {
+	access := s.Access()
+	access.Produces = nil
+	if s.onTimerFinished != nil {
+		access.Produces = append(access.Produces, ecs.Types(TimerFinished{})...)
+	}
+	if s.onCooldownReady != nil {
+		access.Produces = append(access.Produces, ecs.Types(CooldownReady{})...)
+	}
+	s.SetAccess(access)
+}
+
+// Update advances all timers and cooldowns.
+func (s *System) Update() error {
+	em := s.EntityManager()
+	dt := s.Time().DeltaDuration()
+
+	for entityID := range ecs.Query[Timer](em) {
+		timer := ecs.MustGetComponent[Timer](em, entityID)
+
+		completed := timer.advance(dt)
+		if s.onTimerFinished == nil {
+			continue
+		}
+
+		for range completed {
+			s.onTimerFinished(TimerFinished{EntityID: entityID, Timer: timer})
+		}
+	}
+
+	for entityID := range ecs.Query[Cooldown](em) {
+		cooldown := ecs.MustGetComponent[Cooldown](em, entityID)
+		if cooldown.Remaining <= 0 {
+			continue
+		}
+
+		cooldown.Remaining -= dt
+		if cooldown.Remaining > 0 {
+			continue
+		}
+
+		cooldown.Remaining = 0
+		if s.onCooldownReady != nil {
+			s.onCooldownReady(CooldownReady{EntityID: entityID, Cooldown: cooldown})
+		}
+	}
+
+	return nil
+}
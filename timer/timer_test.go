@@ -0,0 +1,66 @@
+package timer_test
+
+import (
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/timer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	system := timer.NewSystem(ecs.NextID(), 0)
+	sm.Add(system)
+
+	finished := make(map[ecs.EntityID]int)
+	system.OnTimerFinished(func(e timer.TimerFinished) { finished[e.EntityID]++ })
+
+	var ready []ecs.EntityID
+	system.OnCooldownReady(func(e timer.CooldownReady) { ready = append(ready, e.EntityID) })
+
+	oneShot := em.NewEntity()
+	ecs.AddComponent[timer.Timer](em, oneShot).Duration = time.Second
+
+	spawner := em.NewEntity()
+	*ecs.AddComponent[timer.Timer](em, spawner) = timer.Timer{Duration: 300 * time.Millisecond, Repeat: true}
+
+	gun := em.NewEntity()
+	cooldown := ecs.AddComponent[timer.Cooldown](em, gun)
+	cooldown.Duration = 500 * time.Millisecond
+
+	step := func() {
+		game.Time().Advance(0.4)
+		require.NoError(t, sm.Update())
+	}
+
+	assert.True(t, cooldown.Trigger())
+	assert.False(t, cooldown.Trigger())
+
+	step()
+	assert.Equal(t, map[ecs.EntityID]int{spawner: 1}, finished)
+	assert.InDelta(t, 0.8, cooldown.Progress(), 1e-9)
+
+	step()
+	assert.Equal(t, map[ecs.EntityID]int{spawner: 2}, finished)
+	assert.Equal(t, []ecs.EntityID{gun}, ready)
+	assert.True(t, cooldown.Ready())
+
+	step()
+	oneShotTimer := ecs.MustGetComponent[timer.Timer](em, oneShot)
+	assert.Equal(t, map[ecs.EntityID]int{spawner: 4, oneShot: 1}, finished, "a repeating timer completes once per period")
+	assert.True(t, oneShotTimer.Finished)
+	assert.Equal(t, time.Duration(0), oneShotTimer.Remaining())
+
+	step()
+	assert.Equal(t, 1, finished[oneShot], "a one-shot timer completes once")
+
+	oneShotTimer.Restart()
+	assert.False(t, oneShotTimer.Finished)
+	assert.Zero(t, oneShotTimer.Progress())
+}
@@ -0,0 +1,36 @@
+// Package transform provides the spatial component shared by the built-in modules.
+package transform
+
+import "golang.org/x/image/math/f64"
+
+// Transform places an entity in world space.
+type Transform struct {
+	Position f64.Vec2
+	Rotation float64
+	Scale    f64.Vec2
+}
+
+// Init sets the transform to the identity.
+func (t *Transform) Init() {
+	t.Position = f64.Vec2{0, 0}
+	t.Rotation = 0
+	t.Scale = f64.Vec2{1, 1}
+}
+
+func (t *Transform) Reset() {
+	t.Position = f64.Vec2{0, 0}
+	t.Rotation = 0
+	t.Scale = f64.Vec2{1, 1}
+}
+
+// Point returns the position, so that transforms can be queried with ecs.QueryWithinRadius
+// and ecs.QueryWithinBounds.
+func (t *Transform) Point() f64.Vec2 {
+	return t.Position
+}
+
+// Translate moves the transform by the given offset.
+func (t *Transform) Translate(dx, dy float64) {
+	t.Position[0] += dx
+	t.Position[1] += dy
+}
@@ -0,0 +1,106 @@
+// Package ease provides easing functions for tweens, following https://easings.net.
+package ease
+
+import "math"
+
+// Func maps the linear progress t of a tween, from 0 to 1, to its eased progress.
+// The eased progress is 0 at t=0 and 1 at t=1, but may overshoot in between.
+type Func func(t float64) float64
+
+// Linear does not ease.
+func Linear(t float64) float64 {
+	return t
+}
+
+func InQuad(t float64) float64 {
+	return t * t
+}
+
+func OutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
+
+func InCubic(t float64) float64 {
+	return t * t * t
+}
+
+func OutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+func InOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+func InSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+func OutSine(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+func InOutSine(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
+
+// InBack pulls back before moving towards the target.
+func InBack(t float64) float64 {
+	const c1 = 1.70158
+	return (c1+1)*t*t*t - c1*t*t
+}
+
+// OutBack overshoots the target before settling.
+func OutBack(t float64) float64 {
+	const c1 = 1.70158
+	return 1 + (c1+1)*math.Pow(t-1, 3) + c1*math.Pow(t-1, 2)
+}
+
+// OutElastic overshoots the target and oscillates around it.
+func OutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+
+	const c4 = 2 * math.Pi / 3
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*c4) + 1
+}
+
+// OutBounce bounces on the target like a dropped ball.
+func OutBounce(t float64) float64 {
+	const (
+		n1 = 7.5625
+		d1 = 2.75
+	)
+
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// InBounce is OutBounce played backwards.
+func InBounce(t float64) float64 {
+	return 1 - OutBounce(1-t)
+}
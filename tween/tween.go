@@ -0,0 +1,280 @@
+// Package tween animates component fields towards a target value over time.
+//
+//	tr := ecs.MustGetComponent[transform.Transform](em, coin)
+//	tween.To(em, coin, &tr.Position, f64.Vec2{x, y - 16}, 200*time.Millisecond, ease.OutQuad).
+//		Then(tween.To(em, coin, &tr.Scale, f64.Vec2{}, 100*time.Millisecond, ease.InQuad)).
+//		OnComplete(func() { em.Commands().Remove(coin) })
+//
+// Every tween is an entity with a Tween component, advanced by a System.
+package tween
+
+import (
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/tween/ease"
+	"golang.org/x/image/math/f64"
+)
+
+// Value is a type that can be tweened.
+type Value interface {
+	float32 | float64 | f64.Vec2 | f64.Vec3 | f64.Vec4
+}
+
+// track interpolates a field between its value when the tween starts and the target.
+type track interface {
+	start()
+	apply(progress float64)
+}
+
+type valueTrack[T Value] struct {
+	field    *T
+	from, to T
+}
+
+func (t *valueTrack[T]) start() {
+	t.from = *t.field
+}
+
+func (t *valueTrack[T]) apply(progress float64) {
+	*t.field = lerp(t.from, t.to, progress)
+}
+
+func lerp[T Value](from, to T, progress float64) T {
+	switch from := any(from).(type) {
+	case float32:
+		to := any(to).(float32)
+		return any(from + (to-from)*float32(progress)).(T)
+	case float64:
+		to := any(to).(float64)
+		return any(from + (to-from)*progress).(T)
+	case f64.Vec2:
+		to := any(to).(f64.Vec2)
+		return any(f64.Vec2{
+			from[0] + (to[0]-from[0])*progress,
+			from[1] + (to[1]-from[1])*progress,
+		}).(T)
+	case f64.Vec3:
+		to := any(to).(f64.Vec3)
+		return any(f64.Vec3{
+			from[0] + (to[0]-from[0])*progress,
+			from[1] + (to[1]-from[1])*progress,
+			from[2] + (to[2]-from[2])*progress,
+		}).(T)
+	default:
+		from4, to4 := any(from).(f64.Vec4), any(to).(f64.Vec4)
+		return any(f64.Vec4{
+			from4[0] + (to4[0]-from4[0])*progress,
+			from4[1] + (to4[1]-from4[1])*progress,
+			from4[2] + (to4[2]-from4[2])*progress,
+			from4[3] + (to4[3]-from4[3])*progress,
+		}).(T)
+	}
+}
+
+// Tween is the component of a tween entity, created with To.
+// Like any component, it is only valid until its entity is removed, when the tween completes or is cancelled.
+type Tween struct {
+	em       *ecs.EntityManager
+	entityID ecs.EntityID
+	target   ecs.EntityID
+
+	track    track
+	easing   ease.Func
+	duration time.Duration
+	delay    time.Duration
+	elapsed  time.Duration
+	started  bool
+
+	next       ecs.EntityID
+	onComplete func()
+}
+
+func (t *Tween) Reset() {
+	*t = Tween{}
+}
+
+// To creates a tween moving *field, a field of one of entity's components, to target over duration.
+// The tween starts from the value the field has when the tween starts, on the next update of the
+// System or after the previous tween of a chain. easing defaults to ease.Linear if nil.
+//
+// The tween is cancelled, along with the tweens chained after it, if the entity is removed.
+// The field must stay valid as long as the entity exists, so the component holding it must not be
+// removed from the entity while the tween runs.
+func To[T Value](em *ecs.EntityManager, entityID ecs.EntityID, field *T, target T, duration time.Duration, easing ease.Func) *Tween {
+	if easing == nil {
+		easing = ease.Linear
+	}
+
+	tweenID := em.NewEntity()
+	t := ecs.AddComponent[Tween](em, tweenID)
+	*t = Tween{
+		em:       em,
+		entityID: tweenID,
+		target:   entityID,
+		track:    &valueTrack[T]{field: field, to: target},
+		easing:   easing,
+		duration: duration,
+		next:     ecs.UndefinedID,
+	}
+
+	return t
+}
+
+// Entity returns the tween entity. Removing it cancels the tween, but not the tweens chained after it.
+func (t *Tween) Entity() ecs.EntityID {
+	return t.entityID
+}
+
+// Target returns the entity whose field is tweened.
+func (t *Tween) Target() ecs.EntityID {
+	return t.target
+}
+
+// Delay waits for d before the tween starts. It returns t.
+func (t *Tween) Delay(d time.Duration) *Tween {
+	t.delay = d
+	return t
+}
+
+// OnComplete sets a function called when the tween completes. It returns t.
+func (t *Tween) OnComplete(fn func()) *Tween {
+	t.onComplete = fn
+	return t
+}
+
+// Then starts next when t completes, and returns next so chains read in order:
+//
+//	a.Then(b).Then(c)
+//
+// next is paused until then; it must not already be running.
+func (t *Tween) Then(next *Tween) *Tween {
+	t.em.SetActive(next.entityID, false)
+	t.next = next.entityID
+
+	return next
+}
+
+// Progress returns the linear progress of the tween, from 0 to 1, ignoring the delay.
+func (t *Tween) Progress() float64 {
+	if t.duration <= 0 {
+		if t.started {
+			return 1
+		}
+		return 0
+	}
+
+	return min(max(float64(t.elapsed-t.delay)/float64(t.duration), 0), 1)
+}
+
+// advance moves the tween forward by dt and reports whether it completed.
+func (t *Tween) advance(dt time.Duration) bool {
+	t.elapsed += dt
+	if t.elapsed < t.delay {
+		return false
+	}
+
+	if !t.started {
+		t.started = true
+		t.track.start()
+	}
+
+	progress := t.Progress()
+	if progress >= 1 {
+		t.track.apply(1)
+		return true
+	}
+
+	t.track.apply(t.easing(progress))
+	return false
+}
+
+// Finished is passed to the System's handler when a tween completes.
+type Finished struct {
+	// Tween is the tween entity, removed at the end of the update.
+	Tween ecs.EntityID
+	// Target is the entity whose field was tweened.
+	Target ecs.EntityID
+}
+
+// System advances every Tween with the game's scaled delta time, and removes the tween entities
+// once they complete or their target entity is removed.
+type System struct {
+	*ecs.BaseSystem
+
+	onFinished func(Finished)
+}
+
+// NewSystem creates a new tween System with the given ID and priority.
+func NewSystem(id ecs.SystemID, priority int) *System {
+	s := &System{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+	}
+	s.SetAccess(ecs.SystemAccess{Writes: ecs.Types(Tween{})})
+
+	return s
+}
+
+// OnFinished sets a handler called for every tween that completes, after its own OnComplete function.
+// A nil handler removes it.
+func (s *System) OnFinished(handler func(Finished)) {
+	s.onFinished = handler
+
+	access := s.Access()
+	access.Produces = nil
+	if handler != nil {
+		access.Produces = ecs.Types(Finished{})
+	}
+	s.SetAccess(access)
+}
+
+// Update advances all tweens. Removals and the start of chained tweens go through the EntityManager's
+// command buffer, so a chained tween starts on the update after the previous one completes.
+func (s *System) Update() error {
+	em := s.EntityManager()
+	dt := s.Time().DeltaDuration()
+	commands := em.Commands()
+
+	for tweenID := range ecs.Query[Tween](em) {
+		t := ecs.MustGetComponent[Tween](em, tweenID)
+
+		if !em.Exists(t.target) {
+			s.cancel(em, t)
+			continue
+		}
+
+		if !t.advance(dt) {
+			continue
+		}
+
+		if t.onComplete != nil {
+			t.onComplete()
+		}
+
+		if s.onFinished != nil {
+			s.onFinished(Finished{Tween: tweenID, Target: t.target})
+		}
+
+		if t.next != ecs.UndefinedID {
+			commands.SetActive(t.next, true)
+		}
+		commands.Remove(tweenID)
+	}
+
+	return nil
+}
+
+// cancel removes the tween and the tweens chained after it.
+func (s *System) cancel(em *ecs.EntityManager, t *Tween) {
+	em.Commands().Remove(t.entityID)
+
+	for next := t.next; next != ecs.UndefinedID; {
+		em.Commands().Remove(next)
+
+		chained, ok := ecs.GetComponent[Tween](em, next)
+		if !ok {
+			break
+		}
+		next = chained.next
+	}
+}
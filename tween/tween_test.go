@@ -0,0 +1,92 @@
+package tween_test
+
+import (
+	"testing"
+	"time"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/samix73/ebiten-ecs/tween"
+	"github.com/samix73/ebiten-ecs/tween/ease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestEase(t *testing.T) {
+	for name, fn := range map[string]ease.Func{
+		"Linear": ease.Linear, "InQuad": ease.InQuad, "OutQuad": ease.OutQuad, "InOutQuad": ease.InOutQuad,
+		"InCubic": ease.InCubic, "OutCubic": ease.OutCubic, "InOutCubic": ease.InOutCubic,
+		"InSine": ease.InSine, "OutSine": ease.OutSine, "InOutSine": ease.InOutSine,
+		"InBack": ease.InBack, "OutBack": ease.OutBack, "OutElastic": ease.OutElastic,
+		"InBounce": ease.InBounce, "OutBounce": ease.OutBounce,
+	} {
+		assert.InDelta(t, 0, fn(0), 1e-9, name)
+		assert.InDelta(t, 1, fn(1), 1e-9, name)
+	}
+}
+
+func TestTween(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	system := tween.NewSystem(ecs.NextID(), 0)
+	sm.Add(system)
+
+	var finished []tween.Finished
+	system.OnFinished(func(e tween.Finished) { finished = append(finished, e) })
+
+	coin := em.NewEntity()
+	tr := ecs.AddComponent[transform.Transform](em, coin)
+	tr.Position = f64.Vec2{0, 100}
+
+	var alpha float64 = 1
+	completed := false
+
+	rise := tween.To(em, coin, &tr.Position, f64.Vec2{0, 80}, time.Second, nil).Delay(500 * time.Millisecond)
+	fade := rise.Then(tween.To(em, coin, &alpha, 0, time.Second, ease.OutQuad)).OnComplete(func() { completed = true })
+	riseID, fadeID := rise.Entity(), fade.Entity()
+
+	step := func(seconds float64) {
+		game.Time().Advance(seconds)
+		require.NoError(t, sm.Update())
+	}
+
+	step(0.25)
+	assert.Equal(t, f64.Vec2{0, 100}, tr.Position, "delayed")
+
+	step(0.75)
+	assert.InDelta(t, 90, tr.Position[1], 1e-9)
+	assert.Equal(t, 1.0, alpha, "chained tweens wait for the previous one")
+
+	step(1)
+	assert.Equal(t, f64.Vec2{0, 80}, tr.Position)
+	require.Len(t, finished, 1)
+	assert.Equal(t, tween.Finished{Tween: riseID, Target: coin}, finished[0])
+	assert.False(t, em.Exists(riseID))
+
+	step(0.5)
+	assert.InDelta(t, 0.25, alpha, 1e-9)
+
+	step(0.5)
+	assert.Zero(t, alpha)
+	assert.True(t, completed)
+	assert.Len(t, finished, 2)
+	assert.False(t, em.Exists(fadeID))
+
+	t.Run("target removed", func(t *testing.T) {
+		x := 0.0
+		target := em.NewEntity()
+		first := tween.To(em, target, &x, 10, time.Second, nil)
+		second := first.Then(tween.To(em, target, &x, 0, time.Second, nil))
+		firstID, secondID := first.Entity(), second.Entity()
+
+		em.Remove(target)
+		step(0.5)
+
+		assert.Zero(t, x)
+		assert.False(t, em.Exists(firstID))
+		assert.False(t, em.Exists(secondID), "chained tweens are cancelled too")
+	})
+}
@@ -0,0 +1,75 @@
+// Package ui places screen-space entities, such as HUD elements, relative to the screen or to each other,
+// so that they stay attached to corners and edges when the window is resized.
+package ui
+
+import (
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+func init() {
+	ecs.Require[RectTransform, transform.Transform]()
+	ecs.Require[RectTransform, render.ScreenSpace]()
+}
+
+// Anchor points, as fractions of the parent rectangle.
+var (
+	TopLeft     = f64.Vec2{0, 0}
+	Top         = f64.Vec2{0.5, 0}
+	TopRight    = f64.Vec2{1, 0}
+	Left        = f64.Vec2{0, 0.5}
+	Center      = f64.Vec2{0.5, 0.5}
+	Right       = f64.Vec2{1, 0.5}
+	BottomLeft  = f64.Vec2{0, 1}
+	Bottom      = f64.Vec2{0.5, 1}
+	BottomRight = f64.Vec2{1, 1}
+)
+
+// RectTransform is a rectangle in screen space, placed relative to its parent: the screen, or the
+// RectTransform of the Parent entity. Its Pivot is placed at Offset pixels from the Anchor point of the
+// parent. The LayoutSystem moves the entity's transform.Transform, added with the rectangle if missing,
+// to its top-left corner, and marks it render.ScreenSpace.
+//
+// For example, Anchor and Pivot both set to BottomRight and an Offset of {-8, -8} keep a rectangle
+// 8 pixels away from the bottom-right corner of the screen.
+type RectTransform struct {
+	// Anchor is the point of the parent the rectangle is attached to, as fractions of its size.
+	Anchor f64.Vec2
+	// Offset is the distance from the anchor point to the pivot, in pixels.
+	Offset f64.Vec2
+	// Pivot is the point of the rectangle placed at the offset, as fractions of its size.
+	Pivot f64.Vec2
+	// Size is the size of the rectangle in pixels.
+	Size f64.Vec2
+	// Parent is the entity with the RectTransform the rectangle is placed in. A zero or stale
+	// reference places it on the screen.
+	Parent ecs.Ref
+
+	min, max f64.Vec2
+}
+
+func (r *RectTransform) Reset() {
+	*r = RectTransform{}
+}
+
+// Bounds returns the corners of the rectangle on the screen, as of the last layout.
+func (r *RectTransform) Bounds() (min, max f64.Vec2) {
+	return r.min, r.max
+}
+
+// Contains reports whether the point, in screen coordinates, was in the rectangle at the last layout,
+// e.g. to hit-test the cursor.
+func (r *RectTransform) Contains(point f64.Vec2) bool {
+	return point[0] >= r.min[0] && point[0] < r.max[0] && point[1] >= r.min[1] && point[1] < r.max[1]
+}
+
+// place sets the bounds of the rectangle within the parent bounds.
+func (r *RectTransform) place(parentMin, parentMax f64.Vec2) {
+	for i := range 2 {
+		anchor := parentMin[i] + r.Anchor[i]*(parentMax[i]-parentMin[i])
+		r.min[i] = anchor + r.Offset[i] - r.Pivot[i]*r.Size[i]
+		r.max[i] = r.min[i] + r.Size[i]
+	}
+}
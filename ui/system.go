@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"image"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"golang.org/x/image/math/f64"
+)
+
+// LayoutSystem places the entities with a RectTransform every frame, against the current
+// ecs.Game.ScreenSize, so that they follow resizes. Parents are placed before their children;
+// a rectangle in a parent cycle is placed on the screen. The transform of each entity is moved to
+// the top-left corner of its rectangle, and the DrawSize of its nine-slice or tiled render.Sprite is set
+// to the rectangle's size. It should run before the systems reading the layout.
+type LayoutSystem struct {
+	*ecs.BaseSystem
+
+	// states records the entities being placed and placed during an update, to detect cycles.
+	states map[ecs.EntityID]layoutState
+}
+
+type layoutState int
+
+const (
+	unplaced layoutState = iota
+	placing
+	placed
+)
+
+// NewLayoutSystem creates a new LayoutSystem with the given ID and priority.
+func NewLayoutSystem(id ecs.SystemID, priority int) *LayoutSystem {
+	return &LayoutSystem{
+		BaseSystem: ecs.NewBaseSystem(id, priority),
+		states:     make(map[ecs.EntityID]layoutState),
+	}
+}
+
+// Update places the rectangles.
+func (s *LayoutSystem) Update() error {
+	em := s.EntityManager()
+
+	width, height := s.Game().ScreenSize()
+	screen := f64.Vec2{float64(width), float64(height)}
+
+	clear(s.states)
+	for entityID := range ecs.Query[RectTransform](em) {
+		s.place(em, entityID, screen)
+	}
+
+	return nil
+}
+
+// place places the rectangle of the entity, after its parent.
+func (s *LayoutSystem) place(em *ecs.EntityManager, entityID ecs.EntityID, screen f64.Vec2) *RectTransform {
+	rect := ecs.MustGetComponent[RectTransform](em, entityID)
+	if s.states[entityID] != unplaced {
+		return rect
+	}
+	s.states[entityID] = placing
+
+	parentMin, parentMax := f64.Vec2{}, screen
+	if parentID, ok := rect.Parent.Get(em); ok && s.states[parentID] != placing && ecs.HasComponent[RectTransform](em, parentID) {
+		parentMin, parentMax = s.place(em, parentID, screen).Bounds()
+	}
+
+	rect.place(parentMin, parentMax)
+	s.states[entityID] = placed
+
+	if tr, ok := ecs.GetComponent[transform.Transform](em, entityID); ok {
+		tr.Position = rect.min
+	}
+
+	if sprite, ok := ecs.GetComponent[render.Sprite](em, entityID); ok && sprite.Mode != render.ModeSimple {
+		sprite.DrawSize = image.Pt(int(rect.Size[0]), int(rect.Size[1]))
+	}
+
+	return rect
+}
@@ -0,0 +1,74 @@
+package ui_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/samix73/ebiten-ecs/render"
+	"github.com/samix73/ebiten-ecs/transform"
+	"github.com/samix73/ebiten-ecs/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/math/f64"
+)
+
+func TestLayoutSystem(t *testing.T) {
+	em := ecs.NewEntityManager()
+	game := ecs.NewGame(&ecs.GameConfig{ScreenWidth: 320, ScreenHeight: 240})
+	sm := ecs.NewSystemManager(em, game)
+	sm.Add(ui.NewLayoutSystem(1, 0))
+
+	corner := em.NewEntity()
+	rect := ecs.AddComponent[ui.RectTransform](em, corner)
+	rect.Anchor, rect.Pivot = ui.BottomRight, ui.BottomRight
+	rect.Offset = f64.Vec2{-8, -8}
+	rect.Size = f64.Vec2{100, 20}
+	assert.True(t, ecs.HasComponent[render.ScreenSpace](em, corner), "rectangles are drawn in screen space")
+
+	label := em.NewEntity()
+	child := ecs.AddComponent[ui.RectTransform](em, label)
+	child.Anchor, child.Pivot = ui.Center, ui.Center
+	child.Size = f64.Vec2{50, 10}
+	child.Parent = em.Ref(corner)
+	sprite := ecs.AddComponent[render.Sprite](em, label)
+	sprite.Image = ebiten.NewImage(4, 4)
+	sprite.Mode = render.ModeNineSlice
+
+	require.NoError(t, sm.Update())
+
+	minPoint, maxPoint := rect.Bounds()
+	assert.Equal(t, f64.Vec2{212, 212}, minPoint)
+	assert.Equal(t, f64.Vec2{312, 232}, maxPoint)
+	assert.Equal(t, f64.Vec2{212, 212}, ecs.MustGetComponent[transform.Transform](em, corner).Position)
+
+	assert.Equal(t, f64.Vec2{237, 217}, ecs.MustGetComponent[transform.Transform](em, label).Position,
+		"children are placed in their parent")
+	assert.Equal(t, 50, sprite.DrawSize.X)
+	assert.True(t, child.Contains(f64.Vec2{240, 220}))
+	assert.False(t, child.Contains(f64.Vec2{200, 220}))
+
+	em.Remove(corner)
+	// Builds with the ecsdebug tag report the stale parent reference after the update.
+	if err := sm.Update(); !errors.Is(err, ecs.ErrInvalidWorld) {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, f64.Vec2{135, 115}, ecs.MustGetComponent[transform.Transform](em, label).Position,
+		"rectangles whose parent is gone are placed on the screen")
+}
+
+func TestLayoutSystemCycle(t *testing.T) {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, ecs.NewGame(&ecs.GameConfig{ScreenWidth: 320, ScreenHeight: 240}))
+	sm.Add(ui.NewLayoutSystem(1, 0))
+
+	a, b := em.NewEntity(), em.NewEntity()
+	ecs.AddComponent[ui.RectTransform](em, a).Parent = em.Ref(b)
+	ecs.AddComponent[ui.RectTransform](em, b).Parent = em.Ref(a)
+	ecs.MustGetComponent[ui.RectTransform](em, b).Offset = f64.Vec2{5, 5}
+
+	require.NoError(t, sm.Update())
+	assert.Equal(t, f64.Vec2{5, 5}, ecs.MustGetComponent[transform.Transform](em, b).Position)
+	assert.Equal(t, f64.Vec2{5, 5}, ecs.MustGetComponent[transform.Transform](em, a).Position)
+}
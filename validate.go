@@ -0,0 +1,257 @@
+package ecs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidWorld is returned by SystemManager.Update in builds with the ecsdebug tag when the EntityManager
+// fails Validate after the update.
+var ErrInvalidWorld = errors.New("invalid world")
+
+// IssueKind is the kind of problem reported by EntityManager.Validate.
+type IssueKind int
+
+const (
+	// IssueDeadEntity is a component stored for an entity that does not exist, or that the entity does
+	// not know it has.
+	IssueDeadEntity IssueKind = iota
+	// IssueMissingRequired is an entity missing a component required by one of its components, see Require.
+	IssueMissingRequired
+	// IssueDanglingRef is a valid-looking Ref field pointing at an entity that was removed or recycled.
+	IssueDanglingRef
+	// IssueNotFinite is a float field, such as a position, that is NaN or infinite.
+	IssueNotFinite
+)
+
+func (k IssueKind) String() string {
+	switch k {
+	case IssueDeadEntity:
+		return "dead entity"
+	case IssueMissingRequired:
+		return "missing required component"
+	case IssueDanglingRef:
+		return "dangling reference"
+	case IssueNotFinite:
+		return "not finite"
+	default:
+		return "IssueKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// ValidationIssue is a problem found by EntityManager.Validate on a component of an entity.
+type ValidationIssue struct {
+	Kind      IssueKind
+	Entity    EntityID
+	Component reflect.Type
+	// Field is the path of the offending field in the component, e.g. "Target" or "Points[2].X".
+	Field string
+	// Detail describes the issue, e.g. the missing component type or the value of the field.
+	Detail string
+}
+
+func (i ValidationIssue) String() string {
+	location := i.Component.String()
+	if i.Field != "" {
+		location += "." + i.Field
+	}
+
+	return fmt.Sprintf("entity %d: %s: %s: %s", i.Entity, location, i.Kind, i.Detail)
+}
+
+// ValidationReport is the result of EntityManager.Validate.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+// OK reports whether no issue was found.
+func (r ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// String returns the issues, one per line.
+func (r ValidationReport) String() string {
+	lines := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		lines = append(lines, issue.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the EntityManager for common problems and returns them ordered by entity, component
+// type name and field:
+//   - components stored for entities that were removed, see IssueDeadEntity;
+//   - entities missing a required component, e.g. a Sprite without a Transform, see Require and CheckRequirements;
+//   - Ref fields pointing at removed or recycled entities, see IssueDanglingRef;
+//   - NaN or infinite float fields, e.g. a position after a division by zero, see IssueNotFinite.
+//
+// Fields are found in nested structs, arrays and slices. Validate is meant for tests and debugging: builds
+// with the ecsdebug tag run it after every SystemManager.Update.
+func (em *EntityManager) Validate() ValidationReport {
+	var report ValidationReport
+	for componentType, storage := range em.componentContainers {
+		for entityID := range storage.Entities() {
+			if _, exists := em.entities[entityID]; !exists {
+				report.Issues = append(report.Issues, ValidationIssue{Kind: IssueDeadEntity, Entity: entityID, Component: componentType, Detail: "entity does not exist"})
+			} else if !em.hasComponentType(entityID, componentType) {
+				report.Issues = append(report.Issues, ValidationIssue{Kind: IssueDeadEntity, Entity: entityID, Component: componentType, Detail: "component not in the entity mask"})
+			}
+		}
+	}
+
+	for _, violation := range em.CheckRequirements() {
+		report.Issues = append(report.Issues, ValidationIssue{
+			Kind:      IssueMissingRequired,
+			Entity:    violation.Entity,
+			Component: violation.Component,
+			Detail:    "requires " + violation.Missing.String(),
+		})
+	}
+
+	for entityID := range em.entities {
+		for componentType, component := range em.Components(entityID) {
+			if !needsFieldValidation(componentType) {
+				continue
+			}
+
+			em.validateFields(&report, entityID, componentType, "", reflect.ValueOf(component).Elem())
+		}
+	}
+
+	slices.SortFunc(report.Issues, func(a, b ValidationIssue) int {
+		return cmp.Or(
+			cmp.Compare(a.Entity, b.Entity),
+			cmp.Compare(a.Component.String(), b.Component.String()),
+			cmp.Compare(a.Field, b.Field),
+			cmp.Compare(a.Kind, b.Kind),
+		)
+	})
+
+	return report
+}
+
+// validateFields reports the dangling Refs and the floats that are not finite in value, at path in the component.
+func (em *EntityManager) validateFields(report *ValidationReport, entityID EntityID, componentType reflect.Type, path string, value reflect.Value) {
+	issue := func(kind IssueKind, detail string) {
+		report.Issues = append(report.Issues, ValidationIssue{Kind: kind, Entity: entityID, Component: componentType, Field: path, Detail: detail})
+	}
+
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if f := value.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			issue(IssueNotFinite, strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	case reflect.Struct:
+		if value.Type() == refType {
+			ref := value.Interface().(Ref)
+			if _, ok := ref.Get(em); !ok && ref != (Ref{}) {
+				issue(IssueDanglingRef, fmt.Sprintf("entity %d generation %d", ref.Entity, ref.Generation))
+			}
+
+			return
+		}
+
+		for i := range value.NumField() {
+			field := value.Type().Field(i)
+			if !field.IsExported() || !needsFieldValidation(field.Type) {
+				continue
+			}
+
+			name := field.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			em.validateFields(report, entityID, componentType, name, value.Field(i))
+		}
+	case reflect.Array, reflect.Slice:
+		if !needsFieldValidation(value.Type().Elem()) {
+			return
+		}
+
+		for i := range value.Len() {
+			em.validateFields(report, entityID, componentType, path+"["+strconv.Itoa(i)+"]", value.Index(i))
+		}
+	}
+}
+
+var (
+	refType = reflect.TypeFor[Ref]()
+	// validatedTypes caches whether types contain a Ref or a float, by reflect.Type.
+	validatedTypes sync.Map
+)
+
+// needsFieldValidation reports whether values of type t may contain a Ref or a float in exported fields.
+func needsFieldValidation(t reflect.Type) bool {
+	if cached, ok := validatedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+
+	needed, _ := inspectFieldValidation(t, make(map[reflect.Type]bool))
+
+	return needed
+}
+
+// inspectFieldValidation reports whether values of type t need validation, and whether the answer is final:
+// a type found again while it is being inspected is assumed not to need validation, so the types depending
+// on that assumption are only cached once the outermost type is done.
+func inspectFieldValidation(t reflect.Type, visiting map[reflect.Type]bool) (needed, final bool) {
+	if cached, ok := validatedTypes.Load(t); ok {
+		return cached.(bool), true
+	}
+
+	if visiting[t] {
+		return false, false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	final = true
+	inspect := func(elem reflect.Type) {
+		elemNeeded, elemFinal := inspectFieldValidation(elem, visiting)
+		needed = needed || elemNeeded
+		final = final && elemFinal
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		needed = true
+	case reflect.Struct:
+		needed = t == refType
+		for i := 0; i < t.NumField() && !needed; i++ {
+			if t.Field(i).IsExported() {
+				inspect(t.Field(i).Type)
+			}
+		}
+	case reflect.Array, reflect.Slice:
+		inspect(t.Elem())
+	}
+
+	// A type that needs validation does whatever the assumptions, and the outermost type depends on none.
+	final = final || needed || len(visiting) == 1
+	if final {
+		validatedTypes.Store(t, needed)
+	}
+
+	return needed, final
+}
+
+// validate returns an error wrapping ErrInvalidWorld if validateUpdates is set and the EntityManager fails Validate.
+func (sm *SystemManager) validate() error {
+	if !validateUpdates || sm.entityManager == nil {
+		return nil
+	}
+
+	if report := sm.entityManager.Validate(); !report.OK() {
+		return fmt.Errorf("ecs.SystemManager.Update tick %d: %w:\n%s", sm.tick, ErrInvalidWorld, report)
+	}
+
+	return nil
+}
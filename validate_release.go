@@ -0,0 +1,6 @@
+//go:build !ecsdebug
+
+package ecs
+
+// validateUpdates makes SystemManager.Update validate its EntityManager after every update, see EntityManager.Validate.
+const validateUpdates = false
@@ -0,0 +1,6 @@
+//go:build !ecsdebug
+
+package ecs_test
+
+// validatingUpdates reports whether SystemManager.Update validates its EntityManager, see ecs.EntityManager.Validate.
+const validatingUpdates = false
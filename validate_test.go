@@ -0,0 +1,62 @@
+package ecs_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+type validatedPath struct {
+	Target ecs.Ref
+	Points []struct{ X, Y float64 }
+	speed  float64
+}
+
+func TestValidate(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	target := em.NewEntity()
+	entityID := em.NewEntity()
+	path := ecs.AddComponent[validatedPath](em, entityID)
+	path.Target = em.Ref(target)
+	path.Points = append(path.Points, struct{ X, Y float64 }{1, 2}, struct{ X, Y float64 }{3, 4})
+	path.speed = math.NaN()
+	ecs.AddComponent[requiringSprite](em, target)
+	assert.True(t, em.Validate().OK(), "unexported fields are not validated")
+
+	em.Remove(target)
+	path.Points[1].Y = math.Inf(1)
+	sprite := em.NewEntity()
+	ecs.AddComponent[requiringSprite](em, sprite)
+	ecs.RemoveComponent[TransformComponent](em, sprite)
+
+	report := em.Validate()
+	pathType := reflect.TypeFor[validatedPath]()
+	assert.Equal(t, []ecs.ValidationIssue{
+		{Kind: ecs.IssueNotFinite, Entity: entityID, Component: pathType, Field: "Points[1].Y", Detail: "+Inf"},
+		{Kind: ecs.IssueDanglingRef, Entity: entityID, Component: pathType, Field: "Target", Detail: fmt.Sprintf("entity %d generation 0", target)},
+		{Kind: ecs.IssueMissingRequired, Entity: sprite, Component: reflect.TypeFor[requiringSprite](), Detail: "requires ecs_test.TransformComponent"},
+	}, report.Issues)
+	assert.Contains(t, report.String(), "ecs_test.validatedPath.Target: dangling reference")
+}
+
+type validatedNode struct {
+	Children []validatedNode
+	X        float64
+}
+
+func TestValidateRecursiveType(t *testing.T) {
+	em := ecs.NewEntityManager()
+
+	entityID := em.NewEntity()
+	node := ecs.AddComponent[validatedNode](em, entityID)
+	node.Children = []validatedNode{{X: 1}, {Children: []validatedNode{{X: math.NaN()}}}}
+
+	assert.Equal(t, []ecs.ValidationIssue{
+		{Kind: ecs.IssueNotFinite, Entity: entityID, Component: reflect.TypeFor[validatedNode](), Field: "Children[1].Children[0].X", Detail: "NaN"},
+	}, em.Validate().Issues)
+}
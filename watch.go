@@ -0,0 +1,225 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// WatchEventKind is the kind of change reported by a Watch.
+type WatchEventKind int
+
+const (
+	// WatchAdded reports a component added to the entity.
+	WatchAdded WatchEventKind = iota
+	// WatchRemoved reports a component removed from the entity.
+	WatchRemoved
+	// WatchChanged reports a component whose value changed.
+	WatchChanged
+	// WatchDestroyed reports that the entity was removed. It is the last event of a Watch.
+	WatchDestroyed
+)
+
+func (k WatchEventKind) String() string {
+	switch k {
+	case WatchAdded:
+		return "added"
+	case WatchRemoved:
+		return "removed"
+	case WatchChanged:
+		return "changed"
+	case WatchDestroyed:
+		return "destroyed"
+	default:
+		return fmt.Sprintf("WatchEventKind(%d)", int(k))
+	}
+}
+
+// WatchEvent is a change of a watched entity.
+type WatchEvent struct {
+	Entity EntityID
+	Kind   WatchEventKind
+	// Component is the type of the added, removed or changed component, nil for WatchDestroyed.
+	Component reflect.Type
+}
+
+// watchBuffer is the capacity of the channels returned by Watch.Events.
+const watchBuffer = 64
+
+// Watch reports the changes of a single entity, e.g. to update a health bar without polling every frame.
+// Changes are detected by comparing the entity's components with copies taken at the previous dispatch,
+// see EntityManager.DispatchWatchEvents, so a component added and removed in between is not reported,
+// and changes inside the slices, maps and pointers of a component are not detected. Only the exported
+// fields of components are compared, except funcs, such as callbacks, which cannot be compared.
+type Watch struct {
+	em       *EntityManager
+	entityID EntityID
+
+	// types and values are the entity's components at the previous dispatch, and copies of their values.
+	types  []reflect.Type
+	values []reflect.Value
+
+	handler func(WatchEvent)
+	events  chan WatchEvent
+	closed  bool
+}
+
+// Watch starts watching the entity. The returned Watch reports changes from now on, to its OnEvent handler
+// and its Events channel, until it is closed or the entity is removed.
+func (em *EntityManager) Watch(entityID EntityID) *Watch {
+	w := &Watch{em: em, entityID: entityID}
+	w.refresh()
+	em.watches = append(em.watches, w)
+
+	return w
+}
+
+// Entity returns the watched entity.
+func (w *Watch) Entity() EntityID {
+	return w.entityID
+}
+
+// OnEvent sets the function called with every change.
+func (w *Watch) OnEvent(handler func(WatchEvent)) {
+	w.handler = handler
+}
+
+// Events returns a channel receiving every change, closed when the Watch is closed. Events are dropped
+// while the channel is full, so it must be drained regularly.
+func (w *Watch) Events() <-chan WatchEvent {
+	if w.events == nil {
+		w.events = make(chan WatchEvent, watchBuffer)
+		if w.closed {
+			close(w.events)
+		}
+	}
+
+	return w.events
+}
+
+// Closed reports whether the Watch was closed, by Close or because the entity was removed.
+func (w *Watch) Closed() bool {
+	return w.closed
+}
+
+// Close stops watching the entity.
+func (w *Watch) Close() {
+	if w.closed {
+		return
+	}
+
+	w.closed = true
+	w.em.watches = slices.DeleteFunc(w.em.watches, func(other *Watch) bool { return other == w })
+
+	if w.events != nil {
+		close(w.events)
+	}
+}
+
+// DispatchWatchEvents reports the changes of the watched entities since the previous dispatch.
+// The SystemManager dispatches them after every Update.
+func (em *EntityManager) DispatchWatchEvents() {
+	if len(em.watches) == 0 {
+		return
+	}
+
+	// Handlers may open and close watches.
+	for _, w := range slices.Clone(em.watches) {
+		if !w.closed {
+			w.dispatch()
+		}
+	}
+}
+
+func (w *Watch) dispatch() {
+	if !w.em.Exists(w.entityID) {
+		w.emit(WatchEvent{Entity: w.entityID, Kind: WatchDestroyed})
+		w.Close()
+		return
+	}
+
+	current := w.em.ComponentTypes(w.entityID)
+	for _, componentType := range w.types {
+		if !slices.Contains(current, componentType) {
+			w.emit(WatchEvent{Entity: w.entityID, Kind: WatchRemoved, Component: componentType})
+		}
+	}
+
+	for _, componentType := range current {
+		component, _ := w.em.GetComponentByType(w.entityID, componentType)
+
+		i := slices.Index(w.types, componentType)
+		switch {
+		case i < 0:
+			w.emit(WatchEvent{Entity: w.entityID, Kind: WatchAdded, Component: componentType})
+		case !watchEqual(w.values[i], reflect.ValueOf(component).Elem()):
+			w.emit(WatchEvent{Entity: w.entityID, Kind: WatchChanged, Component: componentType})
+		}
+	}
+
+	w.refresh()
+}
+
+// watchEqual reports whether a and b, values of the same type, are equal for a Watch: structs are compared
+// by their exported fields, and funcs, which reflect.DeepEqual only treats as equal if both are nil, are skipped.
+func watchEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Func:
+		return true
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if a.Type().Field(i).IsExported() && !watchEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+// refresh copies the values of the entity's components, reusing the copies of the previous dispatch.
+// Changes made by the handlers during the dispatch are not reported.
+func (w *Watch) refresh() {
+	types := w.em.ComponentTypes(w.entityID)
+	values := make([]reflect.Value, len(types))
+	for n, componentType := range types {
+		if i := slices.Index(w.types, componentType); i >= 0 {
+			values[n] = w.values[i]
+		} else {
+			values[n] = reflect.New(componentType).Elem()
+		}
+
+		component, _ := w.em.GetComponentByType(w.entityID, componentType)
+		values[n].Set(reflect.ValueOf(component).Elem())
+	}
+
+	w.types = types
+	w.values = values
+}
+
+func (w *Watch) emit(event WatchEvent) {
+	// A handler may have closed the Watch.
+	if w.closed {
+		return
+	}
+
+	if w.handler != nil {
+		w.handler(event)
+	}
+
+	if w.events != nil && !w.closed {
+		select {
+		case w.events <- event:
+		default:
+		}
+	}
+}
+
+// closeWatches closes the watches of the EntityManager, when it is torn down.
+func (em *EntityManager) closeWatches() {
+	for _, w := range slices.Clone(em.watches) {
+		w.Close()
+	}
+}
@@ -0,0 +1,112 @@
+package ecs_test
+
+import (
+	"reflect"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, game)
+
+	player := NewPlayerEntity(t, em)
+	other := NewPlayerEntity(t, em)
+
+	var events []ecs.WatchEvent
+	watch := em.Watch(player)
+	watch.OnEvent(func(event ecs.WatchEvent) { events = append(events, event) })
+	assert.Equal(t, player, watch.Entity())
+
+	require.NoError(t, sm.Update())
+	assert.Empty(t, events, "nothing changed")
+
+	ecs.MustGetComponent[TransformComponent](em, other).Rotation = 1
+	require.NoError(t, sm.Update())
+	assert.Empty(t, events, "only the watched entity is reported")
+
+	transformType := reflect.TypeFor[TransformComponent]()
+	cameraType := reflect.TypeFor[CameraComponent]()
+
+	ecs.MustGetComponent[TransformComponent](em, player).Rotation = 1
+	ecs.AddComponent[CameraComponent](em, player)
+	require.NoError(t, sm.Update())
+	assert.Equal(t, []ecs.WatchEvent{
+		{Entity: player, Kind: ecs.WatchChanged, Component: transformType},
+		{Entity: player, Kind: ecs.WatchAdded, Component: cameraType},
+	}, events)
+
+	events = nil
+	ecs.RemoveComponent[CameraComponent](em, player)
+	ecs.MustGetComponent[TransformComponent](em, player).Rotation = 1
+	em.DispatchWatchEvents()
+	assert.Equal(t, []ecs.WatchEvent{
+		{Entity: player, Kind: ecs.WatchRemoved, Component: cameraType},
+	}, events, "setting the same value is not a change")
+
+	events = nil
+	em.Remove(player)
+	em.DispatchWatchEvents()
+	assert.Equal(t, []ecs.WatchEvent{{Entity: player, Kind: ecs.WatchDestroyed}}, events)
+	assert.True(t, watch.Closed())
+	assert.Equal(t, "destroyed", events[0].Kind.String())
+}
+
+type callbackComponent struct {
+	Count   int
+	OnCount func(int)
+
+	calls int
+}
+
+func TestWatchSkipsFuncs(t *testing.T) {
+	em := ecs.NewEntityManager()
+	entityID := em.NewEntity()
+	component := ecs.AddComponent[callbackComponent](em, entityID)
+	component.OnCount = func(int) {}
+
+	var events []ecs.WatchEvent
+	em.Watch(entityID).OnEvent(func(event ecs.WatchEvent) { events = append(events, event) })
+
+	em.DispatchWatchEvents()
+	events = nil
+
+	component.calls++
+	em.DispatchWatchEvents()
+	assert.Empty(t, events, "funcs and unexported fields are not compared")
+
+	component.Count++
+	em.DispatchWatchEvents()
+	assert.Equal(t, []ecs.WatchEvent{
+		{Entity: entityID, Kind: ecs.WatchChanged, Component: reflect.TypeFor[callbackComponent]()},
+	}, events)
+}
+
+func TestWatchEvents(t *testing.T) {
+	em := ecs.NewEntityManager()
+	camera := NewCameraEntity(t, em)
+
+	watch := em.Watch(camera)
+	events := watch.Events()
+
+	ecs.MustGetComponent[CameraComponent](em, camera).Zoom = 2
+	em.DispatchWatchEvents()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, ecs.WatchEvent{Entity: camera, Kind: ecs.WatchChanged, Component: reflect.TypeFor[CameraComponent]()}, <-events)
+
+	watch.Close()
+	ecs.MustGetComponent[CameraComponent](em, camera).Zoom = 3
+	em.DispatchWatchEvents()
+
+	_, open := <-events
+	assert.False(t, open, "closing the watch closes the channel")
+
+	closedOnTeardown := em.Watch(camera)
+	em.Teardown()
+	assert.True(t, closedOnTeardown.Closed())
+}
@@ -50,6 +50,11 @@ func (w *BaseWorld) Draw(screen *ebiten.Image) {
 	w.SystemManager().Draw(screen)
 }
 
+// DrawCanvas draws the world onto the given Canvas, see SystemManager.DrawCanvas.
+func (w *BaseWorld) DrawCanvas(canvas Canvas) {
+	w.SystemManager().DrawCanvas(canvas)
+}
+
 // SystemManager returns the SystemManager associated with the world.
 func (w *BaseWorld) SystemManager() *SystemManager {
 	return w.systemManager
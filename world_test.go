@@ -0,0 +1,241 @@
+package ecs_test
+
+import (
+	"slices"
+	"testing"
+
+	ecs "github.com/samix73/ebiten-ecs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookSystem struct {
+	*ecs.BaseSystem
+
+	events *[]string
+	name   string
+}
+
+func (s *hookSystem) record(event string) {
+	if s.events != nil {
+		*s.events = append(*s.events, s.name+":"+event)
+	}
+}
+
+func (s *hookSystem) Update() error {
+	s.record("update")
+	return nil
+}
+
+func (s *hookSystem) OnWorldStart(ecs.World) {
+	s.record("start")
+}
+
+func (s *hookSystem) OnWorldStop() {
+	s.record("stop")
+}
+
+func (s *hookSystem) Teardown() {
+	s.record("teardown")
+}
+
+type hookWorld struct {
+	*ecs.BaseWorld
+
+	events *[]string
+	name   string
+}
+
+func (w *hookWorld) Init(g *ecs.Game) error {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+	sm.Add(&hookSystem{BaseSystem: ecs.NewBaseSystem(ecs.NextID(), 0), events: w.events, name: w.name})
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+func TestWorldStack(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	gameplay := &hookWorld{events: &events, name: "gameplay"}
+	pause := &hookWorld{events: &events, name: "pause"}
+
+	require.NoError(t, game.SetActiveWorld(gameplay))
+	require.NoError(t, game.Update())
+	require.NoError(t, game.PushWorld(pause))
+	assert.Same(t, pause, game.ActiveWorld())
+	require.NoError(t, game.Update())
+	require.NoError(t, game.PopWorld())
+	assert.Same(t, gameplay, game.ActiveWorld())
+	require.NoError(t, game.Update())
+	require.NoError(t, game.PopWorld())
+	assert.Nil(t, game.ActiveWorld())
+	assert.Error(t, game.PopWorld())
+
+	assert.Equal(t, []string{
+		"gameplay:start",
+		"gameplay:update",
+		"gameplay:stop",
+		"pause:start",
+		"pause:update",
+		"pause:stop",
+		"pause:teardown",
+		"gameplay:start",
+		"gameplay:update",
+		"gameplay:stop",
+		"gameplay:teardown",
+	}, events)
+}
+
+type initSystem struct {
+	hookSystem
+
+	err error
+}
+
+func (s *initSystem) Init(w ecs.World) error {
+	s.record("init")
+	return s.err
+}
+
+type systemsWorld struct {
+	*ecs.BaseWorld
+
+	systems []ecs.System
+}
+
+func (w *systemsWorld) Init(g *ecs.Game) error {
+	em := ecs.NewEntityManager()
+	sm := ecs.NewSystemManager(em, g)
+	if err := sm.Add(w.systems...); err != nil {
+		return err
+	}
+
+	w.BaseWorld = ecs.NewBaseWorld(em, sm)
+
+	return nil
+}
+
+func TestSystemIniter(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+	newSystem := func(name string, priority int, err error) *initSystem {
+		return &initSystem{hookSystem: hookSystem{BaseSystem: ecs.NewBaseSystem(ecs.UndefinedID, priority), events: &events, name: name}, err: err}
+	}
+
+	world := &systemsWorld{systems: []ecs.System{newSystem("render", 10, nil), newSystem("physics", 0, nil)}}
+	require.NoError(t, game.SetActiveWorld(world))
+	assert.Equal(t, []string{"physics:init", "render:init", "physics:start", "render:start"}, events,
+		"systems are initialized in update order before the world starts")
+
+	events = nil
+	require.NoError(t, world.SystemManager().Add(newSystem("late", 5, nil)))
+	require.ErrorIs(t, world.SystemManager().Add(newSystem("broken", 5, assert.AnError)), assert.AnError)
+	assert.Equal(t, []string{"late:init", "late:start", "broken:init", "broken:teardown"}, events)
+
+	events = nil
+	require.ErrorIs(t, world.SystemManager().Add(newSystem("first", 5, nil), newSystem("second", 6, assert.AnError)), assert.AnError)
+	assert.Equal(t, []string{"first:init", "second:init", "first:teardown", "second:teardown"}, events,
+		"the systems initialized before the failure are torn down")
+	assert.Len(t, slices.Collect(world.SystemManager().Systems()), 3)
+
+	events = nil
+	broken := &systemsWorld{systems: []ecs.System{newSystem("broken", 0, assert.AnError), newSystem("next", 1, nil)}}
+	require.ErrorIs(t, game.PushWorld(broken), assert.AnError)
+	assert.Same(t, world, game.ActiveWorld(), "a failing system aborts the world switch")
+	assert.Equal(t, []string{
+		"physics:stop", "late:stop", "render:stop",
+		"broken:init", "broken:teardown", "next:teardown",
+		"physics:start", "late:start", "render:start",
+	}, events)
+}
+
+func TestWorldReset(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	startups := 0
+	spawner := ecs.NewSystem(ecs.SystemOptions{
+		Startup: func(s *ecs.FuncSystem) error {
+			startups++
+			s.EntityManager().NewEntity()
+			return nil
+		},
+	}, nil)
+
+	world := &systemsWorld{systems: []ecs.System{spawner}}
+	require.NoError(t, game.SetActiveWorld(world))
+	require.NoError(t, game.Update())
+
+	em := world.EntityManager()
+	profile := em.NewEntity()
+	ecs.AddComponent[ecs.Persistent](em, profile)
+	em.NewEntity()
+	require.Len(t, em.Entities(), 3)
+
+	world.Reset(&ecs.ResetOptions{KeepPersistent: true})
+	assert.Equal(t, []ecs.EntityID{profile}, em.Entities())
+	assert.True(t, world.SystemManager().Has(spawner.ID()), "systems are kept")
+
+	require.NoError(t, game.Update())
+	assert.Equal(t, 2, startups, "startup systems run again")
+	assert.Len(t, em.Entities(), 2)
+
+	world.Reset(nil)
+	assert.Empty(t, em.Entities())
+}
+
+func TestWorldResetPools(t *testing.T) {
+	game := ecs.NewGame(&ecs.GameConfig{})
+	world := &systemsWorld{}
+	require.NoError(t, game.SetActiveWorld(world))
+
+	em := world.EntityManager()
+	bullet := ecs.NewPrefab("bullet", func(em *ecs.EntityManager, entityID ecs.EntityID) {
+		ecs.AddComponent[TransformComponent](em, entityID)
+	})
+	pool := ecs.NewPool(em, bullet, 3)
+	fired := pool.Acquire()
+
+	world.Reset(nil)
+	assert.Equal(t, 3, pool.Available(), "pools are refilled")
+	assert.Zero(t, pool.InUse())
+	assert.False(t, em.Exists(fired))
+
+	var entityID ecs.EntityID
+	require.NotPanics(t, func() { entityID = pool.Acquire() })
+	assert.True(t, em.Active(entityID))
+	assert.True(t, ecs.HasComponent[TransformComponent](em, entityID))
+}
+
+func TestMultipleWorlds(t *testing.T) {
+	var events []string
+	game := ecs.NewGame(&ecs.GameConfig{})
+
+	ui := &hookWorld{events: &events, name: "ui"}
+	background := &hookWorld{events: &events, name: "background"}
+	require.NoError(t, game.AddWorld(ui, 10))
+	require.NoError(t, game.AddWorld(background, -1))
+	require.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "level1"}))
+
+	events = nil
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"background:update", "level1:update", "ui:update"}, events)
+
+	require.NoError(t, game.SetActiveWorld(&hookWorld{events: &events, name: "level2"}))
+	events = nil
+	require.NoError(t, game.Update())
+	assert.Equal(t, []string{"background:update", "level2:update", "ui:update"}, events, "added worlds outlive world switches")
+
+	events = nil
+	game.RemoveWorld(background)
+	assert.Equal(t, []string{"background:stop", "background:teardown"}, events)
+
+	var worlds []ecs.World
+	for world := range game.Worlds() {
+		worlds = append(worlds, world)
+	}
+	assert.Equal(t, []ecs.World{game.ActiveWorld(), ui}, worlds)
+}